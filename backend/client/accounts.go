@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CreateAccountInput são os dados da conta criada; TestMode cria a conta em modo de teste, com o adquirente simulado
+type CreateAccountInput struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Scopes   []string `json:"scopes,omitempty"`
+	TestMode bool     `json:"test_mode,omitempty"`
+}
+
+// Account é uma conta do gateway
+// APIKey só vem na criação da conta; EscrowedBalance e PendingBalance só vêm na consulta da própria conta
+type Account struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Balance         float64    `json:"balance"`
+	APIKey          string     `json:"api_key,omitempty"`
+	Role            string     `json:"role"`
+	Scopes          []string   `json:"scopes"`
+	TenantID        string     `json:"tenant_id,omitempty"`
+	TestMode        bool       `json:"test_mode"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	EscrowedBalance *float64   `json:"escrowed_balance,omitempty"`
+	PendingBalance  *float64   `json:"pending_balance,omitempty"`
+}
+
+// CreateAccount cria uma conta, pela rota pública POST /accounts, e retorna a conta com o API Key
+func (c *Client) CreateAccount(ctx context.Context, input CreateAccountInput) (*Account, error) {
+	var account Account
+	if _, err := c.do(ctx, http.MethodPost, "/accounts", input, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetAccount consulta a conta do API Key
+func (c *Client) GetAccount(ctx context.Context) (*Account, error) {
+	var account Account
+	if _, err := c.do(ctx, http.MethodGet, "/accounts", nil, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
@@ -0,0 +1,138 @@
+// Package client é o cliente Go da API do gateway para os lojistas, com métodos tipados para contas, faturas,
+// reembolsos e a verificação dos webhooks
+//
+// Cada Client fala com uma instância em nome de um API Key, enviado no header X-API-Key:
+//
+//	gateway := client.New("https://gateway.exemplo.com", apiKey)
+//	invoice, err := gateway.CreateInvoice(ctx, client.CreateInvoiceInput{...})
+//
+// Respostas fora da faixa 2xx viram *APIError, com o status HTTP e a mensagem do gateway
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyHeader é o header que autentica as requisições da conta
+const APIKeyHeader = "X-API-Key"
+
+// nextCursorHeader traz o cursor da próxima página das listagens paginadas
+const nextCursorHeader = "X-Next-Cursor"
+
+// DefaultTimeout é o tempo máximo de cada requisição quando o Client não recebe outro http.Client
+const DefaultTimeout = 30 * time.Second
+
+// Client faz as requisições de uma conta a uma instância do gateway
+// É seguro para uso concorrente
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Option ajusta o Client criado por New
+type Option func(*Client)
+
+// WithHTTPClient usa httpClient nas requisições, com o transporte e o timeout dele
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New cria um cliente para a instância em baseURL, como https://gateway.exemplo.com, autenticado com apiKey
+// apiKey pode ficar vazio para as rotas públicas, como CreateAccount
+func New(baseURL, apiKey string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// APIError é uma resposta do gateway fora da faixa 2xx
+// Message é o corpo da resposta, que nas recusas é a mensagem de erro do gateway, e RetryAfter vem preenchido
+// quando o gateway pede para esperar antes de tentar de novo, como no 429
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gateway: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("gateway: %d %s", e.StatusCode, e.Message)
+}
+
+// StatusCode retorna o status HTTP de err quando ele é um *APIError, e 0 nos demais erros
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// do envia a requisição, com input em JSON no corpo quando não é nil, e decodifica a resposta em output
+// A resposta é lida até o fim, para que a conexão volte ao pool
+func (c *Client) do(ctx context.Context, method, path string, input, output any) (http.Header, error) {
+	var body io.Reader
+	if input != nil {
+		payload, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if input != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		request.Header.Set(APIKeyHeader, c.apiKey)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: response.StatusCode, Message: strings.TrimSpace(string(content))}
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, apiErr
+	}
+	if output != nil {
+		if err := json.Unmarshal(content, output); err != nil {
+			return nil, fmt.Errorf("gateway: decoding %s %s response: %w", method, path, err)
+		}
+	}
+	return response.Header, nil
+}
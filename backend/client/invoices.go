@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status das faturas
+const (
+	InvoiceStatusPending     = "pending"
+	InvoiceStatusApproved    = "approved"
+	InvoiceStatusRejected    = "rejected"
+	InvoiceStatusSettled     = "settled"
+	InvoiceStatusRefunded    = "refunded"
+	InvoiceStatusChargedBack = "charged_back"
+)
+
+// CreateInvoiceInput são os dados da fatura criada
+// Os campos opcionais seguem as regras de POST /invoice descritas no README do gateway; CouponCode, CustomerID,
+// CardToken, SaveCard e Currency não são aceitos nos lotes
+type CreateInvoiceInput struct {
+	Amount         float64            `json:"amount,omitempty"`
+	Description    string             `json:"description"`
+	PaymentType    string             `json:"payment_type"`
+	CardNumber     string             `json:"card_number,omitempty"`
+	CVV            string             `json:"cvv,omitempty"`
+	ExpiryMonth    int                `json:"expiry_month,omitempty"`
+	ExpiryYear     int                `json:"expiry_year,omitempty"`
+	CardholderName string             `json:"cardholder_name,omitempty"`
+	Metadata       map[string]string  `json:"metadata,omitempty"`
+	PayerEmail     string             `json:"payer_email,omitempty"`
+	PayerDocument  string             `json:"payer_document,omitempty"`
+	Splits         []SplitRuleInput   `json:"splits,omitempty"`
+	EscrowDays     int                `json:"escrow_days,omitempty"`
+	CouponCode     string             `json:"coupon_code,omitempty"`
+	Items          []InvoiceItemInput `json:"items,omitempty"`
+	CustomerID     string             `json:"customer_id,omitempty"`
+	CardToken      string             `json:"card_token,omitempty"`
+	SaveCard       bool               `json:"save_card,omitempty"`
+	DueDate        string             `json:"due_date,omitempty"`
+	Currency       string             `json:"currency,omitempty"`
+}
+
+// SplitRuleInput é a parte de uma conta recebedora, em percentual ou em valor
+type SplitRuleInput struct {
+	RecipientID string  `json:"recipient_id"`
+	Percentage  float64 `json:"percentage,omitempty"`
+	Amount      float64 `json:"amount,omitempty"`
+	ChargeFee   bool    `json:"charge_fee,omitempty"`
+}
+
+// InvoiceItemInput é um item da fatura
+type InvoiceItemInput struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// Invoice é uma fatura do gateway; os campos opcionais só vêm nas faturas que usam o recurso
+type Invoice struct {
+	ID             string              `json:"id"`
+	AccountID      string              `json:"account_id"`
+	Amount         float64             `json:"amount"`
+	Status         string              `json:"status"`
+	Description    string              `json:"description"`
+	PaymentType    string              `json:"payment_type"`
+	CardToken      string              `json:"card_token"`
+	CardBrand      string              `json:"card_brand"`
+	CardLastDigits string              `json:"card_last_digits"`
+	PayerName      string              `json:"payer_name"`
+	Metadata       map[string]string   `json:"metadata"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	DeletedAt      *time.Time          `json:"deleted_at,omitempty"`
+	Splits         []Split             `json:"splits,omitempty"`
+	Items          []InvoiceItem       `json:"items,omitempty"`
+	Discount       *InvoiceDiscount    `json:"discount,omitempty"`
+	TaxAmount      float64             `json:"tax_amount,omitempty"`
+	Taxes          []InvoiceTax        `json:"taxes,omitempty"`
+	DueDate        string              `json:"due_date,omitempty"`
+	LateCharges    *InvoiceLateCharges `json:"late_charges,omitempty"`
+	FX             *InvoiceFX          `json:"fx,omitempty"`
+}
+
+// Split é a parte de uma conta recebedora numa fatura dividida
+type Split struct {
+	RecipientID string  `json:"recipient_id"`
+	Amount      float64 `json:"amount"`
+	Fee         float64 `json:"fee"`
+	Net         float64 `json:"net"`
+}
+
+// InvoiceItem é um item da fatura, numerado por Line
+type InvoiceItem struct {
+	Line        int     `json:"line"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+// InvoiceDiscount detalha o cupom aplicado à fatura
+type InvoiceDiscount struct {
+	CouponCode     string  `json:"coupon_code"`
+	OriginalAmount float64 `json:"original_amount"`
+	Amount         float64 `json:"amount"`
+}
+
+// InvoiceTax é um imposto de uma linha da fatura
+type InvoiceTax struct {
+	Line   int     `json:"line"`
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// InvoiceLateCharges detalha a multa e os juros de uma fatura paga depois do vencimento
+type InvoiceLateCharges struct {
+	DaysLate       int     `json:"days_late"`
+	Fine           float64 `json:"fine"`
+	Interest       float64 `json:"interest"`
+	OriginalAmount float64 `json:"original_amount"`
+}
+
+// InvoiceFX detalha a moeda e a cotação de uma fatura pedida em outra moeda
+type InvoiceFX struct {
+	Currency    string     `json:"currency"`
+	Amount      float64    `json:"amount"`
+	Rate        float64    `json:"rate"`
+	LockedRate  float64    `json:"locked_rate"`
+	LockedUntil time.Time  `json:"locked_until"`
+	RequotedAt  *time.Time `json:"requoted_at,omitempty"`
+}
+
+// ListInvoicesInput são os filtros da listagem de faturas; os campos vazios não filtram
+// Limit ativa a paginação e Cursor é o NextCursor da página anterior
+type ListInvoicesInput struct {
+	Statuses    []string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   float64
+	MaxAmount   float64
+	Metadata    map[string]string
+	Search      string
+	Limit       int
+	Cursor      string
+}
+
+// InvoicePage é uma página da listagem de faturas; NextCursor fica vazio na última página
+type InvoicePage struct {
+	Invoices   []*Invoice
+	NextCursor string
+}
+
+// CreateInvoice cria e processa uma fatura
+func (c *Client) CreateInvoice(ctx context.Context, input CreateInvoiceInput) (*Invoice, error) {
+	var invoice Invoice
+	if _, err := c.do(ctx, http.MethodPost, "/invoice", input, &invoice); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// CreateInvoiceBatch cria as faturas de uma vez, na mesma ordem; um lote com qualquer fatura recusada não cria nenhuma
+func (c *Client) CreateInvoiceBatch(ctx context.Context, inputs []CreateInvoiceInput) ([]*Invoice, error) {
+	var invoices []*Invoice
+	body := struct {
+		Invoices []CreateInvoiceInput `json:"invoices"`
+	}{inputs}
+	if _, err := c.do(ctx, http.MethodPost, "/invoice/batch", body, &invoices); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// GetInvoice consulta uma fatura da conta
+func (c *Client) GetInvoice(ctx context.Context, id string) (*Invoice, error) {
+	var invoice Invoice
+	if _, err := c.do(ctx, http.MethodGet, "/invoice/"+url.PathEscape(id), nil, &invoice); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// ListInvoices lista as faturas da conta que atendem aos filtros
+func (c *Client) ListInvoices(ctx context.Context, input ListInvoicesInput) (*InvoicePage, error) {
+	query := url.Values{}
+	if len(input.Statuses) > 0 {
+		query.Set("status", strings.Join(input.Statuses, ","))
+	}
+	if !input.CreatedFrom.IsZero() {
+		query.Set("created_from", input.CreatedFrom.Format(time.RFC3339))
+	}
+	if !input.CreatedTo.IsZero() {
+		query.Set("created_to", input.CreatedTo.Format(time.RFC3339))
+	}
+	if input.MinAmount != 0 {
+		query.Set("min_amount", strconv.FormatFloat(input.MinAmount, 'f', -1, 64))
+	}
+	if input.MaxAmount != 0 {
+		query.Set("max_amount", strconv.FormatFloat(input.MaxAmount, 'f', -1, 64))
+	}
+	for key, value := range input.Metadata {
+		query.Set("metadata."+key, value)
+	}
+	if input.Search != "" {
+		query.Set("search", input.Search)
+	}
+	if input.Limit > 0 {
+		query.Set("limit", strconv.Itoa(input.Limit))
+	}
+	if input.Cursor != "" {
+		query.Set("cursor", input.Cursor)
+	}
+
+	path := "/invoice"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	page := &InvoicePage{}
+	header, err := c.do(ctx, http.MethodGet, path, nil, &page.Invoices)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = header.Get(nextCursorHeader)
+	return page, nil
+}
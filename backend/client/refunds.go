@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Status dos reembolsos
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusCompleted = "completed"
+	RefundStatusRejected  = "rejected"
+	RefundStatusExpired   = "expired"
+)
+
+// Decisões sobre um reembolso pendente
+const (
+	RefundDecisionApproved = "approved"
+	RefundDecisionRejected = "rejected"
+)
+
+// RefundInput é o pedido de reembolso de uma fatura; Amount 0 reembolsa o que ainda não foi reembolsado
+type RefundInput struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// Refund é um reembolso; acima do limite de aprovação da conta ele fica pendente até ExpiresAt
+type Refund struct {
+	ID          string     `json:"id"`
+	InvoiceID   string     `json:"invoice_id"`
+	Amount      float64    `json:"amount"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RequestRefund pede o reembolso de uma fatura aprovada
+func (c *Client) RequestRefund(ctx context.Context, invoiceID string, input RefundInput) (*Refund, error) {
+	var refund Refund
+	if _, err := c.do(ctx, http.MethodPost, "/invoice/"+url.PathEscape(invoiceID)+"/refunds", input, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// ListRefunds lista os reembolsos da conta; status vazio lista todos
+func (c *Client) ListRefunds(ctx context.Context, status string) ([]*Refund, error) {
+	path := "/accounts/refunds"
+	if status != "" {
+		path += "?" + url.Values{"status": {status}}.Encode()
+	}
+	var refunds []*Refund
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &refunds); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// DecideRefund aprova ou recusa um reembolso pendente, com RefundDecisionApproved ou RefundDecisionRejected
+func (c *Client) DecideRefund(ctx context.Context, id, decision string) (*Refund, error) {
+	var refund Refund
+	input := struct {
+		Decision string `json:"decision"`
+	}{decision}
+	if _, err := c.do(ctx, http.MethodPost, "/accounts/refunds/"+url.PathEscape(id)+"/decision", input, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/webhook"
+)
+
+// WebhookEvent é um evento recebido por webhook; Data depende de Type, como security.country_not_allowed ou
+// subscription.charge_failed
+type WebhookEvent struct {
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookVerification é a conferência de uma assinatura feita pelo gateway; Reason explica a recusa
+type WebhookVerification struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ParseWebhook confere a assinatura de um webhook recebido, com o API Key do cliente como segredo e a tolerância
+// webhook.DefaultTolerance, e decodifica o evento
+// header e body são os da requisição recebida, com o corpo exatamente como chegou; uma assinatura que não confere
+// retorna webhook.ErrInvalidSignature e um timestamp fora da tolerância, webhook.ErrStaleTimestamp
+func (c *Client) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	timestamp, err := strconv.ParseInt(header.Get(webhook.TimestampHeader), 10, 64)
+	if err != nil {
+		return nil, webhook.ErrInvalidSignature
+	}
+	if err := webhook.Verify(c.apiKey, timestamp, body, header.Get(webhook.SignatureHeader), webhook.DefaultTolerance); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// VerifyWebhookSignature pede ao gateway, em POST /webhooks/verify, a conferência de uma assinatura, para testar a
+// verificação implementada pelo lojista
+func (c *Client) VerifyWebhookSignature(ctx context.Context, payload []byte, timestamp int64, signature string) (*WebhookVerification, error) {
+	input := struct {
+		Payload   string `json:"payload"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}{string(payload), timestamp, signature}
+	var verification WebhookVerification
+	if _, err := c.do(ctx, http.MethodPost, "/webhooks/verify", input, &verification); err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
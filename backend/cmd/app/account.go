@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// cliActor identifica na auditoria as mudanças feitas pelos comandos da CLI
+const cliActor = "system:cli"
+
+// newAccountCommand cria gateway account, que administra as contas direto no armazenamento de STORAGE
+func newAccountCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Administra as contas sem passar pela API",
+	}
+
+	var input dto.CreateAccountInput
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Cria uma conta e imprime ela em JSON, com o API Key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCommand("account create", func(ctx context.Context, app *lifecycle) error {
+				return createAccount(ctx, app, input)
+			})
+		},
+	}
+	create.Flags().StringVar(&input.Name, "name", "", "nome da conta")
+	create.Flags().StringVar(&input.Email, "email", "", "e-mail da conta")
+	create.Flags().StringSliceVar(&input.Scopes, "scopes", nil, "escopos do API Key, separados por vírgula; vazio concede todos")
+	create.Flags().BoolVar(&input.TestMode, "test-mode", false, "cria a conta em modo de teste, com as faturas decididas pelo adquirente simulado")
+	create.MarkFlagRequired("name")
+	create.MarkFlagRequired("email")
+
+	cmd.AddCommand(create)
+	return cmd
+}
+
+// createAccount cria a conta como POST /accounts e a imprime na saída padrão
+func createAccount(ctx context.Context, app *lifecycle, input dto.CreateAccountInput) error {
+	// No armazenamento em memória a conta sumiria com o fim do comando
+	if config.Get("STORAGE", "sql") == "memory" {
+		return configError("storage", "STORAGE", errors.New("account create requires a persistent storage, STORAGE is \"memory\""))
+	}
+	repos, err := openStorage(app, newHealthChecker())
+	if err != nil {
+		return err
+	}
+
+	accountService := service.NewAccountService(repos.accountRepository, config.GetDuration("API_KEY_MISS_CACHE_TTL", 30*time.Second))
+	output, err := accountService.CreateAccount(requestctx.WithActor(ctx, cliActor), input)
+	if err != nil {
+		return err
+	}
+	slog.Info("Account created", "account_id", output.ID)
+	return json.NewEncoder(os.Stdout).Encode(output)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// shutdownPhase ordena o desligamento: uma fase só começa depois que a anterior terminou
+type shutdownPhase int
+
+const (
+	// phaseHTTP para de aceitar conexões e espera as requisições em andamento
+	phaseHTTP shutdownPhase = iota
+	// phaseConsumers termina a mensagem do Kafka em processamento e para as tarefas em segundo plano,
+	// para que nada mais gere eventos
+	phaseConsumers
+	// phaseQueues espera as entregas em segundo plano, como as dos webhooks
+	phaseQueues
+	// phaseClients fecha as conexões com o Kafka e o Redis
+	phaseClients
+	// phaseStorage fecha os bancos
+	phaseStorage
+	// phaseTelemetry envia ao rastreador os erros que ainda estão na fila
+	phaseTelemetry
+)
+
+// shutdownHook é um passo do desligamento
+type shutdownHook struct {
+	phase shutdownPhase
+	name  string
+	stop  func(ctx context.Context) error
+}
+
+// lifecycle coordena o desligamento da aplicação: os componentes registram como param e em que fase, e
+// shutdown os encerra na ordem das fases
+type lifecycle struct {
+	hooks []shutdownHook
+}
+
+// onShutdown registra um passo do desligamento; dentro da fase os passos rodam na ordem inversa do registro,
+// como defers, para que o que foi criado por último feche primeiro
+func (l *lifecycle) onShutdown(phase shutdownPhase, name string, stop func(ctx context.Context) error) {
+	l.hooks = append(l.hooks, shutdownHook{phase: phase, name: name, stop: stop})
+}
+
+// onClose registra o fechamento de um componente que não recebe contexto
+func (l *lifecycle) onClose(phase shutdownPhase, name string, close func() error) {
+	l.onShutdown(phase, name, func(context.Context) error { return close() })
+}
+
+// run inicia uma tarefa em segundo plano; no desligamento, na fase informada, o contexto dela é cancelado e o
+// desligamento espera ela retornar
+func (l *lifecycle) run(phase shutdownPhase, name string, task func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		task(ctx)
+	}()
+
+	l.onShutdown(phase, name, func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
+// shutdown executa os passos fase a fase; o prazo de ctx vale para o desligamento inteiro
+// Um passo que falha ou estoura o prazo vai para o log e não impede os seguintes, para que os bancos
+// sempre fechem
+func (l *lifecycle) shutdown(ctx context.Context) {
+	hooks := slices.Clone(l.hooks)
+	slices.Reverse(hooks)
+	slices.SortStableFunc(hooks, func(a, b shutdownHook) int { return int(a.phase - b.phase) })
+
+	started := time.Now()
+	for _, hook := range hooks {
+		start := time.Now()
+		if err := hook.stop(ctx); err != nil {
+			slog.Error("Error stopping component", "component", hook.name, "error", err)
+			continue
+		}
+		slog.Info("Component stopped", "component", hook.name, "duration_ms", time.Since(start).Milliseconds())
+	}
+	slog.Info("Shutdown complete", "duration_ms", time.Since(started).Milliseconds())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/client"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/loadgen"
+	"github.com/spf13/cobra"
+)
+
+// newLoadgenCommand cria gateway loadgen, que gera tráfego sintético contra uma instância do gateway
+// Ele só fala HTTP com a instância, então não lê o .env nem abre o armazenamento
+func newLoadgenCommand() *cobra.Command {
+	cfg := loadgen.Config{}
+	var accountsFile string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "loadgen",
+		Short: "Gera consultas de conta e criações de fatura em ritmo fixo e mede os percentis de latência",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.RPS <= 0 {
+				return errors.New("--rps must be positive")
+			}
+			if cfg.Duration <= 0 {
+				return errors.New("--duration must be positive")
+			}
+			if cfg.InvoiceShare < 0 || cfg.InvoiceShare > 1 {
+				return errors.New("--invoice-share must be between 0 and 1")
+			}
+			if cfg.MaxInFlight <= 0 {
+				return errors.New("--max-in-flight must be positive")
+			}
+			if len(cfg.APIKeys) == 0 && accountsFile == "" {
+				return errors.New("at least one --api-key or an --accounts-file is required")
+			}
+			if cfg.RandomSeed == 0 {
+				cfg.RandomSeed = time.Now().UnixNano()
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := runLoadgen(ctx, cfg, accountsFile, asJSON); err != nil {
+				slog.Error("Command failed", "command", "loadgen", "error", err)
+				return exitCode(exitCommand)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&cfg.BaseURL, "url", "http://localhost:8080", "endereço da instância do gateway")
+	cmd.Flags().StringSliceVar(&cfg.APIKeys, "api-key", nil, "API Keys usados nas requisições, divididas entre eles; pode ser repetido")
+	cmd.Flags().StringVar(&accountsFile, "accounts-file", "", "arquivo com as contas impressas por gateway seed, de onde vêm os API Keys")
+	cmd.Flags().Float64Var(&cfg.RPS, "rps", 50, "requisições por segundo")
+	cmd.Flags().DurationVar(&cfg.Duration, "duration", time.Minute, "duração da geração de tráfego")
+	cmd.Flags().Float64Var(&cfg.InvoiceShare, "invoice-share", 0.3, "fração das requisições que criam faturas; o restante consulta a conta")
+	cmd.Flags().IntVar(&cfg.MaxInFlight, "max-in-flight", 256, "requisições sem resposta a partir das quais as seguintes são descartadas")
+	cmd.Flags().DurationVar(&cfg.Timeout, "timeout", 10*time.Second, "tempo máximo de cada requisição")
+	cmd.Flags().Int64Var(&cfg.RandomSeed, "random-seed", 0, "semente das operações, contas e valores sorteados; 0 sorteia uma")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "imprime o resumo em JSON em vez da tabela")
+	return cmd
+}
+
+// runLoadgen gera o tráfego e imprime o resumo na saída padrão
+func runLoadgen(ctx context.Context, cfg loadgen.Config, accountsFile string, asJSON bool) error {
+	if accountsFile != "" {
+		keys, err := readAPIKeys(accountsFile)
+		if err != nil {
+			return err
+		}
+		cfg.APIKeys = append(cfg.APIKeys, keys...)
+	}
+
+	slog.Info("Generating load", "url", cfg.BaseURL, "rps", cfg.RPS, "duration", cfg.Duration, "api_keys", len(cfg.APIKeys), "random_seed", cfg.RandomSeed)
+	report, err := loadgen.Run(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	slog.Info("Load generation finished", "sent", report.Sent, "dropped", report.Dropped)
+	for _, operation := range report.Operations {
+		if operation.Errors > 0 {
+			slog.Warn("Requests without response", "operation", operation.Operation, "errors", operation.Errors, "last_error", operation.LastError)
+		}
+	}
+
+	if asJSON {
+		return json.NewEncoder(os.Stdout).Encode(report)
+	}
+	return printLoadReport(os.Stdout, report)
+}
+
+// readAPIKeys lê os API Keys das contas impressas por gateway seed ou gateway account create
+func readAPIKeys(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var accounts []client.Account
+	if err := json.Unmarshal(content, &accounts); err != nil {
+		// account create imprime uma conta só
+		var account client.Account
+		if json.Unmarshal(content, &account) != nil {
+			return nil, fmt.Errorf("accounts file %s: %w", path, err)
+		}
+		accounts = []client.Account{account}
+	}
+
+	var keys []string
+	for _, account := range accounts {
+		if account.APIKey != "" {
+			keys = append(keys, account.APIKey)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("accounts file %s has no api keys", path)
+	}
+	return keys, nil
+}
+
+// printLoadReport imprime o resumo como tabela, uma linha por operação
+func printLoadReport(w io.Writer, report *loadgen.Report) error {
+	fmt.Fprintf(w, "elapsed %.1fs, sent %d, dropped %d\n\n", report.Elapsed, report.Sent, report.Dropped)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "operation\trequests\trps\terrors\tstatuses\tp50 ms\tp90 ms\tp95 ms\tp99 ms\tmax ms\t")
+	for _, operation := range report.Operations {
+		fmt.Fprintf(table, "%s\t%d\t%.1f\t%d\t%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			operation.Operation, operation.Requests, operation.RPS, operation.Errors, formatStatuses(operation.Statuses),
+			operation.Latency.P50, operation.Latency.P90, operation.Latency.P95, operation.Latency.P99, operation.Latency.Max)
+	}
+	return table.Flush()
+}
+
+// formatStatuses lista as respostas por status HTTP, em ordem crescente, como 201:90 422:10
+func formatStatuses(statuses map[int]int) string {
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	formatted := ""
+	for i, code := range codes {
+		if i > 0 {
+			formatted += " "
+		}
+		formatted += fmt.Sprintf("%d:%d", code, statuses[code])
+	}
+	return formatted
+}
@@ -2,86 +2,92 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
-	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
-	"github.com/joaodematejr/imersao22/go-gateway/internal/web/server"
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/spf13/cobra"
 )
 
-// getEnv retorna variável de ambiente ou valor padrão se não definida
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
 func main() {
-	// Carrega variáveis de ambiente do arquivo .env
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
-	}
-
-	// Configura conexão com PostgreSQL usando variáveis de ambiente
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		getEnv("DB_HOST", "db"),
-		getEnv("DB_PORT", "5432"),
-		getEnv("DB_USER", "postgres"),
-		getEnv("DB_PASSWORD", "postgres"),
-		getEnv("DB_NAME", "gateway"),
-		getEnv("DB_SSL_MODE", "disable"),
-	)
+	os.Exit(execute(newRootCommand()))
+}
 
-	// Inicializa conexão com o banco
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		log.Fatal("Error connecting to database: ", err)
+// newRootCommand monta a CLI do gateway; sem subcomando ela sobe a API, como gateway serve
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:     "gateway",
+		Short:   "Gateway de pagamentos",
+		Version: buildinfo.Version,
+		Args:    cobra.NoArgs,
+		// Os erros dos comandos já vão para o log; os de uso são impressos por execute
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe()
+		},
 	}
-	defer db.Close()
-
-	// Configura e inicializa o Kafka
-	baseKafkaConfig := service.NewKafkaConfig()
+	root.AddCommand(newServeCommand(), newMigrateCommand(), newAccountCommand(), newSeedCommand(), newLoadgenCommand())
+	return root
+}
 
-	// Configura e inicializa o produtor Kafka
-	producerTopic := getEnv("KAFKA_PRODUCER_TOPIC", "pending_transactions")
-	producerConfig := baseKafkaConfig.WithTopic(producerTopic)
-	kafkaProducer := service.NewKafkaProducer(producerConfig)
-	defer kafkaProducer.Close()
+// exitCode encerra o processo com o código informado; o motivo já foi para o log
+type exitCode int
 
-	// Inicializa camadas da aplicação (repository -> service -> server)
-	accountRepository := repository.NewAccountRepository(db)
-	accountService := service.NewAccountService(accountRepository)
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit code %d", int(c))
+}
 
-	invoiceRepository := repository.NewInvoiceRepository(db)
-	invoiceService := service.NewInvoiceService(invoiceRepository, *accountService, kafkaProducer)
+// execute roda a linha de comando e retorna o código de saída do processo
+// Subcomandos, argumentos e flags inválidos saem com exitUsage
+func execute(root *cobra.Command) int {
+	err := root.Execute()
+	var code exitCode
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &code):
+		return int(code)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\nRun '%s --help' for usage.\n", err, root.CommandPath())
+		return exitUsage
+	}
+}
 
-	// Configura e inicializa o consumidor Kafka
-	consumerTopic := getEnv("KAFKA_CONSUMER_TOPIC", "transaction_results")
-	consumerConfig := baseKafkaConfig.WithTopic(consumerTopic)
-	groupID := getEnv("KAFKA_CONSUMER_GROUP_ID", "gateway-group")
-	kafkaConsumer := service.NewKafkaConsumer(consumerConfig, groupID, invoiceService)
-	defer kafkaConsumer.Close()
+// runCommand prepara o ambiente de um comando de operação e executa task; ao final, desliga o que ela abriu
+// As falhas da preparação saem como as da subida de serve e as da própria tarefa com exitCommand; SIGINT e
+// SIGTERM cancelam o contexto da tarefa
+func runCommand(name string, task func(ctx context.Context, app *lifecycle) error) error {
+	app := &lifecycle{}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Inicia o consumidor Kafka em uma goroutine
-	go func() {
-		if err := kafkaConsumer.Consume(context.Background()); err != nil {
-			log.Printf("Error consuming kafka messages: %v", err)
+	err := func() (err error) {
+		defer recoverStartup(&err)
+		if _, err := loadEnvironment(app); err != nil {
+			return err
 		}
+		return task(ctx, app)
 	}()
+	var failure *startupError
+	if errors.As(err, &failure) {
+		return exitCode(abortStartup(app, err))
+	}
+	if err != nil {
+		slog.Error("Command failed", "command", name, "error", err)
+	}
 
-	// Configura e inicia o servidor HTTP
-	port := getEnv("HTTP_PORT", "8080")
-	srv := server.NewServer(accountService, invoiceService, port)
-	srv.ConfigureRoutes()
-
-	if err := srv.Start(); err != nil {
-		log.Fatal("Error starting server: ", err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.GetDuration("SHUTDOWN_TIMEOUT", 30*time.Second))
+	defer cancel()
+	app.shutdown(shutdownCtx)
+	if err != nil {
+		return exitCode(exitCommand)
 	}
+	return nil
 }
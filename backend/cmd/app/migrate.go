@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/migrations"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCommand cria gateway migrate, que aplica e desfaz no banco de DB_DRIVER as migrations embutidas no
+// binário
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Aplica ou desfaz as migrations do banco (somente no armazenamento SQL)",
+	}
+
+	var upSteps int
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Aplica as migrations pendentes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if upSteps < 0 {
+				return errors.New("--steps must not be negative")
+			}
+			return migrate("up", func(ctx context.Context, migrator *database.Migrator) (uint, error) {
+				return migrator.Up(ctx, upSteps)
+			})
+		},
+	}
+	up.Flags().IntVar(&upSteps, "steps", 0, "aplica no máximo este número de migrations; 0 aplica todas")
+
+	var downSteps int
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Desfaz as últimas migrations aplicadas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if downSteps <= 0 {
+				return errors.New("--steps must be positive")
+			}
+			return migrate("down", func(ctx context.Context, migrator *database.Migrator) (uint, error) {
+				return migrator.Down(ctx, downSteps)
+			})
+		},
+	}
+	down.Flags().IntVar(&downSteps, "steps", 1, "número de migrations desfeitas, da mais recente para a mais antiga")
+
+	cmd.AddCommand(up, down)
+	return cmd
+}
+
+// migrate conecta ao banco de DB_DRIVER e executa run com as migrations embutidas no binário
+func migrate(direction string, run func(ctx context.Context, migrator *database.Migrator) (uint, error)) error {
+	return runCommand("migrate "+direction, func(ctx context.Context, app *lifecycle) error {
+		if storage := config.Get("STORAGE", "sql"); storage == "memory" || storage == "mongodb" {
+			return configError("migrations", "STORAGE", fmt.Errorf("migrations only apply to the sql storage, STORAGE is %q", storage))
+		}
+		dialect, err := repository.DialectFor(config.DatabaseDriver())
+		if err != nil {
+			return configError("database", "DB_DRIVER", err)
+		}
+		db, _, err := openDatabase(app, dialect, config.MigrationPoolConfig())
+		if err != nil {
+			return err
+		}
+
+		files, err := migrations.FS(dialect.Name())
+		if err != nil {
+			return softwareError("migrations", err)
+		}
+		migrator, err := database.NewMigrator(db, dialect.Name(), files)
+		if err != nil {
+			return unavailableError("migrations", "DB_HOST", err)
+		}
+		app.onClose(phaseStorage, "migrations", migrator.Close)
+
+		version, err := run(ctx, migrator)
+		if err != nil {
+			return err
+		}
+		slog.Info("Migrations finished", "direction", direction, "version", version)
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/seed"
+	"github.com/spf13/cobra"
+)
+
+// newSeedCommand cria gateway seed, que popula o armazenamento de STORAGE com contas, faturas e lançamentos fictícios
+func newSeedCommand() *cobra.Command {
+	cfg := seed.Config{}
+	var randomSeed int64
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Popula o armazenamento com contas, faturas e lançamentos fictícios e imprime as contas em JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Accounts <= 0 {
+				return errors.New("--accounts must be positive")
+			}
+			if cfg.Invoices < 0 {
+				return errors.New("--invoices must not be negative")
+			}
+			if cfg.Days <= 0 {
+				return errors.New("--days must be positive")
+			}
+			if randomSeed == 0 {
+				randomSeed = time.Now().UnixNano()
+			}
+			return runCommand("seed", func(ctx context.Context, app *lifecycle) error {
+				return seedStorage(ctx, app, cfg, randomSeed)
+			})
+		},
+	}
+	cmd.Flags().IntVar(&cfg.Accounts, "accounts", 50, "número de contas criadas")
+	cmd.Flags().IntVar(&cfg.Invoices, "invoices", 5000, "número de faturas, divididas entre as contas")
+	cmd.Flags().IntVar(&cfg.Days, "days", 90, "as faturas ficam espalhadas pelos últimos dias informados")
+	cmd.Flags().BoolVar(&cfg.TestMode, "test-mode", false, "cria as contas em modo de teste")
+	cmd.Flags().Int64Var(&randomSeed, "random-seed", 0, "semente dos valores sorteados, para repetir a mesma massa; 0 sorteia uma")
+	return cmd
+}
+
+// seedStorage grava a massa gerada e imprime as contas criadas, com os API Keys, na saída padrão
+// Cada conta é gravada com as suas faturas, cartões e lançamentos antes da seguinte, então uma falha no meio deixa
+// as contas anteriores completas
+func seedStorage(ctx context.Context, app *lifecycle, cfg seed.Config, randomSeed int64) error {
+	// No armazenamento em memória a massa sumiria com o fim do comando
+	if config.Get("STORAGE", "sql") == "memory" {
+		return configError("storage", "STORAGE", errors.New("seed requires a persistent storage, STORAGE is \"memory\""))
+	}
+	repos, err := openStorage(app, newHealthChecker())
+	if err != nil {
+		return err
+	}
+	cardEncryptor, err := config.CardEncryptor(ctx)
+	if err != nil {
+		return unavailableError("card encryption", "KMS_PROVIDER", err)
+	}
+	cardVault := carddata.NewVault(repos.cardRepository, cardEncryptor)
+
+	ctx = requestctx.WithActor(ctx, cliActor)
+	slog.Info("Seeding storage", "accounts", cfg.Accounts, "invoices", cfg.Invoices, "days", cfg.Days, "random_seed", randomSeed)
+	data := seed.Generate(cfg, rand.New(rand.NewSource(randomSeed)), time.Now())
+
+	output := make([]dto.AccountOutput, 0, len(data))
+	ledgerEntries := 0
+	for _, account := range data {
+		if err := repos.accountRepository.Save(ctx, account.Account); err != nil {
+			return err
+		}
+		output = append(output, dto.FromAccount(account.Account))
+		if len(account.Invoices) == 0 {
+			continue
+		}
+		tokens, err := cardVault.TokenizeBatch(ctx, account.Account.ID, account.Cards, cfg.TestMode)
+		if err != nil {
+			return err
+		}
+		for i, invoice := range account.Invoices {
+			invoice.CardToken = tokens[i]
+		}
+		if err := repos.invoiceRepository.SaveBatch(ctx, account.Invoices); err != nil {
+			return err
+		}
+		if err := repos.ledgerRepository.CreateBatch(ctx, account.Ledger); err != nil {
+			return err
+		}
+		ledgerEntries += len(account.Ledger)
+	}
+
+	slog.Info("Seed finished", "accounts", len(output), "invoices", cfg.Invoices, "ledger_entries", ledgerEntries)
+	return json.NewEncoder(os.Stdout).Encode(output)
+}
@@ -0,0 +1,641 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/flags"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/locker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/server"
+	"github.com/joaodematejr/imersao22/go-gateway/migrations"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// newServeCommand cria gateway serve
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Sobe a API, o consumidor do Kafka e as tarefas em segundo plano",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe()
+		},
+	}
+}
+
+// runServe sobe a API, o consumidor do Kafka e as tarefas em segundo plano e os mantém até SIGINT ou SIGTERM
+func runServe() error {
+	// Os componentes registram em app como param; SIGINT e SIGTERM desligam a aplicação em fases, na ordem de
+	// lifecycle.shutdown, com prazo de SHUTDOWN_TIMEOUT
+	app := &lifecycle{}
+	serve, err := bootstrap(app)
+	if err != nil {
+		return exitCode(abortStartup(app, err))
+	}
+
+	serverErrs := make(chan error, 1)
+	go func() {
+		serverErrs <- serve()
+	}()
+
+	// Um listener que para sozinho também desliga a aplicação, mas o processo termina com erro
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	var serverErr error
+	select {
+	case serverErr = <-serverErrs:
+		slog.Error("Error starting server", "error", serverErr)
+	case <-signals.Done():
+		slog.Info("Shutdown signal received, stopping gracefully")
+	}
+	// Um segundo sinal encerra o processo na hora, sem esperar o desligamento
+	stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetDuration("SHUTDOWN_TIMEOUT", 30*time.Second))
+	app.shutdown(ctx)
+	cancel()
+	if serverErr != nil {
+		return exitCode(exitServer)
+	}
+	return nil
+}
+
+// bootstrap configura os componentes, registrando em app como desligá-los, e retorna a função que serve as
+// requisições até os listeners pararem
+// As falhas retornam *startupError com o componente e a variável de configuração envolvidos; um panic também
+// vira *startupError, com a pilha
+func bootstrap(app *lifecycle) (serve func() error, err error) {
+	defer recoverStartup(&err)
+
+	env, err := loadEnvironment(app)
+	if err != nil {
+		return nil, err
+	}
+
+	// A identificação do build vai para o log de subida e para /metrics, para que cada incidente aponte o build exato
+	commit := buildinfo.Revision()
+	metrics.BuildInfo.WithLabelValues(buildinfo.Version, commit, buildinfo.BuildDate, runtime.Version()).Set(1)
+	slog.Info("Starting gateway",
+		"version", buildinfo.Version,
+		"commit", commit,
+		"build_date", buildinfo.BuildDate,
+		"go_version", runtime.Version())
+
+	// Os traces chegam e seguem no formato W3C Trace Context (traceparent e tracestate): das requisições para
+	// os headers das mensagens do Kafka e dos webhooks
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// Os segredos do cofre são recarregados periodicamente para acompanhar rotações
+	if env.secrets != nil {
+		refresh := config.GetDuration("SECRETS_REFRESH_INTERVAL", 5*time.Minute)
+		app.run(phaseConsumers, "secrets refresh", func(ctx context.Context) { env.secrets.Watch(ctx, refresh) })
+	}
+
+	// A injeção de falhas só existe nos binários compilados com -tags faultinject e serve para testes de resiliência
+	faultRules, err := config.FaultInjection()
+	if err != nil {
+		return nil, configError("fault injection", "FAULT_INJECTION", err)
+	}
+	if faultRules != nil {
+		faults.Configure(faultRules)
+		slog.Warn("Fault injection enabled, do not use this binary in production", "components", len(faultRules))
+	}
+
+	// Verifica a saúde das dependências em /readyz; as opcionais, quando falham, não tiram a instância do ar
+	healthChecker := newHealthChecker()
+
+	// Sem migrations pendentes a instância fica fora do ar em /readyz; só existe no armazenamento SQL
+	var migrationCheck func(ctx context.Context) error
+
+	// Tarefas que não podem rodar em paralelo entre as réplicas disputam um lock; no armazenamento SQL ele vem do
+	// próprio banco, nos demais do Redis ou da memória (veja SingletonLocker)
+	var jobLocker locker.Locker
+	lockRetry, err := config.SingletonLockRetry()
+	if err != nil {
+		return nil, configError("singleton locks", "SINGLETON_LOCK_RETRY_INTERVAL", err)
+	}
+
+	repos, err := openStorage(app, healthChecker)
+	if err != nil {
+		return nil, err
+	}
+	if repos.db != nil {
+		if repos.dialect == repository.Postgres {
+			jobLocker = locker.NewPostgres(repos.db)
+		} else {
+			jobLocker = locker.NewMySQL(repos.db)
+		}
+
+		// O schema precisa estar na versão da última migration embutida no binário; DB_REQUIRE_MIGRATIONS=false
+		// desativa a verificação em bancos que não usam o golang-migrate
+		if config.Get("DB_REQUIRE_MIGRATIONS", "true") == "true" {
+			expected, err := migrations.Latest(repos.dialect.Name())
+			if err != nil {
+				return nil, softwareError("migrations", err)
+			}
+			migrationCheck = database.MigrationCheck(repos.db, expected)
+		}
+
+		// Mantém criadas as partições mensais de invoices dos próximos meses (somente PostgreSQL), em uma réplica
+		// por vez
+		if repos.dialect == repository.Postgres {
+			monthsAhead := config.GetInt("DB_PARTITION_MONTHS_AHEAD", 3)
+			interval := config.GetDuration("DB_PARTITION_MAINTENANCE_INTERVAL", 24*time.Hour)
+			app.run(phaseConsumers, "invoice partitions", func(ctx context.Context) {
+				locker.Run(ctx, jobLocker, "invoice-partitions", lockRetry, func(ctx context.Context) {
+					database.MaintainInvoicePartitions(ctx, repos.db, monthsAhead, interval)
+				})
+			})
+		}
+	}
+
+	// Usuários do dashboard entram com e-mail e senha e recebem JWTs; sem JWT_SECRET o login fica desabilitado
+	tokenManager, err := config.TokenManager()
+	if err != nil {
+		return nil, configError("jwt", "JWT_SECRET", err)
+	}
+	if tokenManager == nil {
+		slog.Warn("JWT_SECRET not set, dashboard user login is disabled")
+	}
+
+	// Login por SSO (Keycloak, Auth0 etc.); a descoberta do provedor exige que ele esteja acessível na subida
+	oidcProvider, err := config.OIDCProvider(context.Background())
+	if err != nil {
+		return nil, unavailableError("oidc", "OIDC_ISSUER_URL", err)
+	}
+
+	// Configura e inicializa o Kafka
+	baseKafkaConfig := service.NewKafkaConfig()
+	// Sem o Kafka as faturas de alto valor não seguem para o antifraude nem recebem o resultado
+	healthChecker.AddCheck("kafka", baseKafkaConfig.Ping, false)
+	// O antifraude é opcional: as faturas pendentes esperam no Kafka até ele voltar
+	if url := config.Get("ANTIFRAUD_HEALTHCHECK_URL", ""); url != "" {
+		healthChecker.AddCheck("antifraud", database.HTTPPing(url), true)
+	}
+
+	// Configura e inicializa o produtor Kafka
+	producerTopic := config.Get("KAFKA_PRODUCER_TOPIC", "pending_transactions")
+	producerConfig := baseKafkaConfig.WithTopic(producerTopic)
+	// Com o Kafka lento ou fora do ar o circuit breaker recusa as faturas pendentes na hora, em vez de segurar
+	// cada requisição até KAFKA_PRODUCER_TIMEOUT
+	kafkaBreaker, err := config.CircuitBreaker("kafka_producer", config.GetDuration("KAFKA_PRODUCER_TIMEOUT", 5*time.Second))
+	if err != nil {
+		return nil, configError("kafka circuit breaker", "CIRCUIT_BREAKER_FAILURES", err)
+	}
+	kafkaProducer := service.NewKafkaProducer(producerConfig, kafkaBreaker)
+	app.onClose(phaseClients, "kafka producer", kafkaProducer.Close)
+
+	// Cartões ficam no cofre de carddata, cifrados com uma chave própria; as faturas guardam apenas o token
+	cardEncryptor, err := config.CardEncryptor(context.Background())
+	if err != nil {
+		return nil, unavailableError("card encryption", "KMS_PROVIDER", err)
+	}
+	if cardEncryptor == nil {
+		slog.Warn("Card encryption key not configured, card numbers will not be retained")
+	}
+	cardVault := carddata.NewVault(repos.cardRepository, cardEncryptor)
+
+	// Base local de faixas de IP por país, usada pelas políticas geográficas das contas
+	geoDatabase, err := config.GeoIPDatabase()
+	if err != nil {
+		return nil, configError("geoip", "GEOIP_DATABASE", err)
+	}
+	if geoDatabase == nil {
+		slog.Warn("GEOIP_DATABASE not set, account geo policies are not enforced")
+	} else {
+		slog.Info("Loaded GeoIP ranges", "ranges", geoDatabase.Len())
+	}
+
+	// Inicializa camadas da aplicação (repository -> service -> server)
+	// API Keys inexistentes ficam em cache por pouco tempo, amortecendo tentativas de enumeração
+	accountService := service.NewAccountService(repos.accountRepository, config.GetDuration("API_KEY_MISS_CACHE_TTL", 30*time.Second))
+	// Organizações das implantações white-label, com administradores, marca, tarifa e tetos de gasto próprios
+	tenantService := service.NewTenantService(repos.tenantRepository, accountService)
+	// Eventos de segurança de cada conta vão para o webhook que ela assinou, com o API Key como segredo
+	securityWebhookService := service.NewSecurityWebhookService(repos.webhookRepository, accountService, config.GetDuration("MERCHANT_WEBHOOK_TIMEOUT", 10*time.Second))
+	app.onShutdown(phaseQueues, "security webhooks", securityWebhookService.Drain)
+	// Autenticações e faturas de países fora da política da conta são recusadas ou marcadas
+	geoService := service.NewGeoRiskService(repos.geoPolicyRepository, accountService, geoDatabase, securityWebhookService)
+	// Com REDIS_URL o limite de requisições, as regras de frequência e os nonces das requisições assinadas valem
+	// para todas as réplicas
+	redisClient, err := config.RedisClient()
+	if err != nil {
+		return nil, configError("redis", "REDIS_URL", err)
+	}
+	var nonces middleware.NonceStore = middleware.NewMemoryNonceStore()
+	if redisClient != nil {
+		app.onClose(phaseClients, "redis", redisClient.Close)
+		nonces = middleware.NewRedisNonceStore(redisClient, config.RedisRequestTimeout())
+		// Sem o Redis apenas as requisições assinadas são recusadas e as regras de frequência deixam de contar, então
+		// ele não tira a instância do ar
+		healthChecker.AddCheck("redis", redisClient.Ping, true)
+	}
+
+	// Faturas e lotes que passariam do teto de gasto diário ou mensal da conta são recusados
+	spendingLimits, err := config.SpendingLimits()
+	if err != nil {
+		return nil, configError("spending limits", "SPENDING_LIMIT_DAILY", err)
+	}
+	// Cartões, pagadores e contas acima do número de transações das regras de frequência são recusados
+	velocityChecker, err := config.VelocityChecker(redisClient)
+	if err != nil {
+		return nil, configError("velocity rules", "VELOCITY_RULES", err)
+	}
+	// Cartões, e-mails, documentos e IPs nas listas de bloqueio global ou da conta têm as faturas recusadas
+	blocklistHashKey, err := config.BlocklistHashKey()
+	if err != nil {
+		return nil, configError("blocklist", "BLOCKLIST_HASH_KEY", err)
+	}
+	blocklistService := service.NewBlocklistService(repos.blocklistRepository, accountService, blocklistHashKey, securityWebhookService)
+	// Faturas divididas creditam as contas recebedoras, descontada a taxa de divisão de cada parte
+	splitFeePercent, err := config.SplitFeePercent()
+	if err != nil {
+		return nil, configError("split", "SPLIT_FEE_PERCENT", err)
+	}
+	// Contas filhas de plataformas têm a comissão retida nos créditos e registrada no razão
+	platformService := service.NewPlatformService(repos.platformRepository, accountService)
+	ledgerService := service.NewLedgerService(repos.ledgerRepository, accountService)
+	// Faturas aprovadas com escrow_days ficam em custódia até o prazo ou a liberação pela conta dona
+	escrowReleaseInterval, err := config.EscrowReleaseInterval()
+	if err != nil {
+		return nil, configError("escrow", "ESCROW_RELEASE_INTERVAL", err)
+	}
+	escrowService := service.NewEscrowService(repos.escrowRepository, repos.invoiceRepository, accountService, ledgerService, escrowReleaseInterval)
+	// As demais ficam retidas até a data de repasse do prazo da conta, liberadas pela mesma tarefa da custódia
+	payoutDefaultDelay, err := config.PayoutDefaultDelay()
+	if err != nil {
+		return nil, configError("payouts", "PAYOUT_DEFAULT_SCHEDULE", err)
+	}
+	payoutScheduleService := service.NewPayoutScheduleService(repos.payoutRepository, accountService, payoutDefaultDelay)
+	splitService := service.NewSplitService(repos.splitRepository, accountService, platformService, ledgerService, escrowService, payoutScheduleService, splitFeePercent)
+	// Os valores retidos até a data de repasse podem ser antecipados pela conta, com taxa; sem ela, não podem
+	anticipationMonthlyRate, err := config.AnticipationMonthlyRate()
+	if err != nil {
+		return nil, configError("anticipation", "ANTICIPATION_MONTHLY_RATE", err)
+	}
+	anticipationService := service.NewAnticipationService(repos.anticipationRepository, repos.escrowRepository, accountService, anticipationMonthlyRate)
+	// Os saldos das contas são recalculados pelo razão e comparados com os gravados
+	reconciliationConfig, err := config.Reconciliation()
+	if err != nil {
+		return nil, configError("reconciliation", "BALANCE_RECONCILIATION_INTERVAL", err)
+	}
+	reconciliationService := service.NewReconciliationService(repos.accountRepository, repos.ledgerRepository, reconciliationConfig)
+	// Cupons de desconto das contas, aplicados na criação das faturas
+	couponService := service.NewCouponService(repos.couponRepository, accountService)
+	// Impostos somados às faturas na criação; sem TAX_PROVIDER as faturas não têm impostos
+	taxCalculator, err := config.TaxCalculator()
+	if err != nil {
+		return nil, configError("taxes", "TAX_PROVIDER", err)
+	}
+	taxService := service.NewTaxService(taxCalculator, repos.invoiceTaxRepository)
+	// Clientes das contas, com os cartões guardados no cofre para as próximas faturas
+	customerService := service.NewCustomerService(repos.customerRepository, cardVault, accountService)
+	// Multa e juros das faturas com vencimento pagas depois dele
+	lateFees, err := config.LateFees()
+	if err != nil {
+		return nil, configError("late fees", "LATE_FEE_FINE_PERCENT", err)
+	}
+	// Faturas em outra moeda, convertidas pela cotação travada na criação; sem FX_PROVIDER só a moeda de liquidação
+	fxConfig, err := config.FX()
+	if err != nil {
+		return nil, configError("fx", "FX_PROVIDER", err)
+	}
+	fxService := service.NewFXService(fxConfig)
+	// Faixas de valor aceitas por fatura: a padrão, a própria de cada conta e a de cada meio de pagamento
+	amountLimits, err := config.AmountLimits()
+	if err != nil {
+		return nil, configError("amount limits", "AMOUNT_LIMITS_BY_PAYMENT_TYPE", err)
+	}
+	amountLimitService := service.NewAmountLimitService(repos.amountLimitRepository, accountService, amountLimits)
+	invoiceService := service.NewInvoiceService(repos.invoiceRepository, *accountService, kafkaProducer, cardVault, geoService, spendingLimits, velocityChecker, blocklistService, splitService, couponService, taxService, repos.invoiceItemRepository, customerService, tenantService, lateFees, fxService, amountLimitService)
+	// Resultados do antifraude com score de risco entre os limites da conta vão para a fila de revisão manual
+	riskReviewConfig, err := config.RiskReview()
+	if err != nil {
+		return nil, configError("risk review", "RISK_APPROVE_BELOW", err)
+	}
+	riskReviewService := service.NewRiskReviewService(repos.riskPolicyRepository, repos.reviewRepository, repos.invoiceRepository, invoiceService, accountService, riskReviewConfig)
+	// Reembolsos acima do limite da conta esperam a aprovação de outro usuário e expiram sem ela; passado o prazo de
+	// reembolso da conta, só os administradores globais reembolsam a fatura
+	refundConfig, err := config.Refund()
+	if err != nil {
+		return nil, configError("refunds", "REFUND_APPROVAL_TTL", err)
+	}
+	refundService := service.NewRefundService(repos.refundRepository, repos.refundPolicyRepository, repos.refundWindowRepository, repos.invoiceRepository, accountService, splitService, refundConfig)
+	// Assinaturas são cobradas no cartão do cofre a cada período, com retentativas das cobranças recusadas
+	subscriptionConfig, err := config.Subscription()
+	if err != nil {
+		return nil, configError("subscriptions", "DUNNING_RETRY_DAYS", err)
+	}
+	subscriptionService := service.NewSubscriptionService(repos.subscriptionRepository, invoiceService, cardVault, accountService, subscriptionConfig)
+	// Ordens permanentes transferem parte do saldo das contas para outra conta da organização a cada período
+	standingOrderConfig, err := config.StandingOrder()
+	if err != nil {
+		return nil, configError("standing orders", "STANDING_ORDER_INTERVAL", err)
+	}
+	standingOrderService := service.NewStandingOrderService(repos.standingOrderRepository, accountService, standingOrderConfig)
+	auditService := service.NewAuditService(repos.auditRepository)
+	// Pedidos de titulares (LGPD/GDPR): exportação e anonimização dos dados pessoais dos pagadores
+	dataSubjectService := service.NewDataSubjectService(repos.invoiceRepository, repos.dataSubjectRepository, accountService, cardVault)
+
+	// Relatórios e exportações ficam no armazenamento de objetos e são baixados por links assinados do gateway
+	exportStore, err := config.ExportStore(context.Background())
+	if err != nil {
+		return nil, configError("export storage", "EXPORT_STORAGE", err)
+	}
+	exportConfig := service.ExportConfig{
+		BaseURL:   strings.TrimSuffix(config.Get("DOWNLOAD_BASE_URL", "http://localhost:"+config.Get("HTTP_PORT", "8080")), "/"),
+		Secret:    []byte(config.Get("DOWNLOAD_URL_SECRET", "")),
+		LinkTTL:   config.GetDuration("DOWNLOAD_URL_TTL", 15*time.Minute),
+		Retention: config.GetDuration("EXPORT_RETENTION", 7*24*time.Hour),
+	}
+	if exportStore == nil {
+		slog.Warn("EXPORT_STORAGE not set, report and export downloads and dispute evidence uploads are disabled")
+	} else if len(exportConfig.Secret) < 32 {
+		return nil, configError("export storage", "DOWNLOAD_URL_SECRET", errors.New("DOWNLOAD_URL_SECRET must have at least 32 characters when EXPORT_STORAGE is set"))
+	} else if exportConfig.LinkTTL <= 0 || exportConfig.Retention <= 0 {
+		return nil, configError("export storage", "DOWNLOAD_URL_TTL", errors.New("DOWNLOAD_URL_TTL and EXPORT_RETENTION must be positive"))
+	}
+	exportService := service.NewExportService(repos.exportRepository, repos.invoiceRepository, dataSubjectService, accountService, exportStore, exportConfig)
+	// Tarifas das contas; sem tarifas próprias vale CHARGEBACK_FEE
+	chargebackFee, err := config.ChargebackFee()
+	if err != nil {
+		return nil, configError("fee schedules", "CHARGEBACK_FEE", err)
+	}
+	feeScheduleService := service.NewFeeScheduleService(repos.feeScheduleRepository, accountService, tenantService, chargebackFee)
+	// As evidências das disputas usam o mesmo armazenamento de objetos das exportações
+	disputeService := service.NewDisputeService(repos.disputeRepository, repos.invoiceRepository, accountService, feeScheduleService, exportStore)
+	healthService := service.NewHealthService(healthChecker)
+	// Até as migrations estarem aplicadas e a busca por API Key aquecida /readyz responde "starting",
+	// para que uma réplica nova não receba tráfego durante o rollout
+	if migrationCheck != nil {
+		healthService.AddStartupCheck("migrations", migrationCheck)
+	}
+	healthService.AddStartupCheck("api key lookup", accountService.WarmUp)
+	startupInterval := config.GetDuration("STARTUP_CHECK_INTERVAL", 2*time.Second)
+	app.run(phaseConsumers, "startup checks", func(ctx context.Context) {
+		healthService.RunStartupChecks(ctx, startupInterval)
+	})
+	// Filas de trabalho em segundo plano exibidas em /status
+	healthService.AddQueue("security_webhooks", securityWebhookService.Pending)
+	if env.tracker != nil {
+		healthService.AddQueue("error_tracker", env.tracker.Pending)
+	}
+	// Comportamentos novos ficam atrás de flags, ligadas por conta ou por porcentagem das contas
+	featureFlagList, err := config.FeatureFlags()
+	if err != nil {
+		return nil, configError("feature flags", "FEATURE_FLAGS", err)
+	}
+	featureFlags := flags.New(featureFlagList)
+	featureFlagService := service.NewFeatureFlagService(featureFlags, accountService)
+	// Tentativas de autenticação são registradas; falhas de muitos IPs contra a mesma credencial geram alerta
+	// e falhas consecutivas bloqueiam temporariamente o IP e o API Key
+	securityConfig, err := config.Security()
+	if err != nil {
+		return nil, configError("authentication lockout", "AUTH_LOCKOUT_THRESHOLD", err)
+	}
+	securityService := service.NewSecurityService(repos.authEventRepository, accountService, securityWebhookService, securityConfig)
+	authService := service.NewAuthService(repos.userRepository, accountService, securityService, tokenManager, oidcProvider, config.RefreshTokenTTL())
+	// Ajustes manuais de saldo e exclusões administrativas exigem o código TOTP de quem cadastrou o segundo fator
+	twoFactorService := service.NewTwoFactorService(
+		repos.twoFactorRepository,
+		securityService,
+		config.Get("TWO_FACTOR_ISSUER", "Go Gateway"),
+		config.Get("TWO_FACTOR_REQUIRED", "false") == "true",
+	)
+
+	// Canais dos alertas de anomalia e do alerta interno; ambos são opcionais
+	securityWebhook, err := config.SecurityWebhook(context.Background())
+	if err != nil {
+		return nil, configError("security webhook", "SECURITY_WEBHOOK_SECRET", err)
+	}
+	mailer, err := config.Mailer()
+	if err != nil {
+		return nil, configError("smtp", "SMTP_ADDR", err)
+	}
+
+	// Compara periodicamente o comportamento de cada conta com o seu histórico e alerta sobre mudanças bruscas
+	// Roda em uma réplica por vez, para que cada alerta seja enviado uma vez; começa depois que o locker está pronto
+	var anomalyService *service.AnomalyService
+	if config.Get("ANOMALY_DETECTION", "false") == "true" {
+		thresholds, err := config.AnomalyThresholds()
+		if err != nil {
+			return nil, configError("anomaly detection", "ANOMALY_VOLUME_FACTOR", err)
+		}
+		anomalyConfig := service.AnomalyConfig{
+			Window:            config.GetDuration("ANOMALY_WINDOW", time.Hour),
+			Baseline:          config.GetDuration("ANOMALY_BASELINE", 7*24*time.Hour),
+			AnomalyThresholds: thresholds,
+			AlertEmails:       config.SecurityAlertEmails(),
+		}
+		if anomalyConfig.Window <= 0 || anomalyConfig.Baseline <= 0 {
+			return nil, configError("anomaly detection", "ANOMALY_WINDOW", errors.New("ANOMALY_WINDOW and ANOMALY_BASELINE must be positive"))
+		}
+
+		anomalyService = service.NewAnomalyService(repos.invoiceRepository, repos.authEventRepository, accountService, geoDatabase, securityWebhook, mailer, securityWebhookService, anomalyConfig)
+	}
+
+	if jobLocker == nil {
+		if jobLocker, err = config.SingletonLocker(redisClient); err != nil {
+			return nil, configError("singleton locks", "SINGLETON_LOCK_TTL", err)
+		}
+		if redisClient == nil && config.Get("STORAGE", "sql") != "memory" {
+			slog.Warn("REDIS_URL not set, singleton jobs are only coordinated within this instance")
+		}
+	}
+	if anomalyService != nil {
+		app.run(phaseConsumers, "anomaly detection", func(ctx context.Context) {
+			locker.Run(ctx, jobLocker, "anomaly-detection", lockRetry, anomalyService.Run)
+		})
+	}
+	// As revisões manuais vencidas são decididas por uma réplica de cada vez, para não decidir a mesma duas vezes
+	app.run(phaseConsumers, "review sla", func(ctx context.Context) {
+		locker.Run(ctx, jobLocker, "review-sla", lockRetry, riskReviewService.Run)
+	})
+	// As retenções em custódia vencidas são liberadas por uma réplica de cada vez
+	app.run(phaseConsumers, "escrow release", func(ctx context.Context) {
+		locker.Run(ctx, jobLocker, "escrow-release", lockRetry, escrowService.Run)
+	})
+	// Os saldos são conciliados com o razão por uma réplica de cada vez, para não lançar o saldo de abertura duas vezes
+	if reconciliationService.Enabled() {
+		app.run(phaseConsumers, "balance reconciliation", func(ctx context.Context) {
+			locker.Run(ctx, jobLocker, "balance-reconciliation", lockRetry, reconciliationService.Run)
+		})
+	}
+	// Os reembolsos pendentes vencidos são expirados por uma réplica de cada vez
+	app.run(phaseConsumers, "refund expiry", func(ctx context.Context) {
+		locker.Run(ctx, jobLocker, "refund-expiry", lockRetry, refundService.Run)
+	})
+	// As assinaturas vencidas são cobradas por uma réplica de cada vez, para não cobrar a mesma duas vezes
+	// Sem a chave dos cartões a cobrança fica parada, para que as assinaturas existentes não sejam recusadas em massa
+	if subscriptionService.Enabled() {
+		app.run(phaseConsumers, "subscription billing", func(ctx context.Context) {
+			locker.Run(ctx, jobLocker, "subscription-billing", lockRetry, subscriptionService.Run)
+		})
+	} else {
+		slog.Warn("Card encryption key not configured, subscriptions are disabled")
+	}
+	// As ordens permanentes vencidas são executadas por uma réplica de cada vez, para não transferir duas vezes
+	app.run(phaseConsumers, "standing orders", func(ctx context.Context) {
+		locker.Run(ctx, jobLocker, "standing-orders", lockRetry, standingOrderService.Run)
+	})
+
+	// Limite de requisições por API Key
+	limiter, err := config.RateLimiter(redisClient)
+	if err != nil {
+		return nil, configError("rate limiter", "RATE_LIMIT_RPS", err)
+	}
+
+	// Configura e inicializa o consumidor Kafka
+	consumerTopic := config.Get("KAFKA_CONSUMER_TOPIC", "transaction_results")
+	consumerConfig := baseKafkaConfig.WithTopic(consumerTopic)
+	groupID := config.Get("KAFKA_CONSUMER_GROUP_ID", "gateway-group")
+	kafkaConsumer := service.NewKafkaConsumer(consumerConfig, groupID, invoiceService, riskReviewService)
+	healthService.AddConsumer(consumerTopic, groupID, kafkaConsumer.Lag)
+	app.onClose(phaseClients, "kafka consumer connection", kafkaConsumer.Close)
+
+	// Inicia o consumidor Kafka em uma goroutine; no desligamento a mensagem em processamento termina e é
+	// confirmada antes de a conexão fechar
+	app.run(phaseConsumers, "kafka consumer", func(ctx context.Context) {
+		if err := kafkaConsumer.Consume(ctx); err != nil {
+			slog.Error("Error consuming kafka messages", "error", err)
+		}
+	})
+
+	// Avalia as regras de alerta cadastradas em /admin/alert-rules sobre as métricas desta instância;
+	// ALERT_EVALUATION_INTERVAL=0 desliga a avaliação, mantendo o cadastro
+	alertConfig := service.AlertConfig{
+		Interval: config.GetDuration("ALERT_EVALUATION_INTERVAL", time.Minute),
+		Emails:   config.AlertEmails(),
+	}
+	alertService := service.NewAlertService(repos.alertRuleRepository, securityWebhook, mailer, kafkaConsumer.Lag, alertConfig)
+	if alertConfig.Interval > 0 {
+		app.run(phaseConsumers, "alert rules", alertService.Run)
+	}
+
+	// Os perfis de bloqueio e de contenção de mutex de /admin/debug/pprof só coletam amostras quando habilitados
+	runtime.SetBlockProfileRate(config.GetInt("PPROF_BLOCK_PROFILE_RATE", 0))
+	runtime.SetMutexProfileFraction(config.GetInt("PPROF_MUTEX_PROFILE_FRACTION", 0))
+
+	// Listener opcional com mTLS para chamadas entre serviços; o certificado identifica uma conta ou um serviço interno
+	var mtls *server.MTLSConfig
+	tlsConfig, certificates, err := config.MTLS()
+	if err != nil {
+		return nil, configError("mtls", "MTLS_PORT", err)
+	}
+	if tlsConfig != nil {
+		mtls = &server.MTLSConfig{Port: config.Get("MTLS_PORT", ""), TLS: tlsConfig, Identities: certificates}
+	}
+
+	// Amostragem das linhas de acesso das rotas de alto volume; erros são sempre registrados
+	accessLogConfig, err := config.AccessLog()
+	if err != nil {
+		return nil, configError("access log", "LOG_SAMPLE_RATE", err)
+	}
+	accessLog := middleware.NewAccessLogSampling(accessLogConfig)
+
+	// Objetivo de latência por rota, consultado em GET /admin/slo e exportado em /metrics
+	latencyTracker, err := config.LatencyTracker()
+	if err != nil {
+		return nil, configError("latency slo", "LATENCY_SLO_THRESHOLD", err)
+	}
+	prometheus.MustRegister(latencyTracker)
+	sloService := service.NewSLOService(latencyTracker)
+
+	// SIGHUP e POST /admin/config/reload releem o .env e o cofre de segredos e trocam, sem reiniciar o processo,
+	// o nível e a amostragem dos logs, o limite de requisições, os limites de bloqueio e de anomalias, as flags
+	// e o objetivo de latência
+	reloader := config.NewReloader(func(tunables config.Tunables) {
+		accessLog.Set(tunables.AccessLog)
+		featureFlags.Replace(tunables.Flags)
+		latencyTracker.SetObjective(tunables.LatencySLO)
+		securityService.SetConfig(tunables.Security)
+		if anomalyService != nil {
+			anomalyService.SetThresholds(tunables.Anomaly)
+		}
+		switch {
+		case limiter != nil && tunables.RateLimit != nil:
+			limiter.SetConfig(*tunables.RateLimit)
+		case (limiter == nil) != (tunables.RateLimit == nil):
+			slog.Warn("Enabling or disabling the rate limiter requires a restart, keeping the current setting")
+		}
+	})
+	app.run(phaseConsumers, "config reload signals", reloader.WatchSignals)
+
+	// Configura e inicia o servidor HTTP
+	port := config.Get("HTTP_PORT", "8080")
+	srv := server.NewServer(
+		accountService,
+		invoiceService,
+		auditService,
+		healthService,
+		authService,
+		securityService,
+		twoFactorService,
+		geoService,
+		limiter,
+		nonces,
+		dataSubjectService,
+		exportService,
+		securityWebhookService,
+		featureFlagService,
+		sloService,
+		alertService,
+		blocklistService,
+		riskReviewService,
+		platformService,
+		ledgerService,
+		escrowService,
+		refundService,
+		disputeService,
+		feeScheduleService,
+		subscriptionService,
+		couponService,
+		customerService,
+		payoutScheduleService,
+		anticipationService,
+		reconciliationService,
+		tenantService,
+		amountLimitService,
+		standingOrderService,
+		config.Get("ADMIN_API_KEY", ""),
+		config.GetDuration("AUTH_SIGNATURE_MAX_SKEW", 5*time.Minute),
+		mtls,
+		config.SecurityHeaders(),
+		accessLog,
+		reloader,
+		port,
+		config.Get("METRICS_PORT", "9090"),
+	)
+	srv.ConfigureRoutes()
+	app.onShutdown(phaseHTTP, "http server", srv.Shutdown)
+
+	// Com TLS configurado as rotas são servidas em HTTPS_PORT e HTTP_PORT apenas redireciona para HTTPS
+	serverTLS, acmeHandler, err := config.ServerTLS()
+	if err != nil {
+		return nil, configError("tls", "TLS_CERT_FILE", err)
+	}
+	return func() error {
+		if serverTLS != nil {
+			return srv.StartTLS(&server.TLSConfig{Port: config.Get("HTTPS_PORT", "8443"), TLS: serverTLS, HTTPHandler: acmeHandler})
+		}
+		return srv.Start()
+	}, nil
+}
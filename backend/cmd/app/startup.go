@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+)
+
+// Códigos de saída do processo, para que as ferramentas de orquestração distingam as falhas sem ler o log
+// Os da subida e os de uso seguem os valores de sysexits.h
+const (
+	// exitServer indica que um listener parou sozinho depois da subida
+	exitServer = 1
+	// exitCommand indica que a operação de um comando, como migrate up ou account create, falhou
+	exitCommand = 1
+	// exitUsage indica um subcomando, um argumento ou uma flag inválidos
+	exitUsage = 64
+	// exitUnavailable indica uma dependência que não respondeu na subida, como o banco, o KMS ou o provedor OIDC
+	exitUnavailable = 69
+	// exitSoftware indica um panic ou um defeito do próprio binário na subida
+	exitSoftware = 70
+	// exitConfig indica uma configuração inválida
+	exitConfig = 78
+)
+
+// startupError é uma falha na subida, com o componente e a variável de configuração envolvidos
+type startupError struct {
+	component string
+	// key é a principal variável de configuração do componente; vazia quando não há uma
+	key  string
+	code int
+	err  error
+	// stack só existe nos panics
+	stack string
+}
+
+func (e *startupError) Error() string {
+	return e.component + ": " + e.err.Error()
+}
+
+func (e *startupError) Unwrap() error {
+	return e.err
+}
+
+// configError é uma configuração inválida do componente
+func configError(component, key string, err error) error {
+	return &startupError{component: component, key: key, code: exitConfig, err: err}
+}
+
+// unavailableError é uma dependência que não respondeu na subida; key é a variável que aponta para ela
+func unavailableError(component, key string, err error) error {
+	return &startupError{component: component, key: key, code: exitUnavailable, err: err}
+}
+
+// softwareError é um defeito do próprio binário, como migrations embutidas ilegíveis
+func softwareError(component string, err error) error {
+	return &startupError{component: component, code: exitSoftware, err: err}
+}
+
+// recoverStartup converte um panic da subida em *startupError, com a pilha, e o grava em err
+// Deve ser chamado com defer
+func recoverStartup(err *error) {
+	if r := recover(); r != nil {
+		*err = &startupError{component: "bootstrap", code: exitSoftware, err: fmt.Errorf("panic: %v", r), stack: string(debug.Stack())}
+	}
+}
+
+// abortStartup registra a falha da subida e desliga o que já tinha subido, para fechar os bancos e entregar o
+// erro ao rastreador; retorna o código de saída
+func abortStartup(app *lifecycle, err error) int {
+	var failure *startupError
+	if !errors.As(err, &failure) {
+		failure = &startupError{component: "bootstrap", code: exitSoftware, err: err}
+	}
+
+	args := []any{"component", failure.component, "exit_code", failure.code, "error", failure.err}
+	if failure.key != "" {
+		args = append(args, "config_key", failure.key)
+	}
+	if failure.stack != "" {
+		args = append(args, "stack", failure.stack)
+	}
+	slog.Error("Startup failed", args...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetDuration("SHUTDOWN_TIMEOUT", 30*time.Second))
+	defer cancel()
+	app.shutdown(ctx)
+	return failure.code
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/errortracker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/memory"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/mongodb"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/secrets"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// environment é o que todos os comandos preparam antes de tocar no armazenamento
+type environment struct {
+	// tracker é nil sem ERROR_TRACKER_DSN
+	tracker *errortracker.Tracker
+	// secrets é nil sem SECRETS_PROVIDER
+	secrets *secrets.Store
+}
+
+// loadEnvironment carrega o .env, configura o rastreador de erros e os logs e lê o cofre de segredos, registrando
+// em app como desligá-los
+func loadEnvironment(app *lifecycle) (*environment, error) {
+	// Carrega variáveis de ambiente do arquivo .env; as do ambiente do processo têm precedência
+	if err := config.LoadEnvFile(".env"); err != nil {
+		return nil, configError("env file", "", err)
+	}
+
+	// Erros inesperados e panics vão para o rastreador de ERROR_TRACKER_DSN, quando configurado
+	tracker, err := config.ErrorTracker()
+	if err != nil {
+		return nil, configError("error tracker", "ERROR_TRACKER_DSN", err)
+	}
+
+	// Os logs saem no formato de LOG_FORMAT a partir de LOG_LEVEL, com os dados sensíveis mascarados
+	logger, err := config.Logger(os.Stderr, tracker)
+	if err != nil {
+		return nil, configError("logger", "LOG_FORMAT", err)
+	}
+	slog.SetDefault(logger)
+
+	// O rastreador é o último a desligar, para receber também os erros do desligamento e de uma subida que falhou
+	if tracker != nil {
+		app.onShutdown(phaseTelemetry, "error tracker", func(ctx context.Context) error {
+			tracker.Close(ctx)
+			return nil
+		})
+	}
+
+	// Segredos do Vault ou do AWS Secrets Manager têm precedência sobre o .env
+	secretStore, err := config.LoadSecrets(context.Background())
+	if err != nil {
+		return nil, unavailableError("secrets", "SECRETS_PROVIDER", err)
+	}
+	return &environment{tracker: tracker, secrets: secretStore}, nil
+}
+
+// openDatabase conecta ao banco do dialeto e registra em app como fechá-lo
+// No PostgreSQL também retorna o pool pgx; no MySQL o pool é nil
+func openDatabase(app *lifecycle, dialect repository.Dialect, poolConfig database.PoolConfig) (*sql.DB, *pgxpool.Pool, error) {
+	db, pool, err := database.Open(context.Background(), dialect.Name(), poolConfig)
+	if err != nil {
+		return nil, nil, unavailableError("database", "DB_HOST", err)
+	}
+	if pool != nil {
+		app.onClose(phaseStorage, "primary pool", func() error { pool.Close(); return nil })
+	}
+	app.onClose(phaseStorage, "primary database", db.Close)
+	return db, pool, nil
+}
+
+// newHealthChecker cria a verificação de saúde das dependências de /readyz
+func newHealthChecker() *database.HealthChecker {
+	return database.NewHealthChecker(
+		config.GetFloat("DB_POOL_SATURATION_THRESHOLD", 0.9),
+		config.GetDuration("DB_HEALTHCHECK_TIMEOUT", 2*time.Second),
+	)
+}
+
+// storage reúne os repositórios do armazenamento de STORAGE
+type storage struct {
+	accountRepository       domain.AccountRepository
+	invoiceRepository       domain.InvoiceRepository
+	auditRepository         domain.AuditRepository
+	userRepository          domain.UserRepository
+	authEventRepository     domain.AuthEventRepository
+	cardRepository          carddata.Repository
+	twoFactorRepository     domain.TwoFactorRepository
+	geoPolicyRepository     domain.GeoPolicyRepository
+	dataSubjectRepository   domain.DataSubjectRequestRepository
+	exportRepository        domain.ExportRepository
+	webhookRepository       domain.SecurityWebhookRepository
+	alertRuleRepository     domain.AlertRuleRepository
+	blocklistRepository     domain.BlocklistRepository
+	riskPolicyRepository    domain.RiskPolicyRepository
+	reviewRepository        domain.ReviewRepository
+	splitRepository         domain.SplitRepository
+	platformRepository      domain.PlatformLinkRepository
+	ledgerRepository        domain.LedgerRepository
+	escrowRepository        domain.EscrowRepository
+	refundPolicyRepository  domain.RefundPolicyRepository
+	refundRepository        domain.RefundRepository
+	disputeRepository       domain.DisputeRepository
+	feeScheduleRepository   domain.FeeScheduleRepository
+	subscriptionRepository  domain.SubscriptionRepository
+	couponRepository        domain.CouponRepository
+	invoiceTaxRepository    domain.InvoiceTaxRepository
+	invoiceItemRepository   domain.InvoiceItemRepository
+	customerRepository      domain.CustomerRepository
+	payoutRepository        domain.PayoutScheduleRepository
+	anticipationRepository  domain.AnticipationRepository
+	tenantRepository        domain.TenantRepository
+	amountLimitRepository   domain.AccountAmountLimitsRepository
+	standingOrderRepository domain.StandingOrderRepository
+	refundWindowRepository  domain.AccountRefundWindowRepository
+
+	// db e dialect só existem no armazenamento SQL
+	db      *sql.DB
+	dialect repository.Dialect
+}
+
+// openStorage conecta ao armazenamento de STORAGE: "sql" (padrão, banco de DB_DRIVER), "mongodb" ou "memory", que
+// dispensa o banco para desenvolvimento local
+// Registra em app como fechá-lo e inclui os bancos na verificação de saúde de healthChecker
+func openStorage(app *lifecycle, healthChecker *database.HealthChecker) (*storage, error) {
+	s := &storage{}
+
+	// Chamadas aos repositórios acima do limite vão para o log e para /metrics; 0 desativa
+	repository.SetSlowQueryThreshold(config.GetDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond))
+
+	switch config.Get("STORAGE", "sql") {
+	case "memory":
+		slog.Warn("Using in-memory storage, data will be lost on restart")
+
+		store := memory.NewStore()
+		s.accountRepository = memory.NewAccountRepository(store)
+		s.invoiceRepository = memory.NewInvoiceRepository(store)
+		s.auditRepository = memory.NewAuditRepository(store)
+		s.userRepository = memory.NewUserRepository(store)
+		s.authEventRepository = memory.NewAuthEventRepository(store)
+		s.cardRepository = memory.NewCardRepository(store)
+		s.twoFactorRepository = memory.NewTwoFactorRepository(store)
+		s.geoPolicyRepository = memory.NewGeoPolicyRepository(store)
+		s.dataSubjectRepository = memory.NewDataSubjectRequestRepository(store)
+		s.exportRepository = memory.NewExportRepository(store)
+		s.webhookRepository = memory.NewSecurityWebhookRepository(store)
+		s.alertRuleRepository = memory.NewAlertRuleRepository(store)
+		s.blocklistRepository = memory.NewBlocklistRepository(store)
+		s.riskPolicyRepository = memory.NewRiskPolicyRepository(store)
+		s.reviewRepository = memory.NewReviewRepository(store)
+		s.splitRepository = memory.NewSplitRepository(store)
+		s.platformRepository = memory.NewPlatformLinkRepository(store)
+		s.ledgerRepository = memory.NewLedgerRepository(store)
+		s.escrowRepository = memory.NewEscrowRepository(store)
+		s.refundPolicyRepository = memory.NewRefundPolicyRepository(store)
+		s.refundRepository = memory.NewRefundRepository(store)
+		s.disputeRepository = memory.NewDisputeRepository(store)
+		s.feeScheduleRepository = memory.NewFeeScheduleRepository(store)
+		s.subscriptionRepository = memory.NewSubscriptionRepository(store)
+		s.couponRepository = memory.NewCouponRepository(store)
+		s.invoiceTaxRepository = memory.NewInvoiceTaxRepository(store)
+		s.invoiceItemRepository = memory.NewInvoiceItemRepository(store)
+		s.customerRepository = memory.NewCustomerRepository(store)
+		s.payoutRepository = memory.NewPayoutScheduleRepository(store)
+		s.anticipationRepository = memory.NewAnticipationRepository(store)
+		s.tenantRepository = memory.NewTenantRepository(store)
+		s.amountLimitRepository = memory.NewAmountLimitRepository(store)
+		s.standingOrderRepository = memory.NewStandingOrderRepository(store)
+		s.refundWindowRepository = memory.NewRefundWindowRepository(store)
+	case "mongodb":
+		// As transações do MongoDB exigem um replica set (pode ser de um único nó)
+		client, err := mongodb.Connect(context.Background(), config.Get("MONGODB_URI", "mongodb://localhost:27017/?replicaSet=rs0"))
+		if err != nil {
+			return nil, unavailableError("mongodb", "MONGODB_URI", err)
+		}
+		app.onShutdown(phaseStorage, "mongodb", client.Disconnect)
+
+		store := mongodb.NewStore(client, config.Get("MONGODB_DATABASE", "gateway"))
+		if err := store.EnsureIndexes(context.Background()); err != nil {
+			return nil, unavailableError("mongodb", "MONGODB_URI", err)
+		}
+
+		healthChecker.AddCheck("primary", store.Ping, false)
+
+		encryptor, err := config.PIIEncryptor(context.Background())
+		if err != nil {
+			return nil, unavailableError("pii encryption", "KMS_PROVIDER", err)
+		}
+		if encryptor == nil {
+			slog.Warn("PII encryption key not configured, personal data will be stored in plain text")
+		}
+
+		// Conflitos de escrita são repetidos pelo próprio driver dentro das transações
+		s.accountRepository = repository.NewInstrumentedAccountRepository(mongodb.NewAccountRepository(store, encryptor))
+		s.invoiceRepository = repository.NewInstrumentedInvoiceRepository(mongodb.NewInvoiceRepository(store, encryptor))
+		s.auditRepository = repository.NewInstrumentedAuditRepository(mongodb.NewAuditRepository(store))
+		s.userRepository = repository.NewInstrumentedUserRepository(mongodb.NewUserRepository(store, encryptor))
+		s.authEventRepository = repository.NewInstrumentedAuthEventRepository(mongodb.NewAuthEventRepository(store))
+		s.cardRepository = repository.NewInstrumentedCardRepository(mongodb.NewCardRepository(store))
+		s.twoFactorRepository = repository.NewInstrumentedTwoFactorRepository(mongodb.NewTwoFactorRepository(store, encryptor))
+		s.geoPolicyRepository = repository.NewInstrumentedGeoPolicyRepository(mongodb.NewGeoPolicyRepository(store))
+		s.dataSubjectRepository = repository.NewInstrumentedDataSubjectRequestRepository(mongodb.NewDataSubjectRequestRepository(store))
+		s.exportRepository = repository.NewInstrumentedExportRepository(mongodb.NewExportRepository(store))
+		s.webhookRepository = repository.NewInstrumentedSecurityWebhookRepository(mongodb.NewSecurityWebhookRepository(store))
+		s.alertRuleRepository = repository.NewInstrumentedAlertRuleRepository(mongodb.NewAlertRuleRepository(store))
+		s.blocklistRepository = repository.NewInstrumentedBlocklistRepository(mongodb.NewBlocklistRepository(store))
+		s.riskPolicyRepository = repository.NewInstrumentedRiskPolicyRepository(mongodb.NewRiskPolicyRepository(store))
+		s.reviewRepository = repository.NewInstrumentedReviewRepository(mongodb.NewReviewRepository(store))
+		s.splitRepository = repository.NewInstrumentedSplitRepository(mongodb.NewSplitRepository(store))
+		s.platformRepository = repository.NewInstrumentedPlatformLinkRepository(mongodb.NewPlatformLinkRepository(store))
+		s.ledgerRepository = repository.NewInstrumentedLedgerRepository(mongodb.NewLedgerRepository(store))
+		s.escrowRepository = repository.NewInstrumentedEscrowRepository(mongodb.NewEscrowRepository(store))
+		s.refundPolicyRepository = repository.NewInstrumentedRefundPolicyRepository(mongodb.NewRefundPolicyRepository(store))
+		s.refundRepository = repository.NewInstrumentedRefundRepository(mongodb.NewRefundRepository(store))
+		s.disputeRepository = repository.NewInstrumentedDisputeRepository(mongodb.NewDisputeRepository(store))
+		s.feeScheduleRepository = repository.NewInstrumentedFeeScheduleRepository(mongodb.NewFeeScheduleRepository(store))
+		s.subscriptionRepository = repository.NewInstrumentedSubscriptionRepository(mongodb.NewSubscriptionRepository(store))
+		s.couponRepository = repository.NewInstrumentedCouponRepository(mongodb.NewCouponRepository(store))
+		s.invoiceTaxRepository = repository.NewInstrumentedInvoiceTaxRepository(mongodb.NewInvoiceTaxRepository(store))
+		s.invoiceItemRepository = repository.NewInstrumentedInvoiceItemRepository(mongodb.NewInvoiceItemRepository(store))
+		s.customerRepository = repository.NewInstrumentedCustomerRepository(mongodb.NewCustomerRepository(store, encryptor))
+		s.payoutRepository = repository.NewInstrumentedPayoutScheduleRepository(mongodb.NewPayoutScheduleRepository(store))
+		s.anticipationRepository = repository.NewInstrumentedAnticipationRepository(mongodb.NewAnticipationRepository(store))
+		s.tenantRepository = repository.NewInstrumentedTenantRepository(mongodb.NewTenantRepository(store))
+		s.amountLimitRepository = repository.NewInstrumentedAmountLimitRepository(mongodb.NewAmountLimitRepository(store))
+		s.standingOrderRepository = repository.NewInstrumentedStandingOrderRepository(mongodb.NewStandingOrderRepository(store))
+		s.refundWindowRepository = repository.NewInstrumentedRefundWindowRepository(mongodb.NewRefundWindowRepository(store))
+	default:
+		// DB_DRIVER seleciona o banco: "postgres" (padrão) ou "mysql"
+		dialect, err := repository.DialectFor(config.DatabaseDriver())
+		if err != nil {
+			return nil, configError("database", "DB_DRIVER", err)
+		}
+
+		// Inicializa a conexão com o banco usando variáveis de ambiente
+		poolConfig := config.PoolConfig()
+		db, pool, err := openDatabase(app, dialect, poolConfig)
+		if err != nil {
+			return nil, err
+		}
+		monitorDB("primary", db, pool, healthChecker, false)
+		s.db, s.dialect = db, dialect
+
+		// Configura a réplica de leitura opcional; sem ela as leituras usam o primário
+		readRouter := database.NewReadRouter(db, nil)
+		if readDSN := config.Get("DB_READ_DSN", ""); readDSN != "" {
+			replicaConfig := poolConfig
+			replicaConfig.DSN = readDSN
+
+			replicaDB, replicaPool, err := database.Open(context.Background(), dialect.Name(), replicaConfig)
+			if err != nil {
+				slog.Error("Error connecting to read replica, falling back to primary", "error", err)
+			} else {
+				if replicaPool != nil {
+					app.onClose(phaseStorage, "replica pool", func() error { replicaPool.Close(); return nil })
+				}
+				app.onClose(phaseStorage, "replica database", replicaDB.Close)
+				monitorDB("replica", replicaDB, replicaPool, healthChecker, true)
+
+				readRouter = database.NewReadRouter(db, replicaDB)
+				interval := config.GetDuration("DB_READ_HEALTHCHECK_INTERVAL", 5*time.Second)
+				app.run(phaseConsumers, "read replica monitor", func(ctx context.Context) { readRouter.Monitor(ctx, interval) })
+			}
+		}
+
+		// Dados pessoais são cifrados na camada de repositório quando há chave de PII configurada (KMS_PROVIDER)
+		encryptor, err := config.PIIEncryptor(context.Background())
+		if err != nil {
+			return nil, unavailableError("pii encryption", "KMS_PROVIDER", err)
+		}
+		if encryptor == nil {
+			slog.Warn("PII encryption key not configured, personal data will be stored in plain text")
+		}
+
+		// Os repositórios repetem automaticamente operações que falham por erros transitórios
+		// e registram latência, erros e linhas de cada tentativa em /metrics e nos traces
+		retryPolicy := config.RetryPolicy()
+
+		sqlAccountRepository, err := repository.NewAccountRepository(context.Background(), db, dialect, encryptor)
+		if err != nil {
+			return nil, unavailableError("database", "DB_HOST", err)
+		}
+		app.onClose(phaseStorage, "account queries", sqlAccountRepository.Close)
+
+		s.accountRepository = repository.NewRetryAccountRepository(
+			repository.NewInstrumentedAccountRepository(sqlAccountRepository),
+			retryPolicy,
+		)
+		s.invoiceRepository = repository.NewRetryInvoiceRepository(
+			repository.NewInstrumentedInvoiceRepository(repository.NewInvoiceRepository(db, readRouter, dialect, encryptor)),
+			retryPolicy,
+		)
+		s.auditRepository = repository.NewInstrumentedAuditRepository(repository.NewAuditRepository(db, dialect))
+		s.userRepository = repository.NewRetryUserRepository(
+			repository.NewInstrumentedUserRepository(repository.NewUserRepository(db, dialect, encryptor)),
+			retryPolicy,
+		)
+		s.authEventRepository = repository.NewInstrumentedAuthEventRepository(repository.NewAuthEventRepository(db, dialect))
+		s.cardRepository = repository.NewInstrumentedCardRepository(repository.NewCardRepository(db, dialect))
+		s.twoFactorRepository = repository.NewInstrumentedTwoFactorRepository(repository.NewTwoFactorRepository(db, dialect, encryptor))
+		s.geoPolicyRepository = repository.NewInstrumentedGeoPolicyRepository(repository.NewGeoPolicyRepository(db, dialect))
+		s.dataSubjectRepository = repository.NewInstrumentedDataSubjectRequestRepository(repository.NewDataSubjectRequestRepository(db, dialect))
+		s.exportRepository = repository.NewInstrumentedExportRepository(repository.NewExportRepository(db, dialect))
+		s.webhookRepository = repository.NewInstrumentedSecurityWebhookRepository(repository.NewSecurityWebhookRepository(db, dialect))
+		s.alertRuleRepository = repository.NewInstrumentedAlertRuleRepository(repository.NewAlertRuleRepository(db, dialect))
+		s.blocklistRepository = repository.NewInstrumentedBlocklistRepository(repository.NewBlocklistRepository(db, dialect))
+		s.riskPolicyRepository = repository.NewInstrumentedRiskPolicyRepository(repository.NewRiskPolicyRepository(db, dialect))
+		s.reviewRepository = repository.NewInstrumentedReviewRepository(repository.NewReviewRepository(db, dialect))
+		s.splitRepository = repository.NewInstrumentedSplitRepository(repository.NewSplitRepository(db, dialect))
+		s.platformRepository = repository.NewInstrumentedPlatformLinkRepository(repository.NewPlatformLinkRepository(db, dialect))
+		s.ledgerRepository = repository.NewInstrumentedLedgerRepository(repository.NewLedgerRepository(db, dialect))
+		s.escrowRepository = repository.NewInstrumentedEscrowRepository(repository.NewEscrowRepository(db, dialect))
+		s.refundPolicyRepository = repository.NewInstrumentedRefundPolicyRepository(repository.NewRefundPolicyRepository(db, dialect))
+		s.refundRepository = repository.NewInstrumentedRefundRepository(repository.NewRefundRepository(db, dialect))
+		s.disputeRepository = repository.NewInstrumentedDisputeRepository(repository.NewDisputeRepository(db, dialect))
+		s.feeScheduleRepository = repository.NewInstrumentedFeeScheduleRepository(repository.NewFeeScheduleRepository(db, dialect))
+		s.subscriptionRepository = repository.NewInstrumentedSubscriptionRepository(repository.NewSubscriptionRepository(db, dialect))
+		s.couponRepository = repository.NewInstrumentedCouponRepository(repository.NewCouponRepository(db, dialect))
+		s.invoiceTaxRepository = repository.NewInstrumentedInvoiceTaxRepository(repository.NewInvoiceTaxRepository(db, dialect))
+		s.invoiceItemRepository = repository.NewInstrumentedInvoiceItemRepository(repository.NewInvoiceItemRepository(db, dialect))
+		s.customerRepository = repository.NewInstrumentedCustomerRepository(repository.NewCustomerRepository(db, dialect, encryptor))
+		s.payoutRepository = repository.NewInstrumentedPayoutScheduleRepository(repository.NewPayoutScheduleRepository(db, dialect))
+		s.anticipationRepository = repository.NewInstrumentedAnticipationRepository(repository.NewAnticipationRepository(db, dialect))
+		s.tenantRepository = repository.NewInstrumentedTenantRepository(repository.NewTenantRepository(db, dialect))
+		s.amountLimitRepository = repository.NewInstrumentedAmountLimitRepository(repository.NewAmountLimitRepository(db, dialect))
+		s.standingOrderRepository = repository.NewInstrumentedStandingOrderRepository(repository.NewStandingOrderRepository(db, dialect))
+		s.refundWindowRepository = repository.NewInstrumentedRefundWindowRepository(repository.NewRefundWindowRepository(db, dialect))
+	}
+
+	// Nas requisições dos administradores de uma organização as contas das demais organizações, e os registros
+	// delas, ficam invisíveis, em qualquer armazenamento
+	s.accountRepository = repository.NewTenantAccountRepository(s.accountRepository)
+	s.invoiceRepository = repository.NewTenantInvoiceRepository(s.invoiceRepository, s.accountRepository)
+	s.feeScheduleRepository = repository.NewTenantFeeScheduleRepository(s.feeScheduleRepository, s.accountRepository)
+	s.platformRepository = repository.NewTenantPlatformLinkRepository(s.platformRepository, s.accountRepository)
+	s.ledgerRepository = repository.NewTenantLedgerRepository(s.ledgerRepository, s.accountRepository)
+	s.disputeRepository = repository.NewTenantDisputeRepository(s.disputeRepository, s.accountRepository)
+	s.refundRepository = repository.NewTenantRefundRepository(s.refundRepository, s.accountRepository, s.invoiceRepository)
+	s.standingOrderRepository = repository.NewTenantStandingOrderRepository(s.standingOrderRepository, s.accountRepository)
+	return s, nil
+}
+
+// monitorDB exporta as estatísticas de conexão em /metrics e inclui o banco na verificação de saúde
+// No PostgreSQL as estatísticas vêm do pool pgx; no MySQL, do próprio *sql.DB
+func monitorDB(name string, db *sql.DB, pool *pgxpool.Pool, healthChecker *database.HealthChecker, optional bool) {
+	if pool != nil {
+		prometheus.MustRegister(metrics.NewDBPoolCollector(pool, name))
+		healthChecker.AddPool(name, pool, optional)
+		return
+	}
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+	healthChecker.AddDB(name, db, optional)
+}
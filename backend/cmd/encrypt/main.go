@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"github.com/joho/godotenv"
+)
+
+// Cifra um segredo da configuração, como SECURITY_WEBHOOK_SECRET, com as chaves do provedor configurado (KMS_PROVIDER)
+// Uso: printf '%s' "$SECRET" | go run cmd/encrypt/main.go -purpose webhook
+func main() {
+	// O .env é opcional aqui para permitir execução em pipelines com variáveis do ambiente
+	_ = godotenv.Load()
+
+	logger, err := config.Logger(os.Stderr, nil)
+	if err != nil {
+		logging.Fatal("Error configuring logger", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if _, err := config.LoadSecrets(context.Background()); err != nil {
+		logging.Fatal("Error loading secrets", "error", err)
+	}
+
+	purpose := flag.String("purpose", "webhook", "chaves usadas na cifragem: webhook")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	var encryptor *pii.Encryptor
+	switch *purpose {
+	case "webhook":
+		encryptor, err = config.WebhookEncryptor(ctx)
+	default:
+		logging.Fatal("Unsupported purpose", "purpose", *purpose)
+	}
+	if err != nil {
+		logging.Fatal("Error configuring encryption", "error", err)
+	}
+	if encryptor == nil {
+		logging.Fatal("No key configured for KMS_PROVIDER", "purpose", strings.ToUpper(*purpose))
+	}
+
+	secret, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		logging.Fatal("Error reading secret", "error", err)
+	}
+	value := strings.TrimRight(string(secret), "\r\n")
+	if value == "" {
+		logging.Fatal("secret must not be empty")
+	}
+
+	encrypted, err := encryptor.Encrypt(ctx, value)
+	if err != nil {
+		logging.Fatal("Error encrypting secret", "error", err)
+	}
+	fmt.Println(encrypted)
+}
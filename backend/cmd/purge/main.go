@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/joho/godotenv"
+)
+
+// Remove definitivamente contas e faturas excluídas logicamente há mais tempo que a retenção
+// Uso: go run cmd/purge/main.go -older-than 2160h -dry-run
+func main() {
+	// O .env é opcional aqui para permitir execução via cron com variáveis do ambiente
+	_ = godotenv.Load()
+
+	logger, err := config.Logger(os.Stderr, nil)
+	if err != nil {
+		logging.Fatal("Error configuring logger", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if _, err := config.LoadSecrets(context.Background()); err != nil {
+		logging.Fatal("Error loading secrets", "error", err)
+	}
+
+	olderThan := flag.Duration("older-than", config.GetDuration("PURGE_DELETED_AFTER", 90*24*time.Hour), "remove registros excluídos há mais tempo que este período")
+	dryRun := flag.Bool("dry-run", false, "apenas conta os registros que seriam removidos")
+	flag.Parse()
+
+	if *olderThan <= 0 {
+		logging.Fatal("older-than must be positive")
+	}
+
+	ctx := context.Background()
+
+	dialect, err := repository.DialectFor(config.DatabaseDriver())
+	if err != nil {
+		logging.Fatal("Error selecting database driver", "error", err)
+	}
+
+	db, pool, err := database.Open(ctx, dialect.Name(), config.MaintenancePoolConfig())
+	if err != nil {
+		logging.Fatal("Error connecting to database", "error", err)
+	}
+	if pool != nil {
+		defer pool.Close()
+	}
+	defer db.Close()
+
+	// O expurgo não lê nem grava dados pessoais, então dispensa o encryptor
+	accountRepository, err := repository.NewAccountRepository(ctx, db, dialect, nil)
+	if err != nil {
+		logging.Fatal("Error preparing account queries", "error", err)
+	}
+	defer accountRepository.Close()
+
+	invoiceRepository := repository.NewInvoiceRepository(db, database.NewReadRouter(db, nil), dialect, nil)
+	purgeService := service.NewPurgeService(invoiceRepository, accountRepository)
+
+	output, err := purgeService.Purge(ctx, time.Now().Add(-*olderThan), *dryRun)
+	if err != nil {
+		logging.Fatal("Error purging deleted records", "error", err)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(output)
+}
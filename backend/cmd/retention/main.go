@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/config"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/joho/godotenv"
+)
+
+// Aplica a política de retenção: arquiva faturas antigas em invoices_archive e remove a auditoria expirada
+// Uso: go run cmd/retention/main.go -invoices-older-than 43800h -audit-logs-older-than 17520h -dry-run
+func main() {
+	// O .env é opcional aqui para permitir execução via cron com variáveis do ambiente
+	_ = godotenv.Load()
+
+	logger, err := config.Logger(os.Stderr, nil)
+	if err != nil {
+		logging.Fatal("Error configuring logger", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if _, err := config.LoadSecrets(context.Background()); err != nil {
+		logging.Fatal("Error loading secrets", "error", err)
+	}
+
+	invoicesOlderThan := flag.Duration("invoices-older-than", config.GetDuration("RETENTION_INVOICES_AFTER", 5*365*24*time.Hour), "arquiva faturas criadas há mais tempo que este período")
+	auditLogsOlderThan := flag.Duration("audit-logs-older-than", config.GetDuration("RETENTION_AUDIT_LOGS_AFTER", 2*365*24*time.Hour), "remove entradas de auditoria mais antigas que este período")
+	dryRun := flag.Bool("dry-run", false, "apenas conta os registros que seriam afetados")
+	flag.Parse()
+
+	if *invoicesOlderThan <= 0 || *auditLogsOlderThan <= 0 {
+		logging.Fatal("retention periods must be positive")
+	}
+
+	ctx := context.Background()
+
+	dialect, err := repository.DialectFor(config.DatabaseDriver())
+	if err != nil {
+		logging.Fatal("Error selecting database driver", "error", err)
+	}
+
+	db, pool, err := database.Open(ctx, dialect.Name(), config.MaintenancePoolConfig())
+	if err != nil {
+		logging.Fatal("Error connecting to database", "error", err)
+	}
+	if pool != nil {
+		defer pool.Close()
+	}
+	defer db.Close()
+
+	// O arquivamento move as linhas sem decifrá-las, então dispensa o encryptor
+	invoiceRepository := repository.NewInvoiceRepository(db, database.NewReadRouter(db, nil), dialect, nil)
+	auditRepository := repository.NewAuditRepository(db, dialect)
+	retentionService := service.NewRetentionService(invoiceRepository, auditRepository)
+
+	now := time.Now()
+	output, err := retentionService.Apply(ctx, now.Add(-*invoicesOlderThan), now.Add(-*auditLogsOlderThan), *dryRun)
+	if err != nil {
+		logging.Fatal("Error applying retention policy", "error", err)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(output)
+}
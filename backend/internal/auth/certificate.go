@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Tipos de identidade atribuídos a certificados de cliente
+const (
+	// IdentityAccount autentica o chamador como a conta, nas rotas dos lojistas
+	IdentityAccount = "account"
+	// IdentityService autentica um serviço interno, nas rotas administrativas
+	IdentityService = "service"
+)
+
+// CertificateIdentity é a identidade associada a um certificado de cliente
+type CertificateIdentity struct {
+	Kind string
+	ID   string
+}
+
+// CertificateMapper associa o CN ou um SAN dos certificados de cliente verificados a uma identidade do gateway
+type CertificateMapper struct {
+	identities map[string]CertificateIdentity
+}
+
+// ParseCertificateMapping lê o mapeamento no formato nome=account:<id> ou nome=service:<nome>, separado por vírgulas,
+// em que nome é o CN ou um SAN (DNS, e-mail ou URI, como IDs SPIFFE) do certificado
+func ParseCertificateMapping(value string) (*CertificateMapper, error) {
+	mapper := &CertificateMapper{identities: make(map[string]CertificateIdentity)}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		// O último "=" separa o nome, já que URIs podem conter "="
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid certificate mapping %q, expected name=kind:id", pair)
+		}
+		kind, id, ok := strings.Cut(pair[i+1:], ":")
+		if !ok || id == "" || (kind != IdentityAccount && kind != IdentityService) {
+			return nil, fmt.Errorf("invalid certificate mapping %q, expected account:<id> or service:<name>", pair)
+		}
+		mapper.identities[pair[:i]] = CertificateIdentity{Kind: kind, ID: id}
+	}
+	return mapper, nil
+}
+
+// Identify procura a identidade do certificado pelos SANs de URI, DNS e e-mail e, por último, pelo CN
+func (m *CertificateMapper) Identify(cert *x509.Certificate) (CertificateIdentity, bool) {
+	if m == nil {
+		return CertificateIdentity{}, false
+	}
+
+	names := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+1)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	names = append(names, cert.Subject.CommonName)
+
+	for _, name := range names {
+		if identity, ok := m.identities[name]; ok {
+			return identity, true
+		}
+	}
+	return CertificateIdentity{}, false
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+// rolePrecedence ordena os papéis do mais para o menos privilegiado, para quem pertence a vários grupos mapeados
+var rolePrecedence = []domain.Role{domain.RoleAdmin, domain.RoleMerchant, domain.RoleReadOnly}
+
+// OIDCConfig configura o login por um provedor de identidade externo (Keycloak, Auth0 etc.)
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// GroupsClaim é a claim do ID token com os grupos; aceita caminho com pontos (ex: realm_access.roles)
+	GroupsClaim string
+	// RoleMapping associa grupos do provedor aos papéis do gateway
+	RoleMapping map[string]domain.Role
+	// AdminGroups são os grupos que podem usar a API administrativa com o ID token
+	AdminGroups []string
+}
+
+// Identity é o usuário autenticado pelo provedor
+type Identity struct {
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+// OIDCProvider conduz o fluxo authorization code e valida os ID tokens emitidos pelo provedor
+type OIDCProvider struct {
+	oauth2      oauth2.Config
+	verifier    *oidc.IDTokenVerifier
+	groupsClaim string
+	roleMapping map[string]domain.Role
+	adminGroups []string
+}
+
+// NewOIDCProvider descobre os endpoints e as chaves do provedor em IssuerURL/.well-known/openid-configuration
+func NewOIDCProvider(ctx context.Context, cfg OIDCConfig) (*OIDCProvider, error) {
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCProvider{
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier:    provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		groupsClaim: cfg.GroupsClaim,
+		roleMapping: cfg.RoleMapping,
+		adminGroups: cfg.AdminGroups,
+	}, nil
+}
+
+// AuthCodeURL retorna o endereço de login do provedor; state e nonce voltam no callback e no ID token
+func (p *OIDCProvider) AuthCodeURL(state, nonce string) string {
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce))
+}
+
+// Exchange troca o código do callback pelo ID token e valida assinatura, audiência, expiração e nonce
+// Retorna ErrInvalidCredentials se o provedor recusar o código ou o token não conferir
+func (p *OIDCProvider) Exchange(ctx context.Context, code, nonce string) (*Identity, error) {
+	token, err := p.oauth2.Exchange(ctx, code)
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil || idToken.Nonce != nonce {
+		return nil, domain.ErrInvalidCredentials
+	}
+	return p.identity(idToken)
+}
+
+// Verify valida um ID token apresentado diretamente, como nas chamadas à API administrativa
+// Retorna ErrInvalidToken se o token não for válido
+func (p *OIDCProvider) Verify(ctx context.Context, rawIDToken string) (*Identity, error) {
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	return p.identity(idToken)
+}
+
+// identity extrai e-mail e grupos do ID token
+// E-mails marcados pelo provedor como não verificados são recusados
+func (p *OIDCProvider) identity(idToken *oidc.IDToken) (*Identity, error) {
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	email, _ := claims["email"].(string)
+	if verified, ok := claims["email_verified"].(bool); email == "" || (ok && !verified) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return &Identity{
+		Subject: idToken.Subject,
+		Email:   email,
+		Groups:  lookupGroups(claims, p.groupsClaim),
+	}, nil
+}
+
+// Role retorna o papel mais privilegiado entre os grupos mapeados da identidade
+// O segundo retorno é false quando nenhum grupo está mapeado
+func (p *OIDCProvider) Role(identity *Identity) (domain.Role, bool) {
+	for _, role := range rolePrecedence {
+		for _, group := range identity.Groups {
+			if p.roleMapping[group] == role {
+				return role, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsAdmin indica se a identidade pertence a um dos grupos da API administrativa
+func (p *OIDCProvider) IsAdmin(identity *Identity) bool {
+	for _, group := range identity.Groups {
+		if slices.Contains(p.adminGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminEnabled indica se algum grupo pode usar a API administrativa
+func (p *OIDCProvider) AdminEnabled() bool {
+	return p != nil && len(p.adminGroups) > 0
+}
+
+// lookupGroups percorre a claim de grupos, que pode estar aninhada, e devolve os grupos em texto
+func lookupGroups(claims map[string]any, path string) []string {
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	list, _ := value.([]any)
+	groups := make([]string, 0, len(list))
+	for _, item := range list {
+		if group, ok := item.(string); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ParseRoleMapping lê o mapeamento no formato grupo=papel separado por vírgulas
+func ParseRoleMapping(value string) (map[string]domain.Role, error) {
+	mapping := make(map[string]domain.Role)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		group, roleName, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid role mapping %q, expected group=role", pair)
+		}
+		role, err := domain.ParseRole(strings.TrimSpace(roleName))
+		if err != nil {
+			return nil, fmt.Errorf("invalid role mapping %q: %w", pair, err)
+		}
+		mapping[strings.TrimSpace(group)] = role
+	}
+	return mapping, nil
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// issuer identifica os tokens emitidos por este gateway
+const issuer = "go-gateway"
+
+// ErrWeakSecret é retornado quando o segredo de assinatura dos JWTs é curto demais
+var ErrWeakSecret = errors.New("jwt secret must have at least 32 bytes")
+
+// MinSecretLength é o tamanho mínimo do segredo HS256
+const MinSecretLength = 32
+
+// Claims são as informações carregadas no JWT de acesso dos usuários do dashboard
+type Claims struct {
+	AccountID string `json:"account_id"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager emite e valida os JWTs de acesso, assinados com HS256
+type TokenManager struct {
+	secret    []byte
+	accessTTL time.Duration
+}
+
+// NewTokenManager cria o gerenciador de tokens com o segredo e a validade dos tokens de acesso
+func NewTokenManager(secret string, accessTTL time.Duration) (*TokenManager, error) {
+	if len(secret) < MinSecretLength {
+		return nil, ErrWeakSecret
+	}
+	return &TokenManager{secret: []byte(secret), accessTTL: accessTTL}, nil
+}
+
+// Issue emite um JWT de acesso para o usuário e retorna também o instante em que expira
+func (m *TokenManager) Issue(user *domain.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.accessTTL)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		AccountID: user.AccountID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        domain.NewID(),
+		},
+	})
+
+	signed, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Parse valida assinatura, emissor e expiração do JWT
+// Apenas HS256 é aceito, evitando a troca de algoritmo pelo cliente
+// Retorna ErrInvalidToken para qualquer token que não passe na validação
+func (m *TokenManager) Parse(tokenString string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (any, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" || claims.AccountID == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &claims, nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret é o segredo ASCII "12345678901234567890" dos vetores de teste da RFC 6238, em base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Os vetores SHA-1 da RFC 6238, com os seis últimos dígitos
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/int64(TOTPPeriod.Seconds())); got != tt.want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / int64(TOTPPeriod.Seconds())
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current period", rfcSecret, totpCode(key, current), 0, current, true},
+		{"lowercase secret", strings.ToLower(rfcSecret), totpCode(key, current), 0, current, true},
+		{"previous period", rfcSecret, totpCode(key, current-1), 0, current - 1, true},
+		{"next period", rfcSecret, totpCode(key, current+1), 0, current + 1, true},
+		{"two periods behind", rfcSecret, totpCode(key, current-2), 0, 0, false},
+		{"two periods ahead", rfcSecret, totpCode(key, current+2), 0, 0, false},
+		{"already used", rfcSecret, totpCode(key, current), current, 0, false},
+		{"older than the last used", rfcSecret, totpCode(key, current-1), current - 1, 0, false},
+		{"newer than the last used", rfcSecret, totpCode(key, current+1), current, current + 1, true},
+		{"wrong code", rfcSecret, "000000", 0, 0, false},
+		{"short code", rfcSecret, totpCode(key, current)[:5], 0, 0, false},
+		{"invalid secret", "not base32!", totpCode(key, current), 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := VerifyTOTP(tt.secret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("VerifyTOTP = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("secret has %d bytes, want 20", len(key))
+	}
+}
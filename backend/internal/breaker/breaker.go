@@ -0,0 +1,180 @@
+// Package breaker protege as chamadas a dependências externas com circuit breakers: depois de falhas seguidas
+// as chamadas são recusadas na hora, sem esperar a dependência, até que sondas mostrem que ela voltou
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// State é o estado do circuito
+type State int
+
+const (
+	// StateClosed deixa passar todas as chamadas
+	StateClosed State = iota
+	// StateHalfOpen deixa passar apenas as sondas que decidem se o circuito fecha ou volta a abrir
+	StateHalfOpen
+	// StateOpen recusa todas as chamadas até o fim de OpenTimeout
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+// Config define quando o circuito abre e como ele volta a fechar
+type Config struct {
+	// FailureThreshold é o número de falhas seguidas que abre o circuito
+	FailureThreshold int
+	// OpenTimeout é quanto tempo o circuito fica aberto antes de liberar as sondas
+	OpenTimeout time.Duration
+	// HalfOpenProbes é o número de chamadas liberadas com o circuito meio aberto; se todas derem certo ele fecha
+	HalfOpenProbes int
+	// Timeout limita cada chamada, e estourar o prazo conta como falha; zero não limita
+	Timeout time.Duration
+}
+
+// Breaker é o circuit breaker de uma dependência; é seguro para uso concorrente
+// Um Breaker nil deixa passar todas as chamadas
+type Breaker struct {
+	name   string
+	config Config
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	// probes são as sondas liberadas e successes as que deram certo no estado meio aberto atual
+	probes    int
+	successes int
+	// generation muda a cada troca de estado, para descartar o resultado de chamadas liberadas no estado anterior
+	generation uint64
+}
+
+// New cria o circuit breaker fechado; name identifica a dependência no label "name" das métricas
+func New(name string, config Config) *Breaker {
+	metrics.CircuitBreakerState.WithLabelValues(name).Set(float64(StateClosed))
+	return &Breaker{name: name, config: config}
+}
+
+// State retorna o estado atual do circuito
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Execute chama a dependência se o circuito permitir, com o prazo de Config.Timeout
+// Retorna ErrDependencyUnavailable, sem chamar a dependência, com o circuito aberto
+// Erros causados pelo cancelamento do contexto de quem chamou não contam como falha da dependência
+func (b *Breaker) Execute(ctx context.Context, call func(ctx context.Context) error) error {
+	if b == nil {
+		return call(ctx)
+	}
+
+	generation, ok := b.allow()
+	if !ok {
+		metrics.CircuitBreakerRejectedTotal.WithLabelValues(b.name).Inc()
+		return domain.ErrDependencyUnavailable
+	}
+
+	callCtx := ctx
+	if b.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, b.config.Timeout)
+		defer cancel()
+	}
+	err := call(callCtx)
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		b.release(generation)
+		return err
+	}
+	b.record(ctx, generation, err == nil)
+	return err
+}
+
+// allow decide se a chamada passa, movendo o circuito aberto para meio aberto ao fim de OpenTimeout
+func (b *Breaker) allow() (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		b.transition(context.Background(), StateHalfOpen)
+	}
+	switch b.state {
+	case StateOpen:
+		return 0, false
+	case StateHalfOpen:
+		if b.probes >= b.config.HalfOpenProbes {
+			return 0, false
+		}
+		b.probes++
+	}
+	return b.generation, true
+}
+
+// release devolve a sonda de uma chamada cancelada por quem chamou, sem decidir nada sobre a dependência
+func (b *Breaker) release(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation && b.state == StateHalfOpen {
+		b.probes--
+	}
+}
+
+// record contabiliza o resultado de uma chamada liberada
+func (b *Breaker) record(ctx context.Context, generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	switch {
+	case b.state == StateHalfOpen && !success:
+		b.transition(ctx, StateOpen)
+	case b.state == StateHalfOpen:
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.transition(ctx, StateClosed)
+		}
+	case success:
+		b.failures = 0
+	default:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.transition(ctx, StateOpen)
+		}
+	}
+}
+
+// transition troca o estado e zera as contagens; deve ser chamada com mu travado
+func (b *Breaker) transition(ctx context.Context, state State) {
+	b.state = state
+	b.failures, b.probes, b.successes = 0, 0, 0
+	b.generation++
+	if state == StateOpen {
+		b.openedAt = time.Now()
+	}
+
+	metrics.CircuitBreakerState.WithLabelValues(b.name).Set(float64(state))
+	metrics.CircuitBreakerTransitionsTotal.WithLabelValues(b.name, state.String()).Inc()
+	if state == StateOpen {
+		slog.WarnContext(ctx, "circuit breaker aberto, chamadas à dependência recusadas", "breaker", b.name, "open_timeout", b.config.OpenTimeout)
+		return
+	}
+	slog.InfoContext(ctx, "circuit breaker mudou de estado", "breaker", b.name, "state", state.String())
+}
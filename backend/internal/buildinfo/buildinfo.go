@@ -0,0 +1,60 @@
+// Package buildinfo identifica o binário em execução: versão, commit, data do build e horário de subida
+package buildinfo
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// Version, Commit e BuildDate são gravados no build com
+// -ldflags "-X github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo.Version=1.2.0
+// -X github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo.Commit=$(git rev-parse HEAD)
+// -X github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// StartedAt é o horário em que o processo subiu
+var StartedAt = time.Now()
+
+// Revision retorna Commit ou, sem ele, a revisão do git gravada pelo go build
+// O sufixo "-dirty" indica um build com alterações não commitadas; vazio quando nenhuma das duas existe
+func Revision() string {
+	if Commit != "" {
+		return Commit
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// Release identifica o build no rastreador de erros: a versão seguida do commit, como "1.2.0+3f9c2d1e8b7a"
+// Sem commit conhecido é só a versão
+func Release() string {
+	if revision := Revision(); revision != "" {
+		return Version + "+" + revision
+	}
+	return Version
+}
+
+// Uptime retorna há quanto tempo o processo está no ar
+func Uptime() time.Duration {
+	return time.Since(StartedAt)
+}
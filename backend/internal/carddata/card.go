@@ -0,0 +1,187 @@
+// Package carddata isola o tratamento dos dados de cartão: validação, tokenização, cifragem e exibição mascarada
+// O número completo só existe dentro deste pacote; o restante do gateway trabalha com o token e os dados mascarados
+package carddata
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// Bandeiras reconhecidas pelo prefixo do número
+const (
+	BrandVisa       = "visa"
+	BrandMastercard = "mastercard"
+	BrandAmex       = "amex"
+	BrandDiscover   = "discover"
+	BrandDiners     = "diners"
+	BrandJCB        = "jcb"
+	BrandHipercard  = "hipercard"
+	BrandUnknown    = "unknown"
+)
+
+// Card é um cartão validado recebido em uma requisição
+// O CVV é apenas validado e descartado; String, LogValue e MarshalJSON exibem o número mascarado,
+// de modo que o cartão nunca aparece completo em logs ou respostas
+type Card struct {
+	number      string
+	expiryMonth int
+	expiryYear  int
+	holderName  string
+}
+
+// NewCard valida os dados do cartão; espaços e hífens do número são ignorados
+// Retorna ErrInvalidCardNumber, ErrInvalidCardCVV, ErrInvalidCardExpiry ou ErrInvalidCardholderName
+func NewCard(number, cvv string, expiryMonth, expiryYear int, holderName string) (*Card, error) {
+	number = strings.NewReplacer(" ", "", "-", "").Replace(number)
+	if len(number) < 12 || len(number) > 19 || !isDigits(number) || !luhn(number) {
+		return nil, domain.ErrInvalidCardNumber
+	}
+	if len(cvv) < 3 || len(cvv) > 4 || !isDigits(cvv) {
+		return nil, domain.ErrInvalidCardCVV
+	}
+	if expiryMonth < 1 || expiryMonth > 12 || expiryYear < 2000 || expiryYear > 9999 {
+		return nil, domain.ErrInvalidCardExpiry
+	}
+
+	holderName = strings.TrimSpace(holderName)
+	if holderName == "" {
+		return nil, domain.ErrInvalidCardholderName
+	}
+
+	return &Card{
+		number:      number,
+		expiryMonth: expiryMonth,
+		expiryYear:  expiryYear,
+		holderName:  holderName,
+	}, nil
+}
+
+// Brand retorna a bandeira do cartão
+func (c *Card) Brand() string {
+	return brandOf(c.number)
+}
+
+// LastDigits retorna os quatro últimos dígitos do número
+func (c *Card) LastDigits() string {
+	return c.number[len(c.number)-4:]
+}
+
+// Number retorna o número completo; deve ser repassado apenas ao adquirente e às regras de frequência, que só
+// guardam o HMAC, nunca gravado ou registrado
+func (c *Card) Number() string {
+	return c.number
+}
+
+// HolderName retorna o nome impresso no cartão
+func (c *Card) HolderName() string {
+	return c.holderName
+}
+
+// PaymentCard retorna a referência do cartão usada nas faturas, ainda sem o token
+func (c *Card) PaymentCard() domain.PaymentCard {
+	return domain.PaymentCard{
+		Brand:      c.Brand(),
+		LastDigits: c.LastDigits(),
+		HolderName: c.holderName,
+	}
+}
+
+// String exibe o cartão mascarado
+func (c *Card) String() string {
+	return Mask(c.number)
+}
+
+// GoString impede que %#v exponha o número
+func (c *Card) GoString() string {
+	return c.String()
+}
+
+// LogValue faz o slog registrar apenas o número mascarado e a bandeira
+func (c *Card) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("number", Mask(c.number)),
+		slog.String("brand", c.Brand()),
+	)
+}
+
+// MarshalJSON serializa o cartão mascarado
+func (c *Card) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + c.String() + `"`), nil
+}
+
+// Mask mantém os seis primeiros e os quatro últimos dígitos, o máximo que o PCI DSS permite exibir
+// Números curtos demais são mascarados por inteiro
+func Mask(number string) string {
+	if len(number) < 13 {
+		return strings.Repeat("*", len(number))
+	}
+	return number[:6] + strings.Repeat("*", len(number)-10) + number[len(number)-4:]
+}
+
+// brandOf identifica a bandeira pelos prefixos (BIN) de cada emissor
+func brandOf(number string) string {
+	switch {
+	case hasPrefixIn(number, "606282", "3841"):
+		return BrandHipercard
+	case hasPrefixIn(number, "4"):
+		return BrandVisa
+	case hasPrefixIn(number, "34", "37"):
+		return BrandAmex
+	case hasPrefixIn(number, "51", "52", "53", "54", "55") || inRange(number, 4, 2221, 2720):
+		return BrandMastercard
+	case hasPrefixIn(number, "6011", "65") || inRange(number, 3, 644, 649):
+		return BrandDiscover
+	case hasPrefixIn(number, "36", "38", "39") || inRange(number, 3, 300, 305):
+		return BrandDiners
+	case inRange(number, 4, 3528, 3589):
+		return BrandJCB
+	}
+	return BrandUnknown
+}
+
+func hasPrefixIn(number string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(number, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange indica se os primeiros digits dígitos do número estão entre low e high
+func inRange(number string, digits, low, high int) bool {
+	prefix := 0
+	for _, d := range number[:digits] {
+		prefix = prefix*10 + int(d-'0')
+	}
+	return prefix >= low && prefix <= high
+}
+
+func isDigits(value string) bool {
+	for _, d := range value {
+		if d < '0' || d > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// luhn confere o dígito verificador do número
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
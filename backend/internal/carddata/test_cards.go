@@ -0,0 +1,35 @@
+package carddata
+
+import "github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+
+// Cartões de teste, aceitos apenas pelas contas em modo de teste, em que o adquirente simulado recusa as faturas
+// sempre com o mesmo motivo; os números passam pelo Luhn como qualquer cartão
+const (
+	// TestCardInsufficientFunds é recusado por saldo insuficiente
+	TestCardInsufficientFunds = "4000000000009995"
+	// TestCardFraudulent é recusado como fraude pelo emissor
+	TestCardFraudulent = "4100000000000019"
+	// TestCardAuthenticationRequired é recusado pedindo a autenticação 3DS, que o gateway ainda não faz
+	TestCardAuthenticationRequired = "4000000000003220"
+)
+
+// TestCards relaciona cada cartão de teste ao motivo da recusa no domain.SandboxProcessor
+var TestCards = map[string]domain.DeclineReason{
+	TestCardInsufficientFunds:      domain.DeclineInsufficientFunds,
+	TestCardFraudulent:             domain.DeclineFraudulent,
+	TestCardAuthenticationRequired: domain.DeclineAuthenticationRequired,
+}
+
+// TestDecline retorna o motivo fixo da recusa do cartão de teste e vazio para os demais cartões
+func (c *Card) TestDecline() domain.DeclineReason {
+	return TestCards[c.number]
+}
+
+// CheckMode confere se o cartão pode ser usado pela conta; os cartões de teste só valem no modo de teste
+// Retorna ErrTestCardNotAllowed para um cartão de teste fora do modo de teste
+func (c *Card) CheckMode(testMode bool) error {
+	if !testMode && c.TestDecline() != "" {
+		return domain.ErrTestCardNotAllowed
+	}
+	return nil
+}
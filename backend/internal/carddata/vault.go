@@ -0,0 +1,205 @@
+package carddata
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+// tokenPrefix identifica os tokens de cartão emitidos pelo cofre
+const tokenPrefix = "tok_"
+
+// Record é o cartão guardado no cofre
+// Number e HolderName são gravados cifrados; Number fica vazio quando o cofre não tem chave própria
+type Record struct {
+	Token       string
+	AccountID   string
+	Brand       string
+	LastDigits  string
+	ExpiryMonth int
+	ExpiryYear  int
+	HolderName  string
+	Number      string
+	CreatedAt   time.Time
+}
+
+// Repository é o armazenamento próprio do cofre, separado das faturas
+type Repository interface {
+	Save(ctx context.Context, record *Record) error
+	SaveBatch(ctx context.Context, records []*Record) error
+	// FindByToken retorna ErrCardNotFound quando o token não existe
+	FindByToken(ctx context.Context, token string) (*Record, error)
+	// Anonymize apaga número e final dos cartões da conta e troca o nome do portador por holderName
+	Anonymize(ctx context.Context, accountID string, tokens []string, holderName string) error
+}
+
+// Vault tokeniza os cartões e guarda os números cifrados com uma chave exclusiva dos dados de cartão
+type Vault struct {
+	repository Repository
+	encryptor  *pii.Encryptor
+}
+
+// NewVault cria o cofre sobre o armazenamento informado
+// Com encryptor nil o número completo não é guardado e apenas bandeira, final e validade ficam no cofre
+func NewVault(repository Repository, encryptor *pii.Encryptor) *Vault {
+	return &Vault{repository: repository, encryptor: encryptor}
+}
+
+// RetainsNumbers indica se o cofre guarda o número completo, necessário para cobrar de novo um cartão pelo token
+func (v *Vault) RetainsNumbers() bool {
+	return v.encryptor != nil
+}
+
+// Tokenize guarda o cartão da conta e retorna o token que o representa
+// testMode informa se a conta está em modo de teste; retorna ErrTestCardNotAllowed para os cartões de teste fora dele
+func (v *Vault) Tokenize(ctx context.Context, accountID string, card *Card, testMode bool) (string, error) {
+	record, err := v.newRecord(ctx, accountID, card, testMode)
+	if err != nil {
+		return "", err
+	}
+
+	if err := v.repository.Save(ctx, record); err != nil {
+		return "", err
+	}
+	return record.Token, nil
+}
+
+// TokenizeBatch guarda os cartões da conta de uma vez e retorna os tokens na mesma ordem
+// Nenhum cartão é guardado se algum for de teste fora do modo de teste
+func (v *Vault) TokenizeBatch(ctx context.Context, accountID string, cards []*Card, testMode bool) ([]string, error) {
+	records := make([]*Record, len(cards))
+	tokens := make([]string, len(cards))
+	for i, card := range cards {
+		record, err := v.newRecord(ctx, accountID, card, testMode)
+		if err != nil {
+			return nil, err
+		}
+		records[i], tokens[i] = record, record.Token
+	}
+
+	if err := v.repository.SaveBatch(ctx, records); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Detokenize recupera o cartão de um token da conta, para a chamada ao adquirente
+// Retorna ErrCardNotFound se o token não existir, pertencer a outra conta ou não tiver o número guardado
+func (v *Vault) Detokenize(ctx context.Context, accountID, token string) (*Card, error) {
+	record, err := v.repository.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if record.AccountID != accountID || record.Number == "" {
+		return nil, domain.ErrCardNotFound
+	}
+
+	number, err := v.encryptor.Decrypt(ctx, record.Number)
+	if err != nil {
+		return nil, err
+	}
+	holderName, err := v.encryptor.Decrypt(ctx, record.HolderName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Card{
+		number:      number,
+		expiryMonth: record.ExpiryMonth,
+		expiryYear:  record.ExpiryYear,
+		holderName:  holderName,
+	}, nil
+}
+
+// Details são os dados de um cartão do cofre que podem ser mostrados ao titular, sem o número
+type Details struct {
+	Token       string
+	Brand       string
+	LastDigits  string
+	ExpiryMonth int
+	ExpiryYear  int
+	HolderName  string
+}
+
+// Describe retorna os dados do cartão de um token da conta, com o nome do portador decifrado
+// Retorna ErrCardNotFound se o token não existir ou pertencer a outra conta
+func (v *Vault) Describe(ctx context.Context, accountID, token string) (*Details, error) {
+	record, err := v.repository.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if record.AccountID != accountID {
+		return nil, domain.ErrCardNotFound
+	}
+
+	holderName, err := v.encryptor.Decrypt(ctx, record.HolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &Details{
+		Token:       record.Token,
+		Brand:       record.Brand,
+		LastDigits:  record.LastDigits,
+		ExpiryMonth: record.ExpiryMonth,
+		ExpiryYear:  record.ExpiryYear,
+		HolderName:  holderName,
+	}, nil
+}
+
+// Forget apaga número e final dos cartões da conta e troca o nome do portador pelo token de anonimização
+// Os tokens continuam existindo, mas deixam de poder ser usados em cobranças
+func (v *Vault) Forget(ctx context.Context, accountID string, tokens []string, anonymizationToken string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return v.repository.Anonymize(ctx, accountID, tokens, anonymizationToken)
+}
+
+// newRecord monta o registro cifrado do cartão com um token novo
+func (v *Vault) newRecord(ctx context.Context, accountID string, card *Card, testMode bool) (*Record, error) {
+	if err := card.CheckMode(testMode); err != nil {
+		return nil, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	holderName, err := v.encryptor.Encrypt(ctx, card.holderName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem chave própria o número seria gravado em texto puro, então não é guardado
+	var number string
+	if v.encryptor != nil {
+		if number, err = v.encryptor.Encrypt(ctx, card.number); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Record{
+		Token:       token,
+		AccountID:   accountID,
+		Brand:       card.Brand(),
+		LastDigits:  card.LastDigits(),
+		ExpiryMonth: card.expiryMonth,
+		ExpiryYear:  card.expiryYear,
+		HolderName:  holderName,
+		Number:      number,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// newToken gera um token aleatório, sem relação com o número do cartão
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
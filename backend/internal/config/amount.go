@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AmountLimits lê as faixas de valor por fatura: AMOUNT_MIN e AMOUNT_MAX valem para todas as faturas das contas sem
+// faixa própria e AMOUNT_LIMITS_BY_PAYMENT_TYPE estreita a faixa de cada meio de pagamento, no formato
+// "MEIO:MIN:MAX", como "credit_card:1:5000,boleto:10:0"; zero desativa o limite
+func AmountLimits() (domain.AmountLimits, error) {
+	limits := domain.AmountLimits{
+		Default: domain.AmountRange{
+			Min: GetFloat("AMOUNT_MIN", 0),
+			Max: GetFloat("AMOUNT_MAX", 0),
+		},
+	}
+	if err := limits.Default.Validate(); err != nil {
+		return limits, fmt.Errorf("AMOUNT_MIN and AMOUNT_MAX must not be negative and AMOUNT_MAX must not be lower than AMOUNT_MIN")
+	}
+
+	paymentTypes, err := amountLimitsByPaymentType(Get("AMOUNT_LIMITS_BY_PAYMENT_TYPE", ""))
+	if err != nil {
+		return limits, err
+	}
+	limits.PaymentTypes = paymentTypes
+	return limits, nil
+}
+
+// amountLimitsByPaymentType lê as faixas no formato "MEIO:MIN:MAX", separadas por vírgula
+func amountLimitsByPaymentType(value string) (map[string]domain.AmountRange, error) {
+	limits := make(map[string]domain.AmountRange)
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("AMOUNT_LIMITS_BY_PAYMENT_TYPE must be PAYMENT_TYPE:MIN:MAX entries, got %q", entry)
+		}
+		paymentType := strings.TrimSpace(parts[0])
+		minAmount, minErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		maxAmount, maxErr := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		_, duplicated := limits[paymentType]
+		limit := domain.AmountRange{Min: minAmount, Max: maxAmount}
+		if paymentType == "" || duplicated || minErr != nil || maxErr != nil || limit.Validate() != nil {
+			return nil, fmt.Errorf("AMOUNT_LIMITS_BY_PAYMENT_TYPE must have unique payment types with non-negative limits and MAX not lower than MIN, got %q", entry)
+		}
+		limits[paymentType] = limit
+	}
+	return limits, nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+)
+
+// TokenManager cria o emissor de JWTs dos usuários do dashboard a partir de JWT_SECRET e JWT_ACCESS_TOKEN_TTL
+// Retorna nil, com o login de usuários desabilitado, quando JWT_SECRET não está definida
+func TokenManager() (*auth.TokenManager, error) {
+	secret := Get("JWT_SECRET", "")
+	if secret == "" {
+		return nil, nil
+	}
+
+	tokens, err := auth.NewTokenManager(secret, GetDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_SECRET: %w", err)
+	}
+	return tokens, nil
+}
+
+// RefreshTokenTTL retorna a validade dos refresh tokens definida em JWT_REFRESH_TOKEN_TTL (padrão 30 dias)
+func RefreshTokenTTL() time.Duration {
+	return GetDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+// OIDCProvider configura o login por provedor de identidade externo a partir das variáveis OIDC_*
+// Retorna nil, com o login por SSO desabilitado, quando OIDC_ISSUER_URL não está definida
+func OIDCProvider(ctx context.Context) (*auth.OIDCProvider, error) {
+	issuerURL := Get("OIDC_ISSUER_URL", "")
+	if issuerURL == "" {
+		return nil, nil
+	}
+
+	roleMapping, err := auth.ParseRoleMapping(Get("OIDC_ROLE_MAPPING", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_ROLE_MAPPING: %w", err)
+	}
+
+	var adminGroups []string
+	for _, group := range strings.Split(Get("OIDC_ADMIN_GROUPS", ""), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			adminGroups = append(adminGroups, group)
+		}
+	}
+
+	return auth.NewOIDCProvider(ctx, auth.OIDCConfig{
+		IssuerURL:    issuerURL,
+		ClientID:     Get("OIDC_CLIENT_ID", ""),
+		ClientSecret: Get("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  Get("OIDC_REDIRECT_URL", "http://localhost:8080/auth/oidc/callback"),
+		GroupsClaim:  Get("OIDC_GROUPS_CLAIM", "groups"),
+		RoleMapping:  roleMapping,
+		AdminGroups:  adminGroups,
+	})
+}
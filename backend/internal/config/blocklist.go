@@ -0,0 +1,17 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// BlocklistHashKey lê BLOCKLIST_HASH_KEY (base64), a chave do HMAC que guarda os valores das listas de bloqueio
+// A chave precisa ser a mesma em todas as réplicas e não pode mudar: as entradas gravadas com outra chave deixam de
+// bater; vazia, os valores vão pelo HMAC sem chave
+func BlocklistHashKey() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(Get("BLOCKLIST_HASH_KEY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLOCKLIST_HASH_KEY: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/breaker"
+)
+
+// CircuitBreaker cria o circuit breaker da dependência name com o limite de cada chamada em timeout
+// O circuito abre após CIRCUIT_BREAKER_FAILURES (padrão 5) falhas seguidas, fica aberto por
+// CIRCUIT_BREAKER_OPEN_TIMEOUT (padrão 30s) e fecha quando as CIRCUIT_BREAKER_HALF_OPEN_PROBES (padrão 1)
+// sondas seguintes dão certo
+func CircuitBreaker(name string, timeout time.Duration) (*breaker.Breaker, error) {
+	config := breaker.Config{
+		FailureThreshold: GetInt("CIRCUIT_BREAKER_FAILURES", 5),
+		OpenTimeout:      GetDuration("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		HalfOpenProbes:   GetInt("CIRCUIT_BREAKER_HALF_OPEN_PROBES", 1),
+		Timeout:          timeout,
+	}
+	if config.FailureThreshold < 1 {
+		return nil, fmt.Errorf("CIRCUIT_BREAKER_FAILURES must be at least 1")
+	}
+	if config.OpenTimeout <= 0 {
+		return nil, fmt.Errorf("CIRCUIT_BREAKER_OPEN_TIMEOUT must be positive")
+	}
+	if config.HalfOpenProbes < 1 {
+		return nil, fmt.Errorf("CIRCUIT_BREAKER_HALF_OPEN_PROBES must be at least 1")
+	}
+	if config.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout for circuit breaker %q", name)
+	}
+	return breaker.New(name, config), nil
+}
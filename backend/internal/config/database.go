@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+)
+
+// DatabaseDriver retorna o banco selecionado em DB_DRIVER: "postgres" (padrão) ou "mysql"
+func DatabaseDriver() string {
+	return Get("DB_DRIVER", "postgres")
+}
+
+// PostgresDSN monta a string de conexão com o PostgreSQL a partir das variáveis DB_*
+func PostgresDSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		Get("DB_HOST", "db"),
+		Get("DB_PORT", "5432"),
+		Get("DB_USER", "postgres"),
+		Get("DB_PASSWORD", "postgres"),
+		Get("DB_NAME", "gateway"),
+		Get("DB_SSL_MODE", "disable"),
+	)
+}
+
+// MySQLDSN monta a string de conexão com o MySQL/MariaDB a partir das variáveis DB_*
+func MySQLDSN() string {
+	return mysqlConfig().FormatDSN()
+}
+
+// mysqlConfig monta a configuração da conexão com o MySQL/MariaDB a partir das variáveis DB_*
+func mysqlConfig() *mysql.Config {
+	cfg := mysql.NewConfig()
+	cfg.User = Get("DB_USER", "root")
+	cfg.Passwd = Get("DB_PASSWORD", "root")
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(Get("DB_HOST", "db"), Get("DB_PORT", "3306"))
+	cfg.DBName = Get("DB_NAME", "gateway")
+	return cfg
+}
+
+// DSN retorna a string de conexão do banco selecionado em DB_DRIVER
+func DSN() string {
+	if DatabaseDriver() == "mysql" {
+		return MySQLDSN()
+	}
+	return PostgresDSN()
+}
+
+// PoolConfig retorna a configuração do pool do primário a partir das variáveis DB_POOL_*,
+// DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME e DB_STATEMENT_TIMEOUT
+func PoolConfig() database.PoolConfig {
+	maxConns := GetInt("DB_POOL_MAX_CONNS", 10)
+	maxConnLifetime := GetDuration("DB_POOL_MAX_CONN_LIFETIME", time.Hour)
+
+	return database.PoolConfig{
+		DSN:             DSN(),
+		MaxConns:        int32(maxConns),
+		MinConns:        int32(GetInt("DB_POOL_MIN_CONNS", 0)),
+		MaxConnIdleTime: GetDuration("DB_POOL_MAX_CONN_IDLE_TIME", 30*time.Minute),
+		MaxConnLifetime: maxConnLifetime,
+
+		MaxOpenConns:    GetInt("DB_MAX_OPEN_CONNS", maxConns),
+		MaxIdleConns:    GetInt("DB_MAX_IDLE_CONNS", 2),
+		ConnMaxLifetime: GetDuration("DB_CONN_MAX_LIFETIME", maxConnLifetime),
+
+		StatementTimeout: GetDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+
+		// Relida a cada nova conexão, acompanhando a rotação de DB_PASSWORD no cofre de segredos
+		Password: func() string { return Get("DB_PASSWORD", "") },
+	}
+}
+
+// MaintenancePoolConfig retorna a configuração usada pelos comandos de manutenção (expurgo, retenção)
+// Eles removem muitas linhas de uma vez, então usam DB_MAINTENANCE_STATEMENT_TIMEOUT (padrão sem limite)
+func MaintenancePoolConfig() database.PoolConfig {
+	cfg := PoolConfig()
+	cfg.StatementTimeout = GetDuration("DB_MAINTENANCE_STATEMENT_TIMEOUT", 0)
+	return cfg
+}
+
+// MigrationPoolConfig retorna a configuração usada por gateway migrate, a mesma dos comandos de manutenção
+// No MySQL cada migration tem vários comandos, então a conexão aceita mais de um comando por chamada
+func MigrationPoolConfig() database.PoolConfig {
+	cfg := MaintenancePoolConfig()
+	if DatabaseDriver() == "mysql" {
+		mysqlCfg := mysqlConfig()
+		mysqlCfg.MultiStatements = true
+		cfg.DSN = mysqlCfg.FormatDSN()
+	}
+	return cfg
+}
+
+// RetryPolicy retorna a política de retentativa para erros transitórios a partir das variáveis DB_RETRY_*
+func RetryPolicy() database.RetryPolicy {
+	return database.RetryPolicy{
+		MaxAttempts: GetInt("DB_RETRY_MAX_ATTEMPTS", 3),
+		BaseDelay:   GetDuration("DB_RETRY_BASE_DELAY", 50*time.Millisecond),
+		MaxDelay:    GetDuration("DB_RETRY_MAX_DELAY", time.Second),
+	}
+}
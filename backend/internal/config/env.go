@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/secrets"
+	"github.com/joho/godotenv"
+)
+
+// secretStore, quando carregado por LoadSecrets, tem precedência sobre as variáveis de ambiente
+var secretStore *secrets.Store
+
+// envFile é o arquivo carregado por LoadEnvFile e envFileKeys as variáveis que vieram dele, as únicas que
+// ReloadEnvFile altera; as definidas no ambiente do processo têm precedência, como no godotenv.Load
+var (
+	envFile     string
+	envFileKeys = map[string]bool{}
+)
+
+// LoadEnvFile carrega as variáveis do arquivo .env sem sobrescrever as do ambiente do processo
+func LoadEnvFile(path string) error {
+	envFile = path
+	return ReloadEnvFile()
+}
+
+// ReloadEnvFile relê o arquivo de LoadEnvFile: variáveis novas ou alteradas passam a valer e as removidas do
+// arquivo deixam de existir
+func ReloadEnvFile() error {
+	values, err := godotenv.Read(envFile)
+	if err != nil {
+		return err
+	}
+
+	for key := range envFileKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(envFileKeys, key)
+		}
+	}
+	for key, value := range values {
+		if _, ok := os.LookupEnv(key); ok && !envFileKeys[key] {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+		envFileKeys[key] = true
+	}
+	return nil
+}
+
+// lookup lê a chave do cofre de segredos, se configurado, ou das variáveis de ambiente
+func lookup(key string) string {
+	if secretStore != nil {
+		if value, ok := secretStore.Lookup(key); ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+// LoadSecrets carrega os segredos do provedor de SECRETS_PROVIDER ("vault" ou "aws") e os torna
+// visíveis para Get e demais funções deste pacote, com precedência sobre o .env
+// Retorna nil, mantendo apenas as variáveis de ambiente, quando SECRETS_PROVIDER não está definida
+func LoadSecrets(ctx context.Context) (*secrets.Store, error) {
+	var provider secrets.Provider
+	var err error
+
+	switch name := os.Getenv("SECRETS_PROVIDER"); name {
+	case "":
+		return nil, nil
+	case "vault":
+		provider, err = secrets.NewVaultProvider(Get("VAULT_SECRET_MOUNT", "secret"), Get("VAULT_SECRET_PATH", "gateway"))
+	case "aws":
+		provider, err = secrets.NewAWSProvider(ctx, Get("AWS_SECRET_ID", "gateway"))
+	default:
+		return nil, fmt.Errorf("unsupported SECRETS_PROVIDER %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	store := secrets.NewStore(provider)
+	if err := store.Load(ctx); err != nil {
+		return nil, err
+	}
+	secretStore = store
+	return store, nil
+}
+
+// Get retorna variável de ambiente (ou segredo do cofre, se carregado) ou valor padrão se não definida
+func Get(key, defaultValue string) string {
+	if value := lookup(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// GetInt retorna variável de ambiente convertida para inteiro ou valor padrão se inválida
+func GetInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(lookup(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetFloat retorna variável de ambiente convertida para float ou valor padrão se inválida
+func GetFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(lookup(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetDuration retorna variável de ambiente convertida para duração (ex: 30m) ou valor padrão se inválida
+func GetDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(lookup(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
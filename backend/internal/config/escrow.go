@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// EscrowReleaseInterval lê ESCROW_RELEASE_INTERVAL, a frequência com que as retenções em custódia vencidas são
+// liberadas para o saldo das contas; o padrão é 1m
+func EscrowReleaseInterval() (time.Duration, error) {
+	interval := GetDuration("ESCROW_RELEASE_INTERVAL", time.Minute)
+	if interval <= 0 {
+		return 0, fmt.Errorf("ESCROW_RELEASE_INTERVAL must be positive, got %s", interval)
+	}
+	return interval, nil
+}
+
+// PayoutDefaultDelay lê PAYOUT_DEFAULT_SCHEDULE, o prazo de repasse das contas que não escolheram um, no formato
+// "D+N"; o padrão é D+0, que credita o saldo na aprovação
+func PayoutDefaultDelay() (int, error) {
+	schedule := Get("PAYOUT_DEFAULT_SCHEDULE", "D+0")
+	delay, err := domain.ParsePayoutDelay(schedule)
+	if err != nil {
+		return 0, fmt.Errorf("PAYOUT_DEFAULT_SCHEDULE must be one of D+0, D+1, D+14 or D+30, got %q", schedule)
+	}
+	return delay, nil
+}
+
+// AnticipationMonthlyRate lê ANTICIPATION_MONTHLY_RATE, a taxa das antecipações em percentual ao mês, proporcional
+// aos dias que faltam para a data de repasse; 0, o padrão, desativa as antecipações
+func AnticipationMonthlyRate() (float64, error) {
+	rate := GetFloat("ANTICIPATION_MONTHLY_RATE", 0)
+	if rate < 0 || rate >= 100 {
+		return 0, fmt.Errorf("ANTICIPATION_MONTHLY_RATE must be between 0 and 100, got %v", rate)
+	}
+	return rate, nil
+}
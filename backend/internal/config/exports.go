@@ -0,0 +1,28 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/objectstore"
+)
+
+// ExportStore cria o armazenamento das exportações conforme EXPORT_STORAGE: "local" (diretório EXPORT_LOCAL_DIR)
+// ou "s3" (EXPORT_S3_BUCKET, EXPORT_S3_PREFIX e EXPORT_S3_ENDPOINT opcional, para MinIO e compatíveis)
+// Retorna nil, com as exportações desativadas, quando EXPORT_STORAGE não está definida
+func ExportStore(ctx context.Context) (objectstore.Store, error) {
+	switch name := Get("EXPORT_STORAGE", ""); name {
+	case "":
+		return nil, nil
+	case "local":
+		return objectstore.NewLocalStore(Get("EXPORT_LOCAL_DIR", "./exports"))
+	case "s3":
+		bucket := Get("EXPORT_S3_BUCKET", "")
+		if bucket == "" {
+			return nil, fmt.Errorf("EXPORT_S3_BUCKET is required when EXPORT_STORAGE is s3")
+		}
+		return objectstore.NewS3Store(ctx, bucket, Get("EXPORT_S3_PREFIX", ""), Get("EXPORT_S3_ENDPOINT", ""))
+	default:
+		return nil, fmt.Errorf("unsupported EXPORT_STORAGE %q", name)
+	}
+}
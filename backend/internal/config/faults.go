@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+)
+
+// FaultInjection lê as regras de injeção de falhas de FAULT_INJECTION, como
+// "db:error=0.05,latency=300ms@0.2;kafka:error=0.5": error é a fração das chamadas que falham e latency o
+// atraso seguido da fração das chamadas atrasadas
+// Retorna nil sem regras; configurar regras em um binário sem a build tag faultinject é um erro
+func FaultInjection() (map[faults.Component]faults.Rule, error) {
+	value := strings.TrimSpace(Get("FAULT_INJECTION", ""))
+	if value == "" {
+		return nil, nil
+	}
+	if !faults.Enabled {
+		return nil, fmt.Errorf("FAULT_INJECTION requires a binary built with -tags faultinject")
+	}
+
+	rules := map[faults.Component]faults.Rule{}
+	for _, entry := range strings.Split(value, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, settings, ok := strings.Cut(entry, ":")
+		component := faults.Component(strings.TrimSpace(name))
+		if !ok || !slices.Contains(faults.Components, component) {
+			return nil, fmt.Errorf("invalid FAULT_INJECTION rule %q", entry)
+		}
+		if _, seen := rules[component]; seen {
+			return nil, fmt.Errorf("duplicate FAULT_INJECTION rule for %q", component)
+		}
+		rule, err := parseFaultRule(settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FAULT_INJECTION rule %q: %w", entry, err)
+		}
+		rules[component] = rule
+	}
+	return rules, nil
+}
+
+// parseFaultRule lê as configurações de um componente, como "error=0.05,latency=300ms@0.2"
+func parseFaultRule(settings string) (faults.Rule, error) {
+	var rule faults.Rule
+	for _, setting := range strings.Split(settings, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(setting), "=")
+		if !ok {
+			return rule, fmt.Errorf("expected key=value, got %q", setting)
+		}
+		switch strings.TrimSpace(key) {
+		case "error":
+			rate, err := parseFaultRate(value)
+			if err != nil {
+				return rule, err
+			}
+			rule.ErrorRate = rate
+		case "latency":
+			delay, rateValue, ok := strings.Cut(value, "@")
+			if !ok {
+				return rule, fmt.Errorf("latency must be written as duration@rate")
+			}
+			latency, err := time.ParseDuration(strings.TrimSpace(delay))
+			if err != nil || latency <= 0 {
+				return rule, fmt.Errorf("latency must be a positive duration")
+			}
+			rate, err := parseFaultRate(rateValue)
+			if err != nil {
+				return rule, err
+			}
+			rule.Latency, rule.LatencyRate = latency, rate
+		default:
+			return rule, fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	return rule, nil
+}
+
+// parseFaultRate lê uma fração entre 0 e 1
+func parseFaultRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate must be between 0 and 1")
+	}
+	return rate, nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// ChargebackFee lê CHARGEBACK_FEE, a tarifa padrão debitada da conta em cada disputa registrada
+// As tarifas de cada conta substituem o padrão; 0, o padrão, não cobra tarifa
+func ChargebackFee() (float64, error) {
+	fee := GetFloat("CHARGEBACK_FEE", 0)
+	if fee < 0 {
+		return 0, fmt.Errorf("CHARGEBACK_FEE must not be negative, got %v", fee)
+	}
+	return fee, nil
+}
+
+// LateFees lê os encargos das faturas com vencimento pagas depois dele: LATE_FEE_FINE_PERCENT (padrão 2) é a multa
+// em percentual do valor e LATE_FEE_MONTHLY_INTEREST_PERCENT (padrão 1) os juros de mora em percentual ao mês,
+// proporcionais aos dias de atraso; 0 desativa cada um
+func LateFees() (domain.LateFeePolicy, error) {
+	policy := domain.LateFeePolicy{
+		FinePercent:            GetFloat("LATE_FEE_FINE_PERCENT", 2),
+		MonthlyInterestPercent: GetFloat("LATE_FEE_MONTHLY_INTEREST_PERCENT", 1),
+	}
+	if policy.FinePercent < 0 || policy.FinePercent > 100 {
+		return policy, fmt.Errorf("LATE_FEE_FINE_PERCENT must be between 0 and 100, got %v", policy.FinePercent)
+	}
+	if policy.MonthlyInterestPercent < 0 || policy.MonthlyInterestPercent > 100 {
+		return policy, fmt.Errorf("LATE_FEE_MONTHLY_INTEREST_PERCENT must be between 0 and 100, got %v", policy.MonthlyInterestPercent)
+	}
+	return policy, nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/flags"
+)
+
+// flagName restringe os nomes das flags a letras minúsculas, dígitos, "_", "." e "-"
+var flagName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// FeatureFlags lê as flags de FEATURE_FLAGS, como "pix=on,new_antifraud=25%,legacy_export=off", e as contas
+// piloto de FEATURE_FLAG_ACCOUNTS, como "new_antifraud=<id>|<id>"; uma flag só com contas piloto fica desligada
+// para as demais
+func FeatureFlags() ([]flags.Flag, error) {
+	byName := map[string]*flags.Flag{}
+	var names []string
+	flag := func(name string) *flags.Flag {
+		if byName[name] == nil {
+			byName[name] = &flags.Flag{Name: name}
+			names = append(names, name)
+		}
+		return byName[name]
+	}
+
+	rules, err := flagRules("FEATURE_FLAGS")
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		target := flag(rule.name)
+		switch value := strings.ToLower(rule.value); {
+		case value == "on" || value == "true":
+			target.Enabled = true
+		case value == "off" || value == "false":
+		case strings.HasSuffix(value, "%"):
+			percentage, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+			if err != nil || percentage < 0 || percentage > 100 {
+				return nil, fmt.Errorf("invalid FEATURE_FLAGS percentage for %q: %q", rule.name, rule.value)
+			}
+			target.Percentage = percentage
+		default:
+			return nil, fmt.Errorf("invalid FEATURE_FLAGS value for %q: %q", rule.name, rule.value)
+		}
+	}
+
+	rules, err = flagRules("FEATURE_FLAG_ACCOUNTS")
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		target := flag(rule.name)
+		for _, account := range strings.Split(rule.value, "|") {
+			if account = strings.TrimSpace(account); account != "" {
+				target.Accounts = append(target.Accounts, account)
+			}
+		}
+	}
+
+	list := make([]flags.Flag, len(names))
+	for i, name := range names {
+		list[i] = *byName[name]
+	}
+	return list, nil
+}
+
+type flagRule struct {
+	name  string
+	value string
+}
+
+// flagRules separa a variável em pares nome=valor separados por vírgula
+func flagRules(key string) ([]flagRule, error) {
+	var rules []flagRule
+	for _, rule := range strings.Split(Get(key, ""), ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(rule, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !flagName.MatchString(name) {
+			return nil, fmt.Errorf("invalid %s rule %q", key, rule)
+		}
+		rules = append(rules, flagRule{name: name, value: strings.TrimSpace(value)})
+	}
+	return rules, nil
+}
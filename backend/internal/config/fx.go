@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/fx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// FX lê as faturas em outra moeda: SETTLEMENT_CURRENCY (padrão BRL) é a moeda dos saldos, FX_RATE_LOCK_WINDOW
+// (padrão 15m) por quanto tempo a cotação da criação vale e FX_PROVIDER o cotador, "static" com as taxas de
+// FX_STATIC_RATES, como "USD:5.10,EUR:5.55", ou "http" com o provedor em FX_PROVIDER_URL, FX_PROVIDER_TOKEN
+// opcional e cada chamada limitada a FX_PROVIDER_TIMEOUT (padrão 2s)
+// Sem FX_PROVIDER as faturas só são aceitas na moeda de liquidação
+func FX() (service.FXConfig, error) {
+	config := service.FXConfig{
+		SettlementCurrency: Get("SETTLEMENT_CURRENCY", "BRL"),
+		LockWindow:         GetDuration("FX_RATE_LOCK_WINDOW", 15*time.Minute),
+	}
+	if !domain.ValidCurrency(config.SettlementCurrency) {
+		return config, fmt.Errorf("SETTLEMENT_CURRENCY must be an ISO 4217 code, got %q", config.SettlementCurrency)
+	}
+	if config.LockWindow <= 0 {
+		return config, fmt.Errorf("FX_RATE_LOCK_WINDOW must be positive, got %s", config.LockWindow)
+	}
+
+	switch name := Get("FX_PROVIDER", ""); name {
+	case "":
+	case "static":
+		rates, err := fxRates(Get("FX_STATIC_RATES", ""), config.SettlementCurrency)
+		if err != nil {
+			return config, err
+		}
+		config.Rates = fx.NewStaticRates(rates)
+	case "http":
+		url := Get("FX_PROVIDER_URL", "")
+		if url == "" {
+			return config, fmt.Errorf("FX_PROVIDER_URL is required when FX_PROVIDER is http")
+		}
+		circuit, err := CircuitBreaker("fx_provider", GetDuration("FX_PROVIDER_TIMEOUT", 2*time.Second))
+		if err != nil {
+			return config, err
+		}
+		config.Rates = fx.NewProvider(url, Get("FX_PROVIDER_TOKEN", ""), circuit)
+	default:
+		return config, fmt.Errorf("unsupported FX_PROVIDER %q", name)
+	}
+	return config, nil
+}
+
+// fxRates lê as taxas no formato "MOEDA:TAXA", separadas por vírgula
+func fxRates(value, settlement string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		currency, rateValue, ok := strings.Cut(pair, ":")
+		currency = strings.TrimSpace(currency)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateValue), 64)
+		if !ok || !domain.ValidCurrency(currency) || currency == settlement || rates[currency] > 0 || err != nil || rate <= 0 {
+			return nil, fmt.Errorf("FX_STATIC_RATES must be CURRENCY:RATE pairs with unique ISO 4217 codes other than the settlement currency and positive rates, got %q", pair)
+		}
+		rates[currency] = rate
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("FX_STATIC_RATES is required when FX_PROVIDER is static")
+	}
+	return rates, nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/geoip"
+)
+
+// GeoIPDatabase carrega a base de países definida em GEOIP_DATABASE
+// Retorna nil, sem resolução de países, quando a variável não está definida
+func GeoIPDatabase() (*geoip.Database, error) {
+	path := Get("GEOIP_DATABASE", "")
+	if path == "" {
+		return nil, nil
+	}
+
+	database, err := geoip.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GEOIP_DATABASE: %w", err)
+	}
+	return database, nil
+}
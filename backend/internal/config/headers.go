@@ -0,0 +1,18 @@
+package config
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
+)
+
+// SecurityHeaders monta os headers de segurança a partir das variáveis SECURITY_*
+func SecurityHeaders() middleware.SecurityHeadersConfig {
+	return middleware.SecurityHeadersConfig{
+		HSTSMaxAge:                GetDuration("SECURITY_HSTS_MAX_AGE", 365*24*time.Hour),
+		HSTSIncludeSubdomains:     Get("SECURITY_HSTS_INCLUDE_SUBDOMAINS", "false") == "true",
+		FrameOptions:              Get("SECURITY_FRAME_OPTIONS", "DENY"),
+		ContentSecurityPolicy:     Get("SECURITY_CSP", middleware.DefaultContentSecurityPolicy),
+		HTMLContentSecurityPolicy: Get("SECURITY_HTML_CSP", middleware.DefaultHTMLContentSecurityPolicy),
+	}
+}
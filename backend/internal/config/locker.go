@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/locker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// SingletonLockRetry é o intervalo em que as réplicas sem o lock de uma tarefa de execução única voltam a
+// disputá-lo, de SINGLETON_LOCK_RETRY_INTERVAL (padrão 15s)
+func SingletonLockRetry() (time.Duration, error) {
+	retry := GetDuration("SINGLETON_LOCK_RETRY_INTERVAL", 15*time.Second)
+	if retry <= 0 {
+		return 0, fmt.Errorf("SINGLETON_LOCK_RETRY_INTERVAL must be positive")
+	}
+	return retry, nil
+}
+
+// SingletonLocker cria o locker das tarefas de execução única quando o armazenamento não é SQL, que usa os
+// locks do próprio banco: com o cliente Redis os locks valem para todas as réplicas e expiram em
+// SINGLETON_LOCK_TTL (padrão 30s) se a réplica parar de renová-los; com client nil ficam na memória da instância
+func SingletonLocker(client *redis.Client) (locker.Locker, error) {
+	if client == nil {
+		return locker.NewLocal(), nil
+	}
+	ttl := GetDuration("SINGLETON_LOCK_TTL", 30*time.Second)
+	if ttl < 3*time.Second {
+		return nil, fmt.Errorf("SINGLETON_LOCK_TTL must be at least 3s")
+	}
+	return locker.NewRedis(client, ttl), nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/errortracker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
+)
+
+// logLevel é o nível do logger criado por Logger, trocado ao recarregar a configuração
+var logLevel = new(slog.LevelVar)
+
+// Logger cria o logger do gateway com o formato de LOG_FORMAT ("json", padrão, ou "text") e o nível de
+// LOG_LEVEL ("debug", "info", padrão, "warn" ou "error")
+// Com tracker, os logs de erro também vão para o rastreador de erros
+func Logger(w io.Writer, tracker *errortracker.Tracker) (*slog.Logger, error) {
+	level, err := logLevelSetting()
+	if err != nil {
+		return nil, err
+	}
+	logLevel.Set(level)
+
+	handler, err := logging.NewHandler(w, logging.Format(Get("LOG_FORMAT", string(logging.FormatJSON))), logLevel, tracker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_FORMAT: %w", err)
+	}
+	return slog.New(handler), nil
+}
+
+// logLevelSetting lê o nível dos logs de LOG_LEVEL
+func logLevelSetting() (slog.Level, error) {
+	level, err := logging.ParseLevel(Get("LOG_LEVEL", "info"))
+	if err != nil {
+		return level, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+	return level, nil
+}
+
+// AccessLog define a amostragem das linhas de acesso: LOG_SAMPLE_RATE (padrão 1, todas) vale para as respostas
+// bem-sucedidas de todas as rotas e LOG_ROUTE_SAMPLE_RATES substitui a taxa por rota, como
+// "GET /invoice/{id}=0.01,POST /invoice=0.1"; respostas de erro são sempre registradas
+func AccessLog() (middleware.AccessLogConfig, error) {
+	config := middleware.AccessLogConfig{
+		SampleRate:       GetFloat("LOG_SAMPLE_RATE", 1),
+		RouteSampleRates: map[string]float64{},
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return config, fmt.Errorf("LOG_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	for _, rule := range strings.Split(Get("LOG_ROUTE_SAMPLE_RATES", ""), ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndex(rule, "=")
+		if i < 0 {
+			return config, fmt.Errorf("invalid LOG_ROUTE_SAMPLE_RATES rule %q", rule)
+		}
+		route := strings.Join(strings.Fields(rule[:i]), " ")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rule[i+1:]), 64)
+		if err != nil || rate < 0 || rate > 1 || !strings.Contains(route, " /") {
+			return config, fmt.Errorf("invalid LOG_ROUTE_SAMPLE_RATES rule %q", rule)
+		}
+		config.RouteSampleRates[route] = rate
+	}
+	return config, nil
+}
+
+// ErrorTracker cria o envio de erros e panics ao rastreador de ERROR_TRACKER_DSN, no protocolo do Sentry,
+// identificando a implantação por ERROR_TRACKER_ENVIRONMENT (padrão "production") e ERROR_TRACKER_RELEASE
+// (padrão a versão e o commit gravados no build)
+// Retorna nil quando o DSN não está configurado
+func ErrorTracker() (*errortracker.Tracker, error) {
+	dsn := Get("ERROR_TRACKER_DSN", "")
+	if dsn == "" {
+		return nil, nil
+	}
+	return errortracker.New(dsn, errortracker.Options{
+		Environment: Get("ERROR_TRACKER_ENVIRONMENT", "production"),
+		Release:     Get("ERROR_TRACKER_RELEASE", buildinfo.Release()),
+	})
+}
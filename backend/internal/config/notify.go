@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+// SecurityWebhook cria o envio dos alertas de segurança para SECURITY_WEBHOOK_URL, assinados com
+// SECURITY_WEBHOOK_SECRET; retorna nil quando a URL não está definida
+// O segredo pode vir cifrado (enc:v1:...) pelas chaves do propósito WEBHOOK, e é decifrado aqui
+func SecurityWebhook(ctx context.Context) (*notify.Webhook, error) {
+	url := Get("SECURITY_WEBHOOK_URL", "")
+	if url == "" {
+		return nil, nil
+	}
+
+	secret := Get("SECURITY_WEBHOOK_SECRET", "")
+	if secret == "" {
+		return nil, fmt.Errorf("SECURITY_WEBHOOK_SECRET is required with SECURITY_WEBHOOK_URL")
+	}
+	if pii.IsEncrypted(secret) {
+		encryptor, err := WebhookEncryptor(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if encryptor == nil {
+			return nil, fmt.Errorf("SECURITY_WEBHOOK_SECRET is encrypted but no WEBHOOK key is configured")
+		}
+		if secret, err = encryptor.Decrypt(ctx, secret); err != nil {
+			return nil, fmt.Errorf("decrypting SECURITY_WEBHOOK_SECRET: %w", err)
+		}
+	}
+	return notify.NewWebhook(url, secret, GetDuration("SECURITY_WEBHOOK_TIMEOUT", 10*time.Second)), nil
+}
+
+// Mailer cria o envio de e-mails pelo servidor SMTP_ADDR; retorna nil quando ele não está definido
+func Mailer() (*notify.Mailer, error) {
+	addr := Get("SMTP_ADDR", "")
+	if addr == "" {
+		return nil, nil
+	}
+
+	from := Get("SMTP_FROM", "")
+	if from == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required with SMTP_ADDR")
+	}
+	return notify.NewMailer(notify.MailerConfig{
+		Addr:     addr,
+		Username: Get("SMTP_USERNAME", ""),
+		Password: Get("SMTP_PASSWORD", ""),
+		From:     from,
+	}), nil
+}
+
+// SecurityAlertEmails lê os endereços de SECURITY_ALERT_EMAILS, separados por vírgula
+func SecurityAlertEmails() []string {
+	return emailList("SECURITY_ALERT_EMAILS")
+}
+
+// AlertEmails lê os destinatários do alerta interno em ALERT_EMAILS, separados por vírgula
+func AlertEmails() []string {
+	return emailList("ALERT_EMAILS")
+}
+
+// emailList lê uma lista de endereços separados por vírgula
+func emailList(key string) []string {
+	var emails []string
+	for _, email := range strings.Split(Get(key, ""), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+// keyProvider cria o provedor das chaves mestras de um propósito (PII, CARD ou WEBHOOK) conforme KMS_PROVIDER:
+// "local" (padrão, chave em <propósito>_ENCRYPTION_KEY), "file" (<propósito>_KEY_FILE), "aws" ou "gcp"
+// (<propósito>_KMS_KEY_ID e <propósito>_KMS_PREVIOUS_KEY_IDS), estes protegidos por um circuit breaker
+// Retorna nil quando o propósito não tem chave configurada
+func keyProvider(ctx context.Context, purpose, defaultKeyID string) (pii.KeyProvider, error) {
+	switch name := Get("KMS_PROVIDER", "local"); name {
+	case "local":
+		encoded := Get(purpose+"_ENCRYPTION_KEY", "")
+		if encoded == "" {
+			return nil, nil
+		}
+		masterKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_ENCRYPTION_KEY: %w", purpose, err)
+		}
+		return pii.NewLocalKeyProvider(Get(purpose+"_KEY_ID", defaultKeyID), masterKey)
+	case "file":
+		path := Get(purpose+"_KEY_FILE", "")
+		if path == "" {
+			return nil, nil
+		}
+		return pii.LoadKeyFile(path)
+	case "aws", "gcp":
+		keyID := Get(purpose+"_KMS_KEY_ID", "")
+		if keyID == "" {
+			return nil, nil
+		}
+		var previous []string
+		for _, previousKeyID := range strings.Split(Get(purpose+"_KMS_PREVIOUS_KEY_IDS", ""), ",") {
+			if previousKeyID = strings.TrimSpace(previousKeyID); previousKeyID != "" {
+				previous = append(previous, previousKeyID)
+			}
+		}
+		var keys pii.KeyProvider
+		var err error
+		if name == "aws" {
+			keys, err = pii.NewAWSKMSProvider(ctx, keyID, previous)
+		} else {
+			keys, err = pii.NewGCPKMSProvider(ctx, keyID, previous)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Um KMS fora do ar recusa as cifragens na hora em vez de segurar cada requisição até KMS_TIMEOUT
+		circuit, err := CircuitBreaker("kms_"+strings.ToLower(purpose), GetDuration("KMS_TIMEOUT", 2*time.Second))
+		if err != nil {
+			return nil, err
+		}
+		return pii.WithBreaker(keys, circuit), nil
+	default:
+		return nil, fmt.Errorf("unsupported KMS_PROVIDER %q", name)
+	}
+}
+
+// PIIEncryptor cria o encryptor de dados pessoais com as chaves do propósito PII e PII_BLIND_INDEX_KEY (base64)
+// Retorna nil, sem cifragem, quando nenhuma chave de PII está configurada
+func PIIEncryptor(ctx context.Context) (*pii.Encryptor, error) {
+	keys, err := keyProvider(ctx, "PII", "local-1")
+	if err != nil || keys == nil {
+		return nil, err
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(Get("PII_BLIND_INDEX_KEY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PII_BLIND_INDEX_KEY: %w", err)
+	}
+	if len(blindIndexKey) == 0 {
+		return nil, fmt.Errorf("PII_BLIND_INDEX_KEY is required when PII encryption is configured")
+	}
+
+	return pii.NewEncryptor(keys, blindIndexKey), nil
+}
+
+// CardEncryptor cria o encryptor do cofre de cartões com as chaves do propósito CARD
+// A chave é separada da dos dados pessoais para que o acesso aos números dos cartões seja controlado à parte
+// Retorna nil quando nenhuma chave de cartão está configurada; nesse caso o cofre não guarda os números
+func CardEncryptor(ctx context.Context) (*pii.Encryptor, error) {
+	keys, err := keyProvider(ctx, "CARD", "card-1")
+	if err != nil || keys == nil {
+		return nil, err
+	}
+
+	// O cofre só busca por token, então dispensa índices cegos
+	return pii.NewEncryptor(keys, nil), nil
+}
+
+// WebhookEncryptor cria o encryptor dos segredos de assinatura de webhooks com as chaves do propósito WEBHOOK
+// Retorna nil quando nenhuma chave de webhook está configurada
+func WebhookEncryptor(ctx context.Context) (*pii.Encryptor, error) {
+	keys, err := keyProvider(ctx, "WEBHOOK", "webhook-1")
+	if err != nil || keys == nil {
+		return nil, err
+	}
+	return pii.NewEncryptor(keys, nil), nil
+}
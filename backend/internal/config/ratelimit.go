@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/ratelimit"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// RedisClient cria o cliente do Redis definido em REDIS_URL, compartilhado pelo limitador e pelo registro de nonces
+// Retorna nil quando a variável não está definida
+func RedisClient() (*redis.Client, error) {
+	redisURL := Get("REDIS_URL", "")
+	if redisURL == "" {
+		return nil, nil
+	}
+
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	return redis.NewClient(options), nil
+}
+
+// RedisRequestTimeout é o tempo máximo de cada consulta ao Redis feita durante uma requisição, lido de
+// REDIS_REQUEST_TIMEOUT (padrão 50ms); a conexão aberta nessa consulta também respeita o prazo
+func RedisRequestTimeout() time.Duration {
+	return GetDuration("REDIS_REQUEST_TIMEOUT", 50*time.Millisecond)
+}
+
+// RateLimiter cria o limitador por API Key a partir de RATE_LIMIT_RPS e RATE_LIMIT_BURST (padrão o dobro da taxa)
+// Com o cliente Redis os limites valem para todas as réplicas; com client nil, em memória por instância
+// Retorna nil, sem limite, quando RATE_LIMIT_RPS não está definida
+func RateLimiter(client *redis.Client) (ratelimit.Limiter, error) {
+	limits, err := rateLimits()
+	if err != nil || limits == nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return ratelimit.NewMemoryLimiter(*limits), nil
+	}
+	return ratelimit.NewRedisLimiter(client, *limits, RedisRequestTimeout()), nil
+}
+
+// rateLimits lê RATE_LIMIT_RPS e RATE_LIMIT_BURST; retorna nil quando RATE_LIMIT_RPS não está definida
+func rateLimits() (*ratelimit.Config, error) {
+	rate := GetFloat("RATE_LIMIT_RPS", 0)
+	if rate <= 0 {
+		return nil, nil
+	}
+
+	limits := &ratelimit.Config{Rate: rate, Burst: GetInt("RATE_LIMIT_BURST", int(math.Ceil(2*rate)))}
+	if limits.Burst < 1 {
+		return nil, fmt.Errorf("RATE_LIMIT_BURST must be at least 1")
+	}
+	return limits, nil
+}
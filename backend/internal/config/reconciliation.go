@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// Reconciliation lê a frequência da conciliação dos saldos com o razão (BALANCE_RECONCILIATION_INTERVAL, padrão 1h,
+// 0 desativa) e se os saldos divergentes são corrigidos (BALANCE_RECONCILIATION_AUTO_CORRECT, padrão false)
+func Reconciliation() (service.ReconciliationConfig, error) {
+	config := service.ReconciliationConfig{
+		Interval:    GetDuration("BALANCE_RECONCILIATION_INTERVAL", time.Hour),
+		AutoCorrect: Get("BALANCE_RECONCILIATION_AUTO_CORRECT", "false") == "true",
+	}
+	if config.Interval < 0 {
+		return config, fmt.Errorf("BALANCE_RECONCILIATION_INTERVAL must not be negative, got %s", config.Interval)
+	}
+	return config, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// Refund lê o prazo dos reembolsos pendentes de aprovação (REFUND_APPROVAL_TTL), a frequência com que os vencidos
+// são expirados (REFUND_EXPIRY_INTERVAL) e o prazo padrão, em dias da criação da fatura, para pedir o reembolso
+// (REFUND_WINDOW_DAYS, padrão 90 e 0 desativa)
+func Refund() (service.RefundConfig, error) {
+	config := service.RefundConfig{
+		ApprovalTTL: GetDuration("REFUND_APPROVAL_TTL", 24*time.Hour),
+		Interval:    GetDuration("REFUND_EXPIRY_INTERVAL", time.Minute),
+		WindowDays:  GetInt("REFUND_WINDOW_DAYS", 90),
+	}
+	if config.ApprovalTTL <= 0 || config.Interval <= 0 {
+		return config, fmt.Errorf("REFUND_APPROVAL_TTL and REFUND_EXPIRY_INTERVAL must be positive")
+	}
+	if config.WindowDays < 0 || config.WindowDays > domain.MaxRefundWindowDays {
+		return config, fmt.Errorf("REFUND_WINDOW_DAYS must be between 0 and %d", domain.MaxRefundWindowDays)
+	}
+	return config, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/flags"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/ratelimit"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
+)
+
+// Tunables reúne os ajustes que podem mudar sem reiniciar o processo
+type Tunables struct {
+	LogLevel  slog.Level
+	AccessLog middleware.AccessLogConfig
+	// RateLimit é nil quando RATE_LIMIT_RPS não está definida
+	RateLimit *ratelimit.Config
+	Security  service.SecurityConfig
+	Anomaly   service.AnomalyThresholds
+	Flags     []flags.Flag
+	// LatencySLO troca o objetivo de latência; as janelas só mudam ao reiniciar
+	LatencySLO metrics.LatencyObjective
+}
+
+// LoadTunables lê e valida todos os ajustes, retornando o primeiro valor inválido encontrado
+func LoadTunables() (Tunables, error) {
+	var tunables Tunables
+	var err error
+
+	if tunables.LogLevel, err = logLevelSetting(); err != nil {
+		return tunables, err
+	}
+	if tunables.AccessLog, err = AccessLog(); err != nil {
+		return tunables, err
+	}
+	if tunables.RateLimit, err = rateLimits(); err != nil {
+		return tunables, err
+	}
+	if tunables.Security, err = Security(); err != nil {
+		return tunables, err
+	}
+	if tunables.Anomaly, err = AnomalyThresholds(); err != nil {
+		return tunables, err
+	}
+	if tunables.Flags, err = FeatureFlags(); err != nil {
+		return tunables, err
+	}
+	if tunables.LatencySLO, err = LatencySLO(); err != nil {
+		return tunables, err
+	}
+	return tunables, nil
+}
+
+// Output converte os ajustes na resposta do recarregamento
+func (t Tunables) Output() *dto.ConfigOutput {
+	output := &dto.ConfigOutput{
+		LogLevel:             t.LogLevel.String(),
+		LogSampleRate:        t.AccessLog.SampleRate,
+		LogRouteSampleRates:  t.AccessLog.RouteSampleRates,
+		AuthAlertFailedIPs:   t.Security.AlertFailedIPs,
+		AuthAlertWindow:      t.Security.AlertWindow.String(),
+		AuthLockoutThreshold: t.Security.LockoutThreshold,
+		AuthLockoutCooloff:   t.Security.LockoutCooloff.String(),
+		AnomalyVolumeFactor:  t.Anomaly.VolumeFactor,
+		AnomalyDeclineFactor: t.Anomaly.DeclineFactor,
+		AnomalyMinInvoices:   t.Anomaly.MinInvoices,
+		FeatureFlags:         dto.FromFeatureFlags(t.Flags),
+		LatencySLO:           dto.FromLatencyObjective(t.LatencySLO),
+	}
+	if t.RateLimit != nil {
+		output.RateLimit = &dto.RateLimitOutput{Rate: t.RateLimit.Rate, Burst: t.RateLimit.Burst}
+	}
+	return output
+}
+
+// Reloader recarrega a configuração em execução: relê o .env e o cofre de segredos, valida todos os ajustes
+// e só então os aplica; com qualquer valor inválido os componentes continuam com os ajustes anteriores
+type Reloader struct {
+	mu    sync.Mutex
+	apply func(Tunables)
+}
+
+// NewReloader cria o recarregador; apply troca os ajustes em cada componente, exceto o nível dos logs,
+// trocado pelo próprio recarregador no logger criado por Logger
+func NewReloader(apply func(Tunables)) *Reloader {
+	return &Reloader{apply: apply}
+}
+
+// Reload relê e aplica os ajustes, retornando os que passaram a valer
+// Retorna ErrInvalidConfig, sem alterar nada, quando o .env não pode ser lido ou algum valor é inválido
+func (r *Reloader) Reload(ctx context.Context) (*dto.ConfigOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	output, err := r.reload(ctx)
+	if err != nil {
+		metrics.ConfigReloadsTotal.WithLabelValues("error").Inc()
+		slog.ErrorContext(ctx, "erro ao recarregar a configuração", "error", err)
+		return nil, err
+	}
+	metrics.ConfigReloadsTotal.WithLabelValues("success").Inc()
+	slog.InfoContext(ctx, "configuração recarregada", "config", output)
+	return output, nil
+}
+
+func (r *Reloader) reload(ctx context.Context) (*dto.ConfigOutput, error) {
+	if err := ReloadEnvFile(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+	if secretStore != nil {
+		if err := secretStore.Load(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	tunables, err := LoadTunables()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+
+	logLevel.Set(tunables.LogLevel)
+	r.apply(tunables)
+	return tunables.Output(), nil
+}
+
+// WatchSignals recarrega a configuração a cada SIGHUP; bloqueia até o contexto ser cancelado
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.Reload(ctx)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// RiskReview lê os limites padrão de score de risco (RISK_APPROVE_BELOW e RISK_REJECT_ABOVE) e o prazo da fila
+// de revisão manual: RISK_REVIEW_SLA, a decisão aplicada ao fim dele (RISK_REVIEW_SLA_DECISION, "approved" ou
+// "declined") e a frequência da verificação (RISK_REVIEW_INTERVAL)
+func RiskReview() (service.RiskReviewConfig, error) {
+	config := service.RiskReviewConfig{
+		SLA:         GetDuration("RISK_REVIEW_SLA", 24*time.Hour),
+		SLADecision: domain.ReviewStatus(Get("RISK_REVIEW_SLA_DECISION", string(domain.ReviewDeclined))),
+		Interval:    GetDuration("RISK_REVIEW_INTERVAL", time.Minute),
+	}
+
+	defaults, err := domain.NewRiskPolicy("", GetFloat("RISK_APPROVE_BELOW", 30), GetFloat("RISK_REJECT_ABOVE", 70))
+	if err != nil {
+		return config, fmt.Errorf("RISK_APPROVE_BELOW and RISK_REJECT_ABOVE must be between 0 and 100, approve below reject")
+	}
+	config.Defaults = *defaults
+
+	if config.SLA <= 0 || config.Interval <= 0 {
+		return config, fmt.Errorf("RISK_REVIEW_SLA and RISK_REVIEW_INTERVAL must be positive")
+	}
+	if config.SLADecision != domain.ReviewApproved && config.SLADecision != domain.ReviewDeclined {
+		return config, fmt.Errorf("invalid RISK_REVIEW_SLA_DECISION %q: expected approved or declined", config.SLADecision)
+	}
+	return config, nil
+}
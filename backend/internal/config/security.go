@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// Security lê os limites de alerta e de bloqueio das autenticações: AUTH_ALERT_FAILED_IPS, AUTH_ALERT_WINDOW,
+// AUTH_LOCKOUT_THRESHOLD e AUTH_LOCKOUT_COOLOFF; zero desativa o recurso correspondente
+func Security() (service.SecurityConfig, error) {
+	config := service.SecurityConfig{
+		AlertFailedIPs:   GetInt("AUTH_ALERT_FAILED_IPS", 5),
+		AlertWindow:      GetDuration("AUTH_ALERT_WINDOW", 10*time.Minute),
+		LockoutThreshold: GetInt("AUTH_LOCKOUT_THRESHOLD", 10),
+		LockoutCooloff:   GetDuration("AUTH_LOCKOUT_COOLOFF", 15*time.Minute),
+	}
+	if config.AlertFailedIPs < 0 || config.LockoutThreshold < 0 {
+		return config, fmt.Errorf("AUTH_ALERT_FAILED_IPS and AUTH_LOCKOUT_THRESHOLD must not be negative")
+	}
+	if config.AlertFailedIPs > 0 && config.AlertWindow <= 0 {
+		return config, fmt.Errorf("AUTH_ALERT_WINDOW must be positive")
+	}
+	if config.LockoutThreshold > 0 && config.LockoutCooloff <= 0 {
+		return config, fmt.Errorf("AUTH_LOCKOUT_COOLOFF must be positive")
+	}
+	return config, nil
+}
+
+// AnomalyThresholds lê os limites da detecção de anomalias: ANOMALY_VOLUME_FACTOR, ANOMALY_DECLINE_FACTOR e
+// ANOMALY_MIN_INVOICES
+func AnomalyThresholds() (service.AnomalyThresholds, error) {
+	thresholds := service.AnomalyThresholds{
+		VolumeFactor:  GetFloat("ANOMALY_VOLUME_FACTOR", 10),
+		DeclineFactor: GetFloat("ANOMALY_DECLINE_FACTOR", 3),
+		MinInvoices:   GetInt("ANOMALY_MIN_INVOICES", 20),
+	}
+	if thresholds.VolumeFactor <= 0 || thresholds.DeclineFactor <= 0 {
+		return thresholds, fmt.Errorf("ANOMALY_VOLUME_FACTOR and ANOMALY_DECLINE_FACTOR must be positive")
+	}
+	if thresholds.MinInvoices < 1 {
+		return thresholds, fmt.Errorf("ANOMALY_MIN_INVOICES must be at least 1")
+	}
+	return thresholds, nil
+}
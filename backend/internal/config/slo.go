@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// LatencySLO define o objetivo de latência: LATENCY_SLO_TARGET (padrão 0.99) das requisições devem levar até
+// LATENCY_SLO_THRESHOLD (padrão 500ms); LATENCY_SLO_ROUTE_THRESHOLDS substitui o limite por rota, como
+// "POST /invoice=300ms,GET /invoice/{id}=100ms", e LATENCY_SLO_FAST_BURN (padrão 14.4) é a taxa de consumo do
+// orçamento que coloca o objetivo em risco
+func LatencySLO() (metrics.LatencyObjective, error) {
+	objective := metrics.LatencyObjective{
+		Threshold:       GetDuration("LATENCY_SLO_THRESHOLD", 500*time.Millisecond),
+		RouteThresholds: map[string]time.Duration{},
+		Target:          GetFloat("LATENCY_SLO_TARGET", 0.99),
+		FastBurnRate:    GetFloat("LATENCY_SLO_FAST_BURN", 14.4),
+	}
+	if objective.Threshold <= 0 {
+		return objective, fmt.Errorf("LATENCY_SLO_THRESHOLD must be positive")
+	}
+	if objective.Target <= 0 || objective.Target >= 1 {
+		return objective, fmt.Errorf("LATENCY_SLO_TARGET must be between 0 and 1, exclusive")
+	}
+	if objective.FastBurnRate <= 1 {
+		return objective, fmt.Errorf("LATENCY_SLO_FAST_BURN must be greater than 1")
+	}
+
+	for _, rule := range strings.Split(Get("LATENCY_SLO_ROUTE_THRESHOLDS", ""), ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndex(rule, "=")
+		if i < 0 {
+			return objective, fmt.Errorf("invalid LATENCY_SLO_ROUTE_THRESHOLDS rule %q", rule)
+		}
+		route := strings.Join(strings.Fields(rule[:i]), " ")
+		threshold, err := time.ParseDuration(strings.TrimSpace(rule[i+1:]))
+		if err != nil || threshold <= 0 || !strings.Contains(route, " /") {
+			return objective, fmt.Errorf("invalid LATENCY_SLO_ROUTE_THRESHOLDS rule %q", rule)
+		}
+		objective.RouteThresholds[route] = threshold
+	}
+	return objective, nil
+}
+
+// LatencyTracker cria o acompanhamento do objetivo de latência com a janela longa de LATENCY_SLO_WINDOW
+// (padrão 1h, até 24h) e a janela curta de LATENCY_SLO_SHORT_WINDOW (padrão 5m); as janelas só mudam ao reiniciar
+func LatencyTracker() (*metrics.LatencyTracker, error) {
+	objective, err := LatencySLO()
+	if err != nil {
+		return nil, err
+	}
+	window := GetDuration("LATENCY_SLO_WINDOW", time.Hour)
+	shortWindow := GetDuration("LATENCY_SLO_SHORT_WINDOW", 5*time.Minute)
+	if window < time.Minute || window > 24*time.Hour {
+		return nil, fmt.Errorf("LATENCY_SLO_WINDOW must be between 1m and 24h")
+	}
+	if shortWindow <= 0 || shortWindow > window {
+		return nil, fmt.Errorf("LATENCY_SLO_SHORT_WINDOW must be positive and at most LATENCY_SLO_WINDOW")
+	}
+	return metrics.NewLatencyTracker(objective, window, shortWindow), nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SpendingLimits lê os tetos de gasto por conta: SPENDING_LIMIT_DAILY e SPENDING_LIMIT_MONTHLY; zero desativa o teto
+func SpendingLimits() (domain.SpendingLimits, error) {
+	limits := domain.SpendingLimits{
+		Daily:   GetFloat("SPENDING_LIMIT_DAILY", 0),
+		Monthly: GetFloat("SPENDING_LIMIT_MONTHLY", 0),
+	}
+	if limits.Daily < 0 || limits.Monthly < 0 {
+		return limits, fmt.Errorf("SPENDING_LIMIT_DAILY and SPENDING_LIMIT_MONTHLY must not be negative")
+	}
+	if limits.Daily > 0 && limits.Monthly > 0 && limits.Monthly < limits.Daily {
+		return limits, fmt.Errorf("SPENDING_LIMIT_MONTHLY must not be lower than SPENDING_LIMIT_DAILY")
+	}
+	return limits, nil
+}
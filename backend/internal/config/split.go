@@ -0,0 +1,13 @@
+package config
+
+import "fmt"
+
+// SplitFeePercent lê SPLIT_FEE_PERCENT, o percentual do valor das faturas divididas retido como taxa de divisão
+// e rateado entre as partes; 0, o padrão, não cobra taxa
+func SplitFeePercent() (float64, error) {
+	percent := GetFloat("SPLIT_FEE_PERCENT", 0)
+	if percent < 0 || percent >= 100 {
+		return 0, fmt.Errorf("SPLIT_FEE_PERCENT must be between 0 and 100, got %v", percent)
+	}
+	return percent, nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// StandingOrder lê a execução das ordens permanentes: STANDING_ORDER_INTERVAL (padrão 1m) é a frequência com que
+// as ordens vencidas são executadas e MERCHANT_WEBHOOK_TIMEOUT limita a entrega de cada evento
+func StandingOrder() (service.StandingOrderConfig, error) {
+	config := service.StandingOrderConfig{
+		Interval:       GetDuration("STANDING_ORDER_INTERVAL", time.Minute),
+		WebhookTimeout: GetDuration("MERCHANT_WEBHOOK_TIMEOUT", 10*time.Second),
+	}
+	if config.Interval <= 0 {
+		return config, fmt.Errorf("STANDING_ORDER_INTERVAL must be positive")
+	}
+	return config, nil
+}
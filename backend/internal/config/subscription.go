@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// Subscription lê as retentativas das cobranças recusadas: DUNNING_RETRY_DAYS (padrão "1,3,7") são os dias,
+// contados da data da cobrança, de cada retentativa, e DUNNING_FINAL_STATUS (padrão past_due) a situação da
+// assinatura depois da última, past_due ou cancelled; SUBSCRIPTION_BILLING_INTERVAL (padrão 1m) é a frequência
+// com que as cobranças vencidas são feitas e MERCHANT_WEBHOOK_TIMEOUT limita a entrega de cada evento
+func Subscription() (service.SubscriptionConfig, error) {
+	config := service.SubscriptionConfig{
+		Dunning: domain.DunningPolicy{
+			FinalStatus: domain.SubscriptionStatus(Get("DUNNING_FINAL_STATUS", string(domain.SubscriptionPastDue))),
+		},
+		Interval:       GetDuration("SUBSCRIPTION_BILLING_INTERVAL", time.Minute),
+		WebhookTimeout: GetDuration("MERCHANT_WEBHOOK_TIMEOUT", 10*time.Second),
+	}
+	if config.Interval <= 0 {
+		return config, fmt.Errorf("SUBSCRIPTION_BILLING_INTERVAL must be positive")
+	}
+	if config.Dunning.FinalStatus != domain.SubscriptionPastDue && config.Dunning.FinalStatus != domain.SubscriptionCancelled {
+		return config, fmt.Errorf("DUNNING_FINAL_STATUS must be past_due or cancelled")
+	}
+
+	previous := 0
+	for _, value := range strings.Split(Get("DUNNING_RETRY_DAYS", "1,3,7"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		day, err := strconv.Atoi(value)
+		if err != nil || day <= previous || day > domain.MaxSubscriptionIntervalDays {
+			return config, fmt.Errorf("DUNNING_RETRY_DAYS must be increasing positive days, got %q", value)
+		}
+		config.Dunning.RetryDays = append(config.Dunning.RetryDays, day)
+		previous = day
+	}
+	return config, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/tax"
+)
+
+// TaxCalculator cria a calculadora de impostos das faturas conforme TAX_PROVIDER: "flat" aplica as alíquotas de
+// TAX_FLAT_RATES, como "ISS:5,PIS:0.65", e "http" chama o provedor em TAX_PROVIDER_URL, com TAX_PROVIDER_TOKEN
+// opcional e cada chamada limitada a TAX_PROVIDER_TIMEOUT (padrão 2s)
+// Retorna nil, sem impostos nas faturas, quando TAX_PROVIDER não está definida
+func TaxCalculator() (domain.TaxCalculator, error) {
+	switch name := Get("TAX_PROVIDER", ""); name {
+	case "":
+		return nil, nil
+	case "flat":
+		rates, err := taxRates(Get("TAX_FLAT_RATES", ""))
+		if err != nil {
+			return nil, err
+		}
+		return tax.NewFlatRate(rates), nil
+	case "http":
+		url := Get("TAX_PROVIDER_URL", "")
+		if url == "" {
+			return nil, fmt.Errorf("TAX_PROVIDER_URL is required when TAX_PROVIDER is http")
+		}
+		circuit, err := CircuitBreaker("tax_provider", GetDuration("TAX_PROVIDER_TIMEOUT", 2*time.Second))
+		if err != nil {
+			return nil, err
+		}
+		return tax.NewProvider(url, Get("TAX_PROVIDER_TOKEN", ""), circuit), nil
+	default:
+		return nil, fmt.Errorf("unsupported TAX_PROVIDER %q", name)
+	}
+}
+
+// taxRates lê as alíquotas no formato "NOME:PERCENTUAL", separadas por vírgula
+func taxRates(value string) ([]tax.Rate, error) {
+	var rates []tax.Rate
+	names := make(map[string]bool)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, percent, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if !ok || name == "" || len(name) > domain.MaxTaxNameLength || names[name] || err != nil || rate <= 0 || rate >= 100 {
+			return nil, fmt.Errorf("TAX_FLAT_RATES must be NAME:PERCENT pairs with unique names and percents between 0 and 100, got %q", pair)
+		}
+		names[name] = true
+		rates = append(rates, tax.Rate{Name: name, Percent: rate})
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("TAX_FLAT_RATES is required when TAX_PROVIDER is flat")
+	}
+	return rates, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ServerTLS monta a terminação TLS do listener principal a partir das variáveis TLS_*
+// Com TLS_AUTOCERT_DOMAINS os certificados são emitidos e renovados pelo Let's Encrypt; sem ela, lidos de
+// TLS_CERT_FILE e TLS_KEY_FILE. Retorna nil quando nenhum dos dois modos está configurado
+// No autocert também retorna o wrapper do listener HTTP que responde aos desafios ACME
+func ServerTLS() (*tls.Config, func(http.Handler) http.Handler, error) {
+	if domains := Get("TLS_AUTOCERT_DOMAINS", ""); domains != "" {
+		hosts := strings.Split(domains, ",")
+		for i := range hosts {
+			hosts[i] = strings.TrimSpace(hosts[i])
+		}
+
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(hosts...),
+			Cache:      autocert.DirCache(Get("TLS_AUTOCERT_CACHE_DIR", "certs")),
+			Email:      Get("TLS_AUTOCERT_EMAIL", ""),
+		}
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return tlsConfig, manager.HTTPHandler, nil
+	}
+
+	certFile, keyFile := Get("TLS_CERT_FILE", ""), Get("TLS_KEY_FILE", "")
+	if certFile == "" && keyFile == "" {
+		return nil, nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TLS_CERT_FILE/TLS_KEY_FILE: %w", err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil, nil
+}
+
+// MTLS monta o listener com certificado de cliente a partir das variáveis MTLS_*
+// Retorna nil, sem o listener, quando MTLS_PORT não está definida
+func MTLS() (*tls.Config, *auth.CertificateMapper, error) {
+	if Get("MTLS_PORT", "") == "" {
+		return nil, nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(Get("MTLS_CERT_FILE", ""), Get("MTLS_KEY_FILE", ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid MTLS_CERT_FILE/MTLS_KEY_FILE: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(Get("MTLS_CLIENT_CA_FILE", ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid MTLS_CLIENT_CA_FILE: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, nil, fmt.Errorf("MTLS_CLIENT_CA_FILE has no PEM certificates")
+	}
+
+	identities, err := auth.ParseCertificateMapping(Get("MTLS_IDENTITY_MAPPING", ""))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid MTLS_IDENTITY_MAPPING: %w", err)
+	}
+
+	// Todo cliente precisa apresentar um certificado assinado pela CA; o mapeamento só define a identidade
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, identities, nil
+}
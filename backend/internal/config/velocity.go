@@ -0,0 +1,76 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/velocity"
+)
+
+// VelocityChecker cria o verificador das regras de frequência de VELOCITY_RULES, contando no Redis quando há
+// cliente e em memória caso contrário; retorna nil quando nenhuma regra está configurada
+// VELOCITY_HASH_KEY (base64) protege cartões e pagadores nas chaves e é obrigatória com Redis, para que todas as
+// réplicas contem nas mesmas chaves; sem Redis, uma chave aleatória é gerada na subida
+func VelocityChecker(client *redis.Client) (*velocity.Checker, error) {
+	rules, err := VelocityRules()
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	hashKey, err := base64.StdEncoding.DecodeString(Get("VELOCITY_HASH_KEY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VELOCITY_HASH_KEY: %w", err)
+	}
+
+	if client == nil {
+		if len(hashKey) == 0 {
+			hashKey = make([]byte, 32)
+			rand.Read(hashKey)
+		}
+		return velocity.NewChecker(velocity.NewMemoryCounter(), rules, hashKey), nil
+	}
+	if len(hashKey) == 0 {
+		return nil, fmt.Errorf("VELOCITY_HASH_KEY is required when VELOCITY_RULES is used with REDIS_URL")
+	}
+	return velocity.NewChecker(velocity.NewRedisCounter(client, RedisRequestTimeout()), rules, hashKey), nil
+}
+
+// VelocityRules lê VELOCITY_RULES no formato "card:5/1m;payer:10/1h;account:300/1m": dimensão (card, payer ou
+// account), número máximo de transações e janela
+func VelocityRules() ([]velocity.Rule, error) {
+	var rules []velocity.Rule
+	var err error
+	for _, entry := range strings.Split(Get("VELOCITY_RULES", ""), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		dimension, rest, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid VELOCITY_RULES entry %q: expected dimension:limit/window", entry)
+		}
+		limitValue, windowValue, ok := strings.Cut(rest, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid VELOCITY_RULES entry %q: expected dimension:limit/window", entry)
+		}
+
+		rule := velocity.Rule{Dimension: velocity.Dimension(strings.TrimSpace(dimension))}
+		if !rule.Dimension.IsValid() {
+			return nil, fmt.Errorf("invalid VELOCITY_RULES dimension %q: expected card, payer or account", rule.Dimension)
+		}
+		if rule.Limit, err = strconv.Atoi(strings.TrimSpace(limitValue)); err != nil || rule.Limit < 1 {
+			return nil, fmt.Errorf("invalid VELOCITY_RULES limit %q: must be a positive integer", limitValue)
+		}
+		if rule.Window, err = time.ParseDuration(strings.TrimSpace(windowValue)); err != nil || rule.Window < time.Second {
+			return nil, fmt.Errorf("invalid VELOCITY_RULES window %q: must be at least 1s", windowValue)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
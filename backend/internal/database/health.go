@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// DependencyStatus é o resultado da verificação de saúde de uma dependência
+// As estatísticas de conexões só são preenchidas nos pools de banco, indicados por Pool
+type DependencyStatus struct {
+	Name string
+	// Optional indica que a falha da dependência não compromete a aplicação (ex: réplica com fallback)
+	Optional          bool
+	PingError         error
+	PingLatency       time.Duration
+	Pool              bool
+	TotalConns        int32
+	AcquiredConns     int32
+	IdleConns         int32
+	MaxConns          int32
+	EmptyAcquireCount int64
+	// Saturated indica que a fração de conexões em uso atingiu o limite configurado
+	Saturated bool
+}
+
+// monitoredPool abstrai o pgxpool, o *sql.DB e as demais dependências por trás do ping e das estatísticas
+// stat é nil nas dependências sem pool de conexões
+type monitoredPool struct {
+	name     string
+	optional bool
+	ping     func(ctx context.Context) error
+	stat     func(status *DependencyStatus)
+}
+
+// HealthChecker verifica a conectividade das dependências e a saturação dos pools de banco
+type HealthChecker struct {
+	pools               []monitoredPool
+	saturationThreshold float64
+	pingTimeout         time.Duration
+}
+
+// NewHealthChecker cria um verificador que considera saturado o pool com ao menos
+// saturationThreshold (0 a 1) das conexões em uso
+func NewHealthChecker(saturationThreshold float64, pingTimeout time.Duration) *HealthChecker {
+	return &HealthChecker{saturationThreshold: saturationThreshold, pingTimeout: pingTimeout}
+}
+
+// AddPool inclui um pool pgx na verificação
+func (h *HealthChecker) AddPool(name string, pool *pgxpool.Pool, optional bool) {
+	h.pools = append(h.pools, monitoredPool{
+		name:     name,
+		optional: optional,
+		ping:     pool.Ping,
+		stat: func(status *DependencyStatus) {
+			stat := pool.Stat()
+			status.TotalConns = stat.TotalConns()
+			status.AcquiredConns = stat.AcquiredConns()
+			status.IdleConns = stat.IdleConns()
+			status.MaxConns = stat.MaxConns()
+			status.EmptyAcquireCount = stat.EmptyAcquireCount()
+		},
+	})
+}
+
+// AddCheck inclui na verificação uma dependência sem estatísticas de pool, como o MongoDB, o Redis ou o Kafka,
+// observando apenas o ping
+func (h *HealthChecker) AddCheck(name string, ping func(ctx context.Context) error, optional bool) {
+	h.pools = append(h.pools, monitoredPool{
+		name:     name,
+		optional: optional,
+		ping:     ping,
+	})
+}
+
+// AddDB inclui na verificação um *sql.DB com pool próprio, como o do MySQL
+// EmptyAcquireCount corresponde às esperas por conexão livre (WaitCount)
+func (h *HealthChecker) AddDB(name string, db *sql.DB, optional bool) {
+	h.pools = append(h.pools, monitoredPool{
+		name:     name,
+		optional: optional,
+		ping:     db.PingContext,
+		stat: func(status *DependencyStatus) {
+			stats := db.Stats()
+			status.TotalConns = int32(stats.OpenConnections)
+			status.AcquiredConns = int32(stats.InUse)
+			status.IdleConns = int32(stats.Idle)
+			status.MaxConns = int32(stats.MaxOpenConnections)
+			status.EmptyAcquireCount = stats.WaitCount
+		},
+	})
+}
+
+// Check pinga as dependências em paralelo, registrando a latência em /metrics, e lê as estatísticas dos pools
+// O resultado segue a ordem em que as dependências foram incluídas
+func (h *HealthChecker) Check(ctx context.Context) []DependencyStatus {
+	statuses := make([]DependencyStatus, len(h.pools))
+	var wg sync.WaitGroup
+	for i, p := range h.pools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = h.check(ctx, p)
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// check pinga uma dependência dentro do timeout e, se for um pool, lê suas estatísticas
+func (h *HealthChecker) check(ctx context.Context, p monitoredPool) DependencyStatus {
+	pingCtx, cancel := context.WithTimeout(ctx, h.pingTimeout)
+	start := time.Now()
+	err := p.ping(pingCtx)
+	latency := time.Since(start)
+	cancel()
+
+	metrics.DBPingDuration.WithLabelValues(p.name).Observe(latency.Seconds())
+
+	status := DependencyStatus{
+		Name:        p.name,
+		Optional:    p.optional,
+		PingError:   err,
+		PingLatency: latency,
+	}
+	if p.stat != nil {
+		status.Pool = true
+		p.stat(&status)
+	}
+	if status.MaxConns > 0 {
+		status.Saturated = float64(status.AcquiredConns)/float64(status.MaxConns) >= h.saturationThreshold
+	}
+	return status
+}
+
+// HTTPPing cria o ping de um serviço HTTP, como o antifraude, para AddCheck
+// O serviço está de pé se responder a um GET na URL com status abaixo de 500
+func HTTPPing(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
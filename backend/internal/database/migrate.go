@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	pgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migrator aplica e desfaz as migrations com o golang-migrate, gravando a versão em schema_migrations, a mesma
+// tabela lida por MigrationCheck
+// Um lock no próprio banco impede que duas instâncias migrem ao mesmo tempo
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewMigrator prepara as migrations de files no banco do driver, "postgres" ou "mysql"
+// No MySQL a conexão precisa aceitar vários comandos por chamada (multiStatements)
+func NewMigrator(db *sql.DB, driver string, files fs.FS) (*Migrator, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var target migratedb.Driver
+	switch driver {
+	case "postgres":
+		target, err = pgx.WithInstance(db, &pgx.Config{})
+	case "mysql":
+		target, err = mysql.WithInstance(db, &mysql.Config{})
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, driver, target)
+	if err != nil {
+		return nil, err
+	}
+	m.Log = migrateLogger{}
+	return &Migrator{migrate: m}, nil
+}
+
+// Up aplica as migrations pendentes, ou no máximo steps delas quando steps é positivo, e retorna a versão final
+// Cancelar ctx interrompe a execução depois da migration em andamento
+func (m *Migrator) Up(ctx context.Context, steps int) (uint, error) {
+	return m.run(ctx, func() error {
+		if steps > 0 {
+			return m.migrate.Steps(steps)
+		}
+		return m.migrate.Up()
+	})
+}
+
+// Down desfaz as últimas steps migrations, que precisa ser positivo, e retorna a versão final
+// Cancelar ctx interrompe a execução depois da migration em andamento
+func (m *Migrator) Down(ctx context.Context, steps int) (uint, error) {
+	if steps <= 0 {
+		return 0, errors.New("steps must be positive")
+	}
+	return m.run(ctx, func() error { return m.migrate.Steps(-steps) })
+}
+
+// run executa as migrations e retorna a versão final; não ter o que executar não é erro
+func (m *Migrator) run(ctx context.Context, execute func() error) (uint, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.migrate.GracefulStop <- true
+		case <-done:
+		}
+	}()
+
+	err := execute()
+	var short migrate.ErrShortLimit
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) && !errors.Is(err, os.ErrNotExist) && !errors.As(err, &short) {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return version, fmt.Errorf("migration %d is dirty", version)
+	}
+	return version, nil
+}
+
+// Close libera o lock e a conexão usados pelas migrations; o *sql.DB também é fechado
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// migrateLogger leva ao slog as migrations executadas pelo golang-migrate
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...any) {
+	// Os erros também são retornados pelo Migrator, e quem chamou os registra
+	if strings.HasPrefix(format, "error:") {
+		return
+	}
+	slog.Info("migration executada", "migration", strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// MigrationCheck cria a verificação de que o schema do banco está ao menos na versão expected, lida da tabela
+// schema_migrations do golang-migrate
+// Falha com o banco sem migrations, com uma migration interrompida (dirty) ou atrás da versão esperada
+// Um schema à frente é aceito: durante um rollout as migrations da versão nova podem ser aplicadas
+// antes de as réplicas antigas saírem
+func MigrationCheck(db *sql.DB, expected uint) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var version uint
+		var dirty bool
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no migrations applied, expected version %d", expected)
+		}
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < expected {
+			return fmt.Errorf("schema at version %d, expected %d", version, expected)
+		}
+		return nil
+	}
+}
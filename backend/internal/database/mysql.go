@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OpenMySQL abre o *sql.DB do MySQL/MariaDB aplicando os limites configurados e valida a conexão com um ping
+// As datas são lidas e gravadas em UTC; StatementTimeout vira max_execution_time, que no MySQL vale só para SELECT
+// Os campos MaxConns/MinConns/MaxConn* são exclusivos do pgxpool e não se aplicam aqui
+func OpenMySQL(ctx context.Context, cfg PoolConfig) (*sql.DB, error) {
+	mysqlConfig, err := mysql.ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlConfig.ParseTime = true
+	mysqlConfig.Loc = time.UTC
+	if mysqlConfig.Params == nil {
+		mysqlConfig.Params = map[string]string{}
+	}
+	mysqlConfig.Params["time_zone"] = "'+00:00'"
+	if cfg.StatementTimeout > 0 {
+		mysqlConfig.Params["max_execution_time"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.Password != nil {
+		err := mysqlConfig.Apply(mysql.BeforeConnect(func(ctx context.Context, connConfig *mysql.Config) error {
+			if password := cfg.Password(); password != "" {
+				connConfig.Passwd = password
+			}
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(connector)
+	setLimits(db, cfg)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Open abre o *sql.DB dos repositórios no banco de DB_DRIVER ("postgres" ou "mysql")
+// No PostgreSQL também retorna o pool pgx que gerencia as conexões, que deve ser fechado depois do *sql.DB;
+// no MySQL o pool é nil
+func Open(ctx context.Context, driver string, cfg PoolConfig) (*sql.DB, *pgxpool.Pool, error) {
+	switch driver {
+	case "mysql":
+		db, err := OpenMySQL(ctx, cfg)
+		return db, nil, err
+	case "postgres":
+		pool, err := NewPool(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return OpenDB(pool, cfg), pool, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported database driver %q", driver)
+}
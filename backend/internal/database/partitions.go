@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// EnsureInvoicePartitions cria as partições mensais de invoices do mês atual até monthsAhead meses à frente
+// Usa a função create_invoice_partition da migration; partições existentes são mantidas
+func EnsureInvoicePartitions(ctx context.Context, db *sql.DB, monthsAhead int) error {
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= monthsAhead; i++ {
+		if _, err := db.ExecContext(ctx, "SELECT create_invoice_partition($1)", month.AddDate(0, i, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaintainInvoicePartitions garante periodicamente as partições futuras de invoices
+// Executa imediatamente e depois a cada interval; bloqueia até o contexto ser cancelado
+func MaintainInvoicePartitions(ctx context.Context, db *sql.DB, monthsAhead int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := EnsureInvoicePartitions(ctx, db, monthsAhead); err != nil {
+			slog.Error("erro ao criar partições de faturas", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
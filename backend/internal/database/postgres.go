@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// PoolConfig agrupa as configurações do pool de conexões com o banco
+// Max*/Min* e MaxConn* configuram o pgxpool; MaxOpenConns, MaxIdleConns e ConnMaxLifetime
+// configuram o *sql.DB exposto aos repositórios
+type PoolConfig struct {
+	DSN             string
+	MaxConns        int32
+	MinConns        int32
+	MaxConnIdleTime time.Duration
+	MaxConnLifetime time.Duration
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// StatementTimeout aborta no servidor qualquer comando mais lento que o limite; zero desativa
+	StatementTimeout time.Duration
+
+	// Password, quando definido, fornece a senha de cada nova conexão no lugar da senha do DSN,
+	// para que senhas rotacionadas no cofre de segredos valham sem reiniciar; vazio mantém a do DSN
+	Password func() string
+}
+
+// NewPool cria um pool pgx aplicando os limites configurados e valida a conexão com um ping
+// Valores zerados mantêm o padrão do pgxpool
+func NewPool(ctx context.Context, cfg PoolConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	// Os comandos executados com um StatementLog no contexto entram no detalhe das consultas lentas
+	poolConfig.ConnConfig.Tracer = StatementTracer{}
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.Password != nil {
+		poolConfig.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			if password := cfg.Password(); password != "" {
+				connConfig.Password = password
+			}
+			return nil
+		}
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// OpenDB expõe o pool como *sql.DB para os repositórios baseados em database/sql
+// As conexões continuam sendo gerenciadas pelo pgxpool; os limites do *sql.DB restringem quantas ele mantém
+func OpenDB(pool *pgxpool.Pool, cfg PoolConfig) *sql.DB {
+	db := stdlib.OpenDBFromPool(pool)
+	setLimits(db, cfg)
+	return db
+}
+
+// setLimits aplica os limites do *sql.DB; valores zerados mantêm o padrão do database/sql
+func setLimits(db *sql.DB, cfg PoolConfig) {
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+}
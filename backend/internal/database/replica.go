@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// ReadRouter direciona consultas de leitura para a réplica, voltando para o primário quando ela está indisponível
+type ReadRouter struct {
+	primary *sql.DB
+	replica *sql.DB
+	healthy atomic.Bool
+}
+
+// NewReadRouter cria um roteador de leitura
+// Se replica for nil todas as leituras vão para o primário
+func NewReadRouter(primary, replica *sql.DB) *ReadRouter {
+	r := &ReadRouter{primary: primary, replica: replica}
+	r.healthy.Store(replica != nil)
+	return r
+}
+
+// DB retorna a conexão que deve atender a próxima leitura
+func (r *ReadRouter) DB() *sql.DB {
+	if r.replica != nil && r.healthy.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Monitor verifica periodicamente a réplica e alterna o roteamento conforme sua disponibilidade
+// Bloqueia até o contexto ser cancelado
+func (r *ReadRouter) Monitor(ctx context.Context, interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			err := r.replica.PingContext(pingCtx)
+			cancel()
+
+			if err != nil {
+				if r.healthy.Swap(false) {
+					slog.Warn("réplica de leitura indisponível, usando o primário", "error", err)
+				}
+				continue
+			}
+
+			if !r.healthy.Swap(true) {
+				slog.Info("réplica de leitura disponível novamente")
+			}
+		}
+	}
+}
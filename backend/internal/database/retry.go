@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// Motivos de retentativa usados no label "reason" da métrica
+const (
+	RetryReasonSerialization = "serialization_failure"
+	RetryReasonDeadlock      = "deadlock"
+	RetryReasonConnection    = "connection"
+	RetryReasonLockTimeout   = "lock_timeout"
+)
+
+// RetryPolicy define quantas vezes e com qual espera uma operação é repetida após erro transitório
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Do executa fn repetindo-a enquanto o erro for transitório e houver tentativas
+// A espera cresce exponencialmente a partir de BaseDelay, limitada a MaxDelay, com jitter completo
+// Com a injeção de falhas ligada, cada tentativa pode falhar como uma queda de conexão antes de chegar ao banco
+func (p RetryPolicy) Do(ctx context.Context, operation string, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = faults.Inject(ctx, faults.Database, driver.ErrBadConn); err == nil {
+			err = fn()
+		}
+
+		reason, transient := TransientReason(err)
+		if !transient || attempt >= p.MaxAttempts {
+			return err
+		}
+
+		metrics.DBRetriesTotal.WithLabelValues(operation, reason).Inc()
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.backoff(attempt)):
+		}
+	}
+}
+
+// backoff calcula a espera antes da próxima tentativa
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay)
+}
+
+// IsUniqueViolation indica se o erro é a violação de uma chave primária ou de um índice único: 23505 no
+// PostgreSQL e 1062 no MySQL
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// TransientReason classifica o erro e indica se vale a pena repetir a operação
+// São transitórios: falhas de serialização (40001), deadlocks (40P01) e quedas de conexão (classe 08,
+// 57P01 e erros de rede); no MySQL, deadlocks (1213) e esperas por lock expiradas (1205)
+// Cancelamentos de contexto nunca são repetidos
+func TransientReason(err error) (string, bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "", false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001":
+			return RetryReasonSerialization, true
+		case pgErr.Code == "40P01":
+			return RetryReasonDeadlock, true
+		case strings.HasPrefix(pgErr.Code, "08"), pgErr.Code == "57P01":
+			return RetryReasonConnection, true
+		}
+		return "", false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1213:
+			return RetryReasonDeadlock, true
+		case 1205:
+			return RetryReasonLockTimeout, true
+		}
+		return "", false
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.As(err, &netErr) ||
+		pgconn.SafeToRetry(err) {
+		return RetryReasonConnection, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxStatementLength limita o SQL guardado de cada comando
+const maxStatementLength = 1000
+
+// Statement é um comando executado no banco, com os parâmetros descritos sem os valores de texto
+type Statement struct {
+	SQL      string
+	Args     []string
+	Duration time.Duration
+}
+
+// StatementLog acumula os comandos executados com um contexto, para detalhar as consultas lentas
+type StatementLog struct {
+	mu         sync.Mutex
+	statements []Statement
+}
+
+type statementLogKey struct{}
+
+// WithStatementLog retorna um contexto cujos comandos no PostgreSQL são registrados no log retornado
+func WithStatementLog(ctx context.Context) (context.Context, *StatementLog) {
+	log := &StatementLog{}
+	return context.WithValue(ctx, statementLogKey{}, log), log
+}
+
+// Statements retorna os comandos registrados, na ordem em que terminaram
+func (l *StatementLog) Statements() []Statement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Statement(nil), l.statements...)
+}
+
+func (l *StatementLog) add(statement Statement) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.statements = append(l.statements, statement)
+}
+
+// StatementTracer é o pgx.QueryTracer que registra os comandos no StatementLog do contexto
+// Sem StatementLog no contexto não faz nada
+type StatementTracer struct{}
+
+type statementStartKey struct{}
+
+// statementStart guarda o comando entre o início e o fim da execução
+type statementStart struct {
+	sql   string
+	args  []string
+	start time.Time
+}
+
+// TraceQueryStart implementa pgx.QueryTracer
+func (StatementTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if _, ok := ctx.Value(statementLogKey{}).(*StatementLog); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, statementStartKey{}, &statementStart{
+		sql:   compactSQL(data.SQL),
+		args:  describeArgs(data.Args),
+		start: time.Now(),
+	})
+}
+
+// TraceQueryEnd implementa pgx.QueryTracer
+func (StatementTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	log, ok := ctx.Value(statementLogKey{}).(*StatementLog)
+	if !ok {
+		return
+	}
+	if start, ok := ctx.Value(statementStartKey{}).(*statementStart); ok {
+		log.add(Statement{SQL: start.sql, Args: start.args, Duration: time.Since(start.start)})
+	}
+}
+
+// compactSQL junta os espaços e quebras de linha do comando e o limita a maxStatementLength
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxStatementLength {
+		sql = sql[:maxStatementLength] + "..."
+	}
+	return sql
+}
+
+// describeArgs descreve os parâmetros sem expor dados: textos e bytes aparecem só pelo tamanho,
+// números, booleanos e datas pelo valor
+func describeArgs(args []any) []string {
+	described := make([]string, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case nil:
+			described[i] = "NULL"
+		case string:
+			described[i] = fmt.Sprintf("string(%d)", len(value))
+		case []byte:
+			described[i] = fmt.Sprintf("bytes(%d)", len(value))
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			described[i] = fmt.Sprint(value)
+		case time.Time:
+			described[i] = value.UTC().Format(time.RFC3339)
+		default:
+			described[i] = fmt.Sprintf("%T", value)
+		}
+	}
+	return described
+}
@@ -3,22 +3,27 @@ package domain
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"slices"
 	"sync"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 // Account representa uma conta com suas informações e saldo protegido para acessos concorrentes
+// Role é o papel do API Key da conta e Scopes restringe as rotas que ele pode chamar
+// TenantID é a organização da conta nas implantações white-label; vazio para as contas sem organização
 type Account struct {
 	ID        string
 	Name      string
 	Email     string
 	APIKey    string
+	Role      Role
+	Scopes    []Permission
+	TenantID  string
 	Balance   float64
 	mu        sync.RWMutex
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
 }
 
 // generateAPIKey gera uma chave API segura usando crypto/rand
@@ -32,11 +37,13 @@ func generateAPIKey() string {
 // NewAccount cria uma conta com ID único, API Key segura e timestamps iniciais
 func NewAccount(name, email string) *Account {
 	account := &Account{
-		ID:        uuid.New().String(),
+		ID:        NewID(),
 		Name:      name,
 		Email:     email,
 		Balance:   0,
 		APIKey:    generateAPIKey(),
+		Role:      RoleMerchant,
+		Scopes:    slices.Clone(APIKeyScopes),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -44,6 +51,11 @@ func NewAccount(name, email string) *Account {
 	return account
 }
 
+// TestMode informa se a conta está em modo de teste, com as faturas decididas pelo SandboxProcessor
+func (a *Account) TestMode() bool {
+	return IsTestAPIKey(a.APIKey)
+}
+
 // AddBalance modifica o saldo da conta de forma thread-safe
 func (a *Account) AddBalance(amount float64) {
 	// Mutex garante exclusão mútua no acesso ao saldo
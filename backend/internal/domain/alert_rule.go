@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// AlertMetric é a medida avaliada por uma regra de alerta
+type AlertMetric string
+
+const (
+	// AlertDeclineRate é a fração das faturas recusadas entre as aprovadas e recusadas na janela, de 0 a 1
+	AlertDeclineRate AlertMetric = "decline_rate"
+	// AlertWebhookFailures são as entregas de webhooks que falharam por minuto, na média da janela
+	AlertWebhookFailures AlertMetric = "webhook_failures"
+	// AlertConsumerLag são as mensagens de resultado do antifraude ainda não lidas pelo consumidor do Kafka,
+	// no momento da avaliação; não usa janela
+	AlertConsumerLag AlertMetric = "consumer_lag"
+)
+
+// Limites da janela das regras de alerta
+const (
+	MinAlertWindow = time.Minute
+	MaxAlertWindow = 24 * time.Hour
+)
+
+// maxAlertRuleName limita o nome das regras, que também vai para os rótulos das métricas
+const maxAlertRuleName = 100
+
+// AlertRule dispara um alerta quando a medida passa de Threshold
+// O alerta é enviado uma vez ao disparar e outra quando a medida volta ao limite
+type AlertRule struct {
+	ID        string
+	Name      string
+	Metric    AlertMetric
+	Threshold float64
+	Window    time.Duration
+	// Enabled desligado mantém a regra cadastrada sem avaliá-la
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewAlertRule cria e valida uma regra de alerta
+// Retorna ErrInvalidAlertRule se o nome, a medida, o limite ou a janela forem inválidos
+func NewAlertRule(name string, metric AlertMetric, threshold float64, window time.Duration, enabled bool) (*AlertRule, error) {
+	now := time.Now()
+	rule := &AlertRule{ID: NewID(), CreatedAt: now}
+	if err := rule.Change(name, metric, threshold, window, enabled); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Change substitui a definição da regra, mantendo o ID e a data de criação
+// Retorna ErrInvalidAlertRule, sem alterar a regra, se algum valor for inválido
+func (r *AlertRule) Change(name string, metric AlertMetric, threshold float64, window time.Duration, enabled bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAlertRuleName {
+		return ErrInvalidAlertRule
+	}
+	switch metric {
+	case AlertDeclineRate:
+		if threshold > 1 {
+			return ErrInvalidAlertRule
+		}
+	case AlertWebhookFailures, AlertConsumerLag:
+	default:
+		return ErrInvalidAlertRule
+	}
+	if threshold < 0 || window < MinAlertWindow || window > MaxAlertWindow {
+		return ErrInvalidAlertRule
+	}
+
+	r.Name = name
+	r.Metric = metric
+	r.Threshold = threshold
+	r.Window = window
+	r.Enabled = enabled
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *AlertRule) error
+	// Update retorna ErrAlertRuleNotFound quando a regra não existe
+	Update(ctx context.Context, rule *AlertRule) error
+	// FindByID retorna ErrAlertRuleNotFound quando a regra não existe
+	FindByID(ctx context.Context, id string) (*AlertRule, error)
+	// List retorna todas as regras, das mais antigas para as mais novas
+	List(ctx context.Context) ([]*AlertRule, error)
+	// Delete retorna ErrAlertRuleNotFound quando a regra não existe
+	Delete(ctx context.Context, id string) error
+}
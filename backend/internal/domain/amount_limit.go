@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limites da faixa de valor violados por uma fatura
+const (
+	AmountBoundMin = "min"
+	AmountBoundMax = "max"
+)
+
+// AmountRange é o valor mínimo e máximo aceito em cada fatura; zero desativa o limite
+type AmountRange struct {
+	Min float64
+	Max float64
+}
+
+// Validate confere a faixa
+// Retorna ErrInvalidAmountLimits se algum limite for negativo ou o máximo for menor que o mínimo
+func (r AmountRange) Validate() error {
+	if r.Min < 0 || r.Max < 0 || (r.Max > 0 && r.Max < r.Min) {
+		return ErrInvalidAmountLimits
+	}
+	return nil
+}
+
+// Narrow retorna a interseção das faixas: o maior dos mínimos e o menor dos máximos configurados
+func (r AmountRange) Narrow(other AmountRange) AmountRange {
+	narrowed := r
+	if other.Min > narrowed.Min {
+		narrowed.Min = other.Min
+	}
+	if other.Max > 0 && (narrowed.Max == 0 || other.Max < narrowed.Max) {
+		narrowed.Max = other.Max
+	}
+	return narrowed
+}
+
+// Check confere se amount está dentro da faixa, comparando em centavos
+// Retorna *AmountLimitError com a faixa permitida se amount ficar abaixo do mínimo ou acima do máximo
+func (r AmountRange) Check(amount float64) error {
+	cents := toCents(amount)
+	if r.Min > 0 && cents < toCents(r.Min) {
+		return &AmountLimitError{Bound: AmountBoundMin, Min: r.Min, Max: r.Max}
+	}
+	if r.Max > 0 && cents > toCents(r.Max) {
+		return &AmountLimitError{Bound: AmountBoundMax, Min: r.Min, Max: r.Max}
+	}
+	return nil
+}
+
+// AmountLimits são as faixas de valor padrão do gateway: Default vale para todas as faturas e PaymentTypes estreita
+// a faixa das faturas de cada meio de pagamento
+type AmountLimits struct {
+	Default      AmountRange
+	PaymentTypes map[string]AmountRange
+}
+
+// Range retorna a faixa de uma fatura: a da conta, ou Default se account for nil, estreitada pela do meio de
+// pagamento
+func (l AmountLimits) Range(account *AmountRange, paymentType string) AmountRange {
+	limits := l.Default
+	if account != nil {
+		limits = *account
+	}
+	if method, ok := l.PaymentTypes[paymentType]; ok {
+		limits = limits.Narrow(method)
+	}
+	return limits
+}
+
+// AccountAmountLimits substitui a faixa padrão do gateway para a conta; os limites por meio de pagamento continuam
+// valendo sobre ela
+type AccountAmountLimits struct {
+	AccountID string
+	Range     AmountRange
+	UpdatedAt time.Time
+}
+
+// NewAccountAmountLimits valida a faixa da conta
+// Retorna ErrInvalidAmountLimits se a faixa for inválida
+func NewAccountAmountLimits(accountID string, limits AmountRange) (*AccountAmountLimits, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	limits = AmountRange{Min: fromCents(toCents(limits.Min)), Max: fromCents(toCents(limits.Max))}
+	return &AccountAmountLimits{AccountID: accountID, Range: limits, UpdatedAt: time.Now()}, nil
+}
+
+// AmountLimitError detalha a faixa de valor que a fatura violou: Bound é o limite violado e Max zero indica que não
+// há máximo
+// Corresponde a ErrInvalidAmount em errors.Is
+type AmountLimitError struct {
+	Bound string
+	Min   float64
+	Max   float64
+}
+
+func (e *AmountLimitError) Error() string {
+	if e.Max > 0 {
+		return fmt.Sprintf("%s: must be between %.2f and %.2f", ErrInvalidAmount, e.Min, e.Max)
+	}
+	return fmt.Sprintf("%s: must be at least %.2f", ErrInvalidAmount, e.Min)
+}
+
+func (e *AmountLimitError) Unwrap() error {
+	return ErrInvalidAmount
+}
+
+// AccountAmountLimitsRepository define a persistência das faixas de valor próprias das contas
+type AccountAmountLimitsRepository interface {
+	Save(ctx context.Context, limits *AccountAmountLimits) error
+	// FindByAccountID retorna ErrAmountLimitsNotFound quando a conta usa a faixa padrão
+	FindByAccountID(ctx context.Context, accountID string) (*AccountAmountLimits, error)
+	// Delete volta a conta para a faixa padrão; não é erro se ela já usava a padrão
+	Delete(ctx context.Context, accountID string) error
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// AnomalyKind é o tipo de mudança brusca detectada no comportamento de uma conta
+type AnomalyKind string
+
+const (
+	// AnomalyVolumeSpike é um volume de faturas muito acima da média da conta
+	AnomalyVolumeSpike AnomalyKind = "volume_spike"
+	// AnomalyDeclineSpike é uma taxa de faturas recusadas muito acima da usual da conta
+	AnomalyDeclineSpike AnomalyKind = "decline_spike"
+	// AnomalyNewCountry é uma autenticação bem-sucedida de um país nunca visto na conta
+	AnomalyNewCountry AnomalyKind = "new_country"
+)
+
+// AccountAnomaly é uma mudança brusca no comportamento da conta entre WindowStart e WindowEnd
+// Observed é o valor da janela e Baseline o esperado pelo histórico: quantidade de faturas no volume
+// e taxa de recusas nas recusas; em new_country, Country é o país novo
+type AccountAnomaly struct {
+	AccountID   string
+	Kind        AnomalyKind
+	Observed    float64
+	Baseline    float64
+	Country     string
+	WindowStart time.Time
+	WindowEnd   time.Time
+}
+
+// AccountActivity resume as faturas criadas por uma conta em um intervalo
+type AccountActivity struct {
+	AccountID string
+	Invoices  int
+	// Declined são as faturas do intervalo com status rejected
+	Declined int
+}
+
+// AccountIP é um IP de onde a conta se autenticou com sucesso
+type AccountIP struct {
+	AccountID string
+	IP        string
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// AnticipationActorPrefix identifica nas retenções liberadas a antecipação que as liberou, seguido do ID dela
+const AnticipationActorPrefix = "anticipation:"
+
+// Anticipation é a liberação antecipada, a pedido da conta, dos valores que aguardavam a data de repasse
+// Amount é o valor antecipado e Fee a taxa descontada dele; a conta recebe Net no saldo disponível
+type Anticipation struct {
+	ID          string
+	AccountID   string
+	Amount      float64
+	Fee         float64
+	Holds       int
+	RequestedBy string
+	CreatedAt   time.Time
+}
+
+// NewAnticipation cria a antecipação da conta pedida por requestedBy, ainda sem valores
+func NewAnticipation(accountID, requestedBy string) *Anticipation {
+	return &Anticipation{ID: NewID(), AccountID: accountID, RequestedBy: requestedBy, CreatedAt: time.Now()}
+}
+
+// Add soma à antecipação o valor da retenção e a taxa dela
+func (a *Anticipation) Add(hold *EscrowHold, fee float64) {
+	a.Amount = fromCents(toCents(a.Amount) + toCents(hold.Amount))
+	a.Fee = fromCents(toCents(a.Fee) + toCents(fee))
+	a.Holds++
+}
+
+// Net é o valor creditado no saldo: o antecipado menos a taxa
+func (a *Anticipation) Net() float64 {
+	return fromCents(toCents(a.Amount) - toCents(a.Fee))
+}
+
+// AnticipationFee é a taxa para antecipar em now o valor da retenção: monthlyRate por cento ao mês, proporcional aos
+// dias que faltam para a data de repasse, contando o dia começado, e arredondada em centavos
+func AnticipationFee(hold *EscrowHold, now time.Time, monthlyRate float64) float64 {
+	days := math.Ceil(hold.ReleaseAt.Sub(now).Hours() / 24)
+	if days <= 0 {
+		return 0
+	}
+	return fromCents(toCents(hold.Amount * monthlyRate / 100 * days / 30))
+}
+
+// AnticipationRepository define a persistência das antecipações
+type AnticipationRepository interface {
+	// Create grava a antecipação e o movimento que libera as retenções e credita o saldo na mesma transação
+	// Retorna ErrEscrowAlreadyReleased se uma das retenções já tiver sido liberada, sem gravar nada
+	Create(ctx context.Context, anticipation *Anticipation, posting *Posting) error
+	// ListByAccountID retorna até limit antecipações da conta, das mais recentes para as mais antigas
+	ListByAccountID(ctx context.Context, accountID string, limit int) ([]*Anticipation, error)
+}
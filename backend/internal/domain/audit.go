@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditActionInsert AuditAction = "insert"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionDownload registra o download de um arquivo gerado, sem alteração da entidade
+	AuditActionDownload AuditAction = "download"
+)
+
+// AuditEntry registra uma mutação com o estado anterior e o novo da entidade
+type AuditEntry struct {
+	ID        int64
+	Entity    string
+	EntityID  string
+	Action    AuditAction
+	OldValue  json.RawMessage
+	NewValue  json.RawMessage
+	Actor     string
+	RequestID string
+	CreatedAt time.Time
+}
+
+// AuditFilter reúne os critérios opcionais para consulta da trilha de auditoria
+// Campos com valor zero não restringem a busca
+type AuditFilter struct {
+	Entity    string
+	EntityID  string
+	Actor     string
+	RequestID string
+	// CreatedFrom e CreatedTo restringem as entradas ao intervalo de criação, inclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+	// After restringe a busca às entradas posteriores ao cursor na ordenação da listagem
+	After *Cursor
+}
+
+// Validate verifica se o intervalo de datas é consistente
+func (f AuditFilter) Validate() error {
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && f.CreatedFrom.After(f.CreatedTo) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuthMethod identifica a credencial usada em uma tentativa de autenticação
+type AuthMethod string
+
+const (
+	AuthMethodAPIKey      AuthMethod = "api_key"
+	AuthMethodSignature   AuthMethod = "signature"
+	AuthMethodJWT         AuthMethod = "jwt"
+	AuthMethodCertificate AuthMethod = "certificate"
+	AuthMethodPassword    AuthMethod = "password"
+	AuthMethodOIDC        AuthMethod = "oidc"
+	AuthMethodAdminKey    AuthMethod = "admin_key"
+	AuthMethodTOTP        AuthMethod = "totp"
+)
+
+// apiKeyPrefixLength é quantos caracteres do API Key identificam a chave nos eventos
+const apiKeyPrefixLength = 8
+
+// APIKeyPrefix retorna o início do API Key, suficiente para identificá-lo sem expor a chave
+func APIKeyPrefix(apiKey string) string {
+	if len(apiKey) <= apiKeyPrefixLength {
+		return apiKey
+	}
+	return apiKey[:apiKeyPrefixLength]
+}
+
+// AuthEvent registra uma tentativa de autenticação, bem-sucedida ou não
+// KeyID identifica a credencial: o prefixo do API Key, a conta nas assinaturas, o usuário nos logins
+// ou o nome do certificado; AccountID fica vazio quando a credencial não levou a nenhuma conta
+type AuthEvent struct {
+	ID        string
+	AccountID string
+	KeyID     string
+	Method    AuthMethod
+	Success   bool
+	Reason    string
+	IP        string
+	UserAgent string
+	Route     string
+	CreatedAt time.Time
+}
+
+// AuthEventFilter reúne os critérios da consulta dos eventos de autenticação de uma conta
+type AuthEventFilter struct {
+	AccountID string
+	// Success restringe a sucessos ou falhas; nil retorna ambos
+	Success *bool
+	Limit   int
+	After   *Cursor
+}
+
+type AuthEventRepository interface {
+	Save(ctx context.Context, event *AuthEvent) error
+	FindByFilter(ctx context.Context, filter AuthEventFilter) ([]*AuthEvent, error)
+	// CountFailedIPs conta os IPs distintos com falhas de autenticação da credencial desde o instante informado
+	CountFailedIPs(ctx context.Context, keyID string, since time.Time) (int, error)
+	// FindAccountIPs retorna os pares distintos de conta e IP com autenticações bem-sucedidas em [from, to)
+	FindAccountIPs(ctx context.Context, from, to time.Time) ([]AccountIP, error)
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// BlocklistType é o dado comparado por uma entrada da lista de bloqueio
+type BlocklistType string
+
+const (
+	// BlocklistCard é o número do cartão, guardado apenas como impressão digital
+	BlocklistCard BlocklistType = "card"
+	// BlocklistEmail é o e-mail do pagador
+	BlocklistEmail BlocklistType = "email"
+	// BlocklistDocument é o documento do pagador, como CPF ou CNPJ
+	BlocklistDocument BlocklistType = "document"
+	// BlocklistIP é o IP de origem da requisição
+	BlocklistIP BlocklistType = "ip"
+)
+
+// BlocklistAction é o que acontece com as faturas que batem com a entrada
+type BlocklistAction string
+
+const (
+	// BlocklistBlock recusa a criação da fatura
+	BlocklistBlock BlocklistAction = "block"
+	// BlocklistAllow libera o valor para a conta mesmo que ele esteja bloqueado na lista global
+	BlocklistAllow BlocklistAction = "allow"
+)
+
+// maxBlocklistReason limita o motivo registrado na entrada
+const maxBlocklistReason = 255
+
+// BlocklistEntry bloqueia ou libera um valor na lista global (AccountID vazio) ou na lista de uma conta
+// O valor só é guardado como ValueHash, o HMAC do tipo com o valor normalizado; Hint é o valor mascarado para exibição
+type BlocklistEntry struct {
+	ID        string
+	AccountID string
+	Type      BlocklistType
+	ValueHash string
+	Hint      string
+	Action    BlocklistAction
+	Reason    string
+	// ExpiresAt nil mantém a entrada até ela ser removida
+	ExpiresAt *time.Time
+	// Hits e LastHitAt registram as faturas que bateram com a entrada
+	Hits      int64
+	LastHitAt *time.Time
+	CreatedAt time.Time
+}
+
+// NewBlocklistEntry valida e cria uma entrada com o valor já normalizado e transformado em hash
+// A ação padrão é bloquear; liberar só faz sentido na lista de uma conta, sobre a global
+// Retorna ErrInvalidBlocklistEntry se a ação, o motivo ou a expiração forem inválidos
+func NewBlocklistEntry(accountID string, entryType BlocklistType, valueHash, hint string, action BlocklistAction, reason string, expiresAt *time.Time) (*BlocklistEntry, error) {
+	if action == "" {
+		action = BlocklistBlock
+	}
+	if action != BlocklistBlock && (action != BlocklistAllow || accountID == "") {
+		return nil, ErrInvalidBlocklistEntry
+	}
+
+	now := time.Now()
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxBlocklistReason || (expiresAt != nil && !expiresAt.After(now)) {
+		return nil, ErrInvalidBlocklistEntry
+	}
+
+	return &BlocklistEntry{
+		ID:        NewID(),
+		AccountID: accountID,
+		Type:      entryType,
+		ValueHash: valueHash,
+		Hint:      hint,
+		Action:    action,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}, nil
+}
+
+// Active indica se a entrada ainda vale no instante informado
+func (e *BlocklistEntry) Active(now time.Time) bool {
+	return e.ExpiresAt == nil || now.Before(*e.ExpiresAt)
+}
+
+// NormalizeBlocklistValue padroniza o valor para que grafias diferentes do mesmo dado batam com a mesma entrada
+// Cartões e documentos ficam só com os dígitos e letras, e-mails em minúsculas e IPs na forma canônica
+// Retorna ErrInvalidBlocklistEntry se o tipo for desconhecido ou o valor não tiver o formato do tipo
+func NormalizeBlocklistValue(entryType BlocklistType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch entryType {
+	case BlocklistCard:
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(value)
+		if len(digits) < 12 || len(digits) > 19 || strings.Trim(digits, "0123456789") != "" {
+			return "", ErrInvalidBlocklistEntry
+		}
+		return digits, nil
+	case BlocklistEmail:
+		email := strings.ToLower(value)
+		local, host, ok := strings.Cut(email, "@")
+		if !ok || local == "" || host == "" || strings.ContainsAny(email, " ") {
+			return "", ErrInvalidBlocklistEntry
+		}
+		return email, nil
+	case BlocklistDocument:
+		var document strings.Builder
+		for _, r := range strings.ToUpper(value) {
+			if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+				document.WriteRune(r)
+			}
+		}
+		if document.Len() < 5 || document.Len() > 32 {
+			return "", ErrInvalidBlocklistEntry
+		}
+		return document.String(), nil
+	case BlocklistIP:
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return "", ErrInvalidBlocklistEntry
+		}
+		return addr.Unmap().String(), nil
+	}
+	return "", ErrInvalidBlocklistEntry
+}
+
+// MaskBlocklistValue retorna o valor normalizado mascarado, exibido no lugar dele nas listagens
+// IPs aparecem completos
+func MaskBlocklistValue(entryType BlocklistType, normalized string) string {
+	switch entryType {
+	case BlocklistCard:
+		return "**** " + normalized[len(normalized)-4:]
+	case BlocklistEmail:
+		local, host, _ := strings.Cut(normalized, "@")
+		return local[:1] + "***@" + host
+	case BlocklistDocument:
+		return "***" + normalized[len(normalized)-3:]
+	}
+	return normalized
+}
+
+// ResolveBlocklist escolhe, entre as entradas que bateram com os valores de uma fatura, as que decidem
+// Para cada valor a entrada da conta prevalece sobre a global, de modo que a conta pode liberar um valor
+// bloqueado globalmente; entradas expiradas são ignoradas
+// Retorna a entrada de bloqueio que recusa a fatura, ou nil, e todas as entradas aplicadas, para registrar os acertos
+func ResolveBlocklist(entries []*BlocklistEntry, accountID string, now time.Time) (*BlocklistEntry, []*BlocklistEntry) {
+	byValue := make(map[string]*BlocklistEntry)
+	for _, entry := range entries {
+		if !entry.Active(now) || (entry.AccountID != "" && entry.AccountID != accountID) {
+			continue
+		}
+		current, ok := byValue[entry.ValueHash]
+		if !ok || (current.AccountID == "" && entry.AccountID != "") {
+			byValue[entry.ValueHash] = entry
+		}
+	}
+
+	var blocked *BlocklistEntry
+	applied := make([]*BlocklistEntry, 0, len(byValue))
+	for _, entry := range byValue {
+		applied = append(applied, entry)
+		if entry.Action == BlocklistBlock && blocked == nil {
+			blocked = entry
+		}
+	}
+	return blocked, applied
+}
+
+// BlocklistRepository define a persistência das listas de bloqueio
+type BlocklistRepository interface {
+	// Create retorna ErrBlocklistEntryExists quando a lista já tem o mesmo valor
+	Create(ctx context.Context, entry *BlocklistEntry) error
+	// FindByID retorna ErrBlocklistEntryNotFound quando a entrada não existe
+	FindByID(ctx context.Context, id string) (*BlocklistEntry, error)
+	// List retorna as entradas da conta, ou as globais com accountID vazio, das mais novas para as mais antigas
+	List(ctx context.Context, accountID string) ([]*BlocklistEntry, error)
+	// Match busca as entradas globais e da conta com algum dos hashes informados, inclusive as expiradas
+	Match(ctx context.Context, accountID string, valueHashes []string) ([]*BlocklistEntry, error)
+	// RecordHits soma um acerto às entradas e marca o instante do último
+	RecordHits(ctx context.Context, ids []string, at time.Time) error
+	// Delete retorna ErrBlocklistEntryNotFound quando a entrada não existe
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chaves dos metadados da fatura com o cupom aplicado na criação e o valor descontado
+const (
+	CouponCodeMetadataKey     = "coupon_code"
+	DiscountAmountMetadataKey = "discount_amount"
+)
+
+// couponCodePattern são os códigos aceitos, já em maiúsculas
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// NormalizeCouponCode padroniza o código do cupom, que não diferencia maiúsculas de minúsculas
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CouponType é a forma de cálculo do desconto
+type CouponType string
+
+const (
+	// CouponPercentage desconta Value por cento do valor da fatura
+	CouponPercentage CouponType = "percentage"
+	// CouponFixed desconta Value do valor da fatura
+	CouponFixed CouponType = "fixed"
+)
+
+// Coupon é um cupom de desconto criado pela conta e informado pelo código na criação das faturas dela
+// MaxRedemptions 0 não limita os usos; ValidFrom e ValidUntil, opcionais, delimitam a validade
+type Coupon struct {
+	ID             string
+	AccountID      string
+	Code           string
+	Type           CouponType
+	Value          float64
+	MaxRedemptions int
+	Redemptions    int
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewCoupon valida o cupom: percentuais de 0 a 100, valores fixos positivos e a validade em ordem
+// Retorna ErrInvalidCoupon se o código, o tipo, o valor, o limite de usos ou a validade forem inválidos
+func NewCoupon(accountID, code string, couponType CouponType, value float64, maxRedemptions int, validFrom, validUntil *time.Time) (*Coupon, error) {
+	code = NormalizeCouponCode(code)
+	if !couponCodePattern.MatchString(code) || maxRedemptions < 0 || toCents(value) <= 0 {
+		return nil, ErrInvalidCoupon
+	}
+	switch couponType {
+	case CouponPercentage:
+		if value > 100 {
+			return nil, ErrInvalidCoupon
+		}
+	case CouponFixed:
+	default:
+		return nil, ErrInvalidCoupon
+	}
+	if validFrom != nil && validUntil != nil && !validUntil.After(*validFrom) {
+		return nil, ErrInvalidCoupon
+	}
+
+	now := time.Now()
+	return &Coupon{
+		ID:             NewID(),
+		AccountID:      accountID,
+		Code:           code,
+		Type:           couponType,
+		Value:          value,
+		MaxRedemptions: maxRedemptions,
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Discount calcula o desconto do cupom sobre amount em now
+// Retorna ErrCouponUnavailable se o cupom estiver desativado, fora da validade, sem usos restantes ou se o desconto
+// cobrir todo o valor, já que a fatura precisa ter valor positivo
+func (c *Coupon) Discount(amount float64, now time.Time) (float64, error) {
+	if !c.Active || (c.ValidFrom != nil && now.Before(*c.ValidFrom)) || (c.ValidUntil != nil && !now.Before(*c.ValidUntil)) {
+		return 0, ErrCouponUnavailable
+	}
+	if c.MaxRedemptions > 0 && c.Redemptions >= c.MaxRedemptions {
+		return 0, ErrCouponUnavailable
+	}
+
+	discount := toCents(c.Value)
+	if c.Type == CouponPercentage {
+		discount = toCents(amount * c.Value / 100)
+	}
+	if discount >= toCents(amount) {
+		return 0, ErrCouponUnavailable
+	}
+	return fromCents(discount), nil
+}
+
+// ApplyDiscount desconta do valor da fatura o desconto do cupom, registrado nos metadados
+func (i *Invoice) ApplyDiscount(code string, discount float64) {
+	if i.Metadata == nil {
+		i.Metadata = map[string]string{}
+	}
+	i.Metadata[CouponCodeMetadataKey] = code
+	i.Metadata[DiscountAmountMetadataKey] = strconv.FormatFloat(discount, 'f', 2, 64)
+	i.Amount = fromCents(toCents(i.Amount) - toCents(discount))
+}
+
+// ClearDiscount remove o cupom enviado nos metadados da requisição; só vale o aplicado por ApplyDiscount
+func (i *Invoice) ClearDiscount() {
+	delete(i.Metadata, CouponCodeMetadataKey)
+	delete(i.Metadata, DiscountAmountMetadataKey)
+}
+
+// Discount retorna o cupom aplicado na criação da fatura e o valor descontado; o código fica vazio sem cupom
+func (i *Invoice) Discount() (string, float64) {
+	code := i.Metadata[CouponCodeMetadataKey]
+	if code == "" {
+		return "", 0
+	}
+	discount, err := strconv.ParseFloat(i.Metadata[DiscountAmountMetadataKey], 64)
+	if err != nil || discount < 0 {
+		return code, 0
+	}
+	return code, discount
+}
+
+// CouponRedemption é um uso do cupom por uma fatura
+type CouponRedemption struct {
+	ID        string
+	CouponID  string
+	AccountID string
+	InvoiceID string
+	Discount  float64
+	CreatedAt time.Time
+}
+
+// NewCouponRedemption registra o uso do cupom pela fatura com o desconto aplicado
+func NewCouponRedemption(coupon *Coupon, invoice *Invoice, discount float64) *CouponRedemption {
+	return &CouponRedemption{
+		ID:        NewID(),
+		CouponID:  coupon.ID,
+		AccountID: coupon.AccountID,
+		InvoiceID: invoice.ID,
+		Discount:  discount,
+		CreatedAt: time.Now(),
+	}
+}
+
+// CouponRepository define a persistência dos cupons e dos seus usos
+type CouponRepository interface {
+	// Create retorna ErrCouponExists se a conta já tiver um cupom com o mesmo código
+	Create(ctx context.Context, coupon *Coupon) error
+	// FindByID retorna ErrCouponNotFound quando o cupom não existe
+	FindByID(ctx context.Context, id string) (*Coupon, error)
+	// FindByCode busca o cupom da conta pelo código normalizado; retorna ErrCouponNotFound quando ele não existe
+	FindByCode(ctx context.Context, accountID, code string) (*Coupon, error)
+	// List retorna até limit cupons da conta, dos mais novos para os mais antigos
+	List(ctx context.Context, accountID string, limit int) ([]*Coupon, error)
+	// Deactivate desativa o cupom, que deixa de ser aceito nas faturas
+	Deactivate(ctx context.Context, id string, updatedAt time.Time) error
+	// Redeem grava o uso e soma um aos usos do cupom, se ele ainda estiver ativo e com usos restantes
+	// Retorna ErrCouponUnavailable quando o último uso foi consumido ou o cupom desativado antes
+	Redeem(ctx context.Context, redemption *CouponRedemption) error
+	// SumDiscounts soma os descontos dos usos de cupons da conta de from até to
+	SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (float64, error)
+}
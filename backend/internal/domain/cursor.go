@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Cursor marca a posição do último item de uma página nas listagens ordenadas por (created_at DESC, id DESC)
+// A próxima página começa no primeiro item estritamente anterior a essa posição
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
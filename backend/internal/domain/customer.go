@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// CustomerIDMetadataKey guarda nos metadados das faturas o cliente da conta que as pagou
+const CustomerIDMetadataKey = "customer_id"
+
+// Limites dos clientes
+const (
+	MaxCustomerNameLength     = 255
+	MaxCustomerDocumentLength = 32
+	// MaxCustomerPaymentMethods limita os cartões guardados de cada cliente
+	MaxCustomerPaymentMethods = 10
+)
+
+// Customer é um pagador da conta, com os dados usados nas compras seguintes
+// Nome, e-mail e documento são dados pessoais e ficam cifrados nos repositórios
+type Customer struct {
+	ID        string
+	AccountID string
+	Name      string
+	Email     string
+	Document  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewCustomer cria o cliente da conta; e-mail e documento são opcionais
+// Retorna ErrInvalidCustomer se o nome estiver vazio ou for longo demais, o e-mail for inválido ou o documento
+// passar de MaxCustomerDocumentLength
+func NewCustomer(accountID, name, email, document string) (*Customer, error) {
+	name, email, document = strings.TrimSpace(name), strings.TrimSpace(email), strings.TrimSpace(document)
+	if name == "" || len(name) > MaxCustomerNameLength || len(document) > MaxCustomerDocumentLength {
+		return nil, ErrInvalidCustomer
+	}
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email {
+			return nil, ErrInvalidCustomer
+		}
+	}
+
+	now := time.Now()
+	return &Customer{
+		ID:        NewID(),
+		AccountID: accountID,
+		Name:      name,
+		Email:     email,
+		Document:  document,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// CustomerPaymentMethod é um cartão do cliente guardado no cofre, cobrado pelo token nas compras seguintes
+type CustomerPaymentMethod struct {
+	CustomerID     string
+	CardToken      string
+	CardBrand      string
+	CardLastDigits string
+	CreatedAt      time.Time
+}
+
+// NewCustomerPaymentMethod liga ao cliente o cartão já guardado no cofre
+func NewCustomerPaymentMethod(customer *Customer, card PaymentCard) *CustomerPaymentMethod {
+	return &CustomerPaymentMethod{
+		CustomerID:     customer.ID,
+		CardToken:      card.Token,
+		CardBrand:      card.Brand,
+		CardLastDigits: card.LastDigits,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// SetCustomer registra nos metadados da fatura o cliente que a pagou
+func (i *Invoice) SetCustomer(customer *Customer) {
+	if i.Metadata == nil {
+		i.Metadata = map[string]string{}
+	}
+	i.Metadata[CustomerIDMetadataKey] = customer.ID
+}
+
+// ClearCustomer remove o cliente enviado nos metadados da requisição; só vale o registrado por SetCustomer
+func (i *Invoice) ClearCustomer() {
+	delete(i.Metadata, CustomerIDMetadataKey)
+}
+
+// CustomerID é o cliente que pagou a fatura, vazio nas faturas sem cliente
+func (i *Invoice) CustomerID() string {
+	return i.Metadata[CustomerIDMetadataKey]
+}
+
+// CustomerRepository define a persistência dos clientes das contas e dos cartões guardados deles
+type CustomerRepository interface {
+	Create(ctx context.Context, customer *Customer) error
+	// FindByID retorna ErrCustomerNotFound se o cliente não existir
+	FindByID(ctx context.Context, id string) (*Customer, error)
+	// List retorna até limit clientes da conta, dos mais novos para os mais antigos
+	List(ctx context.Context, accountID string, limit int) ([]*Customer, error)
+	// Delete apaga o cliente e os cartões ligados a ele; retorna ErrCustomerNotFound se ele não existir
+	Delete(ctx context.Context, id string) error
+	AddPaymentMethod(ctx context.Context, method *CustomerPaymentMethod) error
+	// ListPaymentMethods retorna os cartões do cliente, dos mais antigos para os mais novos
+	ListPaymentMethods(ctx context.Context, customerID string) ([]*CustomerPaymentMethod, error)
+	// RemovePaymentMethod desliga o cartão do cliente; retorna ErrCardNotFound se ele não estiver ligado
+	RemovePaymentMethod(ctx context.Context, customerID, cardToken string) error
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// anonymizationTokenPrefix identifica os valores que substituíram dados pessoais anonimizados
+const anonymizationTokenPrefix = "anon_"
+
+// DataSubjectRequestType é o tipo de pedido de um titular de dados
+type DataSubjectRequestType string
+
+const (
+	// DataSubjectExport exporta os dados pessoais do pagador
+	DataSubjectExport DataSubjectRequestType = "export"
+	// DataSubjectAnonymize substitui os dados pessoais do pagador por um token irreversível
+	DataSubjectAnonymize DataSubjectRequestType = "anonymize"
+)
+
+// DataSubjectRequest registra o atendimento de um pedido de titular, sem os dados pessoais do titular
+// Token é o valor que substituiu os dados na anonimização, para localizar as faturas afetadas
+type DataSubjectRequest struct {
+	ID        string
+	AccountID string
+	Type      DataSubjectRequestType
+	Invoices  int
+	Token     string
+	Actor     string
+	RequestID string
+	CreatedAt time.Time
+}
+
+// NewDataSubjectRequest cria o registro do pedido atendido
+func NewDataSubjectRequest(accountID string, requestType DataSubjectRequestType, invoices int, token, actor, requestID string) *DataSubjectRequest {
+	return &DataSubjectRequest{
+		ID:        NewID(),
+		AccountID: accountID,
+		Type:      requestType,
+		Invoices:  invoices,
+		Token:     token,
+		Actor:     actor,
+		RequestID: requestID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NormalizePayerName prepara o nome do pagador para a busca, que não diferencia maiúsculas
+func NormalizePayerName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// NewAnonymizationToken gera um token aleatório, sem relação com o dado substituído
+func NewAnonymizationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return anonymizationTokenPrefix + hex.EncodeToString(b), nil
+}
+
+type DataSubjectRequestRepository interface {
+	Save(ctx context.Context, request *DataSubjectRequest) error
+	// FindByAccountID retorna os pedidos da conta, dos mais recentes para os mais antigos
+	FindByAccountID(ctx context.Context, accountID string) ([]*DataSubjectRequest, error)
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxDisputeEvidenceSize é o tamanho máximo de cada arquivo de evidência, em bytes
+	MaxDisputeEvidenceSize = 10 << 20
+	// MaxDisputeEvidenceFiles é o número máximo de arquivos de evidência por disputa
+	MaxDisputeEvidenceFiles = 20
+)
+
+// DisputeEvidenceContentTypes são os tipos aceitos nas evidências, identificados pelo conteúdo do arquivo
+var DisputeEvidenceContentTypes = []string{"application/pdf", "image/png", "image/jpeg"}
+
+// DisputeStatus é a situação de uma disputa
+type DisputeStatus string
+
+const (
+	// DisputeOpen aguarda as evidências da conta e a decisão do emissor do cartão
+	DisputeOpen DisputeStatus = "open"
+	// DisputeWon foi decidida a favor da conta
+	DisputeWon DisputeStatus = "won"
+	// DisputeLost foi decidida a favor do pagador
+	DisputeLost DisputeStatus = "lost"
+)
+
+// IsValid indica se a situação é conhecida
+func (s DisputeStatus) IsValid() bool {
+	return s == DisputeOpen || s == DisputeWon || s == DisputeLost
+}
+
+// Dispute é a contestação de uma fatura aprovada pelo pagador junto ao emissor do cartão
+// Enquanto está aberta a conta dona da fatura pode anexar evidências para contestá-la
+// Fee é a tarifa de chargeback debitada da conta no registro, pelas tarifas da conta na época
+type Dispute struct {
+	ID         string
+	InvoiceID  string
+	AccountID  string
+	Amount     float64
+	Fee        float64
+	Reason     string
+	Status     DisputeStatus
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// NewDispute abre a disputa do valor total da fatura com o motivo informado pelo emissor e a tarifa de chargeback
+// Retorna ErrInvalidStatus se a fatura não estiver aprovada ou liquidada e ErrInvalidDispute se o motivo for vazio ou
+// longo demais
+func NewDispute(invoice *Invoice, reason string, fee float64) (*Dispute, error) {
+	if !invoice.Status.IsPaid() {
+		return nil, ErrInvalidStatus
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > 255 {
+		return nil, ErrInvalidDispute
+	}
+	return &Dispute{
+		ID:        NewID(),
+		InvoiceID: invoice.ID,
+		AccountID: invoice.AccountID,
+		Amount:    invoice.Amount,
+		Fee:       fromCents(toCents(fee)),
+		Reason:    reason,
+		Status:    DisputeOpen,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Resolve encerra a disputa com o resultado informado
+// Retorna ErrInvalidDisputeResolution se o resultado não for won nem lost e ErrDisputeClosed se ela já foi encerrada
+func (d *Dispute) Resolve(status DisputeStatus) error {
+	if status != DisputeWon && status != DisputeLost {
+		return ErrInvalidDisputeResolution
+	}
+	if d.Status != DisputeOpen {
+		return ErrDisputeClosed
+	}
+	now := time.Now()
+	d.Status = status
+	d.ResolvedAt = &now
+	return nil
+}
+
+// ChargebackActor é quem fica registrado na devolução das retenções da fatura da disputa perdida
+const ChargebackActor = "system:chargeback"
+
+// ChargebackPosting monta o movimento da disputa perdida: as retenções da fatura ainda retidas em held são
+// devolvidas ao pagador e o restante do valor disputado é debitado do saldo da conta dona, mesmo que ele fique
+// negativo, com um lançamento LedgerChargeback
+// Retorna ErrEscrowAlreadyReleased se alguma retenção de held já foi liberada
+func (d *Dispute) ChargebackPosting(held []*EscrowHold) (*Posting, error) {
+	posting := &Posting{Balances: map[string]float64{}}
+	remaining := toCents(d.Amount)
+	for _, hold := range held {
+		if err := hold.Cancel(ChargebackActor); err != nil {
+			return nil, err
+		}
+		posting.Releases = append(posting.Releases, hold)
+		remaining -= toCents(hold.Amount)
+	}
+	if remaining > 0 {
+		entry := NewLedgerEntry(d.AccountID, "", d.InvoiceID, LedgerChargeback, -fromCents(remaining))
+		posting.Entries = append(posting.Entries, entry)
+		posting.Balances[d.AccountID] = entry.Amount
+	}
+	return posting, nil
+}
+
+// DisputeEvidence é um documento anexado pela conta a uma disputa, como um recibo ou o comprovante de entrega
+// ObjectKey é o caminho do arquivo no armazenamento de objetos, que nunca é exposto
+type DisputeEvidence struct {
+	ID          string
+	DisputeID   string
+	AccountID   string
+	ObjectKey   string
+	FileName    string
+	ContentType string
+	Size        int64
+	Description string
+	UploadedBy  string
+	CreatedAt   time.Time
+}
+
+// NewDisputeEvidence cria a evidência da disputa com o caminho disputes/<conta>/<disputa>/<id> no armazenamento
+// Do nome do arquivo fica apenas a última parte, sem os diretórios enviados pelo cliente
+// Retorna ErrDisputeClosed se a disputa já foi encerrada, ErrDisputeEvidenceTooLarge se o arquivo passar de
+// MaxDisputeEvidenceSize, ErrUnsupportedDisputeEvidence se o tipo não estiver em DisputeEvidenceContentTypes e
+// ErrInvalidDisputeEvidence se o arquivo for vazio ou a descrição longa demais
+func NewDisputeEvidence(dispute *Dispute, fileName, contentType string, size int64, description, uploadedBy string) (*DisputeEvidence, error) {
+	if dispute.Status != DisputeOpen {
+		return nil, ErrDisputeClosed
+	}
+	if size <= 0 || len(description) > 255 {
+		return nil, ErrInvalidDisputeEvidence
+	}
+	if size > MaxDisputeEvidenceSize {
+		return nil, ErrDisputeEvidenceTooLarge
+	}
+	if !slices.Contains(DisputeEvidenceContentTypes, contentType) {
+		return nil, ErrUnsupportedDisputeEvidence
+	}
+
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if fileName == "." || fileName == "/" || len(fileName) > 255 {
+		fileName = "evidence"
+	}
+	id := NewID()
+	return &DisputeEvidence{
+		ID:          id,
+		DisputeID:   dispute.ID,
+		AccountID:   dispute.AccountID,
+		ObjectKey:   "disputes/" + dispute.AccountID + "/" + dispute.ID + "/" + id,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Description: strings.TrimSpace(description),
+		UploadedBy:  uploadedBy,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// DisputeRepository define a persistência das disputas e das suas evidências
+type DisputeRepository interface {
+	// Create grava a disputa e, se posting não for nil, o movimento da tarifa na mesma transação
+	// Retorna ErrTransactionAlreadyDisputed se a fatura já tiver disputa
+	Create(ctx context.Context, dispute *Dispute, posting *Posting) error
+	FindByID(ctx context.Context, id string) (*Dispute, error)
+	// List retorna as disputas da conta, ou de todas com accountID vazio, das mais novas para as mais antigas
+	List(ctx context.Context, accountID string, status DisputeStatus, limit int) ([]*Dispute, error)
+	// Resolve grava o resultado apenas se a disputa ainda estiver aberta; senão retorna ErrDisputeClosed
+	// Na disputa perdida, na mesma transação, grava o ChargebackPosting das retenções ainda retidas da fatura e passa
+	// a fatura aprovada para StatusChargedBack; a fatura já toda reembolsada continua reembolsada
+	Resolve(ctx context.Context, dispute *Dispute) error
+	AddEvidence(ctx context.Context, evidence *DisputeEvidence) error
+	// ListEvidence retorna as evidências da disputa na ordem do envio
+	ListEvidence(ctx context.Context, disputeID string) ([]*DisputeEvidence, error)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDisputeChargebackPosting(t *testing.T) {
+	tests := []struct {
+		name        string
+		held        []float64
+		wantDebit   float64
+		wantEntries int
+	}{
+		{"nothing held", nil, -100, 1},
+		{"partly held", []float64{30, 20.01}, -49.99, 1},
+		{"fully held", []float64{60, 40}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispute := &Dispute{AccountID: "a", InvoiceID: "i", Amount: 100}
+			var held []*EscrowHold
+			for _, amount := range tt.held {
+				held = append(held, NewEscrowHold("a", "i", amount, time.Now()))
+			}
+
+			posting, err := dispute.ChargebackPosting(held)
+			if err != nil {
+				t.Fatalf("ChargebackPosting: %v", err)
+			}
+			if got := posting.Balances["a"]; got != tt.wantDebit {
+				t.Errorf("balance delta = %v, want %v", got, tt.wantDebit)
+			}
+			if len(posting.Entries) != tt.wantEntries {
+				t.Fatalf("got %d entries, want %d", len(posting.Entries), tt.wantEntries)
+			}
+			for _, entry := range posting.Entries {
+				if entry.Type != LedgerChargeback || entry.Amount != tt.wantDebit || entry.InvoiceID != "i" {
+					t.Errorf("entry = %+v, want a %q entry of %v on the invoice", entry, LedgerChargeback, tt.wantDebit)
+				}
+			}
+			// As retenções são devolvidas, e não liberadas para o saldo
+			if len(posting.Releases) != len(held) {
+				t.Fatalf("got %d releases, want %d", len(posting.Releases), len(held))
+			}
+			for _, hold := range posting.Releases {
+				if hold.Status != EscrowCancelled || hold.ReleasedBy != ChargebackActor {
+					t.Errorf("hold status = %q by %q, want %q by %q", hold.Status, hold.ReleasedBy, EscrowCancelled, ChargebackActor)
+				}
+			}
+		})
+	}
+
+	// Uma retenção já liberada não pode ser devolvida
+	released := NewEscrowHold("a", "i", 10, time.Now())
+	if err := released.Release("someone"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	dispute := &Dispute{AccountID: "a", InvoiceID: "i", Amount: 100}
+	if _, err := dispute.ChargebackPosting([]*EscrowHold{released}); !errors.Is(err, ErrEscrowAlreadyReleased) {
+		t.Errorf("ChargebackPosting error = %v, want ErrEscrowAlreadyReleased", err)
+	}
+}
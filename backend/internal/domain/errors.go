@@ -11,7 +11,258 @@ var (
 	ErrInvoiceNotFound = errors.New("invoice not found")
 	// ErrUnauthorizedAccess é retornado quando há tentativa de acesso não autorizado a um recurso.
 	ErrUnauthorizedAccess = errors.New("unauthorized access")
+	// ErrAccountDuplicateKey é retornado quando a chave única da conta já existe.
+	ErrAccountDuplicateKey = errors.New("account duplicate key")
+	// ErrAccountAlreadyExists é retornado quando a conta já existe.
+	ErrAccountAlreadyExists = errors.New("account already exists")
+	// ErrInvalidAPIKey é retornado quando o API Key está malformado.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrInsufficientFunds é retornado quando o saldo disponível da conta não cobre o reembolso.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrTransactionNotFound é retornado quando uma transação não é encontrada.
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionAlreadyExists é retornado quando a transação já existe.
+	ErrTransactionAlreadyExists = errors.New("transaction already exists")
+	// ErrTransactionFailed é retornado quando a transação falha.
+	ErrTransactionFailed = errors.New("transaction failed")
+	// ErrTransactionLimitExceeded é retornado quando a fatura estoura o teto de gasto da conta.
+	ErrTransactionLimitExceeded = errors.New("transaction limit exceeded")
+	// ErrTransactionNotAllowed é retornado quando a operação não é mais permitida, como o reembolso fora do prazo.
+	ErrTransactionNotAllowed = errors.New("transaction not allowed")
+	// ErrTransactionAlreadyProcessed é retornado quando a transação já foi processada.
+	ErrTransactionAlreadyProcessed = errors.New("transaction already processed")
+	// ErrTransactionAlreadyCancelled é retornado quando a transação já foi cancelada.
+	ErrTransactionAlreadyCancelled = errors.New("transaction already cancelled")
+	// ErrTransactionAlreadyRefunded é retornado quando a fatura já foi toda reembolsada.
+	ErrTransactionAlreadyRefunded = errors.New("transaction already refunded")
+	// ErrTransactionAlreadyReversed é retornado quando a transação já foi estornada.
+	ErrTransactionAlreadyReversed = errors.New("transaction already reversed")
+	// ErrTransactionAlreadyChargedBack é retornado quando a transação já sofreu chargeback.
+	ErrTransactionAlreadyChargedBack = errors.New("transaction already charged back")
+	// ErrTransactionAlreadySettled é retornado quando a transação já foi liquidada.
+	ErrTransactionAlreadySettled = errors.New("transaction already settled")
+	// ErrTransactionAlreadyDisputed é retornado quando a fatura já tem uma disputa.
+	ErrTransactionAlreadyDisputed = errors.New("transaction already disputed")
+	// ErrNotFound é retornado quando o recurso não é encontrado.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidCardNumber é retornado quando o número do cartão tem tamanho inválido ou não passa pelo Luhn.
+	ErrInvalidCardNumber = errors.New("invalid card number")
+	// ErrInvalidCardCVV é retornado quando o CVV não tem três ou quatro dígitos.
+	ErrInvalidCardCVV = errors.New("invalid card cvv")
+	// ErrInvalidCardExpiry é retornado quando o mês ou o ano de validade do cartão é inválido.
+	ErrInvalidCardExpiry = errors.New("invalid card expiry")
+	// ErrInvalidCardholderName é retornado quando o nome do portador está vazio.
+	ErrInvalidCardholderName = errors.New("invalid cardholder name")
 
 	ErrInvalidAmount = errors.New("invalid amount")
 	ErrInvalidStatus = errors.New("invalid status")
+	// ErrInvalidDateRange é retornado quando a data inicial de um filtro é posterior à final.
+	ErrInvalidDateRange = errors.New("invalid date range")
+	// ErrInvalidBatchSize é retornado quando um lote de faturas está vazio ou excede o tamanho máximo.
+	ErrInvalidBatchSize = errors.New("invalid batch size")
+	// ErrInvalidCursor é retornado quando o cursor de paginação está malformado.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidSignature é retornado quando a assinatura HMAC da requisição está ausente, malformada ou não confere.
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrStaleTimestamp é retornado quando o timestamp de uma requisição assinada está fora da tolerância.
+	ErrStaleTimestamp = errors.New("request timestamp out of tolerance")
+	// ErrReplayedRequest é retornado quando o nonce de uma requisição assinada já foi usado.
+	ErrReplayedRequest = errors.New("request nonce already used")
+	// ErrUserNotFound é retornado quando um usuário do dashboard não é encontrado.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrDuplicatedEmail é retornado quando já existe um usuário com o e-mail informado.
+	ErrDuplicatedEmail = errors.New("email already registered")
+	// ErrWeakPassword é retornado quando a senha não atinge o tamanho mínimo.
+	ErrWeakPassword = errors.New("password too short")
+	// ErrInvalidCredentials é retornado quando e-mail ou senha não conferem no login.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidToken é retornado quando um JWT ou refresh token é inválido, expirado ou revogado.
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrSessionNotFound é retornado quando a sessão não existe, já foi encerrada ou pertence a outro usuário.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrInvalidRole é retornado quando o papel informado não existe.
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidScope é retornado quando um escopo de API Key informado não existe.
+	ErrInvalidScope = errors.New("invalid API key scope")
+	// ErrForbidden é retornado quando o papel autenticado não concede a permissão exigida pela rota.
+	ErrForbidden = errors.New("insufficient permissions")
+	// ErrClientBlocked é retornado quando o IP está bloqueado temporariamente por falhas consecutivas de autenticação.
+	ErrClientBlocked = errors.New("too many failed authentication attempts")
+	// ErrAPIKeyLocked é retornado quando o API Key está bloqueado temporariamente por falhas consecutivas de autenticação.
+	ErrAPIKeyLocked = errors.New("API key temporarily locked")
+	// ErrCardNotFound é retornado quando o token de cartão não existe, pertence a outra conta ou não guarda o número.
+	ErrCardNotFound = errors.New("card not found")
+	// ErrTwoFactorRequired é retornado quando uma operação sensível é pedida sem o código do segundo fator.
+	ErrTwoFactorRequired = errors.New("two-factor code required")
+	// ErrInvalidTwoFactorCode é retornado quando o código TOTP ou de recuperação não confere.
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTwoFactorNotEnrolled é retornado quando o autor não tem segundo fator cadastrado.
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication not enrolled")
+	// ErrTwoFactorAlreadyEnrolled é retornado quando o autor já concluiu o cadastro do segundo fator.
+	ErrTwoFactorAlreadyEnrolled = errors.New("two-factor authentication already enrolled")
+	// ErrTwoFactorLocked é retornado quando o segundo fator está bloqueado temporariamente por códigos inválidos consecutivos.
+	ErrTwoFactorLocked = errors.New("two-factor authentication temporarily locked")
+	// ErrInvalidGeoPolicy é retornado quando a ação ou algum país da política geográfica é inválido.
+	ErrInvalidGeoPolicy = errors.New("invalid geo policy")
+	// ErrGeoPolicyNotFound é retornado quando a conta não tem política geográfica.
+	ErrGeoPolicyNotFound = errors.New("geo policy not found")
+	// ErrCountryNotAllowed é retornado quando a requisição vem de um país bloqueado pela política da conta.
+	ErrCountryNotAllowed = errors.New("country not allowed")
+	// ErrInvalidDataSubject é retornado quando o pedido do titular não identifica o pagador.
+	ErrInvalidDataSubject = errors.New("payer name is required")
+	// ErrDataSubjectNotFound é retornado quando nenhuma fatura da conta pertence ao pagador informado.
+	ErrDataSubjectNotFound = errors.New("no personal data found for the data subject")
+	// ErrExportNotFound é retornado quando a exportação não existe.
+	ErrExportNotFound = errors.New("export not found")
+	// ErrInvalidDownloadLink é retornado quando a assinatura do link de download não confere ou o link expirou.
+	ErrInvalidDownloadLink = errors.New("invalid or expired download link")
+	// ErrInvalidSecurityWebhook é retornado quando a URL ou algum tipo de evento da assinatura de segurança é inválido.
+	ErrInvalidSecurityWebhook = errors.New("invalid security webhook")
+	// ErrSecurityWebhookNotFound é retornado quando a conta não assinou os eventos de segurança.
+	ErrSecurityWebhookNotFound = errors.New("security webhook not found")
+	// ErrInvalidStatsPeriod é retornado quando o período das estatísticas não é um número de dias ou horas entre 1h e 366d.
+	ErrInvalidStatsPeriod = errors.New("invalid stats period")
+	// ErrInvalidConfig é retornado quando a configuração recarregada tem algum valor inválido; nada é alterado.
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// ErrDependencyUnavailable é retornado quando o circuit breaker de uma dependência externa está aberto e a chamada é recusada sem chegar a ela.
+	ErrDependencyUnavailable = errors.New("dependency temporarily unavailable")
+	// ErrInvalidAlertRule é retornado quando o nome, a medida, o limite ou a janela da regra de alerta são inválidos.
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+	// ErrAlertRuleNotFound é retornado quando a regra de alerta não existe.
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	// ErrVelocityLimitExceeded é retornado quando o cartão, o pagador ou a conta passou do número de transações permitido na janela.
+	ErrVelocityLimitExceeded = errors.New("too many transactions")
+	// ErrInvalidBlocklistEntry é retornado quando o tipo, o valor, a ação, o motivo ou a expiração da entrada da lista de bloqueio são inválidos.
+	ErrInvalidBlocklistEntry = errors.New("invalid blocklist entry")
+	// ErrBlocklistEntryNotFound é retornado quando a entrada da lista de bloqueio não existe ou pertence a outra lista.
+	ErrBlocklistEntryNotFound = errors.New("blocklist entry not found")
+	// ErrBlocklistEntryExists é retornado quando a lista de bloqueio já tem uma entrada com o mesmo valor.
+	ErrBlocklistEntryExists = errors.New("blocklist entry already exists")
+	// ErrBlocklisted é retornado quando o cartão, o e-mail, o documento ou o IP da fatura está na lista de bloqueio.
+	ErrBlocklisted = errors.New("payment blocked by blocklist")
+	// ErrInvalidRiskPolicy é retornado quando os limites de score de risco estão fora de 0 a 100 ou o de aprovação passa o de recusa.
+	ErrInvalidRiskPolicy = errors.New("invalid risk policy")
+	// ErrRiskPolicyNotFound é retornado quando a conta não definiu limites de score e usa os padrão.
+	ErrRiskPolicyNotFound = errors.New("risk policy not found")
+	// ErrReviewNotFound é retornado quando a fatura não está na fila de revisão manual ou pertence a outra conta.
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewExists é retornado quando a fatura já está na fila de revisão manual.
+	ErrReviewExists = errors.New("review already exists")
+	// ErrReviewAlreadyDecided é retornado quando a revisão já foi decidida por um analista ou pelo prazo.
+	ErrReviewAlreadyDecided = errors.New("review already decided")
+	// ErrInvalidReviewDecision é retornado quando a decisão da revisão não é aprovar nem recusar.
+	ErrInvalidReviewDecision = errors.New("invalid review decision")
+	// ErrInvalidSplit é retornado quando as regras de divisão da fatura são inválidas, não fecham o valor dela, têm recebedora inexistente ou a taxa passa de alguma parte.
+	ErrInvalidSplit = errors.New("invalid invoice split")
+	// ErrInvalidPlatformLink é retornado quando a plataforma não existe, é a própria conta, faz parte de outra ligação ou a comissão está fora de 0 a 100.
+	ErrInvalidPlatformLink = errors.New("invalid platform link")
+	// ErrPlatformLinkNotFound é retornado quando a conta não é filha de uma plataforma.
+	ErrPlatformLinkNotFound = errors.New("platform link not found")
+	// ErrInvalidEscrow é retornado quando o prazo de custódia da fatura está fora de 0 a MaxEscrowDays dias.
+	ErrInvalidEscrow = errors.New("invalid escrow period")
+	// ErrEscrowNotFound é retornado quando a fatura não tem valores em custódia, por não ter pedido custódia ou ainda não ter sido aprovada.
+	ErrEscrowNotFound = errors.New("escrow not found")
+	// ErrEscrowAlreadyReleased é retornado quando os valores em custódia da fatura já foram liberados.
+	ErrEscrowAlreadyReleased = errors.New("escrow already released")
+	// ErrInvalidRefund é retornado quando o valor do reembolso não é positivo ou passa do que ainda não foi reembolsado da fatura.
+	ErrInvalidRefund = errors.New("invalid refund amount")
+	// ErrInvalidRefundPolicy é retornado quando o limite de aprovação dos reembolsos é negativo.
+	ErrInvalidRefundPolicy = errors.New("invalid refund policy")
+	// ErrRefundPolicyNotFound é retornado quando a conta não definiu uma política de reembolso.
+	ErrRefundPolicyNotFound = errors.New("refund policy not found")
+	// ErrRefundNotFound é retornado quando o reembolso não existe ou é de outra conta.
+	ErrRefundNotFound = errors.New("refund not found")
+	// ErrRefundAlreadyDecided é retornado quando o reembolso já foi aprovado, recusado ou venceu.
+	ErrRefundAlreadyDecided = errors.New("refund already decided")
+	// ErrRefundSelfApproval é retornado quando quem pediu o reembolso tenta aprová-lo.
+	ErrRefundSelfApproval = errors.New("refund must be approved by a different user")
+	// ErrInvalidRefundDecision é retornado quando a decisão sobre o reembolso não é "approved" nem "rejected".
+	ErrInvalidRefundDecision = errors.New("invalid refund decision")
+	// ErrInvalidDispute é retornado quando o motivo da disputa é vazio ou passa de 255 caracteres.
+	ErrInvalidDispute = errors.New("invalid dispute")
+	// ErrDisputeNotFound é retornado quando a disputa não existe ou é de outra conta.
+	ErrDisputeNotFound = errors.New("dispute not found")
+	// ErrDisputeClosed é retornado quando a disputa já foi encerrada e não aceita evidências nem outro resultado.
+	ErrDisputeClosed = errors.New("dispute already closed")
+	// ErrInvalidDisputeResolution é retornado quando o resultado da disputa não é "won" nem "lost".
+	ErrInvalidDisputeResolution = errors.New("invalid dispute resolution")
+	// ErrInvalidDisputeEvidence é retornado quando o arquivo de evidência é vazio, a descrição passa de 255 caracteres ou a disputa já tem MaxDisputeEvidenceFiles arquivos.
+	ErrInvalidDisputeEvidence = errors.New("invalid dispute evidence")
+	// ErrDisputeEvidenceTooLarge é retornado quando o arquivo de evidência passa de MaxDisputeEvidenceSize.
+	ErrDisputeEvidenceTooLarge = errors.New("dispute evidence too large")
+	// ErrUnsupportedDisputeEvidence é retornado quando o arquivo de evidência não é PDF, PNG nem JPEG.
+	ErrUnsupportedDisputeEvidence = errors.New("unsupported dispute evidence type")
+	// ErrDisputeEvidenceNotFound é retornado quando a evidência não existe na disputa.
+	ErrDisputeEvidenceNotFound = errors.New("dispute evidence not found")
+	// ErrInvalidFeeSchedule é retornado quando alguma tarifa da conta é negativa.
+	ErrInvalidFeeSchedule = errors.New("invalid fee schedule")
+	// ErrFeeScheduleNotFound é retornado quando a conta usa as tarifas padrão.
+	ErrFeeScheduleNotFound = errors.New("fee schedule not found")
+	// ErrInvalidSubscription é retornado quando o valor, o intervalo, a descrição ou a URL do webhook da assinatura é inválido.
+	ErrInvalidSubscription = errors.New("invalid subscription")
+	// ErrSubscriptionNotFound é retornado quando a assinatura não existe ou é de outra conta.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrSubscriptionCancelled é retornado quando a assinatura já foi cancelada.
+	ErrSubscriptionCancelled = errors.New("subscription already cancelled")
+	// ErrSubscriptionNotPastDue é retornado ao retomar uma assinatura que não está inadimplente.
+	ErrSubscriptionNotPastDue = errors.New("subscription is not past due")
+	// ErrSubscriptionChanged é retornado quando a assinatura mudou de situação durante a operação.
+	ErrSubscriptionChanged = errors.New("subscription changed concurrently")
+	// ErrInvalidCoupon é retornado quando o código, o tipo, o valor, o limite de usos ou a validade do cupom é inválido.
+	ErrInvalidCoupon = errors.New("invalid coupon")
+	// ErrCouponNotFound é retornado quando o cupom não existe ou é de outra conta.
+	ErrCouponNotFound = errors.New("coupon not found")
+	// ErrCouponExists é retornado quando a conta já tem um cupom com o mesmo código.
+	ErrCouponExists = errors.New("coupon already exists")
+	// ErrCouponUnavailable é retornado quando o cupom está desativado, fora da validade, sem usos restantes ou cobriria todo o valor da fatura.
+	ErrCouponUnavailable = errors.New("coupon unavailable")
+	// ErrInvalidTax é retornado quando a calculadora de impostos retorna um imposto sem nome, repetido, negativo ou de uma linha inexistente.
+	ErrInvalidTax = errors.New("invalid tax")
+	// ErrInvalidInvoiceItems é retornado quando algum item da fatura é inválido, são itens demais ou a soma deles não é o valor da fatura.
+	ErrInvalidInvoiceItems = errors.New("invalid invoice items")
+	// ErrInvalidCustomer é retornado quando o nome, o e-mail ou o documento do cliente é inválido, ou quando um lote de faturas informa cliente.
+	ErrInvalidCustomer = errors.New("invalid customer")
+	// ErrCustomerNotFound é retornado quando o cliente não existe ou é de outra conta.
+	ErrCustomerNotFound = errors.New("customer not found")
+	// ErrPaymentMethodLimit é retornado quando o cliente já tem MaxCustomerPaymentMethods cartões guardados.
+	ErrPaymentMethodLimit = errors.New("payment method limit reached")
+	// ErrInvalidPayoutSchedule é retornado quando o prazo de repasse não é um dos de PayoutDelays.
+	ErrInvalidPayoutSchedule = errors.New("invalid payout schedule")
+	// ErrPayoutScheduleNotFound é retornado quando a conta não escolheu um prazo de repasse.
+	ErrPayoutScheduleNotFound = errors.New("payout schedule not found")
+	// ErrNothingToAnticipate é retornado quando a conta não tem valores aguardando a data de repasse nas faturas pedidas.
+	ErrNothingToAnticipate = errors.New("nothing to anticipate")
+	// ErrInvalidTenant é retornado quando o nome, a marca, a tarifa ou os tetos de gasto da organização são inválidos.
+	ErrInvalidTenant = errors.New("invalid tenant")
+	// ErrTenantNotFound é retornado quando a organização não existe.
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrInvalidDueDate é retornado quando o vencimento da fatura não é uma data entre hoje e MaxDueDays dias.
+	ErrInvalidDueDate = errors.New("invalid due date")
+	// ErrInvalidPaymentDate é retornado quando a data do pagamento é anterior à criação da fatura ou futura.
+	ErrInvalidPaymentDate = errors.New("invalid payment date")
+	// ErrInvalidCurrency é retornado quando a moeda da fatura não é um código ISO 4217 com cotação disponível.
+	ErrInvalidCurrency = errors.New("invalid currency")
+	// ErrInvalidAmountLimits é retornado quando a faixa de valor tem limite negativo ou máximo menor que o mínimo.
+	ErrInvalidAmountLimits = errors.New("invalid amount limits")
+	// ErrAmountLimitsNotFound é retornado quando a conta usa a faixa de valor padrão do gateway.
+	ErrAmountLimitsNotFound = errors.New("amount limits not found")
+	// ErrInvalidStandingOrder é retornado quando o destino, o valor, a porcentagem, o intervalo ou a URL do webhook da
+	// ordem permanente é inválido.
+	ErrInvalidStandingOrder = errors.New("invalid standing order")
+	// ErrStandingOrderNotFound é retornado quando a ordem permanente não existe ou é de outra conta.
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+	// ErrStandingOrderCancelled é retornado quando a ordem permanente já foi cancelada.
+	ErrStandingOrderCancelled = errors.New("standing order already cancelled")
+	// ErrStandingOrderChanged é retornado quando a ordem permanente mudou de situação durante a operação.
+	ErrStandingOrderChanged = errors.New("standing order changed concurrently")
+	// ErrInsufficientBalance é retornado quando o saldo disponível da conta não cobre a transferência ou o débito.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrInvalidRefundWindow é retornado quando o prazo para reembolsar as faturas é negativo ou passa do máximo.
+	ErrInvalidRefundWindow = errors.New("invalid refund window")
+	// ErrRefundWindowNotFound é retornado quando a conta usa o prazo de reembolso padrão do gateway.
+	ErrRefundWindowNotFound = errors.New("refund window not found")
+	// ErrProcessorTimeout é retornado quando o processador não responde à autorização da fatura a tempo.
+	ErrProcessorTimeout = errors.New("processor timed out")
+	// ErrTestCardNotAllowed é retornado quando um cartão de teste é usado por uma conta fora do modo de teste.
+	ErrTestCardNotAllowed = errors.New("test card not allowed in live mode")
 )
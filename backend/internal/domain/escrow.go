@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// MaxEscrowDays limita o prazo de retenção das faturas em custódia
+const MaxEscrowDays = 365
+
+// EscrowDaysMetadataKey guarda nos metadados da fatura o prazo de custódia pedido na criação
+const EscrowDaysMetadataKey = "escrow_days"
+
+// SetEscrowDays pede que o valor da fatura aprovada fique em custódia por days dias; 0 credita direto no saldo
+// Retorna ErrInvalidEscrow se days estiver fora de 0 a MaxEscrowDays
+func (i *Invoice) SetEscrowDays(days int) error {
+	if days < 0 || days > MaxEscrowDays {
+		return ErrInvalidEscrow
+	}
+	// O prazo enviado nos metadados da requisição não vale; só o do campo escrow_days
+	delete(i.Metadata, EscrowDaysMetadataKey)
+	if days > 0 {
+		if i.Metadata == nil {
+			i.Metadata = map[string]string{}
+		}
+		i.Metadata[EscrowDaysMetadataKey] = strconv.Itoa(days)
+	}
+	return nil
+}
+
+// EscrowDays é o prazo de custódia da fatura em dias, 0 quando ela não pediu custódia
+func (i *Invoice) EscrowDays() int {
+	days, err := strconv.Atoi(i.Metadata[EscrowDaysMetadataKey])
+	if err != nil || days < 0 {
+		return 0
+	}
+	return days
+}
+
+// EscrowStatus é a situação de uma retenção em custódia
+type EscrowStatus string
+
+const (
+	// EscrowHeld é o valor aprovado ainda retido, fora do saldo disponível
+	EscrowHeld EscrowStatus = "held"
+	// EscrowReleased é o valor já liberado para o saldo da conta
+	EscrowReleased EscrowStatus = "released"
+	// EscrowCancelled é o valor retido devolvido ao pagador pelo chargeback da fatura, que nunca chega ao saldo
+	EscrowCancelled EscrowStatus = "cancelled"
+)
+
+// EscrowKind é o motivo da retenção
+type EscrowKind string
+
+const (
+	// EscrowKindEscrow é a custódia pedida pela fatura em escrow_days, que a conta pode liberar antes do prazo
+	EscrowKindEscrow EscrowKind = "escrow"
+	// EscrowKindPayout é o valor que aguarda o prazo de repasse da conta e só é liberado no fim dele
+	EscrowKindPayout EscrowKind = "payout"
+)
+
+// EscrowHold retém em custódia o que uma conta recebe por uma fatura aprovada até ReleaseAt ou uma liberação
+// explícita, como a confirmação da entrega
+type EscrowHold struct {
+	ID         string
+	AccountID  string
+	InvoiceID  string
+	Kind       EscrowKind
+	Amount     float64
+	Status     EscrowStatus
+	ReleaseAt  time.Time
+	ReleasedBy string
+	ReleasedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// NewEscrowHold cria a retenção do valor da conta pela fatura, liberada automaticamente em releaseAt
+func NewEscrowHold(accountID, invoiceID string, amount float64, releaseAt time.Time) *EscrowHold {
+	return &EscrowHold{
+		ID:        NewID(),
+		AccountID: accountID,
+		InvoiceID: invoiceID,
+		Kind:      EscrowKindEscrow,
+		Amount:    amount,
+		Status:    EscrowHeld,
+		ReleaseAt: releaseAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewPayoutHold cria a retenção do valor da conta pela fatura até releaseAt, a data de repasse do prazo da conta
+func NewPayoutHold(accountID, invoiceID string, amount float64, releaseAt time.Time) *EscrowHold {
+	hold := NewEscrowHold(accountID, invoiceID, amount, releaseAt)
+	hold.Kind = EscrowKindPayout
+	return hold
+}
+
+// Release marca a retenção como liberada por releasedBy
+// Retorna ErrEscrowAlreadyReleased se ela já foi liberada
+func (h *EscrowHold) Release(releasedBy string) error {
+	if h.Status != EscrowHeld {
+		return ErrEscrowAlreadyReleased
+	}
+	now := time.Now()
+	h.Status = EscrowReleased
+	h.ReleasedBy = releasedBy
+	h.ReleasedAt = &now
+	return nil
+}
+
+// Cancel marca a retenção como devolvida ao pagador por cancelledBy
+// Retorna ErrEscrowAlreadyReleased se ela já foi liberada ou devolvida
+func (h *EscrowHold) Cancel(cancelledBy string) error {
+	if err := h.Release(cancelledBy); err != nil {
+		return err
+	}
+	h.Status = EscrowCancelled
+	return nil
+}
+
+// EscrowRepository define a persistência das retenções em custódia
+type EscrowRepository interface {
+	// CreateBatch grava as retenções de uma vez
+	CreateBatch(ctx context.Context, holds []*EscrowHold) error
+	// ListByInvoiceID retorna as retenções da fatura, liberadas ou não
+	ListByInvoiceID(ctx context.Context, invoiceID string) ([]*EscrowHold, error)
+	// ListHeld retorna as retenções ainda retidas da conta pelo motivo kind, das que vencem primeiro
+	ListHeld(ctx context.Context, accountID string, kind EscrowKind) ([]*EscrowHold, error)
+	// ListDue retorna até limit retenções ainda retidas com ReleaseAt até before, das que venceram primeiro
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*EscrowHold, error)
+	// Release grava a liberação se a retenção ainda estiver retida; retorna ErrEscrowAlreadyReleased se outra
+	// liberação chegou antes
+	Release(ctx context.Context, hold *EscrowHold) error
+	// SumHeld soma o valor ainda retido da conta pelo motivo kind
+	SumHeld(ctx context.Context, accountID string, kind EscrowKind) (float64, error)
+}
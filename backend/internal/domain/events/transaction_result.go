@@ -2,9 +2,12 @@ package events
 
 import "github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 
+// TransactionResult é o resultado da análise de uma fatura pendente pelo antifraude
+// RiskScore é opcional, de 0 a 100; quando enviado, decide a fatura pelos limites de score da conta no lugar de Status
 type TransactionResult struct {
-	InvoiceID string `json:"invoice_id"`
-	Status    string `json:"status"`
+	InvoiceID string   `json:"invoice_id"`
+	Status    string   `json:"status"`
+	RiskScore *float64 `json:"risk_score,omitempty"`
 }
 
 func NewTransactionResult(invoiceID string, status string) *TransactionResult {
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ExportType é o conteúdo de uma exportação
+type ExportType string
+
+const (
+	// ExportInvoices é o relatório CSV das faturas da conta em um período
+	ExportInvoices ExportType = "invoices"
+	// ExportDataSubject são os dados pessoais de um pagador (LGPD/GDPR), em JSON
+	ExportDataSubject ExportType = "data_subject"
+)
+
+// Export é um arquivo gerado para a conta e guardado no armazenamento de objetos
+// ObjectKey é o caminho no armazenamento, que nunca é exposto; o download é feito por links assinados até ExpiresAt
+type Export struct {
+	ID          string
+	AccountID   string
+	Type        ExportType
+	ObjectKey   string
+	FileName    string
+	ContentType string
+	Size        int64
+	Downloads   int
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// NewExport cria a exportação da conta com o caminho exports/<conta>/<id>/<arquivo> no armazenamento
+func NewExport(accountID string, exportType ExportType, fileName, contentType string, size int64, retention time.Duration) *Export {
+	id := NewID()
+	now := time.Now()
+	return &Export{
+		ID:          id,
+		AccountID:   accountID,
+		Type:        exportType,
+		ObjectKey:   "exports/" + accountID + "/" + id + "/" + fileName,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(retention),
+	}
+}
+
+// Expired indica se a exportação não pode mais ser baixada
+func (e *Export) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+type ExportRepository interface {
+	Save(ctx context.Context, export *Export) error
+	FindByID(ctx context.Context, id string) (*Export, error)
+	// RecordDownload conta o download e o registra na trilha de auditoria com o IP e o User-Agent do cliente
+	RecordDownload(ctx context.Context, id string) error
+}
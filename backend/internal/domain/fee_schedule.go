@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FeeSchedule são as tarifas cobradas de uma conta no lugar das tarifas padrão do gateway
+// ChargebackFee é debitada do saldo da conta em cada disputa registrada; 0 isenta a conta
+type FeeSchedule struct {
+	AccountID     string
+	ChargebackFee float64
+	UpdatedAt     time.Time
+}
+
+// NewFeeSchedule valida as tarifas da conta, que não podem ser negativas
+// Retorna ErrInvalidFeeSchedule se alguma tarifa for inválida
+func NewFeeSchedule(accountID string, chargebackFee float64) (*FeeSchedule, error) {
+	if chargebackFee < 0 {
+		return nil, ErrInvalidFeeSchedule
+	}
+	return &FeeSchedule{AccountID: accountID, ChargebackFee: fromCents(toCents(chargebackFee)), UpdatedAt: time.Now()}, nil
+}
+
+// FeeScheduleRepository define a persistência das tarifas das contas
+type FeeScheduleRepository interface {
+	Save(ctx context.Context, schedule *FeeSchedule) error
+	// FindByAccountID retorna ErrFeeScheduleNotFound quando a conta usa as tarifas padrão
+	FindByAccountID(ctx context.Context, accountID string) (*FeeSchedule, error)
+	// Delete volta a conta às tarifas padrão; não falha se ela já as usava
+	Delete(ctx context.Context, accountID string) error
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FindOptions ajusta o comportamento das buscas nos repositórios
+type FindOptions struct {
+	IncludeDeleted bool
+}
+
+// FindOption modifica as FindOptions de uma busca
+type FindOption func(*FindOptions)
+
+// IncludeDeleted faz a busca considerar registros excluídos logicamente (uso administrativo)
+func IncludeDeleted() FindOption {
+	return func(o *FindOptions) {
+		o.IncludeDeleted = true
+	}
+}
+
+// NewFindOptions aplica as opções informadas sobre os valores padrão
+func NewFindOptions(opts ...FindOption) FindOptions {
+	var options FindOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// PurgeableRepository é implementado pelos repositórios que removem definitivamente registros excluídos
+type PurgeableRepository interface {
+	// CountDeleted conta os registros excluídos antes do instante informado que podem ser removidos
+	CountDeleted(ctx context.Context, before time.Time) (int64, error)
+	// PurgeDeleted remove definitivamente os registros excluídos antes do instante informado
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Chaves dos metadados da fatura em outra moeda: a moeda, a cotação travada na criação e até quando ela vale, e
+// a cotação usada no valor, que muda quando a trava vence antes da captura
+const (
+	CurrencyMetadataKey      = "currency"
+	FXRateMetadataKey        = "fx_rate"
+	FXLockedRateMetadataKey  = "fx_locked_rate"
+	FXLockedUntilMetadataKey = "fx_locked_until"
+	FXRequotedAtMetadataKey  = "fx_requoted_at"
+)
+
+// fxTimeLayout é o formato dos horários da trava e da nova cotação nos metadados
+const fxTimeLayout = time.RFC3339
+
+// currencyPattern aceita os códigos de moeda ISO 4217, como USD
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidCurrency indica se currency é um código de moeda no formato ISO 4217
+func ValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+// ExchangeRateProvider cota as moedas, como as taxas fixas da configuração ou um provedor externo
+type ExchangeRateProvider interface {
+	// Rate retorna quanto vale uma unidade de currency na moeda de liquidação settlement
+	// Retorna ErrInvalidCurrency se não houver cotação para a moeda
+	Rate(ctx context.Context, currency, settlement string) (float64, error)
+}
+
+// LockExchangeRate converte o valor da fatura, pedido em currency, para a moeda de liquidação pela cotação rate,
+// travada até until; a moeda e a cotação ficam nos metadados
+func (i *Invoice) LockExchangeRate(currency string, rate float64, until time.Time) {
+	if i.Metadata == nil {
+		i.Metadata = map[string]string{}
+	}
+	formatted := strconv.FormatFloat(rate, 'f', -1, 64)
+	i.Metadata[CurrencyMetadataKey] = currency
+	i.Metadata[FXRateMetadataKey] = formatted
+	i.Metadata[FXLockedRateMetadataKey] = formatted
+	i.Metadata[FXLockedUntilMetadataKey] = until.UTC().Format(fxTimeLayout)
+	i.Amount = fromCents(toCents(i.Amount * rate))
+}
+
+// ClearExchangeRate remove a moeda e as cotações enviadas nos metadados da requisição; só valem as travadas por
+// LockExchangeRate
+func (i *Invoice) ClearExchangeRate() {
+	delete(i.Metadata, CurrencyMetadataKey)
+	delete(i.Metadata, FXRateMetadataKey)
+	delete(i.Metadata, FXLockedRateMetadataKey)
+	delete(i.Metadata, FXLockedUntilMetadataKey)
+	delete(i.Metadata, FXRequotedAtMetadataKey)
+}
+
+// Currency é a moeda em que a fatura foi pedida, vazia quando ela está na moeda de liquidação
+func (i *Invoice) Currency() string {
+	return i.Metadata[CurrencyMetadataKey]
+}
+
+// ExchangeRate é a cotação usada no valor da fatura, 0 quando ela está na moeda de liquidação
+func (i *Invoice) ExchangeRate() float64 {
+	return metadataFloat(i.Metadata, FXRateMetadataKey)
+}
+
+// LockedExchangeRate é a cotação travada na criação, que só difere de ExchangeRate depois de uma nova cotação
+func (i *Invoice) LockedExchangeRate() float64 {
+	return metadataFloat(i.Metadata, FXLockedRateMetadataKey)
+}
+
+// ExchangeRateLockedUntil é até quando vale a cotação travada na criação
+func (i *Invoice) ExchangeRateLockedUntil() time.Time {
+	until, _ := time.Parse(fxTimeLayout, i.Metadata[FXLockedUntilMetadataKey])
+	return until
+}
+
+// ExchangeRateRequotedAt é quando a fatura foi cotada de novo na captura, zero se a trava ainda valia
+func (i *Invoice) ExchangeRateRequotedAt() time.Time {
+	at, _ := time.Parse(fxTimeLayout, i.Metadata[FXRequotedAtMetadataKey])
+	return at
+}
+
+// ExchangeRateExpired indica se a fatura está em outra moeda e a trava da cotação venceu em now
+func (i *Invoice) ExchangeRateExpired(now time.Time) bool {
+	return i.Currency() != "" && now.After(i.ExchangeRateLockedUntil())
+}
+
+// CurrencyAmount é o valor da fatura na moeda em que ela foi pedida, com o desconto, os impostos e os encargos
+func (i *Invoice) CurrencyAmount() float64 {
+	rate := i.ExchangeRate()
+	if rate <= 0 {
+		return i.Amount
+	}
+	return fromCents(toCents(i.Amount / rate))
+}
+
+// RequoteExchangeRate recalcula o valor da fatura pela nova cotação rate, mantendo o valor na moeda pedida, e
+// registra a nova cotação e quando ela foi obtida
+func (i *Invoice) RequoteExchangeRate(rate float64, now time.Time) {
+	amount := i.Amount / i.ExchangeRate()
+	i.Metadata[FXRateMetadataKey] = strconv.FormatFloat(rate, 'f', -1, 64)
+	i.Metadata[FXRequotedAtMetadataKey] = now.UTC().Format(fxTimeLayout)
+	i.Amount = fromCents(toCents(amount * rate))
+}
+
+// metadataFloat lê um número dos metadados, 0 quando a chave não existe ou não é um número positivo
+func metadataFloat(metadata map[string]string, key string) float64 {
+	value, err := strconv.ParseFloat(metadata[key], 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
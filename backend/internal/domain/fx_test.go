@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidCurrency(t *testing.T) {
+	tests := map[string]bool{"USD": true, "EUR": true, "usd": false, "US": false, "USDT": false, "": false}
+	for currency, want := range tests {
+		if got := ValidCurrency(currency); got != want {
+			t.Errorf("ValidCurrency(%q) = %v, want %v", currency, got, want)
+		}
+	}
+}
+
+func TestInvoiceLockExchangeRate(t *testing.T) {
+	tests := []struct {
+		name               string
+		amount             float64
+		rate               float64
+		wantAmount         float64
+		wantCurrencyAmount float64
+	}{
+		{"rounded down", 10, 5.1234, 51.23, 10},
+		{"rounded up", 19.99, 5.4321, 108.59, 19.99},
+		{"rate below one", 100, 0.1867, 18.67, 100},
+		{"one cent", 0.01, 5.4321, 0.05, 0.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until := time.Now().Add(time.Minute)
+			invoice := &Invoice{Amount: tt.amount}
+			invoice.LockExchangeRate("USD", tt.rate, until)
+
+			if invoice.Amount != tt.wantAmount {
+				t.Errorf("Amount = %v, want %v", invoice.Amount, tt.wantAmount)
+			}
+			if got := invoice.CurrencyAmount(); got != tt.wantCurrencyAmount {
+				t.Errorf("CurrencyAmount = %v, want %v", got, tt.wantCurrencyAmount)
+			}
+			if invoice.Currency() != "USD" || invoice.ExchangeRate() != tt.rate || invoice.LockedExchangeRate() != tt.rate {
+				t.Errorf("currency %q rate %v locked %v, want USD %v", invoice.Currency(), invoice.ExchangeRate(), invoice.LockedExchangeRate(), tt.rate)
+			}
+			if !invoice.ExchangeRateLockedUntil().Equal(until.Truncate(time.Second)) {
+				t.Errorf("ExchangeRateLockedUntil = %v, want %v", invoice.ExchangeRateLockedUntil(), until)
+			}
+		})
+	}
+}
+
+func TestInvoiceRequoteExchangeRate(t *testing.T) {
+	now := time.Now()
+	invoice := &Invoice{Amount: 19.99}
+	invoice.LockExchangeRate("USD", 5.4321, now.Add(-time.Minute))
+
+	// O valor na moeda pedida é mantido; só o valor liquidado muda com a nova cotação
+	invoice.RequoteExchangeRate(5.5, now)
+	if invoice.Amount != 109.95 {
+		t.Errorf("Amount = %v, want 109.95", invoice.Amount)
+	}
+	if got := invoice.CurrencyAmount(); got != 19.99 {
+		t.Errorf("CurrencyAmount = %v, want 19.99", got)
+	}
+	if invoice.ExchangeRate() != 5.5 || invoice.LockedExchangeRate() != 5.4321 {
+		t.Errorf("rate %v locked %v, want 5.5 and 5.4321", invoice.ExchangeRate(), invoice.LockedExchangeRate())
+	}
+	if !invoice.ExchangeRateRequotedAt().Equal(now.Truncate(time.Second)) {
+		t.Errorf("ExchangeRateRequotedAt = %v, want %v", invoice.ExchangeRateRequotedAt(), now)
+	}
+}
+
+func TestInvoiceExchangeRateExpired(t *testing.T) {
+	now := time.Now()
+	local := &Invoice{Amount: 10, Metadata: map[string]string{}}
+	if local.ExchangeRateExpired(now) {
+		t.Error("invoice in the settlement currency reported an expired rate")
+	}
+	if local.CurrencyAmount() != 10 {
+		t.Errorf("CurrencyAmount = %v, want the amount", local.CurrencyAmount())
+	}
+
+	foreign := &Invoice{Amount: 10}
+	foreign.LockExchangeRate("USD", 5, now.Add(time.Minute))
+	if foreign.ExchangeRateExpired(now) {
+		t.Error("rate expired inside the lock window")
+	}
+	if !foreign.ExchangeRateExpired(now.Add(2 * time.Minute)) {
+		t.Error("rate did not expire after the lock window")
+	}
+
+	// A moeda e as cotações enviadas nos metadados da requisição são descartadas
+	foreign.ClearExchangeRate()
+	if foreign.Currency() != "" || foreign.ExchangeRate() != 0 || !foreign.ExchangeRateLockedUntil().IsZero() {
+		t.Errorf("ClearExchangeRate left %v", foreign.Metadata)
+	}
+}
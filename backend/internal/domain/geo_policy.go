@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GeoAction é o que acontece com requisições de países não permitidos pela política da conta
+type GeoAction string
+
+const (
+	// GeoActionBlock recusa a autenticação e a criação de faturas
+	GeoActionBlock GeoAction = "block"
+	// GeoActionFlag aceita a requisição e marca as faturas criadas para análise
+	GeoActionFlag GeoAction = "flag"
+)
+
+// GeoPolicy restringe os países de onde a conta pode ser usada, identificados pelo IP da requisição
+// Com AllowedCountries vazia qualquer país fora de BlockedCountries é permitido
+type GeoPolicy struct {
+	AccountID string
+	Action    GeoAction
+	// Shadow avalia a política sem aplicá-la: as requisições fora dela são registradas e contadas com a ação
+	// que seria aplicada, mas seguem normalmente, para medir o impacto antes de ativar a política
+	Shadow           bool
+	AllowedCountries []string
+	BlockedCountries []string
+	UpdatedAt        time.Time
+}
+
+// NewGeoPolicy valida a política; os países são códigos ISO 3166-1 de duas letras, sem diferenciar maiúsculas
+// Retorna ErrInvalidGeoPolicy se a ação ou algum país for inválido
+func NewGeoPolicy(accountID string, action GeoAction, shadow bool, allowed, blocked []string) (*GeoPolicy, error) {
+	if action != GeoActionBlock && action != GeoActionFlag {
+		return nil, ErrInvalidGeoPolicy
+	}
+
+	allowedCountries, err := normalizeCountries(allowed)
+	if err != nil {
+		return nil, err
+	}
+	blockedCountries, err := normalizeCountries(blocked)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeoPolicy{
+		AccountID:        accountID,
+		Action:           action,
+		Shadow:           shadow,
+		AllowedCountries: allowedCountries,
+		BlockedCountries: blockedCountries,
+		UpdatedAt:        time.Now(),
+	}, nil
+}
+
+// normalizeCountries valida e padroniza os códigos em maiúsculas, sem repetição
+func normalizeCountries(countries []string) ([]string, error) {
+	normalized := make([]string, 0, len(countries))
+	for _, country := range countries {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if len(country) != 2 || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+			return nil, ErrInvalidGeoPolicy
+		}
+		if !slices.Contains(normalized, country) {
+			normalized = append(normalized, country)
+		}
+	}
+	return normalized, nil
+}
+
+// Allows indica se o país é permitido
+// País desconhecido ("") só é permitido quando não há lista de países permitidos
+func (p *GeoPolicy) Allows(country string) bool {
+	if slices.Contains(p.BlockedCountries, country) {
+		return false
+	}
+	return len(p.AllowedCountries) == 0 || slices.Contains(p.AllowedCountries, country)
+}
+
+type GeoPolicyRepository interface {
+	// Save grava a política da conta, substituindo a anterior
+	Save(ctx context.Context, policy *GeoPolicy) error
+	// FindByAccountID retorna ErrGeoPolicyNotFound quando a conta não tem política
+	FindByAccountID(ctx context.Context, accountID string) (*GeoPolicy, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "github.com/google/uuid"
+
+// NewID gera o identificador de uma nova entidade como UUIDv7
+// O prefixo de timestamp mantém as chaves primárias ordenadas por criação, melhorando a localidade dos índices;
+// o formato continua sendo um UUID padrão, então IDs v4 já gravados seguem válidos nas mesmas colunas
+func NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
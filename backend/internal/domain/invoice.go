@@ -1,10 +1,8 @@
 package domain
 
 import (
-	"math/rand"
+	"slices"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 type Status string
@@ -13,8 +11,49 @@ const (
 	StatusPending  Status = "pending"
 	StatusApproved Status = "approved"
 	StatusRejected Status = "rejected"
+	// StatusSettled é a fatura aprovada cujo valor já está todo no saldo disponível das contas, sem nada retido em
+	// custódia nem esperando a data de repasse
+	StatusSettled Status = "settled"
+	// StatusRefunded é a fatura aprovada cujos reembolsos concluídos somam o valor dela
+	StatusRefunded Status = "refunded"
+	// StatusChargedBack é a fatura aprovada que perdeu a disputa aberta pelo pagador
+	StatusChargedBack Status = "charged_back"
 )
 
+// IsValid indica se o status é um dos valores conhecidos
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusRejected, StatusSettled, StatusRefunded, StatusChargedBack:
+		return true
+	}
+	return false
+}
+
+// IsPaid indica se a fatura foi aprovada e ainda não foi reembolsada nem estornada, com o valor liquidado ou não
+func (s Status) IsPaid() bool {
+	return s == StatusApproved || s == StatusSettled
+}
+
+// invoiceTransitions são as mudanças de status permitidas a partir de cada status
+// A fatura nasce pendente e é aprovada ou recusada pelo processamento ou pelo antifraude; a aprovada é liquidada
+// quando o valor dela chega todo ao saldo disponível, e a aprovada ou liquidada é reembolsada quando os reembolsos
+// concluídos somam o valor dela, ou estornada quando perde a disputa; as demais são finais
+var invoiceTransitions = map[Status][]Status{
+	StatusPending:  {StatusApproved, StatusRejected},
+	StatusApproved: {StatusSettled, StatusRefunded, StatusChargedBack},
+	StatusSettled:  {StatusRefunded, StatusChargedBack},
+}
+
+// CanTransitionTo indica se uma fatura neste status pode passar para next
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(invoiceTransitions[s], next)
+}
+
+// IsFinal indica se o status não muda mais
+func (s Status) IsFinal() bool {
+	return len(invoiceTransitions[s]) == 0
+}
+
 type Invoice struct {
 	ID             string
 	AccountID      string
@@ -22,63 +61,78 @@ type Invoice struct {
 	Status         Status
 	Description    string
 	PaymentType    string
+	CardToken      string
+	CardBrand      string
 	CardLastDigits string
+	PayerName      string
+	Metadata       map[string]string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      *time.Time
 }
 
-type CreditCard struct {
-	Number         string
-	CVV            string
-	ExpiryMonth    int
-	ExpiryYear     int
-	CardholderName string
+// PaymentCard é a referência ao cartão guardado no cofre de carddata
+// Número e CVV nunca chegam ao domínio; as faturas guardam apenas o token e os dados mascarados
+type PaymentCard struct {
+	Token      string
+	Brand      string
+	LastDigits string
+	HolderName string
 }
 
-func NewInvoice(accountID string, amount float64, description string, paymentType string, card CreditCard) (*Invoice, error) {
+func NewInvoice(accountID string, amount float64, description string, paymentType string, card PaymentCard) (*Invoice, error) {
 	if amount <= 0 {
 		return nil, ErrInvalidAmount
 	}
 
-	lastDigits := card.Number[len(card.Number)-4:]
-
 	return &Invoice{
-		ID:             uuid.New().String(),
+		ID:             NewID(),
 		AccountID:      accountID,
 		Amount:         amount,
 		Status:         StatusPending,
 		Description:    description,
 		PaymentType:    paymentType,
-		CardLastDigits: lastDigits,
+		CardToken:      card.Token,
+		CardBrand:      card.Brand,
+		CardLastDigits: card.LastDigits,
+		PayerName:      card.HolderName,
+		Metadata:       map[string]string{},
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}, nil
 }
 
-func (i *Invoice) Process() error {
+// Process decide a fatura com o processador informado, guardando em DeclineReasonMetadataKey o motivo da recusa
+// As faturas acima de 10000 ficam pendentes para o antifraude sem passar pelo processador
+func (i *Invoice) Process(processor Processor) error {
 	if i.Amount > 10000 {
 		return nil
 	}
 
-	randomSource := rand.New(rand.NewSource(time.Now().Unix()))
-	var newStatus Status
-
-	if randomSource.Float64() <= 0.7 {
-		newStatus = StatusApproved
-	} else {
-		newStatus = StatusRejected
+	authorization, err := processor.Authorize(i)
+	if err != nil {
+		return err
+	}
+	if err := i.TransitionTo(authorization.Status); err != nil {
+		return err
+	}
+	if authorization.Status == StatusRejected && authorization.DeclineReason != "" {
+		if i.Metadata == nil {
+			i.Metadata = map[string]string{}
+		}
+		i.Metadata[DeclineReasonMetadataKey] = string(authorization.DeclineReason)
 	}
-
-	i.Status = newStatus
 	return nil
 }
 
-func (i *Invoice) UpdateStatus(newStatus Status) error {
-	if i.Status != StatusPending {
+// TransitionTo muda o status da fatura, que é o único caminho para alterá-lo
+// Retorna ErrInvalidStatus, sem alterar a fatura, se a mudança não estiver em invoiceTransitions
+func (i *Invoice) TransitionTo(next Status) error {
+	if !i.Status.CanTransitionTo(next) {
 		return ErrInvalidStatus
 	}
 
-	i.Status = newStatus
+	i.Status = next
 	i.UpdatedAt = time.Now()
 	return nil
 }
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// InvoiceFilter reúne os critérios opcionais para listagem de faturas
+// Campos com valor zero não restringem a busca
+type InvoiceFilter struct {
+	AccountID   string
+	Statuses    []Status
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   float64
+	MaxAmount   float64
+	Metadata    map[string]string
+	// Search busca por texto no nome do pagador, na descrição e nos valores de metadata, ordenando por relevância
+	Search string
+	// Limit limita a quantidade de faturas retornadas; zero retorna todas
+	Limit int
+	// After restringe a busca às faturas posteriores ao cursor na ordenação da listagem
+	After *Cursor
+	// IncludeDeleted inclui faturas excluídas logicamente (uso administrativo)
+	IncludeDeleted bool
+}
+
+// Validate verifica se os status e intervalos informados são consistentes
+func (f InvoiceFilter) Validate() error {
+	for _, status := range f.Statuses {
+		if !status.IsValid() {
+			return ErrInvalidStatus
+		}
+	}
+
+	if f.MinAmount < 0 || f.MaxAmount < 0 {
+		return ErrInvalidAmount
+	}
+	if f.MaxAmount > 0 && f.MinAmount > f.MaxAmount {
+		return ErrInvalidAmount
+	}
+
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && f.CreatedFrom.After(f.CreatedTo) {
+		return ErrInvalidDateRange
+	}
+
+	return nil
+}
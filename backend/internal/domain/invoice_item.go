@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limites dos itens de uma fatura
+const (
+	MaxInvoiceItems                 = 100
+	MaxInvoiceItemDescriptionLength = 255
+	MaxInvoiceItemQuantity          = 10000
+)
+
+// InvoiceItem é um item da fatura; Position é a posição dele na fatura, a mesma das linhas dos impostos
+type InvoiceItem struct {
+	InvoiceID   string
+	Position    int
+	Description string
+	Quantity    int
+	UnitPrice   float64
+	CreatedAt   time.Time
+}
+
+// NewInvoiceItem cria um item ainda sem fatura
+// Retorna ErrInvalidInvoiceItems se a descrição estiver vazia ou for longa demais, a quantidade estiver fora de 1 a
+// MaxInvoiceItemQuantity ou o preço unitário não for positivo
+func NewInvoiceItem(description string, quantity int, unitPrice float64) (*InvoiceItem, error) {
+	if description == "" || len(description) > MaxInvoiceItemDescriptionLength {
+		return nil, ErrInvalidInvoiceItems
+	}
+	if quantity < 1 || quantity > MaxInvoiceItemQuantity || toCents(unitPrice) <= 0 {
+		return nil, ErrInvalidInvoiceItems
+	}
+	return &InvoiceItem{Description: description, Quantity: quantity, UnitPrice: fromCents(toCents(unitPrice))}, nil
+}
+
+// Total é a quantidade vezes o preço unitário
+func (i *InvoiceItem) Total() float64 {
+	return fromCents(i.totalCents())
+}
+
+func (i *InvoiceItem) totalCents() int64 {
+	return int64(i.Quantity) * toCents(i.UnitPrice)
+}
+
+// InvoiceItemsTotal soma os itens em centavos
+func InvoiceItemsTotal(items []*InvoiceItem) float64 {
+	var total int64
+	for _, item := range items {
+		total += item.totalCents()
+	}
+	return fromCents(total)
+}
+
+// SetItems liga os itens à fatura na ordem recebida
+// Retorna ErrInvalidInvoiceItems se forem mais de MaxInvoiceItems ou se a soma não for o valor da fatura
+func (i *Invoice) SetItems(items []*InvoiceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if len(items) > MaxInvoiceItems || toCents(InvoiceItemsTotal(items)) != toCents(i.Amount) {
+		return ErrInvalidInvoiceItems
+	}
+	for position, item := range items {
+		item.InvoiceID = i.ID
+		item.Position = position
+		item.CreatedAt = i.CreatedAt
+	}
+	return nil
+}
+
+// TaxLines são as linhas tributáveis da fatura: um por item ou, sem itens, a fatura inteira com a descrição dela
+// O desconto do cupom é rateado entre os itens pelo valor de cada um, com o resto dos centavos no último
+func (i *Invoice) TaxLines(items []*InvoiceItem) []TaxLine {
+	if len(items) == 0 {
+		return []TaxLine{{Description: i.Description, Amount: i.Amount}}
+	}
+
+	_, discount := i.Discount()
+	remaining := toCents(discount)
+	gross := toCents(InvoiceItemsTotal(items))
+	lines := make([]TaxLine, len(items))
+	for position, item := range items {
+		share := remaining
+		if position < len(items)-1 {
+			share = int64(math.Floor(float64(toCents(discount)) * float64(item.totalCents()) / float64(gross)))
+		}
+		remaining -= share
+		lines[position] = TaxLine{Description: item.Description, Amount: fromCents(item.totalCents() - share)}
+	}
+	return lines
+}
+
+// InvoiceItemRepository define a persistência dos itens das faturas
+type InvoiceItemRepository interface {
+	// SaveBatch grava os itens de uma ou mais faturas de uma vez
+	SaveBatch(ctx context.Context, items []*InvoiceItem) error
+	// FindByInvoiceID retorna os itens da fatura na ordem dela, ou nenhum se ela não tem itens
+	FindByInvoiceID(ctx context.Context, invoiceID string) ([]*InvoiceItem, error)
+}
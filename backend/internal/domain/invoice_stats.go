@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStatsPeriod é o período das estatísticas quando nenhum é informado
+const DefaultStatsPeriod = "30d"
+
+// maxStatsPeriod limita o período das estatísticas, para que a agregação não percorra o histórico inteiro
+const maxStatsPeriod = 366 * 24 * time.Hour
+
+// StatusTotals resume as faturas de um status: quantidade e soma dos valores
+type StatusTotals struct {
+	Status Status
+	Count  int
+	Amount float64
+}
+
+// ParseStatsPeriod interpreta o período das estatísticas em dias ("30d") ou horas ("24h")
+// Retorna ErrInvalidStatsPeriod para formatos desconhecidos, valores não positivos ou acima de um ano
+func ParseStatsPeriod(value string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "h"):
+		unit = time.Hour
+	default:
+		return 0, ErrInvalidStatsPeriod
+	}
+
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount <= 0 || time.Duration(amount) > maxStatsPeriod/unit {
+		return 0, ErrInvalidStatsPeriod
+	}
+	return time.Duration(amount) * unit, nil
+}
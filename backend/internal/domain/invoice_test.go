@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusPending, StatusApproved, true},
+		{StatusPending, StatusRejected, true},
+		{StatusPending, StatusPending, false},
+		{StatusPending, StatusSettled, false},
+		{StatusApproved, StatusSettled, true},
+		{StatusApproved, StatusRefunded, true},
+		{StatusApproved, StatusChargedBack, true},
+		{StatusApproved, StatusRejected, false},
+		{StatusApproved, StatusPending, false},
+		{StatusSettled, StatusRefunded, true},
+		{StatusSettled, StatusChargedBack, true},
+		{StatusSettled, StatusApproved, false},
+		{StatusRefunded, StatusApproved, false},
+		{StatusChargedBack, StatusRefunded, false},
+		{StatusRejected, StatusApproved, false},
+		{StatusRejected, StatusPending, false},
+		{StatusPending, Status("unknown"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusIsFinal(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   bool
+	}{
+		{StatusPending, false},
+		{StatusApproved, false},
+		{StatusSettled, false},
+		{StatusRejected, true},
+		{StatusRefunded, true},
+		{StatusChargedBack, true},
+	}
+	for _, tt := range tests {
+		if got := tt.status.IsFinal(); got != tt.want {
+			t.Errorf("%s.IsFinal() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestStatusIsPaid(t *testing.T) {
+	for _, status := range []Status{StatusPending, StatusApproved, StatusRejected, StatusSettled, StatusRefunded, StatusChargedBack} {
+		want := status == StatusApproved || status == StatusSettled
+		if got := status.IsPaid(); got != want {
+			t.Errorf("%s.IsPaid() = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestInvoiceTransitionTo(t *testing.T) {
+	invoice, err := NewInvoice("a", 10, "Pedido", "credit_card", PaymentCard{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedAt := invoice.UpdatedAt
+
+	if err := invoice.TransitionTo(StatusApproved); err != nil {
+		t.Fatalf("TransitionTo(approved): %v", err)
+	}
+	if invoice.Status != StatusApproved {
+		t.Errorf("Status = %q, want %q", invoice.Status, StatusApproved)
+	}
+	if invoice.UpdatedAt.Before(updatedAt) {
+		t.Error("UpdatedAt went backwards")
+	}
+
+	// Uma mudança fora da tabela falha sem alterar a fatura
+	if err := invoice.TransitionTo(StatusRejected); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("TransitionTo(rejected) error = %v, want ErrInvalidStatus", err)
+	}
+	if invoice.Status != StatusApproved {
+		t.Errorf("Status = %q after a refused transition, want %q", invoice.Status, StatusApproved)
+	}
+}
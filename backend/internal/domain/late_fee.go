@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// DueDateLayout é o formato do vencimento das faturas, AAAA-MM-DD
+const DueDateLayout = "2006-01-02"
+
+// MaxDueDays limita o vencimento das faturas, contado da criação
+const MaxDueDays = 365
+
+// Chaves dos metadados da fatura com o vencimento pedido na criação e os encargos somados ao valor quando o
+// pagamento é confirmado depois dele
+const (
+	DueDateMetadataKey      = "due_date"
+	DaysLateMetadataKey     = "days_late"
+	LateFineMetadataKey     = "late_fine"
+	LateInterestMetadataKey = "late_interest"
+)
+
+// LateFeePolicy são os encargos das faturas pagas depois do vencimento, como nos boletos
+type LateFeePolicy struct {
+	// FinePercent é a multa, em percentual do valor, cobrada uma vez em qualquer atraso
+	FinePercent float64
+	// MonthlyInterestPercent são os juros de mora em percentual ao mês, proporcionais aos dias de atraso em um mês
+	// de 30 dias
+	MonthlyInterestPercent float64
+}
+
+// Charges calcula a multa e os juros de amount pago em paidAt com vencimento em dueDate; as datas são comparadas
+// em UTC e o pagamento no próprio dia do vencimento não tem encargos
+func (p LateFeePolicy) Charges(amount float64, dueDate, paidAt time.Time) (fine, interest float64, daysLate int) {
+	daysLate = int(dueDay(paidAt).Sub(dueDay(dueDate)).Hours() / 24)
+	if daysLate <= 0 {
+		return 0, 0, 0
+	}
+	fine = fromCents(toCents(amount * p.FinePercent / 100))
+	interest = fromCents(toCents(amount * p.MonthlyInterestPercent / 100 * float64(daysLate) / 30))
+	return fine, interest, daysLate
+}
+
+// dueDay é o início do dia de t em UTC
+func dueDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// SetDueDate marca a fatura para esperar a confirmação do pagamento até o vencimento dueDate (AAAA-MM-DD), em vez
+// de ser processada na criação; vazio processa a fatura normalmente
+// Retorna ErrInvalidDueDate se dueDate não for uma data entre hoje e MaxDueDays dias depois da criação
+func (i *Invoice) SetDueDate(dueDate string) error {
+	// O vencimento e os encargos enviados nos metadados da requisição não valem; só os do campo due_date
+	delete(i.Metadata, DueDateMetadataKey)
+	delete(i.Metadata, DaysLateMetadataKey)
+	delete(i.Metadata, LateFineMetadataKey)
+	delete(i.Metadata, LateInterestMetadataKey)
+	if dueDate == "" {
+		return nil
+	}
+
+	due, err := time.Parse(DueDateLayout, dueDate)
+	if err != nil {
+		return ErrInvalidDueDate
+	}
+	today := dueDay(i.CreatedAt)
+	if due.Before(today) || due.After(today.AddDate(0, 0, MaxDueDays)) {
+		return ErrInvalidDueDate
+	}
+
+	if i.Metadata == nil {
+		i.Metadata = map[string]string{}
+	}
+	i.Metadata[DueDateMetadataKey] = due.Format(DueDateLayout)
+	return nil
+}
+
+// DueDate é o vencimento da fatura, zero quando ela não tem vencimento
+func (i *Invoice) DueDate() time.Time {
+	due, err := time.Parse(DueDateLayout, i.Metadata[DueDateMetadataKey])
+	if err != nil {
+		return time.Time{}
+	}
+	return due
+}
+
+// AwaitingPayment indica se a fatura tem vencimento e ainda espera a confirmação do pagamento
+func (i *Invoice) AwaitingPayment() bool {
+	return i.Status == StatusPending && !i.DueDate().IsZero()
+}
+
+// ConfirmPayment aprova a fatura que espera o pagamento, somando ao valor a multa e os juros do atraso de paidAt
+// em relação ao vencimento, que ficam registrados nos metadados
+// Retorna ErrInvalidStatus se a fatura não espera pagamento e ErrInvalidPaymentDate se paidAt for anterior à
+// criação da fatura ou futuro
+func (i *Invoice) ConfirmPayment(policy LateFeePolicy, paidAt time.Time) error {
+	if !i.AwaitingPayment() {
+		return ErrInvalidStatus
+	}
+	if dueDay(paidAt).Before(dueDay(i.CreatedAt)) || paidAt.After(time.Now()) {
+		return ErrInvalidPaymentDate
+	}
+
+	fine, interest, daysLate := policy.Charges(i.Amount, i.DueDate(), paidAt)
+	if err := i.TransitionTo(StatusApproved); err != nil {
+		return err
+	}
+	if daysLate > 0 {
+		i.Metadata[DaysLateMetadataKey] = strconv.Itoa(daysLate)
+		i.Metadata[LateFineMetadataKey] = strconv.FormatFloat(fine, 'f', 2, 64)
+		i.Metadata[LateInterestMetadataKey] = strconv.FormatFloat(interest, 'f', 2, 64)
+		i.Amount = fromCents(toCents(i.Amount) + toCents(fine) + toCents(interest))
+	}
+	return nil
+}
+
+// LateCharges são a multa e os juros somados ao valor da fatura paga com atraso, zero quando ela não atrasou
+func (i *Invoice) LateCharges() (fine, interest float64, daysLate int) {
+	daysLate, err := strconv.Atoi(i.Metadata[DaysLateMetadataKey])
+	if err != nil || daysLate <= 0 {
+		return 0, 0, 0
+	}
+	fine, _ = strconv.ParseFloat(i.Metadata[LateFineMetadataKey], 64)
+	interest, _ = strconv.ParseFloat(i.Metadata[LateInterestMetadataKey], 64)
+	return fine, interest, daysLate
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLateFeePolicyCharges(t *testing.T) {
+	policy := LateFeePolicy{FinePercent: 2, MonthlyInterestPercent: 1}
+	due := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	brt := time.FixedZone("BRT", -3*60*60)
+
+	tests := []struct {
+		name         string
+		amount       float64
+		paidAt       time.Time
+		wantFine     float64
+		wantInterest float64
+		wantDays     int
+	}{
+		{"before the due date", 100, due.AddDate(0, 0, -1), 0, 0, 0},
+		{"on the due date", 100, due.Add(23*time.Hour + 59*time.Minute), 0, 0, 0},
+		{"one day late", 100, due.AddDate(0, 0, 1), 2, 0.03, 1},
+		{"one month late", 100, due.AddDate(0, 0, 30), 2, 1, 30},
+		{"forty five days late", 100, due.AddDate(0, 0, 45), 2, 1.5, 45},
+		{"rounded to cents", 33.33, due.AddDate(0, 0, 10), 0.67, 0.11, 10},
+		{"compared in UTC", 100, time.Date(2026, 1, 10, 22, 0, 0, 0, brt), 2, 0.03, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fine, interest, days := policy.Charges(tt.amount, due, tt.paidAt)
+			if fine != tt.wantFine || interest != tt.wantInterest || days != tt.wantDays {
+				t.Errorf("Charges = (%v, %v, %d), want (%v, %v, %d)", fine, interest, days, tt.wantFine, tt.wantInterest, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestInvoiceSetDueDate(t *testing.T) {
+	createdAt := time.Date(2026, 1, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dueDate string
+		want    string
+		wantErr error
+	}{
+		{"no due date", "", "", nil},
+		{"same day", "2026-01-10", "2026-01-10", nil},
+		{"last allowed day", "2027-01-10", "2027-01-10", nil},
+		{"past", "2026-01-09", "", ErrInvalidDueDate},
+		{"too far", "2027-01-11", "", ErrInvalidDueDate},
+		{"bad format", "10/01/2026", "", ErrInvalidDueDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Vencimento e encargos enviados nos metadados nunca sobrevivem
+			invoice := &Invoice{CreatedAt: createdAt, Metadata: map[string]string{
+				DueDateMetadataKey:  "2026-02-01",
+				LateFineMetadataKey: "0.01",
+			}}
+			if err := invoice.SetDueDate(tt.dueDate); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetDueDate error = %v, want %v", err, tt.wantErr)
+			}
+			if got := invoice.Metadata[DueDateMetadataKey]; got != tt.want {
+				t.Errorf("due date = %q, want %q", got, tt.want)
+			}
+			if _, ok := invoice.Metadata[LateFineMetadataKey]; ok {
+				t.Error("late fine from the request metadata was kept")
+			}
+		})
+	}
+}
+
+func TestInvoiceConfirmPayment(t *testing.T) {
+	policy := LateFeePolicy{FinePercent: 2, MonthlyInterestPercent: 1}
+	createdAt := time.Now().AddDate(0, 0, -40)
+	newAwaiting := func() *Invoice {
+		invoice := &Invoice{Amount: 100, Status: StatusPending, CreatedAt: createdAt, Metadata: map[string]string{}}
+		if err := invoice.SetDueDate(createdAt.UTC().AddDate(0, 0, 5).Format(DueDateLayout)); err != nil {
+			t.Fatal(err)
+		}
+		return invoice
+	}
+
+	invoice := newAwaiting()
+	if err := invoice.ConfirmPayment(policy, time.Now()); err != nil {
+		t.Fatalf("ConfirmPayment: %v", err)
+	}
+	if invoice.Status != StatusApproved {
+		t.Errorf("Status = %q, want %q", invoice.Status, StatusApproved)
+	}
+	fine, interest, days := invoice.LateCharges()
+	if days != 35 || fine != 2 || interest != 1.17 {
+		t.Errorf("LateCharges = (%v, %v, %d), want (2, 1.17, 35)", fine, interest, days)
+	}
+	if invoice.Amount != 103.17 {
+		t.Errorf("Amount = %v, want 103.17", invoice.Amount)
+	}
+	if err := invoice.ConfirmPayment(policy, time.Now()); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("second ConfirmPayment error = %v, want ErrInvalidStatus", err)
+	}
+
+	onTime := newAwaiting()
+	if err := onTime.ConfirmPayment(policy, createdAt.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("ConfirmPayment on time: %v", err)
+	}
+	if onTime.Amount != 100 {
+		t.Errorf("Amount paid on time = %v, want 100", onTime.Amount)
+	}
+	if _, _, days := onTime.LateCharges(); days != 0 {
+		t.Errorf("days late paid on time = %d, want 0", days)
+	}
+
+	for name, paidAt := range map[string]time.Time{
+		"before creation": createdAt.AddDate(0, 0, -1),
+		"in the future":   time.Now().Add(time.Hour),
+	} {
+		if err := newAwaiting().ConfirmPayment(policy, paidAt); !errors.Is(err, ErrInvalidPaymentDate) {
+			t.Errorf("ConfirmPayment %s error = %v, want ErrInvalidPaymentDate", name, err)
+		}
+	}
+
+	withoutDueDate := &Invoice{Amount: 100, Status: StatusPending, CreatedAt: createdAt, Metadata: map[string]string{}}
+	if err := withoutDueDate.ConfirmPayment(policy, time.Now()); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("ConfirmPayment without due date error = %v, want ErrInvalidStatus", err)
+	}
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+)
+
+// LedgerEntryType é o motivo de um lançamento no razão das contas
+type LedgerEntryType string
+
+const (
+	// LedgerCommission é a comissão recebida pela plataforma sobre a transação de uma conta filha
+	LedgerCommission LedgerEntryType = "commission"
+	// LedgerCommissionWithheld é a comissão retida da conta filha, com valor negativo
+	LedgerCommissionWithheld LedgerEntryType = "commission_withheld"
+	// LedgerChargebackFee é a tarifa debitada da conta pela disputa de uma fatura, com valor negativo
+	LedgerChargebackFee LedgerEntryType = "chargeback_fee"
+	// LedgerTax são os impostos cobrados junto com a fatura aprovada, registrados na conta dona, que os recolhe
+	LedgerTax LedgerEntryType = "tax"
+	// LedgerAnticipationFee é a taxa cobrada pela antecipação do valor da fatura que aguardava o repasse, com valor
+	// negativo
+	LedgerAnticipationFee LedgerEntryType = "anticipation_fee"
+	// LedgerCredit é o valor da fatura aprovada creditado no saldo disponível da conta, na aprovação, na liberação da
+	// custódia ou na data de repasse, já descontadas as comissões e as taxas
+	LedgerCredit LedgerEntryType = "credit"
+	// LedgerRefund é a parte do reembolso debitada do saldo de uma conta que recebeu pela fatura, com valor negativo;
+	// nas recebedoras da divisão e nas plataformas, a conta dona fica em CounterpartyID
+	LedgerRefund LedgerEntryType = "refund"
+	// LedgerChargeback é o valor da disputa perdida debitado do saldo da conta dona da fatura, com valor negativo,
+	// descontado o que ainda estava retido em custódia
+	LedgerChargeback LedgerEntryType = "chargeback"
+	// LedgerAdjustment é o ajuste manual do saldo da conta, sem fatura
+	LedgerAdjustment LedgerEntryType = "adjustment"
+	// LedgerTransfer é a transferência entre contas de uma ordem permanente, negativa na conta de origem e positiva na
+	// de destino, com a outra conta em CounterpartyID
+	LedgerTransfer LedgerEntryType = "transfer"
+	// LedgerOpeningBalance é o saldo que a conta tinha na primeira conciliação, formado antes de o razão registrar
+	// todos os movimentos do saldo
+	LedgerOpeningBalance LedgerEntryType = "opening_balance"
+	// LedgerCorrection é a diferença somada ao saldo divergente pela conciliação para voltar ao valor do razão; não
+	// entra na soma, porque desfaz no saldo um movimento que nunca passou pelo razão
+	LedgerCorrection LedgerEntryType = "correction"
+)
+
+// AffectsBalance indica se o lançamento movimenta o saldo disponível da conta
+// As comissões e os impostos só detalham os créditos, que já vêm com eles descontados ou somados, e as correções da
+// conciliação só registram a volta do saldo ao razão
+func (t LedgerEntryType) AffectsBalance() bool {
+	switch t {
+	case LedgerChargebackFee, LedgerChargeback, LedgerAnticipationFee, LedgerCredit, LedgerRefund, LedgerAdjustment, LedgerTransfer, LedgerOpeningBalance:
+		return true
+	}
+	return false
+}
+
+// LedgerEntry é um lançamento no razão de uma conta, ligado à fatura que o originou
+// Amount é positivo nos créditos e negativo nos débitos; CounterpartyID é a outra conta do movimento
+type LedgerEntry struct {
+	ID             string
+	AccountID      string
+	CounterpartyID string
+	InvoiceID      string
+	Type           LedgerEntryType
+	Amount         float64
+	CreatedAt      time.Time
+}
+
+// NewLedgerEntry cria um lançamento com o valor em centavos
+func NewLedgerEntry(accountID, counterpartyID, invoiceID string, entryType LedgerEntryType, amount float64) *LedgerEntry {
+	return &LedgerEntry{
+		ID:             NewID(),
+		AccountID:      accountID,
+		CounterpartyID: counterpartyID,
+		InvoiceID:      invoiceID,
+		Type:           entryType,
+		Amount:         fromCents(toCents(amount)),
+		CreatedAt:      time.Now(),
+	}
+}
+
+// Posting é um movimento de saldo gravado de uma vez: os lançamentos no razão, as retenções criadas, as retenções
+// liberadas e o valor somado ao saldo de cada conta em Balances
+// As retenções de Releases já vêm marcadas por EscrowHold.Release ou, quando devolvidas ao pagador, por
+// EscrowHold.Cancel
+// Com Funded, os débitos de Balances precisam caber no saldo travado de cada conta; sem ele o saldo pode ficar
+// negativo, como na tarifa e no chargeback das disputas
+type Posting struct {
+	Entries  []*LedgerEntry
+	Holds    []*EscrowHold
+	Releases []*EscrowHold
+	Balances map[string]float64
+	Funded   bool
+}
+
+// AccountIDs retorna as contas com saldo alterado em ordem, a ordem em que os saldos são travados
+func (p *Posting) AccountIDs() []string {
+	return slices.Sorted(maps.Keys(p.Balances))
+}
+
+// Settles retorna em ordem as faturas com crédito no saldo e sem retenção criada pelo próprio movimento, as que ele
+// pode liquidar; o repositório passa para StatusSettled, na mesma transação, as aprovadas sem nenhuma retenção ainda
+// retida
+func (p *Posting) Settles() []string {
+	invoiceIDs := make(map[string]bool)
+	for _, entry := range p.Entries {
+		if entry.Type == LedgerCredit && entry.InvoiceID != "" {
+			invoiceIDs[entry.InvoiceID] = true
+		}
+	}
+	for _, hold := range p.Holds {
+		delete(invoiceIDs, hold.InvoiceID)
+	}
+	return slices.Sorted(maps.Keys(invoiceIDs))
+}
+
+// Covered indica se o saldo da conta cobre o valor dela em Balances; sem Funded, e nos créditos, sempre cobre
+func (p *Posting) Covered(accountID string, balance float64) bool {
+	amount := p.Balances[accountID]
+	return !p.Funded || amount >= 0 || toCents(balance)+toCents(amount) >= 0
+}
+
+// LedgerRepository define a persistência do razão das contas
+type LedgerRepository interface {
+	// CreateBatch grava os lançamentos de uma vez
+	CreateBatch(ctx context.Context, entries []*LedgerEntry) error
+	// Post grava o movimento em uma transação, somando os valores ao saldo atual das contas; nada é gravado se uma
+	// parte falhar
+	// As faturas de Settles que ficarem sem retenção retida passam de aprovadas para liquidadas
+	// Retorna ErrAccountNotFound se uma conta de Balances não existir, ErrEscrowAlreadyReleased se uma retenção de
+	// Releases já tiver sido liberada e, com Funded, ErrInsufficientBalance se um débito não couber no saldo
+	Post(ctx context.Context, posting *Posting) error
+	// List retorna os lançamentos da conta criados em [from, to), dos mais antigos para os mais novos
+	List(ctx context.Context, accountID string, from, to time.Time) ([]*LedgerEntry, error)
+	// SumByType soma os lançamentos da conta por tipo; os tipos sem lançamentos ficam fora do mapa
+	SumByType(ctx context.Context, accountID string) (map[LedgerEntryType]float64, error)
+	// Reconcile trava a conta e recalcula o saldo dela pelo razão na mesma transação; com write, grava ali o movimento
+	// de BalanceReconciliation.Settle
+	// Retorna ErrAccountNotFound se a conta não existir
+	Reconcile(ctx context.Context, accountID string, write, correct bool) (*BalanceReconciliation, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPostingSettles(t *testing.T) {
+	tests := []struct {
+		name    string
+		posting *Posting
+		want    []string
+	}{
+		{
+			name: "credits",
+			posting: &Posting{Entries: []*LedgerEntry{
+				NewLedgerEntry("a", "", "i2", LedgerCredit, 10),
+				NewLedgerEntry("b", "", "i1", LedgerCredit, 5),
+				NewLedgerEntry("a", "", "i2", LedgerCredit, 1),
+			}},
+			want: []string{"i1", "i2"},
+		},
+		{
+			// A fatura com valor retido no próprio movimento ainda não está liquidada
+			name: "held in the same posting",
+			posting: &Posting{
+				Entries: []*LedgerEntry{NewLedgerEntry("a", "", "i1", LedgerCredit, 10)},
+				Holds:   []*EscrowHold{NewPayoutHold("b", "i1", 5, time.Now())},
+			},
+		},
+		{
+			name: "other entries",
+			posting: &Posting{Entries: []*LedgerEntry{
+				NewLedgerEntry("a", "", "i1", LedgerRefund, -10),
+				NewLedgerEntry("a", "", "i2", LedgerChargeback, -10),
+				NewLedgerEntry("a", "b", "", LedgerTransfer, 10),
+				NewLedgerEntry("a", "", "", LedgerCredit, 10),
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.posting.Settles(); !slices.Equal(got, tt.want) {
+				t.Errorf("Settles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
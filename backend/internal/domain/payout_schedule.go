@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PayoutDelays são os prazos de repasse aceitos, em dias depois da aprovação: D+0, D+1, D+14 e D+30
+var PayoutDelays = []int{0, 1, 14, 30}
+
+// PayoutSchedule é o prazo de repasse escolhido pela conta: o que ela recebe pelas faturas aprovadas só entra no
+// saldo disponível DelayDays dias depois da aprovação; 0 credita na aprovação
+type PayoutSchedule struct {
+	AccountID string
+	DelayDays int
+	UpdatedAt time.Time
+}
+
+// NewPayoutSchedule valida o prazo de repasse da conta
+// Retorna ErrInvalidPayoutSchedule se delayDays não for um dos prazos de PayoutDelays
+func NewPayoutSchedule(accountID string, delayDays int) (*PayoutSchedule, error) {
+	if !slices.Contains(PayoutDelays, delayDays) {
+		return nil, ErrInvalidPayoutSchedule
+	}
+	return &PayoutSchedule{AccountID: accountID, DelayDays: delayDays, UpdatedAt: time.Now()}, nil
+}
+
+// ParsePayoutDelay lê o prazo no formato "D+N", como "D+14", e retorna os dias
+// Retorna ErrInvalidPayoutSchedule se o formato for outro ou o prazo não for um dos de PayoutDelays
+func ParsePayoutDelay(schedule string) (int, error) {
+	days, ok := strings.CutPrefix(strings.ToUpper(strings.TrimSpace(schedule)), "D+")
+	if !ok {
+		return 0, ErrInvalidPayoutSchedule
+	}
+	delay, err := strconv.Atoi(days)
+	if err != nil || !slices.Contains(PayoutDelays, delay) {
+		return 0, ErrInvalidPayoutSchedule
+	}
+	return delay, nil
+}
+
+// Name é o prazo no formato "D+N"
+func (s *PayoutSchedule) Name() string {
+	return "D+" + strconv.Itoa(s.DelayDays)
+}
+
+// ReleaseAt é quando o valor de uma fatura aprovada em approvedAt entra no saldo: o início do dia, em UTC,
+// DelayDays dias depois da aprovação
+func (s *PayoutSchedule) ReleaseAt(approvedAt time.Time) time.Time {
+	day := approvedAt.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, s.DelayDays)
+}
+
+// PayoutScheduleRepository define a persistência dos prazos de repasse das contas
+type PayoutScheduleRepository interface {
+	Save(ctx context.Context, schedule *PayoutSchedule) error
+	// FindByAccountID retorna ErrPayoutScheduleNotFound quando a conta usa o prazo padrão
+	FindByAccountID(ctx context.Context, accountID string) (*PayoutSchedule, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PlatformLink liga uma conta filha à plataforma (marketplace) que retém uma comissão sobre o que ela recebe
+type PlatformLink struct {
+	AccountID         string
+	PlatformID        string
+	CommissionPercent float64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewPlatformLink valida e cria a ligação da conta com a plataforma
+// Retorna ErrInvalidPlatformLink se a conta for a própria plataforma ou a comissão não estiver entre 0 e 100
+func NewPlatformLink(accountID, platformID string, commissionPercent float64) (*PlatformLink, error) {
+	if platformID == "" || platformID == accountID || commissionPercent <= 0 || commissionPercent >= 100 {
+		return nil, ErrInvalidPlatformLink
+	}
+
+	now := time.Now()
+	return &PlatformLink{
+		AccountID:         accountID,
+		PlatformID:        platformID,
+		CommissionPercent: commissionPercent,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+// Commission retorna a comissão da plataforma sobre amount, arredondada para centavos
+func (l *PlatformLink) Commission(amount float64) float64 {
+	return fromCents(toCents(amount * l.CommissionPercent / 100))
+}
+
+// PlatformLinkRepository define a persistência das ligações entre contas filhas e plataformas
+type PlatformLinkRepository interface {
+	// Save cria ou substitui a ligação da conta
+	Save(ctx context.Context, link *PlatformLink) error
+	// FindByAccountID retorna ErrPlatformLinkNotFound quando a conta não é filha de uma plataforma
+	FindByAccountID(ctx context.Context, accountID string) (*PlatformLink, error)
+	// CountByPlatformID retorna quantas contas são filhas da plataforma
+	CountByPlatformID(ctx context.Context, platformID string) (int64, error)
+	// Delete retorna ErrPlatformLinkNotFound quando a conta não é filha de uma plataforma
+	Delete(ctx context.Context, accountID string) error
+}
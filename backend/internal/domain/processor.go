@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DeclineReason é o motivo informado pelo processador para recusar a fatura
+type DeclineReason string
+
+// Motivos de recusa informados pelo SandboxProcessor
+const (
+	DeclineGeneric                DeclineReason = "generic_decline"
+	DeclineInsufficientFunds      DeclineReason = "insufficient_funds"
+	DeclineFraudulent             DeclineReason = "fraudulent"
+	DeclineAuthenticationRequired DeclineReason = "authentication_required"
+)
+
+// DeclineReasonMetadataKey guarda nos metadados da fatura recusada o motivo informado pelo processador
+const DeclineReasonMetadataKey = "decline_reason"
+
+// Authorization é a decisão do processador sobre a fatura
+// DeclineReason explica as recusas quando o processador informa o motivo
+type Authorization struct {
+	Status        Status
+	DeclineReason DeclineReason
+}
+
+// Processor autoriza as faturas que não passam pelo antifraude, aprovando ou recusando cada uma
+type Processor interface {
+	Authorize(invoice *Invoice) (Authorization, error)
+}
+
+// RandomProcessor simula o adquirente das contas em produção, aprovando 70% das faturas ao acaso
+type RandomProcessor struct{}
+
+// Authorize aprova ou recusa a fatura ao acaso, sem motivo de recusa
+func (RandomProcessor) Authorize(invoice *Invoice) (Authorization, error) {
+	randomSource := rand.New(rand.NewSource(time.Now().Unix()))
+	if randomSource.Float64() <= 0.7 {
+		return Authorization{Status: StatusApproved}, nil
+	}
+	return Authorization{Status: StatusRejected}, nil
+}
+
+// TestAPIKeyPrefix identifica os API Keys das contas em modo de teste, decididas pelo SandboxProcessor
+const TestAPIKeyPrefix = "test_"
+
+// IsTestAPIKey informa se o API Key é de uma conta em modo de teste
+func IsTestAPIKey(apiKey string) bool {
+	return strings.HasPrefix(apiKey, TestAPIKeyPrefix)
+}
+
+// Valores e finais de cartão com resultado fixo no SandboxProcessor
+const (
+	SandboxDeclineAmount = 666
+	SandboxTimeoutAmount = 999
+	SandboxDeclineSuffix = "0002"
+	SandboxTimeoutSuffix = "0119"
+)
+
+// SandboxProcessor simula o adquirente das contas em modo de teste com resultados determinísticos
+// O valor SandboxTimeoutAmount ou o cartão terminado em SandboxTimeoutSuffix simulam o adquirente fora do ar;
+// TestCard, o motivo fixo do cartão de teste da fatura (veja carddata.TestCards), recusa a fatura com ele; o valor
+// SandboxDeclineAmount ou o cartão terminado em SandboxDeclineSuffix recusam com DeclineGeneric e as demais faturas
+// são aprovadas
+type SandboxProcessor struct {
+	TestCard DeclineReason
+}
+
+// Authorize decide a fatura pelo valor e pelo cartão
+// Retorna ErrProcessorTimeout nos valores e cartões que simulam o adquirente fora do ar
+func (p SandboxProcessor) Authorize(invoice *Invoice) (Authorization, error) {
+	switch {
+	case invoice.Amount == SandboxTimeoutAmount, invoice.CardLastDigits == SandboxTimeoutSuffix:
+		return Authorization{}, ErrProcessorTimeout
+	case p.TestCard != "":
+		return Authorization{Status: StatusRejected, DeclineReason: p.TestCard}, nil
+	case invoice.Amount == SandboxDeclineAmount, invoice.CardLastDigits == SandboxDeclineSuffix:
+		return Authorization{Status: StatusRejected, DeclineReason: DeclineGeneric}, nil
+	default:
+		return Authorization{Status: StatusApproved}, nil
+	}
+}
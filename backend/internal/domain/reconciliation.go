@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// BalanceReconciliation compara o saldo gravado da conta com o recalculado pelos lançamentos do razão que
+// movimentam o saldo
+// Sem o saldo de abertura no razão, Opened é falso e o saldo gravado é tomado como certo: OpeningBalance é o que
+// falta ao razão para chegar nele
+type BalanceReconciliation struct {
+	AccountID      string
+	Balance        float64
+	LedgerBalance  float64
+	Opened         bool
+	OpeningBalance float64
+	Corrected      bool
+	CheckedAt      time.Time
+}
+
+// NewBalanceReconciliation recalcula o saldo da conta pelos lançamentos somados por tipo
+func NewBalanceReconciliation(account *Account, sums map[LedgerEntryType]float64) *BalanceReconciliation {
+	var ledger int64
+	for entryType, sum := range sums {
+		if entryType.AffectsBalance() {
+			ledger += toCents(sum)
+		}
+	}
+
+	reconciliation := &BalanceReconciliation{
+		AccountID: account.ID,
+		Balance:   fromCents(toCents(account.Balance)),
+		CheckedAt: time.Now(),
+	}
+	_, reconciliation.Opened = sums[LedgerOpeningBalance]
+	if !reconciliation.Opened {
+		reconciliation.OpeningBalance = fromCents(toCents(account.Balance) - ledger)
+		ledger = toCents(account.Balance)
+	}
+	reconciliation.LedgerBalance = fromCents(ledger)
+	return reconciliation
+}
+
+// Drift é o saldo gravado menos o do razão; positivo quando a conta tem mais saldo do que o razão justifica
+func (r *BalanceReconciliation) Drift() float64 {
+	return fromCents(toCents(r.Balance) - toCents(r.LedgerBalance))
+}
+
+// Drifted indica se o saldo gravado diverge do razão
+func (r *BalanceReconciliation) Drifted() bool {
+	return toCents(r.Balance) != toCents(r.LedgerBalance)
+}
+
+// Settle retorna o movimento que fecha a conciliação e marca o que ele grava: sem o saldo de abertura, o lançamento
+// dele, que não altera o saldo; com correct e o saldo divergente, a diferença somada ao saldo com um lançamento
+// LedgerCorrection, sem sobrescrevê-lo; nil se não há nada a gravar
+func (r *BalanceReconciliation) Settle(correct bool) *Posting {
+	if !r.Opened {
+		r.Opened = true
+		return &Posting{Entries: []*LedgerEntry{NewLedgerEntry(r.AccountID, "", "", LedgerOpeningBalance, r.OpeningBalance)}}
+	}
+	if !correct || !r.Drifted() {
+		return nil
+	}
+	r.Corrected = true
+	entry := NewLedgerEntry(r.AccountID, "", "", LedgerCorrection, -r.Drift())
+	return &Posting{Entries: []*LedgerEntry{entry}, Balances: map[string]float64{r.AccountID: entry.Amount}}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "testing"
+
+func TestBalanceReconciliationSettle(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     float64
+		sums        map[LedgerEntryType]float64
+		correct     bool
+		wantType    LedgerEntryType
+		wantAmount  float64
+		wantBalance float64
+	}{
+		{"opening balance", 100, map[LedgerEntryType]float64{LedgerCredit: 60}, true, LedgerOpeningBalance, 40, 0},
+		{"in sync", 100, map[LedgerEntryType]float64{LedgerOpeningBalance: 40, LedgerCredit: 60}, true, "", 0, 0},
+		{"drift without correct", 110, map[LedgerEntryType]float64{LedgerOpeningBalance: 40, LedgerCredit: 60}, false, "", 0, 0},
+		{"extra balance", 110, map[LedgerEntryType]float64{LedgerOpeningBalance: 40, LedgerCredit: 60}, true, LedgerCorrection, -10, -10},
+		{"missing balance", 90.5, map[LedgerEntryType]float64{LedgerOpeningBalance: 40, LedgerCredit: 60}, true, LedgerCorrection, 9.5, 9.5},
+		// As correções anteriores não entram na soma
+		{"after a correction", 100, map[LedgerEntryType]float64{LedgerOpeningBalance: 40, LedgerCredit: 60, LedgerCorrection: -10}, true, "", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciliation := NewBalanceReconciliation(&Account{ID: "a", Balance: tt.balance}, tt.sums)
+			posting := reconciliation.Settle(tt.correct)
+			if tt.wantType == "" {
+				if posting != nil {
+					t.Fatalf("Settle = %+v, want nil", posting)
+				}
+				return
+			}
+			if posting == nil || len(posting.Entries) != 1 {
+				t.Fatalf("Settle = %+v, want one %q entry", posting, tt.wantType)
+			}
+			if entry := posting.Entries[0]; entry.Type != tt.wantType || entry.Amount != tt.wantAmount {
+				t.Errorf("entry = %q %v, want %q %v", entry.Type, entry.Amount, tt.wantType, tt.wantAmount)
+			}
+			if got := posting.Balances["a"]; got != tt.wantBalance {
+				t.Errorf("balance delta = %v, want %v", got, tt.wantBalance)
+			}
+			if !reconciliation.Opened {
+				t.Error("Opened = false after Settle")
+			}
+			if reconciliation.Corrected != (tt.wantType == LedgerCorrection) {
+				t.Errorf("Corrected = %v", reconciliation.Corrected)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+// RefundPolicy define a partir de que valor os reembolsos da conta precisam da aprovação de um segundo usuário
+// Reembolsos acima de ApprovalThreshold ficam pendentes até a aprovação; 0 dispensa a aprovação
+type RefundPolicy struct {
+	AccountID         string
+	ApprovalThreshold float64
+	UpdatedAt         time.Time
+}
+
+// NewRefundPolicy valida o limite de aprovação, que não pode ser negativo
+// Retorna ErrInvalidRefundPolicy se o limite for inválido
+func NewRefundPolicy(accountID string, approvalThreshold float64) (*RefundPolicy, error) {
+	if approvalThreshold < 0 {
+		return nil, ErrInvalidRefundPolicy
+	}
+	return &RefundPolicy{AccountID: accountID, ApprovalThreshold: approvalThreshold, UpdatedAt: time.Now()}, nil
+}
+
+// RequiresApproval indica se um reembolso de amount precisa ser aprovado por outro usuário
+func (p *RefundPolicy) RequiresApproval(amount float64) bool {
+	return p.ApprovalThreshold > 0 && toCents(amount) > toCents(p.ApprovalThreshold)
+}
+
+// RefundPolicyRepository define a persistência das políticas de reembolso das contas
+type RefundPolicyRepository interface {
+	Save(ctx context.Context, policy *RefundPolicy) error
+	// FindByAccountID retorna ErrRefundPolicyNotFound quando a conta não exige aprovação
+	FindByAccountID(ctx context.Context, accountID string) (*RefundPolicy, error)
+}
+
+// RefundStatus é a situação de um reembolso
+type RefundStatus string
+
+const (
+	// RefundPending aguarda a aprovação de outro usuário até ExpiresAt
+	RefundPending RefundStatus = "pending"
+	// RefundCompleted já foi debitado do saldo da conta
+	RefundCompleted RefundStatus = "completed"
+	// RefundRejected foi recusado por um usuário da conta
+	RefundRejected RefundStatus = "rejected"
+	// RefundExpired não foi aprovado dentro do prazo
+	RefundExpired RefundStatus = "expired"
+)
+
+// IsValid indica se a situação é conhecida
+func (s RefundStatus) IsValid() bool {
+	return s == RefundPending || s == RefundCompleted || s == RefundRejected || s == RefundExpired
+}
+
+// Refund devolve ao pagador parte ou todo o valor de uma fatura aprovada, debitando o saldo da conta dona
+// RequestedBy e DecidedBy identificam quem pediu e quem aprovou, recusou ou deixou vencer o pedido
+type Refund struct {
+	ID          string
+	InvoiceID   string
+	AccountID   string
+	Amount      float64
+	Reason      string
+	Status      RefundStatus
+	RequestedBy string
+	DecidedBy   string
+	DecidedAt   *time.Time
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// NewRefund cria o reembolso de amount da fatura pedido por requestedBy, já concluído
+// Retorna ErrInvalidRefund se amount não for positivo ou passar de remaining, o valor da fatura ainda não
+// reembolsado
+func NewRefund(invoice *Invoice, amount, remaining float64, reason, requestedBy string) (*Refund, error) {
+	if toCents(amount) <= 0 || toCents(amount) > toCents(remaining) {
+		return nil, ErrInvalidRefund
+	}
+	now := time.Now()
+	return &Refund{
+		ID:          NewID(),
+		InvoiceID:   invoice.ID,
+		AccountID:   invoice.AccountID,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      RefundCompleted,
+		RequestedBy: requestedBy,
+		DecidedBy:   requestedBy,
+		DecidedAt:   &now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// RequireApproval deixa o reembolso pendente da aprovação de outro usuário por ttl
+func (r *Refund) RequireApproval(ttl time.Duration) {
+	expiresAt := r.CreatedAt.Add(ttl)
+	r.Status = RefundPending
+	r.DecidedBy = ""
+	r.DecidedAt = nil
+	r.ExpiresAt = &expiresAt
+}
+
+// Decide registra a decisão sobre o reembolso pendente
+// Retorna ErrRefundAlreadyDecided se ele não estiver pendente ou, vencido o prazo, se a decisão não for expirá-lo, e
+// ErrRefundSelfApproval se quem pediu tentar aprovar
+func (r *Refund) Decide(status RefundStatus, decidedBy string) error {
+	now := time.Now()
+	if r.Status != RefundPending || (status != RefundExpired && r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)) {
+		return ErrRefundAlreadyDecided
+	}
+	if status == RefundCompleted && decidedBy == r.RequestedBy {
+		return ErrRefundSelfApproval
+	}
+	r.Status = status
+	r.DecidedBy = decidedBy
+	r.DecidedAt = &now
+	return nil
+}
+
+// Posting monta o movimento do reembolso concluído: cada conta que recebeu pela fatura, a dona, as recebedoras da
+// divisão e as plataformas pela comissão, devolve a parte do reembolso proporcional ao que recebeu, com um
+// lançamento LedgerRefund; os centavos do arredondamento ficam com a conta dona
+// payouts é quanto cada conta recebeu pela fatura; sem nada recebido, a conta dona devolve tudo
+// Os débitos são Funded, para que nenhum saldo fique negativo
+func (r *Refund) Posting(payouts map[string]float64) *Posting {
+	posting := &Posting{Balances: map[string]float64{}, Funded: true}
+	var total int64
+	for _, amount := range payouts {
+		if toCents(amount) > 0 {
+			total += toCents(amount)
+		}
+	}
+
+	amount := toCents(r.Amount)
+	shares := make(map[string]int64, len(payouts)+1)
+	remainder := amount
+	if total > 0 {
+		for _, accountID := range slices.Sorted(maps.Keys(payouts)) {
+			if paid := toCents(payouts[accountID]); paid > 0 {
+				shares[accountID] = int64(math.Floor(float64(amount) * float64(paid) / float64(total)))
+				remainder -= shares[accountID]
+			}
+		}
+	}
+	shares[r.AccountID] += remainder
+
+	for _, accountID := range slices.Sorted(maps.Keys(shares)) {
+		if shares[accountID] == 0 {
+			continue
+		}
+		counterpartyID := ""
+		if accountID != r.AccountID {
+			counterpartyID = r.AccountID
+		}
+		entry := NewLedgerEntry(accountID, counterpartyID, r.InvoiceID, LedgerRefund, -fromCents(shares[accountID]))
+		posting.Entries = append(posting.Entries, entry)
+		posting.Balances[accountID] = entry.Amount
+	}
+	return posting
+}
+
+// CheckRefundable confere se o reembolso ainda cabe na fatura, com refunded já reembolsado ou pendente
+// Retorna ErrTransactionAlreadyRefunded se a fatura já foi toda reembolsada, ErrInvalidStatus se ela não estiver
+// aprovada ou liquidada e ErrInvalidRefund se o valor passar do que falta
+func (r *Refund) CheckRefundable(invoice *Invoice, refunded float64) error {
+	if invoice.Status == StatusRefunded {
+		return ErrTransactionAlreadyRefunded
+	}
+	if !invoice.Status.IsPaid() {
+		return ErrInvalidStatus
+	}
+	remaining := toCents(invoice.Amount) - toCents(refunded)
+	if remaining <= 0 {
+		return ErrTransactionAlreadyRefunded
+	}
+	if toCents(r.Amount) > remaining {
+		return ErrInvalidRefund
+	}
+	return nil
+}
+
+// FullyRefunded indica se os reembolsos concluídos, somando completed, já cobrem todo o valor da fatura
+func FullyRefunded(invoice *Invoice, completed float64) bool {
+	return toCents(completed) >= toCents(invoice.Amount)
+}
+
+// RefundRepository define a persistência dos reembolsos
+// Create e Decide travam a fatura para conferir o limite e gravam na mesma transação o reembolso, o movimento do
+// débito, quando houver, e a passagem da fatura para StatusRefunded quando os concluídos cobrirem todo o valor
+type RefundRepository interface {
+	// Create grava o reembolso e, se posting não for nil, o movimento do débito
+	// Retorna os erros de CheckRefundable, somando os reembolsos concluídos e pendentes da fatura, e os de
+	// LedgerRepository.Post, como ErrInsufficientBalance
+	Create(ctx context.Context, refund *Refund, posting *Posting) error
+	// FindByID retorna ErrRefundNotFound quando o reembolso não existe
+	FindByID(ctx context.Context, id string) (*Refund, error)
+	// List retorna até limit reembolsos da conta na situação informada, ou em todas com status vazio, dos mais
+	// novos para os mais antigos
+	List(ctx context.Context, accountID string, status RefundStatus, limit int) ([]*Refund, error)
+	// ListExpired retorna até limit reembolsos pendentes com ExpiresAt até before, dos que venceram primeiro
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*Refund, error)
+	// Decide grava a decisão se o reembolso ainda estiver pendente e, se posting não for nil, o movimento do débito
+	// Retorna ErrRefundAlreadyDecided quando outra decisão chegou antes e, na aprovação, ErrInvalidStatus se a
+	// fatura não estiver mais aprovada e os erros de LedgerRepository.Post, como ErrInsufficientBalance
+	Decide(ctx context.Context, refund *Refund, posting *Posting) error
+	// SumByInvoiceID soma os reembolsos concluídos e pendentes da fatura
+	SumByInvoiceID(ctx context.Context, invoiceID string) (float64, error)
+}
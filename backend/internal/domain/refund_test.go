@@ -0,0 +1,79 @@
+package domain
+
+import "testing"
+
+func TestRefundPosting(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		payouts map[string]float64
+		want    map[string]float64
+	}{
+		{"owner only", 40, map[string]float64{"owner": 100}, map[string]float64{"owner": -40}},
+		{"nothing paid", 40, nil, map[string]float64{"owner": -40}},
+		{
+			name:    "split and commission",
+			amount:  50,
+			payouts: map[string]float64{"owner": 45, "recipient": 40, "platform": 5},
+			want:    map[string]float64{"owner": -25.01, "recipient": -22.22, "platform": -2.77},
+		},
+		{
+			// O arredondamento fica com a conta dona, mesmo quando ela não recebeu nada
+			name:    "rounding without the owner",
+			amount:  10,
+			payouts: map[string]float64{"a": 1, "b": 1, "c": 1},
+			want:    map[string]float64{"a": -3.33, "b": -3.33, "c": -3.33, "owner": -0.01},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund := &Refund{AccountID: "owner", InvoiceID: "i", Amount: tt.amount}
+			posting := refund.Posting(tt.payouts)
+			if !posting.Funded {
+				t.Error("Funded = false, want true")
+			}
+			if len(posting.Balances) != len(tt.want) {
+				t.Fatalf("Balances = %v, want %v", posting.Balances, tt.want)
+			}
+			var total int64
+			for accountID, amount := range tt.want {
+				if got := posting.Balances[accountID]; got != amount {
+					t.Errorf("balance delta of %s = %v, want %v", accountID, got, amount)
+				}
+				total += toCents(amount)
+			}
+			if total != -toCents(tt.amount) {
+				t.Errorf("debits sum to %v, want %v", fromCents(total), -tt.amount)
+			}
+			for _, entry := range posting.Entries {
+				if entry.Type != LedgerRefund || entry.Amount != tt.want[entry.AccountID] {
+					t.Errorf("entry = %q %v for %s, want %q %v", entry.Type, entry.Amount, entry.AccountID, LedgerRefund, tt.want[entry.AccountID])
+				}
+			}
+		})
+	}
+}
+
+func TestPostingCovered(t *testing.T) {
+	posting := &Posting{Balances: map[string]float64{"a": -10, "b": 5}, Funded: true}
+	tests := []struct {
+		accountID string
+		balance   float64
+		want      bool
+	}{
+		{"a", 10, true},
+		{"a", 9.99, false},
+		{"b", -3, true},
+	}
+	for _, tt := range tests {
+		if got := posting.Covered(tt.accountID, tt.balance); got != tt.want {
+			t.Errorf("Covered(%s, %v) = %v, want %v", tt.accountID, tt.balance, got, tt.want)
+		}
+	}
+
+	// Sem Funded o débito pode deixar o saldo negativo
+	posting.Funded = false
+	if !posting.Covered("a", 0) {
+		t.Error("Covered without Funded = false, want true")
+	}
+}
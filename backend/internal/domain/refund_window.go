@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxRefundWindowDays limita o prazo para reembolsar as faturas
+const MaxRefundWindowDays = 3650
+
+// RefundWindowOpen indica se a fatura ainda pode ser reembolsada em now: o prazo conta windowDays dias da criação
+// dela, e zero desativa o prazo
+func RefundWindowOpen(invoice *Invoice, windowDays int, now time.Time) bool {
+	return windowDays == 0 || now.Before(invoice.CreatedAt.AddDate(0, 0, windowDays))
+}
+
+// AccountRefundWindow substitui o prazo de reembolso padrão do gateway para a conta
+type AccountRefundWindow struct {
+	AccountID string
+	// Days é o prazo em dias, contados da criação da fatura; zero desativa o prazo
+	Days      int
+	UpdatedAt time.Time
+}
+
+// NewAccountRefundWindow valida o prazo da conta
+// Retorna ErrInvalidRefundWindow se o prazo for negativo ou passar de MaxRefundWindowDays
+func NewAccountRefundWindow(accountID string, days int) (*AccountRefundWindow, error) {
+	if days < 0 || days > MaxRefundWindowDays {
+		return nil, ErrInvalidRefundWindow
+	}
+	return &AccountRefundWindow{AccountID: accountID, Days: days, UpdatedAt: time.Now()}, nil
+}
+
+// AccountRefundWindowRepository define a persistência dos prazos de reembolso próprios das contas
+type AccountRefundWindowRepository interface {
+	Save(ctx context.Context, window *AccountRefundWindow) error
+	// FindByAccountID retorna ErrRefundWindowNotFound quando a conta usa o prazo padrão
+	FindByAccountID(ctx context.Context, accountID string) (*AccountRefundWindow, error)
+	// Delete volta a conta para o prazo padrão; não é erro se ela já usava o padrão
+	Delete(ctx context.Context, accountID string) error
+}
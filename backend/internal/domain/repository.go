@@ -1,15 +1,54 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type AccountRepository interface {
-	Save(account *Account) error
-	FindByAPIKey(apiKey string) (*Account, error)
-	FindByID(id string) (*Account, error)
-	UpdateBalance(account *Account) error
+	Save(ctx context.Context, account *Account) error
+	FindByAPIKey(ctx context.Context, apiKey string) (*Account, error)
+	FindByID(ctx context.Context, id string, opts ...FindOption) (*Account, error)
+	UpdateBalance(ctx context.Context, account *Account) error
+	UpdateRole(ctx context.Context, id string, role Role) error
+	// UpdateTenant move a conta para a organização informada; tenantID vazio tira a conta da organização
+	UpdateTenant(ctx context.Context, id, tenantID string) error
+	Delete(ctx context.Context, id string) error
+	// ListAfter retorna até limit contas não excluídas com ID maior que afterID, ordenadas pelo ID
+	ListAfter(ctx context.Context, afterID string, limit int) ([]*Account, error)
+	PurgeableRepository
 }
 
 type InvoiceRepository interface {
-	Save(invoice *Invoice) error
-	FindByID(id string) (*Invoice, error)
-	FindByAccountID(accountID string) ([]*Invoice, error)
-	UpdateStatus(invoice *Invoice) error
+	// Save grava a fatura nova, as partes dela e, se posting não for nil, o crédito da fatura aprovada em uma
+	// transação, para que uma falha não deixe saldo creditado nem partes sem a fatura; retorna os erros de
+	// LedgerRepository.Post
+	Save(ctx context.Context, invoice *Invoice, splits []*InvoiceSplit, posting *Posting) error
+	SaveBatch(ctx context.Context, invoices []*Invoice) error
+	FindByID(ctx context.Context, id string, opts ...FindOption) (*Invoice, error)
+	FindByAccountID(ctx context.Context, accountID string) ([]*Invoice, error)
+	FindByFilter(ctx context.Context, filter InvoiceFilter) ([]*Invoice, error)
+	// UpdateStatus grava o status, o valor e os metadados da fatura, que mudam juntos na confirmação do pagamento
+	// com atraso, e, se posting não for nil, o crédito da aprovação na mesma transação; retorna ErrInvalidStatus
+	// quando o status gravado não pode mudar para o da fatura (veja Status.CanTransitionTo) e os erros de
+	// LedgerRepository.Post
+	UpdateStatus(ctx context.Context, invoice *Invoice, posting *Posting) error
+	Delete(ctx context.Context, id string) error
+	// SummarizeActivity conta por conta as faturas não excluídas criadas em [from, to) e quantas delas foram recusadas
+	SummarizeActivity(ctx context.Context, from, to time.Time) ([]AccountActivity, error)
+	// SummarizeByStatus agrega por status as faturas não excluídas da conta criadas em [from, to)
+	SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) ([]StatusTotals, error)
+	// SumSpending soma as faturas não recusadas e não excluídas da conta criadas desde dayStart e desde monthStart
+	// Roda no banco principal, para que os tetos de gasto não dependam do atraso da réplica
+	SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (SpendingTotals, error)
+	// FindByPayer busca as faturas da conta do pagador, inclusive excluídas e arquivadas, sem diferenciar maiúsculas
+	FindByPayer(ctx context.Context, accountID, payerName string) ([]*Invoice, error)
+	// AnonymizePayer troca o nome do pagador por token nas faturas da conta e na auditoria delas e apaga os
+	// últimos dígitos do cartão; retorna quantas faturas foram alteradas
+	AnonymizePayer(ctx context.Context, accountID, payerName, token string) (int64, error)
+	PurgeableRepository
+}
+
+type AuditRepository interface {
+	FindByFilter(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
 }
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionRepository é implementado pelos repositórios sujeitos à política de retenção de dados
+type RetentionRepository interface {
+	// CountExpired conta os registros criados antes do instante informado
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
+	// RemoveExpired retira da tabela principal os registros criados antes do instante informado,
+	// arquivando-os ou apagando-os conforme a entidade
+	RemoveExpired(ctx context.Context, before time.Time) (int64, error)
+}
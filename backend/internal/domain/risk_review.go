@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxRiskScore é o maior score de risco enviado pelo antifraude; os scores vão de 0 (sem risco) a MaxRiskScore
+const MaxRiskScore = 100
+
+// RiskDecision é o destino de uma fatura pelo score de risco
+type RiskDecision string
+
+const (
+	RiskApprove RiskDecision = "approve"
+	RiskReject  RiskDecision = "reject"
+	RiskReview  RiskDecision = "review"
+)
+
+// RiskPolicy são os limites de score de risco de uma conta
+// Scores abaixo de ApproveBelow são aprovados, acima de RejectAbove recusados e os demais vão para revisão manual
+type RiskPolicy struct {
+	AccountID    string
+	ApproveBelow float64
+	RejectAbove  float64
+	UpdatedAt    time.Time
+}
+
+// NewRiskPolicy valida os limites, que precisam estar entre 0 e MaxRiskScore com ApproveBelow até RejectAbove
+// Retorna ErrInvalidRiskPolicy se os limites forem inválidos
+func NewRiskPolicy(accountID string, approveBelow, rejectAbove float64) (*RiskPolicy, error) {
+	if approveBelow < 0 || rejectAbove > MaxRiskScore || approveBelow > rejectAbove {
+		return nil, ErrInvalidRiskPolicy
+	}
+	return &RiskPolicy{AccountID: accountID, ApproveBelow: approveBelow, RejectAbove: rejectAbove, UpdatedAt: time.Now()}, nil
+}
+
+// Decide retorna o destino da fatura com o score informado
+func (p *RiskPolicy) Decide(score float64) RiskDecision {
+	switch {
+	case score < p.ApproveBelow:
+		return RiskApprove
+	case score > p.RejectAbove:
+		return RiskReject
+	}
+	return RiskReview
+}
+
+// RiskPolicyRepository define a persistência dos limites de score das contas
+type RiskPolicyRepository interface {
+	Save(ctx context.Context, policy *RiskPolicy) error
+	// FindByAccountID retorna ErrRiskPolicyNotFound quando a conta usa os limites padrão
+	FindByAccountID(ctx context.Context, accountID string) (*RiskPolicy, error)
+}
+
+// ReviewStatus é a situação de uma fatura na fila de revisão manual
+type ReviewStatus string
+
+const (
+	ReviewOpen     ReviewStatus = "open"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewDeclined ReviewStatus = "declined"
+)
+
+// IsValid indica se a situação é conhecida
+func (s ReviewStatus) IsValid() bool {
+	return s == ReviewOpen || s == ReviewApproved || s == ReviewDeclined
+}
+
+// InvoiceStatus é o status que a decisão da revisão aplica à fatura
+func (s ReviewStatus) InvoiceStatus() Status {
+	if s == ReviewApproved {
+		return StatusApproved
+	}
+	return StatusRejected
+}
+
+// InvoiceReview é uma fatura pendente aguardando a decisão de um analista
+// Se ninguém decidir até DueAt, o prazo decide sozinho; DecidedBy identifica o analista ou o prazo
+type InvoiceReview struct {
+	InvoiceID string
+	AccountID string
+	Amount    float64
+	Score     float64
+	Status    ReviewStatus
+	DueAt     time.Time
+	DecidedBy string
+	DecidedAt *time.Time
+	CreatedAt time.Time
+}
+
+// NewInvoiceReview abre a revisão da fatura com o prazo de sla
+func NewInvoiceReview(invoice *Invoice, score float64, sla time.Duration) *InvoiceReview {
+	now := time.Now()
+	return &InvoiceReview{
+		InvoiceID: invoice.ID,
+		AccountID: invoice.AccountID,
+		Amount:    invoice.Amount,
+		Score:     score,
+		Status:    ReviewOpen,
+		DueAt:     now.Add(sla),
+		CreatedAt: now,
+	}
+}
+
+// Decide registra a decisão da revisão
+// Retorna ErrReviewAlreadyDecided se a revisão já foi decidida
+func (r *InvoiceReview) Decide(status ReviewStatus, decidedBy string) error {
+	if r.Status != ReviewOpen {
+		return ErrReviewAlreadyDecided
+	}
+	now := time.Now()
+	r.Status = status
+	r.DecidedBy = decidedBy
+	r.DecidedAt = &now
+	return nil
+}
+
+// ReviewRepository define a persistência da fila de revisão manual
+type ReviewRepository interface {
+	// Create retorna ErrReviewExists quando a fatura já tem revisão, como em um resultado repetido do antifraude
+	Create(ctx context.Context, review *InvoiceReview) error
+	// FindByInvoiceID retorna ErrReviewNotFound quando a fatura não tem revisão
+	FindByInvoiceID(ctx context.Context, invoiceID string) (*InvoiceReview, error)
+	// List retorna até limit revisões da conta, ou de todas com accountID vazio, na situação informada ou em
+	// todas com status vazio, das mais antigas para as mais novas
+	List(ctx context.Context, accountID string, status ReviewStatus, limit int) ([]*InvoiceReview, error)
+	// ListDue retorna até limit revisões abertas com prazo até before, das que venceram primeiro
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*InvoiceReview, error)
+	// Decide grava a decisão se a revisão ainda estiver aberta
+	// Retorna ErrReviewAlreadyDecided quando outra decisão chegou antes
+	Decide(ctx context.Context, review *InvoiceReview) error
+}
@@ -0,0 +1,125 @@
+package domain
+
+import "slices"
+
+// Role é o papel atribuído a um API Key ou usuário do dashboard, que define o que ele pode fazer na conta
+type Role string
+
+const (
+	// RoleAdmin pode tudo o que o lojista pode e também ajustar o saldo da conta
+	RoleAdmin Role = "admin"
+	// RoleMerchant é o papel padrão: cria e consulta faturas e gerencia os usuários da conta
+	RoleMerchant Role = "merchant"
+	// RoleReadOnly apenas consulta a conta e as faturas
+	RoleReadOnly Role = "read_only"
+)
+
+// Permission é uma ação verificada pelas rotas autenticadas
+type Permission string
+
+const (
+	PermissionReadAccount   Permission = "accounts:read"
+	PermissionAdjustBalance Permission = "accounts:adjust_balance"
+	PermissionReadInvoices  Permission = "invoices:read"
+	PermissionWriteInvoices Permission = "invoices:write"
+	PermissionWriteRefunds  Permission = "refunds:write"
+	PermissionManageUsers   Permission = "users:manage"
+	// PermissionManageSecurity altera as restrições de segurança da conta, como a política geográfica
+	PermissionManageSecurity Permission = "security:manage"
+	// PermissionManagePrivacy atende os pedidos de titulares de dados (LGPD/GDPR): exportação e anonimização
+	PermissionManagePrivacy Permission = "privacy:manage"
+)
+
+// APIKeyScopes são as permissões que podem ser restringidas por escopo ao criar um API Key, na ordem canônica
+// As demais permissões dependem apenas do papel
+var APIKeyScopes = []Permission{
+	PermissionReadAccount,
+	PermissionReadInvoices,
+	PermissionWriteInvoices,
+	PermissionWriteRefunds,
+}
+
+// ParseScopes valida os escopos informados e os devolve sem repetição, na ordem de APIKeyScopes
+// Retorna ErrInvalidScope se algum escopo não existir
+func ParseScopes(values []string) ([]Permission, error) {
+	for _, value := range values {
+		if !slices.Contains(APIKeyScopes, Permission(value)) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	scopes := make([]Permission, 0, len(values))
+	for _, scope := range APIKeyScopes {
+		if slices.Contains(values, string(scope)) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// ScopesOrDefault trata a lista vazia de registros anteriores aos escopos como todos os escopos
+func ScopesOrDefault(scopes []Permission) []Permission {
+	if len(scopes) == 0 {
+		return slices.Clone(APIKeyScopes)
+	}
+	return scopes
+}
+
+// Allows indica se os escopos do API Key permitem a permissão
+// Com scopes nil (autenticação sem API Key, como JWT) ou permissões fora de APIKeyScopes, só o papel decide
+func Allows(scopes []Permission, permission Permission) bool {
+	if scopes == nil || !slices.Contains(APIKeyScopes, permission) {
+		return true
+	}
+	return slices.Contains(scopes, permission)
+}
+
+// rolePermissions define as permissões de cada papel
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionReadAccount,
+		PermissionAdjustBalance,
+		PermissionReadInvoices,
+		PermissionWriteInvoices,
+		PermissionWriteRefunds,
+		PermissionManageUsers,
+		PermissionManageSecurity,
+		PermissionManagePrivacy,
+	},
+	RoleMerchant: {
+		PermissionReadAccount,
+		PermissionReadInvoices,
+		PermissionWriteInvoices,
+		PermissionWriteRefunds,
+		PermissionManageUsers,
+		PermissionManageSecurity,
+		PermissionManagePrivacy,
+	},
+	RoleReadOnly: {
+		PermissionReadAccount,
+		PermissionReadInvoices,
+	},
+}
+
+// ParseRole valida o nome de um papel
+// Retorna ErrInvalidRole se o papel não existir
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// RoleOrDefault trata o papel vazio de registros anteriores aos papéis como RoleMerchant
+func RoleOrDefault(role Role) Role {
+	if role == "" {
+		return RoleMerchant
+	}
+	return role
+}
+
+// Can indica se o papel concede a permissão
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
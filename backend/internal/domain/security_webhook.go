@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// SecurityEventType é o tipo de um evento de segurança enviado ao webhook da conta
+// Todos começam com "security.", a categoria que separa esses eventos dos demais webhooks do gateway
+type SecurityEventType string
+
+const (
+	// SecurityEventFailedAuthSpike são falhas de autenticação de muitos IPs contra a mesma credencial da conta
+	SecurityEventFailedAuthSpike SecurityEventType = "security.failed_auth_spike"
+	// SecurityEventCredentialLocked é o bloqueio temporário de uma credencial da conta por falhas consecutivas
+	SecurityEventCredentialLocked SecurityEventType = "security.credential_locked"
+	// SecurityEventCountryNotAllowed é uma requisição de um país fora da política geográfica da conta
+	SecurityEventCountryNotAllowed SecurityEventType = "security.country_not_allowed"
+	// SecurityEventAccountAnomaly é uma mudança brusca no comportamento da conta
+	SecurityEventAccountAnomaly SecurityEventType = "security.account_anomaly"
+	// SecurityEventBlocklistHit é uma fatura recusada por uma entrada da lista de bloqueio global ou da conta
+	SecurityEventBlocklistHit SecurityEventType = "security.blocklist_hit"
+)
+
+// SecurityEventTypes são os tipos de eventos de segurança que a conta pode assinar
+var SecurityEventTypes = []SecurityEventType{
+	SecurityEventFailedAuthSpike,
+	SecurityEventCredentialLocked,
+	SecurityEventCountryNotAllowed,
+	SecurityEventAccountAnomaly,
+	SecurityEventBlocklistHit,
+}
+
+// SecurityWebhook é a assinatura dos eventos de segurança da conta, entregues por POST em URL
+// Com Events vazia a conta recebe todos os tipos
+type SecurityWebhook struct {
+	AccountID string
+	URL       string
+	Events    []SecurityEventType
+	UpdatedAt time.Time
+}
+
+// NewSecurityWebhook valida a assinatura: a URL precisa ser HTTPS, exceto em localhost, e os tipos conhecidos
+// Retorna ErrInvalidSecurityWebhook se a URL ou algum tipo for inválido
+func NewSecurityWebhook(accountID, rawURL string, events []SecurityEventType) (*SecurityWebhook, error) {
+	webhookURL, ok := parseWebhookURL(rawURL)
+	if !ok {
+		return nil, ErrInvalidSecurityWebhook
+	}
+
+	normalized := make([]SecurityEventType, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(SecurityEventTypes, event) {
+			return nil, ErrInvalidSecurityWebhook
+		}
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+
+	return &SecurityWebhook{
+		AccountID: accountID,
+		URL:       webhookURL,
+		Events:    normalized,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// parseWebhookURL normaliza a URL de um webhook das contas, que precisa ser HTTPS, exceto em localhost, e não pode
+// levar credenciais
+func parseWebhookURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	local := u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" || u.Hostname() == "::1"
+	if u.Scheme != "https" && !(u.Scheme == "http" && local) {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// Subscribed indica se a conta recebe eventos do tipo informado
+func (w *SecurityWebhook) Subscribed(event SecurityEventType) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+type SecurityWebhookRepository interface {
+	// Save grava a assinatura da conta, substituindo a anterior
+	Save(ctx context.Context, webhook *SecurityWebhook) error
+	// FindByAccountID retorna ErrSecurityWebhookNotFound quando a conta não tem assinatura
+	FindByAccountID(ctx context.Context, accountID string) (*SecurityWebhook, error)
+	// Delete remove a assinatura; retorna ErrSecurityWebhookNotFound quando a conta não tem assinatura
+	Delete(ctx context.Context, accountID string) error
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Períodos dos limites de gasto
+const (
+	SpendingPeriodDaily   = "daily"
+	SpendingPeriodMonthly = "monthly"
+)
+
+// SpendingLimits são os tetos de valor que uma conta pode faturar por dia e por mês; zero desativa o teto
+// Os períodos são os dias e meses do calendário em UTC
+type SpendingLimits struct {
+	Daily   float64
+	Monthly float64
+}
+
+// Enabled indica se algum teto está configurado
+func (l SpendingLimits) Enabled() bool {
+	return l.Daily > 0 || l.Monthly > 0
+}
+
+// SpendingTotals são as somas das faturas não recusadas e não excluídas da conta no dia e no mês correntes
+type SpendingTotals struct {
+	Daily   float64
+	Monthly float64
+}
+
+// SpendingPeriods retorna o início do dia e do mês em UTC do instante informado
+func SpendingPeriods(now time.Time) (dayStart, monthStart time.Time) {
+	now = now.UTC()
+	dayStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return dayStart, monthStart
+}
+
+// Check confere se amount cabe nos tetos depois das somas já faturadas
+// Retorna *TransactionLimitError do primeiro teto estourado, começando pelo diário
+func (l SpendingLimits) Check(totals SpendingTotals, amount float64, now time.Time) error {
+	dayStart, monthStart := SpendingPeriods(now)
+	if l.Daily > 0 && totals.Daily+amount > l.Daily {
+		return newTransactionLimitError(SpendingPeriodDaily, l.Daily, totals.Daily, dayStart.AddDate(0, 0, 1))
+	}
+	if l.Monthly > 0 && totals.Monthly+amount > l.Monthly {
+		return newTransactionLimitError(SpendingPeriodMonthly, l.Monthly, totals.Monthly, monthStart.AddDate(0, 1, 0))
+	}
+	return nil
+}
+
+// TransactionLimitError detalha o teto de gasto estourado: Remaining é quanto ainda pode ser faturado no período
+// e ResetsAt quando o período recomeça
+// Corresponde a ErrTransactionLimitExceeded em errors.Is
+type TransactionLimitError struct {
+	Period    string
+	Limit     float64
+	Remaining float64
+	ResetsAt  time.Time
+}
+
+func newTransactionLimitError(period string, limit, spent float64, resetsAt time.Time) *TransactionLimitError {
+	remaining := math.Max(0, math.Round((limit-spent)*100)/100)
+	return &TransactionLimitError{Period: period, Limit: limit, Remaining: remaining, ResetsAt: resetsAt}
+}
+
+func (e *TransactionLimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %.2f, %.2f remaining", ErrTransactionLimitExceeded, e.Period, e.Limit, e.Remaining)
+}
+
+func (e *TransactionLimitError) Unwrap() error {
+	return ErrTransactionLimitExceeded
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// MaxSplitRecipients limita as contas recebedoras de uma fatura
+const MaxSplitRecipients = 10
+
+// SplitRule reparte o valor de uma fatura com uma conta recebedora, por percentual ou por valor fixo
+// ChargeFee indica se a parte paga a taxa de divisão; se nenhuma regra da fatura pagar, todas pagam
+type SplitRule struct {
+	RecipientID string
+	Percentage  float64
+	Amount      float64
+	ChargeFee   bool
+}
+
+// InvoiceSplit é a parte de uma conta no valor de uma fatura, calculada na criação e creditada na aprovação
+// Amount é a parte bruta e Fee a taxa de divisão atribuída a ela; a conta recebe Net
+type InvoiceSplit struct {
+	InvoiceID   string
+	RecipientID string
+	Amount      float64
+	Fee         float64
+	CreatedAt   time.Time
+}
+
+// Net é o valor creditado à conta recebedora
+func (s *InvoiceSplit) Net() float64 {
+	return fromCents(toCents(s.Amount) - toCents(s.Fee))
+}
+
+// NewInvoiceSplits calcula as partes da fatura pelas regras, em centavos, cobrando feePercent do valor da fatura
+// como taxa de divisão
+// Os percentuais valem sobre o valor da fatura e, somados aos valores fixos, precisam fechar o valor da fatura; os
+// centavos do arredondamento vão para a primeira regra por percentual
+// A taxa é rateada entre as partes que a pagam na proporção de cada uma
+// Retorna ErrInvalidSplit se as regras forem inválidas, não fecharem o valor ou a taxa passar de alguma parte
+func NewInvoiceSplits(invoice *Invoice, rules []SplitRule, feePercent float64) ([]*InvoiceSplit, error) {
+	if len(rules) == 0 || len(rules) > MaxSplitRecipients {
+		return nil, ErrInvalidSplit
+	}
+
+	total := toCents(invoice.Amount)
+	shares := make([]int64, len(rules))
+	recipients := make(map[string]bool, len(rules))
+	var fixed, allocated int64
+	var percentage float64
+	first := -1
+	for i, rule := range rules {
+		if rule.RecipientID == "" || recipients[rule.RecipientID] {
+			return nil, ErrInvalidSplit
+		}
+		recipients[rule.RecipientID] = true
+
+		switch {
+		case rule.Percentage > 0 && rule.Amount == 0 && rule.Percentage <= 100:
+			shares[i] = int64(math.Floor(rule.Percentage * float64(total) / 100))
+			percentage += rule.Percentage
+			if first < 0 {
+				first = i
+			}
+		case rule.Amount > 0 && rule.Percentage == 0:
+			shares[i] = toCents(rule.Amount)
+			fixed += shares[i]
+		default:
+			return nil, ErrInvalidSplit
+		}
+		if shares[i] <= 0 {
+			return nil, ErrInvalidSplit
+		}
+		allocated += shares[i]
+	}
+
+	// A soma exata pode ter frações de centavo; o que o arredondamento para baixo deixou de fora vai para a
+	// primeira regra por percentual
+	if math.Abs(percentage*float64(total)/100+float64(fixed)-float64(total)) >= 1 {
+		return nil, ErrInvalidSplit
+	}
+	if first >= 0 {
+		shares[first] += total - allocated
+	} else if allocated != total {
+		return nil, ErrInvalidSplit
+	}
+
+	fees, err := splitFees(rules, shares, toCents(invoice.Amount*feePercent/100))
+	if err != nil {
+		return nil, err
+	}
+
+	splits := make([]*InvoiceSplit, len(rules))
+	now := time.Now()
+	for i, rule := range rules {
+		splits[i] = &InvoiceSplit{
+			InvoiceID:   invoice.ID,
+			RecipientID: rule.RecipientID,
+			Amount:      fromCents(shares[i]),
+			Fee:         fromCents(fees[i]),
+			CreatedAt:   now,
+		}
+	}
+	return splits, nil
+}
+
+// splitFees rateia a taxa entre as partes que a pagam, em centavos, na proporção de cada uma
+// Os centavos do arredondamento vão para a primeira parte que paga
+func splitFees(rules []SplitRule, shares []int64, fee int64) ([]int64, error) {
+	fees := make([]int64, len(shares))
+	if fee <= 0 {
+		return fees, nil
+	}
+
+	charged := false
+	for _, rule := range rules {
+		charged = charged || rule.ChargeFee
+	}
+	var base int64
+	first := -1
+	for i, rule := range rules {
+		if !charged || rule.ChargeFee {
+			base += shares[i]
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+
+	var allocated int64
+	for i, rule := range rules {
+		if charged && !rule.ChargeFee {
+			continue
+		}
+		fees[i] = fee * shares[i] / base
+		allocated += fees[i]
+	}
+	fees[first] += fee - allocated
+
+	for i := range fees {
+		if fees[i] > shares[i] {
+			return nil, ErrInvalidSplit
+		}
+	}
+	return fees, nil
+}
+
+// SplitPayouts retorna quanto cada conta recebe pela fatura aprovada: a parte líquida de cada recebedora ou, sem
+// divisão, o valor inteiro para a conta dona
+// As partes são calculadas na criação e fecham o valor dela; a diferença para o valor aprovado, como a multa e os
+// juros do pagamento com atraso ou a nova cotação da fatura em outra moeda, fica com a conta dona
+func SplitPayouts(invoice *Invoice, splits []*InvoiceSplit) map[string]float64 {
+	if len(splits) == 0 {
+		return map[string]float64{invoice.AccountID: invoice.Amount}
+	}
+
+	payouts := make(map[string]float64, len(splits)+1)
+	difference := toCents(invoice.Amount)
+	for _, split := range splits {
+		payouts[split.RecipientID] = fromCents(toCents(payouts[split.RecipientID]) + toCents(split.Net()))
+		difference -= toCents(split.Amount)
+	}
+	if difference != 0 {
+		payouts[invoice.AccountID] = fromCents(toCents(payouts[invoice.AccountID]) + difference)
+	}
+	return payouts
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// SplitRepository define a persistência das partes das faturas divididas
+type SplitRepository interface {
+	// SaveBatch grava as partes de uma ou mais faturas de uma vez
+	SaveBatch(ctx context.Context, splits []*InvoiceSplit) error
+	// FindByInvoiceID retorna as partes da fatura, ordenadas pela conta recebedora, ou nenhuma se ela não foi dividida
+	FindByInvoiceID(ctx context.Context, invoiceID string) ([]*InvoiceSplit, error)
+}
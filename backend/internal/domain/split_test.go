@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestNewInvoiceSplits(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     float64
+		rules      []SplitRule
+		feePercent float64
+		wantAmount []float64
+		wantFee    []float64
+		wantErr    error
+	}{
+		{
+			name:       "even percentages",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Percentage: 50}, {RecipientID: "b", Percentage: 50}},
+			wantAmount: []float64{50, 50},
+			wantFee:    []float64{0, 0},
+		},
+		{
+			name:       "rounding cent goes to the first percentage",
+			amount:     100.01,
+			rules:      []SplitRule{{RecipientID: "a", Amount: 0.01}, {RecipientID: "b", Percentage: 50}, {RecipientID: "c", Percentage: 49.99}},
+			wantAmount: []float64{0.01, 50.01, 49.99},
+			wantFee:    []float64{0, 0, 0},
+		},
+		{
+			name:       "thirds",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Percentage: 33.34}, {RecipientID: "b", Percentage: 33.33}, {RecipientID: "c", Percentage: 33.33}},
+			wantAmount: []float64{33.34, 33.33, 33.33},
+			wantFee:    []float64{0, 0, 0},
+		},
+		{
+			name:       "fixed and percentage",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Amount: 30}, {RecipientID: "b", Percentage: 70}},
+			wantAmount: []float64{30, 70},
+			wantFee:    []float64{0, 0},
+		},
+		{
+			name:       "fixed only",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Amount: 60}, {RecipientID: "b", Amount: 40}},
+			wantAmount: []float64{60, 40},
+			wantFee:    []float64{0, 0},
+		},
+		{
+			name:       "fee shared by every part when none pays",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Percentage: 75}, {RecipientID: "b", Percentage: 25}},
+			feePercent: 2,
+			wantAmount: []float64{75, 25},
+			wantFee:    []float64{1.5, 0.5},
+		},
+		{
+			name:       "fee charged only to the parts that pay",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Percentage: 50}, {RecipientID: "b", Percentage: 50, ChargeFee: true}},
+			feePercent: 2,
+			wantAmount: []float64{50, 50},
+			wantFee:    []float64{0, 2},
+		},
+		{
+			name:       "fee rounding cent goes to the first payer",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Percentage: 33.34}, {RecipientID: "b", Percentage: 33.33}, {RecipientID: "c", Percentage: 33.33}},
+			feePercent: 1,
+			wantAmount: []float64{33.34, 33.33, 33.33},
+			wantFee:    []float64{0.34, 0.33, 0.33},
+		},
+		{
+			name:    "percentages below the amount",
+			amount:  100,
+			rules:   []SplitRule{{RecipientID: "a", Percentage: 50}, {RecipientID: "b", Percentage: 40}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:    "fixed amounts above the amount",
+			amount:  100,
+			rules:   []SplitRule{{RecipientID: "a", Amount: 60}, {RecipientID: "b", Amount: 50}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:    "percentage and amount in one rule",
+			amount:  100,
+			rules:   []SplitRule{{RecipientID: "a", Percentage: 100, Amount: 100}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:    "duplicated recipient",
+			amount:  100,
+			rules:   []SplitRule{{RecipientID: "a", Percentage: 50}, {RecipientID: "a", Percentage: 50}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:    "missing recipient",
+			amount:  100,
+			rules:   []SplitRule{{Percentage: 100}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:    "part rounded to zero",
+			amount:  100,
+			rules:   []SplitRule{{RecipientID: "a", Percentage: 99.999}, {RecipientID: "b", Percentage: 0.001}},
+			wantErr: ErrInvalidSplit,
+		},
+		{
+			name:       "fee above a part",
+			amount:     100,
+			rules:      []SplitRule{{RecipientID: "a", Amount: 99.99}, {RecipientID: "b", Amount: 0.01, ChargeFee: true}},
+			feePercent: 1,
+			wantErr:    ErrInvalidSplit,
+		},
+		{
+			name:    "no rules",
+			amount:  100,
+			wantErr: ErrInvalidSplit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &Invoice{ID: "inv", AccountID: "owner", Amount: tt.amount}
+			splits, err := NewInvoiceSplits(invoice, tt.rules, tt.feePercent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewInvoiceSplits error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(splits) != len(tt.wantAmount) {
+				t.Fatalf("got %d splits, want %d", len(splits), len(tt.wantAmount))
+			}
+			var total int64
+			for i, split := range splits {
+				if split.InvoiceID != invoice.ID || split.RecipientID != tt.rules[i].RecipientID {
+					t.Errorf("split %d = %s/%s, want %s/%s", i, split.InvoiceID, split.RecipientID, invoice.ID, tt.rules[i].RecipientID)
+				}
+				if split.Amount != tt.wantAmount[i] || split.Fee != tt.wantFee[i] {
+					t.Errorf("split %d = %v fee %v, want %v fee %v", i, split.Amount, split.Fee, tt.wantAmount[i], tt.wantFee[i])
+				}
+				total += toCents(split.Amount)
+			}
+			// As partes sempre fecham o valor da fatura, centavo a centavo
+			if total != toCents(tt.amount) {
+				t.Errorf("splits add up to %d cents, want %d", total, toCents(tt.amount))
+			}
+		})
+	}
+}
+
+func TestNewInvoiceSplitsTooManyRecipients(t *testing.T) {
+	rules := make([]SplitRule, MaxSplitRecipients+1)
+	for i := range rules {
+		rules[i] = SplitRule{RecipientID: strconv.Itoa(i), Amount: 1}
+	}
+	invoice := &Invoice{ID: "inv", Amount: float64(len(rules))}
+	if _, err := NewInvoiceSplits(invoice, rules, 0); !errors.Is(err, ErrInvalidSplit) {
+		t.Errorf("NewInvoiceSplits error = %v, want ErrInvalidSplit", err)
+	}
+}
+
+func TestSplitPayouts(t *testing.T) {
+	invoice := &Invoice{ID: "inv", AccountID: "owner", Amount: 100}
+	if got := SplitPayouts(invoice, nil); len(got) != 1 || got["owner"] != 100 {
+		t.Errorf("SplitPayouts without splits = %v, want the whole amount to the owner", got)
+	}
+
+	splits := []*InvoiceSplit{
+		{RecipientID: "a", Amount: 75, Fee: 1.5},
+		{RecipientID: "b", Amount: 25, Fee: 0.5},
+	}
+	got := SplitPayouts(invoice, splits)
+	if len(got) != 2 || got["a"] != 73.5 || got["b"] != 24.5 {
+		t.Errorf("SplitPayouts = %v, want a=73.5 b=24.5", got)
+	}
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// MaxStandingOrderIntervalDays limita o intervalo entre as transferências de uma ordem permanente
+const MaxStandingOrderIntervalDays = 365
+
+// Motivos das transferências não feitas, avisados no webhook da ordem
+const (
+	StandingOrderInsufficientBalance = "insufficient_balance"
+	StandingOrderDestinationNotFound = "destination_not_found"
+	StandingOrderTransferFailed      = "transfer_failed"
+)
+
+// StandingOrderStatus é a situação de uma ordem permanente
+type StandingOrderStatus string
+
+const (
+	// StandingOrderActive transfere a cada período, inclusive depois de uma transferência não feita
+	StandingOrderActive StandingOrderStatus = "active"
+	// StandingOrderCancelled não transfere mais
+	StandingOrderCancelled StandingOrderStatus = "cancelled"
+)
+
+// IsValid indica se a situação é conhecida
+func (s StandingOrderStatus) IsValid() bool {
+	return s == StandingOrderActive || s == StandingOrderCancelled
+}
+
+// StandingOrder transfere parte do saldo disponível da conta para DestinationID a cada IntervalDays dias
+// Com Percent maior que zero a transferência é essa porcentagem do saldo no momento dela, como a varredura de 80%
+// do saldo para a conta principal; senão, é o valor fixo Amount, que só sai se couber no saldo
+// NextRunAt é a próxima transferência e fica vazio na ordem cancelada; uma transferência não feita não é repetida e
+// a ordem segue para o próximo período, avisando a conta pelo webhook opcional WebhookURL
+type StandingOrder struct {
+	ID            string
+	AccountID     string
+	DestinationID string
+	Percent       float64
+	Amount        float64
+	IntervalDays  int
+	Status        StandingOrderStatus
+	WebhookURL    string
+	NextRunAt     *time.Time
+	LastRunAt     *time.Time
+	// LastAmount é o valor da última transferência feita, zero quando não havia saldo a varrer
+	LastAmount float64
+	// FailedRuns conta as transferências seguidas não feitas e LastFailure o motivo da última
+	FailedRuns  int
+	LastFailure string
+	CancelledAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewStandingOrder cria a ordem ativa com a primeira transferência em startAt
+// Retorna ErrInvalidStandingOrder se o destino for a própria conta, se não houver exatamente uma entre a
+// porcentagem, de até 100, e o valor fixo, ou se o intervalo ou a URL do webhook forem inválidos
+func NewStandingOrder(accountID, destinationID string, percent, amount float64, intervalDays int, webhookURL string, startAt time.Time) (*StandingOrder, error) {
+	if destinationID == "" || destinationID == accountID {
+		return nil, ErrInvalidStandingOrder
+	}
+	if (percent > 0) == (toCents(amount) > 0) || percent < 0 || percent > 100 || amount < 0 {
+		return nil, ErrInvalidStandingOrder
+	}
+	if intervalDays <= 0 || intervalDays > MaxStandingOrderIntervalDays {
+		return nil, ErrInvalidStandingOrder
+	}
+	if webhookURL != "" {
+		var ok bool
+		if webhookURL, ok = parseWebhookURL(webhookURL); !ok {
+			return nil, ErrInvalidStandingOrder
+		}
+	}
+
+	now := time.Now()
+	nextRunAt := startAt
+	return &StandingOrder{
+		ID:            NewID(),
+		AccountID:     accountID,
+		DestinationID: destinationID,
+		Percent:       percent,
+		Amount:        fromCents(toCents(amount)),
+		IntervalDays:  intervalDays,
+		Status:        StandingOrderActive,
+		WebhookURL:    webhookURL,
+		NextRunAt:     &nextRunAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// TransferAmount calcula a transferência sobre o saldo disponível da conta: a porcentagem do saldo, arredondada
+// para baixo em centavos e zero sem saldo, ou o valor fixo
+// Retorna ErrInsufficientBalance se o valor fixo não couber no saldo
+func (o *StandingOrder) TransferAmount(balance float64) (float64, error) {
+	if o.Percent > 0 {
+		if balance <= 0 {
+			return 0, nil
+		}
+		return math.Floor(float64(toCents(balance))*o.Percent/100) / 100, nil
+	}
+	if toCents(o.Amount) > toCents(balance) {
+		return 0, ErrInsufficientBalance
+	}
+	return o.Amount, nil
+}
+
+// Run executa a ordem sobre o saldo da conta, que o repositório lê travado na mesma transação em que grava a
+// execução, e retorna o movimento da transferência, nil quando não há o que mover
+// Sem saldo para o valor fixo a execução é registrada como não feita por StandingOrderInsufficientBalance
+func (o *StandingOrder) Run(balance float64, now time.Time) *Posting {
+	amount, err := o.TransferAmount(balance)
+	if err != nil {
+		o.Failed(StandingOrderInsufficientBalance, now)
+		return nil
+	}
+	o.Transferred(amount, now)
+	if amount == 0 {
+		return nil
+	}
+	return &Posting{
+		Entries: []*LedgerEntry{
+			NewLedgerEntry(o.AccountID, o.DestinationID, "", LedgerTransfer, -amount),
+			NewLedgerEntry(o.DestinationID, o.AccountID, "", LedgerTransfer, amount),
+		},
+		Balances: map[string]float64{o.AccountID: -amount, o.DestinationID: amount},
+		Funded:   true,
+	}
+}
+
+// advance agenda a próxima transferência; os períodos que venceram com a tarefa parada não são transferidos
+func (o *StandingOrder) advance(now time.Time) {
+	next := *o.NextRunAt
+	for !next.After(now) {
+		next = next.AddDate(0, 0, o.IntervalDays)
+	}
+	o.NextRunAt = &next
+	o.LastRunAt = &now
+	o.UpdatedAt = now
+}
+
+// Transferred registra a transferência de amount e agenda a próxima
+func (o *StandingOrder) Transferred(amount float64, now time.Time) {
+	o.advance(now)
+	o.LastAmount = amount
+	o.FailedRuns = 0
+	o.LastFailure = ""
+}
+
+// Failed registra a transferência não feita pelo motivo informado e agenda a próxima
+func (o *StandingOrder) Failed(reason string, now time.Time) {
+	o.advance(now)
+	o.FailedRuns++
+	o.LastFailure = reason
+}
+
+// Cancel encerra as transferências da ordem
+// Retorna ErrStandingOrderCancelled se ela já foi cancelada
+func (o *StandingOrder) Cancel(now time.Time) error {
+	if o.Status == StandingOrderCancelled {
+		return ErrStandingOrderCancelled
+	}
+	o.Status = StandingOrderCancelled
+	o.NextRunAt = nil
+	o.CancelledAt = &now
+	o.UpdatedAt = now
+	return nil
+}
+
+// StandingOrderRepository define a persistência das ordens permanentes
+type StandingOrderRepository interface {
+	Create(ctx context.Context, order *StandingOrder) error
+	// FindByID retorna ErrStandingOrderNotFound quando a ordem não existe
+	FindByID(ctx context.Context, id string) (*StandingOrder, error)
+	// List retorna até limit ordens da conta na situação informada, ou em todas com status vazio, das mais novas
+	// para as mais antigas
+	List(ctx context.Context, accountID string, status StandingOrderStatus, limit int) ([]*StandingOrder, error)
+	// ListDue retorna até limit ordens ativas com NextRunAt até before, das mais atrasadas primeiro
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*StandingOrder, error)
+	// Update grava a ordem se a situação gravada ainda for from
+	// Retorna ErrStandingOrderChanged quando ela mudou antes
+	Update(ctx context.Context, order *StandingOrder, from StandingOrderStatus) error
+	// Run trava o saldo da conta da ordem, executa a ordem sobre ele com StandingOrder.Run e grava a execução junto
+	// com o movimento, se a ordem ainda estiver ativa; order só recebe a execução quando ela é gravada
+	// Retorna ErrStandingOrderChanged quando ela deixou de estar ativa, como no cancelamento durante uma
+	// transferência, sem mover o saldo, e ErrAccountNotFound sem a conta
+	Run(ctx context.Context, order *StandingOrder, now time.Time) error
+}
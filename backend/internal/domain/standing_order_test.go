@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStandingOrderRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		percent     float64
+		amount      float64
+		balance     float64
+		wantAmount  float64
+		wantFailure string
+	}{
+		{"sweep", 80, 0, 100.01, 80, ""},
+		{"sweep without balance", 80, 0, -5, 0, ""},
+		{"fixed amount", 0, 30, 30, 30, ""},
+		{"fixed amount over the balance", 0, 30, 29.99, 0, StandingOrderInsufficientBalance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			order, err := NewStandingOrder("a", "b", tt.percent, tt.amount, 7, "", now)
+			if err != nil {
+				t.Fatalf("NewStandingOrder: %v", err)
+			}
+			posting := order.Run(tt.balance, now)
+			if order.LastFailure != tt.wantFailure {
+				t.Errorf("LastFailure = %q, want %q", order.LastFailure, tt.wantFailure)
+			}
+			if !order.NextRunAt.After(now) {
+				t.Errorf("NextRunAt = %v, want after %v", order.NextRunAt, now)
+			}
+			if tt.wantAmount == 0 {
+				if posting != nil {
+					t.Fatalf("Run = %+v, want nil", posting)
+				}
+				return
+			}
+			if order.LastAmount != tt.wantAmount {
+				t.Errorf("LastAmount = %v, want %v", order.LastAmount, tt.wantAmount)
+			}
+			if posting == nil || !posting.Funded {
+				t.Fatalf("Run = %+v, want a funded posting", posting)
+			}
+			// O saldo travado precisa cobrir o débito da transferência
+			if !posting.Covered("a", tt.balance) {
+				t.Errorf("posting not covered by the balance %v", tt.balance)
+			}
+			if posting.Balances["a"] != -tt.wantAmount || posting.Balances["b"] != tt.wantAmount {
+				t.Errorf("Balances = %v, want -%v on a and %v on b", posting.Balances, tt.wantAmount, tt.wantAmount)
+			}
+		})
+	}
+}
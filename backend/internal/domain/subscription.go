@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Limites das assinaturas
+const (
+	// MaxSubscriptionIntervalDays limita o intervalo entre as cobranças de uma assinatura
+	MaxSubscriptionIntervalDays = 365
+	// MaxSubscriptionAmount é o maior valor cobrado por período; acima dele a fatura iria para o antifraude e o
+	// resultado da cobrança não sairia na hora
+	MaxSubscriptionAmount = 10000
+)
+
+// SubscriptionIDMetadataKey guarda nos metadados das faturas de cobrança a assinatura que as gerou
+const SubscriptionIDMetadataKey = "subscription_id"
+
+// SubscriptionStatus é a situação de uma assinatura
+type SubscriptionStatus string
+
+const (
+	// SubscriptionActive é cobrada a cada período, inclusive durante as retentativas de uma cobrança recusada
+	SubscriptionActive SubscriptionStatus = "active"
+	// SubscriptionPastDue esgotou as retentativas e só volta a ser cobrada quando a conta a retoma
+	SubscriptionPastDue SubscriptionStatus = "past_due"
+	// SubscriptionCancelled não é mais cobrada
+	SubscriptionCancelled SubscriptionStatus = "cancelled"
+)
+
+// IsValid indica se a situação é conhecida
+func (s SubscriptionStatus) IsValid() bool {
+	return s == SubscriptionActive || s == SubscriptionPastDue || s == SubscriptionCancelled
+}
+
+// DunningPolicy define as retentativas de uma cobrança recusada
+// RetryDays são os dias, contados da data da cobrança, de cada retentativa, em ordem crescente; esgotadas as
+// retentativas, a assinatura passa para FinalStatus, SubscriptionPastDue ou SubscriptionCancelled
+type DunningPolicy struct {
+	RetryDays   []int
+	FinalStatus SubscriptionStatus
+}
+
+// Subscription cobra Amount da conta no cartão do token a cada IntervalDays dias
+// DueAt é a data da cobrança do período atual e só avança com uma cobrança aprovada; NextAttemptAt é a próxima
+// tentativa, DueAt ou uma retentativa, e fica vazio quando a assinatura não está ativa
+// WebhookURL, opcional, recebe os eventos das cobranças recusadas, assinados com o API Key da conta
+type Subscription struct {
+	ID             string
+	AccountID      string
+	CardToken      string
+	CardBrand      string
+	CardLastDigits string
+	Description    string
+	Amount         float64
+	IntervalDays   int
+	Status         SubscriptionStatus
+	WebhookURL     string
+	DueAt          time.Time
+	NextAttemptAt  *time.Time
+	FailedAttempts int
+	LastInvoiceID  string
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewSubscription cria a assinatura ativa com a primeira cobrança em startAt
+// Retorna ErrInvalidSubscription se o valor, o intervalo, a descrição ou a URL do webhook forem inválidos
+func NewSubscription(accountID string, card PaymentCard, description string, amount float64, intervalDays int, webhookURL string, startAt time.Time) (*Subscription, error) {
+	if toCents(amount) <= 0 || amount > MaxSubscriptionAmount {
+		return nil, ErrInvalidSubscription
+	}
+	if intervalDays <= 0 || intervalDays > MaxSubscriptionIntervalDays || len(description) > 255 {
+		return nil, ErrInvalidSubscription
+	}
+	if webhookURL != "" {
+		var ok bool
+		if webhookURL, ok = parseWebhookURL(webhookURL); !ok {
+			return nil, ErrInvalidSubscription
+		}
+	}
+
+	now := time.Now()
+	nextAttemptAt := startAt
+	return &Subscription{
+		ID:             NewID(),
+		AccountID:      accountID,
+		CardToken:      card.Token,
+		CardBrand:      card.Brand,
+		CardLastDigits: card.LastDigits,
+		Description:    description,
+		Amount:         fromCents(toCents(amount)),
+		IntervalDays:   intervalDays,
+		Status:         SubscriptionActive,
+		WebhookURL:     webhookURL,
+		DueAt:          startAt,
+		NextAttemptAt:  &nextAttemptAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// ChargeApproved encerra as retentativas e agenda a cobrança do próximo período
+// Os períodos que venceram durante as retentativas ou a inadimplência não são cobrados
+func (s *Subscription) ChargeApproved(invoiceID string, now time.Time) {
+	for !s.DueAt.After(now) {
+		s.DueAt = s.DueAt.AddDate(0, 0, s.IntervalDays)
+	}
+	nextAttemptAt := s.DueAt
+	s.Status = SubscriptionActive
+	s.NextAttemptAt = &nextAttemptAt
+	s.FailedAttempts = 0
+	s.LastInvoiceID = invoiceID
+	s.UpdatedAt = now
+}
+
+// ChargeFailed registra a cobrança recusada e agenda a próxima retentativa da política; invoiceID fica vazio
+// quando a cobrança foi barrada antes de gerar a fatura
+// Esgotadas as retentativas, a assinatura passa para a situação final da política e ChargeFailed retorna true
+func (s *Subscription) ChargeFailed(invoiceID string, policy DunningPolicy, now time.Time) bool {
+	s.FailedAttempts++
+	s.LastInvoiceID = invoiceID
+	s.UpdatedAt = now
+	if s.FailedAttempts > len(policy.RetryDays) {
+		s.Status = policy.FinalStatus
+		s.NextAttemptAt = nil
+		if s.Status == SubscriptionCancelled {
+			s.CancelledAt = &now
+		}
+		return true
+	}
+
+	nextAttemptAt := s.DueAt.AddDate(0, 0, policy.RetryDays[s.FailedAttempts-1])
+	// Com a cobrança atrasada além da data da retentativa, ela fica para o dia seguinte e não para a mesma hora
+	if !nextAttemptAt.After(now) {
+		nextAttemptAt = now.AddDate(0, 0, 1)
+	}
+	s.NextAttemptAt = &nextAttemptAt
+	return false
+}
+
+// Cancel encerra as cobranças da assinatura
+// Retorna ErrSubscriptionCancelled se ela já foi cancelada
+func (s *Subscription) Cancel(now time.Time) error {
+	if s.Status == SubscriptionCancelled {
+		return ErrSubscriptionCancelled
+	}
+	s.Status = SubscriptionCancelled
+	s.NextAttemptAt = nil
+	s.CancelledAt = &now
+	s.UpdatedAt = now
+	return nil
+}
+
+// Resume reativa a assinatura inadimplente, cobrando agora e contando os próximos períodos a partir de now
+// Retorna ErrSubscriptionCancelled se ela foi cancelada e ErrSubscriptionNotPastDue se ela já está ativa
+func (s *Subscription) Resume(now time.Time) error {
+	switch s.Status {
+	case SubscriptionCancelled:
+		return ErrSubscriptionCancelled
+	case SubscriptionActive:
+		return ErrSubscriptionNotPastDue
+	}
+	nextAttemptAt := now
+	s.Status = SubscriptionActive
+	s.DueAt = now
+	s.NextAttemptAt = &nextAttemptAt
+	s.FailedAttempts = 0
+	s.UpdatedAt = now
+	return nil
+}
+
+// SubscriptionRepository define a persistência das assinaturas
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *Subscription) error
+	// FindByID retorna ErrSubscriptionNotFound quando a assinatura não existe
+	FindByID(ctx context.Context, id string) (*Subscription, error)
+	// List retorna até limit assinaturas da conta na situação informada, ou em todas com status vazio, das mais
+	// novas para as mais antigas
+	List(ctx context.Context, accountID string, status SubscriptionStatus, limit int) ([]*Subscription, error)
+	// ListDue retorna até limit assinaturas ativas com NextAttemptAt até before, das mais atrasadas primeiro
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*Subscription, error)
+	// Update grava a assinatura se a situação gravada ainda for from
+	// Retorna ErrSubscriptionChanged quando ela mudou antes, como no cancelamento durante uma cobrança
+	Update(ctx context.Context, subscription *Subscription, from SubscriptionStatus) error
+}
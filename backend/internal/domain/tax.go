@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// TaxAmountMetadataKey é a chave dos metadados da fatura com o total dos impostos somados ao valor na criação
+const TaxAmountMetadataKey = "tax_amount"
+
+// MaxTaxNameLength limita o nome de cada imposto
+const MaxTaxNameLength = 64
+
+// TaxLine é uma linha tributável da fatura, com o valor já descontado dos cupons
+type TaxLine struct {
+	Description string
+	Amount      float64
+}
+
+// TaxRequest são as linhas da fatura enviadas à calculadora de impostos
+type TaxRequest struct {
+	AccountID string
+	InvoiceID string
+	Lines     []TaxLine
+}
+
+// TaxItem é um imposto calculado sobre uma linha; Line é a posição dela em TaxRequest.Lines e Rate o percentual
+type TaxItem struct {
+	Line   int
+	Name   string
+	Rate   float64
+	Amount float64
+}
+
+// TaxCalculator calcula os impostos das linhas de uma fatura, como a alíquota fixa da configuração ou um
+// provedor externo
+type TaxCalculator interface {
+	Calculate(ctx context.Context, request TaxRequest) ([]TaxItem, error)
+}
+
+// ValidateTaxItems confere os impostos calculados para as linhas da requisição
+// Retorna ErrInvalidTax se algum item apontar para uma linha inexistente, se repetir o nome na linha, não tiver
+// nome, tiver alíquota fora de 0 a 100 ou valor negativo
+func ValidateTaxItems(request TaxRequest, items []TaxItem) error {
+	type key struct {
+		line int
+		name string
+	}
+	seen := make(map[key]bool, len(items))
+	for _, item := range items {
+		if item.Line < 0 || item.Line >= len(request.Lines) || item.Name == "" || len(item.Name) > MaxTaxNameLength {
+			return ErrInvalidTax
+		}
+		if item.Rate < 0 || item.Rate > 100 || toCents(item.Amount) < 0 || seen[key{item.Line, item.Name}] {
+			return ErrInvalidTax
+		}
+		seen[key{item.Line, item.Name}] = true
+	}
+	return nil
+}
+
+// InvoiceTax é um imposto de uma linha da fatura, calculado e somado ao valor na criação
+type InvoiceTax struct {
+	InvoiceID string
+	Line      int
+	Name      string
+	Rate      float64
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// ApplyTaxes soma ao valor da fatura os impostos calculados, registrando o total nos metadados, e retorna os
+// impostos a gravar; itens com valor zero são descartados
+func (i *Invoice) ApplyTaxes(items []TaxItem) []*InvoiceTax {
+	var taxes []*InvoiceTax
+	var total int64
+	now := time.Now()
+	for _, item := range items {
+		cents := toCents(item.Amount)
+		if cents == 0 {
+			continue
+		}
+		total += cents
+		taxes = append(taxes, &InvoiceTax{
+			InvoiceID: i.ID,
+			Line:      item.Line,
+			Name:      item.Name,
+			Rate:      item.Rate,
+			Amount:    fromCents(cents),
+			CreatedAt: now,
+		})
+	}
+	if total == 0 {
+		return nil
+	}
+
+	if i.Metadata == nil {
+		i.Metadata = map[string]string{}
+	}
+	i.Metadata[TaxAmountMetadataKey] = strconv.FormatFloat(fromCents(total), 'f', 2, 64)
+	i.Amount = fromCents(toCents(i.Amount) + total)
+	return taxes
+}
+
+// ClearTaxes remove o total de impostos enviado nos metadados da requisição; só vale o somado por ApplyTaxes
+func (i *Invoice) ClearTaxes() {
+	delete(i.Metadata, TaxAmountMetadataKey)
+}
+
+// TaxAmount é o total dos impostos somados ao valor da fatura, 0 quando ela não tem impostos
+func (i *Invoice) TaxAmount() float64 {
+	amount, err := strconv.ParseFloat(i.Metadata[TaxAmountMetadataKey], 64)
+	if err != nil || amount < 0 {
+		return 0
+	}
+	return amount
+}
+
+// InvoiceTaxRepository define a persistência dos impostos das faturas
+type InvoiceTaxRepository interface {
+	// SaveBatch grava os impostos de uma ou mais faturas de uma vez
+	SaveBatch(ctx context.Context, taxes []*InvoiceTax) error
+	// FindByInvoiceID retorna os impostos da fatura, ordenados pela linha e pelo nome, ou nenhum se ela não tem impostos
+	FindByInvoiceID(ctx context.Context, invoiceID string) ([]*InvoiceTax, error)
+}
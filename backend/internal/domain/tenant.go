@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TenantAdminKeyPrefix identifica no header X-ADMIN-KEY as chaves dos administradores das organizações
+const TenantAdminKeyPrefix = "tak_"
+
+// Limites das organizações
+const (
+	MaxTenantNameLength    = 255
+	MaxTenantLogoURLLength = 2048
+)
+
+// tenantColorPattern aceita a cor principal da marca no formato #RRGGBB
+var tenantColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TenantBranding é a marca exibida pelas telas e e-mails das contas da organização; os campos vazios usam a do
+// gateway
+type TenantBranding struct {
+	DisplayName  string
+	LogoURL      string
+	PrimaryColor string
+	SupportEmail string
+}
+
+// Validate confere a marca: o logo precisa ser uma URL HTTPS, a cor #RRGGBB e o e-mail de suporte um endereço
+// válido
+// Retorna ErrInvalidTenant se algum campo for inválido
+func (b TenantBranding) Validate() error {
+	if len(b.DisplayName) > MaxTenantNameLength || len(b.LogoURL) > MaxTenantLogoURLLength {
+		return ErrInvalidTenant
+	}
+	if b.LogoURL != "" {
+		u, err := url.Parse(b.LogoURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidTenant
+		}
+	}
+	if b.PrimaryColor != "" && !tenantColorPattern.MatchString(b.PrimaryColor) {
+		return ErrInvalidTenant
+	}
+	if b.SupportEmail != "" {
+		address, err := mail.ParseAddress(b.SupportEmail)
+		if err != nil || address.Address != b.SupportEmail {
+			return ErrInvalidTenant
+		}
+	}
+	return nil
+}
+
+// Tenant é uma organização acima das contas nas implantações white-label
+// Os administradores da organização só enxergam as contas dela, e as contas dela usam a marca, a tarifa de
+// chargeback e os tetos de gasto configurados aqui
+type Tenant struct {
+	ID   string
+	Name string
+	// AdminKeyHash é o SHA-256 da chave dos administradores da organização, que só é exibida na criação e na troca
+	AdminKeyHash string
+	Branding     TenantBranding
+	// ChargebackFee é a tarifa das contas da organização sem tarifas próprias; nil usa a padrão do gateway
+	ChargebackFee *float64
+	// SpendingLimits são os tetos de gasto das contas da organização; nil usa os padrão do gateway
+	SpendingLimits *SpendingLimits
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewTenant cria a organização e retorna também a chave dos administradores dela, que não é guardada
+// Retorna ErrInvalidTenant se o nome estiver vazio ou for longo demais
+func NewTenant(name string) (*Tenant, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxTenantNameLength {
+		return nil, "", ErrInvalidTenant
+	}
+
+	now := time.Now()
+	tenant := &Tenant{ID: NewID(), Name: name, CreatedAt: now, UpdatedAt: now}
+	return tenant, tenant.RotateAdminKey(), nil
+}
+
+// RotateAdminKey troca a chave dos administradores da organização, invalidando a anterior, e retorna a nova
+func (t *Tenant) RotateAdminKey() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	key := TenantAdminKeyPrefix + hex.EncodeToString(b)
+	t.AdminKeyHash = HashTenantAdminKey(key)
+	t.UpdatedAt = time.Now()
+	return key
+}
+
+// HashTenantAdminKey calcula o hash guardado da chave dos administradores de uma organização
+func HashTenantAdminKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Configure substitui a marca, a tarifa de chargeback e os tetos de gasto da organização; nil volta ao padrão
+// Retorna ErrInvalidTenant se a marca for inválida ou a tarifa ou algum teto for negativo
+func (t *Tenant) Configure(branding TenantBranding, chargebackFee *float64, limits *SpendingLimits) error {
+	if err := branding.Validate(); err != nil {
+		return err
+	}
+	if chargebackFee != nil {
+		if *chargebackFee < 0 {
+			return ErrInvalidTenant
+		}
+		fee := fromCents(toCents(*chargebackFee))
+		chargebackFee = &fee
+	}
+	if limits != nil && (limits.Daily < 0 || limits.Monthly < 0) {
+		return ErrInvalidTenant
+	}
+
+	t.Branding = branding
+	t.ChargebackFee = chargebackFee
+	t.SpendingLimits = limits
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// TenantRepository define a persistência das organizações
+type TenantRepository interface {
+	Save(ctx context.Context, tenant *Tenant) error
+	// FindByID retorna ErrTenantNotFound se a organização não existir
+	FindByID(ctx context.Context, id string) (*Tenant, error)
+	// FindByAdminKeyHash retorna ErrTenantNotFound se nenhuma organização tiver a chave
+	FindByAdminKeyHash(ctx context.Context, hash string) (*Tenant, error)
+	// List retorna as organizações ordenadas pelo nome
+	List(ctx context.Context) ([]*Tenant, error)
+	// Update grava o nome, a chave, a marca, a tarifa e os tetos; retorna ErrTenantNotFound se ela não existir
+	Update(ctx context.Context, tenant *Tenant) error
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// backupCodeCount é quantos códigos de recuperação são emitidos em cada cadastro
+const backupCodeCount = 10
+
+// TwoFactor é o segundo fator (TOTP) cadastrado por quem executa operações sensíveis
+// Actor é o mesmo autor registrado na auditoria: account:<id>, user:<id>, admin:<e-mail> ou admin
+type TwoFactor struct {
+	Actor string
+	// Secret é o segredo TOTP em base32
+	Secret string
+	// BackupCodes guarda o SHA-256 dos códigos de recuperação ainda não usados
+	BackupCodes []string
+	// LastStep é o período do último código TOTP aceito, para que ele não seja reutilizado
+	LastStep int64
+	// ConfirmedAt fica nil até o primeiro código válido, que conclui o cadastro
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewTwoFactor inicia o cadastro do segundo fator do autor com o segredo informado
+// Retorna também os códigos de recuperação, que só ficam disponíveis neste momento
+func NewTwoFactor(actor, secret string) (*TwoFactor, []string, error) {
+	codes := make([]string, backupCodeCount)
+	hashes := make([]string, backupCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		codes[i] = hex.EncodeToString(b)
+		hashes[i] = hashBackupCode(codes[i])
+	}
+
+	now := time.Now()
+	return &TwoFactor{
+		Actor:       actor,
+		Secret:      secret,
+		BackupCodes: hashes,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, codes, nil
+}
+
+// IsConfirmed indica se o cadastro foi concluído
+func (t *TwoFactor) IsConfirmed() bool {
+	return t.ConfirmedAt != nil
+}
+
+// UseBackupCode consome o código de recuperação, que deixa de valer; retorna false se ele não existir
+func (t *TwoFactor) UseBackupCode(code string) bool {
+	hash := hashBackupCode(code)
+	for i, stored := range t.BackupCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			t.BackupCodes = append(t.BackupCodes[:i:i], t.BackupCodes[i+1:]...)
+			t.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+func hashBackupCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// TwoFactorKeyID identifica o segundo fator do autor nos eventos e bloqueios de autenticação
+func TwoFactorKeyID(actor string) string {
+	return "2fa:" + actor
+}
+
+type TwoFactorRepository interface {
+	Save(ctx context.Context, twoFactor *TwoFactor) error
+	// FindByActor retorna ErrTwoFactorNotEnrolled se o autor não tiver segundo fator
+	FindByActor(ctx context.Context, actor string) (*TwoFactor, error)
+	// Update grava os códigos de recuperação, o último período e a confirmação
+	Update(ctx context.Context, twoFactor *TwoFactor) error
+	Delete(ctx context.Context, actor string) error
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestTwoFactorUseBackupCode(t *testing.T) {
+	twoFactor, codes, err := NewTwoFactor("admin", "SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != backupCodeCount || len(twoFactor.BackupCodes) != backupCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(twoFactor.BackupCodes), backupCodeCount)
+	}
+	for _, hash := range twoFactor.BackupCodes {
+		for _, code := range codes {
+			if hash == code {
+				t.Fatal("backup codes are stored in plain text")
+			}
+		}
+	}
+
+	// O código vale sem diferenciar maiúsculas e com espaços, mas só uma vez
+	if !twoFactor.UseBackupCode(" " + codes[3] + " ") {
+		t.Error("UseBackupCode = false for a valid code")
+	}
+	if twoFactor.UseBackupCode(codes[3]) {
+		t.Error("UseBackupCode = true for a code already used")
+	}
+	if len(twoFactor.BackupCodes) != backupCodeCount-1 {
+		t.Errorf("%d codes left, want %d", len(twoFactor.BackupCodes), backupCodeCount-1)
+	}
+	if twoFactor.UseBackupCode("nope") {
+		t.Error("UseBackupCode = true for an unknown code")
+	}
+}
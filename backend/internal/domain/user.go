@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength é o tamanho mínimo das senhas dos usuários do dashboard
+const MinPasswordLength = 8
+
+// User é um usuário do dashboard, vinculado à conta do lojista
+// Diferente do API Key, que identifica integrações, o usuário entra com e-mail e senha e recebe JWTs
+type User struct {
+	ID           string
+	AccountID    string
+	Email        string
+	Role         Role
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewUser cria um usuário com o papel informado guardando apenas o hash bcrypt da senha
+// Retorna ErrWeakPassword se a senha for menor que MinPasswordLength
+func NewUser(accountID, email, password string, role Role) (*User, error) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &User{
+		ID:           NewID(),
+		AccountID:    accountID,
+		Email:        email,
+		Role:         role,
+		PasswordHash: hash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// CheckPassword compara a senha informada com o hash armazenado
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// HashPassword gera o hash bcrypt de uma nova senha
+// Retorna ErrWeakPassword se a senha for menor que MinPasswordLength
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", ErrWeakPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// RefreshToken é um token opaco de longa duração trocado por novos JWTs
+// Apenas o hash SHA-256 do token é armazenado; cada uso o revoga e emite outro (rotação)
+// SessionID é mantido nas rotações, de modo que a sessão aberta no login é sempre o token ativo com esse ID
+// IP e UserAgent são os do login ou da última renovação
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	SessionID string
+	TokenHash string
+	IP        string
+	UserAgent string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// IsActive indica se o token ainda pode ser usado
+func (t *RefreshToken) IsActive() bool {
+	return t.RevokedAt == nil && time.Now().Before(t.ExpiresAt)
+}
+
+type UserRepository interface {
+	Save(ctx context.Context, user *User) error
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id string) (*User, error)
+	UpdateRole(ctx context.Context, id string, role Role) error
+	// UpdatePassword troca o hash da senha; retorna ErrUserNotFound se o usuário não existir
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	SaveRefreshToken(ctx context.Context, token *RefreshToken) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// FindActiveRefreshTokens retorna os tokens não revogados e não expirados do usuário, um por sessão
+	FindActiveRefreshTokens(ctx context.Context, userID string) ([]*RefreshToken, error)
+	// RevokeRefreshToken revoga o token; retorna ErrInvalidToken se ele já estava revogado
+	RevokeRefreshToken(ctx context.Context, id string) error
+	// RevokeSession revoga o token ativo da sessão; retorna ErrSessionNotFound se a sessão do usuário não estiver ativa
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	// RevokeUserRefreshTokens revoga todas as sessões do usuário
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// VelocityLimitError detalha a regra de frequência estourada: no máximo Limit transações do mesmo cartão, pagador
+// ou conta (Dimension) a cada Window; RetryAfter é quanto falta para a janela recomeçar
+// Corresponde a ErrVelocityLimitExceeded em errors.Is
+type VelocityLimitError struct {
+	Dimension  string
+	Limit      int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *VelocityLimitError) Error() string {
+	return fmt.Sprintf("%s: at most %d per %s per %s", ErrVelocityLimitExceeded, e.Limit, e.Dimension, e.Window)
+}
+
+func (e *VelocityLimitError) Unwrap() error {
+	return ErrVelocityLimitExceeded
+}
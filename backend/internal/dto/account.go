@@ -7,25 +7,53 @@ import (
 )
 
 // CreateAccountInput representa dados para criação de conta
+// Scopes restringe as rotas do API Key criado; vazio concede todos os escopos
+// TestMode cria a conta em modo de teste, com o API Key prefixado por domain.TestAPIKeyPrefix
 type CreateAccountInput struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Scopes   []string `json:"scopes,omitempty"`
+	TestMode bool     `json:"test_mode,omitempty"`
+}
+
+// AdjustBalanceInput representa um ajuste manual de saldo; valores negativos debitam a conta
+type AdjustBalanceInput struct {
+	Amount float64 `json:"amount"`
+}
+
+// UpdateRoleInput representa a troca do papel de um API Key ou usuário
+type UpdateRoleInput struct {
+	Role string `json:"role"`
 }
 
 // AccountOutput representa dados da conta nas respostas da API
 type AccountOutput struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Balance   float64   `json:"balance"`
-	APIKey    string    `json:"api_key,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Email     string              `json:"email"`
+	Balance   float64             `json:"balance"`
+	APIKey    string              `json:"api_key,omitempty"`
+	Role      domain.Role         `json:"role"`
+	Scopes    []domain.Permission `json:"scopes"`
+	TenantID  string              `json:"tenant_id,omitempty"`
+	TestMode  bool                `json:"test_mode"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	DeletedAt *time.Time          `json:"deleted_at,omitempty"`
+	// EscrowedBalance é o valor aprovado retido em custódia, ainda fora de Balance, que é o saldo disponível
+	// Só é preenchido na consulta da própria conta
+	EscrowedBalance *float64 `json:"escrowed_balance,omitempty"`
+	// PendingBalance é o valor aprovado que aguarda a data de repasse do prazo da conta, também fora de Balance
+	PendingBalance *float64 `json:"pending_balance,omitempty"`
 }
 
 // ToAccount converte CreateAccountInput para domain.Account
 func ToAccount(input CreateAccountInput) *domain.Account {
-	return domain.NewAccount(input.Name, input.Email)
+	account := domain.NewAccount(input.Name, input.Email)
+	if input.TestMode {
+		account.APIKey = domain.TestAPIKeyPrefix + account.APIKey
+	}
+	return account
 }
 
 // FromAccount converte domain.Account para AccountOutput
@@ -36,7 +64,12 @@ func FromAccount(account *domain.Account) AccountOutput {
 		Email:     account.Email,
 		Balance:   account.Balance,
 		APIKey:    account.APIKey,
+		Role:      account.Role,
+		Scopes:    account.Scopes,
+		TenantID:  account.TenantID,
+		TestMode:  account.TestMode(),
 		CreatedAt: account.CreatedAt,
 		UpdatedAt: account.UpdatedAt,
+		DeletedAt: account.DeletedAt,
 	}
 }
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AlertRuleInput representa a definição de uma regra de alerta enviada pelo administrador
+// Window é uma duração como "5m" ou "1h"; Enabled omitido liga a regra
+type AlertRuleInput struct {
+	Name      string  `json:"name"`
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	Window    string  `json:"window"`
+	Enabled   *bool   `json:"enabled"`
+}
+
+// AlertRuleOutput representa uma regra de alerta e o resultado da última avaliação nesta instância
+type AlertRuleOutput struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Metric    domain.AlertMetric `json:"metric"`
+	Threshold float64            `json:"threshold"`
+	Window    string             `json:"window"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	State     *AlertStateOutput  `json:"state,omitempty"`
+}
+
+// AlertStateOutput é o resultado da última avaliação de uma regra
+// FiringSince só vem com a regra disparada
+type AlertStateOutput struct {
+	Firing      bool       `json:"firing"`
+	Value       float64    `json:"value"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+	FiringSince *time.Time `json:"firing_since,omitempty"`
+}
+
+// FromAlertRule converte domain.AlertRule para AlertRuleOutput, sem o estado
+func FromAlertRule(rule *domain.AlertRule) *AlertRuleOutput {
+	return &AlertRuleOutput{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		Metric:    rule.Metric,
+		Threshold: rule.Threshold,
+		Window:    rule.Window.String(),
+		Enabled:   rule.Enabled,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AmountRangeInput representa o valor mínimo e máximo aceito em cada fatura; zero desativa o limite
+type AmountRangeInput struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// AmountLimitsOutput representa as faixas de valor que valem para a conta: Default é a própria dela ou a padrão do
+// gateway e PaymentTypes a de cada meio de pagamento com limites, já estreitada sobre Default
+// UpdatedAt fica vazio enquanto a conta usa a faixa padrão
+type AmountLimitsOutput struct {
+	AccountID    string                      `json:"account_id"`
+	Default      AmountRangeInput            `json:"default"`
+	PaymentTypes map[string]AmountRangeInput `json:"payment_types,omitempty"`
+	Custom       bool                        `json:"custom"`
+	UpdatedAt    *time.Time                  `json:"updated_at,omitempty"`
+}
+
+// FromAmountLimits converte a faixa da conta, nil se ela usa a padrão, e as faixas do gateway para
+// AmountLimitsOutput
+func FromAmountLimits(accountID string, account *domain.AccountAmountLimits, limits domain.AmountLimits) *AmountLimitsOutput {
+	output := &AmountLimitsOutput{AccountID: accountID, Default: AmountRangeInput(limits.Default)}
+	var accountRange *domain.AmountRange
+	if account != nil {
+		accountRange = &account.Range
+		output.Default = AmountRangeInput(account.Range)
+		output.Custom = true
+		output.UpdatedAt = &account.UpdatedAt
+	}
+	if len(limits.PaymentTypes) > 0 {
+		output.PaymentTypes = make(map[string]AmountRangeInput, len(limits.PaymentTypes))
+		for paymentType := range limits.PaymentTypes {
+			output.PaymentTypes[paymentType] = AmountRangeInput(limits.Range(accountRange, paymentType))
+		}
+	}
+	return output
+}
+
+// AmountLimitErrorOutput representa a recusa de uma fatura fora da faixa de valor permitida
+// Max zero indica que não há máximo
+type AmountLimitErrorOutput struct {
+	Error string  `json:"error"`
+	Bound string  `json:"bound"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// FromAmountLimitError converte domain.AmountLimitError para AmountLimitErrorOutput
+func FromAmountLimitError(err *domain.AmountLimitError) *AmountLimitErrorOutput {
+	return &AmountLimitErrorOutput{Error: err.Error(), Bound: err.Bound, Min: err.Min, Max: err.Max}
+}
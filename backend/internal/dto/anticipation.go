@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AnticipationInput representa o pedido de antecipação; sem InvoiceIDs, antecipa os valores de todas as faturas
+type AnticipationInput struct {
+	InvoiceIDs []string `json:"invoice_ids"`
+}
+
+// AnticipationOutput representa uma antecipação nas respostas da API; Net é o valor creditado no saldo
+type AnticipationOutput struct {
+	ID          string    `json:"id"`
+	Amount      float64   `json:"amount"`
+	Fee         float64   `json:"fee"`
+	Net         float64   `json:"net"`
+	Holds       int       `json:"holds"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FromAnticipation converte domain.Anticipation para AnticipationOutput
+func FromAnticipation(anticipation *domain.Anticipation) *AnticipationOutput {
+	return &AnticipationOutput{
+		ID:          anticipation.ID,
+		Amount:      anticipation.Amount,
+		Fee:         anticipation.Fee,
+		Net:         anticipation.Net(),
+		Holds:       anticipation.Holds,
+		RequestedBy: anticipation.RequestedBy,
+		CreatedAt:   anticipation.CreatedAt,
+	}
+}
+
+// AnticipationQuoteOutput é a simulação da antecipação de tudo o que aguarda a data de repasse
+type AnticipationQuoteOutput struct {
+	Amount      float64 `json:"amount"`
+	Fee         float64 `json:"fee"`
+	Net         float64 `json:"net"`
+	Holds       int     `json:"holds"`
+	MonthlyRate float64 `json:"monthly_rate"`
+}
+
+// FromAnticipationQuote converte a antecipação simulada para AnticipationQuoteOutput
+func FromAnticipationQuote(quote *domain.Anticipation, monthlyRate float64) *AnticipationQuoteOutput {
+	return &AnticipationQuoteOutput{
+		Amount:      quote.Amount,
+		Fee:         quote.Fee,
+		Net:         quote.Net(),
+		Holds:       quote.Holds,
+		MonthlyRate: monthlyRate,
+	}
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AuditLogFilterInput representa os filtros da consulta de auditoria
+type AuditLogFilterInput struct {
+	Entity      string
+	EntityID    string
+	Actor       string
+	RequestID   string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+	Cursor      string
+}
+
+// AuditLogOutput representa uma entrada de auditoria nas respostas da API
+type AuditLogOutput struct {
+	ID        int64           `json:"id"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	OldValue  json.RawMessage `json:"old_value,omitempty"`
+	NewValue  json.RawMessage `json:"new_value,omitempty"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditLogPage é uma página da consulta de auditoria
+// NextCursor fica vazio na última página
+type AuditLogPage struct {
+	Entries    []*AuditLogOutput
+	NextCursor string
+}
+
+// ToAuditFilter converte AuditLogFilterInput para domain.AuditFilter
+func ToAuditFilter(input AuditLogFilterInput) domain.AuditFilter {
+	return domain.AuditFilter{
+		Entity:      input.Entity,
+		EntityID:    input.EntityID,
+		Actor:       input.Actor,
+		RequestID:   input.RequestID,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+		Limit:       input.Limit,
+	}
+}
+
+// FromAuditEntry converte domain.AuditEntry para AuditLogOutput
+func FromAuditEntry(entry *domain.AuditEntry) *AuditLogOutput {
+	return &AuditLogOutput{
+		ID:        entry.ID,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		Action:    string(entry.Action),
+		OldValue:  entry.OldValue,
+		NewValue:  entry.NewValue,
+		Actor:     entry.Actor,
+		RequestID: entry.RequestID,
+		CreatedAt: entry.CreatedAt,
+	}
+}
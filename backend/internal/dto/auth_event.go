@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AuthEventFilterInput representa os filtros da consulta de eventos de autenticação da conta
+// Success nil retorna sucessos e falhas
+type AuthEventFilterInput struct {
+	Success *bool
+	Limit   int
+	Cursor  string
+}
+
+// AuthEventOutput representa um evento de autenticação nas respostas da API
+type AuthEventOutput struct {
+	ID        string            `json:"id"`
+	KeyID     string            `json:"key_id"`
+	Method    domain.AuthMethod `json:"method"`
+	Success   bool              `json:"success"`
+	Reason    string            `json:"reason,omitempty"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Route     string            `json:"route"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuthEventPage é uma página da consulta de eventos de autenticação
+// NextCursor fica vazio na última página
+type AuthEventPage struct {
+	Events     []*AuthEventOutput
+	NextCursor string
+}
+
+// FromAuthEvent converte domain.AuthEvent para AuthEventOutput
+func FromAuthEvent(event *domain.AuthEvent) *AuthEventOutput {
+	return &AuthEventOutput{
+		ID:        event.ID,
+		KeyID:     event.KeyID,
+		Method:    event.Method,
+		Success:   event.Success,
+		Reason:    event.Reason,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Route:     event.Route,
+		CreatedAt: event.CreatedAt,
+	}
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// BlocklistEntryInput representa uma entrada enviada para a lista de bloqueio global ou da conta
+// Type é "card", "email", "document" ou "ip"; Action é "block" (padrão) ou "allow", só na lista da conta
+// O valor é guardado apenas como hash e exibido mascarado
+type BlocklistEntryInput struct {
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	Action    string     `json:"action"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// LogValue registra a entrada sem o valor, que pode ser um número de cartão
+func (input BlocklistEntryInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", input.Type),
+		slog.String("action", input.Action),
+		slog.String("reason", input.Reason),
+	)
+}
+
+// String impede que fmt exponha o valor
+func (input BlocklistEntryInput) String() string {
+	return input.LogValue().String()
+}
+
+// GoString impede que %#v exponha o valor
+func (input BlocklistEntryInput) GoString() string {
+	return input.String()
+}
+
+// Escopos das entradas nas respostas da API
+const (
+	BlocklistScopeGlobal  = "global"
+	BlocklistScopeAccount = "account"
+)
+
+// BlocklistEntryOutput representa uma entrada da lista de bloqueio nas respostas da API
+// Value é o valor mascarado; Hits e LastHitAt contam as faturas que bateram com a entrada
+type BlocklistEntryOutput struct {
+	ID        string                 `json:"id"`
+	Scope     string                 `json:"scope"`
+	Type      domain.BlocklistType   `json:"type"`
+	Value     string                 `json:"value"`
+	Action    domain.BlocklistAction `json:"action"`
+	Reason    string                 `json:"reason,omitempty"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"`
+	Hits      int64                  `json:"hits"`
+	LastHitAt *time.Time             `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// FromBlocklistEntry converte domain.BlocklistEntry para BlocklistEntryOutput
+func FromBlocklistEntry(entry *domain.BlocklistEntry) *BlocklistEntryOutput {
+	scope := BlocklistScopeAccount
+	if entry.AccountID == "" {
+		scope = BlocklistScopeGlobal
+	}
+	return &BlocklistEntryOutput{
+		ID:        entry.ID,
+		Scope:     scope,
+		Type:      entry.Type,
+		Value:     entry.Hint,
+		Action:    entry.Action,
+		Reason:    entry.Reason,
+		ExpiresAt: entry.ExpiresAt,
+		Hits:      entry.Hits,
+		LastHitAt: entry.LastHitAt,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+// FromBlocklistEntries converte a lista de entradas
+func FromBlocklistEntries(entries []*domain.BlocklistEntry) []*BlocklistEntryOutput {
+	output := make([]*BlocklistEntryOutput, len(entries))
+	for i, entry := range entries {
+		output[i] = FromBlocklistEntry(entry)
+	}
+	return output
+}
@@ -0,0 +1,25 @@
+package dto
+
+// RateLimitOutput representa o limite de requisições por API Key em uso
+type RateLimitOutput struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// ConfigOutput representa os ajustes aplicados pelo recarregamento da configuração
+// RateLimit é omitido quando o limite de requisições está desativado
+type ConfigOutput struct {
+	LogLevel             string                  `json:"log_level"`
+	LogSampleRate        float64                 `json:"log_sample_rate"`
+	LogRouteSampleRates  map[string]float64      `json:"log_route_sample_rates"`
+	RateLimit            *RateLimitOutput        `json:"rate_limit,omitempty"`
+	AuthAlertFailedIPs   int                     `json:"auth_alert_failed_ips"`
+	AuthAlertWindow      string                  `json:"auth_alert_window"`
+	AuthLockoutThreshold int                     `json:"auth_lockout_threshold"`
+	AuthLockoutCooloff   string                  `json:"auth_lockout_cooloff"`
+	AnomalyVolumeFactor  float64                 `json:"anomaly_volume_factor"`
+	AnomalyDeclineFactor float64                 `json:"anomaly_decline_factor"`
+	AnomalyMinInvoices   int                     `json:"anomaly_min_invoices"`
+	FeatureFlags         []*FeatureFlagOutput    `json:"feature_flags"`
+	LatencySLO           *LatencyObjectiveOutput `json:"latency_slo"`
+}
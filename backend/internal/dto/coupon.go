@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateCouponInput representa um cupom criado pela conta
+// Value é o percentual em "percentage" ou o valor em "fixed"; MaxRedemptions 0 não limita os usos e ValidFrom e
+// ValidUntil, opcionais, delimitam a validade
+type CreateCouponInput struct {
+	Code           string            `json:"code"`
+	Type           domain.CouponType `json:"type"`
+	Value          float64           `json:"value"`
+	MaxRedemptions int               `json:"max_redemptions"`
+	ValidFrom      *time.Time        `json:"valid_from"`
+	ValidUntil     *time.Time        `json:"valid_until"`
+}
+
+// CouponOutput representa um cupom nas respostas da API
+type CouponOutput struct {
+	ID             string            `json:"id"`
+	Code           string            `json:"code"`
+	Type           domain.CouponType `json:"type"`
+	Value          float64           `json:"value"`
+	MaxRedemptions int               `json:"max_redemptions"`
+	Redemptions    int               `json:"redemptions"`
+	ValidFrom      *time.Time        `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time        `json:"valid_until,omitempty"`
+	Active         bool              `json:"active"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// FromCoupon converte domain.Coupon para CouponOutput
+func FromCoupon(coupon *domain.Coupon) *CouponOutput {
+	return &CouponOutput{
+		ID:             coupon.ID,
+		Code:           coupon.Code,
+		Type:           coupon.Type,
+		Value:          coupon.Value,
+		MaxRedemptions: coupon.MaxRedemptions,
+		Redemptions:    coupon.Redemptions,
+		ValidFrom:      coupon.ValidFrom,
+		ValidUntil:     coupon.ValidUntil,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	}
+}
+
+// FromCoupons converte a lista de cupons
+func FromCoupons(coupons []*domain.Coupon) []*CouponOutput {
+	output := make([]*CouponOutput, len(coupons))
+	for i, coupon := range coupons {
+		output[i] = FromCoupon(coupon)
+	}
+	return output
+}
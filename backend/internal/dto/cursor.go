@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// cursorPayload é o conteúdo serializado de um cursor antes da codificação em base64
+type cursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// EncodeCursor converte a posição em um cursor opaco para as respostas da API
+func EncodeCursor(cursor domain.Cursor) string {
+	data, _ := json.Marshal(cursorPayload{CreatedAt: cursor.CreatedAt, ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor converte um cursor recebido na API de volta para a posição
+// Cursor vazio retorna nil; cursores malformados retornam ErrInvalidCursor
+func DecodeCursor(value string) (*domain.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.CreatedAt.IsZero() || payload.ID == "" {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	return &domain.Cursor{CreatedAt: payload.CreatedAt, ID: payload.ID}, nil
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateCustomerInput representa um cliente criado pela conta; Card, opcional, já guarda o primeiro cartão
+type CreateCustomerInput struct {
+	Name     string     `json:"name"`
+	Email    string     `json:"email"`
+	Document string     `json:"document"`
+	Card     *CardInput `json:"card"`
+}
+
+// LogValue registra a entrada sem os dados pessoais e sem os dados do cartão
+func (input CreateCustomerInput) LogValue() slog.Value {
+	return slog.GroupValue(slog.Bool("card", input.Card != nil))
+}
+
+// String impede que fmt exponha os dados pessoais e os do cartão
+func (input CreateCustomerInput) String() string {
+	return input.LogValue().String()
+}
+
+// GoString impede que %#v exponha os dados pessoais e os do cartão
+func (input CreateCustomerInput) GoString() string {
+	return input.String()
+}
+
+// CardInput é um cartão guardado para um cliente
+type CardInput struct {
+	CardNumber     string `json:"card_number"`
+	CVV            string `json:"cvv"`
+	ExpiryMonth    int    `json:"expiry_month"`
+	ExpiryYear     int    `json:"expiry_year"`
+	CardholderName string `json:"cardholder_name"`
+}
+
+// LogValue registra o cartão sem o CVV e com o número mascarado
+func (input CardInput) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("card_number", carddata.Mask(input.CardNumber)))
+}
+
+// String impede que fmt exponha os dados do cartão
+func (input CardInput) String() string {
+	return input.LogValue().String()
+}
+
+// GoString impede que %#v exponha os dados do cartão
+func (input CardInput) GoString() string {
+	return input.String()
+}
+
+// CustomerOutput representa um cliente nas respostas da API
+// PaymentMethods só vêm na consulta e na criação do cliente
+type CustomerOutput struct {
+	ID             string                `json:"id"`
+	Name           string                `json:"name"`
+	Email          string                `json:"email,omitempty"`
+	Document       string                `json:"document,omitempty"`
+	PaymentMethods []PaymentMethodOutput `json:"payment_methods,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// PaymentMethodOutput é um cartão guardado do cliente, informado em card_token ao criar as faturas dele
+type PaymentMethodOutput struct {
+	CardToken      string    `json:"card_token"`
+	CardBrand      string    `json:"card_brand"`
+	CardLastDigits string    `json:"card_last_digits"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// FromCustomer converte o cliente e os cartões dele para CustomerOutput
+func FromCustomer(customer *domain.Customer, methods []*domain.CustomerPaymentMethod) *CustomerOutput {
+	output := &CustomerOutput{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Document:  customer.Document,
+		CreatedAt: customer.CreatedAt,
+		UpdatedAt: customer.UpdatedAt,
+	}
+	for _, method := range methods {
+		output.PaymentMethods = append(output.PaymentMethods, *FromPaymentMethod(method))
+	}
+	return output
+}
+
+// FromCustomers converte a lista de clientes, sem os cartões
+func FromCustomers(customers []*domain.Customer) []*CustomerOutput {
+	output := make([]*CustomerOutput, len(customers))
+	for i, customer := range customers {
+		output[i] = FromCustomer(customer, nil)
+	}
+	return output
+}
+
+// FromPaymentMethod converte um cartão guardado do cliente para PaymentMethodOutput
+func FromPaymentMethod(method *domain.CustomerPaymentMethod) *PaymentMethodOutput {
+	return &PaymentMethodOutput{
+		CardToken:      method.CardToken,
+		CardBrand:      method.CardBrand,
+		CardLastDigits: method.CardLastDigits,
+		CreatedAt:      method.CreatedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// DataSubjectInput identifica o pagador titular dos dados pelo nome gravado nas faturas
+type DataSubjectInput struct {
+	PayerName string `json:"payer_name"`
+}
+
+// DataSubjectCardOutput representa um cartão do titular guardado no cofre, sem o número
+type DataSubjectCardOutput struct {
+	Token       string `json:"token"`
+	Brand       string `json:"brand"`
+	LastDigits  string `json:"last_digits"`
+	ExpiryMonth int    `json:"expiry_month"`
+	ExpiryYear  int    `json:"expiry_year"`
+	HolderName  string `json:"holder_name"`
+}
+
+// FromCardDetails converte carddata.Details para DataSubjectCardOutput
+func FromCardDetails(details *carddata.Details) *DataSubjectCardOutput {
+	return &DataSubjectCardOutput{
+		Token:       details.Token,
+		Brand:       details.Brand,
+		LastDigits:  details.LastDigits,
+		ExpiryMonth: details.ExpiryMonth,
+		ExpiryYear:  details.ExpiryYear,
+		HolderName:  details.HolderName,
+	}
+}
+
+// DataSubjectExport reúne os dados pessoais do titular guardados pelo gateway para a conta
+type DataSubjectExport struct {
+	RequestID   string                   `json:"request_id"`
+	PayerName   string                   `json:"payer_name"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Invoices    []*InvoiceOutput         `json:"invoices"`
+	Cards       []*DataSubjectCardOutput `json:"cards"`
+}
+
+// DataSubjectRequestOutput representa um pedido de titular atendido nas respostas da API
+type DataSubjectRequestOutput struct {
+	ID        string                        `json:"id"`
+	Type      domain.DataSubjectRequestType `json:"type"`
+	Invoices  int                           `json:"invoices"`
+	Token     string                        `json:"token,omitempty"`
+	Actor     string                        `json:"actor"`
+	CreatedAt time.Time                     `json:"created_at"`
+}
+
+// FromDataSubjectRequest converte domain.DataSubjectRequest para DataSubjectRequestOutput
+func FromDataSubjectRequest(request *domain.DataSubjectRequest) *DataSubjectRequestOutput {
+	return &DataSubjectRequestOutput{
+		ID:        request.ID,
+		Type:      request.Type,
+		Invoices:  request.Invoices,
+		Token:     request.Token,
+		Actor:     request.Actor,
+		CreatedAt: request.CreatedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// OpenDisputeInput registra a disputa de uma fatura com o motivo informado pelo emissor do cartão
+type OpenDisputeInput struct {
+	Reason string `json:"reason"`
+}
+
+// ResolveDisputeInput encerra a disputa com o resultado "won" ou "lost"
+type ResolveDisputeInput struct {
+	Status domain.DisputeStatus `json:"status"`
+}
+
+// DisputeEvidenceInput é o arquivo de evidência recebido no upload; Body já foi limitado pelo handler
+type DisputeEvidenceInput struct {
+	FileName    string
+	Description string
+	Body        []byte
+}
+
+// DisputeEvidenceOutput representa uma evidência nas respostas da API, sem o caminho no armazenamento
+type DisputeEvidenceOutput struct {
+	ID          string    `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Description string    `json:"description,omitempty"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FromDisputeEvidence converte domain.DisputeEvidence para DisputeEvidenceOutput
+func FromDisputeEvidence(evidence *domain.DisputeEvidence) *DisputeEvidenceOutput {
+	return &DisputeEvidenceOutput{
+		ID:          evidence.ID,
+		FileName:    evidence.FileName,
+		ContentType: evidence.ContentType,
+		Size:        evidence.Size,
+		Description: evidence.Description,
+		UploadedBy:  evidence.UploadedBy,
+		CreatedAt:   evidence.CreatedAt,
+	}
+}
+
+// DisputeOutput representa uma disputa nas respostas da API
+// Evidence só é preenchido na consulta de uma disputa, não nas listagens
+type DisputeOutput struct {
+	ID         string                   `json:"id"`
+	InvoiceID  string                   `json:"invoice_id"`
+	AccountID  string                   `json:"account_id"`
+	Amount     float64                  `json:"amount"`
+	Fee        float64                  `json:"fee"`
+	Reason     string                   `json:"reason"`
+	Status     domain.DisputeStatus     `json:"status"`
+	ResolvedAt *time.Time               `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time                `json:"created_at"`
+	Evidence   []*DisputeEvidenceOutput `json:"evidence,omitempty"`
+}
+
+// FromDispute converte domain.Dispute e as suas evidências para DisputeOutput
+func FromDispute(dispute *domain.Dispute, evidence []*domain.DisputeEvidence) *DisputeOutput {
+	output := &DisputeOutput{
+		ID:         dispute.ID,
+		InvoiceID:  dispute.InvoiceID,
+		AccountID:  dispute.AccountID,
+		Amount:     dispute.Amount,
+		Fee:        dispute.Fee,
+		Reason:     dispute.Reason,
+		Status:     dispute.Status,
+		ResolvedAt: dispute.ResolvedAt,
+		CreatedAt:  dispute.CreatedAt,
+	}
+	for _, item := range evidence {
+		output.Evidence = append(output.Evidence, FromDisputeEvidence(item))
+	}
+	return output
+}
+
+// FromDisputes converte a lista de disputas, sem as evidências
+func FromDisputes(disputes []*domain.Dispute) []*DisputeOutput {
+	output := make([]*DisputeOutput, len(disputes))
+	for i, dispute := range disputes {
+		output[i] = FromDispute(dispute, nil)
+	}
+	return output
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// EscrowHoldOutput representa o valor de uma conta retido em custódia por uma fatura nas respostas da API
+type EscrowHoldOutput struct {
+	ID         string              `json:"id"`
+	AccountID  string              `json:"account_id"`
+	InvoiceID  string              `json:"invoice_id"`
+	Amount     float64             `json:"amount"`
+	Status     domain.EscrowStatus `json:"status"`
+	ReleaseAt  time.Time           `json:"release_at"`
+	ReleasedBy string              `json:"released_by,omitempty"`
+	ReleasedAt *time.Time          `json:"released_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// FromEscrowHolds converte as retenções do domínio para EscrowHoldOutput
+func FromEscrowHolds(holds []*domain.EscrowHold) []*EscrowHoldOutput {
+	output := make([]*EscrowHoldOutput, len(holds))
+	for i, hold := range holds {
+		output[i] = &EscrowHoldOutput{
+			ID:         hold.ID,
+			AccountID:  hold.AccountID,
+			InvoiceID:  hold.InvoiceID,
+			Amount:     hold.Amount,
+			Status:     hold.Status,
+			ReleaseAt:  hold.ReleaseAt,
+			ReleasedBy: hold.ReleasedBy,
+			ReleasedAt: hold.ReleasedAt,
+			CreatedAt:  hold.CreatedAt,
+		}
+	}
+	return output
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// ExportOutput representa uma exportação com um link de download assinado
+// O link vale até URLExpiresAt; um novo link pode ser pedido enquanto a exportação não expirar
+type ExportOutput struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	FileName     string    `json:"file_name"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	DownloadURL  string    `json:"download_url"`
+	URLExpiresAt time.Time `json:"url_expires_at"`
+}
+
+// FromExport converte domain.Export para ExportOutput com o link informado
+func FromExport(export *domain.Export, downloadURL string, urlExpiresAt time.Time) *ExportOutput {
+	return &ExportOutput{
+		ID:           export.ID,
+		Type:         string(export.Type),
+		FileName:     export.FileName,
+		Size:         export.Size,
+		CreatedAt:    export.CreatedAt,
+		ExpiresAt:    export.ExpiresAt,
+		DownloadURL:  downloadURL,
+		URLExpiresAt: urlExpiresAt,
+	}
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/joaodematejr/imersao22/go-gateway/internal/flags"
+
+// FeatureFlagOutput representa a definição de uma flag nas respostas administrativas
+type FeatureFlagOutput struct {
+	Name       string   `json:"name"`
+	Enabled    bool     `json:"enabled"`
+	Percentage int      `json:"percentage"`
+	Accounts   []string `json:"accounts"`
+}
+
+// AccountFlagsOutput representa a decisão de cada flag para uma conta
+type AccountFlagsOutput struct {
+	AccountID string          `json:"account_id"`
+	Flags     map[string]bool `json:"flags"`
+}
+
+// FromFeatureFlags converte as flags nas definições das respostas
+func FromFeatureFlags(list []flags.Flag) []*FeatureFlagOutput {
+	output := make([]*FeatureFlagOutput, len(list))
+	for i, flag := range list {
+		accounts := flag.Accounts
+		if accounts == nil {
+			accounts = []string{}
+		}
+		output[i] = &FeatureFlagOutput{Name: flag.Name, Enabled: flag.Enabled, Percentage: flag.Percentage, Accounts: accounts}
+	}
+	return output
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// FeeScheduleInput define as tarifas de uma conta no lugar das tarifas padrão
+type FeeScheduleInput struct {
+	ChargebackFee float64 `json:"chargeback_fee"`
+}
+
+// FeeScheduleOutput são as tarifas em vigor para a conta
+// Default indica que a conta usa as tarifas padrão do gateway, sem UpdatedAt
+type FeeScheduleOutput struct {
+	AccountID     string     `json:"account_id"`
+	ChargebackFee float64    `json:"chargeback_fee"`
+	Default       bool       `json:"default"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// FromFeeSchedule converte domain.FeeSchedule para FeeScheduleOutput
+func FromFeeSchedule(schedule *domain.FeeSchedule) *FeeScheduleOutput {
+	output := &FeeScheduleOutput{
+		AccountID:     schedule.AccountID,
+		ChargebackFee: schedule.ChargebackFee,
+		Default:       schedule.UpdatedAt.IsZero(),
+	}
+	if !output.Default {
+		output.UpdatedAt = &schedule.UpdatedAt
+	}
+	return output
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// GeoPolicyInput representa a política geográfica enviada pela conta
+// Action é "block" (padrão) ou "flag"; os países são códigos ISO 3166-1 de duas letras
+// Com Shadow a política é apenas avaliada, sem bloquear nem marcar
+type GeoPolicyInput struct {
+	Action           string   `json:"action"`
+	Shadow           bool     `json:"shadow"`
+	AllowedCountries []string `json:"allowed_countries"`
+	BlockedCountries []string `json:"blocked_countries"`
+}
+
+// GeoPolicyOutput representa a política geográfica da conta nas respostas da API
+type GeoPolicyOutput struct {
+	Action           domain.GeoAction `json:"action"`
+	Shadow           bool             `json:"shadow"`
+	AllowedCountries []string         `json:"allowed_countries"`
+	BlockedCountries []string         `json:"blocked_countries"`
+	UpdatedAt        *time.Time       `json:"updated_at,omitempty"`
+}
+
+// FromGeoPolicy converte domain.GeoPolicy para GeoPolicyOutput
+func FromGeoPolicy(policy *domain.GeoPolicy) GeoPolicyOutput {
+	output := GeoPolicyOutput{
+		Action:           policy.Action,
+		Shadow:           policy.Shadow,
+		AllowedCountries: policy.AllowedCountries,
+		BlockedCountries: policy.BlockedCountries,
+	}
+	if output.AllowedCountries == nil {
+		output.AllowedCountries = []string{}
+	}
+	if output.BlockedCountries == nil {
+		output.BlockedCountries = []string{}
+	}
+	if !policy.UpdatedAt.IsZero() {
+		output.UpdatedAt = &policy.UpdatedAt
+	}
+	return output
+}
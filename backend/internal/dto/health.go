@@ -0,0 +1,69 @@
+package dto
+
+import "time"
+
+// Valores de ReadinessOutput.Status e DependencyReadinessOutput.Status
+const (
+	ReadinessOK = "ok"
+	// ReadinessWarning indica uma dependência opcional fora do ar; a instância continua recebendo tráfego
+	ReadinessWarning  = "warning"
+	ReadinessDegraded = "degraded"
+	// ReadinessStarting indica que a instância ainda espera as migrations ou o aquecimento da subida
+	ReadinessStarting    = "starting"
+	ReadinessUnavailable = "unavailable"
+)
+
+// ReadinessOutput representa a resposta de /readyz
+type ReadinessOutput struct {
+	Status       string                      `json:"status"`
+	Dependencies []DependencyReadinessOutput `json:"dependencies"`
+}
+
+// DependencyReadinessOutput detalha a saúde de uma dependência
+// Pool só vem nos pools de banco
+type DependencyReadinessOutput struct {
+	Name                string               `json:"name"`
+	Status              string               `json:"status"`
+	Critical            bool                 `json:"critical"`
+	Error               string               `json:"error,omitempty"`
+	LatencyMilliseconds float64              `json:"latency_ms"`
+	Pool                *PoolReadinessOutput `json:"pool,omitempty"`
+}
+
+// PoolReadinessOutput detalha as conexões de um pool de banco
+type PoolReadinessOutput struct {
+	TotalConns        int32 `json:"total_conns"`
+	AcquiredConns     int32 `json:"acquired_conns"`
+	IdleConns         int32 `json:"idle_conns"`
+	MaxConns          int32 `json:"max_conns"`
+	EmptyAcquireCount int64 `json:"empty_acquire_count"`
+}
+
+// StatusOutput representa a resposta de /status, que reúne em um documento o que painéis e ferramentas de
+// suporte precisam saber da instância
+type StatusOutput struct {
+	Status        string                      `json:"status"`
+	Version       string                      `json:"version"`
+	Commit        string                      `json:"commit,omitempty"`
+	BuildDate     string                      `json:"build_date,omitempty"`
+	GoVersion     string                      `json:"go_version"`
+	StartedAt     time.Time                   `json:"started_at"`
+	UptimeSeconds int64                       `json:"uptime_seconds"`
+	Dependencies  []DependencyReadinessOutput `json:"dependencies"`
+	Consumers     []ConsumerStatusOutput      `json:"consumers"`
+	Queues        []QueueStatusOutput         `json:"queues"`
+}
+
+// ConsumerStatusOutput detalha um consumidor do Kafka
+// Lag são as mensagens do tópico ainda não lidas, segundo o último lote buscado
+type ConsumerStatusOutput struct {
+	Topic   string `json:"topic"`
+	GroupID string `json:"group_id"`
+	Lag     int64  `json:"lag"`
+}
+
+// QueueStatusOutput detalha uma fila interna de trabalho em segundo plano
+type QueueStatusOutput struct {
+	Name  string `json:"name"`
+	Depth int    `json:"depth"`
+}
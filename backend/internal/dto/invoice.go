@@ -1,69 +1,310 @@
 package dto
 
 import (
+	"log/slog"
+	"math"
 	"time"
 
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 )
 
 const (
-	StatusPending  = string(domain.StatusPending)
-	StatusApproved = string(domain.StatusApproved)
-	StatusRejected = string(domain.StatusRejected)
+	StatusPending     = string(domain.StatusPending)
+	StatusApproved    = string(domain.StatusApproved)
+	StatusRejected    = string(domain.StatusRejected)
+	StatusSettled     = string(domain.StatusSettled)
+	StatusRefunded    = string(domain.StatusRefunded)
+	StatusChargedBack = string(domain.StatusChargedBack)
 )
 
 type CreateInvoiceInput struct {
 	APIKey         string
-	Amount         float64 `json:"amount"`
-	Description    string  `json:"description"`
-	PaymentType    string  `json:"payment_type"`
-	CardNumber     string  `json:"card_number"`
-	CVV            string  `json:"cvv"`
-	ExpiryMonth    int     `json:"expiry_month"`
-	ExpiryYear     int     `json:"expiry_year"`
-	CardholderName string  `json:"cardholder_name"`
+	Amount         float64           `json:"amount"`
+	Description    string            `json:"description"`
+	PaymentType    string            `json:"payment_type"`
+	CardNumber     string            `json:"card_number"`
+	CVV            string            `json:"cvv"`
+	ExpiryMonth    int               `json:"expiry_month"`
+	ExpiryYear     int               `json:"expiry_year"`
+	CardholderName string            `json:"cardholder_name"`
+	Metadata       map[string]string `json:"metadata"`
+	// PayerEmail e PayerDocument são opcionais e só servem para conferir as listas de bloqueio; não são gravados
+	PayerEmail    string `json:"payer_email"`
+	PayerDocument string `json:"payer_document"`
+	// Splits divide o valor da fatura entre contas recebedoras; vazio, a conta dona recebe tudo
+	Splits []SplitRuleInput `json:"splits"`
+	// EscrowDays retém o valor aprovado em custódia pelo prazo, até a liberação; 0 credita direto no saldo
+	EscrowDays int `json:"escrow_days"`
+	// CouponCode aplica o cupom de desconto da conta ao valor; não é aceito nos lotes
+	CouponCode string `json:"coupon_code"`
+	// Items compõem a fatura; a soma deles tem de ser Amount, ou vira o valor quando Amount não é informado
+	Items []InvoiceItemInput `json:"items"`
+	// CustomerID liga a fatura a um cliente da conta; com CardToken, um cartão guardado dele, os dados do cartão
+	// não são enviados, e com SaveCard o cartão enviado é guardado no cliente. Não são aceitos nos lotes
+	CustomerID string `json:"customer_id"`
+	CardToken  string `json:"card_token"`
+	SaveCard   bool   `json:"save_card"`
+	// DueDate (AAAA-MM-DD) faz a fatura esperar a confirmação do pagamento, com multa e juros se ele vier depois
+	DueDate string `json:"due_date"`
+	// Currency é a moeda de Amount; em outra moeda que não a de liquidação, o valor é convertido pela cotação
+	// travada na criação. Não é aceita nos lotes nem com itens
+	Currency string `json:"currency"`
+}
+
+// LogValue registra a entrada sem o API Key e o CVV e com o número do cartão mascarado
+func (input CreateInvoiceInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("amount", input.Amount),
+		slog.String("description", input.Description),
+		slog.String("payment_type", input.PaymentType),
+		slog.String("card_number", carddata.Mask(input.CardNumber)),
+	)
+}
+
+// String impede que fmt exponha os dados do cartão
+func (input CreateInvoiceInput) String() string {
+	return input.LogValue().String()
+}
+
+// GoString impede que %#v exponha os dados do cartão
+func (input CreateInvoiceInput) GoString() string {
+	return input.String()
+}
+
+// CreateInvoiceBatchInput representa um lote de faturas criado em uma única requisição
+type CreateInvoiceBatchInput struct {
+	APIKey   string
+	Invoices []CreateInvoiceInput `json:"invoices"`
 }
 
 type InvoiceOutput struct {
-	ID             string    `json:"id"`
-	AccountID      string    `json:"account_id"`
-	Amount         float64   `json:"amount"`
-	Status         string    `json:"status"`
-	Description    string    `json:"description"`
-	PaymentType    string    `json:"payment_type"`
-	CardLastDigits string    `json:"card_last_digits"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-}
-
-func ToInvoice(input CreateInvoiceInput, accountID string) (*domain.Invoice, error) {
-	card := domain.CreditCard{
-		Number:         input.CardNumber,
-		CVV:            input.CVV,
-		ExpiryMonth:    input.ExpiryMonth,
-		ExpiryYear:     input.ExpiryYear,
-		CardholderName: input.CardholderName,
+	ID             string            `json:"id"`
+	AccountID      string            `json:"account_id"`
+	Amount         float64           `json:"amount"`
+	Status         string            `json:"status"`
+	Description    string            `json:"description"`
+	PaymentType    string            `json:"payment_type"`
+	CardToken      string            `json:"card_token"`
+	CardBrand      string            `json:"card_brand"`
+	CardLastDigits string            `json:"card_last_digits"`
+	PayerName      string            `json:"payer_name"`
+	Metadata       map[string]string `json:"metadata"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+	// Splits são as partes das contas recebedoras, só nas faturas divididas
+	Splits []SplitOutput `json:"splits,omitempty"`
+	// Items são os itens da fatura; vêm na criação e na consulta da fatura
+	Items []InvoiceItemOutput `json:"items,omitempty"`
+	// Discount detalha o cupom aplicado, só nas faturas com desconto
+	Discount *InvoiceDiscountOutput `json:"discount,omitempty"`
+	// TaxAmount é o total dos impostos somados ao valor, só nas faturas com impostos
+	TaxAmount float64 `json:"tax_amount,omitempty"`
+	// Taxes detalha os impostos de cada linha; vem na criação e na consulta da fatura
+	Taxes []InvoiceTaxOutput `json:"taxes,omitempty"`
+	// DueDate é o vencimento, só nas faturas que esperam a confirmação do pagamento
+	DueDate string `json:"due_date,omitempty"`
+	// LateCharges detalha a multa e os juros, só nas faturas pagas depois do vencimento
+	LateCharges *InvoiceLateChargesOutput `json:"late_charges,omitempty"`
+	// FX detalha a moeda e a cotação, só nas faturas pedidas em outra moeda
+	FX *InvoiceFXOutput `json:"fx,omitempty"`
+}
+
+// InvoiceFXOutput detalha a fatura pedida em outra moeda; o valor da fatura está na moeda de liquidação
+// Amount é o valor na moeda pedida e Rate a cotação usada no valor, que só difere de LockedRate quando a trava
+// venceu antes da captura, em RequotedAt
+type InvoiceFXOutput struct {
+	Currency    string     `json:"currency"`
+	Amount      float64    `json:"amount"`
+	Rate        float64    `json:"rate"`
+	LockedRate  float64    `json:"locked_rate"`
+	LockedUntil time.Time  `json:"locked_until"`
+	RequotedAt  *time.Time `json:"requoted_at,omitempty"`
+}
+
+// InvoiceLateChargesOutput detalha os encargos do pagamento com atraso; o valor da fatura já os inclui
+// OriginalAmount é o valor antes da multa e dos juros
+type InvoiceLateChargesOutput struct {
+	DaysLate       int     `json:"days_late"`
+	Fine           float64 `json:"fine"`
+	Interest       float64 `json:"interest"`
+	OriginalAmount float64 `json:"original_amount"`
+}
+
+// ConfirmPaymentInput representa a confirmação do pagamento de uma fatura com vencimento
+// PaidAt é a data do pagamento, que define o atraso; ausente, vale o momento da confirmação
+type ConfirmPaymentInput struct {
+	PaidAt *time.Time `json:"paid_at"`
+}
+
+// InvoiceDiscountOutput detalha o desconto do cupom; o valor da fatura já é o valor com desconto
+// OriginalAmount é o valor pedido, sem o desconto e sem os impostos
+type InvoiceDiscountOutput struct {
+	CouponCode     string  `json:"coupon_code"`
+	OriginalAmount float64 `json:"original_amount"`
+	Amount         float64 `json:"amount"`
+}
+
+// ListInvoicesInput representa os filtros aceitos na listagem de faturas
+type ListInvoicesInput struct {
+	APIKey      string
+	Statuses    []string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   float64
+	MaxAmount   float64
+	Metadata    map[string]string
+	Search      string
+	// Limit ativa a paginação; Cursor é o next_cursor da página anterior
+	Limit  int
+	Cursor string
+	// IncludeDeleted só é respeitado nas consultas administrativas
+	IncludeDeleted bool
+}
+
+// ToInvoice monta a fatura e os itens dela com a referência do cartão já validado por carddata
+// Os dados brutos do cartão da entrada não são copiados para a fatura
+func ToInvoice(input CreateInvoiceInput, accountID string, card domain.PaymentCard) (*domain.Invoice, []*domain.InvoiceItem, error) {
+	items, err := ToInvoiceItems(input.Items)
+	if err != nil {
+		return nil, nil, err
+	}
+	amount := input.Amount
+	if amount == 0 && len(items) > 0 {
+		amount = domain.InvoiceItemsTotal(items)
 	}
 
-	return domain.NewInvoice(
+	invoice, err := domain.NewInvoice(
 		accountID,
-		input.Amount,
+		amount,
 		input.Description,
 		input.PaymentType,
 		card,
 	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := invoice.SetItems(items); err != nil {
+		return nil, nil, err
+	}
+
+	if input.Metadata != nil {
+		invoice.Metadata = input.Metadata
+	}
+	if err := invoice.SetEscrowDays(input.EscrowDays); err != nil {
+		return nil, nil, err
+	}
+	if err := invoice.SetDueDate(input.DueDate); err != nil {
+		return nil, nil, err
+	}
+	invoice.ClearDiscount()
+	invoice.ClearTaxes()
+	invoice.ClearCustomer()
+	invoice.ClearExchangeRate()
+	return invoice, items, nil
+}
+
+// InvoicePage é uma página da listagem de faturas
+// NextCursor fica vazio na última página
+type InvoicePage struct {
+	Invoices   []*InvoiceOutput
+	NextCursor string
+}
+
+// ToInvoiceFilter converte ListInvoicesInput para domain.InvoiceFilter restrito à conta informada
+// O cursor é decodificado à parte, pois pode ser inválido
+func ToInvoiceFilter(input ListInvoicesInput, accountID string) domain.InvoiceFilter {
+	statuses := make([]domain.Status, len(input.Statuses))
+	for i, status := range input.Statuses {
+		statuses[i] = domain.Status(status)
+	}
+
+	return domain.InvoiceFilter{
+		AccountID:   accountID,
+		Statuses:    statuses,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+		MinAmount:   input.MinAmount,
+		MaxAmount:   input.MaxAmount,
+		Metadata:    input.Metadata,
+		Search:      input.Search,
+		Limit:       input.Limit,
+
+		IncludeDeleted: input.IncludeDeleted,
+	}
 }
 
 func FromInvoice(invoice *domain.Invoice) *InvoiceOutput {
-	return &InvoiceOutput{
+	output := &InvoiceOutput{
 		ID:             invoice.ID,
 		AccountID:      invoice.AccountID,
 		Amount:         invoice.Amount,
 		Status:         string(invoice.Status),
 		Description:    invoice.Description,
 		PaymentType:    invoice.PaymentType,
+		CardToken:      invoice.CardToken,
+		CardBrand:      invoice.CardBrand,
 		CardLastDigits: invoice.CardLastDigits,
+		PayerName:      invoice.PayerName,
+		Metadata:       invoice.Metadata,
 		CreatedAt:      invoice.CreatedAt,
 		UpdatedAt:      invoice.UpdatedAt,
+		DeletedAt:      invoice.DeletedAt,
+	}
+	output.TaxAmount = invoice.TaxAmount()
+	amount := invoice.Amount
+	if fine, interest, daysLate := invoice.LateCharges(); daysLate > 0 {
+		amount = math.Round((invoice.Amount-fine-interest)*100) / 100
+		output.LateCharges = &InvoiceLateChargesOutput{
+			DaysLate:       daysLate,
+			Fine:           fine,
+			Interest:       interest,
+			OriginalAmount: amount,
+		}
+	}
+	if due := invoice.DueDate(); !due.IsZero() {
+		output.DueDate = due.Format(domain.DueDateLayout)
+	}
+	if currency := invoice.Currency(); currency != "" {
+		output.FX = &InvoiceFXOutput{
+			Currency:    currency,
+			Amount:      invoice.CurrencyAmount(),
+			Rate:        invoice.ExchangeRate(),
+			LockedRate:  invoice.LockedExchangeRate(),
+			LockedUntil: invoice.ExchangeRateLockedUntil(),
+		}
+		if at := invoice.ExchangeRateRequotedAt(); !at.IsZero() {
+			output.FX.RequotedAt = &at
+		}
+	}
+	if code, discount := invoice.Discount(); code != "" {
+		output.Discount = &InvoiceDiscountOutput{
+			CouponCode:     code,
+			OriginalAmount: math.Round((amount-output.TaxAmount+discount)*100) / 100,
+			Amount:         discount,
+		}
+	}
+	return output
+}
+
+// TransactionLimitOutput é a resposta de uma fatura ou lote recusado pelo teto de gasto da conta
+// Remaining é quanto ainda pode ser faturado no período e ResetsAt quando o período recomeça
+type TransactionLimitOutput struct {
+	Error     string    `json:"error"`
+	Period    string    `json:"period"`
+	Limit     float64   `json:"limit"`
+	Remaining float64   `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// FromTransactionLimitError converte domain.TransactionLimitError para TransactionLimitOutput
+func FromTransactionLimitError(err *domain.TransactionLimitError) *TransactionLimitOutput {
+	return &TransactionLimitOutput{
+		Error:     domain.ErrTransactionLimitExceeded.Error(),
+		Period:    err.Period,
+		Limit:     err.Limit,
+		Remaining: err.Remaining,
+		ResetsAt:  err.ResetsAt,
 	}
 }
@@ -0,0 +1,58 @@
+package dto
+
+import "github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+
+// InvoiceItemInput é um item da fatura na criação
+type InvoiceItemInput struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// InvoiceItemOutput é um item da fatura; Amount é a quantidade vezes o preço unitário, antes do desconto e dos
+// impostos, e Line é a linha dos impostos do item
+type InvoiceItemOutput struct {
+	Line        int     `json:"line"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+// ToInvoiceItems converte os itens da entrada para o domínio, ainda sem fatura
+// Retorna ErrInvalidInvoiceItems se algum item for inválido ou forem mais de MaxInvoiceItems
+func ToInvoiceItems(inputs []InvoiceItemInput) ([]*domain.InvoiceItem, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	if len(inputs) > domain.MaxInvoiceItems {
+		return nil, domain.ErrInvalidInvoiceItems
+	}
+	items := make([]*domain.InvoiceItem, len(inputs))
+	for i, input := range inputs {
+		item, err := domain.NewInvoiceItem(input.Description, input.Quantity, input.UnitPrice)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// FromInvoiceItems converte os itens da fatura para InvoiceItemOutput; sem itens retorna nil
+func FromInvoiceItems(items []*domain.InvoiceItem) []InvoiceItemOutput {
+	if len(items) == 0 {
+		return nil
+	}
+	output := make([]InvoiceItemOutput, len(items))
+	for i, item := range items {
+		output[i] = InvoiceItemOutput{
+			Line:        item.Position,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Total(),
+		}
+	}
+	return output
+}
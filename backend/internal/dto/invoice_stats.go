@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AccountStatsOutput resume as faturas da conta no período para os painéis
+// GrossVolume soma todas as faturas e NetVolume apenas as aprovadas, descontados os reembolsos; como as
+// estatísticas ainda não consideram os reembolsos, RefundRate é sempre zero
+// Os volumes já estão descontados dos cupons; Discounts soma os descontos concedidos no período
+type AccountStatsOutput struct {
+	Period        string         `json:"period"`
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Invoices      int            `json:"invoices"`
+	CountByStatus map[string]int `json:"count_by_status"`
+	GrossVolume   float64        `json:"gross_volume"`
+	NetVolume     float64        `json:"net_volume"`
+	AverageTicket float64        `json:"average_ticket"`
+	RefundRate    float64        `json:"refund_rate"`
+	Discounts     float64        `json:"discounts"`
+}
+
+// NewAccountStatsOutput calcula as estatísticas a partir dos totais por status
+// Todos os status aparecem em CountByStatus, inclusive os sem faturas
+func NewAccountStatsOutput(period string, from, to time.Time, totals []domain.StatusTotals) *AccountStatsOutput {
+	output := &AccountStatsOutput{
+		Period: period,
+		From:   from,
+		To:     to,
+		CountByStatus: map[string]int{
+			StatusPending:     0,
+			StatusApproved:    0,
+			StatusRejected:    0,
+			StatusSettled:     0,
+			StatusRefunded:    0,
+			StatusChargedBack: 0,
+		},
+	}
+
+	for _, total := range totals {
+		output.Invoices += total.Count
+		output.CountByStatus[string(total.Status)] += total.Count
+		output.GrossVolume += total.Amount
+		if total.Status.IsPaid() {
+			output.NetVolume += total.Amount
+		}
+	}
+
+	if output.Invoices > 0 {
+		output.AverageTicket = output.GrossVolume / float64(output.Invoices)
+	}
+	return output
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// LedgerEntryOutput representa um lançamento do razão da conta nas respostas da API
+type LedgerEntryOutput struct {
+	ID             string                 `json:"id"`
+	Type           domain.LedgerEntryType `json:"type"`
+	Amount         float64                `json:"amount"`
+	InvoiceID      string                 `json:"invoice_id"`
+	CounterpartyID string                 `json:"counterparty_id,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// StatementCounterpartyOutput soma os lançamentos de um tipo com a mesma outra conta, como a comissão recebida de
+// cada conta filha de uma plataforma
+type StatementCounterpartyOutput struct {
+	AccountID string                 `json:"account_id"`
+	Type      domain.LedgerEntryType `json:"type"`
+	Entries   int                    `json:"entries"`
+	Amount    float64                `json:"amount"`
+}
+
+// StatementOutput é o extrato do razão da conta no período que termina em To
+// Totals soma os lançamentos por tipo
+type StatementOutput struct {
+	Period         string                             `json:"period"`
+	From           time.Time                          `json:"from"`
+	To             time.Time                          `json:"to"`
+	Totals         map[domain.LedgerEntryType]float64 `json:"totals"`
+	Counterparties []StatementCounterpartyOutput      `json:"counterparties"`
+	Entries        []LedgerEntryOutput                `json:"entries"`
+}
+
+// NewStatementOutput monta o extrato com os lançamentos do período, somando em centavos
+func NewStatementOutput(period string, from, to time.Time, entries []*domain.LedgerEntry) *StatementOutput {
+	type counterpartyKey struct {
+		accountID string
+		entryType domain.LedgerEntryType
+	}
+
+	totals := make(map[domain.LedgerEntryType]int64)
+	counterparties := make(map[counterpartyKey]*StatementCounterpartyOutput)
+	counterpartyCents := make(map[counterpartyKey]int64)
+	output := &StatementOutput{
+		Period:         period,
+		From:           from,
+		To:             to,
+		Totals:         make(map[domain.LedgerEntryType]float64),
+		Counterparties: []StatementCounterpartyOutput{},
+		Entries:        make([]LedgerEntryOutput, len(entries)),
+	}
+	for i, entry := range entries {
+		cents := int64(math.Round(entry.Amount * 100))
+		totals[entry.Type] += cents
+		if entry.CounterpartyID != "" {
+			key := counterpartyKey{entry.CounterpartyID, entry.Type}
+			if counterparties[key] == nil {
+				counterparties[key] = &StatementCounterpartyOutput{AccountID: entry.CounterpartyID, Type: entry.Type}
+			}
+			counterparties[key].Entries++
+			counterpartyCents[key] += cents
+		}
+		output.Entries[i] = LedgerEntryOutput{
+			ID:             entry.ID,
+			Type:           entry.Type,
+			Amount:         entry.Amount,
+			InvoiceID:      entry.InvoiceID,
+			CounterpartyID: entry.CounterpartyID,
+			CreatedAt:      entry.CreatedAt,
+		}
+	}
+
+	for entryType, cents := range totals {
+		output.Totals[entryType] = float64(cents) / 100
+	}
+	for key, counterparty := range counterparties {
+		counterparty.Amount = float64(counterpartyCents[key]) / 100
+		output.Counterparties = append(output.Counterparties, *counterparty)
+	}
+	sort.Slice(output.Counterparties, func(i, j int) bool {
+		a, b := output.Counterparties[i], output.Counterparties[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.AccountID < b.AccountID
+	})
+	return output
+}
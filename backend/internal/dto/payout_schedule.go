@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PayoutScheduleInput representa o prazo de repasse escolhido pela conta, no formato "D+N", como "D+14"
+type PayoutScheduleInput struct {
+	Schedule string `json:"schedule"`
+}
+
+// PayoutScheduleOutput representa o prazo de repasse da conta nas respostas da API
+// UpdatedAt fica vazio enquanto a conta usa o prazo padrão
+type PayoutScheduleOutput struct {
+	Schedule  string     `json:"schedule"`
+	DelayDays int        `json:"delay_days"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// FromPayoutSchedule converte domain.PayoutSchedule para PayoutScheduleOutput
+func FromPayoutSchedule(schedule *domain.PayoutSchedule) PayoutScheduleOutput {
+	output := PayoutScheduleOutput{Schedule: schedule.Name(), DelayDays: schedule.DelayDays}
+	if !schedule.UpdatedAt.IsZero() {
+		output.UpdatedAt = &schedule.UpdatedAt
+	}
+	return output
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PlatformLinkInput liga a conta à plataforma informada, que retém CommissionPercent do que a conta recebe
+type PlatformLinkInput struct {
+	PlatformID        string  `json:"platform_id"`
+	CommissionPercent float64 `json:"commission_percent"`
+}
+
+// PlatformLinkOutput representa a ligação da conta com a plataforma nas respostas da API
+type PlatformLinkOutput struct {
+	AccountID         string    `json:"account_id"`
+	PlatformID        string    `json:"platform_id"`
+	CommissionPercent float64   `json:"commission_percent"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// FromPlatformLink converte domain.PlatformLink para PlatformLinkOutput
+func FromPlatformLink(link *domain.PlatformLink) *PlatformLinkOutput {
+	return &PlatformLinkOutput{
+		AccountID:         link.AccountID,
+		PlatformID:        link.PlatformID,
+		CommissionPercent: link.CommissionPercent,
+		CreatedAt:         link.CreatedAt,
+		UpdatedAt:         link.UpdatedAt,
+	}
+}
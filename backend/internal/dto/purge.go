@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// PurgeOutput resume uma execução da remoção definitiva de registros excluídos
+type PurgeOutput struct {
+	Before   time.Time `json:"before"`
+	DryRun   bool      `json:"dry_run"`
+	Invoices int64     `json:"invoices"`
+	Accounts int64     `json:"accounts"`
+}
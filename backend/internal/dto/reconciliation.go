@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// BalanceReconciliationOutput representa a conciliação do saldo de uma conta nas respostas da API
+// Drift é o saldo gravado menos o recalculado pelo razão; Opened é falso enquanto a conta não tiver o saldo de
+// abertura no razão, que então seria OpeningBalance
+type BalanceReconciliationOutput struct {
+	AccountID      string    `json:"account_id"`
+	Balance        float64   `json:"balance"`
+	LedgerBalance  float64   `json:"ledger_balance"`
+	Drift          float64   `json:"drift"`
+	Opened         bool      `json:"opened"`
+	OpeningBalance float64   `json:"opening_balance,omitempty"`
+	Corrected      bool      `json:"corrected"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// FromBalanceReconciliation converte domain.BalanceReconciliation para BalanceReconciliationOutput
+func FromBalanceReconciliation(reconciliation *domain.BalanceReconciliation) *BalanceReconciliationOutput {
+	output := &BalanceReconciliationOutput{
+		AccountID:     reconciliation.AccountID,
+		Balance:       reconciliation.Balance,
+		LedgerBalance: reconciliation.LedgerBalance,
+		Drift:         reconciliation.Drift(),
+		Opened:        reconciliation.Opened,
+		Corrected:     reconciliation.Corrected,
+		CheckedAt:     reconciliation.CheckedAt,
+	}
+	if !reconciliation.Opened {
+		output.OpeningBalance = reconciliation.OpeningBalance
+	}
+	return output
+}
@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RefundPolicyInput representa o limite de aprovação dos reembolsos enviado pela conta; 0 dispensa a aprovação
+type RefundPolicyInput struct {
+	ApprovalThreshold float64 `json:"approval_threshold"`
+}
+
+// RefundPolicyOutput representa a política de reembolso da conta nas respostas da API
+// WindowDays é o prazo de reembolso que vale para a conta, definido pelos administradores, e UpdatedAt fica vazio
+// enquanto a conta não definiu a política
+type RefundPolicyOutput struct {
+	ApprovalThreshold float64    `json:"approval_threshold"`
+	WindowDays        int        `json:"window_days"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// FromRefundPolicy converte domain.RefundPolicy para RefundPolicyOutput
+func FromRefundPolicy(policy *domain.RefundPolicy) RefundPolicyOutput {
+	output := RefundPolicyOutput{ApprovalThreshold: policy.ApprovalThreshold}
+	if !policy.UpdatedAt.IsZero() {
+		output.UpdatedAt = &policy.UpdatedAt
+	}
+	return output
+}
+
+// RefundInput representa o pedido de reembolso de uma fatura; Amount 0 reembolsa o que ainda não foi reembolsado
+type RefundInput struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+// RefundDecisionInput representa a decisão sobre um reembolso pendente: "approved" ou "rejected"
+type RefundDecisionInput struct {
+	Decision string `json:"decision"`
+}
+
+// RefundOutput representa um reembolso nas respostas da API
+type RefundOutput struct {
+	ID          string              `json:"id"`
+	InvoiceID   string              `json:"invoice_id"`
+	Amount      float64             `json:"amount"`
+	Reason      string              `json:"reason,omitempty"`
+	Status      domain.RefundStatus `json:"status"`
+	RequestedBy string              `json:"requested_by"`
+	DecidedBy   string              `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time          `json:"decided_at,omitempty"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// FromRefund converte domain.Refund para RefundOutput
+func FromRefund(refund *domain.Refund) *RefundOutput {
+	return &RefundOutput{
+		ID:          refund.ID,
+		InvoiceID:   refund.InvoiceID,
+		Amount:      refund.Amount,
+		Reason:      refund.Reason,
+		Status:      refund.Status,
+		RequestedBy: refund.RequestedBy,
+		DecidedBy:   refund.DecidedBy,
+		DecidedAt:   refund.DecidedAt,
+		ExpiresAt:   refund.ExpiresAt,
+		CreatedAt:   refund.CreatedAt,
+	}
+}
+
+// FromRefunds converte a lista de reembolsos
+func FromRefunds(refunds []*domain.Refund) []*RefundOutput {
+	output := make([]*RefundOutput, len(refunds))
+	for i, refund := range refunds {
+		output[i] = FromRefund(refund)
+	}
+	return output
+}
+
+// RefundWindowInput representa o prazo de reembolso da conta definido pelos administradores, em dias contados da
+// criação da fatura; 0 desativa o prazo
+type RefundWindowInput struct {
+	Days int `json:"days"`
+}
+
+// RefundWindowOutput representa o prazo de reembolso que vale para a conta: o próprio dela ou o padrão do gateway
+// UpdatedAt fica vazio enquanto a conta usa o prazo padrão
+type RefundWindowOutput struct {
+	AccountID string     `json:"account_id"`
+	Days      int        `json:"days"`
+	Custom    bool       `json:"custom"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// FromRefundWindow converte o prazo da conta, nil se ela usa o padrão, e o prazo padrão para RefundWindowOutput
+func FromRefundWindow(accountID string, window *domain.AccountRefundWindow, defaultDays int) *RefundWindowOutput {
+	output := &RefundWindowOutput{AccountID: accountID, Days: defaultDays}
+	if window != nil {
+		output.Days = window.Days
+		output.Custom = true
+		output.UpdatedAt = &window.UpdatedAt
+	}
+	return output
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// RetentionOutput resume uma execução da política de retenção
+type RetentionOutput struct {
+	InvoicesBefore   time.Time `json:"invoices_before"`
+	AuditLogsBefore  time.Time `json:"audit_logs_before"`
+	DryRun           bool      `json:"dry_run"`
+	ArchivedInvoices int64     `json:"archived_invoices"`
+	PrunedAuditLogs  int64     `json:"pruned_audit_logs"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RiskPolicyInput representa os limites de score de risco enviados pela conta, de 0 a 100
+// Scores abaixo de ApproveBelow são aprovados, acima de RejectAbove recusados e os demais vão para revisão manual
+type RiskPolicyInput struct {
+	ApproveBelow float64 `json:"approve_below"`
+	RejectAbove  float64 `json:"reject_above"`
+}
+
+// RiskPolicyOutput representa os limites de score da conta nas respostas da API
+// UpdatedAt fica vazio enquanto a conta usa os limites padrão
+type RiskPolicyOutput struct {
+	ApproveBelow float64    `json:"approve_below"`
+	RejectAbove  float64    `json:"reject_above"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// FromRiskPolicy converte domain.RiskPolicy para RiskPolicyOutput
+func FromRiskPolicy(policy *domain.RiskPolicy) RiskPolicyOutput {
+	output := RiskPolicyOutput{ApproveBelow: policy.ApproveBelow, RejectAbove: policy.RejectAbove}
+	if !policy.UpdatedAt.IsZero() {
+		output.UpdatedAt = &policy.UpdatedAt
+	}
+	return output
+}
+
+// ReviewDecisionInput representa a decisão de um analista sobre a revisão: "approved" ou "declined"
+type ReviewDecisionInput struct {
+	Decision string `json:"decision"`
+}
+
+// ReviewOutput representa uma fatura da fila de revisão manual nas respostas da API
+type ReviewOutput struct {
+	InvoiceID string              `json:"invoice_id"`
+	AccountID string              `json:"account_id"`
+	Amount    float64             `json:"amount"`
+	Score     float64             `json:"score"`
+	Status    domain.ReviewStatus `json:"status"`
+	DueAt     time.Time           `json:"due_at"`
+	DecidedBy string              `json:"decided_by,omitempty"`
+	DecidedAt *time.Time          `json:"decided_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// FromInvoiceReview converte domain.InvoiceReview para ReviewOutput
+func FromInvoiceReview(review *domain.InvoiceReview) *ReviewOutput {
+	return &ReviewOutput{
+		InvoiceID: review.InvoiceID,
+		AccountID: review.AccountID,
+		Amount:    review.Amount,
+		Score:     review.Score,
+		Status:    review.Status,
+		DueAt:     review.DueAt,
+		DecidedBy: review.DecidedBy,
+		DecidedAt: review.DecidedAt,
+		CreatedAt: review.CreatedAt,
+	}
+}
+
+// FromInvoiceReviews converte a lista de revisões
+func FromInvoiceReviews(reviews []*domain.InvoiceReview) []*ReviewOutput {
+	output := make([]*ReviewOutput, len(reviews))
+	for i, review := range reviews {
+		output[i] = FromInvoiceReview(review)
+	}
+	return output
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SecurityWebhookInput representa a assinatura dos eventos de segurança enviada pela conta
+// Events vazio assina todos os tipos
+type SecurityWebhookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// SecurityWebhookOutput representa a assinatura dos eventos de segurança da conta nas respostas da API
+type SecurityWebhookOutput struct {
+	URL       string                     `json:"url"`
+	Events    []domain.SecurityEventType `json:"events"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// FromSecurityWebhook converte domain.SecurityWebhook para SecurityWebhookOutput
+func FromSecurityWebhook(webhook *domain.SecurityWebhook) SecurityWebhookOutput {
+	output := SecurityWebhookOutput{
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+	if output.Events == nil {
+		output.Events = []domain.SecurityEventType{}
+	}
+	return output
+}
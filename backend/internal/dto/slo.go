@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// Valores de SLOReportOutput.Status e RouteSLOOutput.Status
+const (
+	SLOOK = "ok"
+	// SLOWarning indica que a janela longa consome o orçamento de requisições lentas mais rápido que o permitido
+	SLOWarning = "warning"
+	// SLOCritical indica que as duas janelas consomem o orçamento acima da taxa de LATENCY_SLO_FAST_BURN
+	SLOCritical = "critical"
+)
+
+// LatencyObjectiveOutput representa o objetivo de latência em uso
+type LatencyObjectiveOutput struct {
+	Target          float64            `json:"target"`
+	ThresholdMs     float64            `json:"threshold_ms"`
+	RouteThresholds map[string]float64 `json:"route_thresholds_ms"`
+	FastBurnRate    float64            `json:"fast_burn_rate"`
+}
+
+// LatencyWindowOutput representa a latência de uma rota em uma janela
+type LatencyWindowOutput struct {
+	Requests uint64  `json:"requests"`
+	Slow     uint64  `json:"slow"`
+	P95Ms    float64 `json:"p95_ms"`
+	P99Ms    float64 `json:"p99_ms"`
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// RouteSLOOutput representa o objetivo de latência de uma rota
+type RouteSLOOutput struct {
+	Route       string              `json:"route"`
+	Status      string              `json:"status"`
+	ThresholdMs float64             `json:"threshold_ms"`
+	Short       LatencyWindowOutput `json:"short"`
+	Long        LatencyWindowOutput `json:"long"`
+}
+
+// SLOReportOutput representa a resposta de GET /admin/slo
+// Status é o da soma de todas as rotas; cada rota traz o próprio
+type SLOReportOutput struct {
+	Status      string                  `json:"status"`
+	Objective   *LatencyObjectiveOutput `json:"objective"`
+	Window      string                  `json:"window"`
+	ShortWindow string                  `json:"short_window"`
+	Short       LatencyWindowOutput     `json:"short"`
+	Long        LatencyWindowOutput     `json:"long"`
+	Routes      []RouteSLOOutput        `json:"routes"`
+}
+
+// FromLatencyObjective converte o objetivo de latência na resposta
+func FromLatencyObjective(objective metrics.LatencyObjective) *LatencyObjectiveOutput {
+	output := &LatencyObjectiveOutput{
+		Target:          objective.Target,
+		ThresholdMs:     milliseconds(objective.Threshold),
+		RouteThresholds: make(map[string]float64, len(objective.RouteThresholds)),
+		FastBurnRate:    objective.FastBurnRate,
+	}
+	for route, threshold := range objective.RouteThresholds {
+		output.RouteThresholds[route] = milliseconds(threshold)
+	}
+	return output
+}
+
+// FromLatencyWindow converte o resumo de uma janela na resposta
+func FromLatencyWindow(window metrics.LatencyWindow) LatencyWindowOutput {
+	return LatencyWindowOutput{
+		Requests: window.Requests,
+		Slow:     window.Slow,
+		P95Ms:    milliseconds(window.P95),
+		P99Ms:    milliseconds(window.P99),
+		BurnRate: window.BurnRate,
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
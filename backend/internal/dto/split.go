@@ -0,0 +1,51 @@
+package dto
+
+import "github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+
+// SplitRuleInput reparte o valor da fatura com uma conta recebedora por percentual ou por valor fixo, nunca os dois
+// ChargeFee indica se a parte paga a taxa de divisão; se nenhuma regra pagar, todas pagam
+type SplitRuleInput struct {
+	RecipientID string  `json:"recipient_id"`
+	Percentage  float64 `json:"percentage"`
+	Amount      float64 `json:"amount"`
+	ChargeFee   bool    `json:"charge_fee"`
+}
+
+// SplitOutput é a parte de uma conta recebedora na fatura; ela recebe Net, a parte bruta menos a taxa
+type SplitOutput struct {
+	RecipientID string  `json:"recipient_id"`
+	Amount      float64 `json:"amount"`
+	Fee         float64 `json:"fee"`
+	Net         float64 `json:"net"`
+}
+
+// ToSplitRules converte as regras de divisão da entrada para o domínio
+func ToSplitRules(inputs []SplitRuleInput) []domain.SplitRule {
+	rules := make([]domain.SplitRule, len(inputs))
+	for i, input := range inputs {
+		rules[i] = domain.SplitRule{
+			RecipientID: input.RecipientID,
+			Percentage:  input.Percentage,
+			Amount:      input.Amount,
+			ChargeFee:   input.ChargeFee,
+		}
+	}
+	return rules
+}
+
+// FromInvoiceSplits converte as partes da fatura para SplitOutput; sem divisão retorna nil
+func FromInvoiceSplits(splits []*domain.InvoiceSplit) []SplitOutput {
+	if len(splits) == 0 {
+		return nil
+	}
+	output := make([]SplitOutput, len(splits))
+	for i, split := range splits {
+		output[i] = SplitOutput{
+			RecipientID: split.RecipientID,
+			Amount:      split.Amount,
+			Fee:         split.Fee,
+			Net:         split.Net(),
+		}
+	}
+	return output
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateStandingOrderInput representa uma ordem permanente criada pela conta
+// Informe Percent, a porcentagem do saldo varrida a cada período, ou Amount, o valor fixo transferido; StartAt é a
+// data da primeira transferência, agora quando vazia, e WebhookURL, opcional, recebe os eventos das transferências
+// não feitas
+type CreateStandingOrderInput struct {
+	DestinationAccountID string     `json:"destination_account_id"`
+	Percent              float64    `json:"percent"`
+	Amount               float64    `json:"amount"`
+	IntervalDays         int        `json:"interval_days"`
+	StartAt              *time.Time `json:"start_at"`
+	WebhookURL           string     `json:"webhook_url"`
+}
+
+// StandingOrderOutput representa uma ordem permanente nas respostas da API
+type StandingOrderOutput struct {
+	ID                   string                     `json:"id"`
+	DestinationAccountID string                     `json:"destination_account_id"`
+	Percent              float64                    `json:"percent,omitempty"`
+	Amount               float64                    `json:"amount,omitempty"`
+	IntervalDays         int                        `json:"interval_days"`
+	Status               domain.StandingOrderStatus `json:"status"`
+	WebhookURL           string                     `json:"webhook_url,omitempty"`
+	NextRunAt            *time.Time                 `json:"next_run_at,omitempty"`
+	LastRunAt            *time.Time                 `json:"last_run_at,omitempty"`
+	LastAmount           float64                    `json:"last_amount"`
+	FailedRuns           int                        `json:"failed_runs"`
+	LastFailure          string                     `json:"last_failure,omitempty"`
+	CancelledAt          *time.Time                 `json:"cancelled_at,omitempty"`
+	CreatedAt            time.Time                  `json:"created_at"`
+	UpdatedAt            time.Time                  `json:"updated_at"`
+}
+
+// FromStandingOrder converte domain.StandingOrder para StandingOrderOutput
+func FromStandingOrder(order *domain.StandingOrder) *StandingOrderOutput {
+	return &StandingOrderOutput{
+		ID:                   order.ID,
+		DestinationAccountID: order.DestinationID,
+		Percent:              order.Percent,
+		Amount:               order.Amount,
+		IntervalDays:         order.IntervalDays,
+		Status:               order.Status,
+		WebhookURL:           order.WebhookURL,
+		NextRunAt:            order.NextRunAt,
+		LastRunAt:            order.LastRunAt,
+		LastAmount:           order.LastAmount,
+		FailedRuns:           order.FailedRuns,
+		LastFailure:          order.LastFailure,
+		CancelledAt:          order.CancelledAt,
+		CreatedAt:            order.CreatedAt,
+		UpdatedAt:            order.UpdatedAt,
+	}
+}
+
+// FromStandingOrders converte a lista de ordens permanentes
+func FromStandingOrders(orders []*domain.StandingOrder) []*StandingOrderOutput {
+	output := make([]*StandingOrderOutput, len(orders))
+	for i, order := range orders {
+		output[i] = FromStandingOrder(order)
+	}
+	return output
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateSubscriptionInput representa uma assinatura criada pela conta com o cartão do pagador
+// StartAt é a data da primeira cobrança, agora quando vazia; WebhookURL, opcional, recebe os eventos das
+// cobranças recusadas
+type CreateSubscriptionInput struct {
+	Amount         float64    `json:"amount"`
+	Description    string     `json:"description"`
+	IntervalDays   int        `json:"interval_days"`
+	StartAt        *time.Time `json:"start_at"`
+	WebhookURL     string     `json:"webhook_url"`
+	CardNumber     string     `json:"card_number"`
+	CVV            string     `json:"cvv"`
+	ExpiryMonth    int        `json:"expiry_month"`
+	ExpiryYear     int        `json:"expiry_year"`
+	CardholderName string     `json:"cardholder_name"`
+}
+
+// LogValue registra a entrada sem o CVV e com o número do cartão mascarado
+func (input CreateSubscriptionInput) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("amount", input.Amount),
+		slog.String("description", input.Description),
+		slog.Int("interval_days", input.IntervalDays),
+		slog.String("card_number", carddata.Mask(input.CardNumber)),
+	)
+}
+
+// String impede que fmt exponha os dados do cartão
+func (input CreateSubscriptionInput) String() string {
+	return input.LogValue().String()
+}
+
+// GoString impede que %#v exponha os dados do cartão
+func (input CreateSubscriptionInput) GoString() string {
+	return input.String()
+}
+
+// SubscriptionOutput representa uma assinatura nas respostas da API
+type SubscriptionOutput struct {
+	ID             string                    `json:"id"`
+	Amount         float64                   `json:"amount"`
+	Description    string                    `json:"description,omitempty"`
+	IntervalDays   int                       `json:"interval_days"`
+	Status         domain.SubscriptionStatus `json:"status"`
+	CardToken      string                    `json:"card_token"`
+	CardBrand      string                    `json:"card_brand"`
+	CardLastDigits string                    `json:"card_last_digits"`
+	WebhookURL     string                    `json:"webhook_url,omitempty"`
+	DueAt          time.Time                 `json:"due_at"`
+	NextAttemptAt  *time.Time                `json:"next_attempt_at,omitempty"`
+	FailedAttempts int                       `json:"failed_attempts"`
+	LastInvoiceID  string                    `json:"last_invoice_id,omitempty"`
+	CancelledAt    *time.Time                `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+// FromSubscription converte domain.Subscription para SubscriptionOutput
+func FromSubscription(subscription *domain.Subscription) *SubscriptionOutput {
+	return &SubscriptionOutput{
+		ID:             subscription.ID,
+		Amount:         subscription.Amount,
+		Description:    subscription.Description,
+		IntervalDays:   subscription.IntervalDays,
+		Status:         subscription.Status,
+		CardToken:      subscription.CardToken,
+		CardBrand:      subscription.CardBrand,
+		CardLastDigits: subscription.CardLastDigits,
+		WebhookURL:     subscription.WebhookURL,
+		DueAt:          subscription.DueAt,
+		NextAttemptAt:  subscription.NextAttemptAt,
+		FailedAttempts: subscription.FailedAttempts,
+		LastInvoiceID:  subscription.LastInvoiceID,
+		CancelledAt:    subscription.CancelledAt,
+		CreatedAt:      subscription.CreatedAt,
+		UpdatedAt:      subscription.UpdatedAt,
+	}
+}
+
+// FromSubscriptions converte a lista de assinaturas
+func FromSubscriptions(subscriptions []*domain.Subscription) []*SubscriptionOutput {
+	output := make([]*SubscriptionOutput, len(subscriptions))
+	for i, subscription := range subscriptions {
+		output[i] = FromSubscription(subscription)
+	}
+	return output
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+
+// InvoiceTaxOutput é um imposto de uma linha da fatura; Rate é o percentual aplicado
+type InvoiceTaxOutput struct {
+	Line   int     `json:"line"`
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// FromInvoiceTaxes converte os impostos da fatura para InvoiceTaxOutput; sem impostos retorna nil
+func FromInvoiceTaxes(taxes []*domain.InvoiceTax) []InvoiceTaxOutput {
+	if len(taxes) == 0 {
+		return nil
+	}
+	output := make([]InvoiceTaxOutput, len(taxes))
+	for i, tax := range taxes {
+		output[i] = InvoiceTaxOutput{Line: tax.Line, Name: tax.Name, Rate: tax.Rate, Amount: tax.Amount}
+	}
+	return output
+}
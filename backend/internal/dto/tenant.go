@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateTenantInput representa a criação de uma organização
+type CreateTenantInput struct {
+	Name string `json:"name"`
+}
+
+// TenantBrandingInput representa a marca da organização; os campos vazios usam a do gateway
+type TenantBrandingInput struct {
+	DisplayName  string `json:"display_name"`
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	SupportEmail string `json:"support_email"`
+}
+
+// SpendingLimitsInput representa os tetos de gasto diário e mensal; zero desativa o teto
+type SpendingLimitsInput struct {
+	Daily   float64 `json:"daily"`
+	Monthly float64 `json:"monthly"`
+}
+
+// TenantConfigInput substitui a configuração da organização; ChargebackFee e SpendingLimits ausentes voltam aos
+// padrão do gateway
+type TenantConfigInput struct {
+	Branding       TenantBrandingInput  `json:"branding"`
+	ChargebackFee  *float64             `json:"chargeback_fee"`
+	SpendingLimits *SpendingLimitsInput `json:"spending_limits"`
+}
+
+// AssignTenantInput representa a troca da organização de uma conta; vazio tira a conta da organização
+type AssignTenantInput struct {
+	TenantID string `json:"tenant_id"`
+}
+
+// TenantOutput representa uma organização nas respostas da API
+// AdminKey só é preenchida na criação e na troca da chave, as únicas vezes em que ela é exibida
+type TenantOutput struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	Branding       TenantBrandingInput  `json:"branding"`
+	ChargebackFee  *float64             `json:"chargeback_fee,omitempty"`
+	SpendingLimits *SpendingLimitsInput `json:"spending_limits,omitempty"`
+	AdminKey       string               `json:"admin_key,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// FromTenant converte domain.Tenant para TenantOutput
+func FromTenant(tenant *domain.Tenant) *TenantOutput {
+	output := &TenantOutput{
+		ID:            tenant.ID,
+		Name:          tenant.Name,
+		Branding:      TenantBrandingInput(tenant.Branding),
+		ChargebackFee: tenant.ChargebackFee,
+		CreatedAt:     tenant.CreatedAt,
+		UpdatedAt:     tenant.UpdatedAt,
+	}
+	if tenant.SpendingLimits != nil {
+		output.SpendingLimits = &SpendingLimitsInput{Daily: tenant.SpendingLimits.Daily, Monthly: tenant.SpendingLimits.Monthly}
+	}
+	return output
+}
+
+// FromTenants converte a lista de organizações
+func FromTenants(tenants []*domain.Tenant) []*TenantOutput {
+	outputs := make([]*TenantOutput, len(tenants))
+	for i, tenant := range tenants {
+		outputs[i] = FromTenant(tenant)
+	}
+	return outputs
+}
+
+// TenantBrandingOutput é a marca pública da organização, lida pelas telas das contas dela
+type TenantBrandingOutput struct {
+	TenantID string `json:"tenant_id"`
+	TenantBrandingInput
+}
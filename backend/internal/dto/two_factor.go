@@ -0,0 +1,14 @@
+package dto
+
+// TwoFactorEnrollmentOutput é o cadastro do segundo fator iniciado
+// O segredo e os códigos de recuperação só são exibidos nesta resposta
+type TwoFactorEnrollmentOutput struct {
+	Secret      string   `json:"secret"`
+	URI         string   `json:"otpauth_uri"`
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// ConfirmTwoFactorInput conclui o cadastro com o primeiro código do autenticador
+type ConfirmTwoFactorInput struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CreateUserInput representa dados para criação de um usuário do dashboard
+// Role é opcional (padrão merchant)
+type CreateUserInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role,omitempty"`
+}
+
+// UserOutput representa dados do usuário nas respostas da API, sem o hash da senha
+type UserOutput struct {
+	ID        string      `json:"id"`
+	AccountID string      `json:"account_id"`
+	Email     string      `json:"email"`
+	Role      domain.Role `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// LoginInput representa as credenciais do login
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RefreshTokenInput representa o refresh token trocado por um novo par de tokens ou revogado no logout
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenOutput representa o par de tokens emitido no login e na renovação
+type TokenOutput struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// ChangePasswordInput representa a troca de senha do usuário autenticado
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// SessionOutput representa uma sessão ativa do usuário, isto é, um refresh token ainda válido
+// LastUsedAt é o login ou a última renovação, de onde vêm IP e UserAgent
+type SessionOutput struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// FromRefreshToken converte o token ativo de uma sessão para SessionOutput
+func FromRefreshToken(token *domain.RefreshToken) SessionOutput {
+	return SessionOutput{
+		ID:         token.SessionID,
+		IP:         token.IP,
+		UserAgent:  token.UserAgent,
+		LastUsedAt: token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+	}
+}
+
+// FromUser converte domain.User para UserOutput
+func FromUser(user *domain.User) UserOutput {
+	return UserOutput{
+		ID:        user.ID,
+		AccountID: user.AccountID,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
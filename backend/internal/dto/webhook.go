@@ -0,0 +1,16 @@
+package dto
+
+// VerifyWebhookInput representa um webhook recebido pelo lojista, para conferir a assinatura calculada por ele
+// Payload é o corpo exatamente como recebido
+type VerifyWebhookInput struct {
+	Payload   string `json:"payload"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// VerifyWebhookOutput representa o resultado da verificação
+// A assinatura esperada não é devolvida, para que a rota não sirva para assinar webhooks forjados
+type VerifyWebhookOutput struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,140 @@
+package errortracker
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Event é o evento no formato do Sentry
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Transaction string            `json:"transaction,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *User             `json:"user,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Exception   *Exceptions       `json:"exception,omitempty"`
+}
+
+// User identifica quem fez a requisição: a conta, o usuário ou o serviço autenticado
+type User struct {
+	ID        string `json:"id,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// Exceptions é a lista de exceções do evento
+type Exceptions struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception é o erro capturado com o stack de onde foi registrado
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace lista os frames do mais antigo para o mais recente, como espera o Sentry
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame é uma chamada do stack
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// modulePath identifica os frames do próprio gateway
+const modulePath = "github.com/joaodematejr/imersao22/go-gateway/"
+
+// skippedPackages são os pacotes do caminho do log até o rastreador, omitidos do stack
+var skippedPackages = []string{
+	"log/slog.",
+	"runtime.",
+	modulePath + "internal/errortracker.",
+	modulePath + "internal/logging.",
+	modulePath + "internal/redact.",
+}
+
+// callerStack monta o stack de quem chamou, sem os frames do log e do rastreador
+// Chamado durante um recover, o stack ainda inclui os frames do panic
+func callerStack() *Stacktrace {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []Frame
+	for {
+		frame, more := frames.Next()
+		if !skippedFrame(frame.Function) {
+			module, function := splitFunction(frame.Function)
+			stack = append(stack, Frame{
+				Function: function,
+				Module:   module,
+				Filename: shortFile(frame.File),
+				AbsPath:  frame.File,
+				Lineno:   frame.Line,
+				InApp:    strings.HasPrefix(frame.Function, modulePath),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+
+	// runtime.Callers começa pelo frame mais recente
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return &Stacktrace{Frames: stack}
+}
+
+// skippedFrame indica os frames do log, do rastreador e do runtime
+// runtime.gopanic é mantido para marcar onde o panic começou
+func skippedFrame(function string) bool {
+	if function == "runtime.gopanic" {
+		return false
+	}
+	for _, prefix := range skippedPackages {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitFunction separa o pacote do nome da função, como em "pkg/path.(*Type).Method"
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
+
+// shortFile mantém o caminho do arquivo a partir do diretório do pacote
+func shortFile(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		if j := strings.LastIndex(path[:i], "/"); j >= 0 {
+			return path[j+1:]
+		}
+	}
+	return path
+}
@@ -0,0 +1,145 @@
+package errortracker
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// tagKeys são os atributos dos logs que viram tags pesquisáveis no rastreador
+var tagKeys = map[string]bool{
+	"request_id": true,
+	"account_id": true,
+	"invoice_id": true,
+}
+
+type skipKey struct{}
+
+// WithoutCapture retorna um contexto cujos logs de erro não são enviados ao rastreador
+// Use quando o mesmo erro já foi capturado, como na linha de acesso de uma requisição que entrou em panic
+func WithoutCapture(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKey{}, true)
+}
+
+// Handler é um slog.Handler que repassa os registros ao handler seguinte e envia ao rastreador os de nível
+// erro ou acima, com os atributos do registro e o stack de onde foram emitidos
+// Deve ficar depois do mascaramento para que os dados sensíveis não saiam do gateway
+type Handler struct {
+	next    slog.Handler
+	tracker *Tracker
+	attrs   []slog.Attr
+	group   string
+}
+
+// NewHandler envolve o handler informado com o envio ao rastreador
+func NewHandler(next slog.Handler, tracker *Tracker) *Handler {
+	return &Handler{next: next, tracker: tracker}
+}
+
+// Enabled considera os erros sempre habilitados, para que cheguem ao rastreador mesmo com o log acima de error
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelError || h.next.Enabled(ctx, level)
+}
+
+// Handle envia os erros ao rastreador e repassa o registro ao handler seguinte, se ele aceitar o nível
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelError {
+		if skip, _ := ctx.Value(skipKey{}).(bool); !skip {
+			h.tracker.Capture(h.event(ctx, record))
+		}
+	}
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs guarda os atributos fixos do logger para os eventos e os repassa ao handler seguinte
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{
+		next:    h.next.WithAttrs(attrs),
+		tracker: h.tracker,
+		attrs:   append(append([]slog.Attr(nil), h.attrs...), h.qualify(attrs)...),
+		group:   h.group,
+	}
+}
+
+// WithGroup prefixa os atributos seguintes com o grupo
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), tracker: h.tracker, attrs: h.attrs, group: h.group + name + "."}
+}
+
+// qualify prefixa os atributos com o grupo do logger
+func (h *Handler) qualify(attrs []slog.Attr) []slog.Attr {
+	if h.group == "" {
+		return attrs
+	}
+	qualified := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		qualified[i] = slog.Attr{Key: h.group + attr.Key, Value: attr.Value}
+	}
+	return qualified
+}
+
+// event monta o evento do registro: a mensagem, o atributo "error" como exceção, request_id, account_id
+// e invoice_id como tags, a rota e o autor da requisição e os demais atributos como dados extras
+func (h *Handler) event(ctx context.Context, record slog.Record) *Event {
+	event := &Event{
+		Timestamp: record.Time,
+		Level:     "error",
+		Logger:    "slog",
+		Message:   record.Message,
+		Tags:      map[string]string{},
+		Extra:     map[string]any{},
+	}
+	if record.Level > slog.LevelError {
+		event.Level = "fatal"
+	}
+
+	exception := Exception{Type: "error", Value: record.Message, Stacktrace: callerStack()}
+	addAttr := func(key string, value slog.Value) {
+		switch {
+		case key == "error":
+			exception.Value = value.String()
+		case key == "panic":
+			exception.Type = "panic"
+			exception.Value = value.String()
+		case tagKeys[key]:
+			event.Tags[key] = value.String()
+		default:
+			event.Extra[key] = value.Resolve().Any()
+		}
+	}
+	for _, attr := range h.attrs {
+		flatten("", attr, addAttr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		flatten(h.group, attr, addAttr)
+		return true
+	})
+	event.Exception = &Exceptions{Values: []Exception{exception}}
+
+	client := requestctx.ClientInfo(ctx)
+	if client.Route != "" {
+		event.Transaction = client.Route
+		event.Tags["route"] = client.Route
+	}
+	if actor := requestctx.Actor(ctx); actor != "" || client.IP != "" {
+		event.User = &User{ID: actor, IPAddress: client.IP}
+	}
+	return event
+}
+
+// flatten percorre os grupos, juntando as chaves com ponto
+func flatten(prefix string, attr slog.Attr, add func(key string, value slog.Value)) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, child := range value.Group() {
+			flatten(prefix+attr.Key+".", child, add)
+		}
+		return
+	}
+	add(strings.TrimPrefix(prefix+attr.Key, "."), value)
+}
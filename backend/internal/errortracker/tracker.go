@@ -0,0 +1,172 @@
+// Package errortracker envia os erros inesperados e os panics do gateway a um rastreador de erros compatível
+// com o protocolo do Sentry (Sentry, GlitchTip), com o contexto da requisição: rota, conta, ID e stack
+package errortracker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// queueSize limita os eventos aguardando envio; com a fila cheia os novos eventos são descartados
+const queueSize = 100
+
+// sendTimeout limita cada envio ao rastreador
+const sendTimeout = 5 * time.Second
+
+// Options identifica a implantação nos eventos
+type Options struct {
+	Environment string
+	Release     string
+}
+
+// Tracker envia os eventos ao rastreador em segundo plano, sem bloquear quem os captura
+type Tracker struct {
+	endpoint string
+	auth     string
+	options  Options
+	server   string
+	client   *http.Client
+	queue    chan *Event
+	done     chan struct{}
+
+	// mu protege closed, para que os eventos capturados depois de Close sejam descartados
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New cria o envio para o DSN informado, no formato https://<chave>@<host>/<projeto>
+func New(dsn string, options Options) (*Tracker, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.User == nil || parsed.User.Username() == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("errortracker: invalid DSN")
+	}
+	path := strings.Trim(parsed.Path, "/")
+	i := strings.LastIndex(path, "/")
+	projectID := path[i+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("errortracker: DSN without project ID")
+	}
+	prefix := ""
+	if i >= 0 {
+		prefix = "/" + path[:i]
+	}
+
+	server, _ := os.Hostname()
+	t := &Tracker{
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", parsed.Scheme, parsed.Host, prefix, projectID),
+		auth:     "Sentry sentry_version=7, sentry_client=go-gateway/1.0, sentry_key=" + parsed.User.Username(),
+		options:  options,
+		server:   server,
+		client:   &http.Client{Timeout: sendTimeout},
+		queue:    make(chan *Event, queueSize),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t, nil
+}
+
+// Capture enfileira o evento para envio, completando ID, horário e a identificação da implantação
+// Com a fila cheia o evento é descartado e contado em /metrics
+func (t *Tracker) Capture(event *Event) {
+	event.EventID = newEventID()
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.Platform = "go"
+	event.Environment = t.options.Environment
+	event.Release = t.options.Release
+	event.ServerName = t.server
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		metrics.ErrorTrackerFailuresTotal.WithLabelValues("closed").Inc()
+		return
+	}
+	select {
+	case t.queue <- event:
+	default:
+		metrics.ErrorTrackerFailuresTotal.WithLabelValues("queue_full").Inc()
+	}
+}
+
+// Pending retorna quantos eventos aguardam envio na fila
+func (t *Tracker) Pending() int {
+	return len(t.queue)
+}
+
+// Close para de aceitar eventos e aguarda o envio dos enfileirados até o fim do contexto
+// Os eventos capturados depois dele são descartados
+func (t *Tracker) Close(ctx context.Context) {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+	}
+}
+
+// run envia os eventos da fila, um de cada vez
+// As falhas só vão para /metrics: registrá-las como erro no log geraria novos eventos
+func (t *Tracker) run() {
+	defer close(t.done)
+	for event := range t.queue {
+		if err := t.send(event); err != nil {
+			metrics.ErrorTrackerFailuresTotal.WithLabelValues("send").Inc()
+		}
+	}
+}
+
+// send publica o evento no endpoint de envelopes do rastreador
+func (t *Tracker) send(event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	json.NewEncoder(&body).Encode(map[string]any{"event_id": event.EventID, "sent_at": time.Now().UTC()})
+	json.NewEncoder(&body).Encode(map[string]any{"type": "event", "length": len(payload)})
+	body.Write(payload)
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", t.auth)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("errortracker: tracker responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newEventID gera o ID do evento: 32 dígitos hexadecimais
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,7 @@
+//go:build !faultinject
+
+package faults
+
+// Enabled indica que o binário foi compilado com a injeção de falhas; sem a build tag faultinject
+// Inject não faz nada
+const Enabled = false
@@ -0,0 +1,6 @@
+//go:build faultinject
+
+package faults
+
+// Enabled indica que o binário foi compilado com a injeção de falhas
+const Enabled = true
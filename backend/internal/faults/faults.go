@@ -0,0 +1,97 @@
+// Package faults injeta falhas artificiais nas chamadas às dependências, para verificar que retentativas,
+// circuit breakers e idempotência funcionam de verdade
+// A injeção só existe nos binários compilados com a build tag faultinject; nos demais Inject não faz nada
+package faults
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// Component é a dependência que recebe as falhas
+type Component string
+
+const (
+	// Database são as operações dos repositórios SQL com retentativa; as falhas são quedas de conexão
+	Database Component = "db"
+	// Kafka é a publicação das faturas pendentes para o antifraude
+	Kafka Component = "kafka"
+	// KMS são as chamadas ao provedor de chaves
+	KMS Component = "kms"
+	// Webhook é a entrega dos webhooks de alertas e de segurança
+	Webhook Component = "webhook"
+)
+
+// Components são os componentes que aceitam falhas
+var Components = []Component{Database, Kafka, KMS, Webhook}
+
+// Rule define as falhas injetadas em um componente
+type Rule struct {
+	// ErrorRate é a fração das chamadas que falham, de 0 a 1
+	ErrorRate float64
+	// LatencyRate é a fração das chamadas atrasadas em Latency, de 0 a 1
+	LatencyRate float64
+	Latency     time.Duration
+}
+
+// Error é a falha injetada; Unwrap devolve a causa, para que ela seja tratada como a falha real do componente
+type Error struct {
+	Component Component
+	cause     error
+}
+
+func (e *Error) Error() string {
+	return "faults: injected " + string(e.Component) + " failure"
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// rules são as regras em vigor; nil não injeta nada
+var rules atomic.Pointer[map[Component]Rule]
+
+// Configure substitui as regras de injeção; sem a build tag faultinject as regras são ignoradas
+func Configure(components map[Component]Rule) {
+	rules.Store(&components)
+}
+
+// Inject decide se a chamada ao componente falha ou atrasa, conforme a regra dele
+// O atraso respeita o cancelamento de ctx; a falha é um *Error que envolve cause, que pode ser nil
+func Inject(ctx context.Context, component Component, cause error) error {
+	if !Enabled {
+		return nil
+	}
+	current := rules.Load()
+	if current == nil {
+		return nil
+	}
+	rule, ok := (*current)[component]
+	if !ok {
+		return nil
+	}
+
+	if rule.LatencyRate > 0 && rand.Float64() < rule.LatencyRate {
+		metrics.FaultsInjectedTotal.WithLabelValues(string(component), "latency").Inc()
+		slog.DebugContext(ctx, "latência injetada", "component", component, "latency", rule.Latency)
+		timer := time.NewTimer(rule.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+		metrics.FaultsInjectedTotal.WithLabelValues(string(component), "error").Inc()
+		slog.DebugContext(ctx, "falha injetada", "component", component)
+		return &Error{Component: component, cause: cause}
+	}
+	return nil
+}
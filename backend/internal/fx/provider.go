@@ -0,0 +1,86 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/breaker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// maxProviderResponse limita o corpo lido da resposta do provedor
+const maxProviderResponse = 1 << 20
+
+// providerResponse é a resposta do provedor
+type providerResponse struct {
+	Rate float64 `json:"rate"`
+}
+
+// Provider cota as moedas em um serviço externo, que recebe a moeda e a de liquidação na query string e responde
+// a cotação
+type Provider struct {
+	url     string
+	token   string
+	client  *http.Client
+	breaker *breaker.Breaker
+}
+
+// NewProvider cria o cotador do provedor em rawURL; token, opcional, vai no header Authorization
+// O circuit breaker limita cada chamada e recusa as faturas na hora com o provedor fora do ar
+func NewProvider(rawURL, token string, circuit *breaker.Breaker) *Provider {
+	return &Provider{url: rawURL, token: token, client: &http.Client{}, breaker: circuit}
+}
+
+// Rate consulta a cotação da moeda em GET {url}?currency=USD&settlement=BRL
+// 404 significa que o provedor não cota a moeda; outras respostas fora da faixa 2xx ou cotações que não sejam
+// positivas são tratadas como falha do provedor
+func (p *Provider) Rate(ctx context.Context, currency, settlement string) (float64, error) {
+	query := url.Values{"currency": {currency}, "settlement": {settlement}}
+
+	var rate float64
+	var unsupported bool
+	err := p.breaker.Execute(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		if p.token != "" {
+			req.Header.Set("Authorization", "Bearer "+p.token)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		// A moeda sem cotação é um erro de quem pediu e não conta como falha do provedor no circuit breaker
+		if resp.StatusCode == http.StatusNotFound {
+			unsupported = true
+			return nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("fx: provider responded with status %d", resp.StatusCode)
+		}
+
+		var response providerResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxProviderResponse)).Decode(&response); err != nil {
+			return fmt.Errorf("fx: invalid provider response: %w", err)
+		}
+		if response.Rate <= 0 {
+			return fmt.Errorf("fx: invalid rate %v", response.Rate)
+		}
+		rate = response.Rate
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if unsupported {
+		return 0, domain.ErrInvalidCurrency
+	}
+	return rate, nil
+}
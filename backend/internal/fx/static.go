@@ -0,0 +1,29 @@
+// Package fx implementa as cotações das faturas em outra moeda: taxas fixas da configuração ou um provedor externo
+// por HTTP
+package fx
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// StaticRates cota cada moeda por uma taxa fixa, a mesma para todas as contas
+type StaticRates struct {
+	rates map[string]float64
+}
+
+// NewStaticRates cria o cotador com as taxas de cada moeda, em unidades da moeda de liquidação
+func NewStaticRates(rates map[string]float64) *StaticRates {
+	return &StaticRates{rates: rates}
+}
+
+// Rate retorna a taxa configurada para a moeda
+// Retorna ErrInvalidCurrency se a moeda não estiver configurada
+func (r *StaticRates) Rate(ctx context.Context, currency, settlement string) (float64, error) {
+	rate, ok := r.rates[currency]
+	if !ok {
+		return 0, domain.ErrInvalidCurrency
+	}
+	return rate, nil
+}
@@ -0,0 +1,146 @@
+// Package geoip resolve o país de origem dos IPs a partir de uma base local de faixas de rede
+// A base é um CSV carregado em memória na subida, sem consultas a serviços externos
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidDatabase é retornado quando uma linha da base não é uma faixa de rede com país
+var ErrInvalidDatabase = errors.New("invalid geoip database")
+
+// ipRange é uma faixa contínua de endereços de um mesmo país
+type ipRange struct {
+	first   netip.Addr
+	last    netip.Addr
+	country string
+}
+
+// Database guarda as faixas ordenadas pelo primeiro endereço para busca binária
+type Database struct {
+	ranges []ipRange
+}
+
+// Load lê a base do arquivo informado; veja Parse para o formato
+func Load(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Parse lê uma base CSV com linhas "rede,país" (CIDR) ou "início,fim,país", como as bases gratuitas da DB-IP
+// O país é o código ISO 3166-1 de duas letras; cabeçalho, linhas vazias e comentários com # são ignorados
+// As faixas não devem se sobrepor
+func Parse(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	var ranges []ipRange
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		block, ok := parseRecord(record)
+		if !ok {
+			// A primeira linha pode ser o cabeçalho
+			if line == 0 {
+				continue
+			}
+			return nil, ErrInvalidDatabase
+		}
+		ranges = append(ranges, block)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].first.Less(ranges[j].first)
+	})
+	return &Database{ranges: ranges}, nil
+}
+
+// parseRecord converte uma linha do CSV em faixa
+func parseRecord(record []string) (ipRange, bool) {
+	var block ipRange
+	switch len(record) {
+	case 2:
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return block, false
+		}
+		prefix = prefix.Masked()
+		block.first, block.last = prefix.Addr().Unmap(), lastAddr(prefix).Unmap()
+	case 3:
+		first, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			return block, false
+		}
+		last, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			return block, false
+		}
+		block.first, block.last = first.Unmap(), last.Unmap()
+	default:
+		return block, false
+	}
+
+	block.country = strings.ToUpper(strings.TrimSpace(record[len(record)-1]))
+	if len(block.country) != 2 || block.first.Is4() != block.last.Is4() || block.last.Less(block.first) {
+		return block, false
+	}
+	return block, true
+}
+
+// lastAddr calcula o último endereço da rede
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
+
+// Country retorna o código do país do IP ou "" quando o IP é inválido ou está fora da base,
+// como os endereços de redes privadas; uma base nil também retorna ""
+func (d *Database) Country(ip string) string {
+	if d == nil {
+		return ""
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	// Última faixa que começa antes do endereço ou nele
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return addr.Less(d.ranges[i].first)
+	}) - 1
+	if i < 0 || d.ranges[i].last.Less(addr) {
+		return ""
+	}
+	return d.ranges[i].country
+}
+
+// Len retorna a quantidade de faixas carregadas
+func (d *Database) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.ranges)
+}
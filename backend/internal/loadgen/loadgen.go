@@ -0,0 +1,244 @@
+// Package loadgen gera tráfego sintético contra uma instância do gateway, com consultas de conta e criações de fatura,
+// e mede a latência de cada operação para o planejamento de capacidade
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/client"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/seed"
+)
+
+// Operações geradas
+const (
+	OperationAccountLookup = "account_lookup"
+	OperationInvoiceCreate = "invoice_create"
+)
+
+// Config define o tráfego gerado
+// As requisições saem em ritmo fixo de RPS durante Duration, divididas entre os APIKeys, e InvoiceShare, entre 0 e
+// 1, é a fração delas que cria faturas; o restante consulta a conta
+// Com MaxInFlight requisições sem resposta, as seguintes são descartadas em vez de esperar, para que um servidor
+// lento não reduza o ritmo e esconda a própria latência
+type Config struct {
+	BaseURL      string
+	APIKeys      []string
+	RPS          float64
+	Duration     time.Duration
+	InvoiceShare float64
+	MaxInFlight  int
+	Timeout      time.Duration
+	RandomSeed   int64
+}
+
+// ErrInvalidConfig é retornado por Run quando a configuração não permite gerar tráfego
+var ErrInvalidConfig = errors.New("invalid load generator config")
+
+// Report resume uma execução; Elapsed é o tempo total, em segundos, contando a espera pelas últimas respostas
+type Report struct {
+	Elapsed    float64            `json:"elapsed_seconds"`
+	Sent       int                `json:"sent"`
+	Dropped    int                `json:"dropped"`
+	Operations []*OperationReport `json:"operations"`
+}
+
+// OperationReport resume as requisições de uma operação
+// Statuses conta as respostas por status HTTP e Errors as que não chegaram a ter resposta, como os timeouts, com a
+// última delas em LastError; os percentis de latência são das requisições com resposta
+type OperationReport struct {
+	Operation string         `json:"operation"`
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	LastError string         `json:"last_error,omitempty"`
+	Statuses  map[int]int    `json:"statuses"`
+	RPS       float64        `json:"rps"`
+	Latency   LatencySummary `json:"latency"`
+}
+
+// LatencySummary são os percentis de latência em milissegundos, pelo método do rank mais próximo
+type LatencySummary struct {
+	P50 float64 `json:"p50_ms"`
+	P90 float64 `json:"p90_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// result é o desfecho de uma requisição
+type result struct {
+	operation string
+	status    int
+	latency   time.Duration
+	err       error
+}
+
+// Run gera o tráfego até o fim de Duration ou o cancelamento de ctx e retorna o resumo das requisições feitas
+// As requisições em andamento no fim são esperadas e entram no resumo
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.BaseURL == "" || len(cfg.APIKeys) == 0 || cfg.RPS <= 0 || cfg.Duration <= 0 || cfg.InvoiceShare < 0 || cfg.InvoiceShare > 1 || cfg.MaxInFlight <= 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	httpClient := &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: &http.Transport{MaxIdleConnsPerHost: cfg.MaxInFlight, MaxConnsPerHost: cfg.MaxInFlight},
+	}
+	defer httpClient.CloseIdleConnections()
+	clients := make([]*client.Client, len(cfg.APIKeys))
+	for i, apiKey := range cfg.APIKeys {
+		clients[i] = client.New(cfg.BaseURL, apiKey, client.WithHTTPClient(httpClient))
+	}
+	random := rand.New(rand.NewSource(cfg.RandomSeed))
+	// As requisições em andamento terminam mesmo com o cancelamento de ctx e entram no resumo
+	requestCtx := context.WithoutCancel(ctx)
+
+	results := make(chan result, cfg.MaxInFlight)
+	collected := make(chan map[string][]result)
+	go func() {
+		byOperation := make(map[string][]result)
+		for r := range results {
+			byOperation[r.operation] = append(byOperation[r.operation], r)
+		}
+		collected <- byOperation
+	}()
+
+	report := &Report{}
+	slots := make(chan struct{}, cfg.MaxInFlight)
+	var inFlight sync.WaitGroup
+	interval := time.Duration(float64(time.Second) / cfg.RPS)
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+loop:
+	for next := start; next.Sub(start) < cfg.Duration; next = next.Add(interval) {
+		timer.Reset(time.Until(next))
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-timer.C:
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			report.Dropped++
+			continue
+		}
+		// O sorteio fica fora das goroutines, que não podem dividir random
+		call, operation := newCall(random, cfg, clients)
+		report.Sent++
+		inFlight.Add(1)
+		go func() {
+			defer inFlight.Done()
+			defer func() { <-slots }()
+			results <- send(requestCtx, call, operation)
+		}()
+	}
+	inFlight.Wait()
+	elapsed := time.Since(start)
+	report.Elapsed = elapsed.Seconds()
+	close(results)
+
+	byOperation := <-collected
+	for _, operation := range []string{OperationAccountLookup, OperationInvoiceCreate} {
+		if rs, ok := byOperation[operation]; ok {
+			report.Operations = append(report.Operations, summarize(operation, rs, elapsed))
+		}
+	}
+	return report, nil
+}
+
+// call faz uma requisição e retorna o status HTTP da resposta de sucesso
+type call func(ctx context.Context) (int, error)
+
+// newCall sorteia a operação e a conta da próxima requisição
+func newCall(random *rand.Rand, cfg Config, clients []*client.Client) (call, string) {
+	gateway := clients[random.Intn(len(clients))]
+	if random.Float64() >= cfg.InvoiceShare {
+		return func(ctx context.Context) (int, error) {
+			_, err := gateway.GetAccount(ctx)
+			return http.StatusOK, err
+		}, OperationAccountLookup
+	}
+
+	number := seed.CardNumber(random)
+	input := client.CreateInvoiceInput{
+		Amount:         seed.Amount(random),
+		Description:    seed.Description(random),
+		PaymentType:    "credit_card",
+		CardNumber:     number,
+		CVV:            seed.CVV(random, number),
+		ExpiryMonth:    1 + random.Intn(12),
+		ExpiryYear:     time.Now().Year() + 1 + random.Intn(5),
+		CardholderName: seed.PayerName(random),
+	}
+	return func(ctx context.Context) (int, error) {
+		_, err := gateway.CreateInvoice(ctx, input)
+		return http.StatusCreated, err
+	}, OperationInvoiceCreate
+}
+
+// send faz a requisição e mede a latência até o fim da leitura da resposta
+// As recusas do gateway contam pelo status; só os erros sem resposta, como os timeouts, ficam em err
+func send(ctx context.Context, c call, operation string) result {
+	started := time.Now()
+	status, err := c(ctx)
+	latency := time.Since(started)
+	if err != nil {
+		if status = client.StatusCode(err); status == 0 {
+			return result{operation: operation, err: err}
+		}
+	}
+	return result{operation: operation, status: status, latency: latency}
+}
+
+// summarize resume os resultados de uma operação ao longo de elapsed
+func summarize(operation string, results []result, elapsed time.Duration) *OperationReport {
+	report := &OperationReport{Operation: operation, Requests: len(results), Statuses: make(map[int]int)}
+	latencies := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if r.err != nil {
+			report.Errors++
+			report.LastError = r.err.Error()
+			continue
+		}
+		report.Statuses[r.status]++
+		latencies = append(latencies, r.latency)
+	}
+	if elapsed > 0 {
+		report.RPS = float64(len(results)) / elapsed.Seconds()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.Latency = LatencySummary{
+		P50: percentile(latencies, 0.50),
+		P90: percentile(latencies, 0.90),
+		P95: percentile(latencies, 0.95),
+		P99: percentile(latencies, 0.99),
+	}
+	if len(latencies) > 0 {
+		report.Latency.Max = milliseconds(latencies[len(latencies)-1])
+	}
+	return report
+}
+
+// percentile retorna em milissegundos o percentil q, entre 0 e 1, das latências em ordem crescente
+func percentile(sorted []time.Duration, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return milliseconds(sorted[max(rank, 1)-1])
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
@@ -0,0 +1,48 @@
+package locker
+
+import (
+	"context"
+	"sync"
+)
+
+// LocalLocker guarda os locks na memória do processo; só coordena as tarefas de uma única instância
+type LocalLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewLocal cria o locker em memória
+func NewLocal() *LocalLocker {
+	return &LocalLocker{held: map[string]bool{}}
+}
+
+// TryAcquire adquire o lock se nenhuma outra tarefa do processo o segura
+func (l *LocalLocker) TryAcquire(_ context.Context, name string) (Lock, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return &localLock{locker: l, name: name}, true, nil
+}
+
+// localLock nunca é perdido
+type localLock struct {
+	locker *LocalLocker
+	name   string
+	once   sync.Once
+}
+
+func (l *localLock) Lost() <-chan struct{} {
+	return nil
+}
+
+func (l *localLock) Release(context.Context) error {
+	l.once.Do(func() {
+		l.locker.mu.Lock()
+		delete(l.locker.held, l.name)
+		l.locker.mu.Unlock()
+	})
+	return nil
+}
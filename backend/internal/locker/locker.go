@@ -0,0 +1,138 @@
+// Package locker garante que as tarefas em segundo plano que não podem rodar em paralelo, como a detecção de
+// anomalias, executem em apenas uma réplica por vez
+// Os locks vêm do banco (advisory locks do PostgreSQL, GET_LOCK do MySQL), do Redis ou, com uma única
+// instância, da própria memória
+package locker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// releaseTimeout limita a liberação do lock, que também acontece no desligamento, com o contexto já cancelado
+const releaseTimeout = 5 * time.Second
+
+// Locker adquire locks nomeados compartilhados entre as réplicas
+type Locker interface {
+	// TryAcquire tenta adquirir o lock sem esperar; ok é false quando outra réplica já o segura
+	TryAcquire(ctx context.Context, name string) (lock Lock, ok bool, err error)
+}
+
+// Lock é um lock adquirido
+type Lock interface {
+	// Lost é fechado quando o lock deixa de valer, como na queda da conexão que o segura
+	Lost() <-chan struct{}
+	// Release libera o lock; chamadas seguintes não fazem nada
+	Release(ctx context.Context) error
+}
+
+// Run executa task em apenas uma réplica por vez: a cada retry tenta adquirir o lock name e, com ele, executa
+// task até ela retornar ou o lock ser perdido, quando o contexto de task é cancelado
+// Bloqueia até ctx ser cancelado; depois de liberar o lock volta a disputá-lo
+func Run(ctx context.Context, locker Locker, name string, retry time.Duration, task func(ctx context.Context)) {
+	for {
+		lock, ok, err := locker.TryAcquire(ctx, name)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.ErrorContext(ctx, "erro ao adquirir o lock da tarefa", "job", name, "error", err)
+		case ok:
+			runLocked(ctx, lock, name, task)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
+
+// runLocked executa task enquanto o lock vale e o libera no fim
+func runLocked(ctx context.Context, lock Lock, name string, task func(ctx context.Context)) {
+	slog.InfoContext(ctx, "lock adquirido, tarefa executando nesta réplica", "job", name)
+	metrics.SingletonJobLeader.WithLabelValues(name).Set(1)
+	defer metrics.SingletonJobLeader.WithLabelValues(name).Set(0)
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		task(taskCtx)
+	}()
+
+	select {
+	case <-done:
+	case <-lock.Lost():
+		metrics.SingletonJobLockLostTotal.WithLabelValues(name).Inc()
+		slog.WarnContext(ctx, "lock da tarefa perdido, interrompendo a execução nesta réplica", "job", name)
+	}
+	cancel()
+	<-done
+
+	releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancelRelease()
+	if err := lock.Release(releaseCtx); err != nil {
+		slog.WarnContext(ctx, "erro ao liberar o lock da tarefa", "job", name, "error", err)
+	}
+}
+
+// heldLock é um lock verificado periodicamente enquanto está adquirido
+type heldLock struct {
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     context.CancelFunc
+	stopped  chan struct{}
+
+	releaseOnce sync.Once
+	release     func(ctx context.Context) error
+}
+
+// newHeldLock cria o lock adquirido e verifica com check, a cada interval, se ele continua valendo
+// Lost fecha na primeira falha de check; release libera o lock na origem
+func newHeldLock(interval time.Duration, check, release func(ctx context.Context) error) *heldLock {
+	ctx, stop := context.WithCancel(context.Background())
+	l := &heldLock{lost: make(chan struct{}), stop: stop, stopped: make(chan struct{}), release: release}
+	go l.watch(ctx, interval, check)
+	return l
+}
+
+func (l *heldLock) watch(ctx context.Context, interval time.Duration, check func(ctx context.Context) error) {
+	defer close(l.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := check(checkCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "lock deixou de valer", "error", err)
+			l.lostOnce.Do(func() { close(l.lost) })
+			return
+		}
+	}
+}
+
+func (l *heldLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *heldLock) Release(ctx context.Context) error {
+	var err error
+	l.releaseOnce.Do(func() {
+		l.stop()
+		<-l.stopped
+		err = l.release(ctx)
+	})
+	return err
+}
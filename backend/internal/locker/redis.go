@@ -0,0 +1,78 @@
+package locker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// keyPrefix separa os locks das demais chaves do Redis e dos demais locks do banco
+const keyPrefix = "gateway:lock:"
+
+// errLockExpired indica que o lock expirou ou passou para outra réplica antes de ser renovado
+var errLockExpired = errors.New("locker: lock expired")
+
+// renewScript prolonga o lock apenas se ele ainda for desta réplica
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript apaga o lock apenas se ele ainda for desta réplica
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker guarda os locks no Redis com SET NX e expiração, renovada a cada terço de ttl enquanto o lock vale
+// Usa uma única instância do Redis, sem o Redlock entre vários nós; se o Redis cair a expiração libera o lock
+type RedisLocker struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedis cria o locker sobre o cliente Redis informado, com locks que expiram em ttl sem renovação
+func NewRedis(client *redis.Client, ttl time.Duration) *RedisLocker {
+	return &RedisLocker{client: client, ttl: ttl}
+}
+
+// TryAcquire grava o lock com um token desta aquisição, usado para renová-lo e liberá-lo
+func (l *RedisLocker) TryAcquire(ctx context.Context, name string) (Lock, bool, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, err
+	}
+	key, value := keyPrefix+name, hex.EncodeToString(token)
+
+	reply, err := l.client.Do(ctx, "SET", key, value, "NX", "PX", l.ttl.Milliseconds())
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	renew := func(ctx context.Context) error {
+		renewed, err := renewScript.Run(ctx, l.client, []string{key}, value, l.ttl.Milliseconds())
+		if err != nil {
+			return err
+		}
+		if renewed != int64(1) {
+			return errLockExpired
+		}
+		return nil
+	}
+	release := func(ctx context.Context) error {
+		_, err := releaseScript.Run(ctx, l.client, []string{key}, value)
+		return err
+	}
+	return newHeldLock(l.ttl/3, renew, release), true, nil
+}
@@ -0,0 +1,75 @@
+package locker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"time"
+)
+
+// sqlCheckInterval é o intervalo em que a conexão que segura o lock é verificada
+const sqlCheckInterval = 5 * time.Second
+
+// SQLLocker usa os locks de sessão do banco: pg_try_advisory_lock no PostgreSQL e GET_LOCK no MySQL
+// Cada lock segura uma conexão do pool; se ela cair, o banco libera o lock e Lost é fechado
+type SQLLocker struct {
+	db      *sql.DB
+	acquire string
+	release string
+	key     func(name string) any
+}
+
+// NewPostgres cria o locker sobre os advisory locks do PostgreSQL; o nome vira a chave de 64 bits do lock
+func NewPostgres(db *sql.DB) *SQLLocker {
+	return &SQLLocker{
+		db:      db,
+		acquire: "SELECT pg_try_advisory_lock($1)::int",
+		release: "SELECT pg_advisory_unlock($1)",
+		key:     advisoryKey,
+	}
+}
+
+// NewMySQL cria o locker sobre GET_LOCK do MySQL
+func NewMySQL(db *sql.DB) *SQLLocker {
+	return &SQLLocker{
+		db:      db,
+		acquire: "SELECT GET_LOCK(?, 0)",
+		release: "SELECT RELEASE_LOCK(?)",
+		key:     func(name string) any { return keyPrefix + name },
+	}
+}
+
+// TryAcquire tenta adquirir o lock em uma conexão dedicada, devolvida ao pool quando ele é liberado
+func (l *SQLLocker) TryAcquire(ctx context.Context, name string) (Lock, bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := l.key(name)
+	// GET_LOCK retorna NULL em caso de erro; os dois bancos retornam 1 quando o lock foi adquirido
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, l.acquire, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	lock := newHeldLock(sqlCheckInterval, conn.PingContext, func(ctx context.Context) error {
+		_, err := conn.ExecContext(ctx, l.release, key)
+		// A conexão volta ao pool mesmo sem o unlock: se ela tiver caído, o banco já liberou o lock
+		return errors.Join(err, conn.Close())
+	})
+	return lock, true, nil
+}
+
+// advisoryKey converte o nome do lock na chave numérica dos advisory locks do PostgreSQL
+func advisoryKey(name string) any {
+	hash := fnv.New64a()
+	hash.Write([]byte(keyPrefix + name))
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// noTime é o horário dos registros usados apenas para converter os pares chave-valor de With
+var noTime time.Time
+
+// ContextHandler é um slog.Handler que acrescenta aos registros o ID da requisição, o trace e os campos
+// guardados no contexto por With
+// Os logs sem contexto, como os de slog.Info, não recebem esses campos; use as variantes com Context
+type ContextHandler struct {
+	next slog.Handler
+}
+
+// NewContextHandler envolve o handler informado
+func NewContextHandler(next slog.Handler) *ContextHandler {
+	return &ContextHandler{next: next}
+}
+
+// Enabled repassa a decisão ao handler seguinte
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle acrescenta os campos do contexto ao registro
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	record.AddAttrs(attrsFrom(ctx)...)
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs repassa os atributos fixos do logger ao handler seguinte
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup repassa o grupo ao handler seguinte
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{next: h.next.WithGroup(name)}
+}
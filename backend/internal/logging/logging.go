@@ -0,0 +1,83 @@
+// Package logging configura os logs estruturados do gateway: JSON em produção ou texto em desenvolvimento,
+// com o nível configurável, o mascaramento de dados sensíveis e os campos da requisição guardados no contexto
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/errortracker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redact"
+)
+
+// Format é o formato de saída dos logs
+type Format string
+
+const (
+	// FormatJSON escreve um objeto JSON por linha, para a coleta de logs em produção
+	FormatJSON Format = "json"
+	// FormatText escreve chave=valor, mais fácil de ler no terminal durante o desenvolvimento
+	FormatText Format = "text"
+)
+
+// NewHandler cria o handler dos logs no formato e no nível informados
+// Os registros passam pelo mascaramento e recebem os campos guardados no contexto por With
+// Com tracker, os erros já mascarados também vão para o rastreador de erros
+func NewHandler(w io.Writer, format Format, level slog.Leveler, tracker *errortracker.Tracker) (slog.Handler, error) {
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("logging: unsupported format %q", format)
+	}
+	if tracker != nil {
+		handler = errortracker.NewHandler(handler, tracker)
+	}
+	return NewContextHandler(redact.NewHandler(handler)), nil
+}
+
+// ParseLevel converte debug, info, warn ou error, sem diferenciar maiúsculas, no nível do slog
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return level, fmt.Errorf("logging: invalid level %q", value)
+	}
+	return level, nil
+}
+
+// Fatal registra o erro e encerra o processo, como log.Fatal
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type contextKey struct{}
+
+// With retorna um contexto cujos logs carregam os pares chave-valor informados, além dos já guardados
+// Use para os identificadores da operação, como account_id e invoice_id, que valem para todos os logs dela
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.NewRecord(noTime, 0, "", 0)
+	record.Add(args...)
+
+	attrs := append([]slog.Attr(nil), attrsFrom(ctx)...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, contextKey{}, attrs)
+}
+
+// attrsFrom retorna os atributos guardados no contexto por With
+func attrsFrom(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
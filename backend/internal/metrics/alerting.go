@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// AlertRuleValue expõe a última medida calculada por cada regra de alerta
+var AlertRuleValue = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_alert_rule_value",
+	Help: "Última medida calculada por cada regra de alerta interno.",
+}, []string{"rule_id", "name", "metric"})
+
+// AlertRuleFiring indica as regras de alerta disparadas: 1 disparada e 0 dentro do limite
+var AlertRuleFiring = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_alert_rule_firing",
+	Help: "Regras de alerta interno disparadas (1) ou dentro do limite (0).",
+}, []string{"rule_id", "name", "metric"})
+
+// AlertsFiredTotal conta os alertas internos disparados por medida
+var AlertsFiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_alerts_fired_total",
+	Help: "Alertas internos disparados, por medida (decline_rate, webhook_failures ou consumer_lag).",
+}, []string{"metric"})
+
+// AlertTotals são os contadores acumulados desde a subida lidos pelas regras de alerta
+// As regras comparam duas leituras para obter a variação em uma janela
+type AlertTotals struct {
+	Approved        float64
+	Rejected        float64
+	WebhookFailures float64
+}
+
+// ReadAlertTotals lê os contadores usados pelas regras de alerta
+func ReadAlertTotals() AlertTotals {
+	return AlertTotals{
+		Approved:        counterSum(InvoiceDecisionsTotal, "status", "approved"),
+		Rejected:        counterSum(InvoiceDecisionsTotal, "status", "rejected"),
+		WebhookFailures: counterSum(WebhookDeliveriesTotal, "result", "error"),
+	}
+}
+
+// counterSum soma as séries do contador em que o rótulo label tem o valor informado
+func counterSum(vec *prometheus.CounterVec, label, value string) float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	var sum float64
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+		for _, pair := range m.GetLabel() {
+			if pair.GetName() == label && pair.GetValue() == value {
+				sum += m.GetCounter().GetValue()
+				break
+			}
+		}
+	}
+	return sum
+}
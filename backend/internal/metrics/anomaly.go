@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// AccountAnomaliesTotal conta as mudanças bruscas detectadas no comportamento das contas por tipo
+var AccountAnomaliesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_account_anomalies_total",
+	Help: "Mudanças bruscas detectadas no comportamento das contas, por tipo.",
+}, []string{"kind"})
+
+// AlertDeliveryErrorsTotal conta as falhas na entrega de alertas por canal
+var AlertDeliveryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_alert_delivery_errors_total",
+	Help: "Falhas na entrega de alertas de segurança, por canal (webhook, email ou merchant_webhook).",
+}, []string{"channel"})
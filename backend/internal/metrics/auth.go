@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// AuthAttemptsTotal conta as tentativas de autenticação por método e resultado
+var AuthAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_auth_attempts_total",
+	Help: "Tentativas de autenticação por método e resultado.",
+}, []string{"method", "result"})
+
+// SuspiciousAuthTotal conta os alertas de atividade suspeita de autenticação por padrão detectado
+var SuspiciousAuthTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_auth_suspicious_total",
+	Help: "Alertas de padrões suspeitos nas tentativas de autenticação.",
+}, []string{"pattern"})
+
+// AuthBlockedTotal conta as requisições recusadas por bloqueio temporário do IP ou do API Key
+var AuthBlockedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_auth_blocked_total",
+	Help: "Requisições recusadas porque o IP ou o API Key estava bloqueado por falhas consecutivas.",
+}, []string{"scope"})
+
+// APIKeyMissCacheHitsTotal conta as buscas de API Keys inexistentes respondidas pelo cache, sem ir ao banco
+var APIKeyMissCacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_api_key_miss_cache_hits_total",
+	Help: "Buscas de API Keys inexistentes respondidas pelo cache de chaves não encontradas.",
+})
+
+// GeoRiskTotal conta as requisições de países não permitidos pela política da conta, por ação aplicada
+var GeoRiskTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_geo_risk_total",
+	Help: "Requisições de países não permitidos pela política geográfica da conta, por ação aplicada.",
+}, []string{"action"})
+
+// GeoRiskShadowTotal conta as requisições que as políticas em modo sombra barrariam ou marcariam, por ação
+var GeoRiskShadowTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_geo_risk_shadow_total",
+	Help: "Requisições de países não permitidos por políticas geográficas em modo sombra, por ação que seria aplicada.",
+}, []string{"action"})
+
+// GeoRiskShadowDecisionsTotal conta as decisões das faturas que uma política em modo sombra barraria ou marcaria
+// As aprovadas que seriam barradas estimam os falsos positivos da política
+var GeoRiskShadowDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_geo_risk_shadow_decisions_total",
+	Help: "Decisões das faturas de países não permitidos por políticas geográficas em modo sombra, por ação que seria aplicada e status.",
+}, []string{"action", "status"})
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// BlocklistHitsTotal conta as entradas das listas de bloqueio que decidiram uma fatura, pelo escopo, tipo e ação
+var BlocklistHitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_blocklist_hits_total",
+	Help: "Entradas das listas de bloqueio que bateram com uma fatura, por escopo (global ou account), tipo do valor e ação (block ou allow).",
+}, []string{"scope", "type", "action"})
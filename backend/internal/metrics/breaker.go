@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CircuitBreakerState expõe o estado de cada circuit breaker: 0 fechado, 1 meio aberto e 2 aberto
+var CircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_circuit_breaker_state",
+	Help: "Estado do circuit breaker de cada dependência externa (0 fechado, 1 meio aberto, 2 aberto).",
+}, []string{"name"})
+
+// CircuitBreakerTransitionsTotal conta as mudanças de estado dos circuit breakers, pelo estado de destino
+var CircuitBreakerTransitionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_circuit_breaker_transitions_total",
+	Help: "Mudanças de estado do circuit breaker de cada dependência externa, pelo novo estado.",
+}, []string{"name", "state"})
+
+// CircuitBreakerRejectedTotal conta as chamadas recusadas sem chegar à dependência por estar com o circuito aberto
+var CircuitBreakerRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_circuit_breaker_rejected_total",
+	Help: "Chamadas recusadas pelo circuit breaker sem chegar à dependência externa.",
+}, []string{"name"})
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// BuildInfo identifica o build em execução nos rótulos; o valor é sempre 1
+var BuildInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_build_info",
+	Help: "Build em execução, identificado pelos rótulos (versão, commit, data do build e versão do Go); sempre 1.",
+}, []string{"version", "commit", "build_date", "go_version"})
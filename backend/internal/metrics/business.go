@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// InvoicesCreatedTotal conta as faturas criadas pelo status inicial e pela bandeira do cartão
+var InvoicesCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_invoices_created_total",
+	Help: "Faturas criadas, por status inicial (approved, rejected ou pending, em análise no antifraude) e bandeira do cartão.",
+}, []string{"status", "card_brand"})
+
+// InvoiceDecisionsTotal conta as faturas que chegaram ao status final, pela origem da decisão
+// A taxa de aprovação é a fração de approved; rejected por origem mostra os motivos das recusas
+var InvoiceDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_invoice_decisions_total",
+	Help: "Faturas aprovadas ou recusadas, por status e origem da decisão (processor, na criação, antifraud, review, a revisão manual, ou payment, a confirmação do pagamento das faturas com vencimento).",
+}, []string{"status", "source"})
+
+// InvoiceAmount mede o valor das faturas que chegaram ao status final
+// A soma é o volume financeiro e a soma dividida pela contagem, o ticket médio
+var InvoiceAmount = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gateway_invoice_amount",
+	Help:    "Valor das faturas aprovadas ou recusadas, por status.",
+	Buckets: []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000, 100000},
+}, []string{"status"})
+
+// SpendingLimitRejectionsTotal conta as faturas e lotes recusados por ultrapassarem um teto de gasto da conta
+var SpendingLimitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_spending_limit_rejections_total",
+	Help: "Faturas e lotes recusados por ultrapassarem o teto de gasto da conta, por período (daily ou monthly).",
+}, []string{"period"})
+
+// AmountLimitRejectionsTotal conta as faturas recusadas por ficarem fora da faixa de valor permitida
+var AmountLimitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_amount_limit_rejections_total",
+	Help: "Faturas recusadas por ficarem fora da faixa de valor da conta ou do meio de pagamento, por limite violado (min ou max).",
+}, []string{"bound"})
+
+// WebhookDeliveriesTotal conta as entregas de webhooks por canal e resultado
+var WebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_webhook_deliveries_total",
+	Help: "Entregas de webhooks, por canal (webhook, merchant_webhook, subscription_webhook ou standing_order_webhook) e resultado (success ou error).",
+}, []string{"channel", "result"})
+
+// ObserveWebhookDelivery registra o resultado de uma entrega de webhook
+func ObserveWebhookDelivery(channel string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	WebhookDeliveriesTotal.WithLabelValues(channel, result).Inc()
+}
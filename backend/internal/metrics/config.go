@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ConfigReloadsTotal conta os recarregamentos da configuração, por resultado
+var ConfigReloadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_config_reloads_total",
+	Help: "Recarregamentos da configuração em execução, por resultado (success ou error).",
+}, []string{"result"})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CouponRedemptionsTotal conta os usos de cupons nas faturas
+var CouponRedemptionsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_coupon_redemptions_total",
+	Help: "Usos de cupons de desconto nas faturas.",
+})
+
+// CouponDiscountTotal soma os descontos concedidos pelos cupons
+var CouponDiscountTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_coupon_discount_total",
+	Help: "Soma dos descontos concedidos pelos cupons nas faturas.",
+})
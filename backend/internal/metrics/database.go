@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DBRetriesTotal conta as retentativas de operações no banco por operação e motivo
+var DBRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_db_retries_total",
+	Help: "Retentativas de operações no banco após erros transitórios.",
+}, []string{"operation", "reason"})
+
+// DBPingDuration mede a latência dos pings feitos pelas verificações de saúde
+var DBPingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gateway_db_ping_duration_seconds",
+	Help:    "Latência do ping às dependências (bancos, Redis, Kafka e antifraude) nas verificações de saúde.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+}, []string{"pool"})
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBPoolCollector exporta as estatísticas do pgxpool no formato do Prometheus
+type DBPoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquireCount            *prometheus.Desc
+	acquireDuration         *prometheus.Desc
+	acquiredConns           *prometheus.Desc
+	canceledAcquireCount    *prometheus.Desc
+	constructingConns       *prometheus.Desc
+	emptyAcquireCount       *prometheus.Desc
+	idleConns               *prometheus.Desc
+	maxConns                *prometheus.Desc
+	totalConns              *prometheus.Desc
+	newConnsCount           *prometheus.Desc
+	maxLifetimeDestroyCount *prometheus.Desc
+	maxIdleDestroyCount     *prometheus.Desc
+}
+
+// NewDBPoolCollector cria um coletor para o pool informado
+// O nome identifica o pool no label "pool" (ex: primary, replica)
+func NewDBPoolCollector(pool *pgxpool.Pool, name string) *DBPoolCollector {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc("gateway_db_pool_"+metric, help, nil, labels)
+	}
+
+	return &DBPoolCollector{
+		pool:                    pool,
+		acquireCount:            desc("acquire_total", "Total de conexões adquiridas do pool."),
+		acquireDuration:         desc("acquire_duration_seconds_total", "Tempo total gasto adquirindo conexões do pool."),
+		acquiredConns:           desc("acquired_conns", "Conexões atualmente em uso."),
+		canceledAcquireCount:    desc("canceled_acquire_total", "Aquisições canceladas pelo contexto."),
+		constructingConns:       desc("constructing_conns", "Conexões sendo estabelecidas."),
+		emptyAcquireCount:       desc("empty_acquire_total", "Aquisições que precisaram esperar por uma conexão."),
+		idleConns:               desc("idle_conns", "Conexões ociosas no pool."),
+		maxConns:                desc("max_conns", "Tamanho máximo do pool."),
+		totalConns:              desc("total_conns", "Total de conexões abertas no pool."),
+		newConnsCount:           desc("new_conns_total", "Total de conexões criadas."),
+		maxLifetimeDestroyCount: desc("max_lifetime_destroy_total", "Conexões encerradas por atingirem o tempo máximo de vida."),
+		maxIdleDestroyCount:     desc("max_idle_destroy_total", "Conexões encerradas por ficarem ociosas."),
+	}
+}
+
+// Describe implementa prometheus.Collector
+func (c *DBPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+// Collect implementa prometheus.Collector lendo um snapshot do pool a cada coleta
+func (c *DBPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.newConnsCount, prometheus.CounterValue, float64(stat.NewConnsCount()))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeDestroyCount, prometheus.CounterValue, float64(stat.MaxLifetimeDestroyCount()))
+	ch <- prometheus.MustNewConstMetric(c.maxIdleDestroyCount, prometheus.CounterValue, float64(stat.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DisputesTotal conta as disputas registradas e encerradas, pela situação a que chegaram
+var DisputesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_disputes_total",
+	Help: "Disputas por situação alcançada (open, won ou lost).",
+}, []string{"status"})
+
+// DisputeEvidenceUploadsTotal conta os arquivos de evidência anexados às disputas, pelo tipo do arquivo
+var DisputeEvidenceUploadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_dispute_evidence_uploads_total",
+	Help: "Arquivos de evidência anexados às disputas por tipo de conteúdo.",
+}, []string{"content_type"})
+
+// ChargebackFeeAmountTotal soma as tarifas de chargeback debitadas do saldo das contas
+var ChargebackFeeAmountTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_chargeback_fee_amount_total",
+	Help: "Valor das tarifas de chargeback debitadas do saldo das contas no registro das disputas.",
+})
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrorTrackerFailuresTotal conta os eventos que não chegaram ao rastreador de erros, por motivo
+var ErrorTrackerFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_error_tracker_failures_total",
+	Help: "Eventos não entregues ao rastreador de erros, por motivo (queue_full, send ou closed).",
+}, []string{"reason"})
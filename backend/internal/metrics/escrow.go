@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// EscrowAmountTotal soma o valor retido em custódia na aprovação das faturas e o liberado para o saldo das contas
+var EscrowAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_escrow_amount_total",
+	Help: "Valor das faturas aprovadas em custódia, por evento (held, retido na aprovação, ou released, liberado para o saldo).",
+}, []string{"event"})
+
+// EscrowReleasesTotal conta as retenções liberadas, separando as liberações explícitas das feitas pelo prazo
+var EscrowReleasesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_escrow_releases_total",
+	Help: "Retenções em custódia liberadas para o saldo das contas, por origem (manual ou schedule).",
+}, []string{"source"})
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// FaultsInjectedTotal conta as falhas artificiais injetadas, por componente e tipo (error ou latency)
+var FaultsInjectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_faults_injected_total",
+	Help: "Falhas artificiais injetadas para testes de resiliência, por componente e tipo (error ou latency).",
+}, []string{"component", "kind"})
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// FXRateLocksTotal conta as faturas criadas em outra moeda, com a cotação travada
+var FXRateLocksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_fx_rate_locks_total",
+	Help: "Faturas criadas em outra moeda, com a cotação travada na criação, por moeda.",
+}, []string{"currency"})
+
+// FXRequotesTotal conta as faturas cotadas de novo porque a trava venceu antes da captura
+var FXRequotesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_fx_requotes_total",
+	Help: "Faturas em outra moeda cotadas de novo na captura, depois do vencimento da trava, por moeda.",
+}, []string{"currency"})
+
+// FXRateErrorsTotal conta as falhas do cotador, que recusam a fatura ou adiam a captura
+var FXRateErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_fx_rate_errors_total",
+	Help: "Falhas ou respostas inválidas do cotador de moedas.",
+})
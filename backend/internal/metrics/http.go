@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// HTTPRequestDuration mede a latência das requisições por rota, no formato "MÉTODO padrão", e classe de status
+// Requisições que não casam com nenhuma rota ficam com route "unmatched"
+var HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gateway_http_request_duration_seconds",
+	Help:    "Latência das requisições HTTP por rota e classe de status.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"route", "status_class"})
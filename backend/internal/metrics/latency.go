@@ -0,0 +1,262 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// latencySlots é o número de fatias da janela longa; cada fatia cobre window/latencySlots
+const latencySlots = 60
+
+// latencyBucketCount é o número de limites das faixas de latência
+const latencyBucketCount = 51
+
+// latencyBounds são os limites, em segundos, das faixas usadas para estimar os percentis
+// Crescem 25% a cada faixa, de 1ms até cerca de 70s, o que limita o erro da estimativa a uma faixa
+var latencyBounds = prometheus.ExponentialBuckets(0.001, 1.25, latencyBucketCount)
+
+// LatencyObjective define o objetivo de latência das rotas
+type LatencyObjective struct {
+	// Threshold é a latência máxima de uma requisição dentro do objetivo, nas rotas sem limite próprio
+	Threshold time.Duration
+	// RouteThresholds define o limite por rota, no formato "MÉTODO padrão" das rotas do chi (ex: "POST /invoice")
+	RouteThresholds map[string]time.Duration
+	// Target é a fração, entre 0 e 1, das requisições que devem ficar dentro do limite (ex: 0.99)
+	Target float64
+	// FastBurnRate é a taxa de consumo do orçamento, nas duas janelas, a partir da qual o objetivo está em risco
+	FastBurnRate float64
+}
+
+// threshold retorna o limite de latência da rota
+func (o LatencyObjective) threshold(route string) time.Duration {
+	if threshold, ok := o.RouteThresholds[route]; ok {
+		return threshold
+	}
+	return o.Threshold
+}
+
+// burnRate calcula quantas vezes mais rápido que o permitido o orçamento de requisições lentas está sendo gasto
+func (o LatencyObjective) burnRate(requests, slow uint64) float64 {
+	if requests == 0 || o.Target >= 1 {
+		return 0
+	}
+	return float64(slow) / float64(requests) / (1 - o.Target)
+}
+
+// latencySlot acumula as requisições de uma rota em uma fatia da janela
+type latencySlot struct {
+	// index identifica a fatia desde a época; fatias de outro index estão vencidas
+	index    int64
+	requests uint64
+	slow     uint64
+	// buckets conta as requisições por faixa; a última conta as acima de todos os limites
+	buckets [latencyBucketCount + 1]uint64
+}
+
+// add soma as requisições da fatia
+func (s *latencySlot) add(other *latencySlot) {
+	s.requests += other.requests
+	s.slow += other.slow
+	for i := range s.buckets {
+		s.buckets[i] += other.buckets[i]
+	}
+}
+
+// quantile estima o percentil q, entre 0 e 1, interpolando dentro da faixa em que ele cai
+func (s *latencySlot) quantile(q float64) time.Duration {
+	if s.requests == 0 {
+		return 0
+	}
+	// Posição da requisição do percentil na ordem crescente de latência
+	rank := math.Ceil(q * float64(s.requests))
+	var cumulative uint64
+	for i, count := range s.buckets {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		// Acima do último limite não há como interpolar
+		if i == len(latencyBounds) {
+			return seconds(latencyBounds[i-1])
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = latencyBounds[i-1]
+		}
+		upper := latencyBounds[i]
+		return seconds(lower + (upper-lower)*(rank-float64(cumulative))/float64(count))
+	}
+	return seconds(latencyBounds[len(latencyBounds)-1])
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
+
+// LatencyWindow resume a latência de uma rota em uma janela
+type LatencyWindow struct {
+	Requests uint64
+	// Slow são as requisições acima do limite da rota
+	Slow     uint64
+	P95      time.Duration
+	P99      time.Duration
+	BurnRate float64
+}
+
+// RouteLatency resume a latência de uma rota nas janelas curta e longa
+type RouteLatency struct {
+	Route     string
+	Threshold time.Duration
+	Short     LatencyWindow
+	Long      LatencyWindow
+}
+
+// LatencyReport é o retrato do objetivo de latência em um instante
+// Overall soma todas as rotas, cada requisição comparada ao limite da sua rota
+type LatencyReport struct {
+	Objective    LatencyObjective
+	Window       time.Duration
+	ShortWindow  time.Duration
+	Overall      LatencyWindow
+	OverallShort LatencyWindow
+	Routes       []RouteLatency
+}
+
+// LatencyTracker acompanha a latência por rota em janelas móveis para calcular p95, p99 e o consumo do
+// orçamento do objetivo de latência; é seguro para uso concorrente
+// A memória por rota é fixa: latencySlots fatias, reaproveitadas conforme a janela avança
+type LatencyTracker struct {
+	mu         sync.Mutex
+	objective  LatencyObjective
+	window     time.Duration
+	slot       time.Duration
+	shortSlots int64
+	routes     map[string]*[latencySlots]latencySlot
+	p95        *prometheus.Desc
+	p99        *prometheus.Desc
+	burnRate   *prometheus.Desc
+}
+
+// NewLatencyTracker cria o acompanhamento com a janela longa window e a janela curta shortWindow
+// A janela curta é arredondada para cima em fatias de window/60
+func NewLatencyTracker(objective LatencyObjective, window, shortWindow time.Duration) *LatencyTracker {
+	slot := max(window/latencySlots, time.Second)
+	labels := []string{"route", "window"}
+	return &LatencyTracker{
+		objective:  objective,
+		window:     slot * latencySlots,
+		slot:       slot,
+		shortSlots: min(max(int64((shortWindow+slot-1)/slot), 1), latencySlots),
+		routes:     make(map[string]*[latencySlots]latencySlot),
+		p95:        prometheus.NewDesc("gateway_latency_slo_p95_seconds", "Percentil 95 da latência por rota na janela móvel.", labels, nil),
+		p99:        prometheus.NewDesc("gateway_latency_slo_p99_seconds", "Percentil 99 da latência por rota na janela móvel.", labels, nil),
+		burnRate:   prometheus.NewDesc("gateway_latency_slo_burn_rate", "Taxa de consumo do orçamento de requisições lentas por rota na janela móvel.", labels, nil),
+	}
+}
+
+// SetObjective passa a comparar as próximas requisições com o novo objetivo
+func (t *LatencyTracker) SetObjective(objective LatencyObjective) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objective = objective
+}
+
+// Observe registra a duração de uma requisição atendida pela rota
+func (t *LatencyTracker) Observe(route string, elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slots, ok := t.routes[route]
+	if !ok {
+		slots = &[latencySlots]latencySlot{}
+		t.routes[route] = slots
+	}
+	index := time.Now().UnixNano() / int64(t.slot)
+	slot := &slots[index%latencySlots]
+	if slot.index != index {
+		*slot = latencySlot{index: index}
+	}
+
+	slot.requests++
+	if elapsed > t.objective.threshold(route) {
+		slot.slow++
+	}
+	slot.buckets[sort.SearchFloat64s(latencyBounds, elapsed.Seconds())]++
+}
+
+// Report calcula o retrato atual das janelas; rotas sem requisições na janela longa são descartadas
+func (t *LatencyTracker) Report() *LatencyReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &LatencyReport{
+		Objective:   t.objective,
+		Window:      t.window,
+		ShortWindow: t.slot * time.Duration(t.shortSlots),
+		Routes:      []RouteLatency{},
+	}
+	index := time.Now().UnixNano() / int64(t.slot)
+	var overall, overallShort latencySlot
+	for route, slots := range t.routes {
+		var long, short latencySlot
+		for i := range slots {
+			age := index - slots[i].index
+			if age < 0 || age >= latencySlots {
+				continue
+			}
+			long.add(&slots[i])
+			if age < t.shortSlots {
+				short.add(&slots[i])
+			}
+		}
+		if long.requests == 0 {
+			delete(t.routes, route)
+			continue
+		}
+		overall.add(&long)
+		overallShort.add(&short)
+		report.Routes = append(report.Routes, RouteLatency{
+			Route:     route,
+			Threshold: t.objective.threshold(route),
+			Short:     t.summarize(&short),
+			Long:      t.summarize(&long),
+		})
+	}
+	sort.Slice(report.Routes, func(i, j int) bool { return report.Routes[i].Route < report.Routes[j].Route })
+	report.Overall = t.summarize(&overall)
+	report.OverallShort = t.summarize(&overallShort)
+	return report
+}
+
+// summarize resume as requisições acumuladas de uma janela
+func (t *LatencyTracker) summarize(slot *latencySlot) LatencyWindow {
+	return LatencyWindow{
+		Requests: slot.requests,
+		Slow:     slot.slow,
+		P95:      slot.quantile(0.95),
+		P99:      slot.quantile(0.99),
+		BurnRate: t.objective.burnRate(slot.requests, slot.slow),
+	}
+}
+
+// Describe implementa prometheus.Collector
+func (t *LatencyTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.p95
+	ch <- t.p99
+	ch <- t.burnRate
+}
+
+// Collect implementa prometheus.Collector com o retrato das janelas a cada coleta
+func (t *LatencyTracker) Collect(ch chan<- prometheus.Metric) {
+	for _, route := range t.Report().Routes {
+		for window, summary := range map[string]LatencyWindow{"short": route.Short, "long": route.Long} {
+			ch <- prometheus.MustNewConstMetric(t.p95, prometheus.GaugeValue, summary.P95.Seconds(), route.Route, window)
+			ch <- prometheus.MustNewConstMetric(t.p99, prometheus.GaugeValue, summary.P99.Seconds(), route.Route, window)
+			ch <- prometheus.MustNewConstMetric(t.burnRate, prometheus.GaugeValue, summary.BurnRate, route.Route, window)
+		}
+	}
+}
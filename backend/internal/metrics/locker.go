@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SingletonJobLeader indica as tarefas de execução única que estão rodando nesta réplica: 1 com o lock e 0 sem
+var SingletonJobLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_singleton_job_leader",
+	Help: "Tarefas de execução única rodando nesta réplica (1 com o lock, 0 sem).",
+}, []string{"job"})
+
+// SingletonJobLockLostTotal conta os locks perdidos durante a execução das tarefas de execução única
+var SingletonJobLockLostTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_singleton_job_lock_lost_total",
+	Help: "Locks perdidos durante a execução das tarefas de execução única, que foram interrompidas.",
+}, []string{"job"})
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PayoutPendingAmountTotal soma o valor retido até a data de repasse das contas e o liberado para o saldo, no prazo
+// ou antecipado
+var PayoutPendingAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_payout_pending_amount_total",
+	Help: "Valor das faturas aprovadas aguardando o prazo de repasse, por evento (held, retido na aprovação, released, liberado para o saldo na data de repasse, ou anticipated, antecipado pela conta).",
+}, []string{"event"})
+
+// AnticipationFeeTotal soma as taxas descontadas nas antecipações
+var AnticipationFeeTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_anticipation_fee_total",
+	Help: "Taxas descontadas dos valores antecipados pelas contas.",
+})
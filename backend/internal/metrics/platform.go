@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PlatformCommissionTotal soma as comissões retidas pelas plataformas sobre o que as contas filhas recebem
+var PlatformCommissionTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_platform_commission_total",
+	Help: "Soma das comissões retidas pelas plataformas nas faturas aprovadas das contas filhas.",
+})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RateLimitedTotal conta as requisições recusadas pelo limite de requisições por API Key
+var RateLimitedTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_rate_limited_total",
+	Help: "Requisições recusadas com 429 pelo limite de requisições por API Key.",
+})
+
+// RateLimiterErrorsTotal conta as falhas do limitador, em que a requisição é aceita sem consumir o limite
+var RateLimiterErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_rate_limiter_errors_total",
+	Help: "Falhas ao consultar o limitador de requisições; a requisição é aceita.",
+})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// BalanceDriftAccounts é o número de contas com o saldo divergente do razão na última conciliação completa
+var BalanceDriftAccounts = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "gateway_balance_drift_accounts",
+	Help: "Contas com o saldo gravado divergente do recalculado pelo razão na última conciliação completa.",
+})
+
+// BalanceCorrectionsTotal conta os saldos corrigidos para o valor do razão
+var BalanceCorrectionsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_balance_corrections_total",
+	Help: "Saldos de contas corrigidos para o valor recalculado pelo razão.",
+})
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RefundsTotal conta os reembolsos pedidos e decididos, pela situação a que chegaram
+var RefundsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_refunds_total",
+	Help: "Reembolsos por situação alcançada (pending, completed, rejected ou expired).",
+}, []string{"status"})
+
+// RefundAmountTotal soma o valor dos reembolsos concluídos, debitado do saldo das contas
+var RefundAmountTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_refund_amount_total",
+	Help: "Valor dos reembolsos concluídos, debitado do saldo das contas.",
+})
+
+// RefundWindowTotal conta os reembolsos recusados por terem passado do prazo da conta e os forçados pelos
+// administradores
+var RefundWindowTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_refund_window_total",
+	Help: "Reembolsos pelo prazo de reembolso, por resultado (rejected, pedido fora do prazo, ou forced, feito pelos administradores sem o prazo e a aprovação).",
+}, []string{"result"})
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RepositoryDuration mede a latência de cada método dos repositórios
+var RepositoryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gateway_repository_duration_seconds",
+	Help:    "Latência das chamadas aos repositórios.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"repository", "method"})
+
+// RepositoryErrorsTotal conta as chamadas aos repositórios que falharam
+var RepositoryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_repository_errors_total",
+	Help: "Chamadas aos repositórios que retornaram erro inesperado.",
+}, []string{"repository", "method"})
+
+// RepositoryRowsTotal conta as linhas lidas ou afetadas pelos repositórios
+var RepositoryRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_repository_rows_total",
+	Help: "Linhas lidas ou afetadas pelas chamadas aos repositórios.",
+}, []string{"repository", "method"})
+
+// RepositorySlowCallsTotal conta as chamadas aos repositórios mais lentas que DB_SLOW_QUERY_THRESHOLD
+var RepositorySlowCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_repository_slow_calls_total",
+	Help: "Chamadas aos repositórios mais lentas que o limite de consulta lenta.",
+}, []string{"repository", "method"})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RiskDecisionsTotal conta os resultados do antifraude com score de risco, pelo destino dado pelos limites da conta
+var RiskDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_risk_decisions_total",
+	Help: "Resultados do antifraude com score de risco, por destino (approve, reject ou review).",
+}, []string{"decision"})
+
+// ReviewDecisionsTotal conta as revisões manuais decididas, pela decisão e por quem decidiu
+var ReviewDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_review_decisions_total",
+	Help: "Revisões manuais decididas, por decisão (approved ou declined) e origem (analyst ou sla, quando o prazo venceu).",
+}, []string{"status", "source"})
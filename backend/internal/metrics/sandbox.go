@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SandboxAuthorizationsTotal conta as faturas das contas em modo de teste decididas pelo adquirente simulado
+var SandboxAuthorizationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_sandbox_authorizations_total",
+	Help: "Faturas das contas em modo de teste decididas pelo adquirente simulado, por resultado (approved, rejected, pending, acima do valor do antifraude, ou timeout).",
+}, []string{"result"})
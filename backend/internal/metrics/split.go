@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SplitAmountTotal soma o valor das faturas divididas aprovadas, separando o creditado às recebedoras da taxa
+var SplitAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_split_amount_total",
+	Help: "Valor das faturas divididas aprovadas, por destino (net, creditado às contas recebedoras, ou fee, retido como taxa de divisão).",
+}, []string{"kind"})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// StandingOrderRunsTotal conta as execuções das ordens permanentes pelo resultado
+var StandingOrderRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_standing_order_runs_total",
+	Help: "Execuções das ordens permanentes, por resultado (transferred, empty, sem saldo a varrer, failed ou error).",
+}, []string{"result"})
+
+// StandingOrderTransferredAmountTotal soma o valor transferido entre as contas pelas ordens permanentes
+var StandingOrderTransferredAmountTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_standing_order_transferred_amount_total",
+	Help: "Valor transferido entre as contas pelas ordens permanentes.",
+})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SubscriptionChargesTotal conta as cobranças das assinaturas pelo resultado
+var SubscriptionChargesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_subscription_charges_total",
+	Help: "Cobranças das assinaturas, por resultado (approved, rejected ou error) e tentativa (first ou retry).",
+}, []string{"result", "attempt"})
+
+// SubscriptionsDunnedTotal conta as assinaturas que esgotaram as retentativas, pela situação final
+var SubscriptionsDunnedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_subscriptions_dunned_total",
+	Help: "Assinaturas que esgotaram as retentativas de cobrança, por situação final (past_due ou cancelled).",
+}, []string{"status"})
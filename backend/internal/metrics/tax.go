@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// InvoiceTaxAmountTotal soma os impostos calculados nas faturas pelo nome do imposto
+var InvoiceTaxAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_invoice_tax_amount_total",
+	Help: "Soma dos impostos calculados nas faturas, por imposto.",
+}, []string{"tax"})
+
+// TaxCalculationErrorsTotal conta as faturas recusadas porque os impostos não puderam ser calculados
+var TaxCalculationErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_tax_calculation_errors_total",
+	Help: "Faturas recusadas por falha ou resposta inválida da calculadora de impostos.",
+})
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// VelocityRejectionsTotal conta as faturas e lotes recusados pelas regras de frequência, pela dimensão da regra
+var VelocityRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_velocity_rejections_total",
+	Help: "Faturas e lotes recusados com 429 pelas regras de frequência, por dimensão (card, payer ou account).",
+}, []string{"dimension"})
+
+// VelocityErrorsTotal conta as falhas do contador das regras de frequência, em que a fatura segue sem a verificação
+var VelocityErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_velocity_errors_total",
+	Help: "Falhas ao consultar o contador das regras de frequência; a fatura segue sem a verificação.",
+})
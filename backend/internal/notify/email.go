@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// MailerConfig configura o servidor SMTP usado para os e-mails do gateway
+type MailerConfig struct {
+	// Addr é o host:porta do servidor; a conexão passa a TLS com STARTTLS quando o servidor oferece
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer envia e-mails de texto puro pelo SMTP
+type Mailer struct {
+	config MailerConfig
+}
+
+// NewMailer cria o envio de e-mails pelo servidor configurado
+func NewMailer(config MailerConfig) *Mailer {
+	return &Mailer{config: config}
+}
+
+// Send envia o e-mail aos destinatários, respeitando o prazo do contexto
+func (m *Mailer) Send(ctx context.Context, to []string, subject, body string) error {
+	if len(to) == 0 {
+		return nil
+	}
+	for _, address := range append([]string{m.config.From}, to...) {
+		// Quebras de linha nos endereços permitiriam injetar headers
+		if strings.ContainsAny(address, "\r\n") {
+			return errors.New("notify: invalid email address")
+		}
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.config.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, err := net.SplitHostPort(m.config.Addr)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if m.config.Username != "" {
+		// PlainAuth recusa enviar a senha sem TLS, exceto para localhost
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return err
+	}
+	for _, address := range to {
+		if err := client.Rcpt(address); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.config.From,
+		strings.Join(to, ", "),
+		mime.QEncoding.Encode("utf-8", subject),
+		time.Now().Format(time.RFC1123Z),
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	)
+	if _, err := writer.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,76 @@
+// Package notify entrega alertas do gateway fora do log: webhooks assinados e e-mails
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redact"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/webhook"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Event é o corpo JSON dos webhooks enviados pelo gateway
+type Event struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Webhook envia eventos por POST para uma URL, assinados como os demais webhooks do gateway
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhook cria o envio para a URL informada, assinando os eventos com o segredo
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// Send envia o evento com os dados sensíveis mascarados; respostas fora da faixa 2xx são tratadas como falha
+func (w *Webhook) Send(ctx context.Context, event Event) error {
+	if err := faults.Inject(ctx, faults.Webhook, nil); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	// A assinatura cobre o corpo já mascarado, que é o que o destinatário recebe
+	payload = redact.JSON(payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(w.secret, timestamp, payload))
+	// O ID da requisição e o contexto de trace permitem ao destinatário correlacionar os próprios logs com os do gateway
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		req.Header.Set(webhook.RequestIDHeader, requestID)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
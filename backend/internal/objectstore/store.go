@@ -0,0 +1,19 @@
+// Package objectstore guarda arquivos do gateway, como exportações, relatórios e evidências de disputas, em armazenamento de objetos
+// Os caminhos nunca são expostos aos clientes; os downloads passam pelas rotas do gateway
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectNotFound é retornado quando o objeto não existe no armazenamento
+var ErrObjectNotFound = errors.New("object not found")
+
+// Store grava e lê objetos por chave, como exports/<conta>/<id>/faturas.csv
+type Store interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Get abre o objeto para leitura; o chamador fecha o leitor
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,67 @@
+package pii
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// awsKeyIDContext é a chave do encryption context que vincula cada DEK cifrada ao ID da chave mestra
+const awsKeyIDContext = "gateway_key_id"
+
+// AWSKMSProvider protege as DEKs com uma chave simétrica do AWS KMS
+// A rotação automática do material da chave é transparente; ao trocar de chave, a anterior fica em previousKeyIDs
+type AWSKMSProvider struct {
+	client *kms.Client
+	keyID  string
+	known  map[string]bool
+}
+
+// NewAWSKMSProvider cria o provedor com a cadeia padrão de credenciais e região da AWS (variáveis AWS_*, perfil ou IAM role)
+// keyID é o ID ou o alias (alias/...) da chave atual; previousKeyIDs são as chaves ainda aceitas na decifragem
+func NewAWSKMSProvider(ctx context.Context, keyID string, previousKeyIDs []string) (*AWSKMSProvider, error) {
+	known, err := knownKeyIDs(keyID, previousKeyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSKMSProvider{client: kms.NewFromConfig(cfg), keyID: keyID, known: known}, nil
+}
+
+func (p *AWSKMSProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *AWSKMSProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	output, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(p.keyID),
+		Plaintext:         dek,
+		EncryptionContext: map[string]string{awsKeyIDContext: p.keyID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.CiphertextBlob, nil
+}
+
+func (p *AWSKMSProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if !p.known[keyID] {
+		return nil, ErrUnknownKey
+	}
+
+	output, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(keyID),
+		CiphertextBlob:    wrapped,
+		EncryptionContext: map[string]string{awsKeyIDContext: keyID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
@@ -0,0 +1,56 @@
+package pii
+
+import (
+	"context"
+	"errors"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/breaker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+)
+
+// breakerProvider protege as chamadas ao KMS com um circuit breaker, para que um KMS lento não segure as
+// requisições que precisam cifrar ou decifrar
+type breakerProvider struct {
+	KeyProvider
+	breaker *breaker.Breaker
+}
+
+// WithBreaker protege as chamadas do provedor com o circuit breaker
+// Chaves desconhecidas não são falhas do KMS e não contam para abrir o circuito
+func WithBreaker(keys KeyProvider, circuit *breaker.Breaker) KeyProvider {
+	return &breakerProvider{KeyProvider: keys, breaker: circuit}
+}
+
+func (p *breakerProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	var wrapped []byte
+	err := p.breaker.Execute(ctx, func(ctx context.Context) error {
+		if err := faults.Inject(ctx, faults.KMS, nil); err != nil {
+			return err
+		}
+		var err error
+		wrapped, err = p.KeyProvider.WrapKey(ctx, dek)
+		return err
+	})
+	return wrapped, err
+}
+
+func (p *breakerProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var dek []byte
+	var unknown error
+	err := p.breaker.Execute(ctx, func(ctx context.Context) error {
+		if err := faults.Inject(ctx, faults.KMS, nil); err != nil {
+			return err
+		}
+		var err error
+		dek, err = p.KeyProvider.UnwrapKey(ctx, keyID, wrapped)
+		if errors.Is(err, ErrUnknownKey) {
+			unknown = err
+			return nil
+		}
+		return err
+	})
+	if unknown != nil {
+		return nil, unknown
+	}
+	return dek, err
+}
@@ -0,0 +1,196 @@
+package pii
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// encryptedPrefix marca os valores cifrados; valores sem o prefixo são texto puro legado
+const encryptedPrefix = "enc:v1:"
+
+// ErrMalformedCiphertext é retornado quando um valor com o prefixo de cifragem não pode ser interpretado
+var ErrMalformedCiphertext = errors.New("malformed encrypted value")
+
+// dataKeyLifetime é por quanto tempo a mesma DEK cifra novos valores, poupando uma chamada ao KMS por valor
+const dataKeyLifetime = 5 * time.Minute
+
+// maxCachedDataKeys limita as DEKs decifradas mantidas em memória; ao atingir o limite o cache é esvaziado
+const maxCachedDataKeys = 4096
+
+// dataKey é a DEK usada nas cifragens até expiresAt, com a cópia cifrada pela chave mestra keyID
+type dataKey struct {
+	keyID     string
+	plaintext []byte
+	wrapped   string
+	expiresAt time.Time
+}
+
+// Encryptor cifra e decifra colunas com envelope encryption (AES-256-GCM)
+// Um Encryptor nil não cifra nada, mantendo os valores em texto puro
+type Encryptor struct {
+	keys          KeyProvider
+	blindIndexKey []byte
+
+	mu sync.Mutex
+	// current é a DEK das novas cifragens; é trocada ao expirar ou quando a chave mestra atual muda
+	current *dataKey
+	// unwrapped guarda as DEKs já decifradas pelo provedor, por ID da chave mestra e DEK cifrada
+	unwrapped map[string][]byte
+}
+
+// NewEncryptor cria um encryptor que protege as DEKs com o provedor informado
+// blindIndexKey é a chave HMAC dos índices cegos usados para buscas por igualdade
+func NewEncryptor(keys KeyProvider, blindIndexKey []byte) *Encryptor {
+	return &Encryptor{keys: keys, blindIndexKey: blindIndexKey, unwrapped: make(map[string][]byte)}
+}
+
+// IsEncrypted indica se o valor foi produzido por Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// dataKey retorna a DEK das novas cifragens, gerando e cifrando uma nova com a chave mestra atual quando necessário
+func (e *Encryptor) dataKey(ctx context.Context) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keyID := e.keys.KeyID()
+	if e.current != nil && e.current.keyID == keyID && time.Now().Before(e.current.expiresAt) {
+		return e.current, nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	wrapped, err := e.keys.WrapKey(ctx, dek)
+	if err != nil {
+		return nil, err
+	}
+
+	e.current = &dataKey{
+		keyID:     keyID,
+		plaintext: dek,
+		wrapped:   base64.RawStdEncoding.EncodeToString(wrapped),
+		expiresAt: time.Now().Add(dataKeyLifetime),
+	}
+	e.cacheDataKey(keyID, e.current.wrapped, dek)
+	return e.current, nil
+}
+
+// cacheDataKey guarda a DEK decifrada; deve ser chamado com o lock
+func (e *Encryptor) cacheDataKey(keyID, wrapped string, dek []byte) {
+	if len(e.unwrapped) >= maxCachedDataKeys {
+		clear(e.unwrapped)
+	}
+	e.unwrapped[keyID+":"+wrapped] = dek
+}
+
+// unwrapDataKey decifra a DEK pelo provedor, consultando antes as DEKs já decifradas
+func (e *Encryptor) unwrapDataKey(ctx context.Context, keyID, wrapped string) ([]byte, error) {
+	e.mu.Lock()
+	dek, ok := e.unwrapped[keyID+":"+wrapped]
+	e.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+
+	decoded, err := base64.RawStdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, ErrMalformedCiphertext
+	}
+	dek, err = e.keys.UnwrapKey(ctx, keyID, decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.cacheDataKey(keyID, wrapped, dek)
+	e.mu.Unlock()
+	return dek, nil
+}
+
+// Encrypt cifra o valor no formato enc:v1:<key id>:<DEK cifrada>:<dado cifrado>
+// A mesma DEK cifra os valores por até dataKeyLifetime; valores vazios são mantidos vazios
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if e == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	key, err := e.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newGCM(key.plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(aead, []byte(plaintext), []byte(key.keyID))
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + key.keyID + ":" + key.wrapped + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decifra um valor produzido por Encrypt
+// Valores sem o prefixo são retornados como estão, permitindo ler registros gravados antes da cifragem
+func (e *Encryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	payload, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	if e == nil {
+		return "", ErrUnknownKey
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformedCiphertext
+	}
+	keyID := parts[0]
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformedCiphertext
+	}
+
+	dek, err := e.unwrapDataKey(ctx, keyID, parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(aead, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex calcula o HMAC-SHA256 do valor normalizado para buscas e unicidade sem expor o texto puro
+func (e *Encryptor) BlindIndex(value string) string {
+	var key []byte
+	if e != nil {
+		key = e.blindIndexKey
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,40 @@
+package pii
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// keyFile é o formato do arquivo de chaves locais: a chave atual e todas as chaves mestras, em base64
+//
+//	{"current": "dev-2", "keys": {"dev-1": "<base64>", "dev-2": "<base64>"}}
+type keyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LoadKeyFile cria um provedor local com as chaves do arquivo JSON informado, para desenvolvimento
+// A rotação é feita adicionando a nova chave ao arquivo e apontando current para ela
+func LoadKeyFile(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+
+	masterKeys := make(map[string][]byte, len(file.Keys))
+	for keyID, encoded := range file.Keys {
+		masterKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in %s: %w", keyID, path, err)
+		}
+		masterKeys[keyID] = masterKey
+	}
+	return NewLocalKeyRing(file.Current, masterKeys)
+}
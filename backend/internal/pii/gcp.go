@@ -0,0 +1,106 @@
+package pii
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// gcpKMSEndpoint é a API REST do Cloud KMS
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+	// gcpKMSScope é o escopo OAuth exigido pelas operações de cifragem
+	gcpKMSScope = "https://www.googleapis.com/auth/cloudkms"
+)
+
+// GCPKMSProvider protege as DEKs com uma chave simétrica do Google Cloud KMS, pela API REST
+// Novas versões da chave (rotação) são transparentes; ao trocar de chave, a anterior fica em previousKeyNames
+type GCPKMSProvider struct {
+	client  *http.Client
+	keyName string
+	known   map[string]bool
+}
+
+// NewGCPKMSProvider cria o provedor com as credenciais padrão do Google (GOOGLE_APPLICATION_CREDENTIALS ou a conta
+// de serviço do ambiente)
+// keyName é o nome completo da chave atual (projects/.../locations/.../keyRings/.../cryptoKeys/...) e previousKeyNames
+// as chaves ainda aceitas na decifragem
+func NewGCPKMSProvider(ctx context.Context, keyName string, previousKeyNames []string) (*GCPKMSProvider, error) {
+	known, err := knownKeyIDs(keyName, previousKeyNames)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := google.DefaultClient(ctx, gcpKMSScope)
+	if err != nil {
+		return nil, err
+	}
+	return &GCPKMSProvider{client: client, keyName: keyName, known: known}, nil
+}
+
+func (p *GCPKMSProvider) KeyID() string {
+	return p.keyName
+}
+
+func (p *GCPKMSProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	var response struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	err := p.call(ctx, p.keyName, "encrypt", map[string][]byte{
+		"plaintext":                   dek,
+		"additionalAuthenticatedData": []byte(p.keyName),
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Ciphertext, nil
+}
+
+func (p *GCPKMSProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if !p.known[keyID] {
+		return nil, ErrUnknownKey
+	}
+
+	var response struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	err := p.call(ctx, keyID, "decrypt", map[string][]byte{
+		"ciphertext":                  wrapped,
+		"additionalAuthenticatedData": []byte(keyID),
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
+
+// call executa o método da chave; os campos []byte vão e voltam em base64, como a API espera
+func (p *GCPKMSProvider) call(ctx context.Context, keyName, method string, request, response any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gcpKMSEndpoint+keyName+":"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gcp kms %s responded with status %d: %s", method, resp.StatusCode, bytes.TrimSpace(message))
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
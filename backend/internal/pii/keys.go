@@ -0,0 +1,129 @@
+// Package pii implementa a criptografia em nível de coluna dos dados pessoais
+// Os valores são cifrados com chaves de dados (DEKs) protegidas pela chave mestra (KEK) do provedor de chaves:
+// AWS KMS, Google Cloud KMS ou chaves locais, para desenvolvimento
+package pii
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownKey é retornado quando o valor foi cifrado com uma chave mestra que o provedor não conhece
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyProvider protege as chaves de dados com a chave mestra gerenciada pelo KMS
+// A chave mestra nunca sai do provedor; apenas DEKs cifradas são persistidas junto aos dados
+type KeyProvider interface {
+	// KeyID identifica a chave mestra usada nas novas cifragens
+	KeyID() string
+	// WrapKey cifra uma chave de dados com a chave mestra atual
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+	// UnwrapKey decifra uma chave de dados cifrada com a chave mestra keyID
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyProvider mantém as chaves mestras em memória, lidas da configuração ou de um arquivo de chaves
+// Serve para desenvolvimento e para ambientes sem KMS
+type LocalKeyProvider struct {
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+// NewLocalKeyProvider cria um provedor com a chave mestra AES-256 informada (32 bytes)
+func NewLocalKeyProvider(keyID string, masterKey []byte) (*LocalKeyProvider, error) {
+	return NewLocalKeyRing(keyID, map[string][]byte{keyID: masterKey})
+}
+
+// NewLocalKeyRing cria um provedor com várias chaves mestras AES-256 (32 bytes cada)
+// current cifra as novas DEKs; as demais continuam decifrando as DEKs cifradas antes de uma rotação
+func NewLocalKeyRing(current string, masterKeys map[string][]byte) (*LocalKeyProvider, error) {
+	if _, ok := masterKeys[current]; !ok {
+		return nil, fmt.Errorf("current master key %q not found", current)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(masterKeys))
+	for keyID, masterKey := range masterKeys {
+		if err := validateKeyID(keyID); err != nil {
+			return nil, err
+		}
+		if len(masterKey) != 32 {
+			return nil, fmt.Errorf("master key %s must have 32 bytes, got %d", keyID, len(masterKey))
+		}
+
+		aead, err := newGCM(masterKey)
+		if err != nil {
+			return nil, err
+		}
+		aeads[keyID] = aead
+	}
+	return &LocalKeyProvider{keyID: current, aeads: aeads}, nil
+}
+
+func (p *LocalKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	return seal(p.aeads[p.keyID], dek, []byte(p.keyID))
+}
+
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.aeads[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return open(aead, wrapped, []byte(keyID))
+}
+
+// validateKeyID garante que o ID da chave mestra cabe no formato dos valores cifrados, separado por ":"
+// ARNs da AWS não servem; use o ID ou o alias da chave
+func validateKeyID(keyID string) error {
+	if keyID == "" || strings.Contains(keyID, ":") {
+		return fmt.Errorf("invalid master key id %q: must be non-empty and must not contain ':'", keyID)
+	}
+	return nil
+}
+
+// knownKeyIDs reúne a chave atual e as anteriores aceitas na decifragem, validando os IDs
+func knownKeyIDs(current string, previous []string) (map[string]bool, error) {
+	known := map[string]bool{}
+	for _, keyID := range append([]string{current}, previous...) {
+		if err := validateKeyID(keyID); err != nil {
+			return nil, err
+		}
+		known[keyID] = true
+	}
+	return known, nil
+}
+
+// newGCM cria o AES-GCM para a chave informada
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal cifra com um nonce aleatório prefixado ao resultado
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decifra um valor produzido por seal
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
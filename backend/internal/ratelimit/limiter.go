@@ -0,0 +1,40 @@
+// Package ratelimit limita as requisições por chave com token buckets
+// O limitador em Redis compartilha os limites entre as réplicas do gateway; o em memória vale por instância
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Config define o token bucket: Burst requisições de uma vez, repostas à taxa de Rate por segundo
+type Config struct {
+	Rate  float64
+	Burst int
+}
+
+// Result é a decisão do limitador para uma requisição
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter é quanto falta para a próxima ficha quando a requisição foi recusada
+	RetryAfter time.Duration
+	// ResetAfter é quanto falta para o bucket voltar a ficar cheio
+	ResetAfter time.Duration
+}
+
+// Limiter consome uma ficha do bucket da chave
+// SetConfig troca a taxa e o burst sem descartar os buckets, para recarregar a configuração em execução
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+	SetConfig(config Config)
+}
+
+// durationFor converte fichas em tempo de reposição
+func durationFor(tokens, rate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// maxMemoryBuckets limita as chaves mantidas; buckets cheios são descartados ao atingir o limite
+const maxMemoryBuckets = 100000
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryLimiter guarda os buckets na memória da instância, para desenvolvimento ou uma única réplica
+type MemoryLimiter struct {
+	config  Config
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryLimiter cria o limitador em memória
+func NewMemoryLimiter(config Config) *MemoryLimiter {
+	return &MemoryLimiter{config: config, buckets: make(map[string]*bucket)}
+}
+
+// Allow consome uma ficha do bucket da chave
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	burst := float64(l.config.Burst)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxMemoryBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: burst, updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updatedAt).Seconds()*l.config.Rate)
+	b.updatedAt = now
+
+	result := Result{Limit: l.config.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = durationFor(1-b.tokens, l.config.Rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = durationFor(burst-b.tokens, l.config.Rate)
+	return result, nil
+}
+
+// SetConfig troca a taxa e o burst; os buckets existentes passam a seguir os novos limites
+func (l *MemoryLimiter) SetConfig(config Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+}
+
+// prune remove os buckets que já estariam cheios, equivalentes a chaves nunca vistas
+func (l *MemoryLimiter) prune(now time.Time) {
+	burst := float64(l.config.Burst)
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*l.config.Rate >= burst {
+			delete(l.buckets, key)
+		}
+	}
+}
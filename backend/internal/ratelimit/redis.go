@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// keyPrefix separa as chaves do limitador das demais chaves do Redis
+const keyPrefix = "gateway:ratelimit:"
+
+// tokenBucketScript repõe e consome as fichas de forma atômica no próprio Redis
+// O relógio é o do Redis (TIME), o mesmo para todas as réplicas do gateway
+// Retorna {permitida, fichas restantes, microssegundos até a próxima ficha, microssegundos até encher}
+var tokenBucketScript = redis.NewScript(`
+-- Necessário até o Redis 6 para gravar depois de TIME; no Redis 7 não tem efeito
+redis.replicate_commands()
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000000 / rate)
+end
+
+-- tostring usaria notação científica e perderia os microssegundos de now
+redis.call('HSET', KEYS[1], 'tokens', string.format('%.6f', tokens), 'ts', string.format('%.0f', now))
+local reset = math.ceil((burst - tokens) * 1000000 / rate)
+redis.call('PEXPIRE', KEYS[1], math.ceil(reset / 1000) + 1000)
+
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// errUnexpectedReply indica uma resposta do script fora do formato esperado
+var errUnexpectedReply = errors.New("ratelimit: unexpected redis reply")
+
+// RedisLimiter guarda os buckets no Redis, compartilhados entre as réplicas do gateway
+// Cada consulta tem no máximo timeout, para que um Redis sem resposta não prenda as requisições
+type RedisLimiter struct {
+	client  *redis.Client
+	timeout time.Duration
+	config  atomic.Pointer[Config]
+}
+
+// NewRedisLimiter cria o limitador sobre o cliente Redis informado
+func NewRedisLimiter(client *redis.Client, config Config, timeout time.Duration) *RedisLimiter {
+	limiter := &RedisLimiter{client: client, timeout: timeout}
+	limiter.config.Store(&config)
+	return limiter
+}
+
+// SetConfig troca a taxa e o burst; como o script recebe os limites a cada chamada, os buckets guardados no
+// Redis passam a seguir os novos limites na próxima requisição
+func (l *RedisLimiter) SetConfig(config Config) {
+	l.config.Store(&config)
+}
+
+// Allow consome uma ficha do bucket da chave
+// Um Redis que não responde em timeout retorna o erro do prazo, e o middleware aceita a requisição
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	config := l.config.Load()
+	reply, err := tokenBucketScript.Run(ctx, l.client, []string{keyPrefix + key}, config.Rate, config.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 4 {
+		return Result{}, errUnexpectedReply
+	}
+	numbers := make([]int64, len(values))
+	for i, value := range values {
+		if numbers[i], ok = value.(int64); !ok {
+			return Result{}, errUnexpectedReply
+		}
+	}
+
+	return Result{
+		Allowed:    numbers[0] == 1,
+		Limit:      config.Burst,
+		Remaining:  int(numbers[1]),
+		RetryAfter: time.Duration(numbers[2]) * time.Microsecond,
+		ResetAfter: time.Duration(numbers[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package redact
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler é um slog.Handler que mascara a mensagem e os atributos antes de repassá-los ao handler seguinte
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler envolve o handler informado com o mascaramento
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+// Enabled repassa a decisão ao handler seguinte
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle mascara a mensagem e os atributos do registro
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, String(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(Attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs mascara os atributos fixos do logger
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = Attr(attr)
+	}
+	return &Handler{next: h.next.WithAttrs(redacted)}
+}
+
+// WithGroup repassa o grupo ao handler seguinte
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
+
+// Attr mascara um atributo do slog, resolvendo os LogValuer e percorrendo os grupos
+// Erros são registrados pela mensagem mascarada
+func Attr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, String(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, item := range group {
+			redacted[i] = Attr(item)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, String(err.Error()))
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,155 @@
+// Package redact mascara dados sensíveis antes que saiam do gateway em logs, webhooks e na auditoria:
+// API Keys, números de cartão, CVV e números de documento (CPF/CNPJ)
+// Campos são mascarados pelo nome e textos livres pelo formato do dado
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Redacted substitui o valor dos campos sensíveis e dos dados sem parte útil para diagnóstico
+const Redacted = "[REDACTED]"
+
+// sensitiveFields são os nomes de campo, normalizados por normalizeField, cujo valor é sempre mascarado
+var sensitiveFields = map[string]bool{
+	"api_key":         true,
+	"apikey":          true,
+	"x_api_key":       true,
+	"card_number":     true,
+	"cardnumber":      true,
+	"pan":             true,
+	"cvv":             true,
+	"cvc":             true,
+	"security_code":   true,
+	"document":        true,
+	"document_number": true,
+	"cpf":             true,
+	"cnpj":            true,
+	"password":        true,
+	"secret":          true,
+	"client_secret":   true,
+}
+
+var (
+	// cardPattern encontra sequências de 13 a 19 dígitos, com espaços ou hífens entre eles; só as que passam
+	// no dígito verificador de Luhn são tratadas como cartão
+	cardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	// documentPattern encontra CPF (000.000.000-00) e CNPJ (00.000.000/0000-00) formatados
+	documentPattern = regexp.MustCompile(`\b(?:\d{3}\.\d{3}\.\d{3}-\d{2}|\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2})\b`)
+	// apiKeyPattern encontra API Keys no formato gerado pelo gateway: 32 dígitos hexadecimais
+	apiKeyPattern = regexp.MustCompile(`\b[0-9a-f]{32}\b`)
+)
+
+// normalizeField deixa o nome do campo em minúsculas e com _ no lugar de - , como em X-API-Key
+func normalizeField(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+}
+
+// IsSensitive indica se o campo deve ter o valor mascarado independentemente do conteúdo
+func IsSensitive(field string) bool {
+	return sensitiveFields[normalizeField(field)]
+}
+
+// String mascara os dados sensíveis reconhecidos pelo formato em um texto livre
+// Cartões mantêm os 6 primeiros e os 4 últimos dígitos; documentos e API Keys são substituídos por Redacted
+func String(s string) string {
+	s = cardPattern.ReplaceAllStringFunc(s, func(match string) string {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, match)
+		if !luhn(digits) {
+			return match
+		}
+		return digits[:6] + strings.Repeat("*", len(digits)-10) + digits[len(digits)-4:]
+	})
+	s = documentPattern.ReplaceAllString(s, Redacted)
+	return apiKeyPattern.ReplaceAllString(s, Redacted)
+}
+
+// Field mascara o valor de um campo: por inteiro se o nome for sensível, ou pelo formato caso contrário
+func Field(name, value string) string {
+	if IsSensitive(name) {
+		return Redacted
+	}
+	return String(value)
+}
+
+// JSON mascara os campos sensíveis e os textos de um documento JSON
+// O documento só é reescrito quando algo foi mascarado; JSON inválido é tratado como texto
+func JSON(data []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(String(string(data)))
+	}
+
+	value, changed := redactJSONValue("", value)
+	if !changed {
+		return data
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return []byte(String(string(data)))
+	}
+	return redacted
+}
+
+// redactJSONValue percorre o valor decodificado mascarando pelo nome do campo e pelo formato dos textos
+func redactJSONValue(field string, value any) (any, bool) {
+	if field != "" && IsSensitive(field) && value != nil {
+		return Redacted, true
+	}
+
+	switch v := value.(type) {
+	case string:
+		redacted := String(v)
+		return redacted, redacted != v
+	case map[string]any:
+		changed := false
+		for key, item := range v {
+			redacted, itemChanged := redactJSONValue(key, item)
+			if itemChanged {
+				v[key] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []any:
+		changed := false
+		for i, item := range v {
+			redacted, itemChanged := redactJSONValue(field, item)
+			if itemChanged {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	default:
+		return value, false
+	}
+}
+
+// luhn confere o dígito verificador de um número de cartão
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,322 @@
+// Package redis implementa um cliente mínimo do protocolo RESP do Redis, suficiente para scripts Lua
+// Conexões são reaproveitadas em um pool simples; cada comando usa uma conexão por vez
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error é um erro retornado pelo próprio Redis, como NOSCRIPT ou WRONGTYPE
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Options configura a conexão com o Redis
+type Options struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// TLS é nil para conexões sem criptografia
+	TLS         *tls.Config
+	DialTimeout time.Duration
+	// MaxIdle limita as conexões ociosas mantidas no pool
+	MaxIdle int
+}
+
+// ParseURL lê uma URL redis://[usuário:senha@]host:porta[/db] ou rediss:// para TLS
+func ParseURL(rawURL string) (Options, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Options{}, err
+	}
+
+	options := Options{Addr: u.Host, DialTimeout: defaultDialTimeout, MaxIdle: 16}
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		options.TLS = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	default:
+		return Options{}, fmt.Errorf("redis: unsupported scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		options.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	if u.User != nil {
+		options.Username = u.User.Username()
+		options.Password, _ = u.User.Password()
+		// redis://:senha@host usa apenas a senha, como o AUTH do Redis anterior às ACLs
+		if _, ok := u.User.Password(); !ok {
+			options.Username, options.Password = "", u.User.Username()
+		}
+	}
+
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if options.DB, err = strconv.Atoi(db); err != nil {
+			return Options{}, fmt.Errorf("redis: invalid database %q", db)
+		}
+	}
+	return options, nil
+}
+
+// defaultDialTimeout limita a abertura das conexões quando Options não informa DialTimeout
+const defaultDialTimeout = 5 * time.Second
+
+// Client envia comandos ao Redis reaproveitando as conexões
+type Client struct {
+	options Options
+	idle    chan *conn
+}
+
+// NewClient cria o cliente; as conexões são abertas sob demanda, em no máximo DialTimeout ou no prazo do contexto,
+// o que vier antes
+func NewClient(options Options) *Client {
+	if options.MaxIdle <= 0 {
+		options.MaxIdle = 1
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = defaultDialTimeout
+	}
+	return &Client{options: options, idle: make(chan *conn, options.MaxIdle)}
+}
+
+// Do executa um comando e retorna a resposta: string, int64, []any ou nil
+// Erros do Redis são retornados como Error; a conexão continua válida depois deles
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Ping verifica se o Redis responde
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Close fecha as conexões ociosas
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: c.options.DialTimeout}
+	var netConn net.Conn
+	var err error
+	if c.options.TLS != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.options.TLS}).DialContext(ctx, "tcp", c.options.Addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.options.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if err := c.handshake(ctx, cn); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// handshake autentica e seleciona o banco de uma conexão nova
+func (c *Client) handshake(ctx context.Context, cn *conn) error {
+	if c.options.Password != "" {
+		args := []any{"AUTH", c.options.Password}
+		if c.options.Username != "" {
+			args = []any{"AUTH", c.options.Username, c.options.Password}
+		}
+		if _, err := cn.do(ctx, args); err != nil {
+			return err
+		}
+	}
+	if c.options.DB != 0 {
+		if _, err := cn.do(ctx, []any{"SELECT", c.options.DB}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// Script é um script Lua executado por EVALSHA, enviado com EVAL apenas quando o Redis ainda não o tem
+type Script struct {
+	source string
+	sha    string
+}
+
+// NewScript prepara o script informado
+func NewScript(source string) *Script {
+	sum := sha1.Sum([]byte(source))
+	return &Script{source: source, sha: hex.EncodeToString(sum[:])}
+}
+
+// Run executa o script de forma atômica com as chaves e os argumentos informados
+func (s *Script) Run(ctx context.Context, client *Client, keys []string, args ...any) (any, error) {
+	evalArgs := make([]any, 0, 3+len(keys)+len(args))
+	evalArgs = append(evalArgs, "EVALSHA", s.sha, len(keys))
+	for _, key := range keys {
+		evalArgs = append(evalArgs, key)
+	}
+	evalArgs = append(evalArgs, args...)
+
+	reply, err := client.Do(ctx, evalArgs...)
+	var redisErr Error
+	if errors.As(err, &redisErr) && strings.HasPrefix(string(redisErr), "NOSCRIPT") {
+		evalArgs[0], evalArgs[1] = "EVAL", s.source
+		return client.Do(ctx, evalArgs...)
+	}
+	return reply, err
+}
+
+// conn é uma conexão com o Redis
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// do envia um comando e lê a resposta respeitando o prazo do contexto
+func (cn *conn) do(ctx context.Context, args []any) (any, error) {
+	// Sem prazo no contexto, o valor zero remove o prazo da conexão
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if _, err := cn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+// encodeCommand codifica o comando como um array RESP de bulk strings
+func encodeCommand(args []any) []byte {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		var value string
+		switch v := arg.(type) {
+		case string:
+			value = v
+		case []byte:
+			value = string(v)
+		case int:
+			value = strconv.Itoa(v)
+		case int64:
+			value = strconv.FormatInt(v, 10)
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			value = fmt.Sprint(v)
+		}
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(value)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, value...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+// readReply lê uma resposta RESP2
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, Error(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		items := make([]any, size)
+		for i := range items {
+			// Erros dentro de arrays são devolvidos como itens, sem interromper a leitura
+			item, err := readReply(r)
+			var redisErr Error
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			if err != nil {
+				item = redisErr
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply type %q", kind)
+}
@@ -1,39 +1,135 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
 )
 
+const accountEntity = "account"
+
+const accountColumns = "id, name, email, api_key, role, scopes, tenant_id, balance, created_at, updated_at, deleted_at"
+
 // AccountRepository implementa operações de persistência para Account
+// Contas excluídas logicamente (deleted_at preenchido) ficam fora das consultas padrão
+// O e-mail é gravado cifrado, acompanhado do índice cego email_hash que garante a unicidade
 type AccountRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	dialect   Dialect
+	encryptor *pii.Encryptor
+	// findByAPIKeyStmt é preparado uma vez, pois roda em toda requisição autenticada
+	findByAPIKeyStmt *sql.Stmt
+}
+
+// NewAccountRepository cria um novo repositório de contas preparando as consultas do caminho crítico
+// Com encryptor nil os dados pessoais são gravados em texto puro
+func NewAccountRepository(ctx context.Context, db *sql.DB, dialect Dialect, encryptor *pii.Encryptor) (*AccountRepository, error) {
+	findByAPIKeyStmt, err := db.PrepareContext(ctx, dialect.rebind(
+		"SELECT "+accountColumns+" FROM accounts WHERE api_key = ? AND deleted_at IS NULL",
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountRepository{db: db, dialect: dialect, encryptor: encryptor, findByAPIKeyStmt: findByAPIKeyStmt}, nil
+}
+
+// Close libera as consultas preparadas
+func (r *AccountRepository) Close() error {
+	return r.findByAPIKeyStmt.Close()
+}
+
+// scanAccount lê uma conta na ordem de accountColumns decifrando o e-mail
+func (r *AccountRepository) scanAccount(ctx context.Context, row rowScanner) (*domain.Account, error) {
+	var account domain.Account
+	var scopes string
+	var tenantID sql.NullString
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.APIKey,
+		&account.Role,
+		&scopes,
+		&tenantID,
+		&account.Balance,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		account.DeletedAt = &deletedAt.Time
+	}
+	account.Scopes = splitScopes(scopes)
+	account.TenantID = tenantID.String
+
+	if account.Email, err = r.encryptor.Decrypt(ctx, account.Email); err != nil {
+		return nil, err
+	}
+	return &account, nil
 }
 
-// NewAccountRepository cria um novo repositório de contas
-func NewAccountRepository(db *sql.DB) *AccountRepository {
-	return &AccountRepository{db: db}
+// joinScopes grava os escopos do API Key separados por vírgulas, portável entre os bancos
+func joinScopes(scopes []domain.Permission) string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return strings.Join(values, ",")
 }
 
-// Save persiste uma nova conta no banco de dados
+// splitScopes lê os escopos gravados por joinScopes
+func splitScopes(value string) []domain.Permission {
+	var scopes []domain.Permission
+	for _, scope := range strings.Split(value, ",") {
+		if scope != "" {
+			scopes = append(scopes, domain.Permission(scope))
+		}
+	}
+	return domain.ScopesOrDefault(scopes)
+}
+
+// Save persiste uma nova conta no banco de dados registrando a inserção na auditoria
 // Retorna erro se houver falha na inserção
-func (r *AccountRepository) Save(account *domain.Account) error {
-	stmt, err := r.db.Prepare(`
-        INSERT INTO accounts (id, name, email, api_key, balance, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
-    `)
+func (r *AccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writeAudit(ctx, tx, r.dialect, accountEntity, account.ID, domain.AuditActionInsert, nil, NewAccountSnapshot(account)); err != nil {
+		return err
+	}
+
+	email, err := r.encryptor.Encrypt(ctx, account.Email)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(
+	_, err = tx.ExecContext(ctx, r.dialect.rebind(`
+        INSERT INTO accounts (id, name, email, email_hash, api_key, role, scopes, tenant_id, balance, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `),
 		account.ID,
 		account.Name,
-		account.Email,
+		email,
+		r.encryptor.BlindIndex(account.Email),
 		account.APIKey,
+		account.Role,
+		joinScopes(account.Scopes),
+		nullTenant(account.TenantID),
 		account.Balance,
 		account.CreatedAt,
 		account.UpdatedAt,
@@ -41,28 +137,13 @@ func (r *AccountRepository) Save(account *domain.Account) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return tx.Commit()
 }
 
-// FindByAPIKey busca uma conta pelo API Key
-// Retorna ErrAccountNotFound se não encontrada
-func (r *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error) {
-	var account domain.Account
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at
-		FROM accounts
-		WHERE api_key = $1
-	`, apiKey).Scan(
-		&account.ID,
-		&account.Name,
-		&account.Email,
-		&account.APIKey,
-		&account.Balance,
-		&createdAt,
-		&updatedAt,
-	)
+// FindByAPIKey busca uma conta ativa pelo API Key
+// Retorna ErrAccountNotFound se não encontrada ou excluída
+func (r *AccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	account, err := r.scanAccount(ctx, r.findByAPIKeyStmt.QueryRowContext(ctx, apiKey))
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrAccountNotFound
 	}
@@ -70,30 +151,19 @@ func (r *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error)
 		return nil, err
 	}
 
-	account.CreatedAt = createdAt
-	account.UpdatedAt = updatedAt
-	return &account, nil
+	return account, nil
 }
 
 // FindByID busca uma conta pelo ID
+// Contas excluídas só são retornadas com domain.IncludeDeleted()
 // Retorna ErrAccountNotFound se não encontrada
-func (r *AccountRepository) FindByID(id string) (*domain.Account, error) {
-	var account domain.Account
-	var createdAt, updatedAt time.Time
+func (r *AccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Account, error) {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = ?"
+	if !domain.NewFindOptions(opts...).IncludeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
-	err := r.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at
-		FROM accounts
-		WHERE id = $1
-	`, id).Scan(
-		&account.ID,
-		&account.Name,
-		&account.Email,
-		&account.APIKey,
-		&account.Balance,
-		&createdAt,
-		&updatedAt,
-	)
+	account, err := r.scanAccount(ctx, r.db.QueryRowContext(ctx, r.dialect.rebind(query), id))
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrAccountNotFound
 	}
@@ -101,39 +171,206 @@ func (r *AccountRepository) FindByID(id string) (*domain.Account, error) {
 		return nil, err
 	}
 
-	account.CreatedAt = createdAt
-	account.UpdatedAt = updatedAt
-	return &account, nil
+	return account, nil
+}
+
+// lockAccount lê a conta ativa com SELECT FOR UPDATE dentro da transação
+func (r *AccountRepository) lockAccount(ctx context.Context, tx *sql.Tx, id string) (*domain.Account, error) {
+	account, err := r.scanAccount(ctx, tx.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+accountColumns+" FROM accounts WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, err
 }
 
 // UpdateBalance atualiza o saldo da conta usando SELECT FOR UPDATE para consistência em acessos concorrentes
+// O estado anterior lido sob lock é gravado na auditoria junto com o novo
 // Retorna ErrAccountNotFound se a conta não existir
-func (r *AccountRepository) UpdateBalance(account *domain.Account) error {
-	tx, err := r.db.Begin()
+func (r *AccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
 	// SELECT FOR UPDATE previne race conditions no saldo
-	var currentBalance float64
-	err = tx.QueryRow(`SELECT balance FROM accounts WHERE id = $1 FOR UPDATE`,
-		account.ID).Scan(&currentBalance)
+	current, err := r.lockAccount(ctx, tx, account.ID)
+	if err != nil {
+		return err
+	}
 
-	if err == sql.ErrNoRows {
-		return domain.ErrAccountNotFound
+	updatedAt := time.Now()
+	updated := NewAccountSnapshot(account)
+	updated.UpdatedAt = updatedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, accountEntity, account.ID, domain.AuditActionUpdate, NewAccountSnapshot(current), updated); err != nil {
+		return err
 	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.rebind(`
+        UPDATE accounts
+        SET balance = ?, updated_at = ?
+        WHERE id = ?
+    `), account.Balance, updatedAt, account.ID)
 	if err != nil {
 		return err
 	}
+	return tx.Commit()
+}
 
-	_, err = tx.Exec(`
-        UPDATE accounts
-        SET balance = $1, updated_at = $2
-        WHERE id = $3
-    `, account.Balance, time.Now(), account.ID)
+// UpdateRole altera o papel do API Key da conta registrando o estado anterior na auditoria
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *AccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockAccount(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	updated := NewAccountSnapshot(current)
+	updated.Role = role
+	updated.UpdatedAt = updatedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, accountEntity, id, domain.AuditActionUpdate, NewAccountSnapshot(current), updated); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE accounts SET role = ?, updated_at = ? WHERE id = ?"),
+		role, updatedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateTenant move a conta para a organização informada, ou a tira de qualquer organização com tenantID vazio,
+// registrando o estado anterior na auditoria
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *AccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockAccount(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	updated := NewAccountSnapshot(current)
+	updated.TenantID = tenantID
+	updated.UpdatedAt = updatedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, accountEntity, id, domain.AuditActionUpdate, NewAccountSnapshot(current), updated); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE accounts SET tenant_id = ?, updated_at = ? WHERE id = ?"),
+		nullTenant(tenantID), updatedAt, id,
+	)
 	if err != nil {
 		return err
 	}
 	return tx.Commit()
 }
+
+// nullTenant grava NULL na organização das contas sem organização, que não têm linha em tenants
+func nullTenant(tenantID string) sql.NullString {
+	return sql.NullString{String: tenantID, Valid: tenantID != ""}
+}
+
+// Delete exclui logicamente a conta preenchendo deleted_at
+// Retorna ErrAccountNotFound se a conta não existir ou já estiver excluída
+func (r *AccountRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockAccount(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	deleted := NewAccountSnapshot(current)
+	deleted.UpdatedAt = deletedAt
+	deleted.DeletedAt = &deletedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, accountEntity, id, domain.AuditActionDelete, NewAccountSnapshot(current), deleted); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE accounts SET deleted_at = ?, updated_at = ? WHERE id = ?"),
+		deletedAt, deletedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgeableAccountsCondition seleciona contas excluídas antes do parâmetro que não têm mais faturas associadas
+const purgeableAccountsCondition = `deleted_at IS NOT NULL AND deleted_at < ?
+	AND NOT EXISTS (SELECT 1 FROM invoices WHERE invoices.account_id = accounts.id)`
+
+// ListAfter retorna até limit contas não excluídas com ID maior que afterID, ordenadas pelo ID
+func (r *AccountRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.Account, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+accountColumns+" FROM accounts WHERE id > ? AND deleted_at IS NULL ORDER BY id LIMIT "+strconv.Itoa(limit)),
+		afterID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domain.Account
+	for rows.Next() {
+		account, err := r.scanAccount(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// CountDeleted conta as contas que seriam removidas por PurgeDeleted
+func (r *AccountRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(*) FROM accounts WHERE "+purgeableAccountsCondition),
+		before,
+	).Scan(&count)
+	return count, err
+}
+
+// PurgeDeleted remove definitivamente as contas excluídas antes do instante informado
+// Contas que ainda possuem faturas são mantidas para preservar a integridade referencial
+func (r *AccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("DELETE FROM accounts WHERE "+purgeableAccountsCondition),
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
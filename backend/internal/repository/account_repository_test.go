@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// BenchmarkFindByAPIKey compara a consulta preparada de FindByAPIKey com a mesma consulta montada a cada chamada
+func BenchmarkFindByAPIKey(b *testing.B) {
+	db, dialect := openTestDB(b)
+	ctx := context.Background()
+
+	r, err := NewAccountRepository(ctx, db, dialect, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer r.Close()
+
+	account := domain.NewAccount("Benchmark", domain.NewID()+"@example.com")
+	if err := r.Save(ctx, account); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("prepared", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := r.FindByAPIKey(ctx, account.APIKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	query := dialect.rebind("SELECT " + accountColumns + " FROM accounts WHERE api_key = ? AND deleted_at IS NULL")
+	b.Run("adhoc", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := r.scanAccount(ctx, db.QueryRowContext(ctx, query, account.APIKey)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
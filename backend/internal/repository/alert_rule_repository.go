@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// alertRuleColumns são as colunas lidas por scanAlertRule, na mesma ordem
+const alertRuleColumns = "id, name, metric, threshold, window_seconds, enabled, created_at, updated_at"
+
+// AlertRuleRepository implementa a persistência das regras de alerta
+// A janela é gravada em segundos
+type AlertRuleRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAlertRuleRepository cria um novo repositório de regras de alerta para o banco do dialeto informado
+func NewAlertRuleRepository(db *sql.DB, dialect Dialect) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db, dialect: dialect}
+}
+
+// Create grava uma nova regra
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO alert_rules ("+alertRuleColumns+") VALUES "+valuesPlaceholders(1, 8)),
+		rule.ID, rule.Name, rule.Metric, rule.Threshold, int64(rule.Window.Seconds()), rule.Enabled, rule.CreatedAt, rule.UpdatedAt,
+	)
+	return err
+}
+
+// Update substitui a definição da regra
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE alert_rules SET name = ?, metric = ?, threshold = ?, window_seconds = ?, enabled = ?, updated_at = ? WHERE id = ?"),
+		rule.Name, rule.Metric, rule.Threshold, int64(rule.Window.Seconds()), rule.Enabled, rule.UpdatedAt, rule.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// FindByID busca a regra pelo ID
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) FindByID(ctx context.Context, id string) (*domain.AlertRule, error) {
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+alertRuleColumns+" FROM alert_rules WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+	return rule, err
+}
+
+// List retorna todas as regras, das mais antigas para as mais novas
+func (r *AlertRuleRepository) List(ctx context.Context) ([]*domain.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+alertRuleColumns+" FROM alert_rules ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Delete remove a regra
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM alert_rules WHERE id = ?"), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// scanAlertRule lê uma linha com as colunas de alertRuleColumns
+func scanAlertRule(row rowScanner) (*domain.AlertRule, error) {
+	var rule domain.AlertRule
+	var windowSeconds int64
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Metric, &rule.Threshold, &windowSeconds, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	rule.Window = time.Duration(windowSeconds) * time.Second
+	return &rule, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AmountLimitRepository implementa a persistência das faixas de valor próprias das contas
+type AmountLimitRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAmountLimitRepository cria um novo repositório de faixas de valor para o banco do dialeto informado
+func NewAmountLimitRepository(db *sql.DB, dialect Dialect) *AmountLimitRepository {
+	return &AmountLimitRepository{db: db, dialect: dialect}
+}
+
+// Save substitui a faixa da conta em uma transação
+func (r *AmountLimitRepository) Save(ctx context.Context, limits *domain.AccountAmountLimits) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM account_amount_limits WHERE account_id = ?"), limits.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO account_amount_limits (account_id, min_amount, max_amount, updated_at) VALUES "+valuesPlaceholders(1, 4)),
+		limits.AccountID, limits.Range.Min, limits.Range.Max, limits.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca a faixa da conta
+// Retorna ErrAmountLimitsNotFound se a conta usa a faixa padrão
+func (r *AmountLimitRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountAmountLimits, error) {
+	var limits domain.AccountAmountLimits
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, min_amount, max_amount, updated_at FROM account_amount_limits WHERE account_id = ?"),
+		accountID,
+	).Scan(&limits.AccountID, &limits.Range.Min, &limits.Range.Max, &limits.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAmountLimitsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// Delete remove a faixa da conta, que volta à padrão
+func (r *AmountLimitRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM account_amount_limits WHERE account_id = ?"), accountID)
+	return err
+}
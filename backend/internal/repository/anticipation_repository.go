@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// anticipationColumns são as colunas das antecipações, na ordem lida por ListByAccountID
+const anticipationColumns = "id, account_id, amount, fee, holds, requested_by, created_at"
+
+// AnticipationRepository implementa a persistência das antecipações
+type AnticipationRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAnticipationRepository cria um novo repositório de antecipações para o banco do dialeto informado
+func NewAnticipationRepository(db *sql.DB, dialect Dialect) *AnticipationRepository {
+	return &AnticipationRepository{db: db, dialect: dialect}
+}
+
+// Create grava a antecipação e o movimento em uma transação
+// Retorna ErrEscrowAlreadyReleased se uma das retenções já tiver sido liberada
+func (r *AnticipationRepository) Create(ctx context.Context, anticipation *domain.Anticipation, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO anticipations ("+anticipationColumns+") VALUES "+valuesPlaceholders(1, 7)),
+		anticipation.ID, anticipation.AccountID, anticipation.Amount, anticipation.Fee, anticipation.Holds,
+		anticipation.RequestedBy, anticipation.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListByAccountID retorna até limit antecipações da conta, das mais recentes para as mais antigas
+func (r *AnticipationRepository) ListByAccountID(ctx context.Context, accountID string, limit int) ([]*domain.Anticipation, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+anticipationColumns+" FROM anticipations WHERE account_id = ? ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit)),
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anticipations []*domain.Anticipation
+	for rows.Next() {
+		var anticipation domain.Anticipation
+		err := rows.Scan(&anticipation.ID, &anticipation.AccountID, &anticipation.Amount, &anticipation.Fee,
+			&anticipation.Holds, &anticipation.RequestedBy, &anticipation.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		anticipations = append(anticipations, &anticipation)
+	}
+	return anticipations, rows.Err()
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redact"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// execer é satisfeito por *sql.DB e *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// writeAudit grava a entrada de auditoria antes da mutação, na mesma transação
+// O autor e o ID da requisição são lidos do contexto
+func writeAudit(ctx context.Context, tx execer, dialect Dialect, entity, entityID string, action domain.AuditAction, oldValue, newValue any) error {
+	oldJSON, err := marshalAuditValue(oldValue)
+	if err != nil {
+		return err
+	}
+	newJSON, err := marshalAuditValue(newValue)
+	if err != nil {
+		return err
+	}
+
+	var requestID sql.NullString
+	if id := requestctx.RequestID(ctx); id != "" {
+		requestID = sql.NullString{String: id, Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx, dialect.rebind(`
+		INSERT INTO audit_log (entity, entity_id, action, old_value, new_value, actor, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`), entity, entityID, action, oldJSON, newJSON, requestctx.Actor(ctx), requestID, time.Now())
+	return err
+}
+
+// auditColumns é a quantidade de colunas gravadas por entrada de auditoria
+const auditColumns = 8
+
+// writeInsertAudits grava em um único INSERT as entradas de auditoria de várias inserções da mesma entidade
+func writeInsertAudits(ctx context.Context, tx execer, dialect Dialect, entity string, entityIDs []string, newValues []any) error {
+	var requestID sql.NullString
+	if id := requestctx.RequestID(ctx); id != "" {
+		requestID = sql.NullString{String: id, Valid: true}
+	}
+	actor := requestctx.Actor(ctx)
+	now := time.Now()
+
+	args := make([]any, 0, len(entityIDs)*auditColumns)
+	for i, entityID := range entityIDs {
+		newJSON, err := marshalAuditValue(newValues[i])
+		if err != nil {
+			return err
+		}
+		args = append(args, entity, entityID, domain.AuditActionInsert, nil, newJSON, actor, requestID, now)
+	}
+
+	_, err := tx.ExecContext(ctx,
+		dialect.rebind("INSERT INTO audit_log (entity, entity_id, action, old_value, new_value, actor, request_id, created_at) VALUES "+
+			valuesPlaceholders(len(entityIDs), auditColumns)),
+		args...,
+	)
+	return err
+}
+
+// anonymizeInvoiceAudit reescreve os snapshots auditados das faturas informadas trocando o nome do pagador por token
+// Os IDs são consultados em blocos de invoiceBatchSize
+func anonymizeInvoiceAudit(ctx context.Context, tx *sql.Tx, dialect Dialect, invoiceIDs []string, payerName, token string) error {
+	type auditValues struct {
+		id       int64
+		oldValue sql.NullString
+		newValue sql.NullString
+	}
+
+	for start := 0; start < len(invoiceIDs); start += invoiceBatchSize {
+		chunk := invoiceIDs[start:min(start+invoiceBatchSize, len(invoiceIDs))]
+		args := make([]any, 0, len(chunk)+1)
+		args = append(args, invoiceEntity)
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+
+		rows, err := tx.QueryContext(ctx,
+			dialect.rebind("SELECT id, old_value, new_value FROM audit_log WHERE entity = ? AND entity_id IN "+valuesPlaceholders(1, len(chunk))),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+		var entries []auditValues
+		for rows.Next() {
+			var entry auditValues
+			if err := rows.Scan(&entry.id, &entry.oldValue, &entry.newValue); err != nil {
+				rows.Close()
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// As atualizações só começam depois de lidas as linhas, já que a transação usa uma única conexão
+		for _, entry := range entries {
+			oldValue, oldChanged, err := AnonymizeInvoiceSnapshot(nullableJSON(entry.oldValue), payerName, token)
+			if err != nil {
+				return err
+			}
+			newValue, newChanged, err := AnonymizeInvoiceSnapshot(nullableJSON(entry.newValue), payerName, token)
+			if err != nil {
+				return err
+			}
+			if !oldChanged && !newChanged {
+				continue
+			}
+
+			_, err = tx.ExecContext(ctx,
+				dialect.rebind("UPDATE audit_log SET old_value = ?, new_value = ? WHERE id = ?"),
+				jsonArg(oldValue), jsonArg(newValue), entry.id,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nullableJSON converte a coluna JSON lida do banco; NULL vira nil
+func nullableJSON(value sql.NullString) []byte {
+	if !value.Valid {
+		return nil
+	}
+	return []byte(value.String)
+}
+
+// jsonArg converte o snapshot para gravação; nil vira NULL
+func jsonArg(value []byte) any {
+	if value == nil {
+		return nil
+	}
+	return string(value)
+}
+
+// AnonymizeInvoiceSnapshot troca o nome do pagador do snapshot auditado de uma fatura por token quando ele é
+// do pagador informado, sem diferenciar maiúsculas, preservando os demais campos
+// Retorna false, com o snapshot original, quando não há o que trocar
+func AnonymizeInvoiceSnapshot(snapshot []byte, payerName, token string) ([]byte, bool, error) {
+	if len(snapshot) == 0 {
+		return snapshot, false, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(snapshot, &fields); err != nil {
+		return nil, false, err
+	}
+
+	var current string
+	if raw, ok := fields["payer_name"]; !ok || json.Unmarshal(raw, &current) != nil || !strings.EqualFold(current, payerName) {
+		return snapshot, false, nil
+	}
+
+	encoded, err := json.Marshal(token)
+	if err != nil {
+		return nil, false, err
+	}
+	fields["payer_name"] = encoded
+
+	anonymized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+	return anonymized, true, nil
+}
+
+// marshalAuditValue serializa o snapshot com os dados sensíveis mascarados; valores nil viram NULL
+func marshalAuditValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(redact.JSON(data)), nil
+}
+
+// AccountSnapshot é a representação auditada de uma conta, sem a API Key e sem o e-mail
+// Dados pessoais ficam fora da auditoria, pois ela não é cifrada
+type AccountSnapshot struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Role      domain.Role         `json:"role"`
+	Scopes    []domain.Permission `json:"scopes"`
+	TenantID  string              `json:"tenant_id,omitempty"`
+	Balance   float64             `json:"balance"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	DeletedAt *time.Time          `json:"deleted_at,omitempty"`
+}
+
+// NewAccountSnapshot monta o snapshot auditado de uma conta
+func NewAccountSnapshot(account *domain.Account) *AccountSnapshot {
+	return &AccountSnapshot{
+		ID:        account.ID,
+		Name:      account.Name,
+		Role:      account.Role,
+		Scopes:    account.Scopes,
+		TenantID:  account.TenantID,
+		Balance:   account.Balance,
+		CreatedAt: account.CreatedAt,
+		UpdatedAt: account.UpdatedAt,
+		DeletedAt: account.DeletedAt,
+	}
+}
+
+// InvoiceSnapshot é a representação auditada de uma fatura, sem os dados do cartão
+type InvoiceSnapshot struct {
+	ID          string            `json:"id"`
+	AccountID   string            `json:"account_id"`
+	Amount      float64           `json:"amount"`
+	Status      domain.Status     `json:"status"`
+	Description string            `json:"description"`
+	PaymentType string            `json:"payment_type"`
+	PayerName   string            `json:"payer_name"`
+	Metadata    map[string]string `json:"metadata"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// NewInvoiceSnapshot monta o snapshot auditado de uma fatura
+func NewInvoiceSnapshot(invoice *domain.Invoice) *InvoiceSnapshot {
+	return &InvoiceSnapshot{
+		ID:          invoice.ID,
+		AccountID:   invoice.AccountID,
+		Amount:      invoice.Amount,
+		Status:      invoice.Status,
+		Description: invoice.Description,
+		PaymentType: invoice.PaymentType,
+		PayerName:   invoice.PayerName,
+		Metadata:    invoice.Metadata,
+		CreatedAt:   invoice.CreatedAt,
+		UpdatedAt:   invoice.UpdatedAt,
+		DeletedAt:   invoice.DeletedAt,
+	}
+}
+
+// UserSnapshot é a representação auditada de um usuário do dashboard, sem e-mail e sem hash da senha
+type UserSnapshot struct {
+	ID        string      `json:"id"`
+	AccountID string      `json:"account_id"`
+	Role      domain.Role `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// NewUserSnapshot monta o snapshot auditado de um usuário
+func NewUserSnapshot(user *domain.User) *UserSnapshot {
+	return &UserSnapshot{
+		ID:        user.ID,
+		AccountID: user.AccountID,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// ExportSnapshot é a representação auditada de uma exportação, sem o caminho no armazenamento
+type ExportSnapshot struct {
+	ID        string            `json:"id"`
+	AccountID string            `json:"account_id"`
+	Type      domain.ExportType `json:"type"`
+	FileName  string            `json:"file_name"`
+	Size      int64             `json:"size"`
+	Downloads int               `json:"downloads"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// NewExportSnapshot monta o snapshot auditado de uma exportação
+func NewExportSnapshot(export *domain.Export) *ExportSnapshot {
+	return &ExportSnapshot{
+		ID:        export.ID,
+		AccountID: export.AccountID,
+		Type:      export.Type,
+		FileName:  export.FileName,
+		Size:      export.Size,
+		Downloads: export.Downloads,
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+}
+
+// ExportDownloadSnapshot é o registro auditado de um download, com a origem da requisição
+type ExportDownloadSnapshot struct {
+	Downloads int    `json:"downloads"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// NewExportDownloadSnapshot monta o registro do download de número downloads lendo a origem do contexto
+func NewExportDownloadSnapshot(ctx context.Context, downloads int) *ExportDownloadSnapshot {
+	client := requestctx.ClientInfo(ctx)
+	return &ExportDownloadSnapshot{Downloads: downloads, IP: client.IP, UserAgent: client.UserAgent}
+}
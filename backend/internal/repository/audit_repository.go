@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// defaultAuditLimit limita consultas sem limite explícito
+const defaultAuditLimit = 100
+
+// AuditRepository implementa a consulta da trilha de auditoria
+type AuditRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAuditRepository cria um novo repositório de auditoria para o banco do dialeto informado
+func NewAuditRepository(db *sql.DB, dialect Dialect) *AuditRepository {
+	return &AuditRepository{db: db, dialect: dialect}
+}
+
+// FindByFilter busca entradas de auditoria, das mais recentes para as mais antigas
+func (r *AuditRepository) FindByFilter(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	qb := newQueryBuilder(r.dialect)
+	if filter.Entity != "" {
+		qb.where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != "" {
+		qb.where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		qb.where("actor = ?", filter.Actor)
+	}
+	if filter.RequestID != "" {
+		qb.where("request_id = ?", filter.RequestID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		qb.where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		qb.where("created_at <= ?", filter.CreatedTo)
+	}
+
+	if filter.After != nil {
+		afterID, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		qb.where("(created_at, id) < (?, ?)", filter.After.CreatedAt, afterID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	query, args := qb.build(
+		"SELECT id, entity, entity_id, action, old_value, new_value, actor, request_id, created_at FROM audit_log",
+		"ORDER BY created_at DESC, id DESC LIMIT "+strconv.Itoa(limit),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var oldValue, newValue []byte
+		var requestID sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&oldValue,
+			&newValue,
+			&entry.Actor,
+			&requestID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.OldValue = oldValue
+		entry.NewValue = newValue
+		entry.RequestID = requestID.String
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountExpired conta as entradas de auditoria criadas antes do instante informado
+func (r *AuditRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(*) FROM audit_log WHERE created_at < ?"),
+		before,
+	).Scan(&count)
+	return count, err
+}
+
+// RemoveExpired apaga as entradas de auditoria criadas antes do instante informado
+func (r *AuditRepository) RemoveExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("DELETE FROM audit_log WHERE created_at < ?"),
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+const authEventColumns = "id, account_id, key_id, method, success, reason, ip, user_agent, route, created_at"
+
+// AuthEventRepository implementa a trilha de eventos de autenticação
+type AuthEventRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAuthEventRepository cria um novo repositório de eventos de autenticação para o banco do dialeto informado
+func NewAuthEventRepository(db *sql.DB, dialect Dialect) *AuthEventRepository {
+	return &AuthEventRepository{db: db, dialect: dialect}
+}
+
+// Save persiste um evento de autenticação; account_id fica nulo quando a credencial não levou a nenhuma conta
+func (r *AuthEventRepository) Save(ctx context.Context, event *domain.AuthEvent) error {
+	var accountID sql.NullString
+	if event.AccountID != "" {
+		accountID = sql.NullString{String: event.AccountID, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO auth_events ("+authEventColumns+") VALUES "+valuesPlaceholders(1, 10)),
+		event.ID, accountID, event.KeyID, event.Method, event.Success, event.Reason, event.IP, event.UserAgent, event.Route, event.CreatedAt,
+	)
+	return err
+}
+
+// FindByFilter busca os eventos de autenticação de uma conta, dos mais recentes para os mais antigos
+func (r *AuthEventRepository) FindByFilter(ctx context.Context, filter domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	qb := newQueryBuilder(r.dialect)
+	qb.where("account_id = ?", filter.AccountID)
+	if filter.Success != nil {
+		qb.where("success = ?", *filter.Success)
+	}
+	if filter.After != nil {
+		qb.where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	query, args := qb.build(
+		"SELECT "+authEventColumns+" FROM auth_events",
+		"ORDER BY created_at DESC, id DESC LIMIT "+strconv.Itoa(limit),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.AuthEvent
+	for rows.Next() {
+		var event domain.AuthEvent
+		var accountID sql.NullString
+
+		err := rows.Scan(
+			&event.ID,
+			&accountID,
+			&event.KeyID,
+			&event.Method,
+			&event.Success,
+			&event.Reason,
+			&event.IP,
+			&event.UserAgent,
+			&event.Route,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.AccountID = accountID.String
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// CountFailedIPs conta os IPs distintos com falhas de autenticação da credencial desde o instante informado
+func (r *AuthEventRepository) CountFailedIPs(ctx context.Context, keyID string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(DISTINCT ip) FROM auth_events WHERE key_id = ? AND success = ? AND created_at >= ?"),
+		keyID, false, since,
+	).Scan(&count)
+	return count, err
+}
+
+// FindAccountIPs retorna os pares distintos de conta e IP com autenticações bem-sucedidas em [from, to)
+func (r *AuthEventRepository) FindAccountIPs(ctx context.Context, from, to time.Time) ([]domain.AccountIP, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT DISTINCT account_id, ip FROM auth_events WHERE success = ? AND account_id IS NOT NULL AND ip <> '' AND created_at >= ? AND created_at < ?"),
+		true, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ips []domain.AccountIP
+	for rows.Next() {
+		var ip domain.AccountIP
+		if err := rows.Scan(&ip.AccountID, &ip.IP); err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, rows.Err()
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// blocklistColumns são as colunas lidas por scanBlocklistEntry, na mesma ordem
+const blocklistColumns = "id, account_id, type, value_hash, hint, action, reason, expires_at, hits, last_hit_at, created_at"
+
+// BlocklistRepository implementa a persistência das listas de bloqueio
+// As entradas globais têm account_id vazio
+type BlocklistRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewBlocklistRepository cria um novo repositório de listas de bloqueio para o banco do dialeto informado
+func NewBlocklistRepository(db *sql.DB, dialect Dialect) *BlocklistRepository {
+	return &BlocklistRepository{db: db, dialect: dialect}
+}
+
+// Create grava uma nova entrada
+// Retorna ErrBlocklistEntryExists quando o índice único recusa o mesmo valor na mesma lista
+func (r *BlocklistRepository) Create(ctx context.Context, entry *domain.BlocklistEntry) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO blocklist_entries ("+blocklistColumns+") VALUES "+valuesPlaceholders(1, 11)),
+		entry.ID, entry.AccountID, entry.Type, entry.ValueHash, entry.Hint, entry.Action, entry.Reason,
+		entry.ExpiresAt, entry.Hits, entry.LastHitAt, entry.CreatedAt,
+	)
+	if database.IsUniqueViolation(err) {
+		return domain.ErrBlocklistEntryExists
+	}
+	return err
+}
+
+// FindByID busca a entrada pelo ID
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir
+func (r *BlocklistRepository) FindByID(ctx context.Context, id string) (*domain.BlocklistEntry, error) {
+	entry, err := scanBlocklistEntry(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+blocklistColumns+" FROM blocklist_entries WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBlocklistEntryNotFound
+	}
+	return entry, err
+}
+
+// List retorna as entradas da conta, ou as globais com accountID vazio, das mais novas para as mais antigas
+func (r *BlocklistRepository) List(ctx context.Context, accountID string) ([]*domain.BlocklistEntry, error) {
+	return r.query(ctx, r.dialect.rebind("SELECT "+blocklistColumns+" FROM blocklist_entries WHERE account_id = ? ORDER BY created_at DESC, id"), accountID)
+}
+
+// Match busca as entradas globais e da conta com algum dos hashes informados, pelo índice único do hash
+func (r *BlocklistRepository) Match(ctx context.Context, accountID string, valueHashes []string) ([]*domain.BlocklistEntry, error) {
+	if len(valueHashes) == 0 {
+		return nil, nil
+	}
+
+	hashes := make([]any, len(valueHashes))
+	for i, hash := range valueHashes {
+		hashes[i] = hash
+	}
+	query, args := newQueryBuilder(r.dialect).
+		whereIn("value_hash", hashes).
+		whereIn("account_id", []any{"", accountID}).
+		build("SELECT "+blocklistColumns+" FROM blocklist_entries", "")
+	return r.query(ctx, query, args...)
+}
+
+// RecordHits soma um acerto às entradas e marca o instante do último
+func (r *BlocklistRepository) RecordHits(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	query, args := newQueryBuilder(r.dialect).
+		whereIn("id", values).
+		build("UPDATE blocklist_entries SET hits = hits + 1, last_hit_at = ?", "")
+	_, err := r.db.ExecContext(ctx, query, append([]any{at}, args...)...)
+	return err
+}
+
+// Delete remove a entrada
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir
+func (r *BlocklistRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM blocklist_entries WHERE id = ?"), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrBlocklistEntryNotFound
+	}
+	return nil
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna entradas completas
+func (r *BlocklistRepository) query(ctx context.Context, query string, args ...any) ([]*domain.BlocklistEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.BlocklistEntry
+	for rows.Next() {
+		entry, err := scanBlocklistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// scanBlocklistEntry lê uma linha com as colunas de blocklistColumns
+func scanBlocklistEntry(row rowScanner) (*domain.BlocklistEntry, error) {
+	var entry domain.BlocklistEntry
+	var expiresAt, lastHitAt sql.NullTime
+	err := row.Scan(&entry.ID, &entry.AccountID, &entry.Type, &entry.ValueHash, &entry.Hint, &entry.Action, &entry.Reason,
+		&expiresAt, &entry.Hits, &lastHitAt, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		entry.ExpiresAt = &expiresAt.Time
+	}
+	if lastHitAt.Valid {
+		entry.LastHitAt = &lastHitAt.Time
+	}
+	return &entry, nil
+}
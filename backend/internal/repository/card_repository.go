@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+const cardColumns = "token, account_id, brand, last_digits, expiry_month, expiry_year, holder_name, number, created_at"
+
+// cardColumnCount é a quantidade de colunas em cardColumns
+const cardColumnCount = 9
+
+// CardRepository implementa carddata.Repository na tabela card_tokens, separada das faturas
+// Número e nome do portador já chegam cifrados pelo cofre
+type CardRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewCardRepository cria o armazenamento do cofre de cartões para o banco do dialeto informado
+func NewCardRepository(db *sql.DB, dialect Dialect) *CardRepository {
+	return &CardRepository{db: db, dialect: dialect}
+}
+
+// Save persiste um cartão tokenizado
+func (r *CardRepository) Save(ctx context.Context, record *carddata.Record) error {
+	return r.SaveBatch(ctx, []*carddata.Record{record})
+}
+
+// SaveBatch persiste vários cartões tokenizados em INSERTs com várias linhas, em uma única transação
+func (r *CardRepository) SaveBatch(ctx context.Context, records []*carddata.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(records); start += invoiceBatchSize {
+		chunk := records[start:min(start+invoiceBatchSize, len(records))]
+
+		args := make([]any, 0, len(chunk)*cardColumnCount)
+		for _, record := range chunk {
+			args = append(args, record.Token, record.AccountID, record.Brand, record.LastDigits, record.ExpiryMonth, record.ExpiryYear, record.HolderName, record.Number, record.CreatedAt)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			r.dialect.rebind("INSERT INTO card_tokens ("+cardColumns+") VALUES "+valuesPlaceholders(len(chunk), cardColumnCount)),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByToken busca um cartão pelo token
+func (r *CardRepository) FindByToken(ctx context.Context, token string) (*carddata.Record, error) {
+	var record carddata.Record
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+cardColumns+" FROM card_tokens WHERE token = ?"),
+		token,
+	).Scan(
+		&record.Token,
+		&record.AccountID,
+		&record.Brand,
+		&record.LastDigits,
+		&record.ExpiryMonth,
+		&record.ExpiryYear,
+		&record.HolderName,
+		&record.Number,
+		&record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Anonymize apaga número e final dos cartões da conta e troca o nome do portador por holderName
+// Os tokens são atualizados em blocos de invoiceBatchSize
+func (r *CardRepository) Anonymize(ctx context.Context, accountID string, tokens []string, holderName string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(tokens); start += invoiceBatchSize {
+		chunk := tokens[start:min(start+invoiceBatchSize, len(tokens))]
+		args := make([]any, 0, len(chunk)+2)
+		args = append(args, holderName, accountID)
+		for _, token := range chunk {
+			args = append(args, token)
+		}
+
+		_, err := tx.ExecContext(ctx,
+			r.dialect.rebind("UPDATE card_tokens SET number = '', last_digits = '', holder_name = ? WHERE account_id = ? AND token IN "+valuesPlaceholders(1, len(chunk))),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
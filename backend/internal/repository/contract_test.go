@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/repositorytest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	db, dialect := openTestDB(t)
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		accounts, err := NewAccountRepository(t.Context(), db, dialect, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { accounts.Close() })
+		return repositorytest.Repositories{
+			Accounts: accounts,
+			Invoices: NewInvoiceRepository(db, database.NewReadRouter(db, nil), dialect, nil),
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// couponColumns são as colunas lidas por scanCoupon, na mesma ordem
+const couponColumns = "id, account_id, code, type, value, max_redemptions, redemptions, valid_from, valid_until, active, created_at, updated_at"
+
+// CouponRepository implementa a persistência dos cupons e dos seus usos
+type CouponRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewCouponRepository cria um novo repositório de cupons para o banco do dialeto informado
+func NewCouponRepository(db *sql.DB, dialect Dialect) *CouponRepository {
+	return &CouponRepository{db: db, dialect: dialect}
+}
+
+// Create grava o cupom
+// Retorna ErrCouponExists quando a conta já tem um cupom com o mesmo código
+func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO coupons ("+couponColumns+") VALUES "+valuesPlaceholders(1, 12)),
+		coupon.ID, coupon.AccountID, coupon.Code, coupon.Type, coupon.Value, coupon.MaxRedemptions, coupon.Redemptions,
+		coupon.ValidFrom, coupon.ValidUntil, coupon.Active, coupon.CreatedAt, coupon.UpdatedAt,
+	)
+	if database.IsUniqueViolation(err) {
+		return domain.ErrCouponExists
+	}
+	return err
+}
+
+// FindByID busca o cupom pelo ID
+// Retorna ErrCouponNotFound se o cupom não existir
+func (r *CouponRepository) FindByID(ctx context.Context, id string) (*domain.Coupon, error) {
+	coupon, err := scanCoupon(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+couponColumns+" FROM coupons WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCouponNotFound
+	}
+	return coupon, err
+}
+
+// FindByCode busca o cupom da conta pelo código normalizado
+// Retorna ErrCouponNotFound se a conta não tiver o cupom
+func (r *CouponRepository) FindByCode(ctx context.Context, accountID, code string) (*domain.Coupon, error) {
+	coupon, err := scanCoupon(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+couponColumns+" FROM coupons WHERE account_id = ? AND code = ?"),
+		accountID, code,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCouponNotFound
+	}
+	return coupon, err
+}
+
+// List retorna até limit cupons da conta, dos mais novos para os mais antigos
+func (r *CouponRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Coupon, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+couponColumns+" FROM coupons WHERE account_id = ? ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit)),
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []*domain.Coupon
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+	return coupons, rows.Err()
+}
+
+// Deactivate desativa o cupom
+// Retorna ErrCouponNotFound se o cupom não existir
+func (r *CouponRepository) Deactivate(ctx context.Context, id string, updatedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE coupons SET active = ?, updated_at = ? WHERE id = ?"),
+		false, updatedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrCouponNotFound
+	}
+	return nil
+}
+
+// Redeem soma um aos usos do cupom e grava o uso na mesma transação
+// O incremento só acontece com o cupom ativo e com usos restantes; do contrário retorna ErrCouponUnavailable
+func (r *CouponRepository) Redeem(ctx context.Context, redemption *domain.CouponRedemption) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE coupons SET redemptions = redemptions + 1, updated_at = ? "+
+			"WHERE id = ? AND active = ? AND (max_redemptions = 0 OR redemptions < max_redemptions)"),
+		redemption.CreatedAt, redemption.CouponID, true,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrCouponUnavailable
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO coupon_redemptions (id, coupon_id, account_id, invoice_id, discount, created_at) VALUES "+valuesPlaceholders(1, 6)),
+		redemption.ID, redemption.CouponID, redemption.AccountID, redemption.InvoiceID, redemption.Discount, redemption.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SumDiscounts soma os descontos dos usos de cupons da conta de from até to
+func (r *CouponRepository) SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (float64, error) {
+	var total float64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COALESCE(SUM(discount), 0) FROM coupon_redemptions WHERE account_id = ? AND created_at >= ? AND created_at <= ?"),
+		accountID, from, to,
+	).Scan(&total)
+	return total, err
+}
+
+// scanCoupon lê uma linha com as colunas de couponColumns
+func scanCoupon(row rowScanner) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+	var validFrom, validUntil sql.NullTime
+	err := row.Scan(&coupon.ID, &coupon.AccountID, &coupon.Code, &coupon.Type, &coupon.Value, &coupon.MaxRedemptions,
+		&coupon.Redemptions, &validFrom, &validUntil, &coupon.Active, &coupon.CreatedAt, &coupon.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if validFrom.Valid {
+		coupon.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		coupon.ValidUntil = &validUntil.Time
+	}
+	return &coupon, nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+// customerColumns são as colunas lidas por scanCustomer, na mesma ordem
+const customerColumns = "id, account_id, name, email, document, created_at, updated_at"
+
+// customerPaymentMethodColumns são as colunas dos cartões dos clientes, na ordem lida por ListPaymentMethods
+const customerPaymentMethodColumns = "customer_id, card_token, card_brand, card_last_digits, created_at"
+
+// CustomerRepository implementa a persistência dos clientes e dos cartões guardados deles
+// Nome, e-mail e documento são gravados cifrados
+type CustomerRepository struct {
+	db        *sql.DB
+	dialect   Dialect
+	encryptor *pii.Encryptor
+}
+
+// NewCustomerRepository cria um novo repositório de clientes para o banco do dialeto informado
+// Com encryptor nil os dados pessoais são gravados em texto puro
+func NewCustomerRepository(db *sql.DB, dialect Dialect, encryptor *pii.Encryptor) *CustomerRepository {
+	return &CustomerRepository{db: db, dialect: dialect, encryptor: encryptor}
+}
+
+// scanCustomer lê um cliente na ordem de customerColumns decifrando os dados pessoais
+func (r *CustomerRepository) scanCustomer(ctx context.Context, row rowScanner) (*domain.Customer, error) {
+	var customer domain.Customer
+	err := row.Scan(&customer.ID, &customer.AccountID, &customer.Name, &customer.Email, &customer.Document, &customer.CreatedAt, &customer.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []*string{&customer.Name, &customer.Email, &customer.Document} {
+		if *field, err = r.encryptor.Decrypt(ctx, *field); err != nil {
+			return nil, err
+		}
+	}
+	return &customer, nil
+}
+
+// Create grava o cliente com os dados pessoais cifrados
+func (r *CustomerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	fields := []string{customer.Name, customer.Email, customer.Document}
+	for i, field := range fields {
+		encrypted, err := r.encryptor.Encrypt(ctx, field)
+		if err != nil {
+			return err
+		}
+		fields[i] = encrypted
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO customers ("+customerColumns+") VALUES "+valuesPlaceholders(1, 7)),
+		customer.ID, customer.AccountID, fields[0], fields[1], fields[2], customer.CreatedAt, customer.UpdatedAt,
+	)
+	return err
+}
+
+// FindByID busca o cliente pelo ID
+// Retorna ErrCustomerNotFound se o cliente não existir
+func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*domain.Customer, error) {
+	return r.scanCustomer(ctx, r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+customerColumns+" FROM customers WHERE id = ?"),
+		id,
+	))
+}
+
+// List retorna até limit clientes da conta, dos mais novos para os mais antigos
+func (r *CustomerRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Customer, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+customerColumns+" FROM customers WHERE account_id = ? ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit)),
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []*domain.Customer
+	for rows.Next() {
+		customer, err := r.scanCustomer(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+// Delete apaga o cliente; os cartões ligados a ele são apagados em cascata
+// Retorna ErrCustomerNotFound se o cliente não existir
+func (r *CustomerRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM customers WHERE id = ?"), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrCustomerNotFound
+	}
+	return nil
+}
+
+// AddPaymentMethod liga o cartão ao cliente
+func (r *CustomerRepository) AddPaymentMethod(ctx context.Context, method *domain.CustomerPaymentMethod) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO customer_payment_methods ("+customerPaymentMethodColumns+") VALUES "+valuesPlaceholders(1, 5)),
+		method.CustomerID, method.CardToken, method.CardBrand, method.CardLastDigits, method.CreatedAt,
+	)
+	return err
+}
+
+// ListPaymentMethods retorna os cartões do cliente, dos mais antigos para os mais novos
+func (r *CustomerRepository) ListPaymentMethods(ctx context.Context, customerID string) ([]*domain.CustomerPaymentMethod, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+customerPaymentMethodColumns+" FROM customer_payment_methods WHERE customer_id = ? ORDER BY created_at, card_token"),
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var methods []*domain.CustomerPaymentMethod
+	for rows.Next() {
+		var method domain.CustomerPaymentMethod
+		if err := rows.Scan(&method.CustomerID, &method.CardToken, &method.CardBrand, &method.CardLastDigits, &method.CreatedAt); err != nil {
+			return nil, err
+		}
+		methods = append(methods, &method)
+	}
+	return methods, rows.Err()
+}
+
+// RemovePaymentMethod desliga o cartão do cliente
+// Retorna ErrCardNotFound se o cartão não estiver ligado ao cliente
+func (r *CustomerRepository) RemovePaymentMethod(ctx context.Context, customerID, cardToken string) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("DELETE FROM customer_payment_methods WHERE customer_id = ? AND card_token = ?"),
+		customerID, cardToken,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrCardNotFound
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+const dataSubjectRequestColumns = "id, account_id, type, invoices, token, actor, request_id, created_at"
+
+// DataSubjectRequestRepository implementa o registro dos pedidos de titulares de dados
+type DataSubjectRequestRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewDataSubjectRequestRepository cria um novo repositório de pedidos de titulares para o banco do dialeto informado
+func NewDataSubjectRequestRepository(db *sql.DB, dialect Dialect) *DataSubjectRequestRepository {
+	return &DataSubjectRequestRepository{db: db, dialect: dialect}
+}
+
+// Save registra o pedido atendido
+func (r *DataSubjectRequestRepository) Save(ctx context.Context, request *domain.DataSubjectRequest) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO data_subject_requests ("+dataSubjectRequestColumns+") VALUES "+valuesPlaceholders(1, 8)),
+		request.ID, request.AccountID, request.Type, request.Invoices, request.Token, request.Actor, request.RequestID, request.CreatedAt,
+	)
+	return err
+}
+
+// FindByAccountID retorna os pedidos da conta, dos mais recentes para os mais antigos
+func (r *DataSubjectRequestRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.DataSubjectRequest, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+dataSubjectRequestColumns+" FROM data_subject_requests WHERE account_id = ? ORDER BY created_at DESC, id DESC"),
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.DataSubjectRequest
+	for rows.Next() {
+		var request domain.DataSubjectRequest
+		err := rows.Scan(
+			&request.ID,
+			&request.AccountID,
+			&request.Type,
+			&request.Invoices,
+			&request.Token,
+			&request.Actor,
+			&request.RequestID,
+			&request.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, &request)
+	}
+	return requests, rows.Err()
+}
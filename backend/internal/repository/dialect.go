@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownDialect é retornado quando DB_DRIVER não corresponde a um banco suportado
+var ErrUnknownDialect = errors.New("unknown database driver")
+
+// Dialect isola as diferenças de SQL entre os bancos suportados pelos repositórios
+// As consultas são escritas com "?" como marcador e traduzidas pelo dialeto na execução
+type Dialect interface {
+	// Name identifica o banco com o mesmo valor aceito em DB_DRIVER
+	Name() string
+
+	// rebind troca cada "?" pelo marcador de parâmetro do banco
+	rebind(query string) string
+	// jsonContains monta a condição "coluna JSON contém o documento do parâmetro"
+	jsonContains(column string) string
+	// textSearch retorna a condição da busca textual e a expressão de relevância, ambas com um parâmetro
+	textSearch() (condition, rank string)
+	// deleteReturning indica se DELETE ... RETURNING pode ser usado em uma CTE
+	deleteReturning() bool
+}
+
+var (
+	// Postgres é o dialeto do PostgreSQL, o banco padrão
+	Postgres Dialect = postgresDialect{}
+	// MySQL é o dialeto do MySQL 8 e do MariaDB
+	MySQL Dialect = mysqlDialect{}
+)
+
+// DialectFor retorna o dialeto correspondente ao valor de DB_DRIVER
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case Postgres.Name():
+		return Postgres, nil
+	case MySQL.Name():
+		return MySQL, nil
+	}
+	return nil, ErrUnknownDialect
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+// rebind numera os marcadores como $1, $2...
+func (postgresDialect) rebind(query string) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// jsonContains usa o operador @>, atendido pelo índice GIN da coluna
+func (postgresDialect) jsonContains(column string) string {
+	return column + " @> ?::jsonb"
+}
+
+// textSearch usa o índice GIN de search_vector
+func (postgresDialect) textSearch() (string, string) {
+	return "search_vector @@ websearch_to_tsquery('simple', ?)",
+		"ts_rank(search_vector, websearch_to_tsquery('simple', ?))"
+}
+
+func (postgresDialect) deleteReturning() bool { return true }
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+// rebind mantém os marcadores "?", que já são os do MySQL
+func (mysqlDialect) rebind(query string) string { return query }
+
+func (mysqlDialect) jsonContains(column string) string {
+	return "JSON_CONTAINS(" + column + ", ?)"
+}
+
+// textSearch usa o índice FULLTEXT de payer_name e description
+// Diferente do PostgreSQL, os valores de metadata ficam fora da busca
+func (mysqlDialect) textSearch() (string, string) {
+	match := "MATCH(payer_name, description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	return match, match
+}
+
+func (mysqlDialect) deleteReturning() bool { return false }
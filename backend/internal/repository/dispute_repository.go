@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// disputeColumns são as colunas lidas por scanDispute, na mesma ordem
+const disputeColumns = "id, invoice_id, account_id, amount, fee, reason, status, resolved_at, created_at"
+
+// disputeEvidenceColumns são as colunas das evidências, na ordem lida por ListEvidence
+const disputeEvidenceColumns = "id, dispute_id, account_id, object_key, file_name, content_type, size, description, uploaded_by, created_at"
+
+// DisputeRepository implementa a persistência das disputas e das suas evidências
+type DisputeRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewDisputeRepository cria um novo repositório de disputas para o banco do dialeto informado
+func NewDisputeRepository(db *sql.DB, dialect Dialect) *DisputeRepository {
+	return &DisputeRepository{db: db, dialect: dialect}
+}
+
+// Create grava a disputa e, se posting não for nil, o movimento da tarifa em uma transação
+// Retorna ErrTransactionAlreadyDisputed quando a fatura já tem disputa e ErrAccountNotFound se a conta do movimento
+// não existir
+func (r *DisputeRepository) Create(ctx context.Context, dispute *domain.Dispute, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO disputes ("+disputeColumns+") VALUES "+valuesPlaceholders(1, 9)),
+		dispute.ID, dispute.InvoiceID, dispute.AccountID, dispute.Amount, dispute.Fee, dispute.Reason, dispute.Status,
+		dispute.ResolvedAt, dispute.CreatedAt,
+	)
+	if database.IsUniqueViolation(err) {
+		return domain.ErrTransactionAlreadyDisputed
+	}
+	if err != nil {
+		return err
+	}
+	if posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FindByID busca a disputa pelo ID
+// Retorna ErrDisputeNotFound se a disputa não existir
+func (r *DisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	dispute, err := scanDispute(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+disputeColumns+" FROM disputes WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrDisputeNotFound
+	}
+	return dispute, err
+}
+
+// List retorna até limit disputas da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais novas para as mais antigas
+func (r *DisputeRepository) List(ctx context.Context, accountID string, status domain.DisputeStatus, limit int) ([]*domain.Dispute, error) {
+	builder := newQueryBuilder(r.dialect)
+	if accountID != "" {
+		builder.where("account_id = ?", accountID)
+	}
+	if status != "" {
+		builder.where("status = ?", status)
+	}
+	query, args := builder.build("SELECT "+disputeColumns+" FROM disputes", "ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, dispute)
+	}
+	return disputes, rows.Err()
+}
+
+// Resolve grava o resultado se a disputa ainda estiver aberta e, na disputa perdida, o chargeback em uma transação
+// A fatura e as retenções dela são travadas com SELECT FOR UPDATE, para que uma liberação ou um reembolso simultâneo
+// não credite nem debite o mesmo valor de novo
+// Retorna ErrDisputeClosed quando outro resultado chegou antes
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var invoice *domain.Invoice
+	if dispute.Status == domain.DisputeLost {
+		if invoice, err = lockInvoiceSnapshot(ctx, tx, r.dialect, dispute.InvoiceID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE disputes SET status = ?, resolved_at = ? WHERE id = ? AND status = ?"),
+		dispute.Status, dispute.ResolvedAt, dispute.ID, domain.DisputeOpen,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrDisputeClosed
+	}
+	if invoice != nil {
+		if err := r.chargeBack(ctx, tx, dispute, invoice); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// chargeBack grava o ChargebackPosting das retenções ainda retidas da fatura travada e a passa para
+// StatusChargedBack se ela ainda estiver aprovada
+func (r *DisputeRepository) chargeBack(ctx context.Context, tx *sql.Tx, dispute *domain.Dispute, invoice *domain.Invoice) error {
+	rows, err := tx.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+escrowColumns+" FROM escrow_holds WHERE invoice_id = ? AND status = ? ORDER BY id FOR UPDATE"),
+		invoice.ID, domain.EscrowHeld,
+	)
+	if err != nil {
+		return err
+	}
+	var held []*domain.EscrowHold
+	for rows.Next() {
+		hold, err := scanEscrowHold(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		held = append(held, hold)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	posting, err := dispute.ChargebackPosting(held)
+	if err != nil {
+		return err
+	}
+	if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+		return err
+	}
+	if !invoice.Status.CanTransitionTo(domain.StatusChargedBack) {
+		return nil
+	}
+	return setInvoiceStatus(ctx, tx, r.dialect, invoice, domain.StatusChargedBack)
+}
+
+// AddEvidence grava a evidência da disputa
+func (r *DisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO dispute_evidence ("+disputeEvidenceColumns+") VALUES "+valuesPlaceholders(1, 10)),
+		evidence.ID, evidence.DisputeID, evidence.AccountID, evidence.ObjectKey, evidence.FileName,
+		evidence.ContentType, evidence.Size, evidence.Description, evidence.UploadedBy, evidence.CreatedAt,
+	)
+	return err
+}
+
+// ListEvidence retorna as evidências da disputa na ordem do envio
+func (r *DisputeRepository) ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+disputeEvidenceColumns+" FROM dispute_evidence WHERE dispute_id = ? ORDER BY created_at, id"),
+		disputeID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var evidence []*domain.DisputeEvidence
+	for rows.Next() {
+		var item domain.DisputeEvidence
+		err := rows.Scan(&item.ID, &item.DisputeID, &item.AccountID, &item.ObjectKey, &item.FileName,
+			&item.ContentType, &item.Size, &item.Description, &item.UploadedBy, &item.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, &item)
+	}
+	return evidence, rows.Err()
+}
+
+// scanDispute lê uma linha com as colunas de disputeColumns
+func scanDispute(row rowScanner) (*domain.Dispute, error) {
+	var dispute domain.Dispute
+	var resolvedAt sql.NullTime
+	err := row.Scan(&dispute.ID, &dispute.InvoiceID, &dispute.AccountID, &dispute.Amount, &dispute.Fee,
+		&dispute.Reason, &dispute.Status, &resolvedAt, &dispute.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedAt.Valid {
+		dispute.ResolvedAt = &resolvedAt.Time
+	}
+	return &dispute, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// escrowColumns são as colunas lidas por scanEscrowHold, na mesma ordem
+const escrowColumns = "id, account_id, invoice_id, kind, amount, status, release_at, released_by, released_at, created_at"
+
+// EscrowRepository implementa a persistência das retenções em custódia
+type EscrowRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewEscrowRepository cria um novo repositório de retenções em custódia para o banco do dialeto informado
+func NewEscrowRepository(db *sql.DB, dialect Dialect) *EscrowRepository {
+	return &EscrowRepository{db: db, dialect: dialect}
+}
+
+// CreateBatch grava as retenções em um único INSERT
+func (r *EscrowRepository) CreateBatch(ctx context.Context, holds []*domain.EscrowHold) error {
+	return insertEscrowHolds(ctx, r.db, r.dialect, holds)
+}
+
+// insertEscrowHolds grava as retenções em um único INSERT, no banco ou na transação informada
+func insertEscrowHolds(ctx context.Context, tx execer, dialect Dialect, holds []*domain.EscrowHold) error {
+	if len(holds) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(holds)*10)
+	for _, hold := range holds {
+		args = append(args, hold.ID, hold.AccountID, hold.InvoiceID, hold.Kind, hold.Amount, hold.Status, hold.ReleaseAt,
+			hold.ReleasedBy, hold.ReleasedAt, hold.CreatedAt)
+	}
+	_, err := tx.ExecContext(ctx,
+		dialect.rebind("INSERT INTO escrow_holds ("+escrowColumns+") VALUES "+valuesPlaceholders(len(holds), 10)),
+		args...,
+	)
+	return err
+}
+
+// ListByInvoiceID retorna as retenções da fatura, liberadas ou não, ordenadas pela conta
+func (r *EscrowRepository) ListByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.EscrowHold, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+escrowColumns+" FROM escrow_holds WHERE invoice_id = ? ORDER BY account_id, id"),
+		invoiceID,
+	)
+}
+
+// ListHeld retorna as retenções ainda retidas da conta pelo motivo kind, das que vencem primeiro
+func (r *EscrowRepository) ListHeld(ctx context.Context, accountID string, kind domain.EscrowKind) ([]*domain.EscrowHold, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+escrowColumns+" FROM escrow_holds WHERE account_id = ? AND status = ? AND kind = ? ORDER BY release_at, id"),
+		accountID, domain.EscrowHeld, kind,
+	)
+}
+
+// ListDue retorna até limit retenções ainda retidas com ReleaseAt até before, das que venceram primeiro
+func (r *EscrowRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.EscrowHold, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+escrowColumns+" FROM escrow_holds WHERE status = ? AND release_at <= ? ORDER BY release_at, id LIMIT "+strconv.Itoa(limit)),
+		domain.EscrowHeld, before,
+	)
+}
+
+// Release grava a liberação se a retenção ainda estiver retida
+// Retorna ErrEscrowAlreadyReleased quando outra liberação chegou antes
+func (r *EscrowRepository) Release(ctx context.Context, hold *domain.EscrowHold) error {
+	return releaseEscrowHold(ctx, r.db, r.dialect, hold)
+}
+
+// releaseEscrowHold grava a liberação da retenção, no banco ou na transação informada, se ela ainda estiver retida
+// Retorna ErrEscrowAlreadyReleased se ela já tiver sido liberada
+func releaseEscrowHold(ctx context.Context, tx execer, dialect Dialect, hold *domain.EscrowHold) error {
+	result, err := tx.ExecContext(ctx,
+		dialect.rebind("UPDATE escrow_holds SET status = ?, released_by = ?, released_at = ? WHERE id = ? AND status = ?"),
+		hold.Status, hold.ReleasedBy, hold.ReleasedAt, hold.ID, domain.EscrowHeld,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrEscrowAlreadyReleased
+	}
+	return nil
+}
+
+// SumHeld soma o valor ainda retido da conta pelo motivo kind
+func (r *EscrowRepository) SumHeld(ctx context.Context, accountID string, kind domain.EscrowKind) (float64, error) {
+	var total float64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COALESCE(SUM(amount), 0) FROM escrow_holds WHERE account_id = ? AND status = ? AND kind = ?"),
+		accountID, domain.EscrowHeld, kind,
+	).Scan(&total)
+	return total, err
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna retenções completas
+func (r *EscrowRepository) query(ctx context.Context, query string, args ...any) ([]*domain.EscrowHold, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []*domain.EscrowHold
+	for rows.Next() {
+		hold, err := scanEscrowHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+// scanEscrowHold lê uma linha com as colunas de escrowColumns
+func scanEscrowHold(row rowScanner) (*domain.EscrowHold, error) {
+	var hold domain.EscrowHold
+	var releasedAt sql.NullTime
+	err := row.Scan(&hold.ID, &hold.AccountID, &hold.InvoiceID, &hold.Kind, &hold.Amount, &hold.Status, &hold.ReleaseAt,
+		&hold.ReleasedBy, &releasedAt, &hold.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if releasedAt.Valid {
+		hold.ReleasedAt = &releasedAt.Time
+	}
+	return &hold, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+const exportEntity = "export"
+
+const exportColumns = "id, account_id, type, object_key, file_name, content_type, size, downloads, created_at, expires_at"
+
+// ExportRepository implementa a persistência das exportações, com os downloads na trilha de auditoria
+type ExportRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewExportRepository cria um novo repositório de exportações para o banco do dialeto informado
+func NewExportRepository(db *sql.DB, dialect Dialect) *ExportRepository {
+	return &ExportRepository{db: db, dialect: dialect}
+}
+
+// Save grava a exportação e a entrada de auditoria na mesma transação
+func (r *ExportRepository) Save(ctx context.Context, export *domain.Export) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writeAudit(ctx, tx, r.dialect, exportEntity, export.ID, domain.AuditActionInsert, nil, NewExportSnapshot(export)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO exports ("+exportColumns+") VALUES "+valuesPlaceholders(1, 10)),
+		export.ID, export.AccountID, export.Type, export.ObjectKey, export.FileName, export.ContentType,
+		export.Size, export.Downloads, export.CreatedAt, export.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// scanExport lê uma exportação na ordem de exportColumns
+// Retorna ErrExportNotFound quando a consulta não encontra a exportação
+func scanExport(row rowScanner) (*domain.Export, error) {
+	var export domain.Export
+	err := row.Scan(
+		&export.ID,
+		&export.AccountID,
+		&export.Type,
+		&export.ObjectKey,
+		&export.FileName,
+		&export.ContentType,
+		&export.Size,
+		&export.Downloads,
+		&export.CreatedAt,
+		&export.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindByID busca a exportação
+// Retorna ErrExportNotFound se ela não existir
+func (r *ExportRepository) FindByID(ctx context.Context, id string) (*domain.Export, error) {
+	return scanExport(r.db.QueryRowContext(ctx, r.dialect.rebind("SELECT "+exportColumns+" FROM exports WHERE id = ?"), id))
+}
+
+// RecordDownload incrementa os downloads e registra o download na auditoria, na mesma transação
+// Retorna ErrExportNotFound se a exportação não existir
+func (r *ExportRepository) RecordDownload(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	export, err := scanExport(tx.QueryRowContext(ctx, r.dialect.rebind("SELECT "+exportColumns+" FROM exports WHERE id = ? FOR UPDATE"), id))
+	if err != nil {
+		return err
+	}
+
+	downloads := export.Downloads + 1
+	if err := writeAudit(ctx, tx, r.dialect, exportEntity, id, domain.AuditActionDownload,
+		NewExportDownloadSnapshot(ctx, export.Downloads), NewExportDownloadSnapshot(ctx, downloads)); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("UPDATE exports SET downloads = ? WHERE id = ?"), downloads, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// FeeScheduleRepository implementa a persistência das tarifas das contas
+type FeeScheduleRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewFeeScheduleRepository cria um novo repositório de tarifas para o banco do dialeto informado
+func NewFeeScheduleRepository(db *sql.DB, dialect Dialect) *FeeScheduleRepository {
+	return &FeeScheduleRepository{db: db, dialect: dialect}
+}
+
+// Save substitui as tarifas da conta em uma transação
+func (r *FeeScheduleRepository) Save(ctx context.Context, schedule *domain.FeeSchedule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM fee_schedules WHERE account_id = ?"), schedule.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO fee_schedules (account_id, chargeback_fee, updated_at) VALUES "+valuesPlaceholders(1, 3)),
+		schedule.AccountID, schedule.ChargebackFee, schedule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca as tarifas da conta
+// Retorna ErrFeeScheduleNotFound se a conta usar as tarifas padrão
+func (r *FeeScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	var schedule domain.FeeSchedule
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, chargeback_fee, updated_at FROM fee_schedules WHERE account_id = ?"),
+		accountID,
+	).Scan(&schedule.AccountID, &schedule.ChargebackFee, &schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrFeeScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Delete remove as tarifas da conta, que volta às tarifas padrão
+func (r *FeeScheduleRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM fee_schedules WHERE account_id = ?"), accountID)
+	return err
+}
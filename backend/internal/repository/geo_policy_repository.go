@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// GeoPolicyRepository implementa a persistência das políticas geográficas das contas
+// As listas de países são gravadas separadas por vírgulas
+type GeoPolicyRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewGeoPolicyRepository cria um novo repositório de políticas geográficas para o banco do dialeto informado
+func NewGeoPolicyRepository(db *sql.DB, dialect Dialect) *GeoPolicyRepository {
+	return &GeoPolicyRepository{db: db, dialect: dialect}
+}
+
+// Save substitui a política da conta em uma transação
+func (r *GeoPolicyRepository) Save(ctx context.Context, policy *domain.GeoPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM geo_policies WHERE account_id = ?"), policy.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO geo_policies (account_id, action, shadow, allowed_countries, blocked_countries, updated_at) VALUES "+valuesPlaceholders(1, 6)),
+		policy.AccountID, policy.Action, policy.Shadow, strings.Join(policy.AllowedCountries, ","), strings.Join(policy.BlockedCountries, ","), policy.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca a política da conta
+// Retorna ErrGeoPolicyNotFound se a conta não tiver política
+func (r *GeoPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.GeoPolicy, error) {
+	var policy domain.GeoPolicy
+	var allowed, blocked string
+
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, action, shadow, allowed_countries, blocked_countries, updated_at FROM geo_policies WHERE account_id = ?"),
+		accountID,
+	).Scan(&policy.AccountID, &policy.Action, &policy.Shadow, &allowed, &blocked, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrGeoPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed != "" {
+		policy.AllowedCountries = strings.Split(allowed, ",")
+	}
+	if blocked != "" {
+		policy.BlockedCountries = strings.Split(blocked, ",")
+	}
+	return &policy, nil
+}
@@ -0,0 +1,1716 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/joaodematejr/imersao22/go-gateway/internal/repository")
+
+// slowQueryThreshold é a duração a partir da qual uma chamada aos repositórios é registrada como lenta
+// Zero desativa a detecção; configurado uma vez na inicialização por SetSlowQueryThreshold
+var slowQueryThreshold time.Duration
+
+// SetSlowQueryThreshold define o limite das consultas lentas; zero desativa a detecção
+func SetSlowQueryThreshold(threshold time.Duration) {
+	slowQueryThreshold = threshold
+}
+
+// observe executa fn dentro de um span e registra latência, erros e linhas no Prometheus
+// fn retorna a quantidade de linhas lidas ou afetadas
+// Registros não encontrados são resultados esperados e não contam como erro
+func observe(ctx context.Context, repository, method string, fn func(ctx context.Context) (int64, error)) {
+	ctx, span := tracer.Start(ctx, repository+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.operation", method),
+		),
+	)
+	defer span.End()
+
+	var statements *database.StatementLog
+	if slowQueryThreshold > 0 {
+		ctx, statements = database.WithStatementLog(ctx)
+	}
+
+	start := time.Now()
+	rows, err := fn(ctx)
+	elapsed := time.Since(start)
+	metrics.RepositoryDuration.WithLabelValues(repository, method).Observe(elapsed.Seconds())
+	if slowQueryThreshold > 0 && elapsed >= slowQueryThreshold {
+		logSlowCall(ctx, repository, method, elapsed, rows, err, statements.Statements())
+	}
+
+	if err != nil && !isExpected(err) {
+		metrics.RepositoryErrorsTotal.WithLabelValues(repository, method).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	metrics.RepositoryRowsTotal.WithLabelValues(repository, method).Add(float64(rows))
+	span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+}
+
+// logSlowCall registra a chamada lenta em /metrics e no log
+// No PostgreSQL o log traz cada comando executado na chamada, com a duração e os parâmetros descritos sem os
+// valores de texto; nos demais bancos, apenas o repositório e o método
+func logSlowCall(ctx context.Context, repository, method string, elapsed time.Duration, rows int64, err error, statements []database.Statement) {
+	metrics.RepositorySlowCallsTotal.WithLabelValues(repository, method).Inc()
+
+	attrs := []any{
+		"repository", repository,
+		"method", method,
+		"duration_ms", elapsed.Milliseconds(),
+		"rows", rows,
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	if len(statements) > 0 {
+		groups := make([]any, len(statements))
+		for i, statement := range statements {
+			groups[i] = slog.Group(strconv.Itoa(i),
+				"sql", statement.SQL,
+				"args", strings.Join(statement.Args, ", "),
+				"duration_ms", statement.Duration.Milliseconds(),
+			)
+		}
+		attrs = append(attrs, slog.Group("statements", groups...))
+	}
+	slog.WarnContext(ctx, "consulta lenta no repositório", attrs...)
+}
+
+// isExpected indica os erros que são resultados normais de uma busca ou mudanças recusadas, não falhas do banco
+func isExpected(err error) bool {
+	return errors.Is(err, domain.ErrAccountNotFound) ||
+		errors.Is(err, domain.ErrInvoiceNotFound) ||
+		errors.Is(err, domain.ErrUserNotFound) ||
+		errors.Is(err, domain.ErrInvalidToken) ||
+		errors.Is(err, domain.ErrCardNotFound) ||
+		errors.Is(err, domain.ErrTwoFactorNotEnrolled) ||
+		errors.Is(err, domain.ErrSessionNotFound) ||
+		errors.Is(err, domain.ErrGeoPolicyNotFound) ||
+		errors.Is(err, domain.ErrAlertRuleNotFound) ||
+		errors.Is(err, domain.ErrBlocklistEntryNotFound) ||
+		errors.Is(err, domain.ErrBlocklistEntryExists) ||
+		errors.Is(err, domain.ErrRiskPolicyNotFound) ||
+		errors.Is(err, domain.ErrReviewNotFound) ||
+		errors.Is(err, domain.ErrReviewExists) ||
+		errors.Is(err, domain.ErrReviewAlreadyDecided) ||
+		errors.Is(err, domain.ErrPlatformLinkNotFound) ||
+		errors.Is(err, domain.ErrEscrowAlreadyReleased) ||
+		errors.Is(err, domain.ErrRefundPolicyNotFound) ||
+		errors.Is(err, domain.ErrRefundNotFound) ||
+		errors.Is(err, domain.ErrRefundAlreadyDecided) ||
+		errors.Is(err, domain.ErrDisputeNotFound) ||
+		errors.Is(err, domain.ErrDisputeClosed) ||
+		errors.Is(err, domain.ErrTransactionAlreadyDisputed) ||
+		errors.Is(err, domain.ErrFeeScheduleNotFound) ||
+		errors.Is(err, domain.ErrSubscriptionNotFound) ||
+		errors.Is(err, domain.ErrSubscriptionChanged) ||
+		errors.Is(err, domain.ErrCouponNotFound) ||
+		errors.Is(err, domain.ErrCouponExists) ||
+		errors.Is(err, domain.ErrCouponUnavailable) ||
+		errors.Is(err, domain.ErrCustomerNotFound) ||
+		errors.Is(err, domain.ErrPayoutScheduleNotFound) ||
+		errors.Is(err, domain.ErrTenantNotFound) ||
+		errors.Is(err, domain.ErrAmountLimitsNotFound) ||
+		errors.Is(err, domain.ErrStandingOrderNotFound) ||
+		errors.Is(err, domain.ErrStandingOrderChanged) ||
+		errors.Is(err, domain.ErrRefundWindowNotFound) ||
+		errors.Is(err, domain.ErrInvalidStatus)
+}
+
+// countOf retorna 1 quando a busca pontual encontrou o registro
+func countOf(err error) int64 {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+// InstrumentedAccountRepository registra métricas e spans das operações do repositório de contas
+type InstrumentedAccountRepository struct {
+	next domain.AccountRepository
+}
+
+// NewInstrumentedAccountRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAccountRepository(next domain.AccountRepository) *InstrumentedAccountRepository {
+	return &InstrumentedAccountRepository{next: next}
+}
+
+func (r *InstrumentedAccountRepository) Save(ctx context.Context, account *domain.Account) (err error) {
+	observe(ctx, accountEntity, "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, account)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (account *domain.Account, err error) {
+	observe(ctx, accountEntity, "FindByAPIKey", func(ctx context.Context) (int64, error) {
+		account, err = r.next.FindByAPIKey(ctx, apiKey)
+		return countOf(err), err
+	})
+	return account, err
+}
+
+func (r *InstrumentedAccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (account *domain.Account, err error) {
+	observe(ctx, accountEntity, "FindByID", func(ctx context.Context) (int64, error) {
+		account, err = r.next.FindByID(ctx, id, opts...)
+		return countOf(err), err
+	})
+	return account, err
+}
+
+func (r *InstrumentedAccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) (err error) {
+	observe(ctx, accountEntity, "UpdateBalance", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdateBalance(ctx, account)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) (err error) {
+	observe(ctx, accountEntity, "UpdateRole", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdateRole(ctx, id, role)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) (err error) {
+	observe(ctx, accountEntity, "UpdateTenant", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdateTenant(ctx, id, tenantID)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAccountRepository) Delete(ctx context.Context, id string) (err error) {
+	observe(ctx, accountEntity, "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAccountRepository) ListAfter(ctx context.Context, afterID string, limit int) (accounts []*domain.Account, err error) {
+	observe(ctx, accountEntity, "ListAfter", func(ctx context.Context) (int64, error) {
+		accounts, err = r.next.ListAfter(ctx, afterID, limit)
+		return int64(len(accounts)), err
+	})
+	return accounts, err
+}
+
+func (r *InstrumentedAccountRepository) CountDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	observe(ctx, accountEntity, "CountDeleted", func(ctx context.Context) (int64, error) {
+		count, err = r.next.CountDeleted(ctx, before)
+		return 1, err
+	})
+	return count, err
+}
+
+func (r *InstrumentedAccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	observe(ctx, accountEntity, "PurgeDeleted", func(ctx context.Context) (int64, error) {
+		count, err = r.next.PurgeDeleted(ctx, before)
+		return count, err
+	})
+	return count, err
+}
+
+// InstrumentedInvoiceRepository registra métricas e spans das operações do repositório de faturas
+type InstrumentedInvoiceRepository struct {
+	next domain.InvoiceRepository
+}
+
+// NewInstrumentedInvoiceRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedInvoiceRepository(next domain.InvoiceRepository) *InstrumentedInvoiceRepository {
+	return &InstrumentedInvoiceRepository{next: next}
+}
+
+func (r *InstrumentedInvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) (err error) {
+	observe(ctx, invoiceEntity, "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, invoice, splits, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) (err error) {
+	observe(ctx, invoiceEntity, "SaveBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveBatch(ctx, invoices)
+		return int64(len(invoices)), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (invoice *domain.Invoice, err error) {
+	observe(ctx, invoiceEntity, "FindByID", func(ctx context.Context) (int64, error) {
+		invoice, err = r.next.FindByID(ctx, id, opts...)
+		return countOf(err), err
+	})
+	return invoice, err
+}
+
+func (r *InstrumentedInvoiceRepository) FindByAccountID(ctx context.Context, accountID string) (invoices []*domain.Invoice, err error) {
+	observe(ctx, invoiceEntity, "FindByAccountID", func(ctx context.Context) (int64, error) {
+		invoices, err = r.next.FindByAccountID(ctx, accountID)
+		return int64(len(invoices)), err
+	})
+	return invoices, err
+}
+
+func (r *InstrumentedInvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) (invoices []*domain.Invoice, err error) {
+	observe(ctx, invoiceEntity, "FindByFilter", func(ctx context.Context) (int64, error) {
+		invoices, err = r.next.FindByFilter(ctx, filter)
+		return int64(len(invoices)), err
+	})
+	return invoices, err
+}
+
+func (r *InstrumentedInvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) (err error) {
+	observe(ctx, invoiceEntity, "UpdateStatus", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdateStatus(ctx, invoice, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceRepository) Delete(ctx context.Context, id string) (err error) {
+	observe(ctx, invoiceEntity, "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) (activity []domain.AccountActivity, err error) {
+	observe(ctx, invoiceEntity, "SummarizeActivity", func(ctx context.Context) (int64, error) {
+		activity, err = r.next.SummarizeActivity(ctx, from, to)
+		return int64(len(activity)), err
+	})
+	return activity, err
+}
+
+func (r *InstrumentedInvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) (totals []domain.StatusTotals, err error) {
+	observe(ctx, invoiceEntity, "SummarizeByStatus", func(ctx context.Context) (int64, error) {
+		totals, err = r.next.SummarizeByStatus(ctx, accountID, from, to)
+		return int64(len(totals)), err
+	})
+	return totals, err
+}
+
+func (r *InstrumentedInvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (totals domain.SpendingTotals, err error) {
+	observe(ctx, invoiceEntity, "SumSpending", func(ctx context.Context) (int64, error) {
+		totals, err = r.next.SumSpending(ctx, accountID, dayStart, monthStart)
+		return 1, err
+	})
+	return totals, err
+}
+
+func (r *InstrumentedInvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) (invoices []*domain.Invoice, err error) {
+	observe(ctx, invoiceEntity, "FindByPayer", func(ctx context.Context) (int64, error) {
+		invoices, err = r.next.FindByPayer(ctx, accountID, payerName)
+		return int64(len(invoices)), err
+	})
+	return invoices, err
+}
+
+func (r *InstrumentedInvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (count int64, err error) {
+	observe(ctx, invoiceEntity, "AnonymizePayer", func(ctx context.Context) (int64, error) {
+		count, err = r.next.AnonymizePayer(ctx, accountID, payerName, token)
+		return count, err
+	})
+	return count, err
+}
+
+func (r *InstrumentedInvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	observe(ctx, invoiceEntity, "CountDeleted", func(ctx context.Context) (int64, error) {
+		count, err = r.next.CountDeleted(ctx, before)
+		return 1, err
+	})
+	return count, err
+}
+
+func (r *InstrumentedInvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	observe(ctx, invoiceEntity, "PurgeDeleted", func(ctx context.Context) (int64, error) {
+		count, err = r.next.PurgeDeleted(ctx, before)
+		return count, err
+	})
+	return count, err
+}
+
+// InstrumentedAuditRepository registra métricas e spans das consultas à auditoria
+type InstrumentedAuditRepository struct {
+	next domain.AuditRepository
+}
+
+// NewInstrumentedAuditRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAuditRepository(next domain.AuditRepository) *InstrumentedAuditRepository {
+	return &InstrumentedAuditRepository{next: next}
+}
+
+func (r *InstrumentedAuditRepository) FindByFilter(ctx context.Context, filter domain.AuditFilter) (entries []*domain.AuditEntry, err error) {
+	observe(ctx, "audit", "FindByFilter", func(ctx context.Context) (int64, error) {
+		entries, err = r.next.FindByFilter(ctx, filter)
+		return int64(len(entries)), err
+	})
+	return entries, err
+}
+
+// InstrumentedAuthEventRepository registra métricas e spans das operações dos eventos de autenticação
+type InstrumentedAuthEventRepository struct {
+	next domain.AuthEventRepository
+}
+
+// NewInstrumentedAuthEventRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAuthEventRepository(next domain.AuthEventRepository) *InstrumentedAuthEventRepository {
+	return &InstrumentedAuthEventRepository{next: next}
+}
+
+func (r *InstrumentedAuthEventRepository) Save(ctx context.Context, event *domain.AuthEvent) (err error) {
+	observe(ctx, "auth_event", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, event)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAuthEventRepository) FindByFilter(ctx context.Context, filter domain.AuthEventFilter) (events []*domain.AuthEvent, err error) {
+	observe(ctx, "auth_event", "FindByFilter", func(ctx context.Context) (int64, error) {
+		events, err = r.next.FindByFilter(ctx, filter)
+		return int64(len(events)), err
+	})
+	return events, err
+}
+
+func (r *InstrumentedAuthEventRepository) CountFailedIPs(ctx context.Context, keyID string, since time.Time) (count int, err error) {
+	observe(ctx, "auth_event", "CountFailedIPs", func(ctx context.Context) (int64, error) {
+		count, err = r.next.CountFailedIPs(ctx, keyID, since)
+		return 1, err
+	})
+	return count, err
+}
+
+func (r *InstrumentedAuthEventRepository) FindAccountIPs(ctx context.Context, from, to time.Time) (ips []domain.AccountIP, err error) {
+	observe(ctx, "auth_event", "FindAccountIPs", func(ctx context.Context) (int64, error) {
+		ips, err = r.next.FindAccountIPs(ctx, from, to)
+		return int64(len(ips)), err
+	})
+	return ips, err
+}
+
+// InstrumentedCardRepository registra métricas e spans das operações do cofre de cartões
+type InstrumentedCardRepository struct {
+	next carddata.Repository
+}
+
+// NewInstrumentedCardRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedCardRepository(next carddata.Repository) *InstrumentedCardRepository {
+	return &InstrumentedCardRepository{next: next}
+}
+
+func (r *InstrumentedCardRepository) Save(ctx context.Context, record *carddata.Record) (err error) {
+	observe(ctx, "card", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, record)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCardRepository) SaveBatch(ctx context.Context, records []*carddata.Record) (err error) {
+	observe(ctx, "card", "SaveBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveBatch(ctx, records)
+		return int64(len(records)), err
+	})
+	return err
+}
+
+func (r *InstrumentedCardRepository) FindByToken(ctx context.Context, token string) (record *carddata.Record, err error) {
+	observe(ctx, "card", "FindByToken", func(ctx context.Context) (int64, error) {
+		record, err = r.next.FindByToken(ctx, token)
+		return countOf(err), err
+	})
+	return record, err
+}
+
+func (r *InstrumentedCardRepository) Anonymize(ctx context.Context, accountID string, tokens []string, holderName string) (err error) {
+	observe(ctx, "card", "Anonymize", func(ctx context.Context) (int64, error) {
+		err = r.next.Anonymize(ctx, accountID, tokens, holderName)
+		return int64(len(tokens)), err
+	})
+	return err
+}
+
+// InstrumentedTwoFactorRepository registra métricas e spans das operações do segundo fator
+type InstrumentedTwoFactorRepository struct {
+	next domain.TwoFactorRepository
+}
+
+// NewInstrumentedTwoFactorRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedTwoFactorRepository(next domain.TwoFactorRepository) *InstrumentedTwoFactorRepository {
+	return &InstrumentedTwoFactorRepository{next: next}
+}
+
+func (r *InstrumentedTwoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) (err error) {
+	observe(ctx, "two_factor", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, twoFactor)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedTwoFactorRepository) FindByActor(ctx context.Context, actor string) (twoFactor *domain.TwoFactor, err error) {
+	observe(ctx, "two_factor", "FindByActor", func(ctx context.Context) (int64, error) {
+		twoFactor, err = r.next.FindByActor(ctx, actor)
+		return countOf(err), err
+	})
+	return twoFactor, err
+}
+
+func (r *InstrumentedTwoFactorRepository) Update(ctx context.Context, twoFactor *domain.TwoFactor) (err error) {
+	observe(ctx, "two_factor", "Update", func(ctx context.Context) (int64, error) {
+		err = r.next.Update(ctx, twoFactor)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedTwoFactorRepository) Delete(ctx context.Context, actor string) (err error) {
+	observe(ctx, "two_factor", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, actor)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedGeoPolicyRepository registra métricas e spans das operações das políticas geográficas
+type InstrumentedGeoPolicyRepository struct {
+	next domain.GeoPolicyRepository
+}
+
+// NewInstrumentedGeoPolicyRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedGeoPolicyRepository(next domain.GeoPolicyRepository) *InstrumentedGeoPolicyRepository {
+	return &InstrumentedGeoPolicyRepository{next: next}
+}
+
+func (r *InstrumentedGeoPolicyRepository) Save(ctx context.Context, policy *domain.GeoPolicy) (err error) {
+	observe(ctx, "geo_policy", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, policy)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedGeoPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (policy *domain.GeoPolicy, err error) {
+	observe(ctx, "geo_policy", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		policy, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return policy, err
+}
+
+// InstrumentedSecurityWebhookRepository registra métricas e spans das operações das assinaturas de segurança
+type InstrumentedSecurityWebhookRepository struct {
+	next domain.SecurityWebhookRepository
+}
+
+// NewInstrumentedSecurityWebhookRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedSecurityWebhookRepository(next domain.SecurityWebhookRepository) *InstrumentedSecurityWebhookRepository {
+	return &InstrumentedSecurityWebhookRepository{next: next}
+}
+
+func (r *InstrumentedSecurityWebhookRepository) Save(ctx context.Context, webhook *domain.SecurityWebhook) (err error) {
+	observe(ctx, "security_webhook", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, webhook)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedSecurityWebhookRepository) FindByAccountID(ctx context.Context, accountID string) (webhook *domain.SecurityWebhook, err error) {
+	observe(ctx, "security_webhook", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		webhook, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return webhook, err
+}
+
+func (r *InstrumentedSecurityWebhookRepository) Delete(ctx context.Context, accountID string) (err error) {
+	observe(ctx, "security_webhook", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, accountID)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedDataSubjectRequestRepository registra métricas e spans das operações dos pedidos de titulares
+type InstrumentedDataSubjectRequestRepository struct {
+	next domain.DataSubjectRequestRepository
+}
+
+// NewInstrumentedDataSubjectRequestRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedDataSubjectRequestRepository(next domain.DataSubjectRequestRepository) *InstrumentedDataSubjectRequestRepository {
+	return &InstrumentedDataSubjectRequestRepository{next: next}
+}
+
+func (r *InstrumentedDataSubjectRequestRepository) Save(ctx context.Context, request *domain.DataSubjectRequest) (err error) {
+	observe(ctx, "data_subject_request", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, request)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedDataSubjectRequestRepository) FindByAccountID(ctx context.Context, accountID string) (requests []*domain.DataSubjectRequest, err error) {
+	observe(ctx, "data_subject_request", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		requests, err = r.next.FindByAccountID(ctx, accountID)
+		return int64(len(requests)), err
+	})
+	return requests, err
+}
+
+// InstrumentedExportRepository registra métricas e spans das operações do repositório de exportações
+type InstrumentedExportRepository struct {
+	next domain.ExportRepository
+}
+
+// NewInstrumentedExportRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedExportRepository(next domain.ExportRepository) *InstrumentedExportRepository {
+	return &InstrumentedExportRepository{next: next}
+}
+
+func (r *InstrumentedExportRepository) Save(ctx context.Context, export *domain.Export) (err error) {
+	observe(ctx, "export", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, export)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedExportRepository) FindByID(ctx context.Context, id string) (export *domain.Export, err error) {
+	observe(ctx, "export", "FindByID", func(ctx context.Context) (int64, error) {
+		export, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return export, err
+}
+
+func (r *InstrumentedExportRepository) RecordDownload(ctx context.Context, id string) (err error) {
+	observe(ctx, "export", "RecordDownload", func(ctx context.Context) (int64, error) {
+		err = r.next.RecordDownload(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedUserRepository registra métricas e spans das operações do repositório de usuários
+type InstrumentedUserRepository struct {
+	next domain.UserRepository
+}
+
+// NewInstrumentedUserRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedUserRepository(next domain.UserRepository) *InstrumentedUserRepository {
+	return &InstrumentedUserRepository{next: next}
+}
+
+func (r *InstrumentedUserRepository) Save(ctx context.Context, user *domain.User) (err error) {
+	observe(ctx, userEntity, "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, user)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) FindByEmail(ctx context.Context, email string) (user *domain.User, err error) {
+	observe(ctx, userEntity, "FindByEmail", func(ctx context.Context) (int64, error) {
+		user, err = r.next.FindByEmail(ctx, email)
+		return countOf(err), err
+	})
+	return user, err
+}
+
+func (r *InstrumentedUserRepository) FindByID(ctx context.Context, id string) (user *domain.User, err error) {
+	observe(ctx, userEntity, "FindByID", func(ctx context.Context) (int64, error) {
+		user, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return user, err
+}
+
+func (r *InstrumentedUserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) (err error) {
+	observe(ctx, userEntity, "UpdateRole", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdateRole(ctx, id, role)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) (err error) {
+	observe(ctx, userEntity, "UpdatePassword", func(ctx context.Context) (int64, error) {
+		err = r.next.UpdatePassword(ctx, id, passwordHash)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) SaveRefreshToken(ctx context.Context, token *domain.RefreshToken) (err error) {
+	observe(ctx, userEntity, "SaveRefreshToken", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveRefreshToken(ctx, token)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (token *domain.RefreshToken, err error) {
+	observe(ctx, userEntity, "FindRefreshToken", func(ctx context.Context) (int64, error) {
+		token, err = r.next.FindRefreshToken(ctx, tokenHash)
+		return countOf(err), err
+	})
+	return token, err
+}
+
+func (r *InstrumentedUserRepository) FindActiveRefreshTokens(ctx context.Context, userID string) (tokens []*domain.RefreshToken, err error) {
+	observe(ctx, userEntity, "FindActiveRefreshTokens", func(ctx context.Context) (int64, error) {
+		tokens, err = r.next.FindActiveRefreshTokens(ctx, userID)
+		return int64(len(tokens)), err
+	})
+	return tokens, err
+}
+
+func (r *InstrumentedUserRepository) RevokeRefreshToken(ctx context.Context, id string) (err error) {
+	observe(ctx, userEntity, "RevokeRefreshToken", func(ctx context.Context) (int64, error) {
+		err = r.next.RevokeRefreshToken(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) RevokeSession(ctx context.Context, userID, sessionID string) (err error) {
+	observe(ctx, userEntity, "RevokeSession", func(ctx context.Context) (int64, error) {
+		err = r.next.RevokeSession(ctx, userID, sessionID)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedUserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) (err error) {
+	observe(ctx, userEntity, "RevokeUserRefreshTokens", func(ctx context.Context) (int64, error) {
+		err = r.next.RevokeUserRefreshTokens(ctx, userID)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedAlertRuleRepository registra métricas e spans das operações das regras de alerta
+type InstrumentedAlertRuleRepository struct {
+	next domain.AlertRuleRepository
+}
+
+// NewInstrumentedAlertRuleRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAlertRuleRepository(next domain.AlertRuleRepository) *InstrumentedAlertRuleRepository {
+	return &InstrumentedAlertRuleRepository{next: next}
+}
+
+func (r *InstrumentedAlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) (err error) {
+	observe(ctx, "alert_rule", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, rule)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) (err error) {
+	observe(ctx, "alert_rule", "Update", func(ctx context.Context) (int64, error) {
+		err = r.next.Update(ctx, rule)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAlertRuleRepository) FindByID(ctx context.Context, id string) (rule *domain.AlertRule, err error) {
+	observe(ctx, "alert_rule", "FindByID", func(ctx context.Context) (int64, error) {
+		rule, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return rule, err
+}
+
+func (r *InstrumentedAlertRuleRepository) List(ctx context.Context) (rules []*domain.AlertRule, err error) {
+	observe(ctx, "alert_rule", "List", func(ctx context.Context) (int64, error) {
+		rules, err = r.next.List(ctx)
+		return int64(len(rules)), err
+	})
+	return rules, err
+}
+
+func (r *InstrumentedAlertRuleRepository) Delete(ctx context.Context, id string) (err error) {
+	observe(ctx, "alert_rule", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedBlocklistRepository registra métricas e spans das operações das listas de bloqueio
+type InstrumentedBlocklistRepository struct {
+	next domain.BlocklistRepository
+}
+
+// NewInstrumentedBlocklistRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedBlocklistRepository(next domain.BlocklistRepository) *InstrumentedBlocklistRepository {
+	return &InstrumentedBlocklistRepository{next: next}
+}
+
+func (r *InstrumentedBlocklistRepository) Create(ctx context.Context, entry *domain.BlocklistEntry) (err error) {
+	observe(ctx, "blocklist", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, entry)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedBlocklistRepository) FindByID(ctx context.Context, id string) (entry *domain.BlocklistEntry, err error) {
+	observe(ctx, "blocklist", "FindByID", func(ctx context.Context) (int64, error) {
+		entry, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return entry, err
+}
+
+func (r *InstrumentedBlocklistRepository) List(ctx context.Context, accountID string) (entries []*domain.BlocklistEntry, err error) {
+	observe(ctx, "blocklist", "List", func(ctx context.Context) (int64, error) {
+		entries, err = r.next.List(ctx, accountID)
+		return int64(len(entries)), err
+	})
+	return entries, err
+}
+
+func (r *InstrumentedBlocklistRepository) Match(ctx context.Context, accountID string, valueHashes []string) (entries []*domain.BlocklistEntry, err error) {
+	observe(ctx, "blocklist", "Match", func(ctx context.Context) (int64, error) {
+		entries, err = r.next.Match(ctx, accountID, valueHashes)
+		return int64(len(entries)), err
+	})
+	return entries, err
+}
+
+func (r *InstrumentedBlocklistRepository) RecordHits(ctx context.Context, ids []string, at time.Time) (err error) {
+	observe(ctx, "blocklist", "RecordHits", func(ctx context.Context) (int64, error) {
+		err = r.next.RecordHits(ctx, ids, at)
+		return int64(len(ids)), err
+	})
+	return err
+}
+
+func (r *InstrumentedBlocklistRepository) Delete(ctx context.Context, id string) (err error) {
+	observe(ctx, "blocklist", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedRiskPolicyRepository registra métricas e spans das operações dos limites de score
+type InstrumentedRiskPolicyRepository struct {
+	next domain.RiskPolicyRepository
+}
+
+// NewInstrumentedRiskPolicyRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedRiskPolicyRepository(next domain.RiskPolicyRepository) *InstrumentedRiskPolicyRepository {
+	return &InstrumentedRiskPolicyRepository{next: next}
+}
+
+func (r *InstrumentedRiskPolicyRepository) Save(ctx context.Context, policy *domain.RiskPolicy) (err error) {
+	observe(ctx, "risk_policy", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, policy)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedRiskPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (policy *domain.RiskPolicy, err error) {
+	observe(ctx, "risk_policy", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		policy, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return policy, err
+}
+
+// InstrumentedReviewRepository registra métricas e spans das operações da fila de revisão
+type InstrumentedReviewRepository struct {
+	next domain.ReviewRepository
+}
+
+// NewInstrumentedReviewRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedReviewRepository(next domain.ReviewRepository) *InstrumentedReviewRepository {
+	return &InstrumentedReviewRepository{next: next}
+}
+
+func (r *InstrumentedReviewRepository) Create(ctx context.Context, review *domain.InvoiceReview) (err error) {
+	observe(ctx, "review", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, review)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedReviewRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (review *domain.InvoiceReview, err error) {
+	observe(ctx, "review", "FindByInvoiceID", func(ctx context.Context) (int64, error) {
+		review, err = r.next.FindByInvoiceID(ctx, invoiceID)
+		return countOf(err), err
+	})
+	return review, err
+}
+
+func (r *InstrumentedReviewRepository) List(ctx context.Context, accountID string, status domain.ReviewStatus, limit int) (reviews []*domain.InvoiceReview, err error) {
+	observe(ctx, "review", "List", func(ctx context.Context) (int64, error) {
+		reviews, err = r.next.List(ctx, accountID, status, limit)
+		return int64(len(reviews)), err
+	})
+	return reviews, err
+}
+
+func (r *InstrumentedReviewRepository) ListDue(ctx context.Context, before time.Time, limit int) (reviews []*domain.InvoiceReview, err error) {
+	observe(ctx, "review", "ListDue", func(ctx context.Context) (int64, error) {
+		reviews, err = r.next.ListDue(ctx, before, limit)
+		return int64(len(reviews)), err
+	})
+	return reviews, err
+}
+
+func (r *InstrumentedReviewRepository) Decide(ctx context.Context, review *domain.InvoiceReview) (err error) {
+	observe(ctx, "review", "Decide", func(ctx context.Context) (int64, error) {
+		err = r.next.Decide(ctx, review)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedSplitRepository registra métricas e spans das operações das partes das faturas divididas
+type InstrumentedSplitRepository struct {
+	next domain.SplitRepository
+}
+
+// NewInstrumentedSplitRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedSplitRepository(next domain.SplitRepository) *InstrumentedSplitRepository {
+	return &InstrumentedSplitRepository{next: next}
+}
+
+func (r *InstrumentedSplitRepository) SaveBatch(ctx context.Context, splits []*domain.InvoiceSplit) (err error) {
+	observe(ctx, "split", "SaveBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveBatch(ctx, splits)
+		return int64(len(splits)), err
+	})
+	return err
+}
+
+func (r *InstrumentedSplitRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (splits []*domain.InvoiceSplit, err error) {
+	observe(ctx, "split", "FindByInvoiceID", func(ctx context.Context) (int64, error) {
+		splits, err = r.next.FindByInvoiceID(ctx, invoiceID)
+		return int64(len(splits)), err
+	})
+	return splits, err
+}
+
+// InstrumentedPlatformLinkRepository registra métricas e spans das operações das ligações com plataformas
+type InstrumentedPlatformLinkRepository struct {
+	next domain.PlatformLinkRepository
+}
+
+// NewInstrumentedPlatformLinkRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedPlatformLinkRepository(next domain.PlatformLinkRepository) *InstrumentedPlatformLinkRepository {
+	return &InstrumentedPlatformLinkRepository{next: next}
+}
+
+func (r *InstrumentedPlatformLinkRepository) Save(ctx context.Context, link *domain.PlatformLink) (err error) {
+	observe(ctx, "platform_link", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, link)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedPlatformLinkRepository) FindByAccountID(ctx context.Context, accountID string) (link *domain.PlatformLink, err error) {
+	observe(ctx, "platform_link", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		link, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return link, err
+}
+
+func (r *InstrumentedPlatformLinkRepository) CountByPlatformID(ctx context.Context, platformID string) (count int64, err error) {
+	observe(ctx, "platform_link", "CountByPlatformID", func(ctx context.Context) (int64, error) {
+		count, err = r.next.CountByPlatformID(ctx, platformID)
+		return countOf(err), err
+	})
+	return count, err
+}
+
+func (r *InstrumentedPlatformLinkRepository) Delete(ctx context.Context, accountID string) (err error) {
+	observe(ctx, "platform_link", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, accountID)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedLedgerRepository registra métricas e spans das operações do razão das contas
+type InstrumentedLedgerRepository struct {
+	next domain.LedgerRepository
+}
+
+// NewInstrumentedLedgerRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedLedgerRepository(next domain.LedgerRepository) *InstrumentedLedgerRepository {
+	return &InstrumentedLedgerRepository{next: next}
+}
+
+func (r *InstrumentedLedgerRepository) CreateBatch(ctx context.Context, entries []*domain.LedgerEntry) (err error) {
+	observe(ctx, "ledger", "CreateBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.CreateBatch(ctx, entries)
+		return int64(len(entries)), err
+	})
+	return err
+}
+
+func (r *InstrumentedLedgerRepository) Post(ctx context.Context, posting *domain.Posting) (err error) {
+	observe(ctx, "ledger", "Post", func(ctx context.Context) (int64, error) {
+		err = r.next.Post(ctx, posting)
+		return int64(len(posting.Entries) + len(posting.Holds) + len(posting.Releases) + len(posting.Balances)), err
+	})
+	return err
+}
+
+func (r *InstrumentedLedgerRepository) List(ctx context.Context, accountID string, from, to time.Time) (entries []*domain.LedgerEntry, err error) {
+	observe(ctx, "ledger", "List", func(ctx context.Context) (int64, error) {
+		entries, err = r.next.List(ctx, accountID, from, to)
+		return int64(len(entries)), err
+	})
+	return entries, err
+}
+
+func (r *InstrumentedLedgerRepository) SumByType(ctx context.Context, accountID string) (sums map[domain.LedgerEntryType]float64, err error) {
+	observe(ctx, "ledger", "SumByType", func(ctx context.Context) (int64, error) {
+		sums, err = r.next.SumByType(ctx, accountID)
+		return int64(len(sums)), err
+	})
+	return sums, err
+}
+
+func (r *InstrumentedLedgerRepository) Reconcile(ctx context.Context, accountID string, write, correct bool) (reconciliation *domain.BalanceReconciliation, err error) {
+	observe(ctx, "ledger", "Reconcile", func(ctx context.Context) (int64, error) {
+		reconciliation, err = r.next.Reconcile(ctx, accountID, write, correct)
+		return countOf(err), err
+	})
+	return reconciliation, err
+}
+
+// InstrumentedEscrowRepository registra métricas e spans das operações das retenções em custódia
+type InstrumentedEscrowRepository struct {
+	next domain.EscrowRepository
+}
+
+// NewInstrumentedEscrowRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedEscrowRepository(next domain.EscrowRepository) *InstrumentedEscrowRepository {
+	return &InstrumentedEscrowRepository{next: next}
+}
+
+func (r *InstrumentedEscrowRepository) CreateBatch(ctx context.Context, holds []*domain.EscrowHold) (err error) {
+	observe(ctx, "escrow", "CreateBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.CreateBatch(ctx, holds)
+		return int64(len(holds)), err
+	})
+	return err
+}
+
+func (r *InstrumentedEscrowRepository) ListByInvoiceID(ctx context.Context, invoiceID string) (holds []*domain.EscrowHold, err error) {
+	observe(ctx, "escrow", "ListByInvoiceID", func(ctx context.Context) (int64, error) {
+		holds, err = r.next.ListByInvoiceID(ctx, invoiceID)
+		return int64(len(holds)), err
+	})
+	return holds, err
+}
+
+func (r *InstrumentedEscrowRepository) ListDue(ctx context.Context, before time.Time, limit int) (holds []*domain.EscrowHold, err error) {
+	observe(ctx, "escrow", "ListDue", func(ctx context.Context) (int64, error) {
+		holds, err = r.next.ListDue(ctx, before, limit)
+		return int64(len(holds)), err
+	})
+	return holds, err
+}
+
+func (r *InstrumentedEscrowRepository) Release(ctx context.Context, hold *domain.EscrowHold) (err error) {
+	observe(ctx, "escrow", "Release", func(ctx context.Context) (int64, error) {
+		err = r.next.Release(ctx, hold)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedEscrowRepository) ListHeld(ctx context.Context, accountID string, kind domain.EscrowKind) (holds []*domain.EscrowHold, err error) {
+	observe(ctx, "escrow", "ListHeld", func(ctx context.Context) (int64, error) {
+		holds, err = r.next.ListHeld(ctx, accountID, kind)
+		return int64(len(holds)), err
+	})
+	return holds, err
+}
+
+func (r *InstrumentedEscrowRepository) SumHeld(ctx context.Context, accountID string, kind domain.EscrowKind) (total float64, err error) {
+	observe(ctx, "escrow", "SumHeld", func(ctx context.Context) (int64, error) {
+		total, err = r.next.SumHeld(ctx, accountID, kind)
+		return countOf(err), err
+	})
+	return total, err
+}
+
+// InstrumentedRefundPolicyRepository registra métricas e spans das operações das políticas de reembolso
+type InstrumentedRefundPolicyRepository struct {
+	next domain.RefundPolicyRepository
+}
+
+// NewInstrumentedRefundPolicyRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedRefundPolicyRepository(next domain.RefundPolicyRepository) *InstrumentedRefundPolicyRepository {
+	return &InstrumentedRefundPolicyRepository{next: next}
+}
+
+func (r *InstrumentedRefundPolicyRepository) Save(ctx context.Context, policy *domain.RefundPolicy) (err error) {
+	observe(ctx, "refund_policy", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, policy)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedRefundPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (policy *domain.RefundPolicy, err error) {
+	observe(ctx, "refund_policy", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		policy, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return policy, err
+}
+
+// InstrumentedRefundRepository registra métricas e spans das operações dos reembolsos
+type InstrumentedRefundRepository struct {
+	next domain.RefundRepository
+}
+
+// NewInstrumentedRefundRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedRefundRepository(next domain.RefundRepository) *InstrumentedRefundRepository {
+	return &InstrumentedRefundRepository{next: next}
+}
+
+func (r *InstrumentedRefundRepository) Create(ctx context.Context, refund *domain.Refund, posting *domain.Posting) (err error) {
+	observe(ctx, "refund", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, refund, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedRefundRepository) FindByID(ctx context.Context, id string) (refund *domain.Refund, err error) {
+	observe(ctx, "refund", "FindByID", func(ctx context.Context) (int64, error) {
+		refund, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return refund, err
+}
+
+func (r *InstrumentedRefundRepository) List(ctx context.Context, accountID string, status domain.RefundStatus, limit int) (refunds []*domain.Refund, err error) {
+	observe(ctx, "refund", "List", func(ctx context.Context) (int64, error) {
+		refunds, err = r.next.List(ctx, accountID, status, limit)
+		return int64(len(refunds)), err
+	})
+	return refunds, err
+}
+
+func (r *InstrumentedRefundRepository) ListExpired(ctx context.Context, before time.Time, limit int) (refunds []*domain.Refund, err error) {
+	observe(ctx, "refund", "ListExpired", func(ctx context.Context) (int64, error) {
+		refunds, err = r.next.ListExpired(ctx, before, limit)
+		return int64(len(refunds)), err
+	})
+	return refunds, err
+}
+
+func (r *InstrumentedRefundRepository) Decide(ctx context.Context, refund *domain.Refund, posting *domain.Posting) (err error) {
+	observe(ctx, "refund", "Decide", func(ctx context.Context) (int64, error) {
+		err = r.next.Decide(ctx, refund, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedRefundRepository) SumByInvoiceID(ctx context.Context, invoiceID string) (total float64, err error) {
+	observe(ctx, "refund", "SumByInvoiceID", func(ctx context.Context) (int64, error) {
+		total, err = r.next.SumByInvoiceID(ctx, invoiceID)
+		return countOf(err), err
+	})
+	return total, err
+}
+
+// InstrumentedDisputeRepository registra métricas e spans das operações das disputas e das suas evidências
+type InstrumentedDisputeRepository struct {
+	next domain.DisputeRepository
+}
+
+// NewInstrumentedDisputeRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedDisputeRepository(next domain.DisputeRepository) *InstrumentedDisputeRepository {
+	return &InstrumentedDisputeRepository{next: next}
+}
+
+func (r *InstrumentedDisputeRepository) Create(ctx context.Context, dispute *domain.Dispute, posting *domain.Posting) (err error) {
+	observe(ctx, "dispute", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, dispute, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedDisputeRepository) FindByID(ctx context.Context, id string) (dispute *domain.Dispute, err error) {
+	observe(ctx, "dispute", "FindByID", func(ctx context.Context) (int64, error) {
+		dispute, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return dispute, err
+}
+
+func (r *InstrumentedDisputeRepository) List(ctx context.Context, accountID string, status domain.DisputeStatus, limit int) (disputes []*domain.Dispute, err error) {
+	observe(ctx, "dispute", "List", func(ctx context.Context) (int64, error) {
+		disputes, err = r.next.List(ctx, accountID, status, limit)
+		return int64(len(disputes)), err
+	})
+	return disputes, err
+}
+
+func (r *InstrumentedDisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) (err error) {
+	observe(ctx, "dispute", "Resolve", func(ctx context.Context) (int64, error) {
+		err = r.next.Resolve(ctx, dispute)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedDisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) (err error) {
+	observe(ctx, "dispute", "AddEvidence", func(ctx context.Context) (int64, error) {
+		err = r.next.AddEvidence(ctx, evidence)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedDisputeRepository) ListEvidence(ctx context.Context, disputeID string) (evidence []*domain.DisputeEvidence, err error) {
+	observe(ctx, "dispute", "ListEvidence", func(ctx context.Context) (int64, error) {
+		evidence, err = r.next.ListEvidence(ctx, disputeID)
+		return int64(len(evidence)), err
+	})
+	return evidence, err
+}
+
+// InstrumentedFeeScheduleRepository registra métricas e spans das operações das tarifas das contas
+type InstrumentedFeeScheduleRepository struct {
+	next domain.FeeScheduleRepository
+}
+
+// NewInstrumentedFeeScheduleRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedFeeScheduleRepository(next domain.FeeScheduleRepository) *InstrumentedFeeScheduleRepository {
+	return &InstrumentedFeeScheduleRepository{next: next}
+}
+
+func (r *InstrumentedFeeScheduleRepository) Save(ctx context.Context, schedule *domain.FeeSchedule) (err error) {
+	observe(ctx, "fee_schedule", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, schedule)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedFeeScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (schedule *domain.FeeSchedule, err error) {
+	observe(ctx, "fee_schedule", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		schedule, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return schedule, err
+}
+
+func (r *InstrumentedFeeScheduleRepository) Delete(ctx context.Context, accountID string) (err error) {
+	observe(ctx, "fee_schedule", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, accountID)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedSubscriptionRepository registra métricas e spans das operações das assinaturas
+type InstrumentedSubscriptionRepository struct {
+	next domain.SubscriptionRepository
+}
+
+// NewInstrumentedSubscriptionRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedSubscriptionRepository(next domain.SubscriptionRepository) *InstrumentedSubscriptionRepository {
+	return &InstrumentedSubscriptionRepository{next: next}
+}
+
+func (r *InstrumentedSubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) (err error) {
+	observe(ctx, "subscription", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, subscription)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedSubscriptionRepository) FindByID(ctx context.Context, id string) (subscription *domain.Subscription, err error) {
+	observe(ctx, "subscription", "FindByID", func(ctx context.Context) (int64, error) {
+		subscription, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return subscription, err
+}
+
+func (r *InstrumentedSubscriptionRepository) List(ctx context.Context, accountID string, status domain.SubscriptionStatus, limit int) (subscriptions []*domain.Subscription, err error) {
+	observe(ctx, "subscription", "List", func(ctx context.Context) (int64, error) {
+		subscriptions, err = r.next.List(ctx, accountID, status, limit)
+		return int64(len(subscriptions)), err
+	})
+	return subscriptions, err
+}
+
+func (r *InstrumentedSubscriptionRepository) ListDue(ctx context.Context, before time.Time, limit int) (subscriptions []*domain.Subscription, err error) {
+	observe(ctx, "subscription", "ListDue", func(ctx context.Context) (int64, error) {
+		subscriptions, err = r.next.ListDue(ctx, before, limit)
+		return int64(len(subscriptions)), err
+	})
+	return subscriptions, err
+}
+
+func (r *InstrumentedSubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription, from domain.SubscriptionStatus) (err error) {
+	observe(ctx, "subscription", "Update", func(ctx context.Context) (int64, error) {
+		err = r.next.Update(ctx, subscription, from)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedCouponRepository registra métricas e spans das operações dos cupons
+type InstrumentedCouponRepository struct {
+	next domain.CouponRepository
+}
+
+// NewInstrumentedCouponRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedCouponRepository(next domain.CouponRepository) *InstrumentedCouponRepository {
+	return &InstrumentedCouponRepository{next: next}
+}
+
+func (r *InstrumentedCouponRepository) Create(ctx context.Context, coupon *domain.Coupon) (err error) {
+	observe(ctx, "coupon", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, coupon)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCouponRepository) FindByID(ctx context.Context, id string) (coupon *domain.Coupon, err error) {
+	observe(ctx, "coupon", "FindByID", func(ctx context.Context) (int64, error) {
+		coupon, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return coupon, err
+}
+
+func (r *InstrumentedCouponRepository) FindByCode(ctx context.Context, accountID, code string) (coupon *domain.Coupon, err error) {
+	observe(ctx, "coupon", "FindByCode", func(ctx context.Context) (int64, error) {
+		coupon, err = r.next.FindByCode(ctx, accountID, code)
+		return countOf(err), err
+	})
+	return coupon, err
+}
+
+func (r *InstrumentedCouponRepository) List(ctx context.Context, accountID string, limit int) (coupons []*domain.Coupon, err error) {
+	observe(ctx, "coupon", "List", func(ctx context.Context) (int64, error) {
+		coupons, err = r.next.List(ctx, accountID, limit)
+		return int64(len(coupons)), err
+	})
+	return coupons, err
+}
+
+func (r *InstrumentedCouponRepository) Deactivate(ctx context.Context, id string, updatedAt time.Time) (err error) {
+	observe(ctx, "coupon", "Deactivate", func(ctx context.Context) (int64, error) {
+		err = r.next.Deactivate(ctx, id, updatedAt)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCouponRepository) Redeem(ctx context.Context, redemption *domain.CouponRedemption) (err error) {
+	observe(ctx, "coupon", "Redeem", func(ctx context.Context) (int64, error) {
+		err = r.next.Redeem(ctx, redemption)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCouponRepository) SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (total float64, err error) {
+	observe(ctx, "coupon", "SumDiscounts", func(ctx context.Context) (int64, error) {
+		total, err = r.next.SumDiscounts(ctx, accountID, from, to)
+		return countOf(err), err
+	})
+	return total, err
+}
+
+// InstrumentedInvoiceTaxRepository registra métricas e spans das operações dos impostos das faturas
+type InstrumentedInvoiceTaxRepository struct {
+	next domain.InvoiceTaxRepository
+}
+
+// NewInstrumentedInvoiceTaxRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedInvoiceTaxRepository(next domain.InvoiceTaxRepository) *InstrumentedInvoiceTaxRepository {
+	return &InstrumentedInvoiceTaxRepository{next: next}
+}
+
+func (r *InstrumentedInvoiceTaxRepository) SaveBatch(ctx context.Context, taxes []*domain.InvoiceTax) (err error) {
+	observe(ctx, "invoice_tax", "SaveBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveBatch(ctx, taxes)
+		return int64(len(taxes)), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceTaxRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (taxes []*domain.InvoiceTax, err error) {
+	observe(ctx, "invoice_tax", "FindByInvoiceID", func(ctx context.Context) (int64, error) {
+		taxes, err = r.next.FindByInvoiceID(ctx, invoiceID)
+		return int64(len(taxes)), err
+	})
+	return taxes, err
+}
+
+// InstrumentedInvoiceItemRepository registra métricas e spans das operações dos itens das faturas
+type InstrumentedInvoiceItemRepository struct {
+	next domain.InvoiceItemRepository
+}
+
+// NewInstrumentedInvoiceItemRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedInvoiceItemRepository(next domain.InvoiceItemRepository) *InstrumentedInvoiceItemRepository {
+	return &InstrumentedInvoiceItemRepository{next: next}
+}
+
+func (r *InstrumentedInvoiceItemRepository) SaveBatch(ctx context.Context, items []*domain.InvoiceItem) (err error) {
+	observe(ctx, "invoice_item", "SaveBatch", func(ctx context.Context) (int64, error) {
+		err = r.next.SaveBatch(ctx, items)
+		return int64(len(items)), err
+	})
+	return err
+}
+
+func (r *InstrumentedInvoiceItemRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (items []*domain.InvoiceItem, err error) {
+	observe(ctx, "invoice_item", "FindByInvoiceID", func(ctx context.Context) (int64, error) {
+		items, err = r.next.FindByInvoiceID(ctx, invoiceID)
+		return int64(len(items)), err
+	})
+	return items, err
+}
+
+// InstrumentedCustomerRepository registra métricas e spans das operações dos clientes
+type InstrumentedCustomerRepository struct {
+	next domain.CustomerRepository
+}
+
+// NewInstrumentedCustomerRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedCustomerRepository(next domain.CustomerRepository) *InstrumentedCustomerRepository {
+	return &InstrumentedCustomerRepository{next: next}
+}
+
+func (r *InstrumentedCustomerRepository) Create(ctx context.Context, customer *domain.Customer) (err error) {
+	observe(ctx, "customer", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, customer)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCustomerRepository) FindByID(ctx context.Context, id string) (customer *domain.Customer, err error) {
+	observe(ctx, "customer", "FindByID", func(ctx context.Context) (int64, error) {
+		customer, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return customer, err
+}
+
+func (r *InstrumentedCustomerRepository) List(ctx context.Context, accountID string, limit int) (customers []*domain.Customer, err error) {
+	observe(ctx, "customer", "List", func(ctx context.Context) (int64, error) {
+		customers, err = r.next.List(ctx, accountID, limit)
+		return int64(len(customers)), err
+	})
+	return customers, err
+}
+
+func (r *InstrumentedCustomerRepository) Delete(ctx context.Context, id string) (err error) {
+	observe(ctx, "customer", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, id)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCustomerRepository) AddPaymentMethod(ctx context.Context, method *domain.CustomerPaymentMethod) (err error) {
+	observe(ctx, "customer", "AddPaymentMethod", func(ctx context.Context) (int64, error) {
+		err = r.next.AddPaymentMethod(ctx, method)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedCustomerRepository) ListPaymentMethods(ctx context.Context, customerID string) (methods []*domain.CustomerPaymentMethod, err error) {
+	observe(ctx, "customer", "ListPaymentMethods", func(ctx context.Context) (int64, error) {
+		methods, err = r.next.ListPaymentMethods(ctx, customerID)
+		return int64(len(methods)), err
+	})
+	return methods, err
+}
+
+func (r *InstrumentedCustomerRepository) RemovePaymentMethod(ctx context.Context, customerID, cardToken string) (err error) {
+	observe(ctx, "customer", "RemovePaymentMethod", func(ctx context.Context) (int64, error) {
+		err = r.next.RemovePaymentMethod(ctx, customerID, cardToken)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedPayoutScheduleRepository registra métricas e spans das operações dos prazos de repasse
+type InstrumentedPayoutScheduleRepository struct {
+	next domain.PayoutScheduleRepository
+}
+
+// NewInstrumentedPayoutScheduleRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedPayoutScheduleRepository(next domain.PayoutScheduleRepository) *InstrumentedPayoutScheduleRepository {
+	return &InstrumentedPayoutScheduleRepository{next: next}
+}
+
+func (r *InstrumentedPayoutScheduleRepository) Save(ctx context.Context, schedule *domain.PayoutSchedule) (err error) {
+	observe(ctx, "payout_schedule", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, schedule)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedPayoutScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (schedule *domain.PayoutSchedule, err error) {
+	observe(ctx, "payout_schedule", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		schedule, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return schedule, err
+}
+
+// InstrumentedAnticipationRepository registra métricas e spans das operações das antecipações
+type InstrumentedAnticipationRepository struct {
+	next domain.AnticipationRepository
+}
+
+// NewInstrumentedAnticipationRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAnticipationRepository(next domain.AnticipationRepository) *InstrumentedAnticipationRepository {
+	return &InstrumentedAnticipationRepository{next: next}
+}
+
+func (r *InstrumentedAnticipationRepository) Create(ctx context.Context, anticipation *domain.Anticipation, posting *domain.Posting) (err error) {
+	observe(ctx, "anticipation", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, anticipation, posting)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAnticipationRepository) ListByAccountID(ctx context.Context, accountID string, limit int) (anticipations []*domain.Anticipation, err error) {
+	observe(ctx, "anticipation", "ListByAccountID", func(ctx context.Context) (int64, error) {
+		anticipations, err = r.next.ListByAccountID(ctx, accountID, limit)
+		return int64(len(anticipations)), err
+	})
+	return anticipations, err
+}
+
+// InstrumentedTenantRepository registra métricas e spans das operações das organizações
+type InstrumentedTenantRepository struct {
+	next domain.TenantRepository
+}
+
+// NewInstrumentedTenantRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedTenantRepository(next domain.TenantRepository) *InstrumentedTenantRepository {
+	return &InstrumentedTenantRepository{next: next}
+}
+
+func (r *InstrumentedTenantRepository) Save(ctx context.Context, tenant *domain.Tenant) (err error) {
+	observe(ctx, "tenant", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, tenant)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedTenantRepository) FindByID(ctx context.Context, id string) (tenant *domain.Tenant, err error) {
+	observe(ctx, "tenant", "FindByID", func(ctx context.Context) (int64, error) {
+		tenant, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return tenant, err
+}
+
+func (r *InstrumentedTenantRepository) FindByAdminKeyHash(ctx context.Context, hash string) (tenant *domain.Tenant, err error) {
+	observe(ctx, "tenant", "FindByAdminKeyHash", func(ctx context.Context) (int64, error) {
+		tenant, err = r.next.FindByAdminKeyHash(ctx, hash)
+		return countOf(err), err
+	})
+	return tenant, err
+}
+
+func (r *InstrumentedTenantRepository) List(ctx context.Context) (tenants []*domain.Tenant, err error) {
+	observe(ctx, "tenant", "List", func(ctx context.Context) (int64, error) {
+		tenants, err = r.next.List(ctx)
+		return int64(len(tenants)), err
+	})
+	return tenants, err
+}
+
+func (r *InstrumentedTenantRepository) Update(ctx context.Context, tenant *domain.Tenant) (err error) {
+	observe(ctx, "tenant", "Update", func(ctx context.Context) (int64, error) {
+		err = r.next.Update(ctx, tenant)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedAmountLimitRepository registra métricas e spans das operações das faixas de valor das contas
+type InstrumentedAmountLimitRepository struct {
+	next domain.AccountAmountLimitsRepository
+}
+
+// NewInstrumentedAmountLimitRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedAmountLimitRepository(next domain.AccountAmountLimitsRepository) *InstrumentedAmountLimitRepository {
+	return &InstrumentedAmountLimitRepository{next: next}
+}
+
+func (r *InstrumentedAmountLimitRepository) Save(ctx context.Context, limits *domain.AccountAmountLimits) (err error) {
+	observe(ctx, "amount_limit", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, limits)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedAmountLimitRepository) FindByAccountID(ctx context.Context, accountID string) (limits *domain.AccountAmountLimits, err error) {
+	observe(ctx, "amount_limit", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		limits, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return limits, err
+}
+
+func (r *InstrumentedAmountLimitRepository) Delete(ctx context.Context, accountID string) (err error) {
+	observe(ctx, "amount_limit", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, accountID)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedStandingOrderRepository registra métricas e spans das operações das ordens permanentes
+type InstrumentedStandingOrderRepository struct {
+	next domain.StandingOrderRepository
+}
+
+// NewInstrumentedStandingOrderRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedStandingOrderRepository(next domain.StandingOrderRepository) *InstrumentedStandingOrderRepository {
+	return &InstrumentedStandingOrderRepository{next: next}
+}
+
+func (r *InstrumentedStandingOrderRepository) Create(ctx context.Context, order *domain.StandingOrder) (err error) {
+	observe(ctx, "standing_order", "Create", func(ctx context.Context) (int64, error) {
+		err = r.next.Create(ctx, order)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedStandingOrderRepository) FindByID(ctx context.Context, id string) (order *domain.StandingOrder, err error) {
+	observe(ctx, "standing_order", "FindByID", func(ctx context.Context) (int64, error) {
+		order, err = r.next.FindByID(ctx, id)
+		return countOf(err), err
+	})
+	return order, err
+}
+
+func (r *InstrumentedStandingOrderRepository) List(ctx context.Context, accountID string, status domain.StandingOrderStatus, limit int) (orders []*domain.StandingOrder, err error) {
+	observe(ctx, "standing_order", "List", func(ctx context.Context) (int64, error) {
+		orders, err = r.next.List(ctx, accountID, status, limit)
+		return int64(len(orders)), err
+	})
+	return orders, err
+}
+
+func (r *InstrumentedStandingOrderRepository) ListDue(ctx context.Context, before time.Time, limit int) (orders []*domain.StandingOrder, err error) {
+	observe(ctx, "standing_order", "ListDue", func(ctx context.Context) (int64, error) {
+		orders, err = r.next.ListDue(ctx, before, limit)
+		return int64(len(orders)), err
+	})
+	return orders, err
+}
+
+func (r *InstrumentedStandingOrderRepository) Update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) (err error) {
+	observe(ctx, "standing_order", "Update", func(ctx context.Context) (int64, error) {
+		err = r.next.Update(ctx, order, from)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedStandingOrderRepository) Run(ctx context.Context, order *domain.StandingOrder, now time.Time) (err error) {
+	observe(ctx, "standing_order", "Run", func(ctx context.Context) (int64, error) {
+		err = r.next.Run(ctx, order, now)
+		return countOf(err), err
+	})
+	return err
+}
+
+// InstrumentedRefundWindowRepository registra métricas e spans das operações dos prazos de reembolso das contas
+type InstrumentedRefundWindowRepository struct {
+	next domain.AccountRefundWindowRepository
+}
+
+// NewInstrumentedRefundWindowRepository envolve o repositório informado com a instrumentação
+func NewInstrumentedRefundWindowRepository(next domain.AccountRefundWindowRepository) *InstrumentedRefundWindowRepository {
+	return &InstrumentedRefundWindowRepository{next: next}
+}
+
+func (r *InstrumentedRefundWindowRepository) Save(ctx context.Context, window *domain.AccountRefundWindow) (err error) {
+	observe(ctx, "refund_window", "Save", func(ctx context.Context) (int64, error) {
+		err = r.next.Save(ctx, window)
+		return countOf(err), err
+	})
+	return err
+}
+
+func (r *InstrumentedRefundWindowRepository) FindByAccountID(ctx context.Context, accountID string) (window *domain.AccountRefundWindow, err error) {
+	observe(ctx, "refund_window", "FindByAccountID", func(ctx context.Context) (int64, error) {
+		window, err = r.next.FindByAccountID(ctx, accountID)
+		return countOf(err), err
+	})
+	return window, err
+}
+
+func (r *InstrumentedRefundWindowRepository) Delete(ctx context.Context, accountID string) (err error) {
+	observe(ctx, "refund_window", "Delete", func(ctx context.Context) (int64, error) {
+		err = r.next.Delete(ctx, accountID)
+		return countOf(err), err
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// invoiceItemColumns são as colunas dos itens das faturas, na ordem lida por FindByInvoiceID
+const invoiceItemColumns = "invoice_id, position, description, quantity, unit_price, created_at"
+
+// InvoiceItemRepository implementa a persistência dos itens das faturas
+type InvoiceItemRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewInvoiceItemRepository cria um novo repositório dos itens das faturas para o banco do dialeto informado
+func NewInvoiceItemRepository(db *sql.DB, dialect Dialect) *InvoiceItemRepository {
+	return &InvoiceItemRepository{db: db, dialect: dialect}
+}
+
+// SaveBatch grava os itens de uma ou mais faturas em um único INSERT
+func (r *InvoiceItemRepository) SaveBatch(ctx context.Context, items []*domain.InvoiceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(items)*6)
+	for _, item := range items {
+		args = append(args, item.InvoiceID, item.Position, item.Description, item.Quantity, item.UnitPrice, item.CreatedAt)
+	}
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO invoice_items ("+invoiceItemColumns+") VALUES "+valuesPlaceholders(len(items), 6)),
+		args...,
+	)
+	return err
+}
+
+// FindByInvoiceID retorna os itens da fatura na ordem dela, ou nenhum se ela não tem itens
+func (r *InvoiceItemRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceItem, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+invoiceItemColumns+" FROM invoice_items WHERE invoice_id = ? ORDER BY position"),
+		invoiceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.InvoiceItem
+	for rows.Next() {
+		var item domain.InvoiceItem
+		if err := rows.Scan(&item.InvoiceID, &item.Position, &item.Description, &item.Quantity, &item.UnitPrice, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
@@ -1,50 +1,194 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
 
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
 )
 
+const invoiceEntity = "invoice"
+
+const invoiceInsertColumns = "id, account_id, amount, status, description, payment_type, card_token, card_brand, card_last_digits, payer_name, metadata, created_at, updated_at"
+
+const invoiceColumns = invoiceInsertColumns + ", deleted_at"
+
+// invoiceInsertColumnCount é a quantidade de colunas em invoiceInsertColumns
+const invoiceInsertColumnCount = 13
+
+// invoiceBatchSize limita as linhas por INSERT de SaveBatch
+// 1000 linhas x 13 colunas fica bem abaixo do limite de 65535 parâmetros do PostgreSQL e do MySQL
+const invoiceBatchSize = 1000
+
+// InvoiceRepository implementa operações de persistência para Invoice
+// Escritas e leituras pontuais usam o primário; listagens usam a réplica de leitura
+// Faturas excluídas logicamente (deleted_at preenchido) ficam fora das consultas padrão
+// Os últimos dígitos do cartão são gravados cifrados
 type InvoiceRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	reader    *database.ReadRouter
+	dialect   Dialect
+	encryptor *pii.Encryptor
 }
 
-func NewInvoiceRepository(db *sql.DB) *InvoiceRepository {
-	return &InvoiceRepository{db: db}
+// NewInvoiceRepository cria um novo repositório de faturas para o banco do dialeto informado
+// Com encryptor nil os dados do cartão são gravados em texto puro
+func NewInvoiceRepository(db *sql.DB, reader *database.ReadRouter, dialect Dialect, encryptor *pii.Encryptor) *InvoiceRepository {
+	return &InvoiceRepository{db: db, reader: reader, dialect: dialect, encryptor: encryptor}
 }
 
-// Save salva uma fatura no banco de dados
-func (r *InvoiceRepository) Save(invoice *domain.Invoice) error {
-	_, err := r.db.Exec(
-		"INSERT INTO invoices (id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
-		invoice.ID, invoice.AccountID, invoice.Amount, invoice.Status, invoice.Description, invoice.PaymentType, invoice.CardLastDigits, invoice.CreatedAt, invoice.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-
-	return nil
+// rowScanner abstrai *sql.Row e *sql.Rows para reaproveitar o scan de faturas
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-// FindByID busca uma fatura pelo ID
-func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
+// scanInvoice lê uma fatura na ordem de invoiceColumns decifrando os dados do cartão
+func (r *InvoiceRepository) scanInvoice(ctx context.Context, row rowScanner) (*domain.Invoice, error) {
 	var invoice domain.Invoice
-	err := r.db.QueryRow(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at
-		FROM invoices
-		WHERE id = $1
-	`, id).Scan(
+	var metadata []byte
+	var deletedAt sql.NullTime
+
+	err := row.Scan(
 		&invoice.ID,
 		&invoice.AccountID,
 		&invoice.Amount,
 		&invoice.Status,
 		&invoice.Description,
 		&invoice.PaymentType,
+		&invoice.CardToken,
+		&invoice.CardBrand,
 		&invoice.CardLastDigits,
+		&invoice.PayerName,
+		&metadata,
 		&invoice.CreatedAt,
 		&invoice.UpdatedAt,
+		&deletedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		invoice.DeletedAt = &deletedAt.Time
+	}
+
+	if err := json.Unmarshal(metadata, &invoice.Metadata); err != nil {
+		return nil, err
+	}
+
+	if invoice.CardLastDigits, err = r.encryptor.Decrypt(ctx, invoice.CardLastDigits); err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+// Save salva uma fatura no banco de dados registrando a inserção na auditoria
+// As partes e o crédito, quando há, são gravados na mesma transação
+func (r *InvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) error {
+	metadata, err := json.Marshal(invoice.Metadata)
+	if err != nil {
+		return err
+	}
+
+	cardLastDigits, err := r.encryptor.Encrypt(ctx, invoice.CardLastDigits)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writeAudit(ctx, tx, r.dialect, invoiceEntity, invoice.ID, domain.AuditActionInsert, nil, NewInvoiceSnapshot(invoice)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO invoices ("+invoiceInsertColumns+") VALUES "+valuesPlaceholders(1, invoiceInsertColumnCount)),
+		invoice.ID, invoice.AccountID, invoice.Amount, invoice.Status, invoice.Description, invoice.PaymentType, invoice.CardToken, invoice.CardBrand, cardLastDigits, invoice.PayerName, string(metadata), invoice.CreatedAt, invoice.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := insertSplits(ctx, tx, r.dialect, splits); err != nil {
+		return err
+	}
+	if posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveBatch salva várias faturas em uma única transação usando INSERTs com várias linhas
+// Cada bloco de até invoiceBatchSize faturas é gravado, com sua auditoria, em uma ida ao banco
+func (r *InvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(invoices); start += invoiceBatchSize {
+		chunk := invoices[start:min(start+invoiceBatchSize, len(invoices))]
+
+		ids := make([]string, len(chunk))
+		snapshots := make([]any, len(chunk))
+		args := make([]any, 0, len(chunk)*invoiceInsertColumnCount)
+		for i, invoice := range chunk {
+			metadata, err := json.Marshal(invoice.Metadata)
+			if err != nil {
+				return err
+			}
+			cardLastDigits, err := r.encryptor.Encrypt(ctx, invoice.CardLastDigits)
+			if err != nil {
+				return err
+			}
+
+			ids[i] = invoice.ID
+			snapshots[i] = NewInvoiceSnapshot(invoice)
+			args = append(args, invoice.ID, invoice.AccountID, invoice.Amount, invoice.Status, invoice.Description, invoice.PaymentType, invoice.CardToken, invoice.CardBrand, cardLastDigits, invoice.PayerName, string(metadata), invoice.CreatedAt, invoice.UpdatedAt)
+		}
+
+		if err := writeInsertAudits(ctx, tx, r.dialect, invoiceEntity, ids, snapshots); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			r.dialect.rebind("INSERT INTO invoices ("+invoiceInsertColumns+") VALUES "+valuesPlaceholders(len(chunk), invoiceInsertColumnCount)),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByID busca uma fatura pelo ID
+// Faturas excluídas só são retornadas com domain.IncludeDeleted()
+func (r *InvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Invoice, error) {
+	query := "SELECT " + invoiceColumns + " FROM invoices WHERE id = ?"
+	if !domain.NewFindOptions(opts...).IncludeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	invoice, err := r.scanInvoice(ctx, r.db.QueryRowContext(ctx, r.dialect.rebind(query), id))
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrInvoiceNotFound
@@ -54,16 +198,23 @@ func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
 		return nil, err
 	}
 
-	return &invoice, nil
+	return invoice, nil
 }
 
-// FindByAccountID busca todas as faturas de um determinado accountID
-func (r *InvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice, error) {
-	rows, err := r.db.Query(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at
-		FROM invoices
-		WHERE account_id = $1
-	`, accountID)
+// FindByAccountID busca todas as faturas de um determinado accountID na réplica de leitura
+func (r *InvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
+	return r.FindByFilter(ctx, domain.InvoiceFilter{AccountID: accountID})
+}
+
+// FindByFilter busca faturas combinando os critérios informados na réplica de leitura
+// Cada critério preenchido vira uma condição parametrizada; os demais são ignorados
+func (r *InvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	query, args, err := buildInvoiceFilterQuery(r.dialect, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.reader.DB().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -72,38 +223,427 @@ func (r *InvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice
 
 	var invoices []*domain.Invoice
 	for rows.Next() {
-		var invoice domain.Invoice
-		err := rows.Scan(
-			&invoice.ID, &invoice.AccountID, &invoice.Amount, &invoice.Status, &invoice.Description, &invoice.PaymentType, &invoice.CardLastDigits, &invoice.CreatedAt, &invoice.UpdatedAt,
-		)
+		invoice, err := r.scanInvoice(ctx, rows)
 		if err != nil {
 			return nil, err
 		}
 
-		invoices = append(invoices, &invoice)
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+// buildInvoiceFilterQuery traduz o filtro em SQL parametrizado no dialeto informado
+func buildInvoiceFilterQuery(dialect Dialect, filter domain.InvoiceFilter) (string, []any, error) {
+	qb := newQueryBuilder(dialect)
+
+	if !filter.IncludeDeleted {
+		qb.where("deleted_at IS NULL")
+	}
+	if filter.AccountID != "" {
+		qb.where("account_id = ?", filter.AccountID)
+	}
+
+	statuses := make([]any, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		statuses[i] = status
+	}
+	qb.whereIn("status", statuses)
+
+	if !filter.CreatedFrom.IsZero() {
+		qb.where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		qb.where("created_at <= ?", filter.CreatedTo)
+	}
+	if filter.MinAmount > 0 {
+		qb.where("amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		qb.where("amount <= ?", filter.MaxAmount)
+	}
+
+	// No PostgreSQL o containment (@>) usa o índice GIN de metadata
+	if len(filter.Metadata) > 0 {
+		metadata, err := json.Marshal(filter.Metadata)
+		if err != nil {
+			return "", nil, err
+		}
+		qb.where(dialect.jsonContains("metadata"), string(metadata))
+	}
+
+	// Paginação por keyset: id desempata faturas criadas no mesmo instante
+	if filter.After != nil {
+		qb.where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	suffix := "ORDER BY created_at DESC, id DESC"
+	var suffixArgs []any
+
+	// A busca textual usa o índice de texto do banco e ordena pela relevância
+	if filter.Search != "" {
+		condition, rank := dialect.textSearch()
+		qb.where(condition, filter.Search)
+		suffix = "ORDER BY " + rank + " DESC, created_at DESC, id DESC"
+		suffixArgs = append(suffixArgs, filter.Search)
+	}
+
+	if filter.Limit > 0 {
+		suffix += " LIMIT " + strconv.Itoa(filter.Limit)
+	}
+
+	query, args := qb.build("SELECT "+invoiceColumns+" FROM invoices", suffix, suffixArgs...)
+	return query, args, nil
+}
+
+// lockInvoice lê a fatura ativa com SELECT FOR UPDATE dentro da transação
+func (r *InvoiceRepository) lockInvoice(ctx context.Context, tx *sql.Tx, id string) (*domain.Invoice, error) {
+	invoice, err := r.scanInvoice(ctx, tx.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+invoiceColumns+" FROM invoices WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return invoice, err
+}
+
+// lockInvoiceSnapshot lê com SELECT FOR UPDATE a fatura ativa na transação de outro repositório, como a de um
+// reembolso ou de uma disputa
+// Só lê as colunas do snapshot auditado, sem os dados cifrados do cartão
+// Retorna ErrInvoiceNotFound se a fatura não existir ou estiver excluída
+func lockInvoiceSnapshot(ctx context.Context, tx *sql.Tx, dialect Dialect, id string) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	var metadata []byte
+	err := tx.QueryRowContext(ctx,
+		dialect.rebind("SELECT id, account_id, amount, status, description, payment_type, payer_name, metadata, created_at, updated_at FROM invoices WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+		id,
+	).Scan(&invoice.ID, &invoice.AccountID, &invoice.Amount, &invoice.Status, &invoice.Description, &invoice.PaymentType,
+		&invoice.PayerName, &metadata, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metadata, &invoice.Metadata); err != nil {
+		return nil, err
 	}
+	return &invoice, nil
+}
 
-	return invoices, nil
+// setInvoiceStatus passa a fatura travada por lockInvoiceSnapshot para status, registrando o estado anterior na
+// auditoria
+func setInvoiceStatus(ctx context.Context, tx *sql.Tx, dialect Dialect, invoice *domain.Invoice, status domain.Status) error {
+	updated := *invoice
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	if err := writeAudit(ctx, tx, dialect, invoiceEntity, invoice.ID, domain.AuditActionUpdate, NewInvoiceSnapshot(invoice), NewInvoiceSnapshot(&updated)); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx,
+		dialect.rebind("UPDATE invoices SET status = ?, updated_at = ? WHERE id = ?"),
+		updated.Status, updated.UpdatedAt, invoice.ID,
+	)
+	return err
 }
 
-// UpdateStatus atualiza o status de uma fatura
-func (r *InvoiceRepository) UpdateStatus(invoice *domain.Invoice) error {
-	rows, err := r.db.Exec(
-		"UPDATE invoices SET status = $1, updated_at = $2 WHERE id = $3",
-		invoice.Status, invoice.UpdatedAt, invoice.ID,
+// UpdateStatus atualiza o status, o valor e os metadados de uma fatura registrando o estado anterior na auditoria
+// O crédito, quando há, é gravado na mesma transação, depois da conferência do status
+// Retorna ErrInvoiceNotFound se a fatura não existir e ErrInvalidStatus se o status gravado não puder mudar
+// para o da fatura, como quando outro resultado a decidiu antes
+func (r *InvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockInvoice(ctx, tx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	if !current.Status.CanTransitionTo(invoice.Status) {
+		return domain.ErrInvalidStatus
+	}
+
+	metadata, err := json.Marshal(invoice.Metadata)
+	if err != nil {
+		return err
+	}
+
+	if err := writeAudit(ctx, tx, r.dialect, invoiceEntity, invoice.ID, domain.AuditActionUpdate, NewInvoiceSnapshot(current), NewInvoiceSnapshot(invoice)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE invoices SET status = ?, amount = ?, metadata = ?, updated_at = ? WHERE id = ?"),
+		invoice.Status, invoice.Amount, string(metadata), invoice.UpdatedAt, invoice.ID,
 	)
 	if err != nil {
 		return err
 	}
+	if posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete exclui logicamente a fatura preenchendo deleted_at
+// Retorna ErrInvoiceNotFound se a fatura não existir ou já estiver excluída
+func (r *InvoiceRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	rowsAffected, err := rows.RowsAffected()
+	current, err := r.lockInvoice(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return domain.ErrInvoiceNotFound
+	deletedAt := time.Now()
+	deleted := NewInvoiceSnapshot(current)
+	deleted.UpdatedAt = deletedAt
+	deleted.DeletedAt = &deletedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, invoiceEntity, id, domain.AuditActionDelete, NewInvoiceSnapshot(current), deleted); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE invoices SET deleted_at = ?, updated_at = ? WHERE id = ?"),
+		deletedAt, deletedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SummarizeActivity conta por conta as faturas não excluídas criadas em [from, to) e quantas delas foram recusadas
+// A agregação roda na réplica de leitura, como as listagens
+func (r *InvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) ([]domain.AccountActivity, error) {
+	rows, err := r.reader.DB().QueryContext(ctx, r.dialect.rebind(`
+		SELECT account_id, COUNT(*), COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0)
+		FROM invoices
+		WHERE created_at >= ? AND created_at < ? AND deleted_at IS NULL
+		GROUP BY account_id
+	`), domain.StatusRejected, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []domain.AccountActivity
+	for rows.Next() {
+		var summary domain.AccountActivity
+		if err := rows.Scan(&summary.AccountID, &summary.Invoices, &summary.Declined); err != nil {
+			return nil, err
+		}
+		activity = append(activity, summary)
+	}
+	return activity, rows.Err()
+}
+
+// SummarizeByStatus agrega por status as faturas não excluídas da conta criadas em [from, to)
+// A agregação usa o índice de conta e criação e roda na réplica de leitura, como as listagens
+func (r *InvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) ([]domain.StatusTotals, error) {
+	rows, err := r.reader.DB().QueryContext(ctx, r.dialect.rebind(`
+		SELECT status, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM invoices
+		WHERE account_id = ? AND created_at >= ? AND created_at < ? AND deleted_at IS NULL
+		GROUP BY status
+	`), accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []domain.StatusTotals
+	for rows.Next() {
+		var total domain.StatusTotals
+		if err := rows.Scan(&total.Status, &total.Count, &total.Amount); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
+// SumSpending soma as faturas não recusadas e não excluídas da conta criadas desde dayStart e desde monthStart
+// Roda no banco principal com uma única varredura do mês pelo índice de conta e criação
+func (r *InvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (domain.SpendingTotals, error) {
+	var totals domain.SpendingTotals
+	err := r.db.QueryRowContext(ctx, r.dialect.rebind(`
+		SELECT COALESCE(SUM(CASE WHEN created_at >= ? THEN amount ELSE 0 END), 0), COALESCE(SUM(amount), 0)
+		FROM invoices
+		WHERE account_id = ? AND created_at >= ? AND status <> ? AND deleted_at IS NULL
+	`), dayStart, accountID, monthStart, domain.StatusRejected).Scan(&totals.Daily, &totals.Monthly)
+	return totals, err
+}
+
+// payerCondition seleciona as faturas da conta do pagador sem diferenciar maiúsculas
+const payerCondition = "account_id = ? AND LOWER(payer_name) = LOWER(?)"
+
+// FindByPayer busca as faturas da conta do pagador, inclusive excluídas e arquivadas, sem diferenciar maiúsculas
+func (r *InvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) ([]*domain.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(`
+		SELECT `+invoiceColumns+` FROM invoices WHERE `+payerCondition+`
+		UNION ALL
+		SELECT `+invoiceColumns+` FROM invoices_archive WHERE `+payerCondition+`
+		ORDER BY created_at DESC, id DESC
+	`), accountID, payerName, accountID, payerName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*domain.Invoice
+	for rows.Next() {
+		invoice, err := r.scanInvoice(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
+
+// AnonymizePayer troca o nome do pagador por token e apaga os últimos dígitos do cartão nas faturas da conta,
+// inclusive excluídas e arquivadas, reescrevendo os snapshots da auditoria delas na mesma transação
+func (r *InvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, r.dialect.rebind(`
+		SELECT id FROM invoices WHERE `+payerCondition+`
+		UNION ALL
+		SELECT id FROM invoices_archive WHERE `+payerCondition+`
+	`), accountID, payerName, accountID, payerName)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, tx.Commit()
+	}
+
+	now := time.Now()
+	for _, table := range []string{"invoices", "invoices_archive"} {
+		_, err := tx.ExecContext(ctx,
+			r.dialect.rebind("UPDATE "+table+" SET payer_name = ?, card_last_digits = '', updated_at = ? WHERE "+payerCondition),
+			token, now, accountID, payerName,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := anonymizeInvoiceAudit(ctx, tx, r.dialect, ids, payerName, token); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// CountDeleted conta as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(*) FROM invoices WHERE deleted_at IS NOT NULL AND deleted_at < ?"),
+		before,
+	).Scan(&count)
+	return count, err
+}
+
+// PurgeDeleted remove definitivamente as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("DELETE FROM invoices WHERE deleted_at IS NOT NULL AND deleted_at < ?"),
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountExpired conta as faturas criadas antes do instante informado, candidatas ao arquivamento
+func (r *InvoiceRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(*) FROM invoices WHERE created_at < ?"),
+		before,
+	).Scan(&count)
+	return count, err
+}
+
+// RemoveExpired move para invoices_archive as faturas criadas antes do instante informado
+// A remoção e a cópia acontecem na mesma transação, então nenhuma fatura é perdida em caso de falha
+func (r *InvoiceRepository) RemoveExpired(ctx context.Context, before time.Time) (int64, error) {
+	if r.dialect.deleteReturning() {
+		result, err := r.db.ExecContext(ctx, r.dialect.rebind(`
+			WITH archived AS (
+				DELETE FROM invoices WHERE created_at < ?
+				RETURNING `+invoiceColumns+`
+			)
+			INSERT INTO invoices_archive (`+invoiceColumns+`, archived_at)
+			SELECT `+invoiceColumns+`, NOW() FROM archived
+		`), before)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	// Sem DELETE ... RETURNING, copia e remove em dois comandos com o mesmo critério
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, r.dialect.rebind(`
+		INSERT INTO invoices_archive (`+invoiceColumns+`, archived_at)
+		SELECT `+invoiceColumns+`, NOW() FROM invoices WHERE created_at < ?
+	`), before)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM invoices WHERE created_at < ?"), before)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return removed, tx.Commit()
 }
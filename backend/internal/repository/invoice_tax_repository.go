@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// invoiceTaxColumns são as colunas dos impostos das faturas, na ordem lida por FindByInvoiceID
+const invoiceTaxColumns = "invoice_id, line, name, rate, amount, created_at"
+
+// InvoiceTaxRepository implementa a persistência dos impostos das faturas
+type InvoiceTaxRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewInvoiceTaxRepository cria um novo repositório dos impostos das faturas para o banco do dialeto informado
+func NewInvoiceTaxRepository(db *sql.DB, dialect Dialect) *InvoiceTaxRepository {
+	return &InvoiceTaxRepository{db: db, dialect: dialect}
+}
+
+// SaveBatch grava os impostos de uma ou mais faturas em um único INSERT
+func (r *InvoiceTaxRepository) SaveBatch(ctx context.Context, taxes []*domain.InvoiceTax) error {
+	if len(taxes) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(taxes)*6)
+	for _, tax := range taxes {
+		args = append(args, tax.InvoiceID, tax.Line, tax.Name, tax.Rate, tax.Amount, tax.CreatedAt)
+	}
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO invoice_taxes ("+invoiceTaxColumns+") VALUES "+valuesPlaceholders(len(taxes), 6)),
+		args...,
+	)
+	return err
+}
+
+// FindByInvoiceID retorna os impostos da fatura, ordenados pela linha e pelo nome, ou nenhum se ela não tem impostos
+func (r *InvoiceTaxRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceTax, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+invoiceTaxColumns+" FROM invoice_taxes WHERE invoice_id = ? ORDER BY line, name"),
+		invoiceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []*domain.InvoiceTax
+	for rows.Next() {
+		var tax domain.InvoiceTax
+		if err := rows.Scan(&tax.InvoiceID, &tax.Line, &tax.Name, &tax.Rate, &tax.Amount, &tax.CreatedAt); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, &tax)
+	}
+	return taxes, rows.Err()
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// ledgerColumns são as colunas dos lançamentos do razão, na ordem lida por List
+const ledgerColumns = "id, account_id, counterparty_id, invoice_id, type, amount, created_at"
+
+// ledgerBatchSize limita as linhas por INSERT de CreateBatch
+const ledgerBatchSize = 1000
+
+// LedgerRepository implementa a persistência do razão das contas
+type LedgerRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewLedgerRepository cria um novo repositório do razão para o banco do dialeto informado
+func NewLedgerRepository(db *sql.DB, dialect Dialect) *LedgerRepository {
+	return &LedgerRepository{db: db, dialect: dialect}
+}
+
+// CreateBatch grava os lançamentos em uma transação, em INSERTs de até ledgerBatchSize linhas
+func (r *LedgerRepository) CreateBatch(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertLedgerEntries(ctx, tx, r.dialect, entries); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertLedgerEntries grava os lançamentos em INSERTs de até ledgerBatchSize linhas
+func insertLedgerEntries(ctx context.Context, tx execer, dialect Dialect, entries []*domain.LedgerEntry) error {
+	for start := 0; start < len(entries); start += ledgerBatchSize {
+		chunk := entries[start:min(start+ledgerBatchSize, len(entries))]
+		args := make([]any, 0, len(chunk)*7)
+		for _, entry := range chunk {
+			args = append(args, entry.ID, entry.AccountID, entry.CounterpartyID, entry.InvoiceID, entry.Type, entry.Amount, entry.CreatedAt)
+		}
+		_, err := tx.ExecContext(ctx,
+			dialect.rebind("INSERT INTO ledger_entries ("+ledgerColumns+") VALUES "+valuesPlaceholders(len(chunk), 7)),
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Post grava o movimento em uma transação
+// Retorna ErrAccountNotFound se uma conta de Balances não existir, ErrEscrowAlreadyReleased se uma retenção de
+// Releases já tiver sido liberada e ErrInsufficientBalance se um débito Funded não couber no saldo
+func (r *LedgerRepository) Post(ctx context.Context, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writePosting grava o movimento na transação; os saldos são lidos com SELECT FOR UPDATE, na ordem dos IDs das
+// contas para não haver deadlock entre movimentos simultâneos, e o estado anterior de cada um vai para a auditoria
+// As faturas de Settles são travadas antes dos saldos, como nos reembolsos e nas disputas, e liquidadas no fim
+func writePosting(ctx context.Context, tx *sql.Tx, dialect Dialect, posting *domain.Posting) error {
+	invoices, err := lockSettlements(ctx, tx, dialect, posting)
+	if err != nil {
+		return err
+	}
+	if err := insertLedgerEntries(ctx, tx, dialect, posting.Entries); err != nil {
+		return err
+	}
+	if err := insertEscrowHolds(ctx, tx, dialect, posting.Holds); err != nil {
+		return err
+	}
+	for _, hold := range posting.Releases {
+		if err := releaseEscrowHold(ctx, tx, dialect, hold); err != nil {
+			return err
+		}
+	}
+	for _, accountID := range posting.AccountIDs() {
+		if err := addBalance(ctx, tx, dialect, posting, accountID); err != nil {
+			return err
+		}
+	}
+	return settleInvoices(ctx, tx, dialect, invoices)
+}
+
+// lockSettlements trava as faturas de Posting.Settles; as excluídas ficam de fora
+func lockSettlements(ctx context.Context, tx *sql.Tx, dialect Dialect, posting *domain.Posting) ([]*domain.Invoice, error) {
+	var invoices []*domain.Invoice
+	for _, invoiceID := range posting.Settles() {
+		invoice, err := lockInvoiceSnapshot(ctx, tx, dialect, invoiceID)
+		if errors.Is(err, domain.ErrInvoiceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, nil
+}
+
+// settleInvoices passa para StatusSettled as faturas travadas por lockSettlements que estiverem aprovadas e sem
+// retenção ainda retida
+func settleInvoices(ctx context.Context, tx *sql.Tx, dialect Dialect, invoices []*domain.Invoice) error {
+	for _, invoice := range invoices {
+		if !invoice.Status.CanTransitionTo(domain.StatusSettled) {
+			continue
+		}
+		var held int
+		err := tx.QueryRowContext(ctx,
+			dialect.rebind("SELECT COUNT(*) FROM escrow_holds WHERE invoice_id = ? AND status = ?"),
+			invoice.ID, domain.EscrowHeld,
+		).Scan(&held)
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			continue
+		}
+		if err := setInvoiceStatus(ctx, tx, dialect, invoice, domain.StatusSettled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addBalance soma o valor da conta no movimento ao saldo dela, travado na transação
+// Só lê as colunas do snapshot auditado, sem o e-mail cifrado
+// Retorna ErrInsufficientBalance se o movimento for Funded e o saldo travado não cobrir o débito
+func addBalance(ctx context.Context, tx *sql.Tx, dialect Dialect, posting *domain.Posting, accountID string) error {
+	amount := posting.Balances[accountID]
+	var account domain.Account
+	var scopes string
+	var tenantID sql.NullString
+	err := tx.QueryRowContext(ctx,
+		dialect.rebind("SELECT id, name, role, scopes, tenant_id, balance, created_at, updated_at FROM accounts WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+		accountID,
+	).Scan(&account.ID, &account.Name, &account.Role, &scopes, &tenantID, &account.Balance, &account.CreatedAt, &account.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+	account.Scopes = splitScopes(scopes)
+	account.TenantID = tenantID.String
+	if !posting.Covered(accountID, account.Balance) {
+		return domain.ErrInsufficientBalance
+	}
+
+	current := NewAccountSnapshot(&account)
+	updated := *current
+	updated.Balance += amount
+	updated.UpdatedAt = time.Now()
+	if err := writeAudit(ctx, tx, dialect, accountEntity, accountID, domain.AuditActionUpdate, current, &updated); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		dialect.rebind("UPDATE accounts SET balance = ?, updated_at = ? WHERE id = ?"),
+		updated.Balance, updated.UpdatedAt, accountID,
+	)
+	return err
+}
+
+// List retorna os lançamentos da conta criados em [from, to), dos mais antigos para os mais novos
+func (r *LedgerRepository) List(ctx context.Context, accountID string, from, to time.Time) ([]*domain.LedgerEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+ledgerColumns+" FROM ledger_entries WHERE account_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at, id"),
+		accountID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.LedgerEntry
+	for rows.Next() {
+		var entry domain.LedgerEntry
+		err := rows.Scan(&entry.ID, &entry.AccountID, &entry.CounterpartyID, &entry.InvoiceID, &entry.Type, &entry.Amount, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// SumByType soma os lançamentos da conta por tipo; os tipos sem lançamentos ficam fora do mapa
+func (r *LedgerRepository) SumByType(ctx context.Context, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	return sumLedgerByType(ctx, r.db, r.dialect, accountID)
+}
+
+// rowsQuerier é o *sql.DB ou a *sql.Tx de uma consulta de várias linhas
+type rowsQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// sumLedgerByType soma os lançamentos da conta por tipo
+func sumLedgerByType(ctx context.Context, q rowsQuerier, dialect Dialect, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	rows, err := q.QueryContext(ctx,
+		dialect.rebind("SELECT type, SUM(amount) FROM ledger_entries WHERE account_id = ? GROUP BY type"),
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sums := make(map[domain.LedgerEntryType]float64)
+	for rows.Next() {
+		var entryType domain.LedgerEntryType
+		var sum float64
+		if err := rows.Scan(&entryType, &sum); err != nil {
+			return nil, err
+		}
+		sums[entryType] = sum
+	}
+	return sums, rows.Err()
+}
+
+// Reconcile lê o saldo com SELECT FOR UPDATE e soma o razão na mesma transação, para que nenhum movimento caia entre
+// as duas leituras
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *LedgerRepository) Reconcile(ctx context.Context, accountID string, write, correct bool) (*domain.BalanceReconciliation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	account := domain.Account{ID: accountID}
+	err = tx.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT balance FROM accounts WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+		accountID,
+	).Scan(&account.Balance)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	sums, err := sumLedgerByType(ctx, tx, r.dialect, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := domain.NewBalanceReconciliation(&account, sums)
+	if !write {
+		return reconciliation, nil
+	}
+	if posting := reconciliation.Settle(correct); posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reconciliation, nil
+}
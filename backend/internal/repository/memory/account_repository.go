@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"crypto/subtle"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+const accountEntity = "account"
+
+// AccountRepository implementa domain.AccountRepository em memória
+type AccountRepository struct {
+	store *Store
+}
+
+// NewAccountRepository cria um repositório de contas sobre o armazenamento informado
+func NewAccountRepository(store *Store) *AccountRepository {
+	return &AccountRepository{store: store}
+}
+
+// Save armazena uma nova conta registrando a inserção na auditoria
+func (r *AccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.writeAudit(ctx, accountEntity, account.ID, domain.AuditActionInsert, nil, repository.NewAccountSnapshot(account)); err != nil {
+		return err
+	}
+
+	r.store.accounts[account.ID] = cloneAccount(account)
+	return nil
+}
+
+// FindByAPIKey busca uma conta ativa pelo API Key comparando as chaves em tempo constante
+// Retorna ErrAccountNotFound se não encontrada ou excluída
+func (r *AccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, account := range r.store.accounts {
+		if subtle.ConstantTimeCompare([]byte(account.APIKey), []byte(apiKey)) == 1 && account.DeletedAt == nil {
+			return cloneAccount(account), nil
+		}
+	}
+	return nil, domain.ErrAccountNotFound
+}
+
+// FindByID busca uma conta pelo ID
+// Contas excluídas só são retornadas com domain.IncludeDeleted()
+func (r *AccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Account, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	account, ok := r.store.accounts[id]
+	if !ok || (account.DeletedAt != nil && !domain.NewFindOptions(opts...).IncludeDeleted) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return cloneAccount(account), nil
+}
+
+// activeAccount retorna a conta ativa armazenada; deve ser chamado com o lock
+func (r *AccountRepository) activeAccount(id string) (*domain.Account, error) {
+	account, ok := r.store.accounts[id]
+	if !ok || account.DeletedAt != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// UpdateBalance atualiza o saldo da conta registrando o estado anterior na auditoria
+func (r *AccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeAccount(account.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := cloneAccount(current)
+	updated.Balance = account.Balance
+	updated.UpdatedAt = time.Now()
+
+	if err := r.store.writeAudit(ctx, accountEntity, account.ID, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), repository.NewAccountSnapshot(updated)); err != nil {
+		return err
+	}
+
+	r.store.accounts[account.ID] = updated
+	return nil
+}
+
+// UpdateRole altera o papel do API Key da conta registrando o estado anterior na auditoria
+func (r *AccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeAccount(id)
+	if err != nil {
+		return err
+	}
+
+	updated := cloneAccount(current)
+	updated.Role = role
+	updated.UpdatedAt = time.Now()
+
+	if err := r.store.writeAudit(ctx, accountEntity, id, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), repository.NewAccountSnapshot(updated)); err != nil {
+		return err
+	}
+
+	r.store.accounts[id] = updated
+	return nil
+}
+
+// UpdateTenant move a conta para a organização informada registrando o estado anterior na auditoria
+func (r *AccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeAccount(id)
+	if err != nil {
+		return err
+	}
+
+	updated := cloneAccount(current)
+	updated.TenantID = tenantID
+	updated.UpdatedAt = time.Now()
+
+	if err := r.store.writeAudit(ctx, accountEntity, id, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), repository.NewAccountSnapshot(updated)); err != nil {
+		return err
+	}
+
+	r.store.accounts[id] = updated
+	return nil
+}
+
+// Delete exclui logicamente a conta preenchendo DeletedAt
+func (r *AccountRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeAccount(id)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	deleted := cloneAccount(current)
+	deleted.UpdatedAt = deletedAt
+	deleted.DeletedAt = &deletedAt
+
+	if err := r.store.writeAudit(ctx, accountEntity, id, domain.AuditActionDelete, repository.NewAccountSnapshot(current), repository.NewAccountSnapshot(deleted)); err != nil {
+		return err
+	}
+
+	r.store.accounts[id] = deleted
+	return nil
+}
+
+// ListAfter retorna até limit contas não excluídas com ID maior que afterID, ordenadas pelo ID
+func (r *AccountRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.Account, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var accounts []*domain.Account
+	for _, account := range r.store.accounts {
+		if account.ID > afterID && account.DeletedAt == nil {
+			accounts = append(accounts, cloneAccount(account))
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return accounts, nil
+}
+
+// purgeable indica se a conta foi excluída antes de before e não tem mais faturas; deve ser chamado com o lock
+func (r *AccountRepository) purgeable(account *domain.Account, before time.Time) bool {
+	if account.DeletedAt == nil || !account.DeletedAt.Before(before) {
+		return false
+	}
+	for _, invoice := range r.store.invoices {
+		if invoice.AccountID == account.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// CountDeleted conta as contas que seriam removidas por PurgeDeleted
+func (r *AccountRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, account := range r.store.accounts {
+		if r.purgeable(account, before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// PurgeDeleted remove definitivamente as contas excluídas antes do instante informado que não têm faturas
+func (r *AccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var count int64
+	for id, account := range r.store.accounts {
+		if r.purgeable(account, before) {
+			delete(r.store.accounts, id)
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+func TestAccountRepositorySaveAndFind(t *testing.T) {
+	ctx := context.Background()
+	r := NewAccountRepository(NewStore())
+	account := domain.NewAccount("Loja", "loja@example.com")
+	if err := r.Save(ctx, account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	found, err := r.FindByID(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Name != account.Name || found.Email != account.Email || found.APIKey != account.APIKey {
+		t.Errorf("FindByID = %+v, want %+v", found, account)
+	}
+
+	// As contas devolvidas são cópias: alterar uma não muda a armazenada
+	found.Balance = 999
+	again, err := r.FindByAPIKey(ctx, account.APIKey)
+	if err != nil {
+		t.Fatalf("FindByAPIKey: %v", err)
+	}
+	if again.Balance != 0 {
+		t.Errorf("Balance = %v after changing a returned copy, want 0", again.Balance)
+	}
+
+	if _, err := r.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByID error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := r.FindByAPIKey(ctx, "missing"); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByAPIKey error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestAccountRepositoryUpdateBalance(t *testing.T) {
+	ctx := context.Background()
+	r := NewAccountRepository(NewStore())
+	account := domain.NewAccount("Loja", "loja@example.com")
+	if err := r.Save(ctx, account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	account.Balance = 150.25
+	if err := r.UpdateBalance(ctx, account); err != nil {
+		t.Fatalf("UpdateBalance: %v", err)
+	}
+	found, err := r.FindByID(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Balance != 150.25 {
+		t.Errorf("Balance = %v, want 150.25", found.Balance)
+	}
+
+	missing := domain.NewAccount("Outra", "outra@example.com")
+	if err := r.UpdateBalance(ctx, missing); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("UpdateBalance error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestAccountRepositoryDelete(t *testing.T) {
+	ctx := context.Background()
+	r := NewAccountRepository(NewStore())
+	account := domain.NewAccount("Loja", "loja@example.com")
+	if err := r.Save(ctx, account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := r.Delete(ctx, account.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.FindByID(ctx, account.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByID error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := r.FindByAPIKey(ctx, account.APIKey); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("FindByAPIKey error = %v, want ErrAccountNotFound", err)
+	}
+	found, err := r.FindByID(ctx, account.ID, domain.IncludeDeleted())
+	if err != nil {
+		t.Fatalf("FindByID with IncludeDeleted: %v", err)
+	}
+	if found.DeletedAt == nil {
+		t.Error("DeletedAt = nil, want the deletion time")
+	}
+	if err := r.Delete(ctx, account.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("second Delete error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestAccountRepositoryPurgeDeleted(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	accounts := NewAccountRepository(store)
+	invoices := NewInvoiceRepository(store)
+
+	withInvoice := domain.NewAccount("Com fatura", "com@example.com")
+	withoutInvoice := domain.NewAccount("Sem fatura", "sem@example.com")
+	active := domain.NewAccount("Ativa", "ativa@example.com")
+	for _, account := range []*domain.Account{withInvoice, withoutInvoice, active} {
+		if err := accounts.Save(ctx, account); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := invoices.Save(ctx, newInvoice(withInvoice.ID, 10, time.Now()), nil, nil); err != nil {
+		t.Fatalf("Save invoice: %v", err)
+	}
+	for _, account := range []*domain.Account{withInvoice, withoutInvoice} {
+		if err := accounts.Delete(ctx, account.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+
+	// Só a conta excluída e sem faturas é removida; a com faturas espera a remoção delas
+	before := time.Now().Add(time.Second)
+	count, err := accounts.CountDeleted(ctx, before)
+	if err != nil {
+		t.Fatalf("CountDeleted: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountDeleted = %d, want 1", count)
+	}
+	purged, err := accounts.PurgeDeleted(ctx, before)
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeDeleted = %d, want 1", purged)
+	}
+	if _, err := accounts.FindByID(ctx, withoutInvoice.ID, domain.IncludeDeleted()); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Errorf("purged account FindByID error = %v, want ErrAccountNotFound", err)
+	}
+	if _, err := accounts.FindByID(ctx, withInvoice.ID, domain.IncludeDeleted()); err != nil {
+		t.Errorf("account with invoices FindByID: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AlertRuleRepository implementa domain.AlertRuleRepository em memória
+type AlertRuleRepository struct {
+	store *Store
+}
+
+// NewAlertRuleRepository cria um repositório de regras de alerta sobre o armazenamento informado
+func NewAlertRuleRepository(store *Store) *AlertRuleRepository {
+	return &AlertRuleRepository{store: store}
+}
+
+func cloneAlertRule(rule *domain.AlertRule) *domain.AlertRule {
+	clone := *rule
+	return &clone
+}
+
+// Create grava uma nova regra
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.alertRules[rule.ID] = cloneAlertRule(rule)
+	return nil
+}
+
+// Update substitui a definição da regra
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.alertRules[rule.ID]; !ok {
+		return domain.ErrAlertRuleNotFound
+	}
+	r.store.alertRules[rule.ID] = cloneAlertRule(rule)
+	return nil
+}
+
+// FindByID busca a regra pelo ID
+func (r *AlertRuleRepository) FindByID(ctx context.Context, id string) (*domain.AlertRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rule, ok := r.store.alertRules[id]
+	if !ok {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+	return cloneAlertRule(rule), nil
+}
+
+// List retorna todas as regras, das mais antigas para as mais novas
+func (r *AlertRuleRepository) List(ctx context.Context) ([]*domain.AlertRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := make([]*domain.AlertRule, 0, len(r.store.alertRules))
+	for _, rule := range r.store.alertRules {
+		rules = append(rules, cloneAlertRule(rule))
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if !rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules, nil
+}
+
+// Delete remove a regra
+func (r *AlertRuleRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.alertRules[id]; !ok {
+		return domain.ErrAlertRuleNotFound
+	}
+	delete(r.store.alertRules, id)
+	return nil
+}
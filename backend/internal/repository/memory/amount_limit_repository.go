@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AmountLimitRepository implementa domain.AccountAmountLimitsRepository em memória
+type AmountLimitRepository struct {
+	store *Store
+}
+
+// NewAmountLimitRepository cria um repositório de faixas de valor sobre o armazenamento informado
+func NewAmountLimitRepository(store *Store) *AmountLimitRepository {
+	return &AmountLimitRepository{store: store}
+}
+
+// Save substitui a faixa da conta
+func (r *AmountLimitRepository) Save(ctx context.Context, limits *domain.AccountAmountLimits) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *limits
+	r.store.amountLimits[limits.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca a faixa da conta
+func (r *AmountLimitRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountAmountLimits, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	limits, ok := r.store.amountLimits[accountID]
+	if !ok {
+		return nil, domain.ErrAmountLimitsNotFound
+	}
+	clone := *limits
+	return &clone, nil
+}
+
+// Delete remove a faixa da conta
+func (r *AmountLimitRepository) Delete(ctx context.Context, accountID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.amountLimits, accountID)
+	return nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AnticipationRepository implementa domain.AnticipationRepository em memória
+type AnticipationRepository struct {
+	store *Store
+}
+
+// NewAnticipationRepository cria um repositório de antecipações sobre o armazenamento informado
+func NewAnticipationRepository(store *Store) *AnticipationRepository {
+	return &AnticipationRepository{store: store}
+}
+
+// Create grava a antecipação e o movimento com o lock de escrita
+// Retorna ErrEscrowAlreadyReleased se uma das retenções já tiver sido liberada
+func (r *AnticipationRepository) Create(ctx context.Context, anticipation *domain.Anticipation, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.post(ctx, posting); err != nil {
+		return err
+	}
+	clone := *anticipation
+	r.store.anticipations = append(r.store.anticipations, &clone)
+	return nil
+}
+
+// ListByAccountID retorna até limit antecipações da conta, das mais recentes para as mais antigas
+func (r *AnticipationRepository) ListByAccountID(ctx context.Context, accountID string, limit int) ([]*domain.Anticipation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var anticipations []*domain.Anticipation
+	for _, anticipation := range r.store.anticipations {
+		if anticipation.AccountID == accountID {
+			clone := *anticipation
+			anticipations = append(anticipations, &clone)
+		}
+	}
+	sort.Slice(anticipations, func(i, j int) bool {
+		if !anticipations[i].CreatedAt.Equal(anticipations[j].CreatedAt) {
+			return anticipations[i].CreatedAt.After(anticipations[j].CreatedAt)
+		}
+		return anticipations[i].ID < anticipations[j].ID
+	})
+	if len(anticipations) > limit {
+		anticipations = anticipations[:limit]
+	}
+	return anticipations, nil
+}
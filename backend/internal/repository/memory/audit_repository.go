@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// defaultAuditLimit limita consultas sem limite explícito, como no repositório Postgres
+const defaultAuditLimit = 100
+
+// AuditRepository implementa domain.AuditRepository em memória
+type AuditRepository struct {
+	store *Store
+}
+
+// NewAuditRepository cria um repositório de auditoria sobre o armazenamento informado
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+// FindByFilter busca entradas de auditoria, das mais recentes para as mais antigas
+func (r *AuditRepository) FindByFilter(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var afterID int64
+	if filter.After != nil {
+		id, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		afterID = id
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	var entries []*domain.AuditEntry
+	for i := len(r.store.audit) - 1; i >= 0 && len(entries) < limit; i-- {
+		entry := r.store.audit[i]
+		if filter.Entity != "" && entry.Entity != filter.Entity {
+			continue
+		}
+		if filter.EntityID != "" && entry.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.Actor != "" && entry.Actor != filter.Actor {
+			continue
+		}
+		if filter.RequestID != "" && entry.RequestID != filter.RequestID {
+			continue
+		}
+		if !filter.CreatedFrom.IsZero() && entry.CreatedAt.Before(filter.CreatedFrom) {
+			continue
+		}
+		if !filter.CreatedTo.IsZero() && entry.CreatedAt.After(filter.CreatedTo) {
+			continue
+		}
+		if filter.After != nil && !entryBefore(entry, filter.After.CreatedAt, afterID) {
+			continue
+		}
+
+		clone := *entry
+		entries = append(entries, &clone)
+	}
+	return entries, nil
+}
+
+// entryBefore indica se a entrada vem depois do cursor na ordenação (created_at DESC, id DESC)
+func entryBefore(entry *domain.AuditEntry, createdAt time.Time, id int64) bool {
+	if !entry.CreatedAt.Equal(createdAt) {
+		return entry.CreatedAt.Before(createdAt)
+	}
+	return entry.ID < id
+}
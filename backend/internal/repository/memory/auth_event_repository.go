@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// AuthEventRepository implementa domain.AuthEventRepository em memória
+type AuthEventRepository struct {
+	store *Store
+}
+
+// NewAuthEventRepository cria um repositório de eventos de autenticação sobre o armazenamento informado
+func NewAuthEventRepository(store *Store) *AuthEventRepository {
+	return &AuthEventRepository{store: store}
+}
+
+// Save armazena um evento de autenticação
+func (r *AuthEventRepository) Save(ctx context.Context, event *domain.AuthEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *event
+	r.store.authEvents = append(r.store.authEvents, &clone)
+	return nil
+}
+
+// FindByFilter busca os eventos de autenticação de uma conta, dos mais recentes para os mais antigos
+func (r *AuthEventRepository) FindByFilter(ctx context.Context, filter domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	var events []*domain.AuthEvent
+	for i := len(r.store.authEvents) - 1; i >= 0 && len(events) < limit; i-- {
+		event := r.store.authEvents[i]
+		if event.AccountID != filter.AccountID {
+			continue
+		}
+		if filter.Success != nil && event.Success != *filter.Success {
+			continue
+		}
+		if filter.After != nil && !after(filter.After.CreatedAt, filter.After.ID, event.CreatedAt, event.ID) {
+			continue
+		}
+
+		clone := *event
+		events = append(events, &clone)
+	}
+	return events, nil
+}
+
+// CountFailedIPs conta os IPs distintos com falhas de autenticação da credencial desde o instante informado
+func (r *AuthEventRepository) CountFailedIPs(ctx context.Context, keyID string, since time.Time) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ips := make(map[string]struct{})
+	for _, event := range r.store.authEvents {
+		if event.KeyID == keyID && !event.Success && !event.CreatedAt.Before(since) {
+			ips[event.IP] = struct{}{}
+		}
+	}
+	return len(ips), nil
+}
+
+// FindAccountIPs retorna os pares distintos de conta e IP com autenticações bem-sucedidas em [from, to)
+func (r *AuthEventRepository) FindAccountIPs(ctx context.Context, from, to time.Time) ([]domain.AccountIP, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[domain.AccountIP]struct{})
+	var ips []domain.AccountIP
+	for _, event := range r.store.authEvents {
+		if !event.Success || event.AccountID == "" || event.IP == "" || event.CreatedAt.Before(from) || !event.CreatedAt.Before(to) {
+			continue
+		}
+		ip := domain.AccountIP{AccountID: event.AccountID, IP: event.IP}
+		if _, ok := seen[ip]; !ok {
+			seen[ip] = struct{}{}
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// BlocklistRepository implementa domain.BlocklistRepository em memória
+type BlocklistRepository struct {
+	store *Store
+}
+
+// NewBlocklistRepository cria um repositório de listas de bloqueio sobre o armazenamento informado
+func NewBlocklistRepository(store *Store) *BlocklistRepository {
+	return &BlocklistRepository{store: store}
+}
+
+func cloneBlocklistEntry(entry *domain.BlocklistEntry) *domain.BlocklistEntry {
+	clone := *entry
+	return &clone
+}
+
+// Create grava uma nova entrada
+// Retorna ErrBlocklistEntryExists se a lista já tiver o valor, como o índice único dos bancos
+func (r *BlocklistRepository) Create(ctx context.Context, entry *domain.BlocklistEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.blocklist {
+		if existing.AccountID == entry.AccountID && existing.ValueHash == entry.ValueHash {
+			return domain.ErrBlocklistEntryExists
+		}
+	}
+	r.store.blocklist[entry.ID] = cloneBlocklistEntry(entry)
+	return nil
+}
+
+// FindByID busca a entrada pelo ID
+func (r *BlocklistRepository) FindByID(ctx context.Context, id string) (*domain.BlocklistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entry, ok := r.store.blocklist[id]
+	if !ok {
+		return nil, domain.ErrBlocklistEntryNotFound
+	}
+	return cloneBlocklistEntry(entry), nil
+}
+
+// List retorna as entradas da conta, ou as globais com accountID vazio, das mais novas para as mais antigas
+func (r *BlocklistRepository) List(ctx context.Context, accountID string) ([]*domain.BlocklistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []*domain.BlocklistEntry
+	for _, entry := range r.store.blocklist {
+		if entry.AccountID == accountID {
+			entries = append(entries, cloneBlocklistEntry(entry))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// Match busca as entradas globais e da conta com algum dos hashes informados
+func (r *BlocklistRepository) Match(ctx context.Context, accountID string, valueHashes []string) ([]*domain.BlocklistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []*domain.BlocklistEntry
+	for _, entry := range r.store.blocklist {
+		if (entry.AccountID == "" || entry.AccountID == accountID) && slices.Contains(valueHashes, entry.ValueHash) {
+			entries = append(entries, cloneBlocklistEntry(entry))
+		}
+	}
+	return entries, nil
+}
+
+// RecordHits soma um acerto às entradas e marca o instante do último
+func (r *BlocklistRepository) RecordHits(ctx context.Context, ids []string, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, id := range ids {
+		if entry, ok := r.store.blocklist[id]; ok {
+			entry.Hits++
+			hitAt := at
+			entry.LastHitAt = &hitAt
+		}
+	}
+	return nil
+}
+
+// Delete remove a entrada
+func (r *BlocklistRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.blocklist[id]; !ok {
+		return domain.ErrBlocklistEntryNotFound
+	}
+	delete(r.store.blocklist, id)
+	return nil
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CardRepository implementa carddata.Repository em memória
+type CardRepository struct {
+	store *Store
+}
+
+// NewCardRepository cria o armazenamento do cofre de cartões sobre o armazenamento informado
+func NewCardRepository(store *Store) *CardRepository {
+	return &CardRepository{store: store}
+}
+
+// Save armazena um cartão tokenizado
+func (r *CardRepository) Save(ctx context.Context, record *carddata.Record) error {
+	return r.SaveBatch(ctx, []*carddata.Record{record})
+}
+
+// SaveBatch armazena vários cartões tokenizados de uma vez
+func (r *CardRepository) SaveBatch(ctx context.Context, records []*carddata.Record) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, record := range records {
+		clone := *record
+		r.store.cards[record.Token] = &clone
+	}
+	return nil
+}
+
+// FindByToken busca um cartão pelo token
+func (r *CardRepository) FindByToken(ctx context.Context, token string) (*carddata.Record, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.cards[token]
+	if !ok {
+		return nil, domain.ErrCardNotFound
+	}
+	clone := *record
+	return &clone, nil
+}
+
+// Anonymize apaga número e final dos cartões da conta e troca o nome do portador por holderName
+func (r *CardRepository) Anonymize(ctx context.Context, accountID string, tokens []string, holderName string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, token := range tokens {
+		record, ok := r.store.cards[token]
+		if !ok || record.AccountID != accountID {
+			continue
+		}
+		anonymized := *record
+		anonymized.Number, anonymized.LastDigits, anonymized.HolderName = "", "", holderName
+		r.store.cards[token] = &anonymized
+	}
+	return nil
+}
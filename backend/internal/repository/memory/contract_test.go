@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/repositorytest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := NewStore()
+		return repositorytest.Repositories{
+			Accounts: NewAccountRepository(store),
+			Invoices: NewInvoiceRepository(store),
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CouponRepository implementa domain.CouponRepository em memória
+type CouponRepository struct {
+	store *Store
+}
+
+// NewCouponRepository cria um repositório de cupons sobre o armazenamento informado
+func NewCouponRepository(store *Store) *CouponRepository {
+	return &CouponRepository{store: store}
+}
+
+func cloneCoupon(coupon *domain.Coupon) *domain.Coupon {
+	clone := *coupon
+	return &clone
+}
+
+// Create grava o cupom
+// Retorna ErrCouponExists quando a conta já tem um cupom com o mesmo código
+func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.coupons {
+		if existing.AccountID == coupon.AccountID && existing.Code == coupon.Code {
+			return domain.ErrCouponExists
+		}
+	}
+	r.store.coupons[coupon.ID] = cloneCoupon(coupon)
+	return nil
+}
+
+// FindByID busca o cupom pelo ID
+func (r *CouponRepository) FindByID(ctx context.Context, id string) (*domain.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	coupon, ok := r.store.coupons[id]
+	if !ok {
+		return nil, domain.ErrCouponNotFound
+	}
+	return cloneCoupon(coupon), nil
+}
+
+// FindByCode busca o cupom da conta pelo código normalizado
+func (r *CouponRepository) FindByCode(ctx context.Context, accountID, code string) (*domain.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, coupon := range r.store.coupons {
+		if coupon.AccountID == accountID && coupon.Code == code {
+			return cloneCoupon(coupon), nil
+		}
+	}
+	return nil, domain.ErrCouponNotFound
+}
+
+// List retorna até limit cupons da conta, dos mais novos para os mais antigos
+func (r *CouponRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Coupon, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var coupons []*domain.Coupon
+	for _, coupon := range r.store.coupons {
+		if coupon.AccountID == accountID {
+			coupons = append(coupons, cloneCoupon(coupon))
+		}
+	}
+	sort.Slice(coupons, func(i, j int) bool {
+		if !coupons[i].CreatedAt.Equal(coupons[j].CreatedAt) {
+			return coupons[i].CreatedAt.After(coupons[j].CreatedAt)
+		}
+		return coupons[i].ID < coupons[j].ID
+	})
+	if len(coupons) > limit {
+		coupons = coupons[:limit]
+	}
+	return coupons, nil
+}
+
+// Deactivate desativa o cupom
+func (r *CouponRepository) Deactivate(ctx context.Context, id string, updatedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	coupon, ok := r.store.coupons[id]
+	if !ok {
+		return domain.ErrCouponNotFound
+	}
+	coupon.Active = false
+	coupon.UpdatedAt = updatedAt
+	return nil
+}
+
+// Redeem soma um aos usos do cupom ativo e com usos restantes e grava o uso
+func (r *CouponRepository) Redeem(ctx context.Context, redemption *domain.CouponRedemption) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	coupon, ok := r.store.coupons[redemption.CouponID]
+	if !ok || !coupon.Active || (coupon.MaxRedemptions > 0 && coupon.Redemptions >= coupon.MaxRedemptions) {
+		return domain.ErrCouponUnavailable
+	}
+	coupon.Redemptions++
+	coupon.UpdatedAt = redemption.CreatedAt
+
+	clone := *redemption
+	r.store.couponRedemptions = append(r.store.couponRedemptions, &clone)
+	return nil
+}
+
+// SumDiscounts soma os descontos dos usos de cupons da conta de from até to
+func (r *CouponRepository) SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total float64
+	for _, redemption := range r.store.couponRedemptions {
+		if redemption.AccountID == accountID && !redemption.CreatedAt.Before(from) && !redemption.CreatedAt.After(to) {
+			total += redemption.Discount
+		}
+	}
+	return total, nil
+}
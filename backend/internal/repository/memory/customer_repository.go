@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// CustomerRepository implementa domain.CustomerRepository em memória
+type CustomerRepository struct {
+	store *Store
+}
+
+// NewCustomerRepository cria um repositório de clientes sobre o armazenamento informado
+func NewCustomerRepository(store *Store) *CustomerRepository {
+	return &CustomerRepository{store: store}
+}
+
+func cloneCustomer(customer *domain.Customer) *domain.Customer {
+	clone := *customer
+	return &clone
+}
+
+// Create grava o cliente
+func (r *CustomerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.customers[customer.ID] = cloneCustomer(customer)
+	return nil
+}
+
+// FindByID busca o cliente pelo ID
+func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*domain.Customer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	customer, ok := r.store.customers[id]
+	if !ok {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return cloneCustomer(customer), nil
+}
+
+// List retorna até limit clientes da conta, dos mais novos para os mais antigos
+func (r *CustomerRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Customer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var customers []*domain.Customer
+	for _, customer := range r.store.customers {
+		if customer.AccountID == accountID {
+			customers = append(customers, cloneCustomer(customer))
+		}
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		if !customers[i].CreatedAt.Equal(customers[j].CreatedAt) {
+			return customers[i].CreatedAt.After(customers[j].CreatedAt)
+		}
+		return customers[i].ID < customers[j].ID
+	})
+	if len(customers) > limit {
+		customers = customers[:limit]
+	}
+	return customers, nil
+}
+
+// Delete apaga o cliente e os cartões ligados a ele
+func (r *CustomerRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.customers[id]; !ok {
+		return domain.ErrCustomerNotFound
+	}
+	delete(r.store.customers, id)
+	delete(r.store.paymentMethods, id)
+	return nil
+}
+
+// AddPaymentMethod liga o cartão ao cliente
+func (r *CustomerRepository) AddPaymentMethod(ctx context.Context, method *domain.CustomerPaymentMethod) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *method
+	r.store.paymentMethods[method.CustomerID] = append(r.store.paymentMethods[method.CustomerID], &clone)
+	return nil
+}
+
+// ListPaymentMethods retorna os cartões do cliente, dos mais antigos para os mais novos
+func (r *CustomerRepository) ListPaymentMethods(ctx context.Context, customerID string) ([]*domain.CustomerPaymentMethod, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var methods []*domain.CustomerPaymentMethod
+	for _, method := range r.store.paymentMethods[customerID] {
+		clone := *method
+		methods = append(methods, &clone)
+	}
+	return methods, nil
+}
+
+// RemovePaymentMethod desliga o cartão do cliente
+func (r *CustomerRepository) RemovePaymentMethod(ctx context.Context, customerID, cardToken string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	methods := r.store.paymentMethods[customerID]
+	for i, method := range methods {
+		if method.CardToken == cardToken {
+			r.store.paymentMethods[customerID] = append(methods[:i:i], methods[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrCardNotFound
+}
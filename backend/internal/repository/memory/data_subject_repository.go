@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// DataSubjectRequestRepository implementa domain.DataSubjectRequestRepository em memória
+type DataSubjectRequestRepository struct {
+	store *Store
+}
+
+// NewDataSubjectRequestRepository cria um repositório de pedidos de titulares sobre o armazenamento informado
+func NewDataSubjectRequestRepository(store *Store) *DataSubjectRequestRepository {
+	return &DataSubjectRequestRepository{store: store}
+}
+
+// Save registra o pedido atendido
+func (r *DataSubjectRequestRepository) Save(ctx context.Context, request *domain.DataSubjectRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *request
+	r.store.dataSubjectRequests = append(r.store.dataSubjectRequests, &clone)
+	return nil
+}
+
+// FindByAccountID retorna os pedidos da conta, dos mais recentes para os mais antigos
+func (r *DataSubjectRequestRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.DataSubjectRequest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var requests []*domain.DataSubjectRequest
+	for i := len(r.store.dataSubjectRequests) - 1; i >= 0; i-- {
+		if request := r.store.dataSubjectRequests[i]; request.AccountID == accountID {
+			clone := *request
+			requests = append(requests, &clone)
+		}
+	}
+	return requests, nil
+}
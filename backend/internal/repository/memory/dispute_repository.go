@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+// DisputeRepository implementa domain.DisputeRepository em memória
+type DisputeRepository struct {
+	store *Store
+}
+
+// NewDisputeRepository cria um repositório de disputas sobre o armazenamento informado
+func NewDisputeRepository(store *Store) *DisputeRepository {
+	return &DisputeRepository{store: store}
+}
+
+func cloneDispute(dispute *domain.Dispute) *domain.Dispute {
+	clone := *dispute
+	return &clone
+}
+
+// Create grava a disputa e, se posting não for nil, o movimento da tarifa com o lock de escrita
+// Retorna ErrTransactionAlreadyDisputed se a fatura já tiver disputa, como o índice único dos bancos
+func (r *DisputeRepository) Create(ctx context.Context, dispute *domain.Dispute, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.disputes {
+		if existing.InvoiceID == dispute.InvoiceID {
+			return domain.ErrTransactionAlreadyDisputed
+		}
+	}
+	if posting != nil {
+		if err := r.store.post(ctx, posting); err != nil {
+			return err
+		}
+	}
+	r.store.disputes[dispute.ID] = cloneDispute(dispute)
+	return nil
+}
+
+// FindByID busca a disputa pelo ID
+func (r *DisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	dispute, ok := r.store.disputes[id]
+	if !ok {
+		return nil, domain.ErrDisputeNotFound
+	}
+	return cloneDispute(dispute), nil
+}
+
+// List retorna até limit disputas da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais novas para as mais antigas
+func (r *DisputeRepository) List(ctx context.Context, accountID string, status domain.DisputeStatus, limit int) ([]*domain.Dispute, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var disputes []*domain.Dispute
+	for _, dispute := range r.store.disputes {
+		if (accountID == "" || dispute.AccountID == accountID) && (status == "" || dispute.Status == status) {
+			disputes = append(disputes, cloneDispute(dispute))
+		}
+	}
+	sort.Slice(disputes, func(i, j int) bool {
+		if !disputes[i].CreatedAt.Equal(disputes[j].CreatedAt) {
+			return disputes[i].CreatedAt.After(disputes[j].CreatedAt)
+		}
+		return disputes[i].ID < disputes[j].ID
+	})
+	if len(disputes) > limit {
+		disputes = disputes[:limit]
+	}
+	return disputes, nil
+}
+
+// Resolve grava o resultado se a disputa ainda estiver aberta e, na disputa perdida, o chargeback com o lock de
+// escrita
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.disputes[dispute.ID]
+	if !ok {
+		return domain.ErrDisputeNotFound
+	}
+	if current.Status != domain.DisputeOpen {
+		return domain.ErrDisputeClosed
+	}
+	if dispute.Status == domain.DisputeLost {
+		if err := r.chargeBack(ctx, dispute); err != nil {
+			return err
+		}
+	}
+	r.store.disputes[dispute.ID] = cloneDispute(dispute)
+	return nil
+}
+
+// chargeBack grava o ChargebackPosting das retenções ainda retidas da fatura e a passa para StatusChargedBack se
+// ela ainda estiver aprovada; deve ser chamado com o lock de escrita
+func (r *DisputeRepository) chargeBack(ctx context.Context, dispute *domain.Dispute) error {
+	invoice, ok := r.store.invoices[dispute.InvoiceID]
+	if !ok || invoice.DeletedAt != nil {
+		return domain.ErrInvoiceNotFound
+	}
+	var held []*domain.EscrowHold
+	for _, hold := range r.store.escrow {
+		if hold.InvoiceID == invoice.ID && hold.Status == domain.EscrowHeld {
+			held = append(held, cloneEscrowHold(hold))
+		}
+	}
+	posting, err := dispute.ChargebackPosting(held)
+	if err != nil {
+		return err
+	}
+	if err := r.store.post(ctx, posting); err != nil {
+		return err
+	}
+	if !invoice.Status.CanTransitionTo(domain.StatusChargedBack) {
+		return nil
+	}
+
+	updated := cloneInvoice(invoice)
+	updated.Status = domain.StatusChargedBack
+	updated.UpdatedAt = time.Now()
+	if err := r.store.writeAudit(ctx, invoiceEntity, invoice.ID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(invoice), repository.NewInvoiceSnapshot(updated)); err != nil {
+		return err
+	}
+	r.store.invoices[invoice.ID] = updated
+	return nil
+}
+
+// AddEvidence grava a evidência da disputa
+func (r *DisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *evidence
+	r.store.disputeEvidence = append(r.store.disputeEvidence, &clone)
+	return nil
+}
+
+// ListEvidence retorna as evidências da disputa na ordem do envio
+func (r *DisputeRepository) ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var evidence []*domain.DisputeEvidence
+	for _, item := range r.store.disputeEvidence {
+		if item.DisputeID == disputeID {
+			clone := *item
+			evidence = append(evidence, &clone)
+		}
+	}
+	return evidence, nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// EscrowRepository implementa domain.EscrowRepository em memória
+type EscrowRepository struct {
+	store *Store
+}
+
+// NewEscrowRepository cria um repositório de retenções em custódia sobre o armazenamento informado
+func NewEscrowRepository(store *Store) *EscrowRepository {
+	return &EscrowRepository{store: store}
+}
+
+func cloneEscrowHold(hold *domain.EscrowHold) *domain.EscrowHold {
+	clone := *hold
+	return &clone
+}
+
+// CreateBatch grava as retenções
+func (r *EscrowRepository) CreateBatch(ctx context.Context, holds []*domain.EscrowHold) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, hold := range holds {
+		r.store.escrow[hold.ID] = cloneEscrowHold(hold)
+	}
+	return nil
+}
+
+// ListByInvoiceID retorna as retenções da fatura, liberadas ou não, ordenadas pela conta
+func (r *EscrowRepository) ListByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.EscrowHold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var holds []*domain.EscrowHold
+	for _, hold := range r.store.escrow {
+		if hold.InvoiceID == invoiceID {
+			holds = append(holds, cloneEscrowHold(hold))
+		}
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		if holds[i].AccountID != holds[j].AccountID {
+			return holds[i].AccountID < holds[j].AccountID
+		}
+		return holds[i].ID < holds[j].ID
+	})
+	return holds, nil
+}
+
+// ListHeld retorna as retenções ainda retidas da conta pelo motivo kind, das que vencem primeiro
+func (r *EscrowRepository) ListHeld(ctx context.Context, accountID string, kind domain.EscrowKind) ([]*domain.EscrowHold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var holds []*domain.EscrowHold
+	for _, hold := range r.store.escrow {
+		if hold.AccountID == accountID && hold.Status == domain.EscrowHeld && hold.Kind == kind {
+			holds = append(holds, cloneEscrowHold(hold))
+		}
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		if !holds[i].ReleaseAt.Equal(holds[j].ReleaseAt) {
+			return holds[i].ReleaseAt.Before(holds[j].ReleaseAt)
+		}
+		return holds[i].ID < holds[j].ID
+	})
+	return holds, nil
+}
+
+// ListDue retorna até limit retenções ainda retidas com ReleaseAt até before, das que venceram primeiro
+func (r *EscrowRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.EscrowHold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var holds []*domain.EscrowHold
+	for _, hold := range r.store.escrow {
+		if hold.Status == domain.EscrowHeld && !hold.ReleaseAt.After(before) {
+			holds = append(holds, cloneEscrowHold(hold))
+		}
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		if !holds[i].ReleaseAt.Equal(holds[j].ReleaseAt) {
+			return holds[i].ReleaseAt.Before(holds[j].ReleaseAt)
+		}
+		return holds[i].ID < holds[j].ID
+	})
+	if len(holds) > limit {
+		holds = holds[:limit]
+	}
+	return holds, nil
+}
+
+// Release grava a liberação se a retenção ainda estiver retida
+func (r *EscrowRepository) Release(ctx context.Context, hold *domain.EscrowHold) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.escrow[hold.ID]
+	if !ok || current.Status != domain.EscrowHeld {
+		return domain.ErrEscrowAlreadyReleased
+	}
+	r.store.escrow[hold.ID] = cloneEscrowHold(hold)
+	return nil
+}
+
+// SumHeld soma o valor ainda retido da conta pelo motivo kind
+func (r *EscrowRepository) SumHeld(ctx context.Context, accountID string, kind domain.EscrowKind) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total float64
+	for _, hold := range r.store.escrow {
+		if hold.AccountID == accountID && hold.Status == domain.EscrowHeld && hold.Kind == kind {
+			total += hold.Amount
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+const exportEntity = "export"
+
+// ExportRepository implementa domain.ExportRepository em memória
+type ExportRepository struct {
+	store *Store
+}
+
+// NewExportRepository cria um repositório de exportações sobre o armazenamento informado
+func NewExportRepository(store *Store) *ExportRepository {
+	return &ExportRepository{store: store}
+}
+
+// Save armazena a exportação registrando a inserção na auditoria
+func (r *ExportRepository) Save(ctx context.Context, export *domain.Export) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.writeAudit(ctx, exportEntity, export.ID, domain.AuditActionInsert, nil, repository.NewExportSnapshot(export)); err != nil {
+		return err
+	}
+
+	clone := *export
+	r.store.exports[export.ID] = &clone
+	return nil
+}
+
+// FindByID busca a exportação
+// Retorna ErrExportNotFound se ela não existir
+func (r *ExportRepository) FindByID(ctx context.Context, id string) (*domain.Export, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	export, ok := r.store.exports[id]
+	if !ok {
+		return nil, domain.ErrExportNotFound
+	}
+	clone := *export
+	return &clone, nil
+}
+
+// RecordDownload incrementa os downloads e registra o download na auditoria
+// Retorna ErrExportNotFound se a exportação não existir
+func (r *ExportRepository) RecordDownload(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	export, ok := r.store.exports[id]
+	if !ok {
+		return domain.ErrExportNotFound
+	}
+
+	downloads := export.Downloads + 1
+	if err := r.store.writeAudit(ctx, exportEntity, id, domain.AuditActionDownload,
+		repository.NewExportDownloadSnapshot(ctx, export.Downloads), repository.NewExportDownloadSnapshot(ctx, downloads)); err != nil {
+		return err
+	}
+	export.Downloads = downloads
+	return nil
+}
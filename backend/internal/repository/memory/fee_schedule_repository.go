@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// FeeScheduleRepository implementa domain.FeeScheduleRepository em memória
+type FeeScheduleRepository struct {
+	store *Store
+}
+
+// NewFeeScheduleRepository cria um repositório de tarifas sobre o armazenamento informado
+func NewFeeScheduleRepository(store *Store) *FeeScheduleRepository {
+	return &FeeScheduleRepository{store: store}
+}
+
+// Save substitui as tarifas da conta
+func (r *FeeScheduleRepository) Save(ctx context.Context, schedule *domain.FeeSchedule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *schedule
+	r.store.feeSchedules[schedule.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca as tarifas da conta
+func (r *FeeScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	schedule, ok := r.store.feeSchedules[accountID]
+	if !ok {
+		return nil, domain.ErrFeeScheduleNotFound
+	}
+	clone := *schedule
+	return &clone, nil
+}
+
+// Delete remove as tarifas da conta, que volta às tarifas padrão
+func (r *FeeScheduleRepository) Delete(ctx context.Context, accountID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.feeSchedules, accountID)
+	return nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// GeoPolicyRepository implementa domain.GeoPolicyRepository em memória
+type GeoPolicyRepository struct {
+	store *Store
+}
+
+// NewGeoPolicyRepository cria um repositório de políticas geográficas sobre o armazenamento informado
+func NewGeoPolicyRepository(store *Store) *GeoPolicyRepository {
+	return &GeoPolicyRepository{store: store}
+}
+
+func cloneGeoPolicy(policy *domain.GeoPolicy) *domain.GeoPolicy {
+	clone := *policy
+	clone.AllowedCountries = slices.Clone(policy.AllowedCountries)
+	clone.BlockedCountries = slices.Clone(policy.BlockedCountries)
+	return &clone
+}
+
+// Save substitui a política da conta
+func (r *GeoPolicyRepository) Save(ctx context.Context, policy *domain.GeoPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.geoPolicies[policy.AccountID] = cloneGeoPolicy(policy)
+	return nil
+}
+
+// FindByAccountID busca a política da conta
+func (r *GeoPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.GeoPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	policy, ok := r.store.geoPolicies[accountID]
+	if !ok {
+		return nil, domain.ErrGeoPolicyNotFound
+	}
+	return cloneGeoPolicy(policy), nil
+}
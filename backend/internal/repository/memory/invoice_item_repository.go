@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// InvoiceItemRepository implementa domain.InvoiceItemRepository em memória
+type InvoiceItemRepository struct {
+	store *Store
+}
+
+// NewInvoiceItemRepository cria um repositório dos itens das faturas sobre o armazenamento informado
+func NewInvoiceItemRepository(store *Store) *InvoiceItemRepository {
+	return &InvoiceItemRepository{store: store}
+}
+
+// SaveBatch grava os itens de uma ou mais faturas
+func (r *InvoiceItemRepository) SaveBatch(ctx context.Context, items []*domain.InvoiceItem) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, item := range items {
+		clone := *item
+		r.store.invoiceItems[item.InvoiceID] = append(r.store.invoiceItems[item.InvoiceID], &clone)
+	}
+	return nil
+}
+
+// FindByInvoiceID retorna os itens da fatura na ordem dela, ou nenhum se ela não tem itens
+func (r *InvoiceItemRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []*domain.InvoiceItem
+	for _, item := range r.store.invoiceItems[invoiceID] {
+		clone := *item
+		items = append(items, &clone)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	return items, nil
+}
@@ -0,0 +1,407 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+const invoiceEntity = "invoice"
+
+// InvoiceRepository implementa domain.InvoiceRepository em memória
+type InvoiceRepository struct {
+	store *Store
+}
+
+// NewInvoiceRepository cria um repositório de faturas sobre o armazenamento informado
+func NewInvoiceRepository(store *Store) *InvoiceRepository {
+	return &InvoiceRepository{store: store}
+}
+
+// Save armazena uma nova fatura registrando a inserção na auditoria
+// O crédito, quando há, é conferido antes de qualquer gravação, para que uma falha não deixe a fatura nem as partes
+func (r *InvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if posting != nil {
+		if err := r.store.checkPosting(posting); err != nil {
+			return err
+		}
+	}
+	if err := r.store.writeAudit(ctx, invoiceEntity, invoice.ID, domain.AuditActionInsert, nil, repository.NewInvoiceSnapshot(invoice)); err != nil {
+		return err
+	}
+
+	r.store.invoices[invoice.ID] = cloneInvoice(invoice)
+	r.store.saveSplits(splits)
+	if posting == nil {
+		return nil
+	}
+	return r.store.writePosting(ctx, posting)
+}
+
+// SaveBatch armazena várias faturas de uma vez registrando as inserções na auditoria
+func (r *InvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, invoice := range invoices {
+		if err := r.store.writeAudit(ctx, invoiceEntity, invoice.ID, domain.AuditActionInsert, nil, repository.NewInvoiceSnapshot(invoice)); err != nil {
+			return err
+		}
+	}
+	for _, invoice := range invoices {
+		r.store.invoices[invoice.ID] = cloneInvoice(invoice)
+	}
+	return nil
+}
+
+// FindByID busca uma fatura pelo ID
+// Faturas excluídas só são retornadas com domain.IncludeDeleted()
+func (r *InvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok || (invoice.DeletedAt != nil && !domain.NewFindOptions(opts...).IncludeDeleted) {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return cloneInvoice(invoice), nil
+}
+
+// FindByAccountID busca todas as faturas ativas de um determinado accountID
+func (r *InvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
+	return r.FindByFilter(ctx, domain.InvoiceFilter{AccountID: accountID})
+}
+
+// FindByFilter busca faturas que atendem a todos os critérios, das mais recentes para as mais antigas
+// A ordenação e o cursor seguem (created_at DESC, id DESC), como no repositório Postgres
+func (r *InvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	terms := strings.Fields(strings.ToLower(filter.Search))
+
+	var invoices []*domain.Invoice
+	ranks := map[string]int{}
+	for _, invoice := range r.store.invoices {
+		if !matchesFilter(invoice, filter) {
+			continue
+		}
+		if len(terms) > 0 {
+			rank := searchRank(invoice, terms)
+			if rank == 0 {
+				continue
+			}
+			ranks[invoice.ID] = rank
+		}
+		invoices = append(invoices, cloneInvoice(invoice))
+	}
+
+	sort.Slice(invoices, func(i, j int) bool {
+		if ranks[invoices[i].ID] != ranks[invoices[j].ID] {
+			return ranks[invoices[i].ID] > ranks[invoices[j].ID]
+		}
+		return after(invoices[i].CreatedAt, invoices[i].ID, invoices[j].CreatedAt, invoices[j].ID)
+	})
+
+	if filter.Limit > 0 && len(invoices) > filter.Limit {
+		invoices = invoices[:filter.Limit]
+	}
+	return invoices, nil
+}
+
+// matchesFilter aplica os mesmos critérios da consulta SQL do repositório Postgres
+func matchesFilter(invoice *domain.Invoice, filter domain.InvoiceFilter) bool {
+	if invoice.DeletedAt != nil && !filter.IncludeDeleted {
+		return false
+	}
+	if filter.AccountID != "" && invoice.AccountID != filter.AccountID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, invoice.Status) {
+		return false
+	}
+	if !filter.CreatedFrom.IsZero() && invoice.CreatedAt.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedTo.IsZero() && invoice.CreatedAt.After(filter.CreatedTo) {
+		return false
+	}
+	if filter.MinAmount > 0 && invoice.Amount < filter.MinAmount {
+		return false
+	}
+	if filter.MaxAmount > 0 && invoice.Amount > filter.MaxAmount {
+		return false
+	}
+	if filter.After != nil && !after(filter.After.CreatedAt, filter.After.ID, invoice.CreatedAt, invoice.ID) {
+		return false
+	}
+	for key, value := range filter.Metadata {
+		if v, ok := invoice.Metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// searchRank aproxima a busca textual do Postgres: todos os termos precisam aparecer no nome do pagador,
+// na descrição ou nos valores de metadata, e a relevância é a quantidade de ocorrências
+// Retorna zero quando algum termo não aparece
+func searchRank(invoice *domain.Invoice, terms []string) int {
+	words := strings.Fields(strings.ToLower(invoice.PayerName + " " + invoice.Description))
+	for _, value := range invoice.Metadata {
+		words = append(words, strings.Fields(strings.ToLower(value))...)
+	}
+
+	rank := 0
+	for _, term := range terms {
+		count := 0
+		for _, word := range words {
+			if word == term {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		rank += count
+	}
+	return rank
+}
+
+// activeInvoice retorna a fatura ativa armazenada; deve ser chamado com o lock
+func (r *InvoiceRepository) activeInvoice(id string) (*domain.Invoice, error) {
+	invoice, ok := r.store.invoices[id]
+	if !ok || invoice.DeletedAt != nil {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// UpdateStatus atualiza o status, o valor e os metadados de uma fatura registrando o estado anterior na auditoria
+// O crédito, quando há, é conferido junto com o status e gravado depois da fatura
+// Retorna ErrInvalidStatus se o status gravado não puder mudar para o da fatura
+func (r *InvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeInvoice(invoice.ID)
+	if err != nil {
+		return err
+	}
+	if !current.Status.CanTransitionTo(invoice.Status) {
+		return domain.ErrInvalidStatus
+	}
+	if posting != nil {
+		if err := r.store.checkPosting(posting); err != nil {
+			return err
+		}
+	}
+
+	updated := cloneInvoice(current)
+	updated.Status = invoice.Status
+	updated.Amount = invoice.Amount
+	if invoice.Metadata != nil {
+		updated.Metadata = maps.Clone(invoice.Metadata)
+	}
+	updated.UpdatedAt = invoice.UpdatedAt
+
+	if err := r.store.writeAudit(ctx, invoiceEntity, invoice.ID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(current), repository.NewInvoiceSnapshot(updated)); err != nil {
+		return err
+	}
+
+	r.store.invoices[invoice.ID] = updated
+	if posting == nil {
+		return nil
+	}
+	return r.store.writePosting(ctx, posting)
+}
+
+// Delete exclui logicamente a fatura preenchendo DeletedAt
+func (r *InvoiceRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, err := r.activeInvoice(id)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	deleted := cloneInvoice(current)
+	deleted.UpdatedAt = deletedAt
+	deleted.DeletedAt = &deletedAt
+
+	if err := r.store.writeAudit(ctx, invoiceEntity, id, domain.AuditActionDelete, repository.NewInvoiceSnapshot(current), repository.NewInvoiceSnapshot(deleted)); err != nil {
+		return err
+	}
+
+	r.store.invoices[id] = deleted
+	return nil
+}
+
+// SummarizeActivity conta por conta as faturas não excluídas criadas em [from, to) e quantas delas foram recusadas
+func (r *InvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) ([]domain.AccountActivity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	summaries := make(map[string]*domain.AccountActivity)
+	for _, invoice := range r.store.invoices {
+		if invoice.DeletedAt != nil || invoice.CreatedAt.Before(from) || !invoice.CreatedAt.Before(to) {
+			continue
+		}
+		summary, ok := summaries[invoice.AccountID]
+		if !ok {
+			summary = &domain.AccountActivity{AccountID: invoice.AccountID}
+			summaries[invoice.AccountID] = summary
+		}
+		summary.Invoices++
+		if invoice.Status == domain.StatusRejected {
+			summary.Declined++
+		}
+	}
+
+	activity := make([]domain.AccountActivity, 0, len(summaries))
+	for _, summary := range summaries {
+		activity = append(activity, *summary)
+	}
+	return activity, nil
+}
+
+// SummarizeByStatus agrega por status as faturas não excluídas da conta criadas em [from, to)
+func (r *InvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) ([]domain.StatusTotals, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byStatus := make(map[domain.Status]*domain.StatusTotals)
+	for _, invoice := range r.store.invoices {
+		if invoice.AccountID != accountID || invoice.DeletedAt != nil || invoice.CreatedAt.Before(from) || !invoice.CreatedAt.Before(to) {
+			continue
+		}
+		total, ok := byStatus[invoice.Status]
+		if !ok {
+			total = &domain.StatusTotals{Status: invoice.Status}
+			byStatus[invoice.Status] = total
+		}
+		total.Count++
+		total.Amount += invoice.Amount
+	}
+
+	totals := make([]domain.StatusTotals, 0, len(byStatus))
+	for _, total := range byStatus {
+		totals = append(totals, *total)
+	}
+	return totals, nil
+}
+
+// SumSpending soma as faturas não recusadas e não excluídas da conta criadas desde dayStart e desde monthStart
+func (r *InvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (domain.SpendingTotals, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var totals domain.SpendingTotals
+	for _, invoice := range r.store.invoices {
+		if invoice.AccountID != accountID || invoice.DeletedAt != nil || invoice.Status == domain.StatusRejected || invoice.CreatedAt.Before(monthStart) {
+			continue
+		}
+		totals.Monthly += invoice.Amount
+		if !invoice.CreatedAt.Before(dayStart) {
+			totals.Daily += invoice.Amount
+		}
+	}
+	return totals, nil
+}
+
+// FindByPayer busca as faturas da conta do pagador, inclusive excluídas, sem diferenciar maiúsculas
+func (r *InvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) ([]*domain.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var invoices []*domain.Invoice
+	for _, invoice := range r.store.invoices {
+		if invoice.AccountID == accountID && strings.EqualFold(invoice.PayerName, payerName) {
+			invoices = append(invoices, cloneInvoice(invoice))
+		}
+	}
+	sort.Slice(invoices, func(i, j int) bool {
+		if !invoices[i].CreatedAt.Equal(invoices[j].CreatedAt) {
+			return invoices[i].CreatedAt.After(invoices[j].CreatedAt)
+		}
+		return invoices[i].ID > invoices[j].ID
+	})
+	return invoices, nil
+}
+
+// AnonymizePayer troca o nome do pagador por token e apaga os últimos dígitos do cartão nas faturas da conta,
+// inclusive excluídas, reescrevendo os snapshots da auditoria delas
+func (r *InvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	anonymized := make(map[string]bool)
+	now := time.Now()
+	for id, invoice := range r.store.invoices {
+		if invoice.AccountID != accountID || !strings.EqualFold(invoice.PayerName, payerName) {
+			continue
+		}
+		updated := cloneInvoice(invoice)
+		updated.PayerName = token
+		updated.CardLastDigits = ""
+		updated.UpdatedAt = now
+		r.store.invoices[id] = updated
+		anonymized[id] = true
+	}
+
+	for _, entry := range r.store.audit {
+		if entry.Entity != invoiceEntity || !anonymized[entry.EntityID] {
+			continue
+		}
+		oldValue, _, err := repository.AnonymizeInvoiceSnapshot(entry.OldValue, payerName, token)
+		if err != nil {
+			return 0, err
+		}
+		newValue, _, err := repository.AnonymizeInvoiceSnapshot(entry.NewValue, payerName, token)
+		if err != nil {
+			return 0, err
+		}
+		entry.OldValue, entry.NewValue = oldValue, newValue
+	}
+	return int64(len(anonymized)), nil
+}
+
+// CountDeleted conta as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, invoice := range r.store.invoices {
+		if invoice.DeletedAt != nil && invoice.DeletedAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// PurgeDeleted remove definitivamente as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var count int64
+	for id, invoice := range r.store.invoices {
+		if invoice.DeletedAt != nil && invoice.DeletedAt.Before(before) {
+			delete(r.store.invoices, id)
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// newInvoice cria uma fatura pendente da conta, sem gravar
+func newInvoice(accountID string, amount float64, createdAt time.Time) *domain.Invoice {
+	return &domain.Invoice{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		Amount:      amount,
+		Status:      domain.StatusPending,
+		Description: "Pedido",
+		PaymentType: "credit_card",
+		Metadata:    map[string]string{},
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+}
+
+func TestInvoiceRepositoryFindByFilter(t *testing.T) {
+	ctx := context.Background()
+	r := NewInvoiceRepository(NewStore())
+	now := time.Now()
+
+	small := newInvoice("a", 10, now.Add(-3*time.Hour))
+	approved := newInvoice("a", 50, now.Add(-2*time.Hour))
+	approved.Status = domain.StatusApproved
+	tagged := newInvoice("a", 100, now.Add(-time.Hour))
+	tagged.Metadata = map[string]string{"order_id": "123"}
+	deleted := newInvoice("a", 70, now)
+	other := newInvoice("b", 20, now)
+	for _, invoice := range []*domain.Invoice{small, approved, tagged, deleted, other} {
+		if err := r.Save(ctx, invoice, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := r.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.InvoiceFilter
+		want   []*domain.Invoice
+	}{
+		{"account, newest first", domain.InvoiceFilter{AccountID: "a"}, []*domain.Invoice{tagged, approved, small}},
+		{"status", domain.InvoiceFilter{AccountID: "a", Statuses: []domain.Status{domain.StatusApproved}}, []*domain.Invoice{approved}},
+		{"amount range", domain.InvoiceFilter{AccountID: "a", MinAmount: 20, MaxAmount: 60}, []*domain.Invoice{approved}},
+		{"created range", domain.InvoiceFilter{AccountID: "a", CreatedFrom: now.Add(-150 * time.Minute), CreatedTo: now.Add(-30 * time.Minute)}, []*domain.Invoice{tagged, approved}},
+		{"metadata", domain.InvoiceFilter{Metadata: map[string]string{"order_id": "123"}}, []*domain.Invoice{tagged}},
+		{"include deleted", domain.InvoiceFilter{AccountID: "a", IncludeDeleted: true}, []*domain.Invoice{deleted, tagged, approved, small}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.FindByFilter(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("FindByFilter: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindByFilter returned %d invoices, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i].ID {
+					t.Errorf("invoice %d = %q, want %q", i, got[i].ID, tt.want[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestInvoiceRepositoryUpdateStatusAndDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	r := NewInvoiceRepository(store)
+	invoice := newInvoice("a", 10, time.Now())
+	if err := r.Save(ctx, invoice, nil, nil); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	invoice.Status = domain.StatusApproved
+	if err := r.UpdateStatus(ctx, invoice, nil); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	found, err := r.FindByID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Status != domain.StatusApproved {
+		t.Errorf("Status = %q, want %q", found.Status, domain.StatusApproved)
+	}
+
+	if err := r.Delete(ctx, invoice.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.FindByID(ctx, invoice.ID); !errors.Is(err, domain.ErrInvoiceNotFound) {
+		t.Errorf("FindByID error = %v, want ErrInvoiceNotFound", err)
+	}
+	if err := r.UpdateStatus(ctx, invoice, nil); !errors.Is(err, domain.ErrInvoiceNotFound) {
+		t.Errorf("UpdateStatus error = %v, want ErrInvoiceNotFound", err)
+	}
+
+	// Cada mutação deixa uma entrada na auditoria, das mais recentes para as mais antigas
+	entries, err := NewAuditRepository(store).FindByFilter(ctx, domain.AuditFilter{Entity: invoiceEntity, EntityID: invoice.ID})
+	if err != nil {
+		t.Fatalf("audit FindByFilter: %v", err)
+	}
+	want := []domain.AuditAction{domain.AuditActionDelete, domain.AuditActionUpdate, domain.AuditActionInsert}
+	if len(entries) != len(want) {
+		t.Fatalf("audit returned %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if entry.Action != want[i] {
+			t.Errorf("audit entry %d action = %q, want %q", i, entry.Action, want[i])
+		}
+	}
+}
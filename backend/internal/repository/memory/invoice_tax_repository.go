@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// InvoiceTaxRepository implementa domain.InvoiceTaxRepository em memória
+type InvoiceTaxRepository struct {
+	store *Store
+}
+
+// NewInvoiceTaxRepository cria um repositório dos impostos das faturas sobre o armazenamento informado
+func NewInvoiceTaxRepository(store *Store) *InvoiceTaxRepository {
+	return &InvoiceTaxRepository{store: store}
+}
+
+// SaveBatch grava os impostos de uma ou mais faturas
+func (r *InvoiceTaxRepository) SaveBatch(ctx context.Context, taxes []*domain.InvoiceTax) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, tax := range taxes {
+		clone := *tax
+		r.store.invoiceTaxes[tax.InvoiceID] = append(r.store.invoiceTaxes[tax.InvoiceID], &clone)
+	}
+	return nil
+}
+
+// FindByInvoiceID retorna os impostos da fatura, ordenados pela linha e pelo nome, ou nenhum se ela não tem impostos
+func (r *InvoiceTaxRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceTax, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var taxes []*domain.InvoiceTax
+	for _, tax := range r.store.invoiceTaxes[invoiceID] {
+		clone := *tax
+		taxes = append(taxes, &clone)
+	}
+	sort.Slice(taxes, func(i, j int) bool {
+		if taxes[i].Line != taxes[j].Line {
+			return taxes[i].Line < taxes[j].Line
+		}
+		return taxes[i].Name < taxes[j].Name
+	})
+	return taxes, nil
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+// LedgerRepository implementa domain.LedgerRepository em memória
+type LedgerRepository struct {
+	store *Store
+}
+
+// NewLedgerRepository cria um repositório do razão sobre o armazenamento informado
+func NewLedgerRepository(store *Store) *LedgerRepository {
+	return &LedgerRepository{store: store}
+}
+
+// CreateBatch grava os lançamentos
+func (r *LedgerRepository) CreateBatch(ctx context.Context, entries []*domain.LedgerEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, entry := range entries {
+		clone := *entry
+		r.store.ledger = append(r.store.ledger, &clone)
+	}
+	return nil
+}
+
+// Post grava o movimento com o lock de escrita
+// Retorna ErrAccountNotFound se uma conta de Balances não existir, ErrEscrowAlreadyReleased se uma retenção de
+// Releases já tiver sido liberada e ErrInsufficientBalance se um débito Funded não couber no saldo
+func (r *LedgerRepository) Post(ctx context.Context, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.post(ctx, posting)
+}
+
+// post grava o movimento conferindo as contas e as retenções liberadas antes de qualquer gravação; deve ser chamado
+// com o lock de escrita
+func (s *Store) post(ctx context.Context, posting *domain.Posting) error {
+	if err := s.checkPosting(posting); err != nil {
+		return err
+	}
+	return s.writePosting(ctx, posting)
+}
+
+// checkPosting confere as contas e as retenções liberadas do movimento, sem gravar nada; deve ser chamado com o lock
+func (s *Store) checkPosting(posting *domain.Posting) error {
+	for _, accountID := range posting.AccountIDs() {
+		account, ok := s.accounts[accountID]
+		if !ok || account.DeletedAt != nil {
+			return domain.ErrAccountNotFound
+		}
+		if !posting.Covered(accountID, account.Balance) {
+			return domain.ErrInsufficientBalance
+		}
+	}
+	for _, hold := range posting.Releases {
+		if current, ok := s.escrow[hold.ID]; !ok || current.Status != domain.EscrowHeld {
+			return domain.ErrEscrowAlreadyReleased
+		}
+	}
+	return nil
+}
+
+// writePosting grava o movimento já conferido por checkPosting e liquida as faturas de Settles que ficarem sem
+// retenção retida; deve ser chamado com o lock de escrita
+func (s *Store) writePosting(ctx context.Context, posting *domain.Posting) error {
+	for _, accountID := range posting.AccountIDs() {
+		current := s.accounts[accountID]
+		updated := cloneAccount(current)
+		updated.Balance += posting.Balances[accountID]
+		updated.UpdatedAt = time.Now()
+		if err := s.writeAudit(ctx, accountEntity, accountID, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), repository.NewAccountSnapshot(updated)); err != nil {
+			return err
+		}
+		s.accounts[accountID] = updated
+	}
+	for _, entry := range posting.Entries {
+		clone := *entry
+		s.ledger = append(s.ledger, &clone)
+	}
+	for _, hold := range posting.Holds {
+		s.escrow[hold.ID] = cloneEscrowHold(hold)
+	}
+	for _, hold := range posting.Releases {
+		s.escrow[hold.ID] = cloneEscrowHold(hold)
+	}
+	for _, invoiceID := range posting.Settles() {
+		if err := s.settleInvoice(ctx, invoiceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleInvoice passa a fatura para StatusSettled se ela estiver aprovada e sem retenção retida; deve ser chamado com
+// o lock de escrita
+func (s *Store) settleInvoice(ctx context.Context, invoiceID string) error {
+	invoice, ok := s.invoices[invoiceID]
+	if !ok || invoice.DeletedAt != nil || !invoice.Status.CanTransitionTo(domain.StatusSettled) {
+		return nil
+	}
+	for _, hold := range s.escrow {
+		if hold.InvoiceID == invoiceID && hold.Status == domain.EscrowHeld {
+			return nil
+		}
+	}
+
+	updated := cloneInvoice(invoice)
+	updated.Status = domain.StatusSettled
+	updated.UpdatedAt = time.Now()
+	if err := s.writeAudit(ctx, invoiceEntity, invoiceID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(invoice), repository.NewInvoiceSnapshot(updated)); err != nil {
+		return err
+	}
+	s.invoices[invoiceID] = updated
+	return nil
+}
+
+// List retorna os lançamentos da conta criados em [from, to), dos mais antigos para os mais novos
+func (r *LedgerRepository) List(ctx context.Context, accountID string, from, to time.Time) ([]*domain.LedgerEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []*domain.LedgerEntry
+	for _, entry := range r.store.ledger {
+		if entry.AccountID == accountID && !entry.CreatedAt.Before(from) && entry.CreatedAt.Before(to) {
+			clone := *entry
+			entries = append(entries, &clone)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// SumByType soma os lançamentos da conta por tipo; os tipos sem lançamentos ficam fora do mapa
+func (r *LedgerRepository) SumByType(ctx context.Context, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.sumLedgerByType(accountID), nil
+}
+
+// sumLedgerByType soma os lançamentos da conta por tipo; deve ser chamado com o lock
+func (s *Store) sumLedgerByType(accountID string) map[domain.LedgerEntryType]float64 {
+	sums := make(map[domain.LedgerEntryType]float64)
+	for _, entry := range s.ledger {
+		if entry.AccountID == accountID {
+			sums[entry.Type] += entry.Amount
+		}
+	}
+	return sums
+}
+
+// Reconcile recalcula o saldo da conta e grava o movimento com o lock de escrita
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *LedgerRepository) Reconcile(ctx context.Context, accountID string, write, correct bool) (*domain.BalanceReconciliation, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	account, ok := r.store.accounts[accountID]
+	if !ok || account.DeletedAt != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	reconciliation := domain.NewBalanceReconciliation(account, r.store.sumLedgerByType(accountID))
+	if !write {
+		return reconciliation, nil
+	}
+	if posting := reconciliation.Settle(correct); posting != nil {
+		if err := r.store.post(ctx, posting); err != nil {
+			return nil, err
+		}
+	}
+	return reconciliation, nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PayoutScheduleRepository implementa domain.PayoutScheduleRepository em memória
+type PayoutScheduleRepository struct {
+	store *Store
+}
+
+// NewPayoutScheduleRepository cria um repositório de prazos de repasse sobre o armazenamento informado
+func NewPayoutScheduleRepository(store *Store) *PayoutScheduleRepository {
+	return &PayoutScheduleRepository{store: store}
+}
+
+// Save substitui o prazo da conta
+func (r *PayoutScheduleRepository) Save(ctx context.Context, schedule *domain.PayoutSchedule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *schedule
+	r.store.payoutSchedules[schedule.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca o prazo da conta
+func (r *PayoutScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PayoutSchedule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	schedule, ok := r.store.payoutSchedules[accountID]
+	if !ok {
+		return nil, domain.ErrPayoutScheduleNotFound
+	}
+	clone := *schedule
+	return &clone, nil
+}
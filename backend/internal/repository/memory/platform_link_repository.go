@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PlatformLinkRepository implementa domain.PlatformLinkRepository em memória
+type PlatformLinkRepository struct {
+	store *Store
+}
+
+// NewPlatformLinkRepository cria um repositório de ligações com plataformas sobre o armazenamento informado
+func NewPlatformLinkRepository(store *Store) *PlatformLinkRepository {
+	return &PlatformLinkRepository{store: store}
+}
+
+// Save substitui a ligação da conta
+func (r *PlatformLinkRepository) Save(ctx context.Context, link *domain.PlatformLink) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *link
+	r.store.platformLinks[link.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca a ligação da conta
+func (r *PlatformLinkRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PlatformLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	link, ok := r.store.platformLinks[accountID]
+	if !ok {
+		return nil, domain.ErrPlatformLinkNotFound
+	}
+	clone := *link
+	return &clone, nil
+}
+
+// CountByPlatformID retorna quantas contas são filhas da plataforma
+func (r *PlatformLinkRepository) CountByPlatformID(ctx context.Context, platformID string) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, link := range r.store.platformLinks {
+		if link.PlatformID == platformID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete remove a ligação da conta
+func (r *PlatformLinkRepository) Delete(ctx context.Context, accountID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.platformLinks[accountID]; !ok {
+		return domain.ErrPlatformLinkNotFound
+	}
+	delete(r.store.platformLinks, accountID)
+	return nil
+}
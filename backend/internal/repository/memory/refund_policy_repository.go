@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RefundPolicyRepository implementa domain.RefundPolicyRepository em memória
+type RefundPolicyRepository struct {
+	store *Store
+}
+
+// NewRefundPolicyRepository cria um repositório de políticas de reembolso sobre o armazenamento informado
+func NewRefundPolicyRepository(store *Store) *RefundPolicyRepository {
+	return &RefundPolicyRepository{store: store}
+}
+
+// Save substitui a política da conta
+func (r *RefundPolicyRepository) Save(ctx context.Context, policy *domain.RefundPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *policy
+	r.store.refundPolicies[policy.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca a política da conta
+func (r *RefundPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RefundPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	policy, ok := r.store.refundPolicies[accountID]
+	if !ok {
+		return nil, domain.ErrRefundPolicyNotFound
+	}
+	clone := *policy
+	return &clone, nil
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+// RefundRepository implementa domain.RefundRepository em memória
+type RefundRepository struct {
+	store *Store
+}
+
+// NewRefundRepository cria um repositório de reembolsos sobre o armazenamento informado
+func NewRefundRepository(store *Store) *RefundRepository {
+	return &RefundRepository{store: store}
+}
+
+func cloneRefund(refund *domain.Refund) *domain.Refund {
+	clone := *refund
+	return &clone
+}
+
+// Create confere o limite da fatura e grava o reembolso e, se posting não for nil, o movimento do débito com o lock
+// de escrita
+// Retorna ErrInvoiceNotFound se a fatura não existir e os erros de Refund.CheckRefundable
+func (r *RefundRepository) Create(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice, ok := r.store.invoices[refund.InvoiceID]
+	if !ok || invoice.DeletedAt != nil {
+		return domain.ErrInvoiceNotFound
+	}
+	if err := refund.CheckRefundable(invoice, r.sum(refund.InvoiceID, domain.RefundCompleted, domain.RefundPending)); err != nil {
+		return err
+	}
+	// O movimento vem antes do reembolso para que uma conta inexistente não deixe nada gravado
+	if err := r.post(ctx, refund, posting); err != nil {
+		return err
+	}
+	r.store.refunds[refund.ID] = cloneRefund(refund)
+	return r.markRefunded(ctx, invoice, refund)
+}
+
+// FindByID busca o reembolso pelo ID
+func (r *RefundRepository) FindByID(ctx context.Context, id string) (*domain.Refund, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	refund, ok := r.store.refunds[id]
+	if !ok {
+		return nil, domain.ErrRefundNotFound
+	}
+	return cloneRefund(refund), nil
+}
+
+// List retorna até limit reembolsos da conta na situação informada, ou em todas com status vazio, dos mais novos
+// para os mais antigos
+func (r *RefundRepository) List(ctx context.Context, accountID string, status domain.RefundStatus, limit int) ([]*domain.Refund, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var refunds []*domain.Refund
+	for _, refund := range r.store.refunds {
+		if refund.AccountID == accountID && (status == "" || refund.Status == status) {
+			refunds = append(refunds, cloneRefund(refund))
+		}
+	}
+	sort.Slice(refunds, func(i, j int) bool {
+		if !refunds[i].CreatedAt.Equal(refunds[j].CreatedAt) {
+			return refunds[i].CreatedAt.After(refunds[j].CreatedAt)
+		}
+		return refunds[i].ID < refunds[j].ID
+	})
+	if len(refunds) > limit {
+		refunds = refunds[:limit]
+	}
+	return refunds, nil
+}
+
+// ListExpired retorna até limit reembolsos pendentes com prazo até before, dos que venceram primeiro
+func (r *RefundRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.Refund, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var refunds []*domain.Refund
+	for _, refund := range r.store.refunds {
+		if refund.Status == domain.RefundPending && refund.ExpiresAt != nil && !refund.ExpiresAt.After(before) {
+			refunds = append(refunds, cloneRefund(refund))
+		}
+	}
+	sort.Slice(refunds, func(i, j int) bool {
+		if !refunds[i].ExpiresAt.Equal(*refunds[j].ExpiresAt) {
+			return refunds[i].ExpiresAt.Before(*refunds[j].ExpiresAt)
+		}
+		return refunds[i].ID < refunds[j].ID
+	})
+	if len(refunds) > limit {
+		refunds = refunds[:limit]
+	}
+	return refunds, nil
+}
+
+// Decide grava a decisão se o reembolso ainda estiver pendente e, se posting não for nil, o movimento do débito com
+// o lock de escrita
+// Retorna ErrRefundAlreadyDecided quando outra decisão chegou antes e, na aprovação, ErrInvalidStatus se a fatura
+// não estiver mais aprovada
+func (r *RefundRepository) Decide(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.refunds[refund.ID]
+	if !ok {
+		return domain.ErrRefundNotFound
+	}
+	if current.Status != domain.RefundPending {
+		return domain.ErrRefundAlreadyDecided
+	}
+	var invoice *domain.Invoice
+	if refund.Status == domain.RefundCompleted {
+		invoice, ok = r.store.invoices[refund.InvoiceID]
+		if !ok || invoice.DeletedAt != nil {
+			return domain.ErrInvoiceNotFound
+		}
+		if !invoice.Status.IsPaid() {
+			return domain.ErrInvalidStatus
+		}
+		if err := r.post(ctx, refund, posting); err != nil {
+			return err
+		}
+	}
+	r.store.refunds[refund.ID] = cloneRefund(refund)
+	if invoice == nil {
+		return nil
+	}
+	return r.markRefunded(ctx, invoice, refund)
+}
+
+// SumByInvoiceID soma os reembolsos concluídos e pendentes da fatura
+func (r *RefundRepository) SumByInvoiceID(ctx context.Context, invoiceID string) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.sum(invoiceID, domain.RefundCompleted, domain.RefundPending), nil
+}
+
+// sum soma os reembolsos da fatura nas situações informadas; deve ser chamado com o lock
+func (r *RefundRepository) sum(invoiceID string, statuses ...domain.RefundStatus) float64 {
+	var total float64
+	for _, refund := range r.store.refunds {
+		if refund.InvoiceID == invoiceID && slices.Contains(statuses, refund.Status) {
+			total += refund.Amount
+		}
+	}
+	return total
+}
+
+// post grava o movimento do débito de um reembolso concluído; deve ser chamado com o lock de escrita
+func (r *RefundRepository) post(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	if refund.Status != domain.RefundCompleted || posting == nil {
+		return nil
+	}
+	return r.store.post(ctx, posting)
+}
+
+// markRefunded passa para StatusRefunded, registrando o estado anterior na auditoria, a fatura cujos reembolsos
+// concluídos já cobrem todo o valor; deve ser chamado com o lock de escrita depois de gravar o reembolso
+func (r *RefundRepository) markRefunded(ctx context.Context, invoice *domain.Invoice, refund *domain.Refund) error {
+	if refund.Status != domain.RefundCompleted || !domain.FullyRefunded(invoice, r.sum(invoice.ID, domain.RefundCompleted)) {
+		return nil
+	}
+	updated := cloneInvoice(invoice)
+	updated.Status = domain.StatusRefunded
+	updated.UpdatedAt = time.Now()
+	if err := r.store.writeAudit(ctx, invoiceEntity, invoice.ID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(invoice), repository.NewInvoiceSnapshot(updated)); err != nil {
+		return err
+	}
+	r.store.invoices[invoice.ID] = updated
+	return nil
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RefundWindowRepository implementa domain.AccountRefundWindowRepository em memória
+type RefundWindowRepository struct {
+	store *Store
+}
+
+// NewRefundWindowRepository cria um repositório de prazos de reembolso sobre o armazenamento informado
+func NewRefundWindowRepository(store *Store) *RefundWindowRepository {
+	return &RefundWindowRepository{store: store}
+}
+
+// Save substitui o prazo da conta
+func (r *RefundWindowRepository) Save(ctx context.Context, window *domain.AccountRefundWindow) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *window
+	r.store.refundWindows[window.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca o prazo da conta
+func (r *RefundWindowRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountRefundWindow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	window, ok := r.store.refundWindows[accountID]
+	if !ok {
+		return nil, domain.ErrRefundWindowNotFound
+	}
+	clone := *window
+	return &clone, nil
+}
+
+// Delete remove o prazo da conta
+func (r *RefundWindowRepository) Delete(ctx context.Context, accountID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.refundWindows, accountID)
+	return nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// ReviewRepository implementa domain.ReviewRepository em memória
+type ReviewRepository struct {
+	store *Store
+}
+
+// NewReviewRepository cria um repositório da fila de revisão sobre o armazenamento informado
+func NewReviewRepository(store *Store) *ReviewRepository {
+	return &ReviewRepository{store: store}
+}
+
+func cloneInvoiceReview(review *domain.InvoiceReview) *domain.InvoiceReview {
+	clone := *review
+	return &clone
+}
+
+// Create abre a revisão da fatura
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.InvoiceReview) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reviews[review.InvoiceID]; ok {
+		return domain.ErrReviewExists
+	}
+	r.store.reviews[review.InvoiceID] = cloneInvoiceReview(review)
+	return nil
+}
+
+// FindByInvoiceID busca a revisão da fatura
+func (r *ReviewRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (*domain.InvoiceReview, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	review, ok := r.store.reviews[invoiceID]
+	if !ok {
+		return nil, domain.ErrReviewNotFound
+	}
+	return cloneInvoiceReview(review), nil
+}
+
+// List retorna até limit revisões da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais antigas para as mais novas
+func (r *ReviewRepository) List(ctx context.Context, accountID string, status domain.ReviewStatus, limit int) ([]*domain.InvoiceReview, error) {
+	return r.filter(limit, func(review *domain.InvoiceReview) bool {
+		return (accountID == "" || review.AccountID == accountID) && (status == "" || review.Status == status)
+	}, func(review *domain.InvoiceReview) time.Time {
+		return review.CreatedAt
+	}), nil
+}
+
+// ListDue retorna até limit revisões abertas com prazo até before, das que venceram primeiro
+func (r *ReviewRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.InvoiceReview, error) {
+	return r.filter(limit, func(review *domain.InvoiceReview) bool {
+		return review.Status == domain.ReviewOpen && !review.DueAt.After(before)
+	}, func(review *domain.InvoiceReview) time.Time {
+		return review.DueAt
+	}), nil
+}
+
+// Decide grava a decisão se a revisão ainda estiver aberta
+func (r *ReviewRepository) Decide(ctx context.Context, review *domain.InvoiceReview) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.reviews[review.InvoiceID]
+	if !ok {
+		return domain.ErrReviewNotFound
+	}
+	if current.Status != domain.ReviewOpen {
+		return domain.ErrReviewAlreadyDecided
+	}
+	r.store.reviews[review.InvoiceID] = cloneInvoiceReview(review)
+	return nil
+}
+
+// filter retorna até limit revisões que passam em match, ordenadas por orderBy e pela fatura
+func (r *ReviewRepository) filter(limit int, match func(*domain.InvoiceReview) bool, orderBy func(*domain.InvoiceReview) time.Time) []*domain.InvoiceReview {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reviews []*domain.InvoiceReview
+	for _, review := range r.store.reviews {
+		if match(review) {
+			reviews = append(reviews, cloneInvoiceReview(review))
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if a, b := orderBy(reviews[i]), orderBy(reviews[j]); !a.Equal(b) {
+			return a.Before(b)
+		}
+		return reviews[i].InvoiceID < reviews[j].InvoiceID
+	})
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	return reviews
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RiskPolicyRepository implementa domain.RiskPolicyRepository em memória
+type RiskPolicyRepository struct {
+	store *Store
+}
+
+// NewRiskPolicyRepository cria um repositório de limites de score sobre o armazenamento informado
+func NewRiskPolicyRepository(store *Store) *RiskPolicyRepository {
+	return &RiskPolicyRepository{store: store}
+}
+
+// Save substitui os limites da conta
+func (r *RiskPolicyRepository) Save(ctx context.Context, policy *domain.RiskPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *policy
+	r.store.riskPolicies[policy.AccountID] = &clone
+	return nil
+}
+
+// FindByAccountID busca os limites da conta
+func (r *RiskPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RiskPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	policy, ok := r.store.riskPolicies[accountID]
+	if !ok {
+		return nil, domain.ErrRiskPolicyNotFound
+	}
+	clone := *policy
+	return &clone, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SecurityWebhookRepository implementa domain.SecurityWebhookRepository em memória
+type SecurityWebhookRepository struct {
+	store *Store
+}
+
+// NewSecurityWebhookRepository cria um repositório de assinaturas de segurança sobre o armazenamento informado
+func NewSecurityWebhookRepository(store *Store) *SecurityWebhookRepository {
+	return &SecurityWebhookRepository{store: store}
+}
+
+func cloneSecurityWebhook(webhook *domain.SecurityWebhook) *domain.SecurityWebhook {
+	clone := *webhook
+	clone.Events = slices.Clone(webhook.Events)
+	return &clone
+}
+
+// Save substitui a assinatura da conta
+func (r *SecurityWebhookRepository) Save(ctx context.Context, webhook *domain.SecurityWebhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.securityWebhooks[webhook.AccountID] = cloneSecurityWebhook(webhook)
+	return nil
+}
+
+// FindByAccountID busca a assinatura da conta
+func (r *SecurityWebhookRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.SecurityWebhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhook, ok := r.store.securityWebhooks[accountID]
+	if !ok {
+		return nil, domain.ErrSecurityWebhookNotFound
+	}
+	return cloneSecurityWebhook(webhook), nil
+}
+
+// Delete remove a assinatura da conta
+func (r *SecurityWebhookRepository) Delete(ctx context.Context, accountID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.securityWebhooks[accountID]; !ok {
+		return domain.ErrSecurityWebhookNotFound
+	}
+	delete(r.store.securityWebhooks, accountID)
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SplitRepository implementa domain.SplitRepository em memória
+type SplitRepository struct {
+	store *Store
+}
+
+// NewSplitRepository cria um repositório das partes das faturas sobre o armazenamento informado
+func NewSplitRepository(store *Store) *SplitRepository {
+	return &SplitRepository{store: store}
+}
+
+// SaveBatch grava as partes de uma ou mais faturas
+func (r *SplitRepository) SaveBatch(ctx context.Context, splits []*domain.InvoiceSplit) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.saveSplits(splits)
+	return nil
+}
+
+// saveSplits guarda cópias das partes; deve ser chamado com o lock de escrita
+func (s *Store) saveSplits(splits []*domain.InvoiceSplit) {
+	for _, split := range splits {
+		clone := *split
+		s.splits[split.InvoiceID] = append(s.splits[split.InvoiceID], &clone)
+	}
+}
+
+// FindByInvoiceID retorna as partes da fatura, ordenadas pela conta recebedora, ou nenhuma se ela não foi dividida
+func (r *SplitRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceSplit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var splits []*domain.InvoiceSplit
+	for _, split := range r.store.splits[invoiceID] {
+		clone := *split
+		splits = append(splits, &clone)
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		return splits[i].RecipientID < splits[j].RecipientID
+	})
+	return splits, nil
+}
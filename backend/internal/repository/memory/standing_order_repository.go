@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// StandingOrderRepository implementa domain.StandingOrderRepository em memória
+type StandingOrderRepository struct {
+	store *Store
+}
+
+// NewStandingOrderRepository cria um repositório de ordens permanentes sobre o armazenamento informado
+func NewStandingOrderRepository(store *Store) *StandingOrderRepository {
+	return &StandingOrderRepository{store: store}
+}
+
+func cloneStandingOrder(order *domain.StandingOrder) *domain.StandingOrder {
+	clone := *order
+	return &clone
+}
+
+// Create grava a ordem
+func (r *StandingOrderRepository) Create(ctx context.Context, order *domain.StandingOrder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.standingOrders[order.ID] = cloneStandingOrder(order)
+	return nil
+}
+
+// FindByID busca a ordem pelo ID
+func (r *StandingOrderRepository) FindByID(ctx context.Context, id string) (*domain.StandingOrder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, ok := r.store.standingOrders[id]
+	if !ok {
+		return nil, domain.ErrStandingOrderNotFound
+	}
+	return cloneStandingOrder(order), nil
+}
+
+// List retorna até limit ordens da conta na situação informada, ou em todas com status vazio, das mais novas para
+// as mais antigas
+func (r *StandingOrderRepository) List(ctx context.Context, accountID string, status domain.StandingOrderStatus, limit int) ([]*domain.StandingOrder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []*domain.StandingOrder
+	for _, order := range r.store.standingOrders {
+		if order.AccountID == accountID && (status == "" || order.Status == status) {
+			orders = append(orders, cloneStandingOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// ListDue retorna até limit ordens ativas com transferência até before, das mais atrasadas primeiro
+func (r *StandingOrderRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.StandingOrder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []*domain.StandingOrder
+	for _, order := range r.store.standingOrders {
+		if order.Status == domain.StandingOrderActive && order.NextRunAt != nil && !order.NextRunAt.After(before) {
+			orders = append(orders, cloneStandingOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].NextRunAt.Equal(*orders[j].NextRunAt) {
+			return orders[i].NextRunAt.Before(*orders[j].NextRunAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// Update grava a ordem se a situação gravada ainda for from, com o lock de escrita
+func (r *StandingOrderRepository) Update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkStatus(order.ID, from); err != nil {
+		return err
+	}
+	r.store.standingOrders[order.ID] = cloneStandingOrder(order)
+	return nil
+}
+
+// Run executa a ordem ativa sobre o saldo da conta e grava a execução junto com o movimento, com o lock de escrita
+func (r *StandingOrderRepository) Run(ctx context.Context, order *domain.StandingOrder, now time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkStatus(order.ID, domain.StandingOrderActive); err != nil {
+		return err
+	}
+	account, ok := r.store.accounts[order.AccountID]
+	if !ok || account.DeletedAt != nil {
+		return domain.ErrAccountNotFound
+	}
+	run := cloneStandingOrder(order)
+	if posting := run.Run(account.Balance, now); posting != nil {
+		if err := r.store.post(ctx, posting); err != nil {
+			return err
+		}
+	}
+	r.store.standingOrders[order.ID] = run
+	*order = *run
+	return nil
+}
+
+// checkStatus confere a situação gravada da ordem; deve ser chamado com o lock
+func (r *StandingOrderRepository) checkStatus(id string, from domain.StandingOrderStatus) error {
+	current, ok := r.store.standingOrders[id]
+	if !ok {
+		return domain.ErrStandingOrderNotFound
+	}
+	if current.Status != from {
+		return domain.ErrStandingOrderChanged
+	}
+	return nil
+}
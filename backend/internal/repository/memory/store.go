@@ -0,0 +1,183 @@
+// Package memory implementa os repositórios do domínio em memória, para desenvolvimento local e testes
+// Os dados são perdidos quando o processo termina
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redact"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// Store guarda contas, faturas, usuários, a trilha de auditoria, os eventos de autenticação, o cofre de cartões,
+// os segundos fatores, os pedidos de titulares, as exportações, as assinaturas de segurança, as regras de alerta
+// e as listas de bloqueio compartilhados pelos repositórios em memória
+// Todas as operações são serializadas pelo mutex, equivalente a transações no Postgres
+type Store struct {
+	mu                  sync.RWMutex
+	accounts            map[string]*domain.Account
+	invoices            map[string]*domain.Invoice
+	users               map[string]*domain.User
+	tokens              map[string]*domain.RefreshToken
+	audit               []*domain.AuditEntry
+	nextAuditID         int64
+	authEvents          []*domain.AuthEvent
+	cards               map[string]*carddata.Record
+	twoFactors          map[string]*domain.TwoFactor
+	geoPolicies         map[string]*domain.GeoPolicy
+	dataSubjectRequests []*domain.DataSubjectRequest
+	exports             map[string]*domain.Export
+	securityWebhooks    map[string]*domain.SecurityWebhook
+	alertRules          map[string]*domain.AlertRule
+	blocklist           map[string]*domain.BlocklistEntry
+	riskPolicies        map[string]*domain.RiskPolicy
+	reviews             map[string]*domain.InvoiceReview
+	splits              map[string][]*domain.InvoiceSplit
+	platformLinks       map[string]*domain.PlatformLink
+	ledger              []*domain.LedgerEntry
+	escrow              map[string]*domain.EscrowHold
+	refundPolicies      map[string]*domain.RefundPolicy
+	refunds             map[string]*domain.Refund
+	disputes            map[string]*domain.Dispute
+	disputeEvidence     []*domain.DisputeEvidence
+	feeSchedules        map[string]*domain.FeeSchedule
+	subscriptions       map[string]*domain.Subscription
+	coupons             map[string]*domain.Coupon
+	couponRedemptions   []*domain.CouponRedemption
+	invoiceTaxes        map[string][]*domain.InvoiceTax
+	invoiceItems        map[string][]*domain.InvoiceItem
+	customers           map[string]*domain.Customer
+	paymentMethods      map[string][]*domain.CustomerPaymentMethod
+	payoutSchedules     map[string]*domain.PayoutSchedule
+	anticipations       []*domain.Anticipation
+	tenants             map[string]*domain.Tenant
+	amountLimits        map[string]*domain.AccountAmountLimits
+	standingOrders      map[string]*domain.StandingOrder
+	refundWindows       map[string]*domain.AccountRefundWindow
+}
+
+// NewStore cria um armazenamento em memória vazio
+func NewStore() *Store {
+	return &Store{
+		accounts:         make(map[string]*domain.Account),
+		invoices:         make(map[string]*domain.Invoice),
+		users:            make(map[string]*domain.User),
+		tokens:           make(map[string]*domain.RefreshToken),
+		cards:            make(map[string]*carddata.Record),
+		twoFactors:       make(map[string]*domain.TwoFactor),
+		geoPolicies:      make(map[string]*domain.GeoPolicy),
+		exports:          make(map[string]*domain.Export),
+		securityWebhooks: make(map[string]*domain.SecurityWebhook),
+		alertRules:       make(map[string]*domain.AlertRule),
+		blocklist:        make(map[string]*domain.BlocklistEntry),
+		riskPolicies:     make(map[string]*domain.RiskPolicy),
+		reviews:          make(map[string]*domain.InvoiceReview),
+		splits:           make(map[string][]*domain.InvoiceSplit),
+		platformLinks:    make(map[string]*domain.PlatformLink),
+		escrow:           make(map[string]*domain.EscrowHold),
+		refundPolicies:   make(map[string]*domain.RefundPolicy),
+		refunds:          make(map[string]*domain.Refund),
+		disputes:         make(map[string]*domain.Dispute),
+		feeSchedules:     make(map[string]*domain.FeeSchedule),
+		subscriptions:    make(map[string]*domain.Subscription),
+		coupons:          make(map[string]*domain.Coupon),
+		invoiceTaxes:     make(map[string][]*domain.InvoiceTax),
+		invoiceItems:     make(map[string][]*domain.InvoiceItem),
+		customers:        make(map[string]*domain.Customer),
+		paymentMethods:   make(map[string][]*domain.CustomerPaymentMethod),
+		payoutSchedules:  make(map[string]*domain.PayoutSchedule),
+		tenants:          make(map[string]*domain.Tenant),
+		amountLimits:     make(map[string]*domain.AccountAmountLimits),
+		standingOrders:   make(map[string]*domain.StandingOrder),
+		refundWindows:    make(map[string]*domain.AccountRefundWindow),
+	}
+}
+
+// writeAudit registra a mutação na trilha de auditoria; deve ser chamado com o lock de escrita
+func (s *Store) writeAudit(ctx context.Context, entity, entityID string, action domain.AuditAction, oldValue, newValue any) error {
+	oldJSON, err := marshalAuditValue(oldValue)
+	if err != nil {
+		return err
+	}
+	newJSON, err := marshalAuditValue(newValue)
+	if err != nil {
+		return err
+	}
+
+	s.nextAuditID++
+	s.audit = append(s.audit, &domain.AuditEntry{
+		ID:        s.nextAuditID,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		OldValue:  oldJSON,
+		NewValue:  newJSON,
+		Actor:     requestctx.Actor(ctx),
+		RequestID: requestctx.RequestID(ctx),
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// marshalAuditValue serializa o snapshot com os dados sensíveis mascarados; valores nil ficam vazios
+func marshalAuditValue(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return redact.JSON(data), nil
+}
+
+// cloneAccount copia a conta para que chamadores não alterem o estado armazenado
+func cloneAccount(account *domain.Account) *domain.Account {
+	return &domain.Account{
+		ID:        account.ID,
+		Name:      account.Name,
+		Email:     account.Email,
+		APIKey:    account.APIKey,
+		Role:      account.Role,
+		Scopes:    slices.Clone(account.Scopes),
+		TenantID:  account.TenantID,
+		Balance:   account.Balance,
+		CreatedAt: account.CreatedAt,
+		UpdatedAt: account.UpdatedAt,
+		DeletedAt: cloneTime(account.DeletedAt),
+	}
+}
+
+// cloneInvoice copia a fatura, incluindo os metadados
+func cloneInvoice(invoice *domain.Invoice) *domain.Invoice {
+	clone := *invoice
+	clone.Metadata = maps.Clone(invoice.Metadata)
+	if clone.Metadata == nil {
+		clone.Metadata = map[string]string{}
+	}
+	clone.DeletedAt = cloneTime(invoice.DeletedAt)
+	return &clone
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// after indica se a posição (aTime, aID) vem antes de (bTime, bID) na ordenação (created_at DESC, id DESC)
+func after(aTime time.Time, aID string, bTime time.Time, bID string) bool {
+	if !aTime.Equal(bTime) {
+		return aTime.After(bTime)
+	}
+	return aID > bID
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SubscriptionRepository implementa domain.SubscriptionRepository em memória
+type SubscriptionRepository struct {
+	store *Store
+}
+
+// NewSubscriptionRepository cria um repositório de assinaturas sobre o armazenamento informado
+func NewSubscriptionRepository(store *Store) *SubscriptionRepository {
+	return &SubscriptionRepository{store: store}
+}
+
+func cloneSubscription(subscription *domain.Subscription) *domain.Subscription {
+	clone := *subscription
+	return &clone
+}
+
+// Create grava a assinatura
+func (r *SubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.subscriptions[subscription.ID] = cloneSubscription(subscription)
+	return nil
+}
+
+// FindByID busca a assinatura pelo ID
+func (r *SubscriptionRepository) FindByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, ok := r.store.subscriptions[id]
+	if !ok {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	return cloneSubscription(subscription), nil
+}
+
+// List retorna até limit assinaturas da conta na situação informada, ou em todas com status vazio, das mais novas
+// para as mais antigas
+func (r *SubscriptionRepository) List(ctx context.Context, accountID string, status domain.SubscriptionStatus, limit int) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var subscriptions []*domain.Subscription
+	for _, subscription := range r.store.subscriptions {
+		if subscription.AccountID == accountID && (status == "" || subscription.Status == status) {
+			subscriptions = append(subscriptions, cloneSubscription(subscription))
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.After(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	if len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+	}
+	return subscriptions, nil
+}
+
+// ListDue retorna até limit assinaturas ativas com tentativa até before, das mais atrasadas primeiro
+func (r *SubscriptionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var subscriptions []*domain.Subscription
+	for _, subscription := range r.store.subscriptions {
+		if subscription.Status == domain.SubscriptionActive && subscription.NextAttemptAt != nil && !subscription.NextAttemptAt.After(before) {
+			subscriptions = append(subscriptions, cloneSubscription(subscription))
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].NextAttemptAt.Equal(*subscriptions[j].NextAttemptAt) {
+			return subscriptions[i].NextAttemptAt.Before(*subscriptions[j].NextAttemptAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	if len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+	}
+	return subscriptions, nil
+}
+
+// Update grava a assinatura se a situação gravada ainda for from
+func (r *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription, from domain.SubscriptionStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.subscriptions[subscription.ID]
+	if !ok {
+		return domain.ErrSubscriptionNotFound
+	}
+	if current.Status != from {
+		return domain.ErrSubscriptionChanged
+	}
+	r.store.subscriptions[subscription.ID] = cloneSubscription(subscription)
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// TenantRepository implementa domain.TenantRepository em memória
+type TenantRepository struct {
+	store *Store
+}
+
+// NewTenantRepository cria um repositório de organizações sobre o armazenamento informado
+func NewTenantRepository(store *Store) *TenantRepository {
+	return &TenantRepository{store: store}
+}
+
+// cloneTenant copia a organização, incluindo a tarifa e os tetos de gasto
+func cloneTenant(tenant *domain.Tenant) *domain.Tenant {
+	clone := *tenant
+	if tenant.ChargebackFee != nil {
+		fee := *tenant.ChargebackFee
+		clone.ChargebackFee = &fee
+	}
+	if tenant.SpendingLimits != nil {
+		limits := *tenant.SpendingLimits
+		clone.SpendingLimits = &limits
+	}
+	return &clone
+}
+
+// Save grava a nova organização
+func (r *TenantRepository) Save(ctx context.Context, tenant *domain.Tenant) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.tenants[tenant.ID] = cloneTenant(tenant)
+	return nil
+}
+
+// FindByID busca a organização pelo ID
+func (r *TenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tenant, ok := r.store.tenants[id]
+	if !ok {
+		return nil, domain.ErrTenantNotFound
+	}
+	return cloneTenant(tenant), nil
+}
+
+// FindByAdminKeyHash busca a organização pelo hash da chave dos administradores dela
+func (r *TenantRepository) FindByAdminKeyHash(ctx context.Context, hash string) (*domain.Tenant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, tenant := range r.store.tenants {
+		if tenant.AdminKeyHash == hash {
+			return cloneTenant(tenant), nil
+		}
+	}
+	return nil, domain.ErrTenantNotFound
+}
+
+// List retorna as organizações ordenadas pelo nome
+func (r *TenantRepository) List(ctx context.Context) ([]*domain.Tenant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tenants := make([]*domain.Tenant, 0, len(r.store.tenants))
+	for _, tenant := range r.store.tenants {
+		tenants = append(tenants, cloneTenant(tenant))
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		if tenants[i].Name != tenants[j].Name {
+			return tenants[i].Name < tenants[j].Name
+		}
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants, nil
+}
+
+// Update grava o nome, a chave, a marca, a tarifa e os tetos de gasto da organização
+func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tenants[tenant.ID]; !ok {
+		return domain.ErrTenantNotFound
+	}
+	r.store.tenants[tenant.ID] = cloneTenant(tenant)
+	return nil
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// TwoFactorRepository implementa domain.TwoFactorRepository em memória
+type TwoFactorRepository struct {
+	store *Store
+}
+
+// NewTwoFactorRepository cria um repositório de segundo fator sobre o armazenamento informado
+func NewTwoFactorRepository(store *Store) *TwoFactorRepository {
+	return &TwoFactorRepository{store: store}
+}
+
+func cloneTwoFactor(twoFactor *domain.TwoFactor) *domain.TwoFactor {
+	clone := *twoFactor
+	clone.BackupCodes = slices.Clone(twoFactor.BackupCodes)
+	if twoFactor.ConfirmedAt != nil {
+		confirmedAt := *twoFactor.ConfirmedAt
+		clone.ConfirmedAt = &confirmedAt
+	}
+	return &clone
+}
+
+// Save armazena o cadastro de um segundo fator
+func (r *TwoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.twoFactors[twoFactor.Actor] = cloneTwoFactor(twoFactor)
+	return nil
+}
+
+// FindByActor busca o segundo fator do autor
+func (r *TwoFactorRepository) FindByActor(ctx context.Context, actor string) (*domain.TwoFactor, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	twoFactor, ok := r.store.twoFactors[actor]
+	if !ok {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	return cloneTwoFactor(twoFactor), nil
+}
+
+// Update grava os códigos de recuperação, o último período aceito e a confirmação
+func (r *TwoFactorRepository) Update(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.twoFactors[twoFactor.Actor]
+	if !ok {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+
+	updated := cloneTwoFactor(twoFactor)
+	updated.Secret = current.Secret
+	r.store.twoFactors[twoFactor.Actor] = updated
+	return nil
+}
+
+// Delete remove o segundo fator do autor
+func (r *TwoFactorRepository) Delete(ctx context.Context, actor string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.twoFactors, actor)
+	return nil
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+)
+
+const userEntity = "user"
+
+// UserRepository implementa domain.UserRepository em memória
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository cria um repositório de usuários sobre o armazenamento informado
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// Save armazena um novo usuário registrando a inserção na auditoria
+// Retorna ErrDuplicatedEmail se o e-mail já estiver cadastrado, como o índice único dos bancos
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return domain.ErrDuplicatedEmail
+		}
+	}
+
+	if err := r.store.writeAudit(ctx, userEntity, user.ID, domain.AuditActionInsert, nil, repository.NewUserSnapshot(user)); err != nil {
+		return err
+	}
+
+	clone := *user
+	r.store.users[user.ID] = &clone
+	return nil
+}
+
+// FindByEmail busca um usuário pelo e-mail, sem diferenciar maiúsculas
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if strings.EqualFold(user.Email, strings.TrimSpace(email)) {
+			clone := *user
+			return &clone, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// FindByID busca um usuário pelo ID
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	clone := *user
+	return &clone, nil
+}
+
+// UpdateRole altera o papel do usuário registrando o estado anterior na auditoria
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	updated := *current
+	updated.Role = role
+	updated.UpdatedAt = time.Now()
+
+	if err := r.store.writeAudit(ctx, userEntity, id, domain.AuditActionUpdate, repository.NewUserSnapshot(current), repository.NewUserSnapshot(&updated)); err != nil {
+		return err
+	}
+
+	r.store.users[id] = &updated
+	return nil
+}
+
+// UpdatePassword troca o hash da senha registrando a alteração na auditoria
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	updated := *current
+	updated.PasswordHash = passwordHash
+	updated.UpdatedAt = time.Now()
+
+	if err := r.store.writeAudit(ctx, userEntity, id, domain.AuditActionUpdate, repository.NewUserSnapshot(current), repository.NewUserSnapshot(&updated)); err != nil {
+		return err
+	}
+
+	r.store.users[id] = &updated
+	return nil
+}
+
+// SaveRefreshToken armazena um novo refresh token
+func (r *UserRepository) SaveRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	clone := *token
+	r.store.tokens[token.ID] = &clone
+	return nil
+}
+
+// FindRefreshToken busca um refresh token pelo hash
+func (r *UserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.tokens {
+		if token.TokenHash == tokenHash {
+			clone := *token
+			clone.RevokedAt = cloneTime(token.RevokedAt)
+			return &clone, nil
+		}
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+// FindActiveRefreshTokens busca as sessões ativas do usuário, das renovadas mais recentemente para as mais antigas
+func (r *UserRepository) FindActiveRefreshTokens(ctx context.Context, userID string) ([]*domain.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tokens []*domain.RefreshToken
+	for _, token := range r.store.tokens {
+		if token.UserID == userID && token.IsActive() {
+			clone := *token
+			tokens = append(tokens, &clone)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+// RevokeRefreshToken revoga o token se ainda estiver ativo
+func (r *UserRepository) RevokeRefreshToken(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return domain.ErrInvalidToken
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	return nil
+}
+
+// RevokeSession revoga o token ativo da sessão do usuário
+func (r *UserRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, token := range r.store.tokens {
+		if token.UserID == userID && token.SessionID == sessionID && token.IsActive() {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrSessionNotFound
+}
+
+// RevokeUserRefreshTokens revoga todos os tokens ainda ativos do usuário
+func (r *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.store.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const accountEntity = "account"
+
+// accountDocument é a conta armazenada; deleted_at ausente indica conta ativa
+type accountDocument struct {
+	ID        string              `bson:"_id"`
+	Name      string              `bson:"name"`
+	Email     string              `bson:"email"`
+	EmailHash string              `bson:"email_hash"`
+	APIKey    string              `bson:"api_key"`
+	Role      domain.Role         `bson:"role"`
+	Scopes    []domain.Permission `bson:"scopes"`
+	TenantID  string              `bson:"tenant_id,omitempty"`
+	Balance   float64             `bson:"balance"`
+	CreatedAt time.Time           `bson:"created_at"`
+	UpdatedAt time.Time           `bson:"updated_at"`
+	DeletedAt *time.Time          `bson:"deleted_at,omitempty"`
+}
+
+// AccountRepository implementa domain.AccountRepository no MongoDB
+// O e-mail é gravado cifrado, acompanhado do índice cego email_hash que garante a unicidade
+type AccountRepository struct {
+	store     *Store
+	encryptor *pii.Encryptor
+}
+
+// NewAccountRepository cria um repositório de contas sobre o armazenamento informado
+// Com encryptor nil os dados pessoais são gravados em texto puro
+func NewAccountRepository(store *Store, encryptor *pii.Encryptor) *AccountRepository {
+	return &AccountRepository{store: store, encryptor: encryptor}
+}
+
+// decodeAccount lê a conta do resultado da busca
+func (r *AccountRepository) decodeAccount(ctx context.Context, result *mongo.SingleResult) (*domain.Account, error) {
+	var doc accountDocument
+	if err := result.Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return r.toAccount(ctx, &doc)
+}
+
+// toAccount converte o documento em conta decifrando o e-mail
+func (r *AccountRepository) toAccount(ctx context.Context, doc *accountDocument) (*domain.Account, error) {
+	email, err := r.encryptor.Decrypt(ctx, doc.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Account{
+		ID:        doc.ID,
+		Name:      doc.Name,
+		Email:     email,
+		APIKey:    doc.APIKey,
+		Role:      domain.RoleOrDefault(doc.Role),
+		Scopes:    domain.ScopesOrDefault(doc.Scopes),
+		TenantID:  doc.TenantID,
+		Balance:   doc.Balance,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+		DeletedAt: doc.DeletedAt,
+	}, nil
+}
+
+// Save persiste uma nova conta registrando a inserção na auditoria
+func (r *AccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	email, err := r.encryptor.Encrypt(ctx, account.Email)
+	if err != nil {
+		return err
+	}
+
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		if err := r.store.writeAudit(tx, auditID, accountEntity, account.ID, domain.AuditActionInsert, nil, repository.NewAccountSnapshot(account)); err != nil {
+			return err
+		}
+
+		_, err := r.store.accounts.InsertOne(tx, &accountDocument{
+			ID:        account.ID,
+			Name:      account.Name,
+			Email:     email,
+			EmailHash: r.encryptor.BlindIndex(account.Email),
+			APIKey:    account.APIKey,
+			Role:      account.Role,
+			Scopes:    account.Scopes,
+			TenantID:  account.TenantID,
+			Balance:   account.Balance,
+			CreatedAt: account.CreatedAt,
+			UpdatedAt: account.UpdatedAt,
+		})
+		return err
+	})
+}
+
+// FindByAPIKey busca uma conta ativa pelo API Key
+// Retorna ErrAccountNotFound se não encontrada ou excluída
+func (r *AccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	return r.decodeAccount(ctx, r.store.accounts.FindOne(ctx, bson.M{"api_key": apiKey, "deleted_at": nil}))
+}
+
+// FindByID busca uma conta pelo ID
+// Contas excluídas só são retornadas com domain.IncludeDeleted()
+// Retorna ErrAccountNotFound se não encontrada
+func (r *AccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Account, error) {
+	filter := bson.M{"_id": id}
+	if !domain.NewFindOptions(opts...).IncludeDeleted {
+		filter["deleted_at"] = nil
+	}
+	return r.decodeAccount(ctx, r.store.accounts.FindOne(ctx, filter))
+}
+
+// findActive lê a conta ativa dentro da transação
+// Escritas concorrentes no mesmo documento abortam uma das transações, que é repetida pelo driver
+func (r *AccountRepository) findActive(tx mongo.SessionContext, id string) (*domain.Account, error) {
+	return r.decodeAccount(tx, r.store.accounts.FindOne(tx, bson.M{"_id": id, "deleted_at": nil}))
+}
+
+// UpdateBalance atualiza o saldo da conta registrando o estado anterior na auditoria
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *AccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		updatedAt := time.Now()
+		updated := repository.NewAccountSnapshot(account)
+		updated.UpdatedAt = updatedAt
+
+		if err := r.store.writeAudit(tx, auditID, accountEntity, account.ID, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), updated); err != nil {
+			return err
+		}
+
+		_, err = r.store.accounts.UpdateByID(tx, account.ID, bson.M{
+			"$set": bson.M{"balance": account.Balance, "updated_at": updatedAt},
+		})
+		return err
+	})
+}
+
+// UpdateRole altera o papel do API Key da conta registrando o estado anterior na auditoria
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *AccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, id)
+		if err != nil {
+			return err
+		}
+
+		updatedAt := time.Now()
+		updated := repository.NewAccountSnapshot(current)
+		updated.Role = role
+		updated.UpdatedAt = updatedAt
+
+		if err := r.store.writeAudit(tx, auditID, accountEntity, id, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), updated); err != nil {
+			return err
+		}
+
+		_, err = r.store.accounts.UpdateByID(tx, id, bson.M{
+			"$set": bson.M{"role": role, "updated_at": updatedAt},
+		})
+		return err
+	})
+}
+
+// UpdateTenant move a conta para a organização informada registrando o estado anterior na auditoria
+// Com tenantID vazio o campo tenant_id é removido do documento
+func (r *AccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, id)
+		if err != nil {
+			return err
+		}
+
+		updatedAt := time.Now()
+		updated := repository.NewAccountSnapshot(current)
+		updated.TenantID = tenantID
+		updated.UpdatedAt = updatedAt
+
+		if err := r.store.writeAudit(tx, auditID, accountEntity, id, domain.AuditActionUpdate, repository.NewAccountSnapshot(current), updated); err != nil {
+			return err
+		}
+
+		update := bson.M{"$set": bson.M{"tenant_id": tenantID, "updated_at": updatedAt}}
+		if tenantID == "" {
+			update = bson.M{"$set": bson.M{"updated_at": updatedAt}, "$unset": bson.M{"tenant_id": ""}}
+		}
+		_, err = r.store.accounts.UpdateByID(tx, id, update)
+		return err
+	})
+}
+
+// Delete exclui logicamente a conta preenchendo deleted_at
+// Retorna ErrAccountNotFound se a conta não existir ou já estiver excluída
+func (r *AccountRepository) Delete(ctx context.Context, id string) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, id)
+		if err != nil {
+			return err
+		}
+
+		deletedAt := time.Now()
+		deleted := repository.NewAccountSnapshot(current)
+		deleted.UpdatedAt = deletedAt
+		deleted.DeletedAt = &deletedAt
+
+		if err := r.store.writeAudit(tx, auditID, accountEntity, id, domain.AuditActionDelete, repository.NewAccountSnapshot(current), deleted); err != nil {
+			return err
+		}
+
+		_, err = r.store.accounts.UpdateByID(tx, id, bson.M{
+			"$set": bson.M{"deleted_at": deletedAt, "updated_at": deletedAt},
+		})
+		return err
+	})
+}
+
+// purgeableIDs lista as contas excluídas antes do instante informado que não têm mais faturas associadas
+func (r *AccountRepository) purgeableIDs(ctx context.Context, before time.Time) ([]string, error) {
+	cursor, err := r.store.accounts.Find(ctx,
+		bson.M{"deleted_at": bson.M{"$lt": before}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+
+	withInvoices, err := r.store.invoices.Distinct(ctx, "account_id", bson.M{"account_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	keep := make(map[any]bool, len(withInvoices))
+	for _, id := range withInvoices {
+		keep[id] = true
+	}
+
+	purgeable := ids[:0]
+	for _, id := range ids {
+		if !keep[id] {
+			purgeable = append(purgeable, id)
+		}
+	}
+	return purgeable, nil
+}
+
+// ListAfter retorna até limit contas não excluídas com ID maior que afterID, ordenadas pelo ID
+func (r *AccountRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.Account, error) {
+	cursor, err := r.store.accounts.Find(ctx,
+		bson.M{"_id": bson.M{"$gt": afterID}, "deleted_at": nil},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var accounts []*domain.Account
+	for cursor.Next(ctx) {
+		var doc accountDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		account, err := r.toAccount(ctx, &doc)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, cursor.Err()
+}
+
+// CountDeleted conta as contas que seriam removidas por PurgeDeleted
+func (r *AccountRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ids, err := r.purgeableIDs(ctx, before)
+	return int64(len(ids)), err
+}
+
+// PurgeDeleted remove definitivamente as contas excluídas antes do instante informado
+// Contas que ainda possuem faturas são mantidas para preservar a integridade referencial
+func (r *AccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ids, err := r.purgeableIDs(ctx, before)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result, err := r.store.accounts.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,124 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// alertRuleDocument é a regra de alerta armazenada, com a janela em segundos
+type alertRuleDocument struct {
+	ID            string             `bson:"_id"`
+	Name          string             `bson:"name"`
+	Metric        domain.AlertMetric `bson:"metric"`
+	Threshold     float64            `bson:"threshold"`
+	WindowSeconds int64              `bson:"window_seconds"`
+	Enabled       bool               `bson:"enabled"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at"`
+}
+
+func toAlertRuleDocument(rule *domain.AlertRule) *alertRuleDocument {
+	return &alertRuleDocument{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		Metric:        rule.Metric,
+		Threshold:     rule.Threshold,
+		WindowSeconds: int64(rule.Window.Seconds()),
+		Enabled:       rule.Enabled,
+		CreatedAt:     rule.CreatedAt,
+		UpdatedAt:     rule.UpdatedAt,
+	}
+}
+
+func (d *alertRuleDocument) toDomain() *domain.AlertRule {
+	return &domain.AlertRule{
+		ID:        d.ID,
+		Name:      d.Name,
+		Metric:    d.Metric,
+		Threshold: d.Threshold,
+		Window:    time.Duration(d.WindowSeconds) * time.Second,
+		Enabled:   d.Enabled,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// AlertRuleRepository implementa domain.AlertRuleRepository no MongoDB
+type AlertRuleRepository struct {
+	store *Store
+}
+
+// NewAlertRuleRepository cria um repositório de regras de alerta sobre o armazenamento informado
+func NewAlertRuleRepository(store *Store) *AlertRuleRepository {
+	return &AlertRuleRepository{store: store}
+}
+
+// Create grava uma nova regra
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	_, err := r.store.alertRules.InsertOne(ctx, toAlertRuleDocument(rule))
+	return err
+}
+
+// Update substitui a definição da regra
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	result, err := r.store.alertRules.ReplaceOne(ctx, bson.M{"_id": rule.ID}, toAlertRuleDocument(rule))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// FindByID busca a regra pelo ID
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) FindByID(ctx context.Context, id string) (*domain.AlertRule, error) {
+	var doc alertRuleDocument
+	if err := r.store.alertRules.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAlertRuleNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna todas as regras, das mais antigas para as mais novas
+func (r *AlertRuleRepository) List(ctx context.Context) ([]*domain.AlertRule, error) {
+	cursor, err := r.store.alertRules.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rules []*domain.AlertRule
+	for cursor.Next(ctx) {
+		var doc alertRuleDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		rules = append(rules, doc.toDomain())
+	}
+	return rules, cursor.Err()
+}
+
+// Delete remove a regra
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (r *AlertRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.store.alertRules.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrAlertRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// amountLimitDocument é a faixa de valor armazenada, identificada pela conta
+type amountLimitDocument struct {
+	AccountID string    `bson:"_id"`
+	MinAmount float64   `bson:"min_amount"`
+	MaxAmount float64   `bson:"max_amount"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// AmountLimitRepository implementa domain.AccountAmountLimitsRepository no MongoDB
+type AmountLimitRepository struct {
+	store *Store
+}
+
+// NewAmountLimitRepository cria um repositório de faixas de valor sobre o armazenamento informado
+func NewAmountLimitRepository(store *Store) *AmountLimitRepository {
+	return &AmountLimitRepository{store: store}
+}
+
+// Save substitui a faixa da conta
+func (r *AmountLimitRepository) Save(ctx context.Context, limits *domain.AccountAmountLimits) error {
+	_, err := r.store.amountLimits.ReplaceOne(ctx, bson.M{"_id": limits.AccountID}, &amountLimitDocument{
+		AccountID: limits.AccountID,
+		MinAmount: limits.Range.Min,
+		MaxAmount: limits.Range.Max,
+		UpdatedAt: limits.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca a faixa da conta
+// Retorna ErrAmountLimitsNotFound se a conta usa a faixa padrão
+func (r *AmountLimitRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountAmountLimits, error) {
+	var doc amountLimitDocument
+	if err := r.store.amountLimits.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAmountLimitsNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.AccountAmountLimits{
+		AccountID: doc.AccountID,
+		Range:     domain.AmountRange{Min: doc.MinAmount, Max: doc.MaxAmount},
+		UpdatedAt: doc.UpdatedAt,
+	}, nil
+}
+
+// Delete remove a faixa da conta
+func (r *AmountLimitRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.store.amountLimits.DeleteOne(ctx, bson.M{"_id": accountID})
+	return err
+}
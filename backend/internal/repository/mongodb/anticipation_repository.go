@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// anticipationDocument é uma antecipação armazenada
+type anticipationDocument struct {
+	ID          string    `bson:"_id"`
+	AccountID   string    `bson:"account_id"`
+	Amount      float64   `bson:"amount"`
+	Fee         float64   `bson:"fee"`
+	Holds       int       `bson:"holds"`
+	RequestedBy string    `bson:"requested_by"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// AnticipationRepository implementa domain.AnticipationRepository no MongoDB
+type AnticipationRepository struct {
+	store *Store
+}
+
+// NewAnticipationRepository cria um repositório de antecipações sobre o armazenamento informado
+func NewAnticipationRepository(store *Store) *AnticipationRepository {
+	return &AnticipationRepository{store: store}
+}
+
+// Create grava a antecipação e o movimento em uma transação
+// Retorna ErrEscrowAlreadyReleased se uma das retenções já tiver sido liberada
+func (r *AnticipationRepository) Create(ctx context.Context, anticipation *domain.Anticipation, posting *domain.Posting) error {
+	var auditID int64
+	if n := postingAuditIDs(posting); n > 0 {
+		var err error
+		if auditID, err = r.store.reserveAuditIDs(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		_, err := r.store.anticipations.InsertOne(tx, &anticipationDocument{
+			ID:          anticipation.ID,
+			AccountID:   anticipation.AccountID,
+			Amount:      anticipation.Amount,
+			Fee:         anticipation.Fee,
+			Holds:       anticipation.Holds,
+			RequestedBy: anticipation.RequestedBy,
+			CreatedAt:   anticipation.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return r.store.post(tx, posting, auditID)
+	})
+}
+
+// ListByAccountID retorna até limit antecipações da conta, das mais recentes para as mais antigas
+func (r *AnticipationRepository) ListByAccountID(ctx context.Context, accountID string, limit int) ([]*domain.Anticipation, error) {
+	cursor, err := r.store.anticipations.Find(ctx, bson.M{"account_id": accountID}, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var anticipations []*domain.Anticipation
+	for cursor.Next(ctx) {
+		var doc anticipationDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		anticipations = append(anticipations, &domain.Anticipation{
+			ID:          doc.ID,
+			AccountID:   doc.AccountID,
+			Amount:      doc.Amount,
+			Fee:         doc.Fee,
+			Holds:       doc.Holds,
+			RequestedBy: doc.RequestedBy,
+			CreatedAt:   doc.CreatedAt,
+		})
+	}
+	return anticipations, cursor.Err()
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultAuditLimit limita consultas sem limite explícito, como nos repositórios SQL
+const defaultAuditLimit = 100
+
+// AuditRepository implementa domain.AuditRepository no MongoDB
+type AuditRepository struct {
+	store *Store
+}
+
+// NewAuditRepository cria um repositório de auditoria sobre o armazenamento informado
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+// FindByFilter busca entradas de auditoria, das mais recentes para as mais antigas
+func (r *AuditRepository) FindByFilter(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	query := bson.M{}
+	if filter.Entity != "" {
+		query["entity"] = filter.Entity
+	}
+	if filter.EntityID != "" {
+		query["entity_id"] = filter.EntityID
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.RequestID != "" {
+		query["request_id"] = filter.RequestID
+	}
+	createdAt := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		createdAt["$gte"] = filter.CreatedFrom
+	}
+	if !filter.CreatedTo.IsZero() {
+		createdAt["$lte"] = filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	if filter.After != nil {
+		afterID, err := strconv.ParseInt(filter.After.ID, 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{"$lt": afterID}},
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	cursor, err := r.store.audit.Find(ctx, query, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []auditDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.AuditEntry, 0, len(docs))
+	for _, doc := range docs {
+		entry := &domain.AuditEntry{
+			ID:        doc.ID,
+			Entity:    doc.Entity,
+			EntityID:  doc.EntityID,
+			Action:    doc.Action,
+			Actor:     doc.Actor,
+			CreatedAt: doc.CreatedAt,
+		}
+		if doc.OldValue != nil {
+			entry.OldValue = json.RawMessage(*doc.OldValue)
+		}
+		if doc.NewValue != nil {
+			entry.NewValue = json.RawMessage(*doc.NewValue)
+		}
+		if doc.RequestID != nil {
+			entry.RequestID = *doc.RequestID
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// authEventDocument é o evento de autenticação armazenado; account_id ausente indica credencial sem conta
+type authEventDocument struct {
+	ID        string            `bson:"_id"`
+	AccountID string            `bson:"account_id,omitempty"`
+	KeyID     string            `bson:"key_id"`
+	Method    domain.AuthMethod `bson:"method"`
+	Success   bool              `bson:"success"`
+	Reason    string            `bson:"reason"`
+	IP        string            `bson:"ip"`
+	UserAgent string            `bson:"user_agent"`
+	Route     string            `bson:"route"`
+	CreatedAt time.Time         `bson:"created_at"`
+}
+
+// AuthEventRepository implementa domain.AuthEventRepository no MongoDB
+type AuthEventRepository struct {
+	store *Store
+}
+
+// NewAuthEventRepository cria um repositório de eventos de autenticação sobre o armazenamento informado
+func NewAuthEventRepository(store *Store) *AuthEventRepository {
+	return &AuthEventRepository{store: store}
+}
+
+// Save persiste um evento de autenticação
+func (r *AuthEventRepository) Save(ctx context.Context, event *domain.AuthEvent) error {
+	_, err := r.store.authEvents.InsertOne(ctx, &authEventDocument{
+		ID:        event.ID,
+		AccountID: event.AccountID,
+		KeyID:     event.KeyID,
+		Method:    event.Method,
+		Success:   event.Success,
+		Reason:    event.Reason,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Route:     event.Route,
+		CreatedAt: event.CreatedAt,
+	})
+	return err
+}
+
+// FindByFilter busca os eventos de autenticação de uma conta, dos mais recentes para os mais antigos
+func (r *AuthEventRepository) FindByFilter(ctx context.Context, filter domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	query := bson.M{"account_id": filter.AccountID}
+	if filter.Success != nil {
+		query["success"] = *filter.Success
+	}
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{"$lt": filter.After.ID}},
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	cursor, err := r.store.authEvents.Find(ctx, query, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []authEventDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.AuthEvent, len(docs))
+	for i, doc := range docs {
+		events[i] = &domain.AuthEvent{
+			ID:        doc.ID,
+			AccountID: doc.AccountID,
+			KeyID:     doc.KeyID,
+			Method:    doc.Method,
+			Success:   doc.Success,
+			Reason:    doc.Reason,
+			IP:        doc.IP,
+			UserAgent: doc.UserAgent,
+			Route:     doc.Route,
+			CreatedAt: doc.CreatedAt,
+		}
+	}
+	return events, nil
+}
+
+// CountFailedIPs conta os IPs distintos com falhas de autenticação da credencial desde o instante informado
+func (r *AuthEventRepository) CountFailedIPs(ctx context.Context, keyID string, since time.Time) (int, error) {
+	ips, err := r.store.authEvents.Distinct(ctx, "ip", bson.M{
+		"key_id":     keyID,
+		"success":    false,
+		"created_at": bson.M{"$gte": since},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ips), nil
+}
+
+// FindAccountIPs retorna os pares distintos de conta e IP com autenticações bem-sucedidas em [from, to)
+func (r *AuthEventRepository) FindAccountIPs(ctx context.Context, from, to time.Time) ([]domain.AccountIP, error) {
+	cursor, err := r.store.authEvents.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"success":    true,
+			"account_id": bson.M{"$exists": true},
+			"ip":         bson.M{"$ne": ""},
+			"created_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"account_id": "$account_id", "ip": "$ip"}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID struct {
+			AccountID string `bson:"account_id"`
+			IP        string `bson:"ip"`
+		} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ips := make([]domain.AccountIP, len(docs))
+	for i, doc := range docs {
+		ips[i] = domain.AccountIP{AccountID: doc.ID.AccountID, IP: doc.ID.IP}
+	}
+	return ips, nil
+}
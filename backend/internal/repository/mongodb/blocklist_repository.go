@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// blocklistDocument é a entrada da lista de bloqueio armazenada; as globais têm account_id vazio
+type blocklistDocument struct {
+	ID        string                 `bson:"_id"`
+	AccountID string                 `bson:"account_id"`
+	Type      domain.BlocklistType   `bson:"type"`
+	ValueHash string                 `bson:"value_hash"`
+	Hint      string                 `bson:"hint"`
+	Action    domain.BlocklistAction `bson:"action"`
+	Reason    string                 `bson:"reason"`
+	ExpiresAt *time.Time             `bson:"expires_at,omitempty"`
+	Hits      int64                  `bson:"hits"`
+	LastHitAt *time.Time             `bson:"last_hit_at,omitempty"`
+	CreatedAt time.Time              `bson:"created_at"`
+}
+
+func toBlocklistDocument(entry *domain.BlocklistEntry) *blocklistDocument {
+	return &blocklistDocument{
+		ID:        entry.ID,
+		AccountID: entry.AccountID,
+		Type:      entry.Type,
+		ValueHash: entry.ValueHash,
+		Hint:      entry.Hint,
+		Action:    entry.Action,
+		Reason:    entry.Reason,
+		ExpiresAt: entry.ExpiresAt,
+		Hits:      entry.Hits,
+		LastHitAt: entry.LastHitAt,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func (d *blocklistDocument) toDomain() *domain.BlocklistEntry {
+	return &domain.BlocklistEntry{
+		ID:        d.ID,
+		AccountID: d.AccountID,
+		Type:      d.Type,
+		ValueHash: d.ValueHash,
+		Hint:      d.Hint,
+		Action:    d.Action,
+		Reason:    d.Reason,
+		ExpiresAt: d.ExpiresAt,
+		Hits:      d.Hits,
+		LastHitAt: d.LastHitAt,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+// BlocklistRepository implementa domain.BlocklistRepository no MongoDB
+type BlocklistRepository struct {
+	store *Store
+}
+
+// NewBlocklistRepository cria um repositório de listas de bloqueio sobre o armazenamento informado
+func NewBlocklistRepository(store *Store) *BlocklistRepository {
+	return &BlocklistRepository{store: store}
+}
+
+// Create grava uma nova entrada
+// Retorna ErrBlocklistEntryExists se o índice único de hash e conta recusar a entrada
+func (r *BlocklistRepository) Create(ctx context.Context, entry *domain.BlocklistEntry) error {
+	_, err := r.store.blocklist.InsertOne(ctx, toBlocklistDocument(entry))
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrBlocklistEntryExists
+	}
+	return err
+}
+
+// FindByID busca a entrada pelo ID
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir
+func (r *BlocklistRepository) FindByID(ctx context.Context, id string) (*domain.BlocklistEntry, error) {
+	var doc blocklistDocument
+	if err := r.store.blocklist.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrBlocklistEntryNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna as entradas da conta, ou as globais com accountID vazio, das mais novas para as mais antigas
+func (r *BlocklistRepository) List(ctx context.Context, accountID string) ([]*domain.BlocklistEntry, error) {
+	return r.find(ctx, bson.M{"account_id": accountID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}))
+}
+
+// Match busca as entradas globais e da conta com algum dos hashes informados
+func (r *BlocklistRepository) Match(ctx context.Context, accountID string, valueHashes []string) ([]*domain.BlocklistEntry, error) {
+	if len(valueHashes) == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, bson.M{
+		"value_hash": bson.M{"$in": valueHashes},
+		"account_id": bson.M{"$in": bson.A{"", accountID}},
+	})
+}
+
+// RecordHits soma um acerto às entradas e marca o instante do último
+func (r *BlocklistRepository) RecordHits(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.store.blocklist.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$inc": bson.M{"hits": 1}, "$set": bson.M{"last_hit_at": at}},
+	)
+	return err
+}
+
+// Delete remove a entrada
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir
+func (r *BlocklistRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.store.blocklist.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrBlocklistEntryNotFound
+	}
+	return nil
+}
+
+// find retorna as entradas que atendem ao filtro
+func (r *BlocklistRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.BlocklistEntry, error) {
+	cursor, err := r.store.blocklist.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.BlocklistEntry
+	for cursor.Next(ctx) {
+		var doc blocklistDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		entries = append(entries, doc.toDomain())
+	}
+	return entries, cursor.Err()
+}
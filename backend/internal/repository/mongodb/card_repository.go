@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// cardDocument é o cartão tokenizado armazenado; número e nome já chegam cifrados pelo cofre
+type cardDocument struct {
+	Token       string    `bson:"_id"`
+	AccountID   string    `bson:"account_id"`
+	Brand       string    `bson:"brand"`
+	LastDigits  string    `bson:"last_digits"`
+	ExpiryMonth int       `bson:"expiry_month"`
+	ExpiryYear  int       `bson:"expiry_year"`
+	HolderName  string    `bson:"holder_name"`
+	Number      string    `bson:"number"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// CardRepository implementa carddata.Repository no MongoDB, na coleção card_tokens
+type CardRepository struct {
+	store *Store
+}
+
+// NewCardRepository cria o armazenamento do cofre de cartões sobre o armazenamento informado
+func NewCardRepository(store *Store) *CardRepository {
+	return &CardRepository{store: store}
+}
+
+func newCardDocument(record *carddata.Record) *cardDocument {
+	return &cardDocument{
+		Token:       record.Token,
+		AccountID:   record.AccountID,
+		Brand:       record.Brand,
+		LastDigits:  record.LastDigits,
+		ExpiryMonth: record.ExpiryMonth,
+		ExpiryYear:  record.ExpiryYear,
+		HolderName:  record.HolderName,
+		Number:      record.Number,
+		CreatedAt:   record.CreatedAt,
+	}
+}
+
+// Save persiste um cartão tokenizado
+func (r *CardRepository) Save(ctx context.Context, record *carddata.Record) error {
+	_, err := r.store.cards.InsertOne(ctx, newCardDocument(record))
+	return err
+}
+
+// SaveBatch persiste vários cartões tokenizados com um único InsertMany
+func (r *CardRepository) SaveBatch(ctx context.Context, records []*carddata.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(records))
+	for i, record := range records {
+		docs[i] = newCardDocument(record)
+	}
+	_, err := r.store.cards.InsertMany(ctx, docs)
+	return err
+}
+
+// FindByToken busca um cartão pelo token
+func (r *CardRepository) FindByToken(ctx context.Context, token string) (*carddata.Record, error) {
+	var doc cardDocument
+	if err := r.store.cards.FindOne(ctx, bson.M{"_id": token}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCardNotFound
+		}
+		return nil, err
+	}
+
+	return &carddata.Record{
+		Token:       doc.Token,
+		AccountID:   doc.AccountID,
+		Brand:       doc.Brand,
+		LastDigits:  doc.LastDigits,
+		ExpiryMonth: doc.ExpiryMonth,
+		ExpiryYear:  doc.ExpiryYear,
+		HolderName:  doc.HolderName,
+		Number:      doc.Number,
+		CreatedAt:   doc.CreatedAt,
+	}, nil
+}
+
+// Anonymize apaga número e final dos cartões da conta e troca o nome do portador por holderName
+func (r *CardRepository) Anonymize(ctx context.Context, accountID string, tokens []string, holderName string) error {
+	_, err := r.store.cards.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": tokens}, "account_id": accountID},
+		bson.M{"$set": bson.M{"number": "", "last_digits": "", "holder_name": holderName}},
+	)
+	return err
+}
@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/repositorytest"
+)
+
+// TestRepositoryContract roda no replica set de TEST_MONGODB_URI, no banco gateway_test; sem a variável é pulado
+func TestRepositoryContract(t *testing.T) {
+	uri := os.Getenv("TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGODB_URI not set")
+	}
+	client, err := Connect(t.Context(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	store := NewStore(client, "gateway_test")
+	if err := store.EnsureIndexes(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		return repositorytest.Repositories{
+			Accounts: NewAccountRepository(store, nil),
+			Invoices: NewInvoiceRepository(store, nil),
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// couponDocument é um cupom armazenado
+type couponDocument struct {
+	ID             string            `bson:"_id"`
+	AccountID      string            `bson:"account_id"`
+	Code           string            `bson:"code"`
+	Type           domain.CouponType `bson:"type"`
+	Value          float64           `bson:"value"`
+	MaxRedemptions int               `bson:"max_redemptions"`
+	Redemptions    int               `bson:"redemptions"`
+	ValidFrom      *time.Time        `bson:"valid_from,omitempty"`
+	ValidUntil     *time.Time        `bson:"valid_until,omitempty"`
+	Active         bool              `bson:"active"`
+	CreatedAt      time.Time         `bson:"created_at"`
+	UpdatedAt      time.Time         `bson:"updated_at"`
+}
+
+func (d *couponDocument) toDomain() *domain.Coupon {
+	return &domain.Coupon{
+		ID:             d.ID,
+		AccountID:      d.AccountID,
+		Code:           d.Code,
+		Type:           d.Type,
+		Value:          d.Value,
+		MaxRedemptions: d.MaxRedemptions,
+		Redemptions:    d.Redemptions,
+		ValidFrom:      d.ValidFrom,
+		ValidUntil:     d.ValidUntil,
+		Active:         d.Active,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// couponRedemptionDocument é um uso de cupom armazenado
+type couponRedemptionDocument struct {
+	ID        string    `bson:"_id"`
+	CouponID  string    `bson:"coupon_id"`
+	AccountID string    `bson:"account_id"`
+	InvoiceID string    `bson:"invoice_id"`
+	Discount  float64   `bson:"discount"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// CouponRepository implementa domain.CouponRepository no MongoDB
+type CouponRepository struct {
+	store *Store
+}
+
+// NewCouponRepository cria um repositório de cupons sobre o armazenamento informado
+func NewCouponRepository(store *Store) *CouponRepository {
+	return &CouponRepository{store: store}
+}
+
+// Create grava o cupom
+// Retorna ErrCouponExists quando a conta já tem um cupom com o mesmo código
+func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	_, err := r.store.coupons.InsertOne(ctx, &couponDocument{
+		ID:             coupon.ID,
+		AccountID:      coupon.AccountID,
+		Code:           coupon.Code,
+		Type:           coupon.Type,
+		Value:          coupon.Value,
+		MaxRedemptions: coupon.MaxRedemptions,
+		Redemptions:    coupon.Redemptions,
+		ValidFrom:      coupon.ValidFrom,
+		ValidUntil:     coupon.ValidUntil,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrCouponExists
+	}
+	return err
+}
+
+// FindByID busca o cupom pelo ID
+// Retorna ErrCouponNotFound se o cupom não existir
+func (r *CouponRepository) FindByID(ctx context.Context, id string) (*domain.Coupon, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByCode busca o cupom da conta pelo código normalizado
+// Retorna ErrCouponNotFound se a conta não tiver o cupom
+func (r *CouponRepository) FindByCode(ctx context.Context, accountID, code string) (*domain.Coupon, error) {
+	return r.findOne(ctx, bson.M{"account_id": accountID, "code": code})
+}
+
+// List retorna até limit cupons da conta, dos mais novos para os mais antigos
+func (r *CouponRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Coupon, error) {
+	cursor, err := r.store.coupons.Find(ctx, bson.M{"account_id": accountID}, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var coupons []*domain.Coupon
+	for cursor.Next(ctx) {
+		var doc couponDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, doc.toDomain())
+	}
+	return coupons, cursor.Err()
+}
+
+// Deactivate desativa o cupom
+// Retorna ErrCouponNotFound se o cupom não existir
+func (r *CouponRepository) Deactivate(ctx context.Context, id string, updatedAt time.Time) error {
+	result, err := r.store.coupons.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"active": false, "updated_at": updatedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrCouponNotFound
+	}
+	return nil
+}
+
+// Redeem soma um aos usos do cupom e grava o uso na mesma transação
+// O incremento só acontece com o cupom ativo e com usos restantes; do contrário retorna ErrCouponUnavailable
+func (r *CouponRepository) Redeem(ctx context.Context, redemption *domain.CouponRedemption) error {
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		result, err := r.store.coupons.UpdateOne(tx,
+			bson.M{
+				"_id":    redemption.CouponID,
+				"active": true,
+				"$or": bson.A{
+					bson.M{"max_redemptions": 0},
+					bson.M{"$expr": bson.M{"$lt": bson.A{"$redemptions", "$max_redemptions"}}},
+				},
+			},
+			bson.M{
+				"$inc": bson.M{"redemptions": 1},
+				"$set": bson.M{"updated_at": redemption.CreatedAt},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrCouponUnavailable
+		}
+
+		_, err = r.store.couponRedemptions.InsertOne(tx, &couponRedemptionDocument{
+			ID:        redemption.ID,
+			CouponID:  redemption.CouponID,
+			AccountID: redemption.AccountID,
+			InvoiceID: redemption.InvoiceID,
+			Discount:  redemption.Discount,
+			CreatedAt: redemption.CreatedAt,
+		})
+		return err
+	})
+}
+
+// SumDiscounts soma os descontos dos usos de cupons da conta de from até to
+func (r *CouponRepository) SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (float64, error) {
+	cursor, err := r.store.couponRedemptions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"account_id": accountID, "created_at": bson.M{"$gte": from, "$lte": to}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$discount"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	return docs[0].Total, nil
+}
+
+// findOne retorna o cupom que atende ao filtro
+func (r *CouponRepository) findOne(ctx context.Context, filter bson.M) (*domain.Coupon, error) {
+	var doc couponDocument
+	if err := r.store.coupons.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCouponNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
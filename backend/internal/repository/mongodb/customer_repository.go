@@ -0,0 +1,193 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// customerDocument é um cliente armazenado, com nome, e-mail e documento cifrados
+type customerDocument struct {
+	ID        string    `bson:"_id"`
+	AccountID string    `bson:"account_id"`
+	Name      string    `bson:"name"`
+	Email     string    `bson:"email"`
+	Document  string    `bson:"document"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// customerPaymentMethodDocument é um cartão guardado de um cliente; o cliente e o token formam o índice único
+type customerPaymentMethodDocument struct {
+	CustomerID     string    `bson:"customer_id"`
+	CardToken      string    `bson:"card_token"`
+	CardBrand      string    `bson:"card_brand"`
+	CardLastDigits string    `bson:"card_last_digits"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
+
+// CustomerRepository implementa domain.CustomerRepository no MongoDB
+type CustomerRepository struct {
+	store     *Store
+	encryptor *pii.Encryptor
+}
+
+// NewCustomerRepository cria um repositório de clientes sobre o armazenamento informado
+// Com encryptor nil os dados pessoais são gravados em texto puro
+func NewCustomerRepository(store *Store, encryptor *pii.Encryptor) *CustomerRepository {
+	return &CustomerRepository{store: store, encryptor: encryptor}
+}
+
+// toDomain decifra os dados pessoais do documento
+func (r *CustomerRepository) toDomain(ctx context.Context, doc *customerDocument) (*domain.Customer, error) {
+	customer := &domain.Customer{
+		ID:        doc.ID,
+		AccountID: doc.AccountID,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+	var err error
+	if customer.Name, err = r.encryptor.Decrypt(ctx, doc.Name); err != nil {
+		return nil, err
+	}
+	if customer.Email, err = r.encryptor.Decrypt(ctx, doc.Email); err != nil {
+		return nil, err
+	}
+	if customer.Document, err = r.encryptor.Decrypt(ctx, doc.Document); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// Create grava o cliente com os dados pessoais cifrados
+func (r *CustomerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	doc := &customerDocument{
+		ID:        customer.ID,
+		AccountID: customer.AccountID,
+		CreatedAt: customer.CreatedAt,
+		UpdatedAt: customer.UpdatedAt,
+	}
+	var err error
+	if doc.Name, err = r.encryptor.Encrypt(ctx, customer.Name); err != nil {
+		return err
+	}
+	if doc.Email, err = r.encryptor.Encrypt(ctx, customer.Email); err != nil {
+		return err
+	}
+	if doc.Document, err = r.encryptor.Encrypt(ctx, customer.Document); err != nil {
+		return err
+	}
+	_, err = r.store.customers.InsertOne(ctx, doc)
+	return err
+}
+
+// FindByID busca o cliente pelo ID
+// Retorna ErrCustomerNotFound se o cliente não existir
+func (r *CustomerRepository) FindByID(ctx context.Context, id string) (*domain.Customer, error) {
+	var doc customerDocument
+	if err := r.store.customers.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(ctx, &doc)
+}
+
+// List retorna até limit clientes da conta, dos mais novos para os mais antigos
+func (r *CustomerRepository) List(ctx context.Context, accountID string, limit int) ([]*domain.Customer, error) {
+	cursor, err := r.store.customers.Find(ctx, bson.M{"account_id": accountID}, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var customers []*domain.Customer
+	for cursor.Next(ctx) {
+		var doc customerDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		customer, err := r.toDomain(ctx, &doc)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, cursor.Err()
+}
+
+// Delete apaga o cliente e os cartões ligados a ele na mesma transação
+// Retorna ErrCustomerNotFound se o cliente não existir
+func (r *CustomerRepository) Delete(ctx context.Context, id string) error {
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		result, err := r.store.customers.DeleteOne(tx, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return domain.ErrCustomerNotFound
+		}
+		_, err = r.store.paymentMethods.DeleteMany(tx, bson.M{"customer_id": id})
+		return err
+	})
+}
+
+// AddPaymentMethod liga o cartão ao cliente
+func (r *CustomerRepository) AddPaymentMethod(ctx context.Context, method *domain.CustomerPaymentMethod) error {
+	_, err := r.store.paymentMethods.InsertOne(ctx, &customerPaymentMethodDocument{
+		CustomerID:     method.CustomerID,
+		CardToken:      method.CardToken,
+		CardBrand:      method.CardBrand,
+		CardLastDigits: method.CardLastDigits,
+		CreatedAt:      method.CreatedAt,
+	})
+	return err
+}
+
+// ListPaymentMethods retorna os cartões do cliente, dos mais antigos para os mais novos
+func (r *CustomerRepository) ListPaymentMethods(ctx context.Context, customerID string) ([]*domain.CustomerPaymentMethod, error) {
+	cursor, err := r.store.paymentMethods.Find(ctx, bson.M{"customer_id": customerID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "card_token", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var methods []*domain.CustomerPaymentMethod
+	for cursor.Next(ctx) {
+		var doc customerPaymentMethodDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		methods = append(methods, &domain.CustomerPaymentMethod{
+			CustomerID:     doc.CustomerID,
+			CardToken:      doc.CardToken,
+			CardBrand:      doc.CardBrand,
+			CardLastDigits: doc.CardLastDigits,
+			CreatedAt:      doc.CreatedAt,
+		})
+	}
+	return methods, cursor.Err()
+}
+
+// RemovePaymentMethod desliga o cartão do cliente
+// Retorna ErrCardNotFound se o cartão não estiver ligado ao cliente
+func (r *CustomerRepository) RemovePaymentMethod(ctx context.Context, customerID, cardToken string) error {
+	result, err := r.store.paymentMethods.DeleteOne(ctx, bson.M{"customer_id": customerID, "card_token": cardToken})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrCardNotFound
+	}
+	return nil
+}
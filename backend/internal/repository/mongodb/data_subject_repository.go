@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dataSubjectRequestDocument é o pedido de titular armazenado
+type dataSubjectRequestDocument struct {
+	ID        string                        `bson:"_id"`
+	AccountID string                        `bson:"account_id"`
+	Type      domain.DataSubjectRequestType `bson:"type"`
+	Invoices  int                           `bson:"invoices"`
+	Token     string                        `bson:"token"`
+	Actor     string                        `bson:"actor"`
+	RequestID string                        `bson:"request_id"`
+	CreatedAt time.Time                     `bson:"created_at"`
+}
+
+// DataSubjectRequestRepository implementa domain.DataSubjectRequestRepository no MongoDB
+type DataSubjectRequestRepository struct {
+	store *Store
+}
+
+// NewDataSubjectRequestRepository cria um repositório de pedidos de titulares sobre o armazenamento informado
+func NewDataSubjectRequestRepository(store *Store) *DataSubjectRequestRepository {
+	return &DataSubjectRequestRepository{store: store}
+}
+
+// Save registra o pedido atendido
+func (r *DataSubjectRequestRepository) Save(ctx context.Context, request *domain.DataSubjectRequest) error {
+	_, err := r.store.dataSubjectRequests.InsertOne(ctx, &dataSubjectRequestDocument{
+		ID:        request.ID,
+		AccountID: request.AccountID,
+		Type:      request.Type,
+		Invoices:  request.Invoices,
+		Token:     request.Token,
+		Actor:     request.Actor,
+		RequestID: request.RequestID,
+		CreatedAt: request.CreatedAt,
+	})
+	return err
+}
+
+// FindByAccountID retorna os pedidos da conta, dos mais recentes para os mais antigos
+func (r *DataSubjectRequestRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.DataSubjectRequest, error) {
+	cursor, err := r.store.dataSubjectRequests.Find(ctx, bson.M{"account_id": accountID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []dataSubjectRequestDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	requests := make([]*domain.DataSubjectRequest, len(docs))
+	for i, doc := range docs {
+		requests[i] = &domain.DataSubjectRequest{
+			ID:        doc.ID,
+			AccountID: doc.AccountID,
+			Type:      doc.Type,
+			Invoices:  doc.Invoices,
+			Token:     doc.Token,
+			Actor:     doc.Actor,
+			RequestID: doc.RequestID,
+			CreatedAt: doc.CreatedAt,
+		}
+	}
+	return requests, nil
+}
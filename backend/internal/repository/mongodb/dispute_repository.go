@@ -0,0 +1,247 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// disputeDocument é uma disputa armazenada
+type disputeDocument struct {
+	ID         string               `bson:"_id"`
+	InvoiceID  string               `bson:"invoice_id"`
+	AccountID  string               `bson:"account_id"`
+	Amount     float64              `bson:"amount"`
+	Fee        float64              `bson:"fee"`
+	Reason     string               `bson:"reason"`
+	Status     domain.DisputeStatus `bson:"status"`
+	ResolvedAt *time.Time           `bson:"resolved_at,omitempty"`
+	CreatedAt  time.Time            `bson:"created_at"`
+}
+
+func (d *disputeDocument) toDomain() *domain.Dispute {
+	return &domain.Dispute{
+		ID:         d.ID,
+		InvoiceID:  d.InvoiceID,
+		AccountID:  d.AccountID,
+		Amount:     d.Amount,
+		Fee:        d.Fee,
+		Reason:     d.Reason,
+		Status:     d.Status,
+		ResolvedAt: d.ResolvedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+// disputeEvidenceDocument é uma evidência armazenada; o arquivo fica no armazenamento de objetos
+type disputeEvidenceDocument struct {
+	ID          string    `bson:"_id"`
+	DisputeID   string    `bson:"dispute_id"`
+	AccountID   string    `bson:"account_id"`
+	ObjectKey   string    `bson:"object_key"`
+	FileName    string    `bson:"file_name"`
+	ContentType string    `bson:"content_type"`
+	Size        int64     `bson:"size"`
+	Description string    `bson:"description"`
+	UploadedBy  string    `bson:"uploaded_by"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// DisputeRepository implementa domain.DisputeRepository no MongoDB
+type DisputeRepository struct {
+	store *Store
+}
+
+// NewDisputeRepository cria um repositório de disputas sobre o armazenamento informado
+func NewDisputeRepository(store *Store) *DisputeRepository {
+	return &DisputeRepository{store: store}
+}
+
+// Create grava a disputa e, se posting não for nil, o movimento da tarifa em uma transação
+// Retorna ErrTransactionAlreadyDisputed se a fatura já tiver disputa
+func (r *DisputeRepository) Create(ctx context.Context, dispute *domain.Dispute, posting *domain.Posting) error {
+	var auditID int64
+	if n := postingAuditIDs(posting); n > 0 {
+		var err error
+		if auditID, err = r.store.reserveAuditIDs(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		_, err := r.store.disputes.InsertOne(tx, &disputeDocument{
+			ID:         dispute.ID,
+			InvoiceID:  dispute.InvoiceID,
+			AccountID:  dispute.AccountID,
+			Amount:     dispute.Amount,
+			Fee:        dispute.Fee,
+			Reason:     dispute.Reason,
+			Status:     dispute.Status,
+			ResolvedAt: dispute.ResolvedAt,
+			CreatedAt:  dispute.CreatedAt,
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTransactionAlreadyDisputed
+		}
+		if err != nil || posting == nil {
+			return err
+		}
+		return r.store.post(tx, posting, auditID)
+	})
+}
+
+// FindByID busca a disputa pelo ID
+// Retorna ErrDisputeNotFound se a disputa não existir
+func (r *DisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	var doc disputeDocument
+	if err := r.store.disputes.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna até limit disputas da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais novas para as mais antigas
+func (r *DisputeRepository) List(ctx context.Context, accountID string, status domain.DisputeStatus, limit int) ([]*domain.Dispute, error) {
+	filter := bson.M{}
+	if accountID != "" {
+		filter["account_id"] = accountID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := r.store.disputes.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var disputes []*domain.Dispute
+	for cursor.Next(ctx) {
+		var doc disputeDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, doc.toDomain())
+	}
+	return disputes, cursor.Err()
+}
+
+// Resolve grava o resultado se a disputa ainda estiver aberta e, na disputa perdida, o chargeback em uma transação
+// A fatura é travada por lockInvoice e as retenções só são devolvidas se ainda estiverem retidas, então uma
+// liberação ou um reembolso simultâneo entra em conflito com a transação
+// Retorna ErrDisputeClosed quando outro resultado chegou antes
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	var auditID int64
+	if dispute.Status == domain.DisputeLost {
+		// Um ID para a conta debitada e outro para a fatura estornada
+		var err error
+		if auditID, err = r.store.reserveAuditIDs(ctx, 2); err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		var invoice *domain.Invoice
+		if dispute.Status == domain.DisputeLost {
+			var err error
+			if invoice, err = r.store.lockInvoice(tx, dispute.InvoiceID); err != nil {
+				return err
+			}
+		}
+
+		result, err := r.store.disputes.UpdateOne(tx,
+			bson.M{"_id": dispute.ID, "status": domain.DisputeOpen},
+			bson.M{"$set": bson.M{"status": dispute.Status, "resolved_at": dispute.ResolvedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrDisputeClosed
+		}
+		if invoice == nil {
+			return nil
+		}
+		return r.chargeBack(tx, dispute, invoice, auditID)
+	})
+}
+
+// chargeBack grava o ChargebackPosting das retenções ainda retidas da fatura travada e a passa para
+// StatusChargedBack se ela ainda estiver aprovada; o movimento usa o ID da auditoria auditID e a fatura, o seguinte
+func (r *DisputeRepository) chargeBack(tx mongo.SessionContext, dispute *domain.Dispute, invoice *domain.Invoice, auditID int64) error {
+	held, err := NewEscrowRepository(r.store).find(tx, bson.M{"invoice_id": invoice.ID, "status": domain.EscrowHeld},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	posting, err := dispute.ChargebackPosting(held)
+	if err != nil {
+		return err
+	}
+	if err := r.store.post(tx, posting, auditID); err != nil {
+		return err
+	}
+	if !invoice.Status.CanTransitionTo(domain.StatusChargedBack) {
+		return nil
+	}
+	return r.store.setInvoiceStatus(tx, auditID+1, invoice, domain.StatusChargedBack)
+}
+
+// AddEvidence grava a evidência da disputa
+func (r *DisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	_, err := r.store.disputeEvidence.InsertOne(ctx, &disputeEvidenceDocument{
+		ID:          evidence.ID,
+		DisputeID:   evidence.DisputeID,
+		AccountID:   evidence.AccountID,
+		ObjectKey:   evidence.ObjectKey,
+		FileName:    evidence.FileName,
+		ContentType: evidence.ContentType,
+		Size:        evidence.Size,
+		Description: evidence.Description,
+		UploadedBy:  evidence.UploadedBy,
+		CreatedAt:   evidence.CreatedAt,
+	})
+	return err
+}
+
+// ListEvidence retorna as evidências da disputa na ordem do envio
+func (r *DisputeRepository) ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error) {
+	cursor, err := r.store.disputeEvidence.Find(ctx, bson.M{"dispute_id": disputeID}, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var evidence []*domain.DisputeEvidence
+	for cursor.Next(ctx) {
+		var doc disputeEvidenceDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, &domain.DisputeEvidence{
+			ID:          doc.ID,
+			DisputeID:   doc.DisputeID,
+			AccountID:   doc.AccountID,
+			ObjectKey:   doc.ObjectKey,
+			FileName:    doc.FileName,
+			ContentType: doc.ContentType,
+			Size:        doc.Size,
+			Description: doc.Description,
+			UploadedBy:  doc.UploadedBy,
+			CreatedAt:   doc.CreatedAt,
+		})
+	}
+	return evidence, cursor.Err()
+}
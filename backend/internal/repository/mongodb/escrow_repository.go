@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// escrowDocument é uma retenção em custódia armazenada
+type escrowDocument struct {
+	ID         string              `bson:"_id"`
+	AccountID  string              `bson:"account_id"`
+	InvoiceID  string              `bson:"invoice_id"`
+	Kind       domain.EscrowKind   `bson:"kind,omitempty"`
+	Amount     float64             `bson:"amount"`
+	Status     domain.EscrowStatus `bson:"status"`
+	ReleaseAt  time.Time           `bson:"release_at"`
+	ReleasedBy string              `bson:"released_by"`
+	ReleasedAt *time.Time          `bson:"released_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at"`
+}
+
+// toDomain converte o documento; as retenções gravadas antes dos prazos de repasse não têm motivo e são custódias
+func (d *escrowDocument) toDomain() *domain.EscrowHold {
+	kind := d.Kind
+	if kind == "" {
+		kind = domain.EscrowKindEscrow
+	}
+	return &domain.EscrowHold{
+		ID:         d.ID,
+		AccountID:  d.AccountID,
+		InvoiceID:  d.InvoiceID,
+		Kind:       kind,
+		Amount:     d.Amount,
+		Status:     d.Status,
+		ReleaseAt:  d.ReleaseAt,
+		ReleasedBy: d.ReleasedBy,
+		ReleasedAt: d.ReleasedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+// EscrowRepository implementa domain.EscrowRepository no MongoDB
+type EscrowRepository struct {
+	store *Store
+}
+
+// NewEscrowRepository cria um repositório de retenções em custódia sobre o armazenamento informado
+func NewEscrowRepository(store *Store) *EscrowRepository {
+	return &EscrowRepository{store: store}
+}
+
+// newEscrowDocument converte a retenção no documento armazenado
+func newEscrowDocument(hold *domain.EscrowHold) *escrowDocument {
+	return &escrowDocument{
+		ID:         hold.ID,
+		AccountID:  hold.AccountID,
+		InvoiceID:  hold.InvoiceID,
+		Kind:       hold.Kind,
+		Amount:     hold.Amount,
+		Status:     hold.Status,
+		ReleaseAt:  hold.ReleaseAt,
+		ReleasedBy: hold.ReleasedBy,
+		ReleasedAt: hold.ReleasedAt,
+		CreatedAt:  hold.CreatedAt,
+	}
+}
+
+// CreateBatch grava as retenções em um único InsertMany
+func (r *EscrowRepository) CreateBatch(ctx context.Context, holds []*domain.EscrowHold) error {
+	if len(holds) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(holds))
+	for i, hold := range holds {
+		docs[i] = newEscrowDocument(hold)
+	}
+	_, err := r.store.escrow.InsertMany(ctx, docs)
+	return err
+}
+
+// ListByInvoiceID retorna as retenções da fatura, liberadas ou não, ordenadas pela conta
+func (r *EscrowRepository) ListByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.EscrowHold, error) {
+	return r.find(ctx, bson.M{"invoice_id": invoiceID}, options.Find().
+		SetSort(bson.D{{Key: "account_id", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+// ListHeld retorna as retenções ainda retidas da conta pelo motivo kind, das que vencem primeiro
+func (r *EscrowRepository) ListHeld(ctx context.Context, accountID string, kind domain.EscrowKind) ([]*domain.EscrowHold, error) {
+	return r.find(ctx, bson.M{"account_id": accountID, "status": domain.EscrowHeld, "kind": bson.M{"$in": escrowKinds(kind)}},
+		options.Find().SetSort(bson.D{{Key: "release_at", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
+// ListDue retorna até limit retenções ainda retidas com ReleaseAt até before, das que venceram primeiro
+func (r *EscrowRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.EscrowHold, error) {
+	return r.find(ctx, bson.M{"status": domain.EscrowHeld, "release_at": bson.M{"$lte": before}}, options.Find().
+		SetSort(bson.D{{Key: "release_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// Release grava a liberação se a retenção ainda estiver retida
+// Retorna ErrEscrowAlreadyReleased quando outra liberação chegou antes
+func (r *EscrowRepository) Release(ctx context.Context, hold *domain.EscrowHold) error {
+	return releaseEscrowHold(ctx, r.store, hold)
+}
+
+// releaseEscrowHold grava a liberação da retenção, se ela ainda estiver retida; ctx pode ser uma transação
+// Retorna ErrEscrowAlreadyReleased se ela já tiver sido liberada
+func releaseEscrowHold(ctx context.Context, store *Store, hold *domain.EscrowHold) error {
+	result, err := store.escrow.UpdateOne(ctx,
+		bson.M{"_id": hold.ID, "status": domain.EscrowHeld},
+		bson.M{"$set": bson.M{"status": hold.Status, "released_by": hold.ReleasedBy, "released_at": hold.ReleasedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrEscrowAlreadyReleased
+	}
+	return nil
+}
+
+// SumHeld soma o valor ainda retido da conta pelo motivo kind
+func (r *EscrowRepository) SumHeld(ctx context.Context, accountID string, kind domain.EscrowKind) (float64, error) {
+	cursor, err := r.store.escrow.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"account_id": accountID, "status": domain.EscrowHeld, "kind": bson.M{"$in": escrowKinds(kind)}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	return docs[0].Total, nil
+}
+
+// escrowKinds são os valores do campo kind das retenções do motivo informado; as sem motivo, gravadas antes dos
+// prazos de repasse, contam como custódia
+func escrowKinds(kind domain.EscrowKind) bson.A {
+	if kind == domain.EscrowKindEscrow {
+		return bson.A{kind, nil}
+	}
+	return bson.A{kind}
+}
+
+// find retorna as retenções que atendem ao filtro
+func (r *EscrowRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.EscrowHold, error) {
+	cursor, err := r.store.escrow.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var holds []*domain.EscrowHold
+	for cursor.Next(ctx) {
+		var doc escrowDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		holds = append(holds, doc.toDomain())
+	}
+	return holds, cursor.Err()
+}
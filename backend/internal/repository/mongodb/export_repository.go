@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const exportEntity = "export"
+
+// exportDocument é a exportação armazenada
+type exportDocument struct {
+	ID          string            `bson:"_id"`
+	AccountID   string            `bson:"account_id"`
+	Type        domain.ExportType `bson:"type"`
+	ObjectKey   string            `bson:"object_key"`
+	FileName    string            `bson:"file_name"`
+	ContentType string            `bson:"content_type"`
+	Size        int64             `bson:"size"`
+	Downloads   int               `bson:"downloads"`
+	CreatedAt   time.Time         `bson:"created_at"`
+	ExpiresAt   time.Time         `bson:"expires_at"`
+}
+
+func (d *exportDocument) toDomain() *domain.Export {
+	return &domain.Export{
+		ID:          d.ID,
+		AccountID:   d.AccountID,
+		Type:        d.Type,
+		ObjectKey:   d.ObjectKey,
+		FileName:    d.FileName,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		Downloads:   d.Downloads,
+		CreatedAt:   d.CreatedAt,
+		ExpiresAt:   d.ExpiresAt,
+	}
+}
+
+// ExportRepository implementa domain.ExportRepository no MongoDB
+type ExportRepository struct {
+	store *Store
+}
+
+// NewExportRepository cria um repositório de exportações sobre o armazenamento informado
+func NewExportRepository(store *Store) *ExportRepository {
+	return &ExportRepository{store: store}
+}
+
+// Save grava a exportação e a entrada de auditoria na mesma transação
+func (r *ExportRepository) Save(ctx context.Context, export *domain.Export) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		if err := r.store.writeAudit(tx, auditID, exportEntity, export.ID, domain.AuditActionInsert, nil, repository.NewExportSnapshot(export)); err != nil {
+			return err
+		}
+
+		_, err := r.store.exports.InsertOne(tx, &exportDocument{
+			ID:          export.ID,
+			AccountID:   export.AccountID,
+			Type:        export.Type,
+			ObjectKey:   export.ObjectKey,
+			FileName:    export.FileName,
+			ContentType: export.ContentType,
+			Size:        export.Size,
+			Downloads:   export.Downloads,
+			CreatedAt:   export.CreatedAt,
+			ExpiresAt:   export.ExpiresAt,
+		})
+		return err
+	})
+}
+
+// find busca a exportação, dentro ou fora de uma transação
+func (r *ExportRepository) find(ctx context.Context, id string) (*domain.Export, error) {
+	var doc exportDocument
+	err := r.store.exports.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// FindByID busca a exportação
+// Retorna ErrExportNotFound se ela não existir
+func (r *ExportRepository) FindByID(ctx context.Context, id string) (*domain.Export, error) {
+	return r.find(ctx, id)
+}
+
+// RecordDownload incrementa os downloads e registra o download na auditoria, na mesma transação
+// Retorna ErrExportNotFound se a exportação não existir
+func (r *ExportRepository) RecordDownload(ctx context.Context, id string) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		export, err := r.find(tx, id)
+		if err != nil {
+			return err
+		}
+
+		downloads := export.Downloads + 1
+		if err := r.store.writeAudit(tx, auditID, exportEntity, id, domain.AuditActionDownload,
+			repository.NewExportDownloadSnapshot(tx, export.Downloads), repository.NewExportDownloadSnapshot(tx, downloads)); err != nil {
+			return err
+		}
+
+		// O filtro pelo valor lido faz a transação conflitar, e ser repetida, com downloads concorrentes
+		result, err := r.store.exports.UpdateOne(tx,
+			bson.M{"_id": id, "downloads": export.Downloads},
+			bson.M{"$set": bson.M{"downloads": downloads}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrExportNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// feeScheduleDocument são as tarifas armazenadas, identificadas pela conta
+type feeScheduleDocument struct {
+	AccountID     string    `bson:"_id"`
+	ChargebackFee float64   `bson:"chargeback_fee"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+// FeeScheduleRepository implementa domain.FeeScheduleRepository no MongoDB
+type FeeScheduleRepository struct {
+	store *Store
+}
+
+// NewFeeScheduleRepository cria um repositório de tarifas sobre o armazenamento informado
+func NewFeeScheduleRepository(store *Store) *FeeScheduleRepository {
+	return &FeeScheduleRepository{store: store}
+}
+
+// Save substitui as tarifas da conta
+func (r *FeeScheduleRepository) Save(ctx context.Context, schedule *domain.FeeSchedule) error {
+	_, err := r.store.feeSchedules.ReplaceOne(ctx, bson.M{"_id": schedule.AccountID}, &feeScheduleDocument{
+		AccountID:     schedule.AccountID,
+		ChargebackFee: schedule.ChargebackFee,
+		UpdatedAt:     schedule.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca as tarifas da conta
+// Retorna ErrFeeScheduleNotFound se a conta usar as tarifas padrão
+func (r *FeeScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	var doc feeScheduleDocument
+	if err := r.store.feeSchedules.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrFeeScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.FeeSchedule{
+		AccountID:     doc.AccountID,
+		ChargebackFee: doc.ChargebackFee,
+		UpdatedAt:     doc.UpdatedAt,
+	}, nil
+}
+
+// Delete remove as tarifas da conta, que volta às tarifas padrão
+func (r *FeeScheduleRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.store.feeSchedules.DeleteOne(ctx, bson.M{"_id": accountID})
+	return err
+}
@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// geoPolicyDocument é a política geográfica armazenada, identificada pela conta
+type geoPolicyDocument struct {
+	AccountID        string           `bson:"_id"`
+	Action           domain.GeoAction `bson:"action"`
+	Shadow           bool             `bson:"shadow"`
+	AllowedCountries []string         `bson:"allowed_countries"`
+	BlockedCountries []string         `bson:"blocked_countries"`
+	UpdatedAt        time.Time        `bson:"updated_at"`
+}
+
+// GeoPolicyRepository implementa domain.GeoPolicyRepository no MongoDB
+type GeoPolicyRepository struct {
+	store *Store
+}
+
+// NewGeoPolicyRepository cria um repositório de políticas geográficas sobre o armazenamento informado
+func NewGeoPolicyRepository(store *Store) *GeoPolicyRepository {
+	return &GeoPolicyRepository{store: store}
+}
+
+// Save substitui a política da conta
+func (r *GeoPolicyRepository) Save(ctx context.Context, policy *domain.GeoPolicy) error {
+	_, err := r.store.geoPolicies.ReplaceOne(ctx, bson.M{"_id": policy.AccountID}, &geoPolicyDocument{
+		AccountID:        policy.AccountID,
+		Action:           policy.Action,
+		Shadow:           policy.Shadow,
+		AllowedCountries: policy.AllowedCountries,
+		BlockedCountries: policy.BlockedCountries,
+		UpdatedAt:        policy.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca a política da conta
+// Retorna ErrGeoPolicyNotFound se a conta não tiver política
+func (r *GeoPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.GeoPolicy, error) {
+	var doc geoPolicyDocument
+	if err := r.store.geoPolicies.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrGeoPolicyNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.GeoPolicy{
+		AccountID:        doc.AccountID,
+		Action:           doc.Action,
+		Shadow:           doc.Shadow,
+		AllowedCountries: doc.AllowedCountries,
+		BlockedCountries: doc.BlockedCountries,
+		UpdatedAt:        doc.UpdatedAt,
+	}, nil
+}
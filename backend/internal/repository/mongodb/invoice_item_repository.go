@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invoiceItemDocument é um item da fatura; a fatura e a posição formam o índice único
+type invoiceItemDocument struct {
+	InvoiceID   string    `bson:"invoice_id"`
+	Position    int       `bson:"position"`
+	Description string    `bson:"description"`
+	Quantity    int       `bson:"quantity"`
+	UnitPrice   float64   `bson:"unit_price"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// InvoiceItemRepository implementa domain.InvoiceItemRepository no MongoDB
+type InvoiceItemRepository struct {
+	store *Store
+}
+
+// NewInvoiceItemRepository cria um repositório dos itens das faturas sobre o armazenamento informado
+func NewInvoiceItemRepository(store *Store) *InvoiceItemRepository {
+	return &InvoiceItemRepository{store: store}
+}
+
+// SaveBatch grava os itens de uma ou mais faturas em um único InsertMany
+func (r *InvoiceItemRepository) SaveBatch(ctx context.Context, items []*domain.InvoiceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(items))
+	for i, item := range items {
+		docs[i] = &invoiceItemDocument{
+			InvoiceID:   item.InvoiceID,
+			Position:    item.Position,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			CreatedAt:   item.CreatedAt,
+		}
+	}
+	_, err := r.store.invoiceItems.InsertMany(ctx, docs)
+	return err
+}
+
+// FindByInvoiceID retorna os itens da fatura na ordem dela, ou nenhum se ela não tem itens
+func (r *InvoiceItemRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceItem, error) {
+	cursor, err := r.store.invoiceItems.Find(ctx, bson.M{"invoice_id": invoiceID},
+		options.Find().SetSort(bson.D{{Key: "position", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.InvoiceItem
+	for cursor.Next(ctx) {
+		var doc invoiceItemDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		items = append(items, &domain.InvoiceItem{
+			InvoiceID:   doc.InvoiceID,
+			Position:    doc.Position,
+			Description: doc.Description,
+			Quantity:    doc.Quantity,
+			UnitPrice:   doc.UnitPrice,
+			CreatedAt:   doc.CreatedAt,
+		})
+	}
+	return items, cursor.Err()
+}
@@ -0,0 +1,624 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const invoiceEntity = "invoice"
+
+// invoiceDocument é a fatura armazenada; deleted_at ausente indica fatura ativa
+type invoiceDocument struct {
+	ID             string            `bson:"_id"`
+	AccountID      string            `bson:"account_id"`
+	Amount         float64           `bson:"amount"`
+	Status         domain.Status     `bson:"status"`
+	Description    string            `bson:"description"`
+	PaymentType    string            `bson:"payment_type"`
+	CardToken      string            `bson:"card_token"`
+	CardBrand      string            `bson:"card_brand"`
+	CardLastDigits string            `bson:"card_last_digits"`
+	PayerName      string            `bson:"payer_name"`
+	Metadata       map[string]string `bson:"metadata"`
+	CreatedAt      time.Time         `bson:"created_at"`
+	UpdatedAt      time.Time         `bson:"updated_at"`
+	DeletedAt      *time.Time        `bson:"deleted_at,omitempty"`
+}
+
+// InvoiceRepository implementa domain.InvoiceRepository no MongoDB
+// Os últimos dígitos do cartão são gravados cifrados
+type InvoiceRepository struct {
+	store     *Store
+	encryptor *pii.Encryptor
+}
+
+// NewInvoiceRepository cria um repositório de faturas sobre o armazenamento informado
+// Com encryptor nil os dados do cartão são gravados em texto puro
+func NewInvoiceRepository(store *Store, encryptor *pii.Encryptor) *InvoiceRepository {
+	return &InvoiceRepository{store: store, encryptor: encryptor}
+}
+
+// newInvoiceDocument monta o documento cifrando os dados do cartão
+func (r *InvoiceRepository) newInvoiceDocument(ctx context.Context, invoice *domain.Invoice) (*invoiceDocument, error) {
+	cardLastDigits, err := r.encryptor.Encrypt(ctx, invoice.CardLastDigits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoiceDocument{
+		ID:             invoice.ID,
+		AccountID:      invoice.AccountID,
+		Amount:         invoice.Amount,
+		Status:         invoice.Status,
+		Description:    invoice.Description,
+		PaymentType:    invoice.PaymentType,
+		CardToken:      invoice.CardToken,
+		CardBrand:      invoice.CardBrand,
+		CardLastDigits: cardLastDigits,
+		PayerName:      invoice.PayerName,
+		Metadata:       invoice.Metadata,
+		CreatedAt:      invoice.CreatedAt,
+		UpdatedAt:      invoice.UpdatedAt,
+		DeletedAt:      invoice.DeletedAt,
+	}, nil
+}
+
+// toInvoice converte o documento em fatura decifrando os dados do cartão
+func (r *InvoiceRepository) toInvoice(ctx context.Context, doc *invoiceDocument) (*domain.Invoice, error) {
+	cardLastDigits, err := r.encryptor.Decrypt(ctx, doc.CardLastDigits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Invoice{
+		ID:             doc.ID,
+		AccountID:      doc.AccountID,
+		Amount:         doc.Amount,
+		Status:         doc.Status,
+		Description:    doc.Description,
+		PaymentType:    doc.PaymentType,
+		CardToken:      doc.CardToken,
+		CardBrand:      doc.CardBrand,
+		CardLastDigits: cardLastDigits,
+		PayerName:      doc.PayerName,
+		Metadata:       doc.Metadata,
+		CreatedAt:      doc.CreatedAt,
+		UpdatedAt:      doc.UpdatedAt,
+		DeletedAt:      doc.DeletedAt,
+	}, nil
+}
+
+// decodeInvoice lê o resultado de uma busca pontual
+func (r *InvoiceRepository) decodeInvoice(ctx context.Context, result *mongo.SingleResult) (*domain.Invoice, error) {
+	var doc invoiceDocument
+	if err := result.Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return r.toInvoice(ctx, &doc)
+}
+
+// Save salva uma fatura registrando a inserção na auditoria
+// As partes e o crédito, quando há, são gravados na mesma transação
+func (r *InvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) error {
+	doc, err := r.newInvoiceDocument(ctx, invoice)
+	if err != nil {
+		return err
+	}
+
+	auditID, err := r.invoiceAuditIDs(ctx, posting)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		if err := r.store.writeAudit(tx, auditID, invoiceEntity, invoice.ID, domain.AuditActionInsert, nil, repository.NewInvoiceSnapshot(invoice)); err != nil {
+			return err
+		}
+
+		if _, err := r.store.invoices.InsertOne(tx, doc); err != nil {
+			return err
+		}
+		if err := r.store.insertSplits(tx, splits); err != nil {
+			return err
+		}
+		if posting == nil {
+			return nil
+		}
+		return r.store.post(tx, posting, auditID+1)
+	})
+}
+
+// invoiceAuditIDs reserva um ID de auditoria para a fatura e os de postingAuditIDs para o crédito de Save e
+// UpdateStatus
+func (r *InvoiceRepository) invoiceAuditIDs(ctx context.Context, posting *domain.Posting) (int64, error) {
+	return r.store.reserveAuditIDs(ctx, 1+postingAuditIDs(posting))
+}
+
+// SaveBatch salva várias faturas e suas entradas de auditoria em uma única transação
+func (r *InvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	firstAuditID, err := r.store.reserveAuditIDs(ctx, len(invoices))
+	if err != nil {
+		return err
+	}
+
+	docs := make([]any, len(invoices))
+	audits := make([]any, len(invoices))
+	for i, invoice := range invoices {
+		if docs[i], err = r.newInvoiceDocument(ctx, invoice); err != nil {
+			return err
+		}
+		audits[i], err = newAuditDocument(ctx, firstAuditID+int64(i), invoiceEntity, invoice.ID, domain.AuditActionInsert, nil, repository.NewInvoiceSnapshot(invoice))
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		if _, err := r.store.audit.InsertMany(tx, audits); err != nil {
+			return err
+		}
+		_, err := r.store.invoices.InsertMany(tx, docs)
+		return err
+	})
+}
+
+// FindByID busca uma fatura pelo ID
+// Faturas excluídas só são retornadas com domain.IncludeDeleted()
+func (r *InvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Invoice, error) {
+	filter := bson.M{"_id": id}
+	if !domain.NewFindOptions(opts...).IncludeDeleted {
+		filter["deleted_at"] = nil
+	}
+	return r.decodeInvoice(ctx, r.store.invoices.FindOne(ctx, filter))
+}
+
+// FindByAccountID busca todas as faturas de um determinado accountID
+func (r *InvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
+	return r.FindByFilter(ctx, domain.InvoiceFilter{AccountID: accountID})
+}
+
+// FindByFilter busca faturas combinando os critérios informados
+// A ordenação e o cursor seguem os repositórios SQL: created_at DESC, id DESC
+func (r *InvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	query, findOptions := buildInvoiceQuery(filter)
+
+	cursor, err := r.store.invoices.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	for cursor.Next(ctx) {
+		var doc invoiceDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		invoice, err := r.toInvoice(ctx, &doc)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, cursor.Err()
+}
+
+// buildInvoiceQuery traduz o filtro na consulta e nas opções de ordenação do MongoDB
+func buildInvoiceQuery(filter domain.InvoiceFilter) (bson.M, *options.FindOptions) {
+	query := bson.M{}
+
+	if !filter.IncludeDeleted {
+		query["deleted_at"] = nil
+	}
+	if filter.AccountID != "" {
+		query["account_id"] = filter.AccountID
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+
+	createdAt := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		createdAt["$gte"] = filter.CreatedFrom
+	}
+	if !filter.CreatedTo.IsZero() {
+		createdAt["$lte"] = filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	amount := bson.M{}
+	if filter.MinAmount > 0 {
+		amount["$gte"] = filter.MinAmount
+	}
+	if filter.MaxAmount > 0 {
+		amount["$lte"] = filter.MaxAmount
+	}
+	if len(amount) > 0 {
+		query["amount"] = amount
+	}
+
+	for key, value := range filter.Metadata {
+		query["metadata."+key] = value
+	}
+
+	// Paginação por keyset: id desempata faturas criadas no mesmo instante
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.After.CreatedAt}},
+			bson.M{"created_at": filter.After.CreatedAt, "_id": bson.M{"$lt": filter.After.ID}},
+		}
+	}
+
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	findOptions := options.Find()
+
+	// A busca textual usa o índice de texto e ordena pela relevância
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": filter.Search}
+		score := bson.M{"$meta": "textScore"}
+		sort = append(bson.D{{Key: "score", Value: score}}, sort...)
+		findOptions.SetProjection(bson.M{"score": score})
+	}
+
+	findOptions.SetSort(sort)
+	if filter.Limit > 0 {
+		findOptions.SetLimit(int64(filter.Limit))
+	}
+
+	return query, findOptions
+}
+
+// findActive lê a fatura ativa dentro da transação
+func (r *InvoiceRepository) findActive(tx mongo.SessionContext, id string) (*domain.Invoice, error) {
+	return r.decodeInvoice(tx, r.store.invoices.FindOne(tx, bson.M{"_id": id, "deleted_at": nil}))
+}
+
+// UpdateStatus atualiza o status, o valor e os metadados de uma fatura registrando o estado anterior na auditoria
+// Retorna ErrInvoiceNotFound se a fatura não existir e ErrInvalidStatus se o status gravado não puder mudar
+// para o da fatura; um resultado concorrente faz a transação repetir e cair nessa verificação
+// O crédito, quando há, é gravado na mesma transação
+func (r *InvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) error {
+	auditID, err := r.invoiceAuditIDs(ctx, posting)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		if !current.Status.CanTransitionTo(invoice.Status) {
+			return domain.ErrInvalidStatus
+		}
+
+		if err := r.store.writeAudit(tx, auditID, invoiceEntity, invoice.ID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(current), repository.NewInvoiceSnapshot(invoice)); err != nil {
+			return err
+		}
+
+		_, err = r.store.invoices.UpdateByID(tx, invoice.ID, bson.M{
+			"$set": bson.M{"status": invoice.Status, "amount": invoice.Amount, "metadata": invoice.Metadata, "updated_at": invoice.UpdatedAt},
+		})
+		if err != nil || posting == nil {
+			return err
+		}
+		return r.store.post(tx, posting, auditID+1)
+	})
+}
+
+// Delete exclui logicamente a fatura preenchendo deleted_at
+// Retorna ErrInvoiceNotFound se a fatura não existir ou já estiver excluída
+func (r *InvoiceRepository) Delete(ctx context.Context, id string) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.findActive(tx, id)
+		if err != nil {
+			return err
+		}
+
+		deletedAt := time.Now()
+		deleted := repository.NewInvoiceSnapshot(current)
+		deleted.UpdatedAt = deletedAt
+		deleted.DeletedAt = &deletedAt
+
+		if err := r.store.writeAudit(tx, auditID, invoiceEntity, id, domain.AuditActionDelete, repository.NewInvoiceSnapshot(current), deleted); err != nil {
+			return err
+		}
+
+		_, err = r.store.invoices.UpdateByID(tx, id, bson.M{
+			"$set": bson.M{"deleted_at": deletedAt, "updated_at": deletedAt},
+		})
+		return err
+	})
+}
+
+// payerQuery seleciona as faturas da conta do pagador, inclusive excluídas, sem diferenciar maiúsculas
+func payerQuery(accountID, payerName string) bson.M {
+	return bson.M{
+		"account_id": accountID,
+		"payer_name": bson.M{"$regex": "^" + regexp.QuoteMeta(payerName) + "$", "$options": "i"},
+	}
+}
+
+// FindByPayer busca as faturas da conta do pagador, inclusive excluídas, sem diferenciar maiúsculas
+func (r *InvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) ([]*domain.Invoice, error) {
+	cursor, err := r.store.invoices.Find(ctx, payerQuery(accountID, payerName),
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	for cursor.Next(ctx) {
+		var doc invoiceDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		invoice, err := r.toInvoice(ctx, &doc)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, cursor.Err()
+}
+
+// AnonymizePayer troca o nome do pagador por token e apaga os últimos dígitos do cartão nas faturas da conta,
+// inclusive excluídas, reescrevendo os snapshots da auditoria delas na mesma transação
+func (r *InvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (int64, error) {
+	var count int64
+	err := r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		query := payerQuery(accountID, payerName)
+		ids, err := r.store.invoices.Distinct(tx, "_id", query)
+		if err != nil {
+			return err
+		}
+		count = int64(len(ids))
+		if count == 0 {
+			return nil
+		}
+
+		_, err = r.store.invoices.UpdateMany(tx, query, bson.M{
+			"$set": bson.M{"payer_name": token, "card_last_digits": "", "updated_at": time.Now()},
+		})
+		if err != nil {
+			return err
+		}
+
+		cursor, err := r.store.audit.Find(tx, bson.M{"entity": invoiceEntity, "entity_id": bson.M{"$in": ids}})
+		if err != nil {
+			return err
+		}
+		var entries []auditDocument
+		if err := cursor.All(tx, &entries); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			oldValue, oldChanged, err := anonymizeAuditValue(entry.OldValue, payerName, token)
+			if err != nil {
+				return err
+			}
+			newValue, newChanged, err := anonymizeAuditValue(entry.NewValue, payerName, token)
+			if err != nil {
+				return err
+			}
+			if !oldChanged && !newChanged {
+				continue
+			}
+
+			_, err = r.store.audit.UpdateByID(tx, entry.ID, bson.M{
+				"$set": bson.M{"old_value": oldValue, "new_value": newValue},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// anonymizeAuditValue aplica repository.AnonymizeInvoiceSnapshot ao snapshot serializado da auditoria
+func anonymizeAuditValue(value *string, payerName, token string) (*string, bool, error) {
+	if value == nil {
+		return nil, false, nil
+	}
+	anonymized, changed, err := repository.AnonymizeInvoiceSnapshot([]byte(*value), payerName, token)
+	if err != nil || !changed {
+		return value, false, err
+	}
+	encoded := string(anonymized)
+	return &encoded, true, nil
+}
+
+// SummarizeActivity conta por conta as faturas não excluídas criadas em [from, to) e quantas delas foram recusadas
+func (r *InvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) ([]domain.AccountActivity, error) {
+	cursor, err := r.store.invoices.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"created_at": bson.M{"$gte": from, "$lt": to},
+			"deleted_at": bson.M{"$exists": false},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$account_id",
+			"invoices": bson.M{"$sum": 1},
+			"declined": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", domain.StatusRejected}}, 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		AccountID string `bson:"_id"`
+		Invoices  int    `bson:"invoices"`
+		Declined  int    `bson:"declined"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	activity := make([]domain.AccountActivity, len(docs))
+	for i, doc := range docs {
+		activity[i] = domain.AccountActivity{AccountID: doc.AccountID, Invoices: doc.Invoices, Declined: doc.Declined}
+	}
+	return activity, nil
+}
+
+// SummarizeByStatus agrega por status as faturas não excluídas da conta criadas em [from, to)
+func (r *InvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) ([]domain.StatusTotals, error) {
+	cursor, err := r.store.invoices.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"account_id": accountID,
+			"created_at": bson.M{"$gte": from, "$lt": to},
+			"deleted_at": bson.M{"$exists": false},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$status",
+			"count":  bson.M{"$sum": 1},
+			"amount": bson.M{"$sum": "$amount"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Status domain.Status `bson:"_id"`
+		Count  int           `bson:"count"`
+		Amount float64       `bson:"amount"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	totals := make([]domain.StatusTotals, len(docs))
+	for i, doc := range docs {
+		totals[i] = domain.StatusTotals{Status: doc.Status, Count: doc.Count, Amount: doc.Amount}
+	}
+	return totals, nil
+}
+
+// SumSpending soma as faturas não recusadas e não excluídas da conta criadas desde dayStart e desde monthStart
+func (r *InvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (domain.SpendingTotals, error) {
+	cursor, err := r.store.invoices.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"account_id": accountID,
+			"created_at": bson.M{"$gte": monthStart},
+			"status":     bson.M{"$ne": domain.StatusRejected},
+			"deleted_at": bson.M{"$exists": false},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": nil,
+			"daily": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{"$created_at", dayStart}}, "$amount", 0,
+			}}},
+			"monthly": bson.M{"$sum": "$amount"},
+		}}},
+	})
+	if err != nil {
+		return domain.SpendingTotals{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Daily   float64 `bson:"daily"`
+		Monthly float64 `bson:"monthly"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil || len(docs) == 0 {
+		return domain.SpendingTotals{}, err
+	}
+	return domain.SpendingTotals{Daily: docs[0].Daily, Monthly: docs[0].Monthly}, nil
+}
+
+// CountDeleted conta as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.store.invoices.CountDocuments(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+}
+
+// PurgeDeleted remove definitivamente as faturas excluídas antes do instante informado
+func (r *InvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.store.invoices.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// lockInvoice trava a fatura ativa na transação de outro repositório, como a de um reembolso ou de uma disputa, com
+// um $inc em refund_lock, e retorna o estado dela; duas transações que travam a mesma fatura entram em conflito e
+// a repetida lê o estado de novo
+// Os dados cifrados do cartão ficam fora, porque a fatura só é usada nas conferências e no snapshot auditado
+// Retorna ErrInvoiceNotFound se a fatura não existir ou estiver excluída
+func (s *Store) lockInvoice(tx mongo.SessionContext, id string) (*domain.Invoice, error) {
+	var doc invoiceDocument
+	err := s.invoices.FindOneAndUpdate(tx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$inc": bson.M{"refund_lock": 1}},
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Invoice{
+		ID:          doc.ID,
+		AccountID:   doc.AccountID,
+		Amount:      doc.Amount,
+		Status:      doc.Status,
+		Description: doc.Description,
+		PaymentType: doc.PaymentType,
+		PayerName:   doc.PayerName,
+		Metadata:    doc.Metadata,
+		CreatedAt:   doc.CreatedAt,
+		UpdatedAt:   doc.UpdatedAt,
+	}, nil
+}
+
+// setInvoiceStatus passa a fatura travada por lockInvoice para status, registrando o estado anterior na auditoria
+// com o ID auditID
+func (s *Store) setInvoiceStatus(tx mongo.SessionContext, auditID int64, invoice *domain.Invoice, status domain.Status) error {
+	updated := *invoice
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	if err := s.writeAudit(tx, auditID, invoiceEntity, invoice.ID, domain.AuditActionUpdate, repository.NewInvoiceSnapshot(invoice), repository.NewInvoiceSnapshot(&updated)); err != nil {
+		return err
+	}
+	_, err := s.invoices.UpdateByID(tx, invoice.ID, bson.M{
+		"$set": bson.M{"status": updated.Status, "updated_at": updated.UpdatedAt},
+	})
+	return err
+}
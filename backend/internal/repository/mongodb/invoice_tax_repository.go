@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invoiceTaxDocument é um imposto de uma linha da fatura; a fatura, a linha e o nome formam o índice único
+type invoiceTaxDocument struct {
+	InvoiceID string    `bson:"invoice_id"`
+	Line      int       `bson:"line"`
+	Name      string    `bson:"name"`
+	Rate      float64   `bson:"rate"`
+	Amount    float64   `bson:"amount"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// InvoiceTaxRepository implementa domain.InvoiceTaxRepository no MongoDB
+type InvoiceTaxRepository struct {
+	store *Store
+}
+
+// NewInvoiceTaxRepository cria um repositório dos impostos das faturas sobre o armazenamento informado
+func NewInvoiceTaxRepository(store *Store) *InvoiceTaxRepository {
+	return &InvoiceTaxRepository{store: store}
+}
+
+// SaveBatch grava os impostos de uma ou mais faturas em um único InsertMany
+func (r *InvoiceTaxRepository) SaveBatch(ctx context.Context, taxes []*domain.InvoiceTax) error {
+	if len(taxes) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(taxes))
+	for i, tax := range taxes {
+		docs[i] = &invoiceTaxDocument{
+			InvoiceID: tax.InvoiceID,
+			Line:      tax.Line,
+			Name:      tax.Name,
+			Rate:      tax.Rate,
+			Amount:    tax.Amount,
+			CreatedAt: tax.CreatedAt,
+		}
+	}
+	_, err := r.store.invoiceTaxes.InsertMany(ctx, docs)
+	return err
+}
+
+// FindByInvoiceID retorna os impostos da fatura, ordenados pela linha e pelo nome, ou nenhum se ela não tem impostos
+func (r *InvoiceTaxRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceTax, error) {
+	cursor, err := r.store.invoiceTaxes.Find(ctx, bson.M{"invoice_id": invoiceID},
+		options.Find().SetSort(bson.D{{Key: "line", Value: 1}, {Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var taxes []*domain.InvoiceTax
+	for cursor.Next(ctx) {
+		var doc invoiceTaxDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, &domain.InvoiceTax{
+			InvoiceID: doc.InvoiceID,
+			Line:      doc.Line,
+			Name:      doc.Name,
+			Rate:      doc.Rate,
+			Amount:    doc.Amount,
+			CreatedAt: doc.CreatedAt,
+		})
+	}
+	return taxes, cursor.Err()
+}
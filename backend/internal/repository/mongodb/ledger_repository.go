@@ -0,0 +1,304 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ledgerDocument é um lançamento do razão armazenado
+type ledgerDocument struct {
+	ID             string                 `bson:"_id"`
+	AccountID      string                 `bson:"account_id"`
+	CounterpartyID string                 `bson:"counterparty_id"`
+	InvoiceID      string                 `bson:"invoice_id"`
+	Type           domain.LedgerEntryType `bson:"type"`
+	Amount         float64                `bson:"amount"`
+	CreatedAt      time.Time              `bson:"created_at"`
+}
+
+// LedgerRepository implementa domain.LedgerRepository no MongoDB
+type LedgerRepository struct {
+	store *Store
+}
+
+// NewLedgerRepository cria um repositório do razão sobre o armazenamento informado
+func NewLedgerRepository(store *Store) *LedgerRepository {
+	return &LedgerRepository{store: store}
+}
+
+// newLedgerDocument converte o lançamento no documento armazenado
+func newLedgerDocument(entry *domain.LedgerEntry) *ledgerDocument {
+	return &ledgerDocument{
+		ID:             entry.ID,
+		AccountID:      entry.AccountID,
+		CounterpartyID: entry.CounterpartyID,
+		InvoiceID:      entry.InvoiceID,
+		Type:           entry.Type,
+		Amount:         entry.Amount,
+		CreatedAt:      entry.CreatedAt,
+	}
+}
+
+// CreateBatch grava os lançamentos em um único InsertMany
+func (r *LedgerRepository) CreateBatch(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(entries))
+	for i, entry := range entries {
+		docs[i] = newLedgerDocument(entry)
+	}
+	_, err := r.store.ledger.InsertMany(ctx, docs)
+	return err
+}
+
+// Post grava o movimento em uma transação
+// Retorna ErrAccountNotFound se uma conta de Balances não existir, ErrEscrowAlreadyReleased se uma retenção de
+// Releases já tiver sido liberada e ErrInsufficientBalance se um débito Funded não couber no saldo
+func (r *LedgerRepository) Post(ctx context.Context, posting *domain.Posting) error {
+	var auditID int64
+	if n := postingAuditIDs(posting); n > 0 {
+		var err error
+		if auditID, err = r.store.reserveAuditIDs(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		return r.store.post(tx, posting, auditID)
+	})
+}
+
+// postingAuditIDs é quantos IDs da auditoria post pode usar: um por conta de Balances e um por fatura de Settles;
+// zero para um movimento nil
+func postingAuditIDs(posting *domain.Posting) int {
+	if posting == nil {
+		return 0
+	}
+	return len(posting.Balances) + len(posting.Settles())
+}
+
+// post grava o movimento na transação; os saldos recebem os valores com $inc e o estado anterior de cada conta vai
+// para a auditoria com os IDs reservados a partir de auditID, um por conta de Balances e depois um por fatura de
+// Settles, liquidada por settleInvoice
+func (s *Store) post(tx mongo.SessionContext, posting *domain.Posting, auditID int64) error {
+	if len(posting.Entries) > 0 {
+		docs := make([]any, len(posting.Entries))
+		for i, entry := range posting.Entries {
+			docs[i] = newLedgerDocument(entry)
+		}
+		if _, err := s.ledger.InsertMany(tx, docs); err != nil {
+			return err
+		}
+	}
+	if len(posting.Holds) > 0 {
+		docs := make([]any, len(posting.Holds))
+		for i, hold := range posting.Holds {
+			docs[i] = newEscrowDocument(hold)
+		}
+		if _, err := s.escrow.InsertMany(tx, docs); err != nil {
+			return err
+		}
+	}
+	for _, hold := range posting.Releases {
+		if err := releaseEscrowHold(tx, s, hold); err != nil {
+			return err
+		}
+	}
+
+	for i, accountID := range posting.AccountIDs() {
+		amount := posting.Balances[accountID]
+		updatedAt := time.Now()
+		filter := bson.M{"_id": accountID, "deleted_at": nil}
+		funded := posting.Funded && amount < 0
+		if funded {
+			// Meio centavo de tolerância para o arredondamento do saldo em ponto flutuante
+			filter["balance"] = bson.M{"$gte": -amount - 0.005}
+		}
+		var doc accountDocument
+		err := s.accounts.FindOneAndUpdate(tx,
+			filter,
+			bson.M{"$inc": bson.M{"balance": amount}, "$set": bson.M{"updated_at": updatedAt}},
+		).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if !funded {
+				return domain.ErrAccountNotFound
+			}
+			// Sem a conta no filtro do débito, falta saldo ou falta a conta
+			n, err := s.accounts.CountDocuments(tx, bson.M{"_id": accountID, "deleted_at": nil})
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				return domain.ErrInsufficientBalance
+			}
+			return domain.ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// O e-mail cifrado fica fora do snapshot auditado
+		current := repository.NewAccountSnapshot(&domain.Account{
+			ID:        doc.ID,
+			Name:      doc.Name,
+			Role:      domain.RoleOrDefault(doc.Role),
+			Scopes:    domain.ScopesOrDefault(doc.Scopes),
+			TenantID:  doc.TenantID,
+			Balance:   doc.Balance,
+			CreatedAt: doc.CreatedAt,
+			UpdatedAt: doc.UpdatedAt,
+		})
+		updated := *current
+		updated.Balance += amount
+		updated.UpdatedAt = updatedAt
+		if err := s.writeAudit(tx, auditID+int64(i), accountEntity, accountID, domain.AuditActionUpdate, current, &updated); err != nil {
+			return err
+		}
+	}
+
+	auditID += int64(len(posting.Balances))
+	for i, invoiceID := range posting.Settles() {
+		if err := s.settleInvoice(tx, auditID+int64(i), invoiceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleInvoice passa a fatura para StatusSettled se ela estiver aprovada e sem retenção retida
+// A fatura é travada por lockInvoice antes da contagem, para que duas liberações simultâneas da mesma fatura entrem em
+// conflito e a repetida conte de novo; a fatura excluída fica como está
+func (s *Store) settleInvoice(tx mongo.SessionContext, auditID int64, invoiceID string) error {
+	invoice, err := s.lockInvoice(tx, invoiceID)
+	if errors.Is(err, domain.ErrInvoiceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !invoice.Status.CanTransitionTo(domain.StatusSettled) {
+		return nil
+	}
+	held, err := s.escrow.CountDocuments(tx, bson.M{"invoice_id": invoiceID, "status": domain.EscrowHeld})
+	if err != nil || held > 0 {
+		return err
+	}
+	return s.setInvoiceStatus(tx, auditID, invoice, domain.StatusSettled)
+}
+
+// List retorna os lançamentos da conta criados em [from, to), dos mais antigos para os mais novos
+func (r *LedgerRepository) List(ctx context.Context, accountID string, from, to time.Time) ([]*domain.LedgerEntry, error) {
+	cursor, err := r.store.ledger.Find(ctx,
+		bson.M{"account_id": accountID, "created_at": bson.M{"$gte": from, "$lt": to}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.LedgerEntry
+	for cursor.Next(ctx) {
+		var doc ledgerDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &domain.LedgerEntry{
+			ID:             doc.ID,
+			AccountID:      doc.AccountID,
+			CounterpartyID: doc.CounterpartyID,
+			InvoiceID:      doc.InvoiceID,
+			Type:           doc.Type,
+			Amount:         doc.Amount,
+			CreatedAt:      doc.CreatedAt,
+		})
+	}
+	return entries, cursor.Err()
+}
+
+// SumByType soma os lançamentos da conta por tipo; os tipos sem lançamentos ficam fora do mapa
+func (r *LedgerRepository) SumByType(ctx context.Context, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	return r.store.sumLedgerByType(ctx, accountID)
+}
+
+// sumLedgerByType soma os lançamentos da conta por tipo com uma agregação
+func (s *Store) sumLedgerByType(ctx context.Context, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	cursor, err := s.ledger.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"account_id": accountID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$type", "total": bson.M{"$sum": "$amount"}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Type  domain.LedgerEntryType `bson:"_id"`
+		Total float64                `bson:"total"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	sums := make(map[domain.LedgerEntryType]float64, len(docs))
+	for _, doc := range docs {
+		sums[doc.Type] = doc.Total
+	}
+	return sums, nil
+}
+
+// Reconcile lê o saldo e soma o razão no mesmo snapshot de uma transação; com write, a conta é travada antes com um
+// $inc em balance_lock, para que um movimento simultâneo entre em conflito com a gravação e seja refeito
+// Retorna ErrAccountNotFound se a conta não existir
+func (r *LedgerRepository) Reconcile(ctx context.Context, accountID string, write, correct bool) (*domain.BalanceReconciliation, error) {
+	var auditID int64
+	if write && correct {
+		var err error
+		if auditID, err = r.store.reserveAuditIDs(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	var reconciliation *domain.BalanceReconciliation
+	err := r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		filter := bson.M{"_id": accountID, "deleted_at": nil}
+		var doc accountDocument
+		var err error
+		if write {
+			err = r.store.accounts.FindOneAndUpdate(tx, filter, bson.M{"$inc": bson.M{"balance_lock": 1}}).Decode(&doc)
+		} else {
+			err = r.store.accounts.FindOne(tx, filter).Decode(&doc)
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		sums, err := r.store.sumLedgerByType(tx, accountID)
+		if err != nil {
+			return err
+		}
+
+		reconciliation = domain.NewBalanceReconciliation(&domain.Account{ID: doc.ID, Balance: doc.Balance}, sums)
+		if !write {
+			return nil
+		}
+		if posting := reconciliation.Settle(correct); posting != nil {
+			return r.store.post(tx, posting, auditID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reconciliation, nil
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// payoutScheduleDocument é o prazo de repasse armazenado, identificado pela conta
+type payoutScheduleDocument struct {
+	AccountID string    `bson:"_id"`
+	DelayDays int       `bson:"delay_days"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// PayoutScheduleRepository implementa domain.PayoutScheduleRepository no MongoDB
+type PayoutScheduleRepository struct {
+	store *Store
+}
+
+// NewPayoutScheduleRepository cria um repositório de prazos de repasse sobre o armazenamento informado
+func NewPayoutScheduleRepository(store *Store) *PayoutScheduleRepository {
+	return &PayoutScheduleRepository{store: store}
+}
+
+// Save substitui o prazo da conta
+func (r *PayoutScheduleRepository) Save(ctx context.Context, schedule *domain.PayoutSchedule) error {
+	_, err := r.store.payoutSchedules.ReplaceOne(ctx, bson.M{"_id": schedule.AccountID}, &payoutScheduleDocument{
+		AccountID: schedule.AccountID,
+		DelayDays: schedule.DelayDays,
+		UpdatedAt: schedule.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca o prazo da conta
+// Retorna ErrPayoutScheduleNotFound se a conta usa o prazo padrão
+func (r *PayoutScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PayoutSchedule, error) {
+	var doc payoutScheduleDocument
+	if err := r.store.payoutSchedules.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrPayoutScheduleNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.PayoutSchedule{
+		AccountID: doc.AccountID,
+		DelayDays: doc.DelayDays,
+		UpdatedAt: doc.UpdatedAt,
+	}, nil
+}
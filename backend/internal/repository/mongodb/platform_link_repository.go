@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// platformLinkDocument é a ligação armazenada, identificada pela conta filha
+type platformLinkDocument struct {
+	AccountID         string    `bson:"_id"`
+	PlatformID        string    `bson:"platform_id"`
+	CommissionPercent float64   `bson:"commission_percent"`
+	CreatedAt         time.Time `bson:"created_at"`
+	UpdatedAt         time.Time `bson:"updated_at"`
+}
+
+// PlatformLinkRepository implementa domain.PlatformLinkRepository no MongoDB
+type PlatformLinkRepository struct {
+	store *Store
+}
+
+// NewPlatformLinkRepository cria um repositório de ligações com plataformas sobre o armazenamento informado
+func NewPlatformLinkRepository(store *Store) *PlatformLinkRepository {
+	return &PlatformLinkRepository{store: store}
+}
+
+// Save substitui a ligação da conta
+func (r *PlatformLinkRepository) Save(ctx context.Context, link *domain.PlatformLink) error {
+	_, err := r.store.platformLinks.ReplaceOne(ctx, bson.M{"_id": link.AccountID}, &platformLinkDocument{
+		AccountID:         link.AccountID,
+		PlatformID:        link.PlatformID,
+		CommissionPercent: link.CommissionPercent,
+		CreatedAt:         link.CreatedAt,
+		UpdatedAt:         link.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca a ligação da conta
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (r *PlatformLinkRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PlatformLink, error) {
+	var doc platformLinkDocument
+	if err := r.store.platformLinks.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrPlatformLinkNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.PlatformLink{
+		AccountID:         doc.AccountID,
+		PlatformID:        doc.PlatformID,
+		CommissionPercent: doc.CommissionPercent,
+		CreatedAt:         doc.CreatedAt,
+		UpdatedAt:         doc.UpdatedAt,
+	}, nil
+}
+
+// CountByPlatformID retorna quantas contas são filhas da plataforma
+func (r *PlatformLinkRepository) CountByPlatformID(ctx context.Context, platformID string) (int64, error) {
+	return r.store.platformLinks.CountDocuments(ctx, bson.M{"platform_id": platformID})
+}
+
+// Delete remove a ligação da conta
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (r *PlatformLinkRepository) Delete(ctx context.Context, accountID string) error {
+	result, err := r.store.platformLinks.DeleteOne(ctx, bson.M{"_id": accountID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrPlatformLinkNotFound
+	}
+	return nil
+}
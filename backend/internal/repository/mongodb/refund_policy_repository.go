@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refundPolicyDocument é a política de reembolso armazenada, identificada pela conta
+type refundPolicyDocument struct {
+	AccountID         string    `bson:"_id"`
+	ApprovalThreshold float64   `bson:"approval_threshold"`
+	UpdatedAt         time.Time `bson:"updated_at"`
+}
+
+// RefundPolicyRepository implementa domain.RefundPolicyRepository no MongoDB
+type RefundPolicyRepository struct {
+	store *Store
+}
+
+// NewRefundPolicyRepository cria um repositório de políticas de reembolso sobre o armazenamento informado
+func NewRefundPolicyRepository(store *Store) *RefundPolicyRepository {
+	return &RefundPolicyRepository{store: store}
+}
+
+// Save substitui a política da conta
+func (r *RefundPolicyRepository) Save(ctx context.Context, policy *domain.RefundPolicy) error {
+	_, err := r.store.refundPolicies.ReplaceOne(ctx, bson.M{"_id": policy.AccountID}, &refundPolicyDocument{
+		AccountID:         policy.AccountID,
+		ApprovalThreshold: policy.ApprovalThreshold,
+		UpdatedAt:         policy.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca a política da conta
+// Retorna ErrRefundPolicyNotFound se a conta não exigir aprovação
+func (r *RefundPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RefundPolicy, error) {
+	var doc refundPolicyDocument
+	if err := r.store.refundPolicies.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRefundPolicyNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.RefundPolicy{
+		AccountID:         doc.AccountID,
+		ApprovalThreshold: doc.ApprovalThreshold,
+		UpdatedAt:         doc.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,245 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refundDocument é um reembolso armazenado
+type refundDocument struct {
+	ID          string              `bson:"_id"`
+	InvoiceID   string              `bson:"invoice_id"`
+	AccountID   string              `bson:"account_id"`
+	Amount      float64             `bson:"amount"`
+	Reason      string              `bson:"reason"`
+	Status      domain.RefundStatus `bson:"status"`
+	RequestedBy string              `bson:"requested_by"`
+	DecidedBy   string              `bson:"decided_by"`
+	DecidedAt   *time.Time          `bson:"decided_at,omitempty"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at"`
+}
+
+func (d *refundDocument) toDomain() *domain.Refund {
+	return &domain.Refund{
+		ID:          d.ID,
+		InvoiceID:   d.InvoiceID,
+		AccountID:   d.AccountID,
+		Amount:      d.Amount,
+		Reason:      d.Reason,
+		Status:      d.Status,
+		RequestedBy: d.RequestedBy,
+		DecidedBy:   d.DecidedBy,
+		DecidedAt:   d.DecidedAt,
+		ExpiresAt:   d.ExpiresAt,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+// RefundRepository implementa domain.RefundRepository no MongoDB
+type RefundRepository struct {
+	store *Store
+}
+
+// NewRefundRepository cria um repositório de reembolsos sobre o armazenamento informado
+func NewRefundRepository(store *Store) *RefundRepository {
+	return &RefundRepository{store: store}
+}
+
+// refundAuditIDs reserva os IDs da auditoria de Create e Decide: os de postingAuditIDs para o movimento e um para
+// a fatura que passa para StatusRefunded
+func (r *RefundRepository) refundAuditIDs(ctx context.Context, posting *domain.Posting) (int64, error) {
+	return r.store.reserveAuditIDs(ctx, 1+postingAuditIDs(posting))
+}
+
+// Create grava o reembolso e, se posting não for nil, o movimento do débito em uma transação
+// A fatura é travada com um $inc em refund_lock antes de somar os reembolsos, para que dois pedidos simultâneos
+// entrem em conflito e a transação repetida confira o limite de novo
+// Retorna ErrInvoiceNotFound se a fatura não existir e os erros de Refund.CheckRefundable
+func (r *RefundRepository) Create(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	auditID, err := r.refundAuditIDs(ctx, posting)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		invoice, err := r.store.lockInvoice(tx, refund.InvoiceID)
+		if err != nil {
+			return err
+		}
+		refunded, err := r.sum(tx, refund.InvoiceID, domain.RefundCompleted, domain.RefundPending)
+		if err != nil {
+			return err
+		}
+		if err := refund.CheckRefundable(invoice, refunded); err != nil {
+			return err
+		}
+
+		_, err = r.store.refunds.InsertOne(tx, &refundDocument{
+			ID:          refund.ID,
+			InvoiceID:   refund.InvoiceID,
+			AccountID:   refund.AccountID,
+			Amount:      refund.Amount,
+			Reason:      refund.Reason,
+			Status:      refund.Status,
+			RequestedBy: refund.RequestedBy,
+			DecidedBy:   refund.DecidedBy,
+			DecidedAt:   refund.DecidedAt,
+			ExpiresAt:   refund.ExpiresAt,
+			CreatedAt:   refund.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return r.complete(tx, invoice, refund, posting, auditID)
+	})
+}
+
+// FindByID busca o reembolso pelo ID
+// Retorna ErrRefundNotFound se o reembolso não existir
+func (r *RefundRepository) FindByID(ctx context.Context, id string) (*domain.Refund, error) {
+	var doc refundDocument
+	if err := r.store.refunds.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna até limit reembolsos da conta na situação informada, ou em todas com status vazio, dos mais novos
+// para os mais antigos
+func (r *RefundRepository) List(ctx context.Context, accountID string, status domain.RefundStatus, limit int) ([]*domain.Refund, error) {
+	filter := bson.M{"account_id": accountID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// ListExpired retorna até limit reembolsos pendentes com prazo até before, dos que venceram primeiro
+func (r *RefundRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.Refund, error) {
+	return r.find(ctx, bson.M{"status": domain.RefundPending, "expires_at": bson.M{"$lte": before}}, options.Find().
+		SetSort(bson.D{{Key: "expires_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// Decide grava a decisão se o reembolso ainda estiver pendente e, se posting não for nil, o movimento do débito em
+// uma transação; na aprovação a fatura é travada e precisa continuar aprovada
+// Retorna ErrRefundAlreadyDecided quando outra decisão chegou antes e ErrInvalidStatus se a fatura aprovada no
+// pedido foi estornada depois
+func (r *RefundRepository) Decide(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	var auditID int64
+	if refund.Status == domain.RefundCompleted {
+		var err error
+		if auditID, err = r.refundAuditIDs(ctx, posting); err != nil {
+			return err
+		}
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		var invoice *domain.Invoice
+		if refund.Status == domain.RefundCompleted {
+			var err error
+			if invoice, err = r.store.lockInvoice(tx, refund.InvoiceID); err != nil {
+				return err
+			}
+			if !invoice.Status.IsPaid() {
+				return domain.ErrInvalidStatus
+			}
+		}
+
+		result, err := r.store.refunds.UpdateOne(tx,
+			bson.M{"_id": refund.ID, "status": domain.RefundPending},
+			bson.M{"$set": bson.M{"status": refund.Status, "decided_by": refund.DecidedBy, "decided_at": refund.DecidedAt}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrRefundAlreadyDecided
+		}
+		if invoice == nil {
+			return nil
+		}
+		return r.complete(tx, invoice, refund, posting, auditID)
+	})
+}
+
+// SumByInvoiceID soma os reembolsos concluídos e pendentes da fatura
+func (r *RefundRepository) SumByInvoiceID(ctx context.Context, invoiceID string) (float64, error) {
+	return r.sum(ctx, invoiceID, domain.RefundCompleted, domain.RefundPending)
+}
+
+// sum soma os reembolsos da fatura nas situações informadas
+func (r *RefundRepository) sum(ctx context.Context, invoiceID string, statuses ...domain.RefundStatus) (float64, error) {
+	cursor, err := r.store.refunds.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"invoice_id": invoiceID, "status": bson.M{"$in": statuses}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return 0, err
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	return docs[0].Total, nil
+}
+
+// complete grava o movimento do reembolso concluído e, se os concluídos já cobrem a fatura travada, passa a fatura
+// para StatusRefunded registrando o estado anterior na auditoria
+// O movimento usa os IDs da auditoria a partir de auditID e a fatura, o seguinte; não faz nada para um reembolso
+// pendente
+func (r *RefundRepository) complete(tx mongo.SessionContext, invoice *domain.Invoice, refund *domain.Refund, posting *domain.Posting, auditID int64) error {
+	if refund.Status != domain.RefundCompleted {
+		return nil
+	}
+	if posting != nil {
+		if err := r.store.post(tx, posting, auditID); err != nil {
+			return err
+		}
+		auditID += int64(postingAuditIDs(posting))
+	}
+
+	completed, err := r.sum(tx, invoice.ID, domain.RefundCompleted)
+	if err != nil || !domain.FullyRefunded(invoice, completed) {
+		return err
+	}
+	return r.store.setInvoiceStatus(tx, auditID, invoice, domain.StatusRefunded)
+}
+
+// find retorna os reembolsos que atendem ao filtro
+func (r *RefundRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.Refund, error) {
+	cursor, err := r.store.refunds.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var refunds []*domain.Refund
+	for cursor.Next(ctx) {
+		var doc refundDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, doc.toDomain())
+	}
+	return refunds, cursor.Err()
+}
@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refundWindowDocument é o prazo de reembolso armazenado, identificado pela conta
+type refundWindowDocument struct {
+	AccountID string    `bson:"_id"`
+	Days      int       `bson:"days"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// RefundWindowRepository implementa domain.AccountRefundWindowRepository no MongoDB
+type RefundWindowRepository struct {
+	store *Store
+}
+
+// NewRefundWindowRepository cria um repositório de prazos de reembolso sobre o armazenamento informado
+func NewRefundWindowRepository(store *Store) *RefundWindowRepository {
+	return &RefundWindowRepository{store: store}
+}
+
+// Save substitui o prazo da conta
+func (r *RefundWindowRepository) Save(ctx context.Context, window *domain.AccountRefundWindow) error {
+	_, err := r.store.refundWindows.ReplaceOne(ctx, bson.M{"_id": window.AccountID}, &refundWindowDocument{
+		AccountID: window.AccountID,
+		Days:      window.Days,
+		UpdatedAt: window.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca o prazo da conta
+// Retorna ErrRefundWindowNotFound se a conta usa o prazo padrão
+func (r *RefundWindowRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountRefundWindow, error) {
+	var doc refundWindowDocument
+	if err := r.store.refundWindows.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRefundWindowNotFound
+		}
+		return nil, err
+	}
+	return &domain.AccountRefundWindow{AccountID: doc.AccountID, Days: doc.Days, UpdatedAt: doc.UpdatedAt}, nil
+}
+
+// Delete remove o prazo da conta
+func (r *RefundWindowRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.store.refundWindows.DeleteOne(ctx, bson.M{"_id": accountID})
+	return err
+}
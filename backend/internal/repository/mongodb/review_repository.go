@@ -0,0 +1,139 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reviewDocument é a revisão armazenada, identificada pela fatura
+type reviewDocument struct {
+	InvoiceID string              `bson:"_id"`
+	AccountID string              `bson:"account_id"`
+	Amount    float64             `bson:"amount"`
+	Score     float64             `bson:"score"`
+	Status    domain.ReviewStatus `bson:"status"`
+	DueAt     time.Time           `bson:"due_at"`
+	DecidedBy string              `bson:"decided_by"`
+	DecidedAt *time.Time          `bson:"decided_at,omitempty"`
+	CreatedAt time.Time           `bson:"created_at"`
+}
+
+func (d *reviewDocument) toDomain() *domain.InvoiceReview {
+	return &domain.InvoiceReview{
+		InvoiceID: d.InvoiceID,
+		AccountID: d.AccountID,
+		Amount:    d.Amount,
+		Score:     d.Score,
+		Status:    d.Status,
+		DueAt:     d.DueAt,
+		DecidedBy: d.DecidedBy,
+		DecidedAt: d.DecidedAt,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+// ReviewRepository implementa domain.ReviewRepository no MongoDB
+type ReviewRepository struct {
+	store *Store
+}
+
+// NewReviewRepository cria um repositório da fila de revisão sobre o armazenamento informado
+func NewReviewRepository(store *Store) *ReviewRepository {
+	return &ReviewRepository{store: store}
+}
+
+// Create abre a revisão da fatura
+// Retorna ErrReviewExists se a fatura já tiver revisão
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.InvoiceReview) error {
+	_, err := r.store.reviews.InsertOne(ctx, &reviewDocument{
+		InvoiceID: review.InvoiceID,
+		AccountID: review.AccountID,
+		Amount:    review.Amount,
+		Score:     review.Score,
+		Status:    review.Status,
+		DueAt:     review.DueAt,
+		DecidedBy: review.DecidedBy,
+		DecidedAt: review.DecidedAt,
+		CreatedAt: review.CreatedAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrReviewExists
+	}
+	return err
+}
+
+// FindByInvoiceID busca a revisão da fatura
+// Retorna ErrReviewNotFound se a fatura não tiver revisão
+func (r *ReviewRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (*domain.InvoiceReview, error) {
+	var doc reviewDocument
+	if err := r.store.reviews.FindOne(ctx, bson.M{"_id": invoiceID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna até limit revisões da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais antigas para as mais novas
+func (r *ReviewRepository) List(ctx context.Context, accountID string, status domain.ReviewStatus, limit int) ([]*domain.InvoiceReview, error) {
+	filter := bson.M{}
+	if accountID != "" {
+		filter["account_id"] = accountID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// ListDue retorna até limit revisões abertas com prazo até before, das que venceram primeiro
+func (r *ReviewRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.InvoiceReview, error) {
+	return r.find(ctx, bson.M{"status": domain.ReviewOpen, "due_at": bson.M{"$lte": before}}, options.Find().
+		SetSort(bson.D{{Key: "due_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// Decide grava a decisão se a revisão ainda estiver aberta
+// Retorna ErrReviewAlreadyDecided quando outra decisão chegou antes
+func (r *ReviewRepository) Decide(ctx context.Context, review *domain.InvoiceReview) error {
+	result, err := r.store.reviews.UpdateOne(ctx,
+		bson.M{"_id": review.InvoiceID, "status": domain.ReviewOpen},
+		bson.M{"$set": bson.M{"status": review.Status, "decided_by": review.DecidedBy, "decided_at": review.DecidedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrReviewAlreadyDecided
+	}
+	return nil
+}
+
+// find retorna as revisões que atendem ao filtro
+func (r *ReviewRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.InvoiceReview, error) {
+	cursor, err := r.store.reviews.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*domain.InvoiceReview
+	for cursor.Next(ctx) {
+		var doc reviewDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, doc.toDomain())
+	}
+	return reviews, cursor.Err()
+}
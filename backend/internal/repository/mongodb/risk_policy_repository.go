@@ -0,0 +1,60 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// riskPolicyDocument são os limites de score armazenados, identificados pela conta
+type riskPolicyDocument struct {
+	AccountID    string    `bson:"_id"`
+	ApproveBelow float64   `bson:"approve_below"`
+	RejectAbove  float64   `bson:"reject_above"`
+	UpdatedAt    time.Time `bson:"updated_at"`
+}
+
+// RiskPolicyRepository implementa domain.RiskPolicyRepository no MongoDB
+type RiskPolicyRepository struct {
+	store *Store
+}
+
+// NewRiskPolicyRepository cria um repositório de limites de score sobre o armazenamento informado
+func NewRiskPolicyRepository(store *Store) *RiskPolicyRepository {
+	return &RiskPolicyRepository{store: store}
+}
+
+// Save substitui os limites da conta
+func (r *RiskPolicyRepository) Save(ctx context.Context, policy *domain.RiskPolicy) error {
+	_, err := r.store.riskPolicies.ReplaceOne(ctx, bson.M{"_id": policy.AccountID}, &riskPolicyDocument{
+		AccountID:    policy.AccountID,
+		ApproveBelow: policy.ApproveBelow,
+		RejectAbove:  policy.RejectAbove,
+		UpdatedAt:    policy.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca os limites da conta
+// Retorna ErrRiskPolicyNotFound se a conta usar os limites padrão
+func (r *RiskPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RiskPolicy, error) {
+	var doc riskPolicyDocument
+	if err := r.store.riskPolicies.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRiskPolicyNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.RiskPolicy{
+		AccountID:    doc.AccountID,
+		ApproveBelow: doc.ApproveBelow,
+		RejectAbove:  doc.RejectAbove,
+		UpdatedAt:    doc.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// securityWebhookDocument é a assinatura de segurança armazenada, identificada pela conta
+type securityWebhookDocument struct {
+	AccountID string                     `bson:"_id"`
+	URL       string                     `bson:"url"`
+	Events    []domain.SecurityEventType `bson:"events"`
+	UpdatedAt time.Time                  `bson:"updated_at"`
+}
+
+// SecurityWebhookRepository implementa domain.SecurityWebhookRepository no MongoDB
+type SecurityWebhookRepository struct {
+	store *Store
+}
+
+// NewSecurityWebhookRepository cria um repositório de assinaturas de segurança sobre o armazenamento informado
+func NewSecurityWebhookRepository(store *Store) *SecurityWebhookRepository {
+	return &SecurityWebhookRepository{store: store}
+}
+
+// Save substitui a assinatura da conta
+func (r *SecurityWebhookRepository) Save(ctx context.Context, webhook *domain.SecurityWebhook) error {
+	_, err := r.store.securityWebhooks.ReplaceOne(ctx, bson.M{"_id": webhook.AccountID}, &securityWebhookDocument{
+		AccountID: webhook.AccountID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		UpdatedAt: webhook.UpdatedAt,
+	}, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindByAccountID busca a assinatura da conta
+// Retorna ErrSecurityWebhookNotFound se a conta não tiver assinatura
+func (r *SecurityWebhookRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.SecurityWebhook, error) {
+	var doc securityWebhookDocument
+	if err := r.store.securityWebhooks.FindOne(ctx, bson.M{"_id": accountID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSecurityWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.SecurityWebhook{
+		AccountID: doc.AccountID,
+		URL:       doc.URL,
+		Events:    doc.Events,
+		UpdatedAt: doc.UpdatedAt,
+	}, nil
+}
+
+// Delete remove a assinatura da conta
+// Retorna ErrSecurityWebhookNotFound se a conta não tiver assinatura
+func (r *SecurityWebhookRepository) Delete(ctx context.Context, accountID string) error {
+	result, err := r.store.securityWebhooks.DeleteOne(ctx, bson.M{"_id": accountID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSecurityWebhookNotFound
+	}
+	return nil
+}
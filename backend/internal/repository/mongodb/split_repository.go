@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// splitDocument é a parte de uma fatura dividida; a fatura e a recebedora formam o índice único
+type splitDocument struct {
+	InvoiceID   string    `bson:"invoice_id"`
+	RecipientID string    `bson:"recipient_id"`
+	Amount      float64   `bson:"amount"`
+	Fee         float64   `bson:"fee"`
+	CreatedAt   time.Time `bson:"created_at"`
+}
+
+// SplitRepository implementa domain.SplitRepository no MongoDB
+type SplitRepository struct {
+	store *Store
+}
+
+// NewSplitRepository cria um repositório das partes das faturas sobre o armazenamento informado
+func NewSplitRepository(store *Store) *SplitRepository {
+	return &SplitRepository{store: store}
+}
+
+// SaveBatch grava as partes de uma ou mais faturas em um único InsertMany
+func (r *SplitRepository) SaveBatch(ctx context.Context, splits []*domain.InvoiceSplit) error {
+	return r.store.insertSplits(ctx, splits)
+}
+
+// insertSplits grava as partes em um único InsertMany, também dentro da transação de InvoiceRepository.Save
+func (s *Store) insertSplits(ctx context.Context, splits []*domain.InvoiceSplit) error {
+	if len(splits) == 0 {
+		return nil
+	}
+
+	docs := make([]any, len(splits))
+	for i, split := range splits {
+		docs[i] = &splitDocument{
+			InvoiceID:   split.InvoiceID,
+			RecipientID: split.RecipientID,
+			Amount:      split.Amount,
+			Fee:         split.Fee,
+			CreatedAt:   split.CreatedAt,
+		}
+	}
+	_, err := s.splits.InsertMany(ctx, docs)
+	return err
+}
+
+// FindByInvoiceID retorna as partes da fatura, ordenadas pela conta recebedora, ou nenhuma se ela não foi dividida
+func (r *SplitRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceSplit, error) {
+	cursor, err := r.store.splits.Find(ctx, bson.M{"invoice_id": invoiceID},
+		options.Find().SetSort(bson.D{{Key: "recipient_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var splits []*domain.InvoiceSplit
+	for cursor.Next(ctx) {
+		var doc splitDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		splits = append(splits, &domain.InvoiceSplit{
+			InvoiceID:   doc.InvoiceID,
+			RecipientID: doc.RecipientID,
+			Amount:      doc.Amount,
+			Fee:         doc.Fee,
+			CreatedAt:   doc.CreatedAt,
+		})
+	}
+	return splits, cursor.Err()
+}
@@ -0,0 +1,209 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// standingOrderDocument é uma ordem permanente armazenada
+type standingOrderDocument struct {
+	ID            string                     `bson:"_id"`
+	AccountID     string                     `bson:"account_id"`
+	DestinationID string                     `bson:"destination_id"`
+	Percent       float64                    `bson:"percent"`
+	Amount        float64                    `bson:"amount"`
+	IntervalDays  int                        `bson:"interval_days"`
+	Status        domain.StandingOrderStatus `bson:"status"`
+	WebhookURL    string                     `bson:"webhook_url"`
+	NextRunAt     *time.Time                 `bson:"next_run_at,omitempty"`
+	LastRunAt     *time.Time                 `bson:"last_run_at,omitempty"`
+	LastAmount    float64                    `bson:"last_amount"`
+	FailedRuns    int                        `bson:"failed_runs"`
+	LastFailure   string                     `bson:"last_failure"`
+	CancelledAt   *time.Time                 `bson:"cancelled_at,omitempty"`
+	CreatedAt     time.Time                  `bson:"created_at"`
+	UpdatedAt     time.Time                  `bson:"updated_at"`
+}
+
+func (d *standingOrderDocument) toDomain() *domain.StandingOrder {
+	return &domain.StandingOrder{
+		ID:            d.ID,
+		AccountID:     d.AccountID,
+		DestinationID: d.DestinationID,
+		Percent:       d.Percent,
+		Amount:        d.Amount,
+		IntervalDays:  d.IntervalDays,
+		Status:        d.Status,
+		WebhookURL:    d.WebhookURL,
+		NextRunAt:     d.NextRunAt,
+		LastRunAt:     d.LastRunAt,
+		LastAmount:    d.LastAmount,
+		FailedRuns:    d.FailedRuns,
+		LastFailure:   d.LastFailure,
+		CancelledAt:   d.CancelledAt,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+// StandingOrderRepository implementa domain.StandingOrderRepository no MongoDB
+type StandingOrderRepository struct {
+	store *Store
+}
+
+// NewStandingOrderRepository cria um repositório de ordens permanentes sobre o armazenamento informado
+func NewStandingOrderRepository(store *Store) *StandingOrderRepository {
+	return &StandingOrderRepository{store: store}
+}
+
+// Create grava a ordem
+func (r *StandingOrderRepository) Create(ctx context.Context, order *domain.StandingOrder) error {
+	_, err := r.store.standingOrders.InsertOne(ctx, &standingOrderDocument{
+		ID:            order.ID,
+		AccountID:     order.AccountID,
+		DestinationID: order.DestinationID,
+		Percent:       order.Percent,
+		Amount:        order.Amount,
+		IntervalDays:  order.IntervalDays,
+		Status:        order.Status,
+		WebhookURL:    order.WebhookURL,
+		NextRunAt:     order.NextRunAt,
+		LastRunAt:     order.LastRunAt,
+		LastAmount:    order.LastAmount,
+		FailedRuns:    order.FailedRuns,
+		LastFailure:   order.LastFailure,
+		CancelledAt:   order.CancelledAt,
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+	})
+	return err
+}
+
+// FindByID busca a ordem pelo ID
+// Retorna ErrStandingOrderNotFound se a ordem não existir
+func (r *StandingOrderRepository) FindByID(ctx context.Context, id string) (*domain.StandingOrder, error) {
+	var doc standingOrderDocument
+	if err := r.store.standingOrders.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrStandingOrderNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna até limit ordens da conta na situação informada, ou em todas com status vazio, das mais novas para
+// as mais antigas
+func (r *StandingOrderRepository) List(ctx context.Context, accountID string, status domain.StandingOrderStatus, limit int) ([]*domain.StandingOrder, error) {
+	filter := bson.M{"account_id": accountID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// ListDue retorna até limit ordens ativas com transferência até before, das mais atrasadas primeiro
+func (r *StandingOrderRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.StandingOrder, error) {
+	return r.find(ctx, bson.M{"status": domain.StandingOrderActive, "next_run_at": bson.M{"$lte": before}}, options.Find().
+		SetSort(bson.D{{Key: "next_run_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// Update grava a ordem se a situação gravada ainda for from
+// Retorna ErrStandingOrderChanged quando ela mudou antes
+func (r *StandingOrderRepository) Update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	return r.update(ctx, order, from)
+}
+
+// Run executa a ordem ativa sobre o saldo da conta em uma transação; a conta é travada antes com um $inc em
+// balance_lock, para que um débito simultâneo entre em conflito com a transferência e seja refeito sobre o saldo
+// que sobrou
+// Retorna ErrStandingOrderChanged quando ela deixou de estar ativa e ErrAccountNotFound sem a conta
+func (r *StandingOrderRepository) Run(ctx context.Context, order *domain.StandingOrder, now time.Time) error {
+	// Os IDs são os das duas contas da transferência; uma execução sem movimento perde os dois
+	auditID, err := r.store.reserveAuditIDs(ctx, 2)
+	if err != nil {
+		return err
+	}
+
+	var run domain.StandingOrder
+	err = r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		var doc accountDocument
+		err := r.store.accounts.FindOneAndUpdate(tx,
+			bson.M{"_id": order.AccountID, "deleted_at": nil},
+			bson.M{"$inc": bson.M{"balance_lock": 1}},
+		).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		run = *order
+		posting := run.Run(doc.Balance, now)
+		if err := r.update(tx, &run, domain.StandingOrderActive); err != nil {
+			return err
+		}
+		if posting == nil {
+			return nil
+		}
+		return r.store.post(tx, posting, auditID)
+	})
+	if err != nil {
+		return err
+	}
+	*order = run
+	return nil
+}
+
+// update grava a execução ou o cancelamento da ordem se a situação gravada ainda for from
+func (r *StandingOrderRepository) update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	result, err := r.store.standingOrders.UpdateOne(ctx,
+		bson.M{"_id": order.ID, "status": from},
+		bson.M{"$set": bson.M{
+			"status":       order.Status,
+			"next_run_at":  order.NextRunAt,
+			"last_run_at":  order.LastRunAt,
+			"last_amount":  order.LastAmount,
+			"failed_runs":  order.FailedRuns,
+			"last_failure": order.LastFailure,
+			"cancelled_at": order.CancelledAt,
+			"updated_at":   order.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrStandingOrderChanged
+	}
+	return nil
+}
+
+// find retorna as ordens que atendem ao filtro
+func (r *StandingOrderRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.StandingOrder, error) {
+	cursor, err := r.store.standingOrders.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []*domain.StandingOrder
+	for cursor.Next(ctx) {
+		var doc standingOrderDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		orders = append(orders, doc.toDomain())
+	}
+	return orders, cursor.Err()
+}
@@ -0,0 +1,453 @@
+// Package mongodb implementa os repositórios do domínio sobre o MongoDB
+// As mutações e suas entradas de auditoria são gravadas em transações, que exigem um replica set
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redact"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// auditCounterID identifica em counters a sequência dos IDs da auditoria
+const auditCounterID = "audit_log"
+
+// Connect conecta ao MongoDB e valida a conexão com um ping
+func Connect(ctx context.Context, uri string) (*mongo.Client, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Store agrupa as coleções compartilhadas pelos repositórios MongoDB
+type Store struct {
+	client              *mongo.Client
+	accounts            *mongo.Collection
+	invoices            *mongo.Collection
+	users               *mongo.Collection
+	tokens              *mongo.Collection
+	audit               *mongo.Collection
+	authEvents          *mongo.Collection
+	cards               *mongo.Collection
+	twoFactors          *mongo.Collection
+	geoPolicies         *mongo.Collection
+	dataSubjectRequests *mongo.Collection
+	exports             *mongo.Collection
+	securityWebhooks    *mongo.Collection
+	alertRules          *mongo.Collection
+	blocklist           *mongo.Collection
+	riskPolicies        *mongo.Collection
+	reviews             *mongo.Collection
+	splits              *mongo.Collection
+	platformLinks       *mongo.Collection
+	ledger              *mongo.Collection
+	escrow              *mongo.Collection
+	refundPolicies      *mongo.Collection
+	refunds             *mongo.Collection
+	disputes            *mongo.Collection
+	disputeEvidence     *mongo.Collection
+	feeSchedules        *mongo.Collection
+	subscriptions       *mongo.Collection
+	coupons             *mongo.Collection
+	couponRedemptions   *mongo.Collection
+	invoiceTaxes        *mongo.Collection
+	invoiceItems        *mongo.Collection
+	customers           *mongo.Collection
+	paymentMethods      *mongo.Collection
+	payoutSchedules     *mongo.Collection
+	anticipations       *mongo.Collection
+	tenants             *mongo.Collection
+	amountLimits        *mongo.Collection
+	standingOrders      *mongo.Collection
+	refundWindows       *mongo.Collection
+	counters            *mongo.Collection
+}
+
+// NewStore cria o armazenamento sobre o banco informado
+func NewStore(client *mongo.Client, database string) *Store {
+	db := client.Database(database)
+	return &Store{
+		client:              client,
+		accounts:            db.Collection("accounts"),
+		invoices:            db.Collection("invoices"),
+		users:               db.Collection("users"),
+		tokens:              db.Collection("refresh_tokens"),
+		audit:               db.Collection("audit_log"),
+		authEvents:          db.Collection("auth_events"),
+		cards:               db.Collection("card_tokens"),
+		twoFactors:          db.Collection("two_factor"),
+		geoPolicies:         db.Collection("geo_policies"),
+		dataSubjectRequests: db.Collection("data_subject_requests"),
+		exports:             db.Collection("exports"),
+		securityWebhooks:    db.Collection("security_webhooks"),
+		alertRules:          db.Collection("alert_rules"),
+		blocklist:           db.Collection("blocklist_entries"),
+		riskPolicies:        db.Collection("risk_policies"),
+		reviews:             db.Collection("invoice_reviews"),
+		splits:              db.Collection("invoice_splits"),
+		platformLinks:       db.Collection("platform_links"),
+		ledger:              db.Collection("ledger_entries"),
+		escrow:              db.Collection("escrow_holds"),
+		refundPolicies:      db.Collection("refund_policies"),
+		refunds:             db.Collection("refunds"),
+		disputes:            db.Collection("disputes"),
+		disputeEvidence:     db.Collection("dispute_evidence"),
+		feeSchedules:        db.Collection("fee_schedules"),
+		subscriptions:       db.Collection("subscriptions"),
+		coupons:             db.Collection("coupons"),
+		couponRedemptions:   db.Collection("coupon_redemptions"),
+		invoiceTaxes:        db.Collection("invoice_taxes"),
+		invoiceItems:        db.Collection("invoice_items"),
+		customers:           db.Collection("customers"),
+		paymentMethods:      db.Collection("customer_payment_methods"),
+		payoutSchedules:     db.Collection("payout_schedules"),
+		anticipations:       db.Collection("anticipations"),
+		tenants:             db.Collection("tenants"),
+		amountLimits:        db.Collection("account_amount_limits"),
+		standingOrders:      db.Collection("standing_orders"),
+		refundWindows:       db.Collection("account_refund_windows"),
+		counters:            db.Collection("counters"),
+	}
+}
+
+// Ping verifica se o primário do replica set responde
+func (s *Store) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx, readpref.Primary())
+}
+
+// EnsureIndexes cria os índices equivalentes aos das migrations SQL; índices existentes são mantidos
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.accounts.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "api_key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "email_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.invoices.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+		// Busca textual sem stemming, com o nome do pagador pesando mais que a descrição
+		{
+			Keys: bson.D{{Key: "payer_name", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "payer_name", Value: 2}, {Key: "description", Value: 1}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.users.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "account_id", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.tokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "session_id", Value: 1}}},
+		// Tokens expirados são removidos pelo próprio MongoDB um dia depois de expirarem
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(24 * 60 * 60)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.audit.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "request_id", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.authEvents.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "key_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dataSubjectRequests.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.cards.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "account_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.blocklist.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "value_hash", Value: 1}, {Key: "account_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.reviews.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "due_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.splits.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "invoice_id", Value: 1}, {Key: "recipient_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "recipient_id", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.platformLinks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "platform_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ledger.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.escrow.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "invoice_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "release_at", Value: 1}}},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.refunds.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "invoice_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.disputes.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "invoice_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.disputeEvidence.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "dispute_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.subscriptions.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.coupons.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.couponRedemptions.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "invoice_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.invoiceTaxes.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "invoice_id", Value: 1}, {Key: "line", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.invoiceItems.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "invoice_id", Value: 1}, {Key: "position", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.customers.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.paymentMethods.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "customer_id", Value: 1}, {Key: "card_token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.anticipations.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.tenants.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "admin_key_hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.standingOrders.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "account_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}}},
+	})
+	return err
+}
+
+// withTransaction executa fn em uma transação; conflitos de escrita são repetidos pelo driver
+func (s *Store) withTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := s.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// reserveAuditIDs reserva n IDs consecutivos da auditoria e retorna o primeiro
+// Como uma sequência do PostgreSQL, a reserva fica fora da transação e IDs de transações abortadas são perdidos
+func (s *Store) reserveAuditIDs(ctx context.Context, n int) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := s.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": auditCounterID},
+		bson.M{"$inc": bson.M{"seq": int64(n)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq - int64(n) + 1, nil
+}
+
+// auditDocument é a entrada de auditoria armazenada; os snapshots ficam serializados em JSON
+type auditDocument struct {
+	ID        int64              `bson:"_id"`
+	Entity    string             `bson:"entity"`
+	EntityID  string             `bson:"entity_id"`
+	Action    domain.AuditAction `bson:"action"`
+	OldValue  *string            `bson:"old_value"`
+	NewValue  *string            `bson:"new_value"`
+	Actor     string             `bson:"actor"`
+	RequestID *string            `bson:"request_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// newAuditDocument monta a entrada de auditoria lendo o autor e o ID da requisição do contexto
+func newAuditDocument(ctx context.Context, id int64, entity, entityID string, action domain.AuditAction, oldValue, newValue any) (*auditDocument, error) {
+	oldJSON, err := marshalAuditValue(oldValue)
+	if err != nil {
+		return nil, err
+	}
+	newJSON, err := marshalAuditValue(newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &auditDocument{
+		ID:        id,
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		OldValue:  oldJSON,
+		NewValue:  newJSON,
+		Actor:     requestctx.Actor(ctx),
+		CreatedAt: time.Now(),
+	}
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		doc.RequestID = &requestID
+	}
+	return doc, nil
+}
+
+// writeAudit grava a entrada de auditoria dentro da transação da mutação
+func (s *Store) writeAudit(ctx mongo.SessionContext, id int64, entity, entityID string, action domain.AuditAction, oldValue, newValue any) error {
+	doc, err := newAuditDocument(ctx, id, entity, entityID, action, oldValue, newValue)
+	if err != nil {
+		return err
+	}
+	_, err = s.audit.InsertOne(ctx, doc)
+	return err
+}
+
+// marshalAuditValue serializa o snapshot com os dados sensíveis mascarados; valores nil ficam nulos
+func marshalAuditValue(value any) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(redact.JSON(data))
+	return &encoded, nil
+}
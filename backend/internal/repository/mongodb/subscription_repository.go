@@ -0,0 +1,164 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// subscriptionDocument é uma assinatura armazenada
+type subscriptionDocument struct {
+	ID             string                    `bson:"_id"`
+	AccountID      string                    `bson:"account_id"`
+	CardToken      string                    `bson:"card_token"`
+	CardBrand      string                    `bson:"card_brand"`
+	CardLastDigits string                    `bson:"card_last_digits"`
+	Description    string                    `bson:"description"`
+	Amount         float64                   `bson:"amount"`
+	IntervalDays   int                       `bson:"interval_days"`
+	Status         domain.SubscriptionStatus `bson:"status"`
+	WebhookURL     string                    `bson:"webhook_url"`
+	DueAt          time.Time                 `bson:"due_at"`
+	NextAttemptAt  *time.Time                `bson:"next_attempt_at,omitempty"`
+	FailedAttempts int                       `bson:"failed_attempts"`
+	LastInvoiceID  string                    `bson:"last_invoice_id"`
+	CancelledAt    *time.Time                `bson:"cancelled_at,omitempty"`
+	CreatedAt      time.Time                 `bson:"created_at"`
+	UpdatedAt      time.Time                 `bson:"updated_at"`
+}
+
+func (d *subscriptionDocument) toDomain() *domain.Subscription {
+	return &domain.Subscription{
+		ID:             d.ID,
+		AccountID:      d.AccountID,
+		CardToken:      d.CardToken,
+		CardBrand:      d.CardBrand,
+		CardLastDigits: d.CardLastDigits,
+		Description:    d.Description,
+		Amount:         d.Amount,
+		IntervalDays:   d.IntervalDays,
+		Status:         d.Status,
+		WebhookURL:     d.WebhookURL,
+		DueAt:          d.DueAt,
+		NextAttemptAt:  d.NextAttemptAt,
+		FailedAttempts: d.FailedAttempts,
+		LastInvoiceID:  d.LastInvoiceID,
+		CancelledAt:    d.CancelledAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// SubscriptionRepository implementa domain.SubscriptionRepository no MongoDB
+type SubscriptionRepository struct {
+	store *Store
+}
+
+// NewSubscriptionRepository cria um repositório de assinaturas sobre o armazenamento informado
+func NewSubscriptionRepository(store *Store) *SubscriptionRepository {
+	return &SubscriptionRepository{store: store}
+}
+
+// Create grava a assinatura
+func (r *SubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) error {
+	_, err := r.store.subscriptions.InsertOne(ctx, &subscriptionDocument{
+		ID:             subscription.ID,
+		AccountID:      subscription.AccountID,
+		CardToken:      subscription.CardToken,
+		CardBrand:      subscription.CardBrand,
+		CardLastDigits: subscription.CardLastDigits,
+		Description:    subscription.Description,
+		Amount:         subscription.Amount,
+		IntervalDays:   subscription.IntervalDays,
+		Status:         subscription.Status,
+		WebhookURL:     subscription.WebhookURL,
+		DueAt:          subscription.DueAt,
+		NextAttemptAt:  subscription.NextAttemptAt,
+		FailedAttempts: subscription.FailedAttempts,
+		LastInvoiceID:  subscription.LastInvoiceID,
+		CancelledAt:    subscription.CancelledAt,
+		CreatedAt:      subscription.CreatedAt,
+		UpdatedAt:      subscription.UpdatedAt,
+	})
+	return err
+}
+
+// FindByID busca a assinatura pelo ID
+// Retorna ErrSubscriptionNotFound se a assinatura não existir
+func (r *SubscriptionRepository) FindByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	var doc subscriptionDocument
+	if err := r.store.subscriptions.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List retorna até limit assinaturas da conta na situação informada, ou em todas com status vazio, das mais novas
+// para as mais antigas
+func (r *SubscriptionRepository) List(ctx context.Context, accountID string, status domain.SubscriptionStatus, limit int) ([]*domain.Subscription, error) {
+	filter := bson.M{"account_id": accountID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// ListDue retorna até limit assinaturas ativas com tentativa até before, das mais atrasadas primeiro
+func (r *SubscriptionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	return r.find(ctx, bson.M{"status": domain.SubscriptionActive, "next_attempt_at": bson.M{"$lte": before}}, options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+}
+
+// Update grava a assinatura se a situação gravada ainda for from
+// Retorna ErrSubscriptionChanged quando ela mudou antes
+func (r *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription, from domain.SubscriptionStatus) error {
+	result, err := r.store.subscriptions.UpdateOne(ctx,
+		bson.M{"_id": subscription.ID, "status": from},
+		bson.M{"$set": bson.M{
+			"status":          subscription.Status,
+			"due_at":          subscription.DueAt,
+			"next_attempt_at": subscription.NextAttemptAt,
+			"failed_attempts": subscription.FailedAttempts,
+			"last_invoice_id": subscription.LastInvoiceID,
+			"cancelled_at":    subscription.CancelledAt,
+			"updated_at":      subscription.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSubscriptionChanged
+	}
+	return nil
+}
+
+// find retorna as assinaturas que atendem ao filtro
+func (r *SubscriptionRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]*domain.Subscription, error) {
+	cursor, err := r.store.subscriptions.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subscriptions []*domain.Subscription
+	for cursor.Next(ctx) {
+		var doc subscriptionDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, doc.toDomain())
+	}
+	return subscriptions, cursor.Err()
+}
@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tenantDocument é uma organização armazenada; os campos ausentes usam a tarifa e os tetos padrão
+type tenantDocument struct {
+	ID             string                 `bson:"_id"`
+	Name           string                 `bson:"name"`
+	AdminKeyHash   string                 `bson:"admin_key_hash"`
+	DisplayName    string                 `bson:"display_name"`
+	LogoURL        string                 `bson:"logo_url"`
+	PrimaryColor   string                 `bson:"primary_color"`
+	SupportEmail   string                 `bson:"support_email"`
+	ChargebackFee  *float64               `bson:"chargeback_fee,omitempty"`
+	SpendingLimits *domain.SpendingLimits `bson:"spending_limits,omitempty"`
+	CreatedAt      time.Time              `bson:"created_at"`
+	UpdatedAt      time.Time              `bson:"updated_at"`
+}
+
+func newTenantDocument(tenant *domain.Tenant) *tenantDocument {
+	return &tenantDocument{
+		ID:             tenant.ID,
+		Name:           tenant.Name,
+		AdminKeyHash:   tenant.AdminKeyHash,
+		DisplayName:    tenant.Branding.DisplayName,
+		LogoURL:        tenant.Branding.LogoURL,
+		PrimaryColor:   tenant.Branding.PrimaryColor,
+		SupportEmail:   tenant.Branding.SupportEmail,
+		ChargebackFee:  tenant.ChargebackFee,
+		SpendingLimits: tenant.SpendingLimits,
+		CreatedAt:      tenant.CreatedAt,
+		UpdatedAt:      tenant.UpdatedAt,
+	}
+}
+
+func (d *tenantDocument) toDomain() *domain.Tenant {
+	return &domain.Tenant{
+		ID:           d.ID,
+		Name:         d.Name,
+		AdminKeyHash: d.AdminKeyHash,
+		Branding: domain.TenantBranding{
+			DisplayName:  d.DisplayName,
+			LogoURL:      d.LogoURL,
+			PrimaryColor: d.PrimaryColor,
+			SupportEmail: d.SupportEmail,
+		},
+		ChargebackFee:  d.ChargebackFee,
+		SpendingLimits: d.SpendingLimits,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// TenantRepository implementa domain.TenantRepository no MongoDB
+type TenantRepository struct {
+	store *Store
+}
+
+// NewTenantRepository cria um repositório de organizações sobre o armazenamento informado
+func NewTenantRepository(store *Store) *TenantRepository {
+	return &TenantRepository{store: store}
+}
+
+// Save grava a nova organização
+func (r *TenantRepository) Save(ctx context.Context, tenant *domain.Tenant) error {
+	_, err := r.store.tenants.InsertOne(ctx, newTenantDocument(tenant))
+	return err
+}
+
+// findOne busca a organização que atende ao filtro
+func (r *TenantRepository) findOne(ctx context.Context, filter bson.M) (*domain.Tenant, error) {
+	var doc tenantDocument
+	if err := r.store.tenants.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// FindByID busca a organização pelo ID
+// Retorna ErrTenantNotFound se a organização não existir
+func (r *TenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByAdminKeyHash busca a organização pelo hash da chave dos administradores dela
+// Retorna ErrTenantNotFound se nenhuma organização tiver a chave
+func (r *TenantRepository) FindByAdminKeyHash(ctx context.Context, hash string) (*domain.Tenant, error) {
+	return r.findOne(ctx, bson.M{"admin_key_hash": hash})
+}
+
+// List retorna as organizações ordenadas pelo nome
+func (r *TenantRepository) List(ctx context.Context) ([]*domain.Tenant, error) {
+	cursor, err := r.store.tenants.Find(ctx, bson.M{}, options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	for cursor.Next(ctx) {
+		var doc tenantDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, doc.toDomain())
+	}
+	return tenants, cursor.Err()
+}
+
+// Update grava o nome, a chave, a marca, a tarifa e os tetos de gasto da organização
+// Retorna ErrTenantNotFound se a organização não existir
+func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
+	result, err := r.store.tenants.ReplaceOne(ctx, bson.M{"_id": tenant.ID}, newTenantDocument(tenant))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTenantNotFound
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// twoFactorDocument é o segundo fator armazenado, identificado pelo autor
+type twoFactorDocument struct {
+	Actor       string     `bson:"_id"`
+	Secret      string     `bson:"secret"`
+	BackupCodes []string   `bson:"backup_codes"`
+	LastStep    int64      `bson:"last_step"`
+	ConfirmedAt *time.Time `bson:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at"`
+}
+
+// TwoFactorRepository implementa domain.TwoFactorRepository no MongoDB
+// O segredo TOTP é gravado cifrado
+type TwoFactorRepository struct {
+	store     *Store
+	encryptor *pii.Encryptor
+}
+
+// NewTwoFactorRepository cria um repositório de segundo fator sobre o armazenamento informado
+// Com encryptor nil os segredos são gravados em texto puro
+func NewTwoFactorRepository(store *Store, encryptor *pii.Encryptor) *TwoFactorRepository {
+	return &TwoFactorRepository{store: store, encryptor: encryptor}
+}
+
+// Save persiste o cadastro de um segundo fator
+func (r *TwoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	secret, err := r.encryptor.Encrypt(ctx, twoFactor.Secret)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.store.twoFactors.InsertOne(ctx, &twoFactorDocument{
+		Actor:       twoFactor.Actor,
+		Secret:      secret,
+		BackupCodes: twoFactor.BackupCodes,
+		LastStep:    twoFactor.LastStep,
+		ConfirmedAt: twoFactor.ConfirmedAt,
+		CreatedAt:   twoFactor.CreatedAt,
+		UpdatedAt:   twoFactor.UpdatedAt,
+	})
+	return err
+}
+
+// FindByActor busca o segundo fator do autor decifrando o segredo
+func (r *TwoFactorRepository) FindByActor(ctx context.Context, actor string) (*domain.TwoFactor, error) {
+	var doc twoFactorDocument
+	if err := r.store.twoFactors.FindOne(ctx, bson.M{"_id": actor}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	secret, err := r.encryptor.Decrypt(ctx, doc.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactor{
+		Actor:       doc.Actor,
+		Secret:      secret,
+		BackupCodes: doc.BackupCodes,
+		LastStep:    doc.LastStep,
+		ConfirmedAt: doc.ConfirmedAt,
+		CreatedAt:   doc.CreatedAt,
+		UpdatedAt:   doc.UpdatedAt,
+	}, nil
+}
+
+// Update grava os códigos de recuperação, o último período aceito e a confirmação
+// Retorna ErrTwoFactorNotEnrolled se o cadastro não existir mais
+func (r *TwoFactorRepository) Update(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	result, err := r.store.twoFactors.UpdateByID(ctx, twoFactor.Actor, bson.M{
+		"$set": bson.M{
+			"backup_codes": twoFactor.BackupCodes,
+			"last_step":    twoFactor.LastStep,
+			"confirmed_at": twoFactor.ConfirmedAt,
+			"updated_at":   twoFactor.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+	return nil
+}
+
+// Delete remove o segundo fator do autor
+func (r *TwoFactorRepository) Delete(ctx context.Context, actor string) error {
+	_, err := r.store.twoFactors.DeleteOne(ctx, bson.M{"_id": actor})
+	return err
+}
@@ -0,0 +1,287 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const userEntity = "user"
+
+// userDocument é o usuário do dashboard armazenado
+type userDocument struct {
+	ID           string      `bson:"_id"`
+	AccountID    string      `bson:"account_id"`
+	Email        string      `bson:"email"`
+	EmailHash    string      `bson:"email_hash"`
+	Role         domain.Role `bson:"role"`
+	PasswordHash string      `bson:"password_hash"`
+	CreatedAt    time.Time   `bson:"created_at"`
+	UpdatedAt    time.Time   `bson:"updated_at"`
+}
+
+// refreshTokenDocument é o refresh token armazenado, identificado pelo hash do token
+type refreshTokenDocument struct {
+	ID        string     `bson:"_id"`
+	UserID    string     `bson:"user_id"`
+	SessionID string     `bson:"session_id"`
+	TokenHash string     `bson:"token_hash"`
+	IP        string     `bson:"ip"`
+	UserAgent string     `bson:"user_agent"`
+	ExpiresAt time.Time  `bson:"expires_at"`
+	CreatedAt time.Time  `bson:"created_at"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+// UserRepository implementa domain.UserRepository no MongoDB
+// O e-mail é gravado cifrado e buscado pelo índice cego email_hash
+type UserRepository struct {
+	store     *Store
+	encryptor *pii.Encryptor
+}
+
+// NewUserRepository cria um repositório de usuários sobre o armazenamento informado
+// Com encryptor nil os e-mails são gravados em texto puro
+func NewUserRepository(store *Store, encryptor *pii.Encryptor) *UserRepository {
+	return &UserRepository{store: store, encryptor: encryptor}
+}
+
+// decodeUser converte o documento em usuário decifrando o e-mail
+func (r *UserRepository) decodeUser(ctx context.Context, result *mongo.SingleResult) (*domain.User, error) {
+	var doc userDocument
+	if err := result.Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	email, err := r.encryptor.Decrypt(ctx, doc.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.User{
+		ID:           doc.ID,
+		AccountID:    doc.AccountID,
+		Email:        email,
+		Role:         domain.RoleOrDefault(doc.Role),
+		PasswordHash: doc.PasswordHash,
+		CreatedAt:    doc.CreatedAt,
+		UpdatedAt:    doc.UpdatedAt,
+	}, nil
+}
+
+// Save persiste um novo usuário registrando a inserção na auditoria
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	email, err := r.encryptor.Encrypt(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		if err := r.store.writeAudit(tx, auditID, userEntity, user.ID, domain.AuditActionInsert, nil, repository.NewUserSnapshot(user)); err != nil {
+			return err
+		}
+
+		_, err := r.store.users.InsertOne(tx, &userDocument{
+			ID:           user.ID,
+			AccountID:    user.AccountID,
+			Email:        email,
+			EmailHash:    r.encryptor.BlindIndex(user.Email),
+			Role:         user.Role,
+			PasswordHash: user.PasswordHash,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
+		})
+		return err
+	})
+}
+
+// FindByEmail busca um usuário pelo e-mail através do índice cego
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.decodeUser(ctx, r.store.users.FindOne(ctx, bson.M{"email_hash": r.encryptor.BlindIndex(email)}))
+}
+
+// FindByID busca um usuário pelo ID
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.decodeUser(ctx, r.store.users.FindOne(ctx, bson.M{"_id": id}))
+}
+
+// UpdateRole altera o papel do usuário registrando o estado anterior na auditoria
+// Retorna ErrUserNotFound se o usuário não existir
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.decodeUser(tx, r.store.users.FindOne(tx, bson.M{"_id": id}))
+		if err != nil {
+			return err
+		}
+
+		updatedAt := time.Now()
+		updated := repository.NewUserSnapshot(current)
+		updated.Role = role
+		updated.UpdatedAt = updatedAt
+
+		if err := r.store.writeAudit(tx, auditID, userEntity, id, domain.AuditActionUpdate, repository.NewUserSnapshot(current), updated); err != nil {
+			return err
+		}
+
+		_, err = r.store.users.UpdateByID(tx, id, bson.M{
+			"$set": bson.M{"role": role, "updated_at": updatedAt},
+		})
+		return err
+	})
+}
+
+// UpdatePassword troca o hash da senha registrando a alteração na auditoria, sem o hash
+// Retorna ErrUserNotFound se o usuário não existir
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	auditID, err := r.store.reserveAuditIDs(ctx, 1)
+	if err != nil {
+		return err
+	}
+
+	return r.store.withTransaction(ctx, func(tx mongo.SessionContext) error {
+		current, err := r.decodeUser(tx, r.store.users.FindOne(tx, bson.M{"_id": id}))
+		if err != nil {
+			return err
+		}
+
+		updatedAt := time.Now()
+		updated := repository.NewUserSnapshot(current)
+		updated.UpdatedAt = updatedAt
+
+		if err := r.store.writeAudit(tx, auditID, userEntity, id, domain.AuditActionUpdate, repository.NewUserSnapshot(current), updated); err != nil {
+			return err
+		}
+
+		_, err = r.store.users.UpdateByID(tx, id, bson.M{
+			"$set": bson.M{"password_hash": passwordHash, "updated_at": updatedAt},
+		})
+		return err
+	})
+}
+
+// SaveRefreshToken persiste um novo refresh token
+func (r *UserRepository) SaveRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	_, err := r.store.tokens.InsertOne(ctx, &refreshTokenDocument{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		SessionID: token.SessionID,
+		TokenHash: token.TokenHash,
+		IP:        token.IP,
+		UserAgent: token.UserAgent,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+		RevokedAt: token.RevokedAt,
+	})
+	return err
+}
+
+// FindRefreshToken busca um refresh token pelo hash
+// Retorna ErrInvalidToken se não encontrado
+func (r *UserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var doc refreshTokenDocument
+	if err := r.store.tokens.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return doc.refreshToken(), nil
+}
+
+// FindActiveRefreshTokens busca as sessões ativas do usuário, das renovadas mais recentemente para as mais antigas
+func (r *UserRepository) FindActiveRefreshTokens(ctx context.Context, userID string) ([]*domain.RefreshToken, error) {
+	cursor, err := r.store.tokens.Find(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []refreshTokenDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*domain.RefreshToken, len(docs))
+	for i := range docs {
+		tokens[i] = docs[i].refreshToken()
+	}
+	return tokens, nil
+}
+
+// RevokeRefreshToken revoga o token apenas se ainda estiver ativo
+func (r *UserRepository) RevokeRefreshToken(ctx context.Context, id string) error {
+	result, err := r.store.tokens.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+// RevokeSession revoga o token ativo da sessão do usuário
+// Retorna ErrSessionNotFound se não houver token ativo da sessão
+func (r *UserRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	now := time.Now()
+	result, err := r.store.tokens.UpdateOne(ctx,
+		bson.M{"user_id": userID, "session_id": sessionID, "revoked_at": nil, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revoga todos os tokens ainda ativos do usuário
+func (r *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	_, err := r.store.tokens.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+// refreshToken converte o documento em refresh token
+func (doc *refreshTokenDocument) refreshToken() *domain.RefreshToken {
+	return &domain.RefreshToken{
+		ID:        doc.ID,
+		UserID:    doc.UserID,
+		SessionID: doc.SessionID,
+		TokenHash: doc.TokenHash,
+		IP:        doc.IP,
+		UserAgent: doc.UserAgent,
+		ExpiresAt: doc.ExpiresAt,
+		CreatedAt: doc.CreatedAt,
+		RevokedAt: doc.RevokedAt,
+	}
+}
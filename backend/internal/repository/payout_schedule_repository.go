@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PayoutScheduleRepository implementa a persistência dos prazos de repasse das contas
+type PayoutScheduleRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewPayoutScheduleRepository cria um novo repositório de prazos de repasse para o banco do dialeto informado
+func NewPayoutScheduleRepository(db *sql.DB, dialect Dialect) *PayoutScheduleRepository {
+	return &PayoutScheduleRepository{db: db, dialect: dialect}
+}
+
+// Save substitui o prazo da conta em uma transação
+func (r *PayoutScheduleRepository) Save(ctx context.Context, schedule *domain.PayoutSchedule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM payout_schedules WHERE account_id = ?"), schedule.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO payout_schedules (account_id, delay_days, updated_at) VALUES "+valuesPlaceholders(1, 3)),
+		schedule.AccountID, schedule.DelayDays, schedule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca o prazo da conta
+// Retorna ErrPayoutScheduleNotFound se a conta usa o prazo padrão
+func (r *PayoutScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PayoutSchedule, error) {
+	var schedule domain.PayoutSchedule
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, delay_days, updated_at FROM payout_schedules WHERE account_id = ?"),
+		accountID,
+	).Scan(&schedule.AccountID, &schedule.DelayDays, &schedule.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// PlatformLinkRepository implementa a persistência das ligações entre contas filhas e plataformas
+type PlatformLinkRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewPlatformLinkRepository cria um novo repositório de ligações com plataformas para o banco do dialeto informado
+func NewPlatformLinkRepository(db *sql.DB, dialect Dialect) *PlatformLinkRepository {
+	return &PlatformLinkRepository{db: db, dialect: dialect}
+}
+
+// Save substitui a ligação da conta em uma transação
+func (r *PlatformLinkRepository) Save(ctx context.Context, link *domain.PlatformLink) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM platform_links WHERE account_id = ?"), link.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO platform_links (account_id, platform_id, commission_percent, created_at, updated_at) VALUES "+valuesPlaceholders(1, 5)),
+		link.AccountID, link.PlatformID, link.CommissionPercent, link.CreatedAt, link.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca a ligação da conta
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (r *PlatformLinkRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PlatformLink, error) {
+	var link domain.PlatformLink
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, platform_id, commission_percent, created_at, updated_at FROM platform_links WHERE account_id = ?"),
+		accountID,
+	).Scan(&link.AccountID, &link.PlatformID, &link.CommissionPercent, &link.CreatedAt, &link.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPlatformLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CountByPlatformID retorna quantas contas são filhas da plataforma
+func (r *PlatformLinkRepository) CountByPlatformID(ctx context.Context, platformID string) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT COUNT(*) FROM platform_links WHERE platform_id = ?"),
+		platformID,
+	).Scan(&count)
+	return count, err
+}
+
+// Delete remove a ligação da conta
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (r *PlatformLinkRepository) Delete(ctx context.Context, accountID string) error {
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM platform_links WHERE account_id = ?"), accountID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrPlatformLinkNotFound
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import "strings"
+
+// queryBuilder monta cláusulas WHERE parametrizadas de forma incremental
+// As condições usam "?" como marcador e são traduzidas pelo dialeto na montagem
+type queryBuilder struct {
+	dialect    Dialect
+	conditions []string
+	args       []any
+}
+
+// newQueryBuilder cria um construtor de consultas para o dialeto informado
+func newQueryBuilder(dialect Dialect) *queryBuilder {
+	return &queryBuilder{dialect: dialect}
+}
+
+// where adiciona uma condição com seus argumentos, combinada às demais com AND
+func (b *queryBuilder) where(condition string, args ...any) *queryBuilder {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// whereIn adiciona uma condição "coluna IN (...)" com um marcador por valor
+// Listas vazias são ignoradas
+func (b *queryBuilder) whereIn(column string, values []any) *queryBuilder {
+	if len(values) == 0 {
+		return b
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return b.where(column+" IN ("+placeholders+")", values...)
+}
+
+// build concatena a consulta base com as condições e o sufixo (ORDER BY, LIMIT...)
+// suffixArgs preenchem os marcadores do sufixo, que vêm depois dos das condições
+func (b *queryBuilder) build(base, suffix string, suffixArgs ...any) (string, []any) {
+	var sb strings.Builder
+	sb.WriteString(base)
+
+	if len(b.conditions) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(b.conditions, " AND "))
+	}
+	if suffix != "" {
+		sb.WriteString(" ")
+		sb.WriteString(suffix)
+	}
+
+	return b.dialect.rebind(sb.String()), append(b.args, suffixArgs...)
+}
+
+// valuesPlaceholders monta a lista VALUES de um INSERT com várias linhas: (?, ?), (?, ?)...
+func valuesPlaceholders(rows, columns int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", columns), ", ") + ")"
+	return strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+}
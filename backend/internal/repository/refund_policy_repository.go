@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RefundPolicyRepository implementa a persistência das políticas de reembolso das contas
+type RefundPolicyRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewRefundPolicyRepository cria um novo repositório de políticas de reembolso para o banco do dialeto informado
+func NewRefundPolicyRepository(db *sql.DB, dialect Dialect) *RefundPolicyRepository {
+	return &RefundPolicyRepository{db: db, dialect: dialect}
+}
+
+// Save substitui a política da conta em uma transação
+func (r *RefundPolicyRepository) Save(ctx context.Context, policy *domain.RefundPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM refund_policies WHERE account_id = ?"), policy.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO refund_policies (account_id, approval_threshold, updated_at) VALUES "+valuesPlaceholders(1, 3)),
+		policy.AccountID, policy.ApprovalThreshold, policy.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca a política da conta
+// Retorna ErrRefundPolicyNotFound se a conta não exigir aprovação
+func (r *RefundPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RefundPolicy, error) {
+	var policy domain.RefundPolicy
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, approval_threshold, updated_at FROM refund_policies WHERE account_id = ?"),
+		accountID,
+	).Scan(&policy.AccountID, &policy.ApprovalThreshold, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefundPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
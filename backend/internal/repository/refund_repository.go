@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// refundColumns são as colunas lidas por scanRefund, na mesma ordem
+const refundColumns = "id, invoice_id, account_id, amount, reason, status, requested_by, decided_by, decided_at, expires_at, created_at"
+
+// RefundRepository implementa a persistência dos reembolsos
+type RefundRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewRefundRepository cria um novo repositório de reembolsos para o banco do dialeto informado
+func NewRefundRepository(db *sql.DB, dialect Dialect) *RefundRepository {
+	return &RefundRepository{db: db, dialect: dialect}
+}
+
+// Create grava o reembolso e, se posting não for nil, o movimento do débito em uma transação
+// A fatura é travada com SELECT FOR UPDATE antes de somar os reembolsos, para que dois pedidos simultâneos não
+// reembolsem juntos mais que ela
+// Retorna ErrInvoiceNotFound se a fatura não existir e os erros de Refund.CheckRefundable
+func (r *RefundRepository) Create(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoiceSnapshot(ctx, tx, r.dialect, refund.InvoiceID)
+	if err != nil {
+		return err
+	}
+	refunded, err := r.sum(ctx, tx, refund.InvoiceID, domain.RefundCompleted, domain.RefundPending)
+	if err != nil {
+		return err
+	}
+	if err := refund.CheckRefundable(invoice, refunded); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO refunds ("+refundColumns+") VALUES "+valuesPlaceholders(1, 11)),
+		refund.ID, refund.InvoiceID, refund.AccountID, refund.Amount, refund.Reason, refund.Status,
+		refund.RequestedBy, refund.DecidedBy, refund.DecidedAt, refund.ExpiresAt, refund.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := r.complete(ctx, tx, invoice, refund, posting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByID busca o reembolso pelo ID
+// Retorna ErrRefundNotFound se o reembolso não existir
+func (r *RefundRepository) FindByID(ctx context.Context, id string) (*domain.Refund, error) {
+	refund, err := scanRefund(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+refundColumns+" FROM refunds WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefundNotFound
+	}
+	return refund, err
+}
+
+// List retorna até limit reembolsos da conta na situação informada, ou em todas com status vazio, dos mais novos
+// para os mais antigos
+func (r *RefundRepository) List(ctx context.Context, accountID string, status domain.RefundStatus, limit int) ([]*domain.Refund, error) {
+	builder := newQueryBuilder(r.dialect).where("account_id = ?", accountID)
+	if status != "" {
+		builder.where("status = ?", status)
+	}
+	query, args := builder.build("SELECT "+refundColumns+" FROM refunds", "ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit))
+	return r.query(ctx, query, args...)
+}
+
+// ListExpired retorna até limit reembolsos pendentes com prazo até before, dos que venceram primeiro
+func (r *RefundRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.Refund, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+refundColumns+" FROM refunds WHERE status = ? AND expires_at <= ? ORDER BY expires_at, id LIMIT "+strconv.Itoa(limit)),
+		domain.RefundPending, before,
+	)
+}
+
+// Decide grava a decisão se o reembolso ainda estiver pendente e, se posting não for nil, o movimento do débito em
+// uma transação; na aprovação a fatura é travada e precisa continuar aprovada
+// Retorna ErrRefundAlreadyDecided quando outra decisão chegou antes e ErrInvalidStatus se a fatura aprovada no
+// pedido foi estornada depois
+func (r *RefundRepository) Decide(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var invoice *domain.Invoice
+	if refund.Status == domain.RefundCompleted {
+		if invoice, err = lockInvoiceSnapshot(ctx, tx, r.dialect, refund.InvoiceID); err != nil {
+			return err
+		}
+		if !invoice.Status.IsPaid() {
+			return domain.ErrInvalidStatus
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE refunds SET status = ?, decided_by = ?, decided_at = ? WHERE id = ? AND status = ?"),
+		refund.Status, refund.DecidedBy, refund.DecidedAt, refund.ID, domain.RefundPending,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrRefundAlreadyDecided
+	}
+	if invoice != nil {
+		if err := r.complete(ctx, tx, invoice, refund, posting); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SumByInvoiceID soma os reembolsos concluídos e pendentes da fatura
+func (r *RefundRepository) SumByInvoiceID(ctx context.Context, invoiceID string) (float64, error) {
+	return r.sum(ctx, r.db, invoiceID, domain.RefundCompleted, domain.RefundPending)
+}
+
+// querier abstrai *sql.DB e *sql.Tx para as consultas de uma linha
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sum soma os reembolsos da fatura nas situações informadas
+func (r *RefundRepository) sum(ctx context.Context, q querier, invoiceID string, statuses ...any) (float64, error) {
+	query, args := newQueryBuilder(r.dialect).
+		where("invoice_id = ?", invoiceID).
+		whereIn("status", statuses).
+		build("SELECT COALESCE(SUM(amount), 0) FROM refunds", "")
+	var total float64
+	err := q.QueryRowContext(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+// complete grava o movimento do reembolso concluído e, se os concluídos já cobrem a fatura travada, passa a fatura
+// para StatusRefunded registrando o estado anterior na auditoria
+// Não faz nada para um reembolso pendente
+func (r *RefundRepository) complete(ctx context.Context, tx *sql.Tx, invoice *domain.Invoice, refund *domain.Refund, posting *domain.Posting) error {
+	if refund.Status != domain.RefundCompleted {
+		return nil
+	}
+	if posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return err
+		}
+	}
+
+	completed, err := r.sum(ctx, tx, invoice.ID, domain.RefundCompleted)
+	if err != nil || !domain.FullyRefunded(invoice, completed) {
+		return err
+	}
+	return setInvoiceStatus(ctx, tx, r.dialect, invoice, domain.StatusRefunded)
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna reembolsos completos
+func (r *RefundRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Refund, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []*domain.Refund
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+// scanRefund lê uma linha com as colunas de refundColumns
+func scanRefund(row rowScanner) (*domain.Refund, error) {
+	var refund domain.Refund
+	var decidedAt, expiresAt sql.NullTime
+	err := row.Scan(&refund.ID, &refund.InvoiceID, &refund.AccountID, &refund.Amount, &refund.Reason, &refund.Status,
+		&refund.RequestedBy, &refund.DecidedBy, &decidedAt, &expiresAt, &refund.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if decidedAt.Valid {
+		refund.DecidedAt = &decidedAt.Time
+	}
+	if expiresAt.Valid {
+		refund.ExpiresAt = &expiresAt.Time
+	}
+	return &refund, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RefundWindowRepository implementa a persistência dos prazos de reembolso próprios das contas
+type RefundWindowRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewRefundWindowRepository cria um novo repositório de prazos de reembolso para o banco do dialeto informado
+func NewRefundWindowRepository(db *sql.DB, dialect Dialect) *RefundWindowRepository {
+	return &RefundWindowRepository{db: db, dialect: dialect}
+}
+
+// Save substitui o prazo da conta em uma transação
+func (r *RefundWindowRepository) Save(ctx context.Context, window *domain.AccountRefundWindow) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM account_refund_windows WHERE account_id = ?"), window.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO account_refund_windows (account_id, days, updated_at) VALUES "+valuesPlaceholders(1, 3)),
+		window.AccountID, window.Days, window.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca o prazo da conta
+// Retorna ErrRefundWindowNotFound se a conta usa o prazo padrão
+func (r *RefundWindowRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.AccountRefundWindow, error) {
+	var window domain.AccountRefundWindow
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, days, updated_at FROM account_refund_windows WHERE account_id = ?"),
+		accountID,
+	).Scan(&window.AccountID, &window.Days, &window.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefundWindowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// Delete remove o prazo da conta, que volta ao padrão
+func (r *RefundWindowRepository) Delete(ctx context.Context, accountID string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM account_refund_windows WHERE account_id = ?"), accountID)
+	return err
+}
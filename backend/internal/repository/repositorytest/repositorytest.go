@@ -0,0 +1,375 @@
+// Package repositorytest reúne o contrato dos repositórios de contas e faturas, executado pelos testes de cada
+// armazenamento (SQL, memória e MongoDB) para que todos se comportem do mesmo jeito
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// Repositories são os repositórios de um armazenamento, sobre o mesmo banco
+type Repositories struct {
+	Accounts domain.AccountRepository
+	Invoices domain.InvoiceRepository
+}
+
+// Run executa o contrato com os repositórios de open, chamado uma vez por teste
+// Os testes só leem o que gravam, então o banco pode ser compartilhado e não precisa ser limpo
+func Run(t *testing.T, open func(t *testing.T) Repositories) {
+	t.Run("accounts", func(t *testing.T) { testAccounts(t, open) })
+	t.Run("invoices", func(t *testing.T) { testInvoices(t, open) })
+}
+
+// newAccount grava uma conta nova com e-mail único
+func newAccount(t *testing.T, accounts domain.AccountRepository) *domain.Account {
+	t.Helper()
+	account := domain.NewAccount("Contract", domain.NewID()+"@example.com")
+	if err := accounts.Save(context.Background(), account); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return account
+}
+
+// newInvoice cria uma fatura pendente da conta, sem gravar
+func newInvoice(t *testing.T, accountID string, amount float64) *domain.Invoice {
+	t.Helper()
+	invoice, err := domain.NewInvoice(accountID, amount, "Pedido", "credit_card", domain.PaymentCard{
+		Token:      domain.NewID(),
+		Brand:      "visa",
+		LastDigits: "1111",
+		HolderName: "John Doe",
+	})
+	if err != nil {
+		t.Fatalf("NewInvoice: %v", err)
+	}
+	invoice.Metadata = map[string]string{"order_id": "123"}
+	return invoice
+}
+
+func testAccounts(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("save and find", func(t *testing.T) {
+		accounts := open(t).Accounts
+		account := newAccount(t, accounts)
+
+		found, err := accounts.FindByID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Name != account.Name || found.Email != account.Email || found.APIKey != account.APIKey ||
+			found.Role != account.Role || found.Balance != account.Balance || found.DeletedAt != nil {
+			t.Errorf("FindByID = %+v, want %+v", found, account)
+		}
+
+		found, err = accounts.FindByAPIKey(ctx, account.APIKey)
+		if err != nil {
+			t.Fatalf("FindByAPIKey: %v", err)
+		}
+		if found.ID != account.ID {
+			t.Errorf("FindByAPIKey ID = %q, want %q", found.ID, account.ID)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		accounts := open(t).Accounts
+		if _, err := accounts.FindByID(ctx, domain.NewID()); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("FindByID error = %v, want ErrAccountNotFound", err)
+		}
+		if _, err := accounts.FindByAPIKey(ctx, domain.NewID()); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("FindByAPIKey error = %v, want ErrAccountNotFound", err)
+		}
+		if err := accounts.UpdateRole(ctx, domain.NewID(), domain.RoleAdmin); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("UpdateRole error = %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		accounts := open(t).Accounts
+		account := newAccount(t, accounts)
+
+		account.Balance = 150.25
+		if err := accounts.UpdateBalance(ctx, account); err != nil {
+			t.Fatalf("UpdateBalance: %v", err)
+		}
+		if err := accounts.UpdateRole(ctx, account.ID, domain.RoleAdmin); err != nil {
+			t.Fatalf("UpdateRole: %v", err)
+		}
+
+		found, err := accounts.FindByID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Balance != 150.25 {
+			t.Errorf("Balance = %v, want 150.25", found.Balance)
+		}
+		if found.Role != domain.RoleAdmin {
+			t.Errorf("Role = %q, want %q", found.Role, domain.RoleAdmin)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		accounts := open(t).Accounts
+		account := newAccount(t, accounts)
+
+		if err := accounts.Delete(ctx, account.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := accounts.FindByID(ctx, account.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("FindByID error = %v, want ErrAccountNotFound", err)
+		}
+		if _, err := accounts.FindByAPIKey(ctx, account.APIKey); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("FindByAPIKey error = %v, want ErrAccountNotFound", err)
+		}
+		found, err := accounts.FindByID(ctx, account.ID, domain.IncludeDeleted())
+		if err != nil {
+			t.Fatalf("FindByID with IncludeDeleted: %v", err)
+		}
+		if found.DeletedAt == nil {
+			t.Error("DeletedAt = nil, want the deletion time")
+		}
+
+		if err := accounts.Delete(ctx, account.ID); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("second Delete error = %v, want ErrAccountNotFound", err)
+		}
+		if err := accounts.UpdateBalance(ctx, account); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("UpdateBalance error = %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("list after", func(t *testing.T) {
+		accounts := open(t).Accounts
+		ids := []string{newAccount(t, accounts).ID, newAccount(t, accounts).ID}
+		sort.Strings(ids)
+
+		// O banco pode ter outras contas entre as duas, mas nenhuma depois da segunda vem antes dela
+		listed, err := accounts.ListAfter(ctx, ids[0], 1)
+		if err != nil {
+			t.Fatalf("ListAfter: %v", err)
+		}
+		if len(listed) != 1 {
+			t.Fatalf("ListAfter returned %d accounts, want 1", len(listed))
+		}
+		if listed[0].ID <= ids[0] || listed[0].ID > ids[1] {
+			t.Errorf("ListAfter ID = %q, want one in (%q, %q]", listed[0].ID, ids[0], ids[1])
+		}
+	})
+}
+
+func testInvoices(t *testing.T, open func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("save and find", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		invoice := newInvoice(t, account.ID, 100)
+		if err := repos.Invoices.Save(ctx, invoice, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		found, err := repos.Invoices.FindByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.AccountID != account.ID || found.Amount != 100 || found.Status != domain.StatusPending ||
+			found.Description != invoice.Description || found.PayerName != invoice.PayerName ||
+			found.CardLastDigits != invoice.CardLastDigits || found.Metadata["order_id"] != "123" {
+			t.Errorf("FindByID = %+v, want %+v", found, invoice)
+		}
+
+		if _, err := repos.Invoices.FindByID(ctx, domain.NewID()); !errors.Is(err, domain.ErrInvoiceNotFound) {
+			t.Errorf("FindByID error = %v, want ErrInvoiceNotFound", err)
+		}
+	})
+
+	t.Run("save with credit", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		invoice := newInvoice(t, account.ID, 100)
+		if err := invoice.TransitionTo(domain.StatusApproved); err != nil {
+			t.Fatalf("TransitionTo: %v", err)
+		}
+		posting := &domain.Posting{Balances: map[string]float64{account.ID: 100}}
+		if err := repos.Invoices.Save(ctx, invoice, nil, posting); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		found, err := repos.Accounts.FindByID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Balance != 100 {
+			t.Errorf("Balance = %v, want 100", found.Balance)
+		}
+
+		// Um crédito que não pode ser gravado desfaz a fatura
+		failed := newInvoice(t, account.ID, 50)
+		posting = &domain.Posting{Balances: map[string]float64{domain.NewID(): 50}}
+		if err := repos.Invoices.Save(ctx, failed, nil, posting); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Fatalf("Save error = %v, want ErrAccountNotFound", err)
+		}
+		if _, err := repos.Invoices.FindByID(ctx, failed.ID); !errors.Is(err, domain.ErrInvoiceNotFound) {
+			t.Errorf("FindByID error = %v, want ErrInvoiceNotFound", err)
+		}
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		invoice := newInvoice(t, account.ID, 100)
+		if err := repos.Invoices.Save(ctx, invoice, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		if err := invoice.TransitionTo(domain.StatusApproved); err != nil {
+			t.Fatalf("TransitionTo: %v", err)
+		}
+		if err := repos.Invoices.UpdateStatus(ctx, invoice, nil); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		found, err := repos.Invoices.FindByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Status != domain.StatusApproved {
+			t.Errorf("Status = %q, want %q", found.Status, domain.StatusApproved)
+		}
+
+		// O status gravado é conferido, e não o da fatura recebida
+		invoice.Status = domain.StatusRejected
+		if err := repos.Invoices.UpdateStatus(ctx, invoice, nil); !errors.Is(err, domain.ErrInvalidStatus) {
+			t.Errorf("UpdateStatus error = %v, want ErrInvalidStatus", err)
+		}
+	})
+
+	t.Run("update status with credit", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		invoice := newInvoice(t, account.ID, 100)
+		if err := repos.Invoices.Save(ctx, invoice, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if err := invoice.TransitionTo(domain.StatusApproved); err != nil {
+			t.Fatalf("TransitionTo: %v", err)
+		}
+
+		// Um crédito que não pode ser gravado desfaz a aprovação
+		failed := &domain.Posting{Balances: map[string]float64{domain.NewID(): 100}}
+		if err := repos.Invoices.UpdateStatus(ctx, invoice, failed); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Fatalf("UpdateStatus error = %v, want ErrAccountNotFound", err)
+		}
+		found, err := repos.Invoices.FindByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Status != domain.StatusPending {
+			t.Errorf("Status = %q, want %q", found.Status, domain.StatusPending)
+		}
+
+		// Sem nada retido, o crédito liquida a fatura na mesma transação
+		posting := &domain.Posting{
+			Entries:  []*domain.LedgerEntry{domain.NewLedgerEntry(account.ID, "", invoice.ID, domain.LedgerCredit, 100)},
+			Balances: map[string]float64{account.ID: 100},
+		}
+		if err := repos.Invoices.UpdateStatus(ctx, invoice, posting); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		if found, err = repos.Invoices.FindByID(ctx, invoice.ID); err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Status != domain.StatusSettled {
+			t.Errorf("Status = %q, want %q", found.Status, domain.StatusSettled)
+		}
+		credited, err := repos.Accounts.FindByID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if credited.Balance != 100 {
+			t.Errorf("Balance = %v, want 100", credited.Balance)
+		}
+
+		// O resultado repetido não credita de novo
+		if err := repos.Invoices.UpdateStatus(ctx, invoice, posting); !errors.Is(err, domain.ErrInvalidStatus) {
+			t.Errorf("second UpdateStatus error = %v, want ErrInvalidStatus", err)
+		}
+	})
+
+	t.Run("find by account", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		first := newInvoice(t, account.ID, 10)
+		if err := repos.Invoices.Save(ctx, first, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		batch := []*domain.Invoice{newInvoice(t, account.ID, 20), newInvoice(t, account.ID, 30)}
+		if err := repos.Invoices.SaveBatch(ctx, batch); err != nil {
+			t.Fatalf("SaveBatch: %v", err)
+		}
+		other := newInvoice(t, newAccount(t, repos.Accounts).ID, 40)
+		if err := repos.Invoices.Save(ctx, other, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		invoices, err := repos.Invoices.FindByAccountID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByAccountID: %v", err)
+		}
+		if len(invoices) != 3 {
+			t.Fatalf("FindByAccountID returned %d invoices, want 3", len(invoices))
+		}
+		for i := 1; i < len(invoices); i++ {
+			if invoices[i].CreatedAt.After(invoices[i-1].CreatedAt) {
+				t.Errorf("invoices not ordered by created_at desc: %v after %v", invoices[i].CreatedAt, invoices[i-1].CreatedAt)
+			}
+		}
+
+		if err := batch[0].TransitionTo(domain.StatusApproved); err != nil {
+			t.Fatalf("TransitionTo: %v", err)
+		}
+		if err := repos.Invoices.UpdateStatus(ctx, batch[0], nil); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		approved, err := repos.Invoices.FindByFilter(ctx, domain.InvoiceFilter{
+			AccountID: account.ID,
+			Statuses:  []domain.Status{domain.StatusApproved},
+		})
+		if err != nil {
+			t.Fatalf("FindByFilter: %v", err)
+		}
+		if len(approved) != 1 || approved[0].ID != batch[0].ID {
+			t.Errorf("FindByFilter returned %v, want only %q", approved, batch[0].ID)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repos := open(t)
+		account := newAccount(t, repos.Accounts)
+		invoice := newInvoice(t, account.ID, 100)
+		if err := repos.Invoices.Save(ctx, invoice, nil, nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		if err := repos.Invoices.Delete(ctx, invoice.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repos.Invoices.FindByID(ctx, invoice.ID); !errors.Is(err, domain.ErrInvoiceNotFound) {
+			t.Errorf("FindByID error = %v, want ErrInvoiceNotFound", err)
+		}
+		if _, err := repos.Invoices.FindByID(ctx, invoice.ID, domain.IncludeDeleted()); err != nil {
+			t.Errorf("FindByID with IncludeDeleted: %v", err)
+		}
+		invoices, err := repos.Invoices.FindByAccountID(ctx, account.ID)
+		if err != nil {
+			t.Fatalf("FindByAccountID: %v", err)
+		}
+		if len(invoices) != 0 {
+			t.Errorf("FindByAccountID returned %d invoices, want 0", len(invoices))
+		}
+		if err := repos.Invoices.Delete(ctx, invoice.ID); !errors.Is(err, domain.ErrInvoiceNotFound) {
+			t.Errorf("second Delete error = %v, want ErrInvoiceNotFound", err)
+		}
+	})
+}
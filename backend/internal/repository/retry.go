@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RetryAccountRepository repete as operações do repositório de contas após erros transitórios
+// Cada operação do repositório é uma transação completa, então repeti-la é seguro
+type RetryAccountRepository struct {
+	next   domain.AccountRepository
+	policy database.RetryPolicy
+}
+
+// NewRetryAccountRepository envolve o repositório informado com a política de retentativa
+func NewRetryAccountRepository(next domain.AccountRepository, policy database.RetryPolicy) *RetryAccountRepository {
+	return &RetryAccountRepository{next: next, policy: policy}
+}
+
+func (r *RetryAccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	return r.policy.Do(ctx, "account.save", func() error {
+		return r.next.Save(ctx, account)
+	})
+}
+
+func (r *RetryAccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (account *domain.Account, err error) {
+	err = r.policy.Do(ctx, "account.find_by_api_key", func() error {
+		account, err = r.next.FindByAPIKey(ctx, apiKey)
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryAccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (account *domain.Account, err error) {
+	err = r.policy.Do(ctx, "account.find_by_id", func() error {
+		account, err = r.next.FindByID(ctx, id, opts...)
+		return err
+	})
+	return account, err
+}
+
+func (r *RetryAccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) error {
+	return r.policy.Do(ctx, "account.update_balance", func() error {
+		return r.next.UpdateBalance(ctx, account)
+	})
+}
+
+func (r *RetryAccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	return r.policy.Do(ctx, "account.update_role", func() error {
+		return r.next.UpdateRole(ctx, id, role)
+	})
+}
+
+func (r *RetryAccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) error {
+	return r.policy.Do(ctx, "account.update_tenant", func() error {
+		return r.next.UpdateTenant(ctx, id, tenantID)
+	})
+}
+
+func (r *RetryAccountRepository) Delete(ctx context.Context, id string) error {
+	return r.policy.Do(ctx, "account.delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *RetryAccountRepository) ListAfter(ctx context.Context, afterID string, limit int) (accounts []*domain.Account, err error) {
+	err = r.policy.Do(ctx, "account.list_after", func() error {
+		accounts, err = r.next.ListAfter(ctx, afterID, limit)
+		return err
+	})
+	return accounts, err
+}
+
+func (r *RetryAccountRepository) CountDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	err = r.policy.Do(ctx, "account.count_deleted", func() error {
+		count, err = r.next.CountDeleted(ctx, before)
+		return err
+	})
+	return count, err
+}
+
+func (r *RetryAccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	err = r.policy.Do(ctx, "account.purge_deleted", func() error {
+		count, err = r.next.PurgeDeleted(ctx, before)
+		return err
+	})
+	return count, err
+}
+
+// RetryInvoiceRepository repete as operações do repositório de faturas após erros transitórios
+type RetryInvoiceRepository struct {
+	next   domain.InvoiceRepository
+	policy database.RetryPolicy
+}
+
+// NewRetryInvoiceRepository envolve o repositório informado com a política de retentativa
+func NewRetryInvoiceRepository(next domain.InvoiceRepository, policy database.RetryPolicy) *RetryInvoiceRepository {
+	return &RetryInvoiceRepository{next: next, policy: policy}
+}
+
+func (r *RetryInvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) error {
+	return r.policy.Do(ctx, "invoice.save", func() error {
+		return r.next.Save(ctx, invoice, splits, posting)
+	})
+}
+
+func (r *RetryInvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) error {
+	return r.policy.Do(ctx, "invoice.save_batch", func() error {
+		return r.next.SaveBatch(ctx, invoices)
+	})
+}
+
+func (r *RetryInvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (invoice *domain.Invoice, err error) {
+	err = r.policy.Do(ctx, "invoice.find_by_id", func() error {
+		invoice, err = r.next.FindByID(ctx, id, opts...)
+		return err
+	})
+	return invoice, err
+}
+
+func (r *RetryInvoiceRepository) FindByAccountID(ctx context.Context, accountID string) (invoices []*domain.Invoice, err error) {
+	err = r.policy.Do(ctx, "invoice.find_by_account_id", func() error {
+		invoices, err = r.next.FindByAccountID(ctx, accountID)
+		return err
+	})
+	return invoices, err
+}
+
+func (r *RetryInvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) (invoices []*domain.Invoice, err error) {
+	err = r.policy.Do(ctx, "invoice.find_by_filter", func() error {
+		invoices, err = r.next.FindByFilter(ctx, filter)
+		return err
+	})
+	return invoices, err
+}
+
+func (r *RetryInvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) error {
+	return r.policy.Do(ctx, "invoice.update_status", func() error {
+		return r.next.UpdateStatus(ctx, invoice, posting)
+	})
+}
+
+func (r *RetryInvoiceRepository) Delete(ctx context.Context, id string) error {
+	return r.policy.Do(ctx, "invoice.delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *RetryInvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) (activity []domain.AccountActivity, err error) {
+	err = r.policy.Do(ctx, "invoice.summarize_activity", func() error {
+		activity, err = r.next.SummarizeActivity(ctx, from, to)
+		return err
+	})
+	return activity, err
+}
+
+func (r *RetryInvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) (totals []domain.StatusTotals, err error) {
+	err = r.policy.Do(ctx, "invoice.summarize_by_status", func() error {
+		totals, err = r.next.SummarizeByStatus(ctx, accountID, from, to)
+		return err
+	})
+	return totals, err
+}
+
+func (r *RetryInvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (totals domain.SpendingTotals, err error) {
+	err = r.policy.Do(ctx, "invoice.sum_spending", func() error {
+		totals, err = r.next.SumSpending(ctx, accountID, dayStart, monthStart)
+		return err
+	})
+	return totals, err
+}
+
+func (r *RetryInvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) (invoices []*domain.Invoice, err error) {
+	err = r.policy.Do(ctx, "invoice.find_by_payer", func() error {
+		invoices, err = r.next.FindByPayer(ctx, accountID, payerName)
+		return err
+	})
+	return invoices, err
+}
+
+func (r *RetryInvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (count int64, err error) {
+	err = r.policy.Do(ctx, "invoice.anonymize_payer", func() error {
+		count, err = r.next.AnonymizePayer(ctx, accountID, payerName, token)
+		return err
+	})
+	return count, err
+}
+
+func (r *RetryInvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	err = r.policy.Do(ctx, "invoice.count_deleted", func() error {
+		count, err = r.next.CountDeleted(ctx, before)
+		return err
+	})
+	return count, err
+}
+
+func (r *RetryInvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (count int64, err error) {
+	err = r.policy.Do(ctx, "invoice.purge_deleted", func() error {
+		count, err = r.next.PurgeDeleted(ctx, before)
+		return err
+	})
+	return count, err
+}
+
+// RetryUserRepository repete as operações do repositório de usuários após erros transitórios
+type RetryUserRepository struct {
+	next   domain.UserRepository
+	policy database.RetryPolicy
+}
+
+// NewRetryUserRepository envolve o repositório informado com a política de retentativa
+func NewRetryUserRepository(next domain.UserRepository, policy database.RetryPolicy) *RetryUserRepository {
+	return &RetryUserRepository{next: next, policy: policy}
+}
+
+func (r *RetryUserRepository) Save(ctx context.Context, user *domain.User) error {
+	return r.policy.Do(ctx, "user.save", func() error {
+		return r.next.Save(ctx, user)
+	})
+}
+
+func (r *RetryUserRepository) FindByEmail(ctx context.Context, email string) (user *domain.User, err error) {
+	err = r.policy.Do(ctx, "user.find_by_email", func() error {
+		user, err = r.next.FindByEmail(ctx, email)
+		return err
+	})
+	return user, err
+}
+
+func (r *RetryUserRepository) FindByID(ctx context.Context, id string) (user *domain.User, err error) {
+	err = r.policy.Do(ctx, "user.find_by_id", func() error {
+		user, err = r.next.FindByID(ctx, id)
+		return err
+	})
+	return user, err
+}
+
+func (r *RetryUserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	return r.policy.Do(ctx, "user.update_role", func() error {
+		return r.next.UpdateRole(ctx, id, role)
+	})
+}
+
+func (r *RetryUserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	return r.policy.Do(ctx, "user.update_password", func() error {
+		return r.next.UpdatePassword(ctx, id, passwordHash)
+	})
+}
+
+func (r *RetryUserRepository) SaveRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	return r.policy.Do(ctx, "user.save_refresh_token", func() error {
+		return r.next.SaveRefreshToken(ctx, token)
+	})
+}
+
+func (r *RetryUserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (token *domain.RefreshToken, err error) {
+	err = r.policy.Do(ctx, "user.find_refresh_token", func() error {
+		token, err = r.next.FindRefreshToken(ctx, tokenHash)
+		return err
+	})
+	return token, err
+}
+
+func (r *RetryUserRepository) FindActiveRefreshTokens(ctx context.Context, userID string) (tokens []*domain.RefreshToken, err error) {
+	err = r.policy.Do(ctx, "user.find_active_refresh_tokens", func() error {
+		tokens, err = r.next.FindActiveRefreshTokens(ctx, userID)
+		return err
+	})
+	return tokens, err
+}
+
+func (r *RetryUserRepository) RevokeRefreshToken(ctx context.Context, id string) error {
+	return r.policy.Do(ctx, "user.revoke_refresh_token", func() error {
+		return r.next.RevokeRefreshToken(ctx, id)
+	})
+}
+
+func (r *RetryUserRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	return r.policy.Do(ctx, "user.revoke_session", func() error {
+		return r.next.RevokeSession(ctx, userID, sessionID)
+	})
+}
+
+func (r *RetryUserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	return r.policy.Do(ctx, "user.revoke_user_refresh_tokens", func() error {
+		return r.next.RevokeUserRefreshTokens(ctx, userID)
+	})
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// reviewColumns são as colunas lidas por scanInvoiceReview, na mesma ordem
+const reviewColumns = "invoice_id, account_id, amount, score, status, due_at, decided_by, decided_at, created_at"
+
+// ReviewRepository implementa a persistência da fila de revisão manual das faturas
+type ReviewRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewReviewRepository cria um novo repositório da fila de revisão para o banco do dialeto informado
+func NewReviewRepository(db *sql.DB, dialect Dialect) *ReviewRepository {
+	return &ReviewRepository{db: db, dialect: dialect}
+}
+
+// Create abre a revisão da fatura
+// Retorna ErrReviewExists quando a fatura já tem revisão
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.InvoiceReview) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO invoice_reviews ("+reviewColumns+") VALUES "+valuesPlaceholders(1, 9)),
+		review.InvoiceID, review.AccountID, review.Amount, review.Score, review.Status, review.DueAt,
+		review.DecidedBy, review.DecidedAt, review.CreatedAt,
+	)
+	if database.IsUniqueViolation(err) {
+		return domain.ErrReviewExists
+	}
+	return err
+}
+
+// FindByInvoiceID busca a revisão da fatura
+// Retorna ErrReviewNotFound se a fatura não tiver revisão
+func (r *ReviewRepository) FindByInvoiceID(ctx context.Context, invoiceID string) (*domain.InvoiceReview, error) {
+	review, err := scanInvoiceReview(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+reviewColumns+" FROM invoice_reviews WHERE invoice_id = ?"),
+		invoiceID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrReviewNotFound
+	}
+	return review, err
+}
+
+// List retorna até limit revisões da conta, ou de todas com accountID vazio, na situação informada ou em todas
+// com status vazio, das mais antigas para as mais novas
+func (r *ReviewRepository) List(ctx context.Context, accountID string, status domain.ReviewStatus, limit int) ([]*domain.InvoiceReview, error) {
+	builder := newQueryBuilder(r.dialect)
+	if accountID != "" {
+		builder.where("account_id = ?", accountID)
+	}
+	if status != "" {
+		builder.where("status = ?", status)
+	}
+	query, args := builder.build("SELECT "+reviewColumns+" FROM invoice_reviews", "ORDER BY created_at, invoice_id LIMIT "+strconv.Itoa(limit))
+	return r.query(ctx, query, args...)
+}
+
+// ListDue retorna até limit revisões abertas com prazo até before, das que venceram primeiro
+func (r *ReviewRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.InvoiceReview, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+reviewColumns+" FROM invoice_reviews WHERE status = ? AND due_at <= ? ORDER BY due_at, invoice_id LIMIT "+strconv.Itoa(limit)),
+		domain.ReviewOpen, before,
+	)
+}
+
+// Decide grava a decisão se a revisão ainda estiver aberta
+// Retorna ErrReviewAlreadyDecided quando outra decisão chegou antes
+func (r *ReviewRepository) Decide(ctx context.Context, review *domain.InvoiceReview) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE invoice_reviews SET status = ?, decided_by = ?, decided_at = ? WHERE invoice_id = ? AND status = ?"),
+		review.Status, review.DecidedBy, review.DecidedAt, review.InvoiceID, domain.ReviewOpen,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrReviewAlreadyDecided
+	}
+	return nil
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna revisões completas
+func (r *ReviewRepository) query(ctx context.Context, query string, args ...any) ([]*domain.InvoiceReview, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*domain.InvoiceReview
+	for rows.Next() {
+		review, err := scanInvoiceReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// scanInvoiceReview lê uma linha com as colunas de reviewColumns
+func scanInvoiceReview(row rowScanner) (*domain.InvoiceReview, error) {
+	var review domain.InvoiceReview
+	var decidedAt sql.NullTime
+	err := row.Scan(&review.InvoiceID, &review.AccountID, &review.Amount, &review.Score, &review.Status, &review.DueAt,
+		&review.DecidedBy, &decidedAt, &review.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if decidedAt.Valid {
+		review.DecidedAt = &decidedAt.Time
+	}
+	return &review, nil
+}
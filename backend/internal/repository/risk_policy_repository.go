@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// RiskPolicyRepository implementa a persistência dos limites de score de risco das contas
+type RiskPolicyRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewRiskPolicyRepository cria um novo repositório de limites de score para o banco do dialeto informado
+func NewRiskPolicyRepository(db *sql.DB, dialect Dialect) *RiskPolicyRepository {
+	return &RiskPolicyRepository{db: db, dialect: dialect}
+}
+
+// Save substitui os limites da conta em uma transação
+func (r *RiskPolicyRepository) Save(ctx context.Context, policy *domain.RiskPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM risk_policies WHERE account_id = ?"), policy.AccountID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO risk_policies (account_id, approve_below, reject_above, updated_at) VALUES "+valuesPlaceholders(1, 4)),
+		policy.AccountID, policy.ApproveBelow, policy.RejectAbove, policy.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca os limites da conta
+// Retorna ErrRiskPolicyNotFound se a conta usar os limites padrão
+func (r *RiskPolicyRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.RiskPolicy, error) {
+	var policy domain.RiskPolicy
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, approve_below, reject_above, updated_at FROM risk_policies WHERE account_id = ?"),
+		accountID,
+	).Scan(&policy.AccountID, &policy.ApproveBelow, &policy.RejectAbove, &policy.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRiskPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// SecurityWebhookRepository implementa a persistência das assinaturas de eventos de segurança das contas
+// Os tipos de eventos são gravados separados por vírgulas
+type SecurityWebhookRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSecurityWebhookRepository cria um novo repositório de assinaturas de segurança para o banco do dialeto informado
+func NewSecurityWebhookRepository(db *sql.DB, dialect Dialect) *SecurityWebhookRepository {
+	return &SecurityWebhookRepository{db: db, dialect: dialect}
+}
+
+// Save substitui a assinatura da conta em uma transação
+func (r *SecurityWebhookRepository) Save(ctx context.Context, webhook *domain.SecurityWebhook) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.dialect.rebind("DELETE FROM security_webhooks WHERE account_id = ?"), webhook.AccountID); err != nil {
+		return err
+	}
+
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO security_webhooks (account_id, url, events, updated_at) VALUES "+valuesPlaceholders(1, 4)),
+		webhook.AccountID, webhook.URL, strings.Join(events, ","), webhook.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByAccountID busca a assinatura da conta
+// Retorna ErrSecurityWebhookNotFound se a conta não tiver assinatura
+func (r *SecurityWebhookRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.SecurityWebhook, error) {
+	var webhook domain.SecurityWebhook
+	var events string
+
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT account_id, url, events, updated_at FROM security_webhooks WHERE account_id = ?"),
+		accountID,
+	).Scan(&webhook.AccountID, &webhook.URL, &events, &webhook.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSecurityWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if events != "" {
+		for _, event := range strings.Split(events, ",") {
+			webhook.Events = append(webhook.Events, domain.SecurityEventType(event))
+		}
+	}
+	return &webhook, nil
+}
+
+// Delete remove a assinatura da conta
+// Retorna ErrSecurityWebhookNotFound se a conta não tiver assinatura
+func (r *SecurityWebhookRepository) Delete(ctx context.Context, accountID string) error {
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM security_webhooks WHERE account_id = ?"), accountID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrSecurityWebhookNotFound
+	}
+	return nil
+}
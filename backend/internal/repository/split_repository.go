@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// splitColumns são as colunas das partes das faturas divididas, na ordem lida por FindByInvoiceID
+const splitColumns = "invoice_id, recipient_id, amount, fee, created_at"
+
+// SplitRepository implementa a persistência das partes das faturas divididas
+type SplitRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSplitRepository cria um novo repositório das partes das faturas para o banco do dialeto informado
+func NewSplitRepository(db *sql.DB, dialect Dialect) *SplitRepository {
+	return &SplitRepository{db: db, dialect: dialect}
+}
+
+// SaveBatch grava as partes de uma ou mais faturas em um único INSERT
+func (r *SplitRepository) SaveBatch(ctx context.Context, splits []*domain.InvoiceSplit) error {
+	return insertSplits(ctx, r.db, r.dialect, splits)
+}
+
+// insertSplits grava as partes em um único INSERT, também dentro da transação de InvoiceRepository.Save
+func insertSplits(ctx context.Context, tx execer, dialect Dialect, splits []*domain.InvoiceSplit) error {
+	if len(splits) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(splits)*5)
+	for _, split := range splits {
+		args = append(args, split.InvoiceID, split.RecipientID, split.Amount, split.Fee, split.CreatedAt)
+	}
+	_, err := tx.ExecContext(ctx,
+		dialect.rebind("INSERT INTO invoice_splits ("+splitColumns+") VALUES "+valuesPlaceholders(len(splits), 5)),
+		args...,
+	)
+	return err
+}
+
+// FindByInvoiceID retorna as partes da fatura, ordenadas pela conta recebedora, ou nenhuma se ela não foi dividida
+func (r *SplitRepository) FindByInvoiceID(ctx context.Context, invoiceID string) ([]*domain.InvoiceSplit, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+splitColumns+" FROM invoice_splits WHERE invoice_id = ? ORDER BY recipient_id"),
+		invoiceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []*domain.InvoiceSplit
+	for rows.Next() {
+		var split domain.InvoiceSplit
+		if err := rows.Scan(&split.InvoiceID, &split.RecipientID, &split.Amount, &split.Fee, &split.CreatedAt); err != nil {
+			return nil, err
+		}
+		splits = append(splits, &split)
+	}
+	return splits, rows.Err()
+}
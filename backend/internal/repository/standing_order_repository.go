@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// standingOrderColumns são as colunas lidas por scanStandingOrder, na mesma ordem
+const standingOrderColumns = "id, account_id, destination_id, percent, amount, interval_days, status, webhook_url, next_run_at, " +
+	"last_run_at, last_amount, failed_runs, last_failure, cancelled_at, created_at, updated_at"
+
+// StandingOrderRepository implementa a persistência das ordens permanentes
+type StandingOrderRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewStandingOrderRepository cria um novo repositório de ordens permanentes para o banco do dialeto informado
+func NewStandingOrderRepository(db *sql.DB, dialect Dialect) *StandingOrderRepository {
+	return &StandingOrderRepository{db: db, dialect: dialect}
+}
+
+// Create grava a ordem
+func (r *StandingOrderRepository) Create(ctx context.Context, order *domain.StandingOrder) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO standing_orders ("+standingOrderColumns+") VALUES "+valuesPlaceholders(1, 16)),
+		order.ID, order.AccountID, order.DestinationID, order.Percent, order.Amount, order.IntervalDays, order.Status,
+		order.WebhookURL, order.NextRunAt, order.LastRunAt, order.LastAmount, order.FailedRuns, order.LastFailure,
+		order.CancelledAt, order.CreatedAt, order.UpdatedAt,
+	)
+	return err
+}
+
+// FindByID busca a ordem pelo ID
+// Retorna ErrStandingOrderNotFound se a ordem não existir
+func (r *StandingOrderRepository) FindByID(ctx context.Context, id string) (*domain.StandingOrder, error) {
+	order, err := scanStandingOrder(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+standingOrderColumns+" FROM standing_orders WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrStandingOrderNotFound
+	}
+	return order, err
+}
+
+// List retorna até limit ordens da conta na situação informada, ou em todas com status vazio, das mais novas para
+// as mais antigas
+func (r *StandingOrderRepository) List(ctx context.Context, accountID string, status domain.StandingOrderStatus, limit int) ([]*domain.StandingOrder, error) {
+	builder := newQueryBuilder(r.dialect).where("account_id = ?", accountID)
+	if status != "" {
+		builder.where("status = ?", status)
+	}
+	query, args := builder.build("SELECT "+standingOrderColumns+" FROM standing_orders", "ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit))
+	return r.query(ctx, query, args...)
+}
+
+// ListDue retorna até limit ordens ativas com transferência até before, das mais atrasadas primeiro
+func (r *StandingOrderRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.StandingOrder, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+standingOrderColumns+" FROM standing_orders WHERE status = ? AND next_run_at <= ? ORDER BY next_run_at, id LIMIT "+strconv.Itoa(limit)),
+		domain.StandingOrderActive, before,
+	)
+}
+
+// Update grava a ordem se a situação gravada ainda for from
+// Retorna ErrStandingOrderChanged quando ela mudou antes
+func (r *StandingOrderRepository) Update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	return updateStandingOrder(ctx, r.db, r.dialect, order, from)
+}
+
+// Run executa a ordem ativa sobre o saldo da conta em uma transação; os saldos da conta e do destino são travados
+// com SELECT FOR UPDATE antes, na ordem dos IDs como em writePosting, para que um débito simultâneo espere a
+// transferência em vez de deixá-la calculada sobre um saldo que já saiu
+// Retorna ErrStandingOrderChanged quando ela deixou de estar ativa e ErrAccountNotFound sem uma das contas
+func (r *StandingOrderRepository) Run(ctx context.Context, order *domain.StandingOrder, now time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ids := []string{order.AccountID, order.DestinationID}
+	sort.Strings(ids)
+	var balance float64
+	for _, id := range ids {
+		var locked float64
+		err := tx.QueryRowContext(ctx,
+			r.dialect.rebind("SELECT balance FROM accounts WHERE id = ? AND deleted_at IS NULL FOR UPDATE"),
+			id,
+		).Scan(&locked)
+		if err == sql.ErrNoRows {
+			return domain.ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if id == order.AccountID {
+			balance = locked
+		}
+	}
+
+	run := *order
+	posting := run.Run(balance, now)
+	if err := updateStandingOrder(ctx, tx, r.dialect, &run, domain.StandingOrderActive); err != nil {
+		return err
+	}
+	if posting != nil {
+		if err := writePosting(ctx, tx, r.dialect, posting); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*order = run
+	return nil
+}
+
+// updateStandingOrder grava a execução ou o cancelamento da ordem se a situação gravada ainda for from
+// Retorna ErrStandingOrderChanged quando ela mudou antes
+func updateStandingOrder(ctx context.Context, db execer, dialect Dialect, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	result, err := db.ExecContext(ctx,
+		dialect.rebind("UPDATE standing_orders SET status = ?, next_run_at = ?, last_run_at = ?, last_amount = ?, failed_runs = ?, "+
+			"last_failure = ?, cancelled_at = ?, updated_at = ? WHERE id = ? AND status = ?"),
+		order.Status, order.NextRunAt, order.LastRunAt, order.LastAmount, order.FailedRuns, order.LastFailure,
+		order.CancelledAt, order.UpdatedAt, order.ID, from,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrStandingOrderChanged
+	}
+	return nil
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna ordens completas
+func (r *StandingOrderRepository) query(ctx context.Context, query string, args ...any) ([]*domain.StandingOrder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.StandingOrder
+	for rows.Next() {
+		order, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// scanStandingOrder lê uma linha com as colunas de standingOrderColumns
+func scanStandingOrder(row rowScanner) (*domain.StandingOrder, error) {
+	var order domain.StandingOrder
+	var nextRunAt, lastRunAt, cancelledAt sql.NullTime
+	err := row.Scan(&order.ID, &order.AccountID, &order.DestinationID, &order.Percent, &order.Amount, &order.IntervalDays,
+		&order.Status, &order.WebhookURL, &nextRunAt, &lastRunAt, &order.LastAmount, &order.FailedRuns, &order.LastFailure,
+		&cancelledAt, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if nextRunAt.Valid {
+		order.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		order.LastRunAt = &lastRunAt.Time
+	}
+	if cancelledAt.Valid {
+		order.CancelledAt = &cancelledAt.Time
+	}
+	return &order, nil
+}
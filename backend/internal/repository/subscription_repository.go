@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// subscriptionColumns são as colunas lidas por scanSubscription, na mesma ordem
+const subscriptionColumns = "id, account_id, card_token, card_brand, card_last_digits, description, amount, interval_days, status, " +
+	"webhook_url, due_at, next_attempt_at, failed_attempts, last_invoice_id, cancelled_at, created_at, updated_at"
+
+// SubscriptionRepository implementa a persistência das assinaturas
+type SubscriptionRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSubscriptionRepository cria um novo repositório de assinaturas para o banco do dialeto informado
+func NewSubscriptionRepository(db *sql.DB, dialect Dialect) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db, dialect: dialect}
+}
+
+// Create grava a assinatura
+func (r *SubscriptionRepository) Create(ctx context.Context, subscription *domain.Subscription) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO subscriptions ("+subscriptionColumns+") VALUES "+valuesPlaceholders(1, 17)),
+		subscription.ID, subscription.AccountID, subscription.CardToken, subscription.CardBrand, subscription.CardLastDigits,
+		subscription.Description, subscription.Amount, subscription.IntervalDays, subscription.Status, subscription.WebhookURL,
+		subscription.DueAt, subscription.NextAttemptAt, subscription.FailedAttempts, subscription.LastInvoiceID,
+		subscription.CancelledAt, subscription.CreatedAt, subscription.UpdatedAt,
+	)
+	return err
+}
+
+// FindByID busca a assinatura pelo ID
+// Retorna ErrSubscriptionNotFound se a assinatura não existir
+func (r *SubscriptionRepository) FindByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+subscriptionColumns+" FROM subscriptions WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	return subscription, err
+}
+
+// List retorna até limit assinaturas da conta na situação informada, ou em todas com status vazio, das mais novas
+// para as mais antigas
+func (r *SubscriptionRepository) List(ctx context.Context, accountID string, status domain.SubscriptionStatus, limit int) ([]*domain.Subscription, error) {
+	builder := newQueryBuilder(r.dialect).where("account_id = ?", accountID)
+	if status != "" {
+		builder.where("status = ?", status)
+	}
+	query, args := builder.build("SELECT "+subscriptionColumns+" FROM subscriptions", "ORDER BY created_at DESC, id LIMIT "+strconv.Itoa(limit))
+	return r.query(ctx, query, args...)
+}
+
+// ListDue retorna até limit assinaturas ativas com tentativa até before, das mais atrasadas primeiro
+func (r *SubscriptionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	return r.query(ctx,
+		r.dialect.rebind("SELECT "+subscriptionColumns+" FROM subscriptions WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT "+strconv.Itoa(limit)),
+		domain.SubscriptionActive, before,
+	)
+}
+
+// Update grava a assinatura se a situação gravada ainda for from
+// Retorna ErrSubscriptionChanged quando ela mudou antes
+func (r *SubscriptionRepository) Update(ctx context.Context, subscription *domain.Subscription, from domain.SubscriptionStatus) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE subscriptions SET status = ?, due_at = ?, next_attempt_at = ?, failed_attempts = ?, last_invoice_id = ?, "+
+			"cancelled_at = ?, updated_at = ? WHERE id = ? AND status = ?"),
+		subscription.Status, subscription.DueAt, subscription.NextAttemptAt, subscription.FailedAttempts, subscription.LastInvoiceID,
+		subscription.CancelledAt, subscription.UpdatedAt, subscription.ID, from,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrSubscriptionChanged
+	}
+	return nil
+}
+
+// query executa uma consulta já traduzida pelo dialeto que retorna assinaturas completas
+func (r *SubscriptionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.Subscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// scanSubscription lê uma linha com as colunas de subscriptionColumns
+func scanSubscription(row rowScanner) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	var nextAttemptAt, cancelledAt sql.NullTime
+	err := row.Scan(&subscription.ID, &subscription.AccountID, &subscription.CardToken, &subscription.CardBrand,
+		&subscription.CardLastDigits, &subscription.Description, &subscription.Amount, &subscription.IntervalDays,
+		&subscription.Status, &subscription.WebhookURL, &subscription.DueAt, &nextAttemptAt, &subscription.FailedAttempts,
+		&subscription.LastInvoiceID, &cancelledAt, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if nextAttemptAt.Valid {
+		subscription.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if cancelledAt.Valid {
+		subscription.CancelledAt = &cancelledAt.Time
+	}
+	return &subscription, nil
+}
@@ -0,0 +1,696 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// TenantAccountRepository restringe as contas às da organização do contexto (requestctx.Tenant), como nas
+// requisições dos administradores de uma organização; as contas de outras organizações respondem
+// ErrAccountNotFound, como se não existissem
+// Sem organização no contexto, como nas requisições das próprias contas, dos administradores globais e nos jobs,
+// as operações passam direto
+type TenantAccountRepository struct {
+	next domain.AccountRepository
+}
+
+// NewTenantAccountRepository envolve o repositório informado com a restrição por organização
+func NewTenantAccountRepository(next domain.AccountRepository) *TenantAccountRepository {
+	return &TenantAccountRepository{next: next}
+}
+
+// visible indica se a conta pertence à organização do contexto ou se o contexto não tem organização
+func visible(ctx context.Context, account *domain.Account) bool {
+	tenantID := requestctx.Tenant(ctx)
+	return tenantID == "" || account.TenantID == tenantID
+}
+
+// jobOnly reserva a operação aos jobs, que rodam sem organização no contexto; com organização retorna ErrForbidden
+func jobOnly(ctx context.Context) error {
+	if requestctx.Tenant(ctx) != "" {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// check confere que a conta existe e pertence à organização do contexto antes de uma alteração
+func (r *TenantAccountRepository) check(ctx context.Context, id string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := r.FindByID(ctx, id)
+	return err
+}
+
+// Save grava a conta na organização do contexto, se houver
+func (r *TenantAccountRepository) Save(ctx context.Context, account *domain.Account) error {
+	if tenantID := requestctx.Tenant(ctx); tenantID != "" {
+		account.TenantID = tenantID
+	}
+	return r.next.Save(ctx, account)
+}
+
+func (r *TenantAccountRepository) FindByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	account, err := r.next.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(ctx, account) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (r *TenantAccountRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Account, error) {
+	account, err := r.next.FindByID(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(ctx, account) {
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (r *TenantAccountRepository) UpdateBalance(ctx context.Context, account *domain.Account) error {
+	if err := r.check(ctx, account.ID); err != nil {
+		return err
+	}
+	return r.next.UpdateBalance(ctx, account)
+}
+
+func (r *TenantAccountRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	if err := r.check(ctx, id); err != nil {
+		return err
+	}
+	return r.next.UpdateRole(ctx, id, role)
+}
+
+// UpdateTenant só é permitido sem organização no contexto: os administradores de uma organização não movem
+// contas entre organizações
+func (r *TenantAccountRepository) UpdateTenant(ctx context.Context, id, tenantID string) error {
+	if requestctx.Tenant(ctx) != "" {
+		return domain.ErrForbidden
+	}
+	return r.next.UpdateTenant(ctx, id, tenantID)
+}
+
+func (r *TenantAccountRepository) Delete(ctx context.Context, id string) error {
+	if err := r.check(ctx, id); err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// ListAfter percorre as páginas seguintes até juntar limit contas da organização do contexto
+func (r *TenantAccountRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.Account, error) {
+	if requestctx.Tenant(ctx) == "" {
+		return r.next.ListAfter(ctx, afterID, limit)
+	}
+
+	var accounts []*domain.Account
+	for len(accounts) < limit {
+		page, err := r.next.ListAfter(ctx, afterID, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range page {
+			if visible(ctx, account) && len(accounts) < limit {
+				accounts = append(accounts, account)
+			}
+		}
+		if len(page) < limit {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+	return accounts, nil
+}
+
+// CountDeleted e PurgeDeleted servem à limpeza periódica, que roda sem organização no contexto
+func (r *TenantAccountRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if err := jobOnly(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.CountDeleted(ctx, before)
+}
+
+func (r *TenantAccountRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if err := jobOnly(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.PurgeDeleted(ctx, before)
+}
+
+// TenantInvoiceRepository restringe as faturas às das contas da organização do contexto, conferindo a conta de
+// cada fatura em accounts; as faturas de contas de outras organizações respondem ErrInvoiceNotFound
+// Sem organização no contexto as operações passam direto
+type TenantInvoiceRepository struct {
+	tenantScope
+	next domain.InvoiceRepository
+}
+
+// NewTenantInvoiceRepository envolve o repositório informado com a restrição por organização; accounts deve ser
+// o repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantInvoiceRepository(next domain.InvoiceRepository, accounts domain.AccountRepository) *TenantInvoiceRepository {
+	return &TenantInvoiceRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+// tenantScope confere as contas dos repositórios restritos por organização no repositório de contas já restrito
+// por NewTenantAccountRepository
+type tenantScope struct {
+	accounts domain.AccountRepository
+}
+
+// checkAccount confere que a conta pertence à organização do contexto, inclusive se já foi excluída
+func (s tenantScope) checkAccount(ctx context.Context, accountID string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := s.accounts.FindByID(ctx, accountID, domain.IncludeDeleted())
+	return err
+}
+
+// checkPosting confere as contas dos saldos e dos lançamentos do movimento
+func (s tenantScope) checkPosting(ctx context.Context, posting *domain.Posting) error {
+	if posting == nil || requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	for _, accountID := range posting.AccountIDs() {
+		if err := s.checkAccount(ctx, accountID); err != nil {
+			return err
+		}
+	}
+	for _, entry := range posting.Entries {
+		if err := s.checkAccount(ctx, entry.AccountID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkList exige a conta quando o contexto tem organização, já que a listagem de todas as contas passaria pelas de
+// outras organizações
+func (s tenantScope) checkList(ctx context.Context, accountID string) error {
+	if requestctx.Tenant(ctx) != "" && accountID == "" {
+		return domain.ErrForbidden
+	}
+	return s.checkAccount(ctx, accountID)
+}
+
+// checkFound troca o ErrAccountNotFound de checkAccount pelo erro de registro inexistente do repositório, para que
+// os registros de outras organizações pareçam não existir
+func (s tenantScope) checkFound(ctx context.Context, accountID string, notFound error) error {
+	err := s.checkAccount(ctx, accountID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return notFound
+	}
+	return err
+}
+
+// checkInvoice confere que a fatura existe e é de uma conta da organização do contexto
+func (r *TenantInvoiceRepository) checkInvoice(ctx context.Context, id string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := r.FindByID(ctx, id, domain.IncludeDeleted())
+	return err
+}
+
+func (r *TenantInvoiceRepository) Save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit, posting *domain.Posting) error {
+	if err := r.checkAccount(ctx, invoice.AccountID); err != nil {
+		return err
+	}
+	return r.next.Save(ctx, invoice, splits, posting)
+}
+
+func (r *TenantInvoiceRepository) SaveBatch(ctx context.Context, invoices []*domain.Invoice) error {
+	for _, invoice := range invoices {
+		if err := r.checkAccount(ctx, invoice.AccountID); err != nil {
+			return err
+		}
+	}
+	return r.next.SaveBatch(ctx, invoices)
+}
+
+func (r *TenantInvoiceRepository) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*domain.Invoice, error) {
+	invoice, err := r.next.FindByID(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkFound(ctx, invoice.AccountID, domain.ErrInvoiceNotFound); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (r *TenantInvoiceRepository) FindByAccountID(ctx context.Context, accountID string) ([]*domain.Invoice, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.FindByAccountID(ctx, accountID)
+}
+
+// FindByFilter exige a conta no filtro quando o contexto tem organização, já que a busca em todas as contas
+// passaria pelas de outras organizações
+func (r *TenantInvoiceRepository) FindByFilter(ctx context.Context, filter domain.InvoiceFilter) ([]*domain.Invoice, error) {
+	if err := r.checkList(ctx, filter.AccountID); err != nil {
+		return nil, err
+	}
+	return r.next.FindByFilter(ctx, filter)
+}
+
+func (r *TenantInvoiceRepository) UpdateStatus(ctx context.Context, invoice *domain.Invoice, posting *domain.Posting) error {
+	if err := r.checkInvoice(ctx, invoice.ID); err != nil {
+		return err
+	}
+	return r.next.UpdateStatus(ctx, invoice, posting)
+}
+
+func (r *TenantInvoiceRepository) Delete(ctx context.Context, id string) error {
+	if err := r.checkInvoice(ctx, id); err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// SummarizeActivity descarta as contas fora da organização do contexto
+func (r *TenantInvoiceRepository) SummarizeActivity(ctx context.Context, from, to time.Time) ([]domain.AccountActivity, error) {
+	activity, err := r.next.SummarizeActivity(ctx, from, to)
+	if err != nil || requestctx.Tenant(ctx) == "" {
+		return activity, err
+	}
+
+	var scoped []domain.AccountActivity
+	for _, account := range activity {
+		err := r.checkAccount(ctx, account.AccountID)
+		if err == domain.ErrAccountNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scoped = append(scoped, account)
+	}
+	return scoped, nil
+}
+
+func (r *TenantInvoiceRepository) SummarizeByStatus(ctx context.Context, accountID string, from, to time.Time) ([]domain.StatusTotals, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.SummarizeByStatus(ctx, accountID, from, to)
+}
+
+func (r *TenantInvoiceRepository) SumSpending(ctx context.Context, accountID string, dayStart, monthStart time.Time) (domain.SpendingTotals, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return domain.SpendingTotals{}, err
+	}
+	return r.next.SumSpending(ctx, accountID, dayStart, monthStart)
+}
+
+func (r *TenantInvoiceRepository) FindByPayer(ctx context.Context, accountID, payerName string) ([]*domain.Invoice, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.FindByPayer(ctx, accountID, payerName)
+}
+
+func (r *TenantInvoiceRepository) AnonymizePayer(ctx context.Context, accountID, payerName, token string) (int64, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return 0, err
+	}
+	return r.next.AnonymizePayer(ctx, accountID, payerName, token)
+}
+
+// CountDeleted e PurgeDeleted servem à limpeza periódica, que roda sem organização no contexto
+func (r *TenantInvoiceRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if err := jobOnly(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.CountDeleted(ctx, before)
+}
+
+func (r *TenantInvoiceRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if err := jobOnly(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.PurgeDeleted(ctx, before)
+}
+
+// TenantFeeScheduleRepository restringe as tarifas às das contas da organização do contexto
+// Sem organização no contexto as operações passam direto
+type TenantFeeScheduleRepository struct {
+	tenantScope
+	next domain.FeeScheduleRepository
+}
+
+// NewTenantFeeScheduleRepository envolve o repositório informado com a restrição por organização; accounts deve
+// ser o repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantFeeScheduleRepository(next domain.FeeScheduleRepository, accounts domain.AccountRepository) *TenantFeeScheduleRepository {
+	return &TenantFeeScheduleRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+func (r *TenantFeeScheduleRepository) Save(ctx context.Context, schedule *domain.FeeSchedule) error {
+	if err := r.checkAccount(ctx, schedule.AccountID); err != nil {
+		return err
+	}
+	return r.next.Save(ctx, schedule)
+}
+
+func (r *TenantFeeScheduleRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.FindByAccountID(ctx, accountID)
+}
+
+func (r *TenantFeeScheduleRepository) Delete(ctx context.Context, accountID string) error {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, accountID)
+}
+
+// TenantPlatformLinkRepository restringe as ligações com plataformas às contas da organização do contexto, tanto a
+// conta filha quanto a plataforma
+// Sem organização no contexto as operações passam direto
+type TenantPlatformLinkRepository struct {
+	tenantScope
+	next domain.PlatformLinkRepository
+}
+
+// NewTenantPlatformLinkRepository envolve o repositório informado com a restrição por organização; accounts deve
+// ser o repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantPlatformLinkRepository(next domain.PlatformLinkRepository, accounts domain.AccountRepository) *TenantPlatformLinkRepository {
+	return &TenantPlatformLinkRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+func (r *TenantPlatformLinkRepository) Save(ctx context.Context, link *domain.PlatformLink) error {
+	if err := r.checkAccount(ctx, link.AccountID); err != nil {
+		return err
+	}
+	if err := r.checkAccount(ctx, link.PlatformID); err != nil {
+		return err
+	}
+	return r.next.Save(ctx, link)
+}
+
+func (r *TenantPlatformLinkRepository) FindByAccountID(ctx context.Context, accountID string) (*domain.PlatformLink, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.FindByAccountID(ctx, accountID)
+}
+
+func (r *TenantPlatformLinkRepository) CountByPlatformID(ctx context.Context, platformID string) (int64, error) {
+	if err := r.checkAccount(ctx, platformID); err != nil {
+		return 0, err
+	}
+	return r.next.CountByPlatformID(ctx, platformID)
+}
+
+func (r *TenantPlatformLinkRepository) Delete(ctx context.Context, accountID string) error {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, accountID)
+}
+
+// TenantLedgerRepository restringe o razão, os movimentos e a conciliação às contas da organização do contexto;
+// um movimento com uma conta de outra organização não é gravado
+// Sem organização no contexto as operações passam direto
+type TenantLedgerRepository struct {
+	tenantScope
+	next domain.LedgerRepository
+}
+
+// NewTenantLedgerRepository envolve o repositório informado com a restrição por organização; accounts deve ser o
+// repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantLedgerRepository(next domain.LedgerRepository, accounts domain.AccountRepository) *TenantLedgerRepository {
+	return &TenantLedgerRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+func (r *TenantLedgerRepository) CreateBatch(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if err := r.checkPosting(ctx, &domain.Posting{Entries: entries}); err != nil {
+		return err
+	}
+	return r.next.CreateBatch(ctx, entries)
+}
+
+func (r *TenantLedgerRepository) Post(ctx context.Context, posting *domain.Posting) error {
+	if err := r.checkPosting(ctx, posting); err != nil {
+		return err
+	}
+	return r.next.Post(ctx, posting)
+}
+
+func (r *TenantLedgerRepository) List(ctx context.Context, accountID string, from, to time.Time) ([]*domain.LedgerEntry, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, accountID, from, to)
+}
+
+func (r *TenantLedgerRepository) SumByType(ctx context.Context, accountID string) (map[domain.LedgerEntryType]float64, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.SumByType(ctx, accountID)
+}
+
+func (r *TenantLedgerRepository) Reconcile(ctx context.Context, accountID string, write, correct bool) (*domain.BalanceReconciliation, error) {
+	if err := r.checkAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.Reconcile(ctx, accountID, write, correct)
+}
+
+// TenantDisputeRepository restringe as disputas às das contas da organização do contexto; as disputas de contas
+// de outras organizações respondem ErrDisputeNotFound
+// Sem organização no contexto as operações passam direto
+type TenantDisputeRepository struct {
+	tenantScope
+	next domain.DisputeRepository
+}
+
+// NewTenantDisputeRepository envolve o repositório informado com a restrição por organização; accounts deve ser o
+// repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantDisputeRepository(next domain.DisputeRepository, accounts domain.AccountRepository) *TenantDisputeRepository {
+	return &TenantDisputeRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+// checkDispute confere que a disputa existe e é de uma conta da organização do contexto
+func (r *TenantDisputeRepository) checkDispute(ctx context.Context, id string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := r.FindByID(ctx, id)
+	return err
+}
+
+func (r *TenantDisputeRepository) Create(ctx context.Context, dispute *domain.Dispute, posting *domain.Posting) error {
+	if err := r.checkAccount(ctx, dispute.AccountID); err != nil {
+		return err
+	}
+	if err := r.checkPosting(ctx, posting); err != nil {
+		return err
+	}
+	return r.next.Create(ctx, dispute, posting)
+}
+
+func (r *TenantDisputeRepository) FindByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	dispute, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkFound(ctx, dispute.AccountID, domain.ErrDisputeNotFound); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (r *TenantDisputeRepository) List(ctx context.Context, accountID string, status domain.DisputeStatus, limit int) ([]*domain.Dispute, error) {
+	if err := r.checkList(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, accountID, status, limit)
+}
+
+func (r *TenantDisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	if err := r.checkDispute(ctx, dispute.ID); err != nil {
+		return err
+	}
+	return r.next.Resolve(ctx, dispute)
+}
+
+func (r *TenantDisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	if err := r.checkDispute(ctx, evidence.DisputeID); err != nil {
+		return err
+	}
+	return r.next.AddEvidence(ctx, evidence)
+}
+
+func (r *TenantDisputeRepository) ListEvidence(ctx context.Context, disputeID string) ([]*domain.DisputeEvidence, error) {
+	if err := r.checkDispute(ctx, disputeID); err != nil {
+		return nil, err
+	}
+	return r.next.ListEvidence(ctx, disputeID)
+}
+
+// TenantRefundRepository restringe os reembolsos aos das contas da organização do contexto; os reembolsos de
+// contas de outras organizações respondem ErrRefundNotFound
+// Sem organização no contexto as operações passam direto
+type TenantRefundRepository struct {
+	tenantScope
+	next     domain.RefundRepository
+	invoices domain.InvoiceRepository
+}
+
+// NewTenantRefundRepository envolve o repositório informado com a restrição por organização; accounts e invoices
+// devem ser os repositórios já restritos por NewTenantAccountRepository e NewTenantInvoiceRepository
+func NewTenantRefundRepository(next domain.RefundRepository, accounts domain.AccountRepository, invoices domain.InvoiceRepository) *TenantRefundRepository {
+	return &TenantRefundRepository{tenantScope: tenantScope{accounts: accounts}, next: next, invoices: invoices}
+}
+
+// checkRefund confere que o reembolso existe e é de uma conta da organização do contexto
+func (r *TenantRefundRepository) checkRefund(ctx context.Context, id string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := r.FindByID(ctx, id)
+	return err
+}
+
+func (r *TenantRefundRepository) Create(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	if err := r.checkAccount(ctx, refund.AccountID); err != nil {
+		return err
+	}
+	if err := r.checkPosting(ctx, posting); err != nil {
+		return err
+	}
+	return r.next.Create(ctx, refund, posting)
+}
+
+func (r *TenantRefundRepository) FindByID(ctx context.Context, id string) (*domain.Refund, error) {
+	refund, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkFound(ctx, refund.AccountID, domain.ErrRefundNotFound); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (r *TenantRefundRepository) List(ctx context.Context, accountID string, status domain.RefundStatus, limit int) ([]*domain.Refund, error) {
+	if err := r.checkList(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, accountID, status, limit)
+}
+
+// ListExpired serve à expiração periódica, que roda sem organização no contexto
+func (r *TenantRefundRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.Refund, error) {
+	if err := jobOnly(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListExpired(ctx, before, limit)
+}
+
+func (r *TenantRefundRepository) Decide(ctx context.Context, refund *domain.Refund, posting *domain.Posting) error {
+	if err := r.checkRefund(ctx, refund.ID); err != nil {
+		return err
+	}
+	if err := r.checkPosting(ctx, posting); err != nil {
+		return err
+	}
+	return r.next.Decide(ctx, refund, posting)
+}
+
+func (r *TenantRefundRepository) SumByInvoiceID(ctx context.Context, invoiceID string) (float64, error) {
+	if requestctx.Tenant(ctx) != "" {
+		if _, err := r.invoices.FindByID(ctx, invoiceID, domain.IncludeDeleted()); err != nil {
+			return 0, err
+		}
+	}
+	return r.next.SumByInvoiceID(ctx, invoiceID)
+}
+
+// TenantStandingOrderRepository restringe as ordens permanentes às das contas da organização do contexto; as
+// ordens de contas de outras organizações respondem ErrStandingOrderNotFound
+// Sem organização no contexto as operações passam direto
+type TenantStandingOrderRepository struct {
+	tenantScope
+	next domain.StandingOrderRepository
+}
+
+// NewTenantStandingOrderRepository envolve o repositório informado com a restrição por organização; accounts deve
+// ser o repositório de contas já restrito por NewTenantAccountRepository
+func NewTenantStandingOrderRepository(next domain.StandingOrderRepository, accounts domain.AccountRepository) *TenantStandingOrderRepository {
+	return &TenantStandingOrderRepository{tenantScope: tenantScope{accounts: accounts}, next: next}
+}
+
+// checkOrder confere que a ordem existe e é de uma conta da organização do contexto
+func (r *TenantStandingOrderRepository) checkOrder(ctx context.Context, id string) error {
+	if requestctx.Tenant(ctx) == "" {
+		return nil
+	}
+	_, err := r.FindByID(ctx, id)
+	return err
+}
+
+// Create confere também o destino, que precisa estar na mesma organização
+func (r *TenantStandingOrderRepository) Create(ctx context.Context, order *domain.StandingOrder) error {
+	if err := r.checkAccount(ctx, order.AccountID); err != nil {
+		return err
+	}
+	if err := r.checkAccount(ctx, order.DestinationID); err != nil {
+		return err
+	}
+	return r.next.Create(ctx, order)
+}
+
+func (r *TenantStandingOrderRepository) FindByID(ctx context.Context, id string) (*domain.StandingOrder, error) {
+	order, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkFound(ctx, order.AccountID, domain.ErrStandingOrderNotFound); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *TenantStandingOrderRepository) List(ctx context.Context, accountID string, status domain.StandingOrderStatus, limit int) ([]*domain.StandingOrder, error) {
+	if err := r.checkList(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, accountID, status, limit)
+}
+
+// ListDue e Run servem à execução periódica, que roda sem organização no contexto
+func (r *TenantStandingOrderRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.StandingOrder, error) {
+	if err := jobOnly(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListDue(ctx, before, limit)
+}
+
+func (r *TenantStandingOrderRepository) Run(ctx context.Context, order *domain.StandingOrder, now time.Time) error {
+	if err := jobOnly(ctx); err != nil {
+		return err
+	}
+	return r.next.Run(ctx, order, now)
+}
+
+func (r *TenantStandingOrderRepository) Update(ctx context.Context, order *domain.StandingOrder, from domain.StandingOrderStatus) error {
+	if err := r.checkOrder(ctx, order.ID); err != nil {
+		return err
+	}
+	return r.next.Update(ctx, order, from)
+}
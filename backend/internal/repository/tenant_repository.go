@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// tenantColumns são as colunas lidas por scanTenant, na mesma ordem
+const tenantColumns = "id, name, admin_key_hash, display_name, logo_url, primary_color, support_email, chargeback_fee, spending_limit_daily, spending_limit_monthly, created_at, updated_at"
+
+// TenantRepository implementa a persistência das organizações
+type TenantRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewTenantRepository cria um novo repositório de organizações para o banco do dialeto informado
+func NewTenantRepository(db *sql.DB, dialect Dialect) *TenantRepository {
+	return &TenantRepository{db: db, dialect: dialect}
+}
+
+// tenantSettings são os valores nulos da organização: a tarifa e os tetos de gasto, NULL quando ela usa os padrão
+func tenantSettings(tenant *domain.Tenant) (chargebackFee, daily, monthly sql.NullFloat64) {
+	if tenant.ChargebackFee != nil {
+		chargebackFee = sql.NullFloat64{Float64: *tenant.ChargebackFee, Valid: true}
+	}
+	if tenant.SpendingLimits != nil {
+		daily = sql.NullFloat64{Float64: tenant.SpendingLimits.Daily, Valid: true}
+		monthly = sql.NullFloat64{Float64: tenant.SpendingLimits.Monthly, Valid: true}
+	}
+	return chargebackFee, daily, monthly
+}
+
+// Save grava a nova organização
+func (r *TenantRepository) Save(ctx context.Context, tenant *domain.Tenant) error {
+	chargebackFee, daily, monthly := tenantSettings(tenant)
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO tenants ("+tenantColumns+") VALUES "+valuesPlaceholders(1, 12)),
+		tenant.ID, tenant.Name, tenant.AdminKeyHash, tenant.Branding.DisplayName, tenant.Branding.LogoURL,
+		tenant.Branding.PrimaryColor, tenant.Branding.SupportEmail, chargebackFee, daily, monthly,
+		tenant.CreatedAt, tenant.UpdatedAt,
+	)
+	return err
+}
+
+// FindByID busca a organização pelo ID
+// Retorna ErrTenantNotFound se a organização não existir
+func (r *TenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	tenant, err := scanTenant(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+tenantColumns+" FROM tenants WHERE id = ?"),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+// FindByAdminKeyHash busca a organização pelo hash da chave dos administradores dela
+// Retorna ErrTenantNotFound se nenhuma organização tiver a chave
+func (r *TenantRepository) FindByAdminKeyHash(ctx context.Context, hash string) (*domain.Tenant, error) {
+	tenant, err := scanTenant(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+tenantColumns+" FROM tenants WHERE admin_key_hash = ?"),
+		hash,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+// List retorna as organizações ordenadas pelo nome
+func (r *TenantRepository) List(ctx context.Context) ([]*domain.Tenant, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+tenantColumns+" FROM tenants ORDER BY name, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []*domain.Tenant
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// Update grava o nome, a chave, a marca, a tarifa e os tetos de gasto da organização
+// Retorna ErrTenantNotFound se a organização não existir
+func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
+	chargebackFee, daily, monthly := tenantSettings(tenant)
+	result, err := r.db.ExecContext(ctx, r.dialect.rebind(`
+        UPDATE tenants
+        SET name = ?, admin_key_hash = ?, display_name = ?, logo_url = ?, primary_color = ?, support_email = ?,
+            chargeback_fee = ?, spending_limit_daily = ?, spending_limit_monthly = ?, updated_at = ?
+        WHERE id = ?
+    `),
+		tenant.Name, tenant.AdminKeyHash, tenant.Branding.DisplayName, tenant.Branding.LogoURL,
+		tenant.Branding.PrimaryColor, tenant.Branding.SupportEmail, chargebackFee, daily, monthly,
+		tenant.UpdatedAt, tenant.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTenantNotFound
+	}
+	return nil
+}
+
+// scanTenant lê uma organização na ordem de tenantColumns
+func scanTenant(row rowScanner) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	var chargebackFee, daily, monthly sql.NullFloat64
+	err := row.Scan(&tenant.ID, &tenant.Name, &tenant.AdminKeyHash, &tenant.Branding.DisplayName,
+		&tenant.Branding.LogoURL, &tenant.Branding.PrimaryColor, &tenant.Branding.SupportEmail, &chargebackFee,
+		&daily, &monthly, &tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if chargebackFee.Valid {
+		tenant.ChargebackFee = &chargebackFee.Float64
+	}
+	if daily.Valid || monthly.Valid {
+		tenant.SpendingLimits = &domain.SpendingLimits{Daily: daily.Float64, Monthly: monthly.Float64}
+	}
+	return &tenant, nil
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/memory"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+func TestTenantScopedRepositories(t *testing.T) {
+	store := memory.NewStore()
+	accounts := repository.NewTenantAccountRepository(memory.NewAccountRepository(store))
+	ledger := repository.NewTenantLedgerRepository(memory.NewLedgerRepository(store), accounts)
+	orders := repository.NewTenantStandingOrderRepository(memory.NewStandingOrderRepository(store), accounts)
+
+	ctx := context.Background()
+	own, other := domain.NewAccount("Loja", "loja@example.com"), domain.NewAccount("Outra", "outra@example.com")
+	own.TenantID, other.TenantID = "t1", "t2"
+	for _, account := range []*domain.Account{own, other} {
+		if err := accounts.Save(ctx, account); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	order, err := domain.NewStandingOrder(other.ID, own.ID, 80, 0, 7, "", time.Now())
+	if err != nil {
+		t.Fatalf("NewStandingOrder: %v", err)
+	}
+	if err := orders.Create(ctx, order); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tenant := requestctx.WithTenant(ctx, "t1")
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+		want error
+	}{
+		{"own ledger", func(ctx context.Context) error {
+			_, err := ledger.SumByType(ctx, own.ID)
+			return err
+		}, nil},
+		{"other ledger", func(ctx context.Context) error {
+			_, err := ledger.SumByType(ctx, other.ID)
+			return err
+		}, domain.ErrAccountNotFound},
+		{"posting to another tenant", func(ctx context.Context) error {
+			return ledger.Post(ctx, &domain.Posting{Balances: map[string]float64{own.ID: 1, other.ID: -1}})
+		}, domain.ErrAccountNotFound},
+		{"order of another tenant", func(ctx context.Context) error {
+			_, err := orders.FindByID(ctx, order.ID)
+			return err
+		}, domain.ErrStandingOrderNotFound},
+		{"cancel an order of another tenant", func(ctx context.Context) error {
+			return orders.Update(ctx, order, domain.StandingOrderActive)
+		}, domain.ErrStandingOrderNotFound},
+		{"orders of every account", func(ctx context.Context) error {
+			_, err := orders.List(ctx, "", "", 10)
+			return err
+		}, domain.ErrForbidden},
+		{"due orders", func(ctx context.Context) error {
+			_, err := orders.ListDue(ctx, time.Now(), 10)
+			return err
+		}, domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(tenant); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			// Sem organização no contexto, como nos jobs, nada é restrito
+			if err := tt.call(ctx); err != nil {
+				t.Errorf("error without a tenant = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/migrations"
+)
+
+// openTestDB abre o banco de TEST_DB_DSN, no driver de TEST_DB_DRIVER ("postgres", o padrão, ou "mysql"), com as
+// migrations aplicadas e, no PostgreSQL, a partição de invoices do mês atual
+// Sem TEST_DB_DSN o teste é pulado; use um banco descartável, pois os dados gravados não são apagados
+func openTestDB(tb testing.TB) (*sql.DB, Dialect) {
+	tb.Helper()
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_DB_DSN not set")
+	}
+	driver := os.Getenv("TEST_DB_DRIVER")
+	if driver == "" {
+		driver = "postgres"
+	}
+	dialect, err := DialectFor(driver)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cfg := database.PoolConfig{DSN: dsn}
+	migrate(tb, ctx, driver, cfg)
+
+	db, pool, err := database.Open(ctx, driver, cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		db.Close()
+		if pool != nil {
+			pool.Close()
+		}
+	})
+	if dialect == Postgres {
+		if err := database.EnsureInvoicePartitions(ctx, db, 0); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return db, dialect
+}
+
+// migrate aplica as migrations numa conexão própria, já que o Migrator fecha o *sql.DB que recebe
+func migrate(tb testing.TB, ctx context.Context, driver string, cfg database.PoolConfig) {
+	tb.Helper()
+	db, pool, err := database.Open(ctx, driver, cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if pool != nil {
+		defer pool.Close()
+	}
+
+	files, err := migrations.FS(driver)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	migrator, err := database.NewMigrator(db, driver, files)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer migrator.Close()
+	if _, err := migrator.Up(ctx, 0); err != nil {
+		tb.Fatal(err)
+	}
+}
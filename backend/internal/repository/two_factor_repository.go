@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+const twoFactorColumns = "actor, secret, backup_codes, last_step, confirmed_at, created_at, updated_at"
+
+// TwoFactorRepository implementa a persistência do segundo fator das operações sensíveis
+// O segredo TOTP é gravado cifrado; os códigos de recuperação, como hashes separados por vírgulas
+type TwoFactorRepository struct {
+	db        *sql.DB
+	dialect   Dialect
+	encryptor *pii.Encryptor
+}
+
+// NewTwoFactorRepository cria um novo repositório de segundo fator para o banco do dialeto informado
+// Com encryptor nil os segredos são gravados em texto puro
+func NewTwoFactorRepository(db *sql.DB, dialect Dialect, encryptor *pii.Encryptor) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db, dialect: dialect, encryptor: encryptor}
+}
+
+// Save persiste o cadastro de um segundo fator
+func (r *TwoFactorRepository) Save(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	secret, err := r.encryptor.Encrypt(ctx, twoFactor.Secret)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO two_factor ("+twoFactorColumns+") VALUES "+valuesPlaceholders(1, 7)),
+		twoFactor.Actor, secret, strings.Join(twoFactor.BackupCodes, ","), twoFactor.LastStep, twoFactor.ConfirmedAt, twoFactor.CreatedAt, twoFactor.UpdatedAt,
+	)
+	return err
+}
+
+// FindByActor busca o segundo fator do autor decifrando o segredo
+func (r *TwoFactorRepository) FindByActor(ctx context.Context, actor string) (*domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	var backupCodes string
+	var confirmedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+twoFactorColumns+" FROM two_factor WHERE actor = ?"),
+		actor,
+	).Scan(
+		&twoFactor.Actor,
+		&twoFactor.Secret,
+		&backupCodes,
+		&twoFactor.LastStep,
+		&confirmedAt,
+		&twoFactor.CreatedAt,
+		&twoFactor.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if backupCodes != "" {
+		twoFactor.BackupCodes = strings.Split(backupCodes, ",")
+	}
+	if confirmedAt.Valid {
+		twoFactor.ConfirmedAt = &confirmedAt.Time
+	}
+	if twoFactor.Secret, err = r.encryptor.Decrypt(ctx, twoFactor.Secret); err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// Update grava os códigos de recuperação, o último período aceito e a confirmação
+// Retorna ErrTwoFactorNotEnrolled se o cadastro não existir mais
+func (r *TwoFactorRepository) Update(ctx context.Context, twoFactor *domain.TwoFactor) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE two_factor SET backup_codes = ?, last_step = ?, confirmed_at = ?, updated_at = ? WHERE actor = ?"),
+		strings.Join(twoFactor.BackupCodes, ","), twoFactor.LastStep, twoFactor.ConfirmedAt, twoFactor.UpdatedAt, twoFactor.Actor,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+	return nil
+}
+
+// Delete remove o segundo fator do autor
+func (r *TwoFactorRepository) Delete(ctx context.Context, actor string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.rebind("DELETE FROM two_factor WHERE actor = ?"), actor)
+	return err
+}
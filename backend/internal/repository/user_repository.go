@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/pii"
+)
+
+const userEntity = "user"
+
+const userColumns = "id, account_id, email, role, password_hash, created_at, updated_at"
+
+const refreshTokenColumns = "id, user_id, session_id, token_hash, ip, user_agent, expires_at, created_at, revoked_at"
+
+// UserRepository implementa operações de persistência para os usuários do dashboard e seus refresh tokens
+// O e-mail é gravado cifrado e buscado pelo índice cego email_hash, como nas contas
+type UserRepository struct {
+	db        *sql.DB
+	dialect   Dialect
+	encryptor *pii.Encryptor
+}
+
+// NewUserRepository cria um novo repositório de usuários para o banco do dialeto informado
+// Com encryptor nil os e-mails são gravados em texto puro
+func NewUserRepository(db *sql.DB, dialect Dialect, encryptor *pii.Encryptor) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect, encryptor: encryptor}
+}
+
+// scanUser lê um usuário na ordem de userColumns decifrando o e-mail
+func (r *UserRepository) scanUser(ctx context.Context, row rowScanner) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(&user.ID, &user.AccountID, &user.Email, &user.Role, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Email, err = r.encryptor.Decrypt(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Save persiste um novo usuário registrando a inserção na auditoria
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	email, err := r.encryptor.Encrypt(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := writeAudit(ctx, tx, r.dialect, userEntity, user.ID, domain.AuditActionInsert, nil, NewUserSnapshot(user)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.rebind(`
+		INSERT INTO users (id, account_id, email, email_hash, role, password_hash, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`), user.ID, user.AccountID, email, r.encryptor.BlindIndex(user.Email), user.Role, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindByEmail busca um usuário pelo e-mail através do índice cego
+// Retorna ErrUserNotFound se não encontrado
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.scanUser(ctx, r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+userColumns+" FROM users WHERE email_hash = ?"),
+		r.encryptor.BlindIndex(email),
+	))
+}
+
+// FindByID busca um usuário pelo ID
+// Retorna ErrUserNotFound se não encontrado
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.scanUser(ctx, r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+userColumns+" FROM users WHERE id = ?"),
+		id,
+	))
+}
+
+// UpdateRole altera o papel do usuário registrando o estado anterior na auditoria
+// Retorna ErrUserNotFound se o usuário não existir
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.scanUser(ctx, tx.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+userColumns+" FROM users WHERE id = ? FOR UPDATE"),
+		id,
+	))
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	updated := NewUserSnapshot(current)
+	updated.Role = role
+	updated.UpdatedAt = updatedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, userEntity, id, domain.AuditActionUpdate, NewUserSnapshot(current), updated); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE users SET role = ?, updated_at = ? WHERE id = ?"),
+		role, updatedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdatePassword troca o hash da senha registrando a alteração na auditoria, sem o hash
+// Retorna ErrUserNotFound se o usuário não existir
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.scanUser(ctx, tx.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+userColumns+" FROM users WHERE id = ? FOR UPDATE"),
+		id,
+	))
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	updated := NewUserSnapshot(current)
+	updated.UpdatedAt = updatedAt
+
+	if err := writeAudit(ctx, tx, r.dialect, userEntity, id, domain.AuditActionUpdate, NewUserSnapshot(current), updated); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		r.dialect.rebind("UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?"),
+		passwordHash, updatedAt, id,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveRefreshToken persiste um novo refresh token; apenas o hash do token é gravado
+func (r *UserRepository) SaveRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("INSERT INTO refresh_tokens ("+refreshTokenColumns+") VALUES "+valuesPlaceholders(1, 9)),
+		token.ID, token.UserID, token.SessionID, token.TokenHash, token.IP, token.UserAgent, token.ExpiresAt, token.CreatedAt, token.RevokedAt,
+	)
+	return err
+}
+
+// scanRefreshToken lê um refresh token na ordem de refreshTokenColumns
+func scanRefreshToken(row rowScanner) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	var revokedAt sql.NullTime
+
+	err := row.Scan(&token.ID, &token.UserID, &token.SessionID, &token.TokenHash, &token.IP, &token.UserAgent, &token.ExpiresAt, &token.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
+
+// FindRefreshToken busca um refresh token pelo hash
+// Retorna ErrInvalidToken se não encontrado
+func (r *UserRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	token, err := scanRefreshToken(r.db.QueryRowContext(ctx,
+		r.dialect.rebind("SELECT "+refreshTokenColumns+" FROM refresh_tokens WHERE token_hash = ?"),
+		tokenHash,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvalidToken
+	}
+	return token, err
+}
+
+// FindActiveRefreshTokens busca as sessões ativas do usuário, das renovadas mais recentemente para as mais antigas
+func (r *UserRepository) FindActiveRefreshTokens(ctx context.Context, userID string) ([]*domain.RefreshToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		r.dialect.rebind("SELECT "+refreshTokenColumns+" FROM refresh_tokens WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY created_at DESC"),
+		userID, time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*domain.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeRefreshToken revoga o token apenas se ainda estiver ativo, para que dois usos concorrentes
+// do mesmo token não emitam dois pares novos
+func (r *UserRepository) RevokeRefreshToken(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"),
+		time.Now(), id,
+	)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+// RevokeSession revoga o token ativo da sessão do usuário
+// Retorna ErrSessionNotFound se não houver token ativo da sessão
+func (r *UserRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND session_id = ? AND revoked_at IS NULL AND expires_at > ?"),
+		now, userID, sessionID, now,
+	)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revoga todos os tokens ainda ativos do usuário
+func (r *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		r.dialect.rebind("UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL"),
+		time.Now(), userID,
+	)
+	return err
+}
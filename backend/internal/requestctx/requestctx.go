@@ -0,0 +1,111 @@
+package requestctx
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	actorKey
+	apiKeyKey
+	roleKey
+	scopesKey
+	clientKey
+	tenantKey
+)
+
+// SystemActor identifica mutações disparadas pelo próprio gateway (consumidores, jobs)
+const SystemActor = "system"
+
+// WithRequestID retorna um contexto carregando o ID da requisição
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID retorna o ID da requisição ou vazio se não houver
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithActor retorna um contexto carregando quem está executando a operação (ex: account:<id>)
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor retorna o autor da operação ou SystemActor se não houver
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// WithAPIKey retorna um contexto carregando o API Key da conta autenticada
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyKey, apiKey)
+}
+
+// APIKey retorna o API Key da conta autenticada ou vazio se não houver
+// Em requisições assinadas o API Key não trafega no header, então os handlers o leem daqui
+func APIKey(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyKey).(string)
+	return apiKey
+}
+
+// WithRole retorna um contexto carregando o papel do API Key ou do usuário autenticado
+func WithRole(ctx context.Context, role domain.Role) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// Role retorna o papel autenticado ou vazio, que não concede nenhuma permissão
+func Role(ctx context.Context) domain.Role {
+	role, _ := ctx.Value(roleKey).(domain.Role)
+	return role
+}
+
+// WithScopes retorna um contexto carregando os escopos do API Key autenticado
+func WithScopes(ctx context.Context, scopes []domain.Permission) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// Scopes retorna os escopos do API Key autenticado ou nil quando a autenticação não foi por API Key
+func Scopes(ctx context.Context) []domain.Permission {
+	scopes, _ := ctx.Value(scopesKey).([]domain.Permission)
+	return scopes
+}
+
+// Client descreve a origem da requisição, registrada nos eventos de autenticação
+type Client struct {
+	IP        string
+	UserAgent string
+	// Route é o método e o caminho chamados
+	Route string
+}
+
+// WithClient retorna um contexto carregando a origem da requisição
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey, client)
+}
+
+// ClientInfo retorna a origem da requisição ou o valor zero fora de requisições HTTP
+func ClientInfo(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey).(Client)
+	return client
+}
+
+// WithTenant retorna um contexto restrito às contas da organização, como nas requisições dos administradores dela
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// Tenant retorna a organização a que a operação está restrita ou vazio, sem restrição, para os administradores
+// globais, as próprias contas e os jobs
+func Tenant(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey).(string)
+	return tenantID
+}
@@ -0,0 +1,45 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSProvider lê os segredos de um secret do AWS Secrets Manager cujo valor é um objeto JSON de textos
+type AWSProvider struct {
+	client   *secretsmanager.Client
+	secretID string
+}
+
+// NewAWSProvider cria o provedor com a cadeia padrão de credenciais e região da AWS (variáveis AWS_*, perfil ou IAM role)
+func NewAWSProvider(ctx context.Context, secretID string) (*AWSProvider, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSProvider{client: secretsmanager.NewFromConfig(cfg), secretID: secretID}, nil
+}
+
+// Fetch lê a versão corrente (AWSCURRENT) do secret
+func (p *AWSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	output, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(p.secretID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output.SecretString == nil {
+		return nil, fmt.Errorf("aws secret %s has no string value", p.secretID)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(*output.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("aws secret %s must be a JSON object of strings: %w", p.secretID, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Provider busca os segredos da aplicação em um cofre externo, como pares nome/valor
+// Os nomes são os mesmos das variáveis de ambiente que eles substituem (ex: DB_PASSWORD)
+type Provider interface {
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// Store mantém a última versão dos segredos buscados no provedor
+// Leituras nunca vão ao cofre; Watch as atualiza periodicamente para acompanhar rotações
+type Store struct {
+	provider Provider
+	mu       sync.RWMutex
+	values   map[string]string
+}
+
+// NewStore cria um armazenamento vazio para o provedor informado; use Load antes da primeira leitura
+func NewStore(provider Provider) *Store {
+	return &Store{provider: provider, values: map[string]string{}}
+}
+
+// Load busca todos os segredos no provedor e substitui os valores atuais
+func (s *Store) Load(ctx context.Context) error {
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+	return nil
+}
+
+// Lookup retorna o valor do segredo e se ele existe no provedor
+func (s *Store) Lookup(name string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// Watch recarrega os segredos a cada intervalo até o contexto ser cancelado
+// Falhas são registradas e mantêm os valores anteriores, para que uma indisponibilidade do cofre não derrube a aplicação
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil {
+				slog.ErrorContext(ctx, "Error refreshing secrets", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// VaultProvider lê os segredos de um caminho do engine KV v2 do HashiCorp Vault
+type VaultProvider struct {
+	client *vault.Client
+	mount  string
+	path   string
+}
+
+// NewVaultProvider cria o provedor a partir de VAULT_ADDR, VAULT_TOKEN e das demais variáveis VAULT_* do cliente oficial
+// mount é o engine KV v2 (ex: secret) e path o segredo com os valores da aplicação (ex: gateway)
+func NewVaultProvider(mount, path string) (*VaultProvider, error) {
+	client, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &VaultProvider{client: client, mount: mount, path: path}, nil
+}
+
+// Fetch lê a versão mais recente do segredo; todos os campos precisam ser texto
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	secret, err := p.client.KVv2(p.mount).Get(ctx, p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(secret.Data))
+	for name, value := range secret.Data {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("vault secret %s/%s: field %q is not a string", p.mount, p.path, name)
+		}
+		values[name] = text
+	}
+	return values, nil
+}
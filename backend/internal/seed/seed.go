@@ -0,0 +1,232 @@
+// Package seed gera contas, faturas e lançamentos fictícios com a cara de dados reais, para demonstrações, testes de
+// carga e o desenvolvimento do frontend
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// Config define o tamanho da massa gerada
+// As faturas ficam espalhadas pelos últimos Days dias; TestMode cria as contas em modo de teste
+type Config struct {
+	Accounts int
+	Invoices int
+	Days     int
+	TestMode bool
+}
+
+// AccountData é uma conta gerada com as faturas, os cartões de cada fatura, na mesma ordem, e os lançamentos
+// do razão
+// Balance da conta é a soma dos créditos, então o saldo bate com o razão na conciliação
+type AccountData struct {
+	Account  *domain.Account
+	Invoices []*domain.Invoice
+	Cards    []*carddata.Card
+	Ledger   []*domain.LedgerEntry
+}
+
+// highValueShare é a fração das faturas acima do valor que vai para o antifraude
+const highValueShare = 0.03
+
+// antifraudDelay é a idade a partir da qual as faturas de alto valor já foram decididas pelo antifraude
+const antifraudDelay = 24 * time.Hour
+
+// Generate gera a massa de dados com os números de random, até now
+// As faturas se concentram em poucas contas, como nos gateways reais; até 10000 são aprovadas em 70% das vezes,
+// como no processamento da criação, e as de alto valor ficam pendentes no último dia e depois decididas pelo antifraude
+func Generate(cfg Config, random *rand.Rand, now time.Time) []*AccountData {
+	window := time.Duration(cfg.Days) * 24 * time.Hour
+	run := strconv.FormatInt(random.Int63n(1<<32), 36)
+
+	data := make([]*AccountData, cfg.Accounts)
+	for i := range data {
+		data[i] = &AccountData{Account: newAccount(cfg, random, run, i, now.Add(-window-randomDuration(random, 30*24*time.Hour)))}
+	}
+
+	// A distribuição de Zipf dá a poucas contas a maior parte das faturas
+	owners := rand.NewZipf(random, 1.2, 4, uint64(cfg.Accounts-1))
+	for range cfg.Invoices {
+		owner := data[owners.Uint64()]
+		createdAt := now.Add(-randomDuration(random, window))
+		invoice, card := newInvoice(random, owner.Account.ID, createdAt)
+		decide(random, invoice, createdAt, now)
+
+		owner.Invoices = append(owner.Invoices, invoice)
+		owner.Cards = append(owner.Cards, card)
+		if invoice.Status == domain.StatusApproved {
+			entry := domain.NewLedgerEntry(owner.Account.ID, "", invoice.ID, domain.LedgerCredit, invoice.Amount)
+			entry.CreatedAt = invoice.UpdatedAt
+			owner.Ledger = append(owner.Ledger, entry)
+			owner.Account.Balance += entry.Amount
+			// Sem custódia nem prazo de repasse, o crédito já liquida a fatura, como no gateway
+			invoice.TransitionTo(domain.StatusSettled)
+			invoice.UpdatedAt = entry.CreatedAt
+		}
+	}
+	for _, account := range data {
+		account.Account.Balance = math.Round(account.Account.Balance*100) / 100
+	}
+	return data
+}
+
+var (
+	businessKinds = []string{"Padaria", "Livraria", "Farmácia", "Pet Shop", "Academia", "Café", "Floricultura", "Ótica", "Papelaria", "Estúdio", "Mercado", "Oficina"}
+	businessNames = []string{"Aurora", "Central", "do Bairro", "Bom Preço", "Estrela", "Paulista", "Horizonte", "Vila Nova", "Ipê", "Atlântico", "Girassol", "Mantiqueira"}
+	firstNames    = []string{"Ana", "Bruno", "Carla", "Diego", "Eduarda", "Felipe", "Gabriela", "Henrique", "Isabela", "João", "Larissa", "Marcos", "Natália", "Otávio", "Paula", "Rafael", "Sofia", "Thiago", "Vitória", "William"}
+	lastNames     = []string{"Silva", "Santos", "Oliveira", "Souza", "Lima", "Pereira", "Costa", "Rodrigues", "Almeida", "Nascimento", "Carvalho", "Ribeiro", "Martins", "Rocha", "Gomes"}
+	products      = []string{"Pedido", "Assinatura mensal", "Compra no balcão", "Pacote de serviços", "Reserva", "Mensalidade", "Encomenda", "Kit presente"}
+)
+
+// slug troca acentos e espaços para usar o nome da conta no e-mail
+var slug = strings.NewReplacer(" ", "-", "á", "a", "ã", "a", "â", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "ô", "o", "ç", "c", "Ó", "o")
+
+// newAccount gera a conta index; run deixa os e-mails únicos entre execuções
+func newAccount(cfg Config, random *rand.Rand, run string, index int, createdAt time.Time) *domain.Account {
+	name := pick(random, businessKinds) + " " + pick(random, businessNames)
+	email := fmt.Sprintf("financeiro+%s-%d@%s.example.com", run, index+1, strings.ToLower(slug.Replace(name)))
+
+	account := domain.NewAccount(name, email)
+	if cfg.TestMode {
+		account.APIKey = domain.TestAPIKeyPrefix + account.APIKey
+	}
+	account.CreatedAt, account.UpdatedAt = createdAt, createdAt
+	return account
+}
+
+// newInvoice gera uma fatura de cartão de crédito pendente, com o cartão do pagador
+func newInvoice(random *rand.Rand, accountID string, createdAt time.Time) (*domain.Invoice, *carddata.Card) {
+	number := CardNumber(random)
+	// Os dados gerados são sempre válidos
+	card, _ := carddata.NewCard(number, CVV(random, number), 1+random.Intn(12), createdAt.Year()+1+random.Intn(5), PayerName(random))
+	invoice, _ := domain.NewInvoice(accountID, Amount(random), Description(random), "credit_card", card.PaymentCard())
+	invoice.CreatedAt, invoice.UpdatedAt = createdAt, createdAt
+	return invoice, card
+}
+
+// Amount sorteia o valor de uma fatura, numa log-normal em torno de R$ 90, com uma cauda de faturas de alto valor
+func Amount(random *rand.Rand) float64 {
+	amount := math.Min(math.Exp(random.NormFloat64()+4.5), 9500)
+	if random.Float64() < highValueShare {
+		amount = 10000 + random.Float64()*40000
+	}
+	return math.Max(5, math.Round(amount*100)/100)
+}
+
+// Description sorteia a descrição de uma fatura
+func Description(random *rand.Rand) string {
+	description := pick(random, products)
+	if description == "Pedido" || description == "Encomenda" {
+		description += fmt.Sprintf(" #%d", 1000+random.Intn(90000))
+	}
+	return description
+}
+
+// PayerName sorteia o nome do pagador impresso no cartão
+func PayerName(random *rand.Rand) string {
+	return pick(random, firstNames) + " " + pick(random, lastNames)
+}
+
+// decide leva a fatura ao status que ela teria hoje
+func decide(random *rand.Rand, invoice *domain.Invoice, createdAt, now time.Time) {
+	approval := 0.7
+	decidedAt := createdAt.Add(randomDuration(random, 3*time.Second))
+	if invoice.Amount > 10000 {
+		if now.Sub(createdAt) < antifraudDelay {
+			return
+		}
+		approval = 0.6
+		decidedAt = createdAt.Add(randomDuration(random, antifraudDelay))
+	}
+
+	status := domain.StatusRejected
+	if random.Float64() < approval {
+		status = domain.StatusApproved
+	}
+	invoice.TransitionTo(status)
+	invoice.UpdatedAt = decidedAt
+}
+
+// Prefixos (BIN) e tamanhos dos números gerados, com a participação aproximada de cada bandeira no Brasil
+var cardPrefixes = []struct {
+	prefix string
+	length int
+	weight float64
+}{
+	{"4", 16, 0.45},
+	{"5", 16, 0.40},
+	{"37", 15, 0.05},
+	{"606282", 16, 0.05},
+	{"6011", 16, 0.05},
+}
+
+// CardNumber sorteia um número de cartão que passa pelo Luhn, nunca um dos carddata.TestCards
+func CardNumber(random *rand.Rand) string {
+	for {
+		roll := random.Float64()
+		choice := cardPrefixes[len(cardPrefixes)-1]
+		for _, candidate := range cardPrefixes {
+			if roll < candidate.weight {
+				choice = candidate
+				break
+			}
+			roll -= candidate.weight
+		}
+		// Mastercard usa de 51 a 55
+		prefix := choice.prefix
+		if prefix == "5" {
+			prefix += strconv.Itoa(1 + random.Intn(5))
+		}
+
+		digits := []byte(prefix)
+		for len(digits) < choice.length-1 {
+			digits = append(digits, byte('0'+random.Intn(10)))
+		}
+		number := string(digits) + strconv.Itoa(luhnCheckDigit(string(digits)))
+		if _, ok := carddata.TestCards[number]; !ok {
+			return number
+		}
+	}
+}
+
+// CVV sorteia o código de segurança do cartão, com quatro dígitos nos números de 15 dígitos (Amex)
+func CVV(random *rand.Rand, number string) string {
+	if len(number) == 15 {
+		return fmt.Sprintf("%04d", random.Intn(10000))
+	}
+	return fmt.Sprintf("%03d", random.Intn(1000))
+}
+
+// luhnCheckDigit calcula o dígito verificador que completa o número
+func luhnCheckDigit(partial string) int {
+	sum := 0
+	double := true
+	for i := len(partial) - 1; i >= 0; i-- {
+		d := int(partial[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+func pick(random *rand.Rand, values []string) string {
+	return values[random.Intn(len(values))]
+}
+
+// randomDuration sorteia uma duração em [0, limit)
+func randomDuration(random *rand.Rand, limit time.Duration) time.Duration {
+	return time.Duration(random.Int63n(int64(limit)))
+}
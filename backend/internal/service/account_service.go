@@ -1,27 +1,81 @@
 package service
 
 import (
+	"context"
+	"crypto/subtle"
+	"time"
+
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
 )
 
 // AccountService implementa a lógica de negócios para operações com Account
 type AccountService struct {
 	repository domain.AccountRepository
+	missing    *missingKeyCache
 }
 
 // NewAccountService cria um novo serviço de contas
-func NewAccountService(repository domain.AccountRepository) *AccountService {
-	return &AccountService{repository: repository}
+// API Keys não encontrados são lembrados por missingKeyTTL, poupando o banco de tentativas de enumeração;
+// com missingKeyTTL zero toda busca vai ao banco
+func NewAccountService(repository domain.AccountRepository, missingKeyTTL time.Duration) *AccountService {
+	return &AccountService{repository: repository, missing: newMissingKeyCache(missingKeyTTL)}
+}
+
+// findByAPIKey busca a conta do API Key consultando antes o cache de chaves inexistentes
+// A chave encontrada é conferida em tempo constante, o que também descarta correspondências
+// aproximadas de collations que ignoram maiúsculas, como as padrão do MySQL
+func (s *AccountService) findByAPIKey(ctx context.Context, apiKey string) (*domain.Account, error) {
+	if s.missing.contains(apiKey) {
+		metrics.APIKeyMissCacheHitsTotal.Inc()
+		return nil, domain.ErrAccountNotFound
+	}
+
+	account, err := s.repository.FindByAPIKey(ctx, apiKey)
+	if err == domain.ErrAccountNotFound {
+		s.missing.add(apiKey)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(account.APIKey), []byte(apiKey)) != 1 {
+		s.missing.add(apiKey)
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// warmUpAPIKey é o API Key procurado no aquecimento; não precisa existir
+const warmUpAPIKey = "warm-up"
+
+// WarmUp faz uma busca por API Key antes do primeiro cliente, abrindo as conexões e preparando a consulta que
+// autentica as requisições
+// O cache de chaves inexistentes começa vazio de propósito e não guarda a chave usada aqui
+func (s *AccountService) WarmUp(ctx context.Context) error {
+	_, err := s.repository.FindByAPIKey(ctx, warmUpAPIKey)
+	if err != nil && err != domain.ErrAccountNotFound {
+		return err
+	}
+	return nil
 }
 
 // CreateAccount cria uma nova conta e valida duplicidade de API Key
-// Retorna ErrDuplicatedAPIKey se a chave já existir
-func (s *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto.AccountOutput, error) {
+// Retorna ErrInvalidScope se algum escopo não existir e ErrDuplicatedAPIKey se a chave já existir
+func (s *AccountService) CreateAccount(ctx context.Context, input dto.CreateAccountInput) (*dto.AccountOutput, error) {
 	account := dto.ToAccount(input)
+	if len(input.Scopes) > 0 {
+		scopes, err := domain.ParseScopes(input.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		account.Scopes = scopes
+	}
 
 	// Verifica duplicidade de API Key antes da criação
-	existingAccount, err := s.repository.FindByAPIKey(account.APIKey)
+	existingAccount, err := s.repository.FindByAPIKey(ctx, account.APIKey)
 	if err != nil && err != domain.ErrAccountNotFound {
 		return nil, err
 	}
@@ -29,10 +83,11 @@ func (s *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto.Accou
 		return nil, domain.ErrDuplicatedAPIKey
 	}
 
-	err = s.repository.Save(account)
+	err = s.repository.Save(ctx, account)
 	if err != nil {
 		return nil, err
 	}
+	s.missing.remove(account.APIKey)
 
 	output := dto.FromAccount(account)
 	return &output, nil
@@ -40,14 +95,14 @@ func (s *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto.Accou
 
 // UpdateBalance atualiza o saldo de uma conta de forma thread-safe
 // O amount pode ser positivo (crédito)
-func (s *AccountService) UpdateBalance(apiKey string, amount float64) (*dto.AccountOutput, error) {
-	account, err := s.repository.FindByAPIKey(apiKey)
+func (s *AccountService) UpdateBalance(ctx context.Context, apiKey string, amount float64) (*dto.AccountOutput, error) {
+	account, err := s.findByAPIKey(ctx, apiKey)
 	if err != nil {
 		return nil, err
 	}
 
 	account.AddBalance(amount)
-	err = s.repository.UpdateBalance(account)
+	err = s.repository.UpdateBalance(ctx, account)
 	if err != nil {
 		return nil, err
 	}
@@ -56,8 +111,8 @@ func (s *AccountService) UpdateBalance(apiKey string, amount float64) (*dto.Acco
 }
 
 // FindByAPIKey busca uma conta pelo API Key
-func (s *AccountService) FindByAPIKey(apiKey string) (*dto.AccountOutput, error) {
-	account, err := s.repository.FindByAPIKey(apiKey)
+func (s *AccountService) FindByAPIKey(ctx context.Context, apiKey string) (*dto.AccountOutput, error) {
+	account, err := s.findByAPIKey(ctx, apiKey)
 	if err != nil {
 		return nil, err
 	}
@@ -66,11 +121,40 @@ func (s *AccountService) FindByAPIKey(apiKey string) (*dto.AccountOutput, error)
 }
 
 // FindByID busca uma conta pelo ID
-func (s *AccountService) FindByID(id string) (*dto.AccountOutput, error) {
-	account, err := s.repository.FindByID(id)
+// Use domain.IncludeDeleted() para consultas administrativas de contas excluídas
+func (s *AccountService) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByID(ctx, id, opts...)
 	if err != nil {
 		return nil, err
 	}
 	output := dto.FromAccount(account)
 	return &output, nil
 }
+
+// UpdateRole altera o papel do API Key da conta
+// Retorna ErrInvalidRole se o papel não existir
+func (s *AccountService) UpdateRole(ctx context.Context, id string, input dto.UpdateRoleInput) (*dto.AccountOutput, error) {
+	role, err := domain.ParseRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+	return s.FindByID(ctx, id)
+}
+
+// UpdateTenant move a conta para a organização informada; tenantID vazio tira a conta da organização
+func (s *AccountService) UpdateTenant(ctx context.Context, id, tenantID string) (*dto.AccountOutput, error) {
+	if err := s.repository.UpdateTenant(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+	return s.FindByID(ctx, id)
+}
+
+// Delete exclui logicamente uma conta
+// Retorna ErrAccountNotFound se a conta não existir ou já estiver excluída
+func (s *AccountService) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+)
+
+// defaultAlertWindow é a janela das regras cadastradas sem janela
+const defaultAlertWindow = 5 * time.Minute
+
+// alertMinDecisions é o mínimo de faturas aprovadas ou recusadas na janela para avaliar a taxa de recusas,
+// para que poucas recusas logo após a subida não disparem o alerta
+const alertMinDecisions = 20
+
+// Tipos dos eventos enviados pelos canais de notificação
+const (
+	alertFiringEvent   = "alert.firing"
+	alertResolvedEvent = "alert.resolved"
+)
+
+// AlertConfig define a avaliação das regras de alerta
+type AlertConfig struct {
+	// Interval é o intervalo entre as avaliações e entre as leituras das métricas
+	Interval time.Duration
+	// Emails recebe os alertas por e-mail, quando há servidor SMTP configurado
+	Emails []string
+}
+
+// alertSample é uma leitura dos contadores usados pelas regras
+type alertSample struct {
+	at     time.Time
+	totals metrics.AlertTotals
+}
+
+// alertState é o resultado da última avaliação de uma regra nesta instância
+// name e metric são os rótulos com que as métricas da regra foram publicadas
+type alertState struct {
+	name        string
+	metric      domain.AlertMetric
+	firing      bool
+	value       float64
+	evaluatedAt time.Time
+	since       time.Time
+}
+
+// alertTransition é uma regra que disparou ou voltou ao limite na avaliação
+type alertTransition struct {
+	rule   *domain.AlertRule
+	firing bool
+	value  float64
+}
+
+// AlertService avalia periodicamente as regras de alerta cadastradas no banco sobre as métricas do gateway
+// e envia um alerta quando uma regra dispara e outro quando ela volta ao limite
+// As medidas são as desta instância: com várias réplicas cada uma avalia e alerta sobre o próprio tráfego
+type AlertService struct {
+	rules   domain.AlertRuleRepository
+	webhook *notify.Webhook
+	mailer  *notify.Mailer
+	// lag lê as mensagens pendentes do consumidor do Kafka; nil desativa as regras de consumer_lag
+	lag      func() int64
+	config   AlertConfig
+	instance string
+
+	mu      sync.Mutex
+	samples []alertSample
+	states  map[string]*alertState
+}
+
+// NewAlertService cria o avaliador de alertas; webhook e mailer são opcionais
+// Sem nenhum dos dois os alertas vão apenas para o log e para /metrics
+func NewAlertService(rules domain.AlertRuleRepository, webhook *notify.Webhook, mailer *notify.Mailer, lag func() int64, config AlertConfig) *AlertService {
+	instance, _ := os.Hostname()
+	return &AlertService{
+		rules:    rules,
+		webhook:  webhook,
+		mailer:   mailer,
+		lag:      lag,
+		config:   config,
+		instance: instance,
+		states:   make(map[string]*alertState),
+	}
+}
+
+// alertRuleDefinition lê a janela e o estado da regra enviados pelo administrador
+func alertRuleDefinition(input dto.AlertRuleInput) (time.Duration, bool, error) {
+	window := defaultAlertWindow
+	if input.Window != "" {
+		parsed, err := time.ParseDuration(input.Window)
+		if err != nil {
+			return 0, false, domain.ErrInvalidAlertRule
+		}
+		window = parsed
+	}
+	enabled := input.Enabled == nil || *input.Enabled
+	return window, enabled, nil
+}
+
+// Create cadastra uma regra, avaliada a partir da próxima execução
+// Retorna ErrInvalidAlertRule se algum valor for inválido
+func (s *AlertService) Create(ctx context.Context, input dto.AlertRuleInput) (*dto.AlertRuleOutput, error) {
+	window, enabled, err := alertRuleDefinition(input)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := domain.NewAlertRule(input.Name, domain.AlertMetric(input.Metric), input.Threshold, window, enabled)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rules.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return s.output(rule), nil
+}
+
+// Update substitui a definição da regra; o estado da avaliação é mantido
+// Retorna ErrAlertRuleNotFound se a regra não existir e ErrInvalidAlertRule se algum valor for inválido
+func (s *AlertService) Update(ctx context.Context, id string, input dto.AlertRuleInput) (*dto.AlertRuleOutput, error) {
+	window, enabled, err := alertRuleDefinition(input)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.rules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := rule.Change(input.Name, domain.AlertMetric(input.Metric), input.Threshold, window, enabled); err != nil {
+		return nil, err
+	}
+	if err := s.rules.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return s.output(rule), nil
+}
+
+// Delete remove a regra; um alerta disparado por ela não recebe o aviso de resolvido
+// Retorna ErrAlertRuleNotFound se a regra não existir
+func (s *AlertService) Delete(ctx context.Context, id string) error {
+	if err := s.rules.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forget(id)
+	return nil
+}
+
+// List retorna as regras com o resultado da última avaliação nesta instância
+func (s *AlertService) List(ctx context.Context) ([]*dto.AlertRuleOutput, error) {
+	rules, err := s.rules.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.AlertRuleOutput, len(rules))
+	for i, rule := range rules {
+		output[i] = s.output(rule)
+	}
+	return output, nil
+}
+
+// output converte a regra incluindo o estado da última avaliação, quando houver
+func (s *AlertService) output(rule *domain.AlertRule) *dto.AlertRuleOutput {
+	output := dto.FromAlertRule(rule)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.states[rule.ID]; ok {
+		output.State = &dto.AlertStateOutput{Firing: state.firing, Value: state.value, EvaluatedAt: state.evaluatedAt}
+		if state.firing {
+			since := state.since
+			output.State.FiringSince = &since
+		}
+	}
+	return output
+}
+
+// Run avalia as regras a cada Interval, a primeira vez imediatamente; bloqueia até o contexto ser cancelado
+func (s *AlertService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.Evaluate(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Evaluate(ctx, now)
+		}
+	}
+}
+
+// Evaluate lê as métricas, relê as regras do banco e envia os alertas das regras que mudaram de estado
+// Regras sem dados suficientes na janela mantêm o estado anterior
+func (s *AlertService) Evaluate(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	s.record(now)
+	s.mu.Unlock()
+
+	rules, err := s.rules.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao carregar as regras de alerta", "error", err)
+		return
+	}
+
+	var transitions []alertTransition
+	s.mu.Lock()
+	active := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		active[rule.ID] = true
+
+		value, ok := s.measure(rule, now)
+		if !ok {
+			continue
+		}
+
+		state, ok := s.states[rule.ID]
+		if ok && (state.name != rule.Name || state.metric != rule.Metric) {
+			metrics.AlertRuleValue.DeleteLabelValues(rule.ID, state.name, string(state.metric))
+			metrics.AlertRuleFiring.DeleteLabelValues(rule.ID, state.name, string(state.metric))
+		}
+		if !ok {
+			state = &alertState{}
+			s.states[rule.ID] = state
+		}
+		state.name, state.metric = rule.Name, rule.Metric
+		state.value, state.evaluatedAt = value, now
+
+		firing := value > rule.Threshold
+		if firing != state.firing {
+			state.firing, state.since = firing, now
+			transitions = append(transitions, alertTransition{rule: rule, firing: firing, value: value})
+		}
+
+		metrics.AlertRuleValue.WithLabelValues(rule.ID, rule.Name, string(rule.Metric)).Set(value)
+		firingValue := 0.0
+		if firing {
+			firingValue = 1
+		}
+		metrics.AlertRuleFiring.WithLabelValues(rule.ID, rule.Name, string(rule.Metric)).Set(firingValue)
+	}
+	for id := range s.states {
+		if !active[id] {
+			s.forget(id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, transition := range transitions {
+		s.notify(ctx, transition)
+	}
+}
+
+// record guarda a leitura atual dos contadores e descarta as que nenhuma janela alcança mais,
+// mantendo a mais recente delas como início da janela máxima; deve ser chamado com o lock
+func (s *AlertService) record(now time.Time) {
+	s.samples = append(s.samples, alertSample{at: now, totals: metrics.ReadAlertTotals()})
+
+	cutoff := now.Add(-domain.MaxAlertWindow)
+	drop := 0
+	for drop+1 < len(s.samples) && !s.samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	s.samples = s.samples[drop:]
+}
+
+// measure calcula a medida da regra; retorna false quando ainda não há dados suficientes
+// Logo após a subida a janela é a parte dela já observada; deve ser chamado com o lock
+func (s *AlertService) measure(rule *domain.AlertRule, now time.Time) (float64, bool) {
+	if rule.Metric == domain.AlertConsumerLag {
+		if s.lag == nil {
+			return 0, false
+		}
+		return float64(s.lag()), true
+	}
+
+	current := s.samples[len(s.samples)-1]
+	start := s.samples[0]
+	for i := len(s.samples) - 1; i >= 0; i-- {
+		if !s.samples[i].at.After(now.Add(-rule.Window)) {
+			start = s.samples[i]
+			break
+		}
+	}
+	elapsed := current.at.Sub(start.at)
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	switch rule.Metric {
+	case domain.AlertDeclineRate:
+		approved := current.totals.Approved - start.totals.Approved
+		rejected := current.totals.Rejected - start.totals.Rejected
+		if approved+rejected < alertMinDecisions {
+			return 0, false
+		}
+		return rejected / (approved + rejected), true
+	case domain.AlertWebhookFailures:
+		return (current.totals.WebhookFailures - start.totals.WebhookFailures) / elapsed.Minutes(), true
+	}
+	return 0, false
+}
+
+// forget descarta o estado e as métricas de uma regra removida ou desligada; deve ser chamado com o lock
+func (s *AlertService) forget(id string) {
+	state, ok := s.states[id]
+	if !ok {
+		return
+	}
+	metrics.AlertRuleValue.DeleteLabelValues(id, state.name, string(state.metric))
+	metrics.AlertRuleFiring.DeleteLabelValues(id, state.name, string(state.metric))
+	delete(s.states, id)
+}
+
+// notify registra a mudança de estado e a entrega pelo webhook e por e-mail
+// Falhas na entrega só vão para o log e para /metrics
+func (s *AlertService) notify(ctx context.Context, transition alertTransition) {
+	rule := transition.rule
+	eventType := alertResolvedEvent
+	if transition.firing {
+		eventType = alertFiringEvent
+		metrics.AlertsFiredTotal.WithLabelValues(string(rule.Metric)).Inc()
+		slog.WarnContext(ctx, "alerta disparado", "rule_id", rule.ID, "rule", rule.Name, "metric", rule.Metric, "value", transition.value, "threshold", rule.Threshold)
+	} else {
+		slog.InfoContext(ctx, "alerta resolvido", "rule_id", rule.ID, "rule", rule.Name, "metric", rule.Metric, "value", transition.value, "threshold", rule.Threshold)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertDeliveryTimeout)
+	defer cancel()
+
+	if s.webhook != nil {
+		err := s.webhook.Send(ctx, notify.Event{
+			Type:      eventType,
+			CreatedAt: time.Now(),
+			Data: map[string]any{
+				"rule_id":   rule.ID,
+				"name":      rule.Name,
+				"metric":    rule.Metric,
+				"threshold": rule.Threshold,
+				"value":     transition.value,
+				"window":    rule.Window.String(),
+				"instance":  s.instance,
+			},
+		})
+		metrics.ObserveWebhookDelivery("webhook", err)
+		if err != nil {
+			metrics.AlertDeliveryErrorsTotal.WithLabelValues("webhook").Inc()
+			slog.ErrorContext(ctx, "erro ao enviar webhook de alerta", "error", err, "rule_id", rule.ID)
+		}
+	}
+
+	if s.mailer != nil && len(s.config.Emails) > 0 {
+		subject, body := s.alertEmail(transition)
+		if err := s.mailer.Send(ctx, s.config.Emails, subject, body); err != nil {
+			metrics.AlertDeliveryErrorsTotal.WithLabelValues("email").Inc()
+			slog.ErrorContext(ctx, "erro ao enviar e-mail de alerta", "error", err, "rule_id", rule.ID)
+		}
+	}
+}
+
+// alertEmail monta o assunto e o texto do e-mail de alerta
+func (s *AlertService) alertEmail(transition alertTransition) (string, string) {
+	rule := transition.rule
+	var value, threshold string
+	switch rule.Metric {
+	case domain.AlertDeclineRate:
+		value = fmt.Sprintf("%.1f%% das faturas recusadas nos últimos %s", transition.value*100, rule.Window)
+		threshold = fmt.Sprintf("%.1f%%", rule.Threshold*100)
+	case domain.AlertWebhookFailures:
+		value = fmt.Sprintf("%.1f falhas de webhook por minuto nos últimos %s", transition.value, rule.Window)
+		threshold = fmt.Sprintf("%.1f por minuto", rule.Threshold)
+	case domain.AlertConsumerLag:
+		value = fmt.Sprintf("%.0f mensagens pendentes no consumidor do Kafka", transition.value)
+		threshold = fmt.Sprintf("%.0f mensagens", rule.Threshold)
+	}
+
+	var body strings.Builder
+	subject := "Alerta resolvido: " + rule.Name
+	if transition.firing {
+		subject = "Alerta: " + rule.Name
+		fmt.Fprintf(&body, "A regra de alerta %q passou do limite de %s.\n\n", rule.Name, threshold)
+	} else {
+		fmt.Fprintf(&body, "A regra de alerta %q voltou ao limite de %s.\n\n", rule.Name, threshold)
+	}
+	fmt.Fprintf(&body, "Medida atual: %s.\n", value)
+	fmt.Fprintf(&body, "Instância: %s.\n", s.instance)
+	return subject, body.String()
+}
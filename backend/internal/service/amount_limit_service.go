@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// AmountLimitService gerencia as faixas de valor aceitas na criação das faturas: a padrão do gateway, a própria de
+// cada conta definida pelos administradores e a de cada meio de pagamento, que estreita as outras duas
+type AmountLimitService struct {
+	limits         domain.AccountAmountLimitsRepository
+	accountService *AccountService
+	defaults       domain.AmountLimits
+}
+
+// NewAmountLimitService cria o serviço de faixas de valor; defaults são as faixas de AMOUNT_MIN, AMOUNT_MAX e
+// AMOUNT_LIMITS_BY_PAYMENT_TYPE
+func NewAmountLimitService(limits domain.AccountAmountLimitsRepository, accountService *AccountService, defaults domain.AmountLimits) *AmountLimitService {
+	return &AmountLimitService{limits: limits, accountService: accountService, defaults: defaults}
+}
+
+// accountLimits retorna a faixa própria da conta, ou nil se ela usa a padrão
+func (s *AmountLimitService) accountLimits(ctx context.Context, accountID string) (*domain.AccountAmountLimits, error) {
+	limits, err := s.limits.FindByAccountID(ctx, accountID)
+	if err == domain.ErrAmountLimitsNotFound {
+		return nil, nil
+	}
+	return limits, err
+}
+
+// Check confere se amount cabe na faixa da conta estreitada pela do meio de pagamento
+// Retorna *domain.AmountLimitError com a faixa permitida; um AmountLimitService nil não limita
+func (s *AmountLimitService) Check(ctx context.Context, accountID, paymentType string, amount float64) error {
+	if s == nil {
+		return nil
+	}
+	account, err := s.accountLimits(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	var accountRange *domain.AmountRange
+	if account != nil {
+		accountRange = &account.Range
+	}
+
+	err = s.defaults.Range(accountRange, paymentType).Check(amount)
+	var limitErr *domain.AmountLimitError
+	if errors.As(err, &limitErr) {
+		metrics.AmountLimitRejectionsTotal.WithLabelValues(limitErr.Bound).Inc()
+		slog.InfoContext(ctx, "fatura recusada pela faixa de valor",
+			"account_id", accountID, "payment_type", paymentType, "amount", amount, "bound", limitErr.Bound)
+	}
+	return err
+}
+
+// Get retorna as faixas que valem para a conta
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *AmountLimitService) Get(ctx context.Context, accountID string) (*dto.AmountLimitsOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	account, err := s.accountLimits(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromAmountLimits(accountID, account, s.defaults), nil
+}
+
+// Update substitui a faixa própria da conta; os limites por meio de pagamento continuam valendo sobre ela
+// Retorna ErrAccountNotFound se a conta não existir e ErrInvalidAmountLimits se a faixa for inválida
+func (s *AmountLimitService) Update(ctx context.Context, accountID string, input dto.AmountRangeInput) (*dto.AmountLimitsOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	limits, err := domain.NewAccountAmountLimits(accountID, domain.AmountRange(input))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.limits.Save(ctx, limits); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "faixa de valor da conta alterada", "account_id", accountID, "min", limits.Range.Min, "max", limits.Range.Max)
+	return dto.FromAmountLimits(accountID, limits, s.defaults), nil
+}
+
+// Reset volta a conta para a faixa padrão do gateway
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *AmountLimitService) Reset(ctx context.Context, accountID string) (*dto.AmountLimitsOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	if err := s.limits.Delete(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "faixa de valor da conta voltou à padrão", "account_id", accountID)
+	return dto.FromAmountLimits(accountID, nil, s.defaults), nil
+}
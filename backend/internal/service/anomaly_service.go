@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/geoip"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+)
+
+// minBaselineDeclineRate é a taxa de recusas mínima usada como referência, para que contas quase sem recusas
+// não alertem com poucas faturas recusadas
+const minBaselineDeclineRate = 0.05
+
+// alertDeliveryTimeout limita a entrega de cada alerta, somando webhook e e-mail
+const alertDeliveryTimeout = 30 * time.Second
+
+// AnomalyThresholds são os limites que caracterizam uma anomalia, ajustáveis sem reiniciar o processo
+type AnomalyThresholds struct {
+	// VolumeFactor é quantas vezes acima da média do histórico o volume de faturas precisa estar
+	VolumeFactor float64
+	// DeclineFactor é quantas vezes acima da taxa de recusas do histórico a taxa da janela precisa estar
+	DeclineFactor float64
+	// MinInvoices é o mínimo de faturas na janela para avaliar volume e recusas
+	MinInvoices int
+}
+
+// AnomalyConfig define as janelas e os limites da detecção de anomalias
+type AnomalyConfig struct {
+	// Window é a janela analisada a cada execução, comparada com as Baseline anteriores a ela
+	Window   time.Duration
+	Baseline time.Duration
+	AnomalyThresholds
+	// AlertEmails recebe cópia de todos os alertas, além do e-mail da conta
+	AlertEmails []string
+}
+
+// AnomalyService procura periodicamente mudanças bruscas no comportamento das contas: volume de faturas,
+// taxa de recusas e autenticações de países novos, sinais de uma credencial comprometida
+// Os alertas vão para o log e para /metrics e, quando configurados, para o webhook de segurança e por e-mail
+type AnomalyService struct {
+	invoices       domain.InvoiceRepository
+	events         domain.AuthEventRepository
+	accountService *AccountService
+	database       *geoip.Database
+	webhook        *notify.Webhook
+	mailer         *notify.Mailer
+	notifier       *SecurityWebhookService
+	config         AnomalyConfig
+	// thresholds substitui os limites de config quando trocados por SetThresholds
+	thresholds atomic.Pointer[AnomalyThresholds]
+}
+
+// NewAnomalyService cria o analisador de anomalias
+// Com database nil os países não são resolvidos e países novos não são detectados; webhook e mailer são opcionais
+// Cada anomalia também vai para o webhook de segurança da conta pelo notifier
+func NewAnomalyService(
+	invoices domain.InvoiceRepository,
+	events domain.AuthEventRepository,
+	accountService *AccountService,
+	database *geoip.Database,
+	webhook *notify.Webhook,
+	mailer *notify.Mailer,
+	notifier *SecurityWebhookService,
+	config AnomalyConfig,
+) *AnomalyService {
+	s := &AnomalyService{
+		invoices:       invoices,
+		events:         events,
+		accountService: accountService,
+		database:       database,
+		webhook:        webhook,
+		mailer:         mailer,
+		notifier:       notifier,
+		config:         config,
+	}
+	s.SetThresholds(config.AnomalyThresholds)
+	return s
+}
+
+// SetThresholds troca os limites usados a partir da próxima análise; as janelas só mudam reiniciando o processo
+func (s *AnomalyService) SetThresholds(thresholds AnomalyThresholds) {
+	s.thresholds.Store(&thresholds)
+}
+
+// Run analisa a janela que terminou a cada Window; bloqueia até o contexto ser cancelado
+func (s *AnomalyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			anomalies, err := s.Analyze(ctx, now)
+			if err != nil {
+				slog.ErrorContext(ctx, "erro ao analisar anomalias das contas", "error", err)
+				continue
+			}
+			for _, anomaly := range anomalies {
+				s.alert(ctx, anomaly)
+			}
+		}
+	}
+}
+
+// Analyze compara a janela que termina em now com o histórico anterior a ela e retorna as anomalias por conta
+// Contas sem histórico não são avaliadas, já que não há comportamento usual para comparar
+func (s *AnomalyService) Analyze(ctx context.Context, now time.Time) ([]*domain.AccountAnomaly, error) {
+	thresholds := s.thresholds.Load()
+	windowStart := now.Add(-s.config.Window)
+	baselineStart := windowStart.Add(-s.config.Baseline)
+
+	current, err := s.invoices.SummarizeActivity(ctx, windowStart, now)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := s.invoices.SummarizeActivity(ctx, baselineStart, windowStart)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[string]domain.AccountActivity, len(baseline))
+	for _, activity := range baseline {
+		history[activity.AccountID] = activity
+	}
+
+	var anomalies []*domain.AccountAnomaly
+	newAnomaly := func(accountID string, kind domain.AnomalyKind) *domain.AccountAnomaly {
+		anomaly := &domain.AccountAnomaly{AccountID: accountID, Kind: kind, WindowStart: windowStart, WindowEnd: now}
+		anomalies = append(anomalies, anomaly)
+		return anomaly
+	}
+
+	// Proporção entre a janela e o histórico, para comparar o volume da janela com a média de janelas do mesmo tamanho
+	scale := s.config.Window.Seconds() / s.config.Baseline.Seconds()
+	for _, activity := range current {
+		previous, ok := history[activity.AccountID]
+		if !ok || activity.Invoices < thresholds.MinInvoices {
+			continue
+		}
+
+		expected := float64(previous.Invoices) * scale
+		if float64(activity.Invoices) >= thresholds.VolumeFactor*expected {
+			anomaly := newAnomaly(activity.AccountID, domain.AnomalyVolumeSpike)
+			anomaly.Observed = float64(activity.Invoices)
+			anomaly.Baseline = expected
+		}
+
+		rate := float64(activity.Declined) / float64(activity.Invoices)
+		usual := float64(previous.Declined) / float64(previous.Invoices)
+		if rate >= thresholds.DeclineFactor*max(usual, minBaselineDeclineRate) {
+			anomaly := newAnomaly(activity.AccountID, domain.AnomalyDeclineSpike)
+			anomaly.Observed = rate
+			anomaly.Baseline = usual
+		}
+	}
+
+	if s.database != nil {
+		currentCountries, err := s.accountCountries(ctx, windowStart, now)
+		if err != nil {
+			return nil, err
+		}
+		knownCountries, err := s.accountCountries(ctx, baselineStart, windowStart)
+		if err != nil {
+			return nil, err
+		}
+
+		for accountID, countries := range currentCountries {
+			known, ok := knownCountries[accountID]
+			if !ok {
+				continue
+			}
+			for country := range countries {
+				if _, seen := known[country]; !seen {
+					newAnomaly(accountID, domain.AnomalyNewCountry).Country = country
+				}
+			}
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].AccountID != anomalies[j].AccountID {
+			return anomalies[i].AccountID < anomalies[j].AccountID
+		}
+		if anomalies[i].Kind != anomalies[j].Kind {
+			return anomalies[i].Kind < anomalies[j].Kind
+		}
+		return anomalies[i].Country < anomalies[j].Country
+	})
+	return anomalies, nil
+}
+
+// accountCountries resolve os países de onde cada conta se autenticou com sucesso no intervalo
+// IPs fora da base GeoIP são ignorados
+func (s *AnomalyService) accountCountries(ctx context.Context, from, to time.Time) (map[string]map[string]struct{}, error) {
+	ips, err := s.events.FindAccountIPs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make(map[string]map[string]struct{})
+	for _, ip := range ips {
+		country := s.database.Country(ip.IP)
+		if country == "" {
+			continue
+		}
+		if countries[ip.AccountID] == nil {
+			countries[ip.AccountID] = make(map[string]struct{})
+		}
+		countries[ip.AccountID][country] = struct{}{}
+	}
+	return countries, nil
+}
+
+// alert registra a anomalia e a entrega pelo webhook de segurança, pelo webhook da conta e por e-mail
+// Falhas na entrega só vão para o log e para /metrics, sem impedir os demais alertas
+func (s *AnomalyService) alert(ctx context.Context, anomaly *domain.AccountAnomaly) {
+	metrics.AccountAnomaliesTotal.WithLabelValues(string(anomaly.Kind)).Inc()
+	slog.WarnContext(ctx, "comportamento anômalo da conta",
+		"kind", anomaly.Kind,
+		"account_id", anomaly.AccountID,
+		"observed", anomaly.Observed,
+		"baseline", anomaly.Baseline,
+		"country", anomaly.Country,
+		"window_start", anomaly.WindowStart,
+		"window_end", anomaly.WindowEnd)
+
+	data := map[string]any{
+		"account_id":   anomaly.AccountID,
+		"kind":         anomaly.Kind,
+		"observed":     anomaly.Observed,
+		"baseline":     anomaly.Baseline,
+		"country":      anomaly.Country,
+		"window_start": anomaly.WindowStart,
+		"window_end":   anomaly.WindowEnd,
+	}
+	s.notifier.Notify(ctx, SecurityEvent{
+		AccountID: anomaly.AccountID,
+		Type:      domain.SecurityEventAccountAnomaly,
+		Key:       string(anomaly.Kind) + ":" + anomaly.Country,
+		Data:      data,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, alertDeliveryTimeout)
+	defer cancel()
+
+	if s.webhook != nil {
+		err := s.webhook.Send(ctx, notify.Event{
+			Type:      string(domain.SecurityEventAccountAnomaly),
+			CreatedAt: time.Now(),
+			Data:      data,
+		})
+		metrics.ObserveWebhookDelivery("webhook", err)
+		if err != nil {
+			metrics.AlertDeliveryErrorsTotal.WithLabelValues("webhook").Inc()
+			slog.ErrorContext(ctx, "erro ao enviar webhook de anomalia", "error", err, "account_id", anomaly.AccountID, "kind", anomaly.Kind)
+		}
+	}
+
+	if s.mailer != nil {
+		recipients := s.config.AlertEmails
+		account, err := s.accountService.FindByID(ctx, anomaly.AccountID)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar a conta do alerta de anomalia", "error", err, "account_id", anomaly.AccountID)
+		} else if account.Email != "" {
+			recipients = append([]string{account.Email}, recipients...)
+		}
+
+		subject, body := anomalyEmail(anomaly)
+		if err := s.mailer.Send(ctx, recipients, subject, body); err != nil {
+			metrics.AlertDeliveryErrorsTotal.WithLabelValues("email").Inc()
+			slog.ErrorContext(ctx, "erro ao enviar e-mail de anomalia", "error", err, "account_id", anomaly.AccountID, "kind", anomaly.Kind)
+		}
+	}
+}
+
+// anomalyEmail monta o assunto e o texto do e-mail de alerta
+func anomalyEmail(anomaly *domain.AccountAnomaly) (string, string) {
+	var summary string
+	switch anomaly.Kind {
+	case domain.AnomalyVolumeSpike:
+		summary = fmt.Sprintf("A conta criou %.0f faturas na janela, contra uma média de %.1f.", anomaly.Observed, anomaly.Baseline)
+	case domain.AnomalyDeclineSpike:
+		summary = fmt.Sprintf("%.0f%% das faturas da janela foram recusadas, contra %.0f%% no histórico.", anomaly.Observed*100, anomaly.Baseline*100)
+	case domain.AnomalyNewCountry:
+		summary = fmt.Sprintf("A conta foi acessada a partir de um país novo: %s.", anomaly.Country)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Detectamos uma mudança brusca no comportamento da conta %s.\n\n", anomaly.AccountID)
+	fmt.Fprintf(&body, "%s\n", summary)
+	fmt.Fprintf(&body, "Janela analisada: %s a %s.\n\n", anomaly.WindowStart.UTC().Format(time.RFC3339), anomaly.WindowEnd.UTC().Format(time.RFC3339))
+	body.WriteString("Se a atividade não foi reconhecida, revise os eventos de autenticação em GET /accounts/security/events e proteja o API Key da conta.\n")
+	return "Alerta de segurança: atividade incomum na conta", body.String()
+}
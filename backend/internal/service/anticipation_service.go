@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// maxAnticipationPageSize limita as antecipações listadas
+const maxAnticipationPageSize = 100
+
+// AnticipationService antecipa, a pedido das contas, os valores que aguardam a data de repasse, descontando uma taxa
+// proporcional aos dias que faltavam
+type AnticipationService struct {
+	anticipations  domain.AnticipationRepository
+	holds          domain.EscrowRepository
+	accountService *AccountService
+	monthlyRate    float64
+}
+
+// NewAnticipationService cria o serviço de antecipações; monthlyRate é a taxa, em percentual ao mês, e 0 desativa
+// as antecipações
+func NewAnticipationService(anticipations domain.AnticipationRepository, holds domain.EscrowRepository, accountService *AccountService, monthlyRate float64) *AnticipationService {
+	return &AnticipationService{
+		anticipations:  anticipations,
+		holds:          holds,
+		accountService: accountService,
+		monthlyRate:    monthlyRate,
+	}
+}
+
+// Enabled indica se a taxa de antecipação está configurada
+func (s *AnticipationService) Enabled() bool {
+	return s.monthlyRate > 0
+}
+
+// anticipable retorna as retenções da conta que aguardam a data de repasse, só as das faturas de invoiceIDs quando
+// informadas
+func (s *AnticipationService) anticipable(ctx context.Context, accountID string, invoiceIDs []string) ([]*domain.EscrowHold, error) {
+	holds, err := s.holds.ListHeld(ctx, accountID, domain.EscrowKindPayout)
+	if err != nil || len(invoiceIDs) == 0 {
+		return holds, err
+	}
+	return slices.DeleteFunc(holds, func(hold *domain.EscrowHold) bool {
+		return !slices.Contains(invoiceIDs, hold.InvoiceID)
+	}), nil
+}
+
+// Quote simula a antecipação de tudo o que a conta do API Key tem aguardando a data de repasse
+func (s *AnticipationService) Quote(ctx context.Context, apiKey string) (*dto.AnticipationQuoteOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.anticipable(ctx, account.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := domain.NewAnticipation(account.ID, "")
+	now := time.Now()
+	for _, hold := range holds {
+		quote.Add(hold, domain.AnticipationFee(hold, now, s.monthlyRate))
+	}
+	return dto.FromAnticipationQuote(quote, s.monthlyRate), nil
+}
+
+// anticipationAttempts limita as tentativas de um pedido cujas retenções são liberadas pelo prazo durante ele
+const anticipationAttempts = 3
+
+// Request antecipa para o saldo disponível da conta do API Key os valores que aguardam a data de repasse, de todas as
+// faturas ou só das informadas, e lança as taxas no razão
+// A antecipação, a liberação das retenções, os lançamentos e o crédito são gravados na mesma transação; se o prazo
+// liberar uma das retenções durante o pedido, ele é refeito sem ela
+// Retorna ErrNothingToAnticipate se não houver valores a antecipar
+func (s *AnticipationService) Request(ctx context.Context, apiKey string, input dto.AnticipationInput) (*dto.AnticipationOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var anticipation *domain.Anticipation
+	for attempt := 1; ; attempt++ {
+		holds, err := s.anticipable(ctx, account.ID, input.InvoiceIDs)
+		if err != nil {
+			return nil, err
+		}
+		var posting *domain.Posting
+		anticipation, posting = s.build(ctx, account.ID, holds)
+		if anticipation.Holds == 0 {
+			return nil, domain.ErrNothingToAnticipate
+		}
+
+		err = s.anticipations.Create(ctx, anticipation, posting)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, domain.ErrEscrowAlreadyReleased) || attempt == anticipationAttempts {
+			return nil, err
+		}
+	}
+
+	metrics.PayoutPendingAmountTotal.WithLabelValues("anticipated").Add(anticipation.Amount)
+	metrics.AnticipationFeeTotal.Add(anticipation.Fee)
+	slog.InfoContext(ctx, "valores antecipados",
+		"anticipation_id", anticipation.ID, "account_id", account.ID, "amount", anticipation.Amount, "fee", anticipation.Fee, "holds", anticipation.Holds)
+	return dto.FromAnticipation(anticipation), nil
+}
+
+// build monta a antecipação das retenções pedida pelo autor do contexto e o movimento que as libera, lança os
+// créditos e as taxas e credita o valor líquido no saldo da conta
+func (s *AnticipationService) build(ctx context.Context, accountID string, holds []*domain.EscrowHold) (*domain.Anticipation, *domain.Posting) {
+	anticipation := domain.NewAnticipation(accountID, requestctx.Actor(ctx))
+	actor := domain.AnticipationActorPrefix + anticipation.ID
+	now := time.Now()
+	posting := &domain.Posting{}
+	for _, hold := range holds {
+		if err := hold.Release(actor); err != nil {
+			continue
+		}
+		fee := domain.AnticipationFee(hold, now, s.monthlyRate)
+		anticipation.Add(hold, fee)
+		posting.Releases = append(posting.Releases, hold)
+		posting.Entries = append(posting.Entries, domain.NewLedgerEntry(accountID, "", hold.InvoiceID, domain.LedgerCredit, hold.Amount))
+		if fee > 0 {
+			posting.Entries = append(posting.Entries, domain.NewLedgerEntry(accountID, "", hold.InvoiceID, domain.LedgerAnticipationFee, -fee))
+		}
+	}
+	posting.Balances = map[string]float64{accountID: anticipation.Net()}
+	return anticipation, posting
+}
+
+// List retorna as antecipações da conta do API Key, das mais recentes para as mais antigas
+func (s *AnticipationService) List(ctx context.Context, apiKey string) ([]*dto.AnticipationOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	anticipations, err := s.anticipations.ListByAccountID(ctx, account.ID, maxAnticipationPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.AnticipationOutput, len(anticipations))
+	for i, anticipation := range anticipations {
+		output[i] = dto.FromAnticipation(anticipation)
+	}
+	return output, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// maxAuditLimit impede consultas administrativas muito grandes
+const maxAuditLimit = 1000
+
+// defaultAuditLimit é o tamanho da página quando o limite não é informado
+const defaultAuditLimit = 100
+
+// auditExportBatch é quantas entradas a exportação busca por vez, mantendo a memória constante
+const auditExportBatch = 1000
+
+// AuditService implementa a consulta da trilha de auditoria
+type AuditService struct {
+	repository domain.AuditRepository
+}
+
+// NewAuditService cria um novo serviço de auditoria
+func NewAuditService(repository domain.AuditRepository) *AuditService {
+	return &AuditService{repository: repository}
+}
+
+// List busca uma página de entradas de auditoria pelos filtros informados
+// NextCursor aponta para a próxima página; retorna ErrInvalidCursor se o cursor estiver malformado e
+// ErrInvalidDateRange se o intervalo de datas estiver invertido
+func (s *AuditService) List(ctx context.Context, input dto.AuditLogFilterInput) (*dto.AuditLogPage, error) {
+	filter := dto.ToAuditFilter(input)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	after, err := dto.DecodeCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter.After = after
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	limit = min(limit, maxAuditLimit)
+	// Um item extra indica se existe próxima página
+	filter.Limit = limit + 1
+
+	entries, err := s.repository.FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.AuditLogPage{}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		page.NextCursor = dto.EncodeCursor(domain.Cursor{CreatedAt: last.CreatedAt, ID: strconv.FormatInt(last.ID, 10)})
+	}
+
+	page.Entries = make([]*dto.AuditLogOutput, len(entries))
+	for i, entry := range entries {
+		page.Entries[i] = dto.FromAuditEntry(entry)
+	}
+	return page, nil
+}
+
+// Export escreve em w, em CSV, todas as entradas que atendem aos filtros, das mais recentes para as mais antigas
+// As entradas são buscadas em lotes pela paginação por keyset; Limit e Cursor da entrada são ignorados
+// Os erros de validação são retornados antes de qualquer escrita em w
+func (s *AuditService) Export(ctx context.Context, input dto.AuditLogFilterInput, w io.Writer) error {
+	filter := dto.ToAuditFilter(input)
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	filter.Limit = auditExportBatch
+
+	entries, err := s.repository.FindByFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "created_at", "entity", "entity_id", "action", "actor", "request_id", "old_value", "new_value"})
+	for {
+		for _, entry := range entries {
+			writer.Write([]string{
+				strconv.FormatInt(entry.ID, 10),
+				entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+				entry.Entity,
+				entry.EntityID,
+				string(entry.Action),
+				entry.Actor,
+				entry.RequestID,
+				string(entry.OldValue),
+				string(entry.NewValue),
+			})
+		}
+		if len(entries) < auditExportBatch {
+			break
+		}
+
+		last := entries[len(entries)-1]
+		filter.After = &domain.Cursor{CreatedAt: last.CreatedAt, ID: strconv.FormatInt(last.ID, 10)}
+		if entries, err = s.repository.FindByFilter(ctx, filter); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// dummyPasswordHash é comparado quando o e-mail não existe, para que o login leve o mesmo tempo
+// com e sem usuário cadastrado e não revele quais e-mails existem
+const dummyPasswordHash = "$2a$10$QrGzDhuIu3vPEpgcnbdj2uM647Bw1.BhW5OXZYl6ORlOg3NBk705a"
+
+// AuthService implementa o login dos usuários do dashboard com JWTs de acesso e refresh tokens
+type AuthService struct {
+	users          domain.UserRepository
+	accountService *AccountService
+	events         *SecurityService
+	tokens         *auth.TokenManager
+	oidc           *auth.OIDCProvider
+	refreshTTL     time.Duration
+}
+
+// NewAuthService cria o serviço de autenticação de usuários
+// Com tokens nil o login fica desabilitado e Enabled retorna false; com oidc nil, o login por SSO
+// As tentativas de login são registradas em events
+func NewAuthService(users domain.UserRepository, accountService *AccountService, events *SecurityService, tokens *auth.TokenManager, oidc *auth.OIDCProvider, refreshTTL time.Duration) *AuthService {
+	return &AuthService{
+		users:          users,
+		accountService: accountService,
+		events:         events,
+		tokens:         tokens,
+		oidc:           oidc,
+		refreshTTL:     refreshTTL,
+	}
+}
+
+// Enabled indica se JWT_SECRET foi configurado
+func (s *AuthService) Enabled() bool {
+	return s.tokens != nil
+}
+
+// OIDCEnabled indica se o login por SSO está configurado; ele também depende de JWT_SECRET
+func (s *AuthService) OIDCEnabled() bool {
+	return s.tokens != nil && s.oidc != nil
+}
+
+// OIDCLoginURL retorna o endereço de login no provedor com state e nonce novos,
+// que o chamador guarda para conferir no callback
+func (s *AuthService) OIDCLoginURL() (loginURL, state, nonce string, err error) {
+	if state, err = randomToken(); err != nil {
+		return "", "", "", err
+	}
+	if nonce, err = randomToken(); err != nil {
+		return "", "", "", err
+	}
+	return s.oidc.AuthCodeURL(state, nonce), state, nonce, nil
+}
+
+// OIDCLogin conclui o login por SSO e emite o mesmo par de tokens do login por senha
+// O usuário precisa ter sido criado antes (POST /users) com o e-mail do provedor, que o vincula à conta
+// Quando os grupos do provedor estão mapeados, o papel do usuário é sincronizado com eles
+// Retorna ErrInvalidCredentials se o provedor recusar o login ou o e-mail não tiver usuário
+func (s *AuthService) OIDCLogin(ctx context.Context, code, nonce string) (*dto.TokenOutput, error) {
+	event := domain.AuthEvent{Method: domain.AuthMethodOIDC}
+
+	identity, err := s.oidc.Exchange(ctx, code, nonce)
+	if err == domain.ErrInvalidCredentials || err == domain.ErrInvalidToken {
+		event.Reason = err.Error()
+		s.events.Record(ctx, event)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.FindByEmail(ctx, identity.Email)
+	if err == domain.ErrUserNotFound {
+		event.Reason = "unknown email"
+		s.events.Record(ctx, event)
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if role, ok := s.oidc.Role(identity); ok && role != user.Role {
+		// A troca de papel é atribuída ao provedor na auditoria
+		if err := s.users.UpdateRole(requestctx.WithActor(ctx, "oidc:"+identity.Subject), user.ID, role); err != nil {
+			return nil, err
+		}
+		user.Role = role
+	}
+
+	s.events.Record(ctx, domain.AuthEvent{Method: domain.AuthMethodOIDC, AccountID: user.AccountID, KeyID: "user:" + user.ID, Success: true})
+	return s.issueTokens(ctx, user, "")
+}
+
+// AuthenticateAdmin valida o ID token do provedor apresentado à API administrativa e retorna o e-mail do operador
+// Retorna ErrInvalidToken se o token for inválido e ErrForbidden, com o e-mail, se o operador não estiver em OIDC_ADMIN_GROUPS
+func (s *AuthService) AuthenticateAdmin(ctx context.Context, idToken string) (string, error) {
+	if !s.oidc.AdminEnabled() {
+		return "", domain.ErrInvalidToken
+	}
+
+	identity, err := s.oidc.Verify(ctx, idToken)
+	if err != nil {
+		return "", err
+	}
+	if !s.oidc.IsAdmin(identity) {
+		return identity.Email, domain.ErrForbidden
+	}
+	return identity.Email, nil
+}
+
+// AdminSSOEnabled indica se a API administrativa aceita ID tokens do provedor
+func (s *AuthService) AdminSSOEnabled() bool {
+	return s.oidc.AdminEnabled()
+}
+
+// CreateUser cria um usuário do dashboard vinculado à conta do API Key
+// O papel admin só é concedido pela API administrativa; pedi-lo aqui retorna ErrForbidden
+// Retorna ErrDuplicatedEmail se o e-mail já estiver cadastrado
+func (s *AuthService) CreateUser(ctx context.Context, apiKey string, input dto.CreateUserInput) (*dto.UserOutput, error) {
+	role := domain.RoleMerchant
+	if input.Role != "" {
+		var err error
+		if role, err = domain.ParseRole(input.Role); err != nil {
+			return nil, err
+		}
+	}
+	if role == domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(input.Email)
+	existing, err := s.users.FindByEmail(ctx, email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrDuplicatedEmail
+	}
+
+	user, err := domain.NewUser(account.ID, email, input.Password, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromUser(user)
+	return &output, nil
+}
+
+// Login valida e-mail e senha e emite um novo par de tokens
+// Retorna ErrInvalidCredentials sem distinguir e-mail inexistente de senha errada
+func (s *AuthService) Login(ctx context.Context, input dto.LoginInput) (*dto.TokenOutput, error) {
+	event := domain.AuthEvent{Method: domain.AuthMethodPassword}
+
+	user, err := s.users.FindByEmail(ctx, input.Email)
+	if err == domain.ErrUserNotFound {
+		(&domain.User{PasswordHash: dummyPasswordHash}).CheckPassword(input.Password)
+		event.Reason = "unknown email"
+		s.events.Record(ctx, event)
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event.AccountID, event.KeyID = user.AccountID, "user:"+user.ID
+	if !user.CheckPassword(input.Password) {
+		event.Reason = "wrong password"
+		s.events.Record(ctx, event)
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	event.Success = true
+	s.events.Record(ctx, event)
+	return s.issueTokens(ctx, user, "")
+}
+
+// Refresh troca um refresh token ativo por um novo par, revogando o anterior (rotação)
+// Retorna ErrInvalidToken se o token não existir, estiver expirado ou já tiver sido usado
+func (s *AuthService) Refresh(ctx context.Context, input dto.RefreshTokenInput) (*dto.TokenOutput, error) {
+	token, err := s.activeRefreshToken(ctx, input.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// A revogação condicional garante que dois usos simultâneos do mesmo token renovem apenas uma vez
+	if err := s.users.RevokeRefreshToken(ctx, token.ID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, token.UserID)
+	if err == domain.ErrUserNotFound {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, token.SessionID)
+}
+
+// Logout revoga o refresh token; o JWT de acesso continua válido até expirar
+func (s *AuthService) Logout(ctx context.Context, input dto.RefreshTokenInput) error {
+	token, err := s.activeRefreshToken(ctx, input.RefreshToken)
+	if err != nil {
+		return err
+	}
+	return s.users.RevokeRefreshToken(ctx, token.ID)
+}
+
+// Authenticate valida o JWT de acesso e retorna a conta e o usuário
+// O papel vem do usuário armazenado, então trocas de papel valem já na próxima requisição
+// Retorna ErrInvalidToken se o token for inválido ou o usuário não existir mais
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*dto.AccountOutput, *dto.UserOutput, error) {
+	if s.tokens == nil {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	claims, err := s.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, claims.Subject)
+	if err == domain.ErrUserNotFound {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.accountService.FindByID(ctx, user.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := dto.FromUser(user)
+	return account, &output, nil
+}
+
+// UpdateUserRole altera o papel de um usuário
+// Retorna ErrInvalidRole se o papel não existir e ErrUserNotFound se o usuário não existir
+func (s *AuthService) UpdateUserRole(ctx context.Context, id string, input dto.UpdateRoleInput) (*dto.UserOutput, error) {
+	role, err := domain.ParseRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.UpdateRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	output := dto.FromUser(user)
+	return &output, nil
+}
+
+// ListSessions lista as sessões ativas do usuário, das usadas mais recentemente para as mais antigas
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]dto.SessionOutput, error) {
+	tokens, err := s.users.FindActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.SessionOutput, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = dto.FromRefreshToken(token)
+	}
+	return sessions, nil
+}
+
+// RevokeSession encerra uma sessão do usuário; o JWT de acesso já emitido continua válido até expirar
+// Retorna ErrSessionNotFound se a sessão não existir, já tiver sido encerrada ou for de outro usuário
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	return s.users.RevokeSession(ctx, userID, sessionID)
+}
+
+// ChangePassword troca a senha do usuário e encerra todas as suas sessões, inclusive a atual
+// Retorna ErrInvalidCredentials se a senha atual não conferir e ErrWeakPassword se a nova for curta demais
+func (s *AuthService) ChangePassword(ctx context.Context, userID string, input dto.ChangePasswordInput) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Senhas atuais erradas contam para o bloqueio como falhas de login
+	if !user.CheckPassword(input.CurrentPassword) {
+		s.events.Record(ctx, domain.AuthEvent{Method: domain.AuthMethodPassword, AccountID: user.AccountID, KeyID: "user:" + user.ID, Reason: "wrong password"})
+		return domain.ErrInvalidCredentials
+	}
+
+	hash, err := domain.HashPassword(input.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+	return s.users.RevokeUserRefreshTokens(ctx, user.ID)
+}
+
+// activeRefreshToken busca o refresh token pelo hash e confere se ainda está ativo
+func (s *AuthService) activeRefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshToken, error) {
+	if refreshToken == "" {
+		return nil, domain.ErrInvalidToken
+	}
+
+	token, err := s.users.FindRefreshToken(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if !token.IsActive() {
+		return nil, domain.ErrInvalidToken
+	}
+	return token, nil
+}
+
+// issueTokens emite o JWT de acesso e um novo refresh token para o usuário
+// sessionID vazio abre uma sessão nova; na renovação o token novo continua a sessão do anterior
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, sessionID string) (*dto.TokenOutput, error) {
+	accessToken, expiresAt, err := s.tokens.Issue(user)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionID == "" {
+		sessionID = domain.NewID()
+	}
+
+	now := time.Now()
+	client := requestctx.ClientInfo(ctx)
+	err = s.users.SaveRefreshToken(ctx, &domain.RefreshToken{
+		ID:        domain.NewID(),
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: hashRefreshToken(refreshToken),
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: now.Add(s.refreshTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenOutput{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// randomToken gera 256 bits aleatórios codificados para uso em URLs e cookies
+func randomToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashRefreshToken calcula o SHA-256 armazenado no lugar do refresh token
+// O token tem 256 bits aleatórios, então um hash rápido basta
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// BlocklistSubject são os valores de uma fatura conferidos contra as listas de bloqueio; vazios são ignorados
+type BlocklistSubject struct {
+	Card     string
+	Email    string
+	Document string
+	IP       string
+}
+
+// BlocklistService mantém a lista de bloqueio global, gerida pelos administradores, e as listas das contas
+// Os valores só são guardados pelo HMAC com hashKey, junto de uma versão mascarada para exibição
+type BlocklistService struct {
+	entries        domain.BlocklistRepository
+	accountService *AccountService
+	hashKey        []byte
+	notifier       *SecurityWebhookService
+}
+
+// NewBlocklistService cria o serviço de listas de bloqueio
+// As faturas recusadas vão para o webhook de segurança da conta pelo notifier
+func NewBlocklistService(entries domain.BlocklistRepository, accountService *AccountService, hashKey []byte, notifier *SecurityWebhookService) *BlocklistService {
+	return &BlocklistService{entries: entries, accountService: accountService, hashKey: hashKey, notifier: notifier}
+}
+
+// List retorna a lista de bloqueio da conta do API Key
+func (s *BlocklistService) List(ctx context.Context, apiKey string) ([]*dto.BlocklistEntryOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, account.ID)
+}
+
+// Create adiciona uma entrada à lista da conta do API Key, que pode bloquear um valor ou liberá-lo da lista global
+// Retorna ErrInvalidBlocklistEntry para entradas inválidas e ErrBlocklistEntryExists se o valor já está na lista
+func (s *BlocklistService) Create(ctx context.Context, apiKey string, input dto.BlocklistEntryInput) (*dto.BlocklistEntryOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(ctx, account.ID, input)
+}
+
+// Delete remove uma entrada da lista da conta do API Key
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir ou não for da conta
+func (s *BlocklistService) Delete(ctx context.Context, apiKey, id string) error {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, account.ID, id)
+}
+
+// ListGlobal retorna a lista de bloqueio global
+func (s *BlocklistService) ListGlobal(ctx context.Context) ([]*dto.BlocklistEntryOutput, error) {
+	return s.list(ctx, "")
+}
+
+// CreateGlobal adiciona um bloqueio à lista global, que vale para todas as contas
+// Retorna ErrInvalidBlocklistEntry para entradas inválidas, inclusive liberações, e ErrBlocklistEntryExists se o
+// valor já está na lista
+func (s *BlocklistService) CreateGlobal(ctx context.Context, input dto.BlocklistEntryInput) (*dto.BlocklistEntryOutput, error) {
+	return s.create(ctx, "", input)
+}
+
+// DeleteGlobal remove uma entrada da lista global
+// Retorna ErrBlocklistEntryNotFound se a entrada não existir ou for de uma conta
+func (s *BlocklistService) DeleteGlobal(ctx context.Context, id string) error {
+	return s.delete(ctx, "", id)
+}
+
+func (s *BlocklistService) list(ctx context.Context, accountID string) ([]*dto.BlocklistEntryOutput, error) {
+	entries, err := s.entries.List(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromBlocklistEntries(entries), nil
+}
+
+func (s *BlocklistService) create(ctx context.Context, accountID string, input dto.BlocklistEntryInput) (*dto.BlocklistEntryOutput, error) {
+	entryType := domain.BlocklistType(input.Type)
+	normalized, err := domain.NormalizeBlocklistValue(entryType, input.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := domain.NewBlocklistEntry(accountID, entryType, s.hash(entryType, normalized),
+		domain.MaskBlocklistValue(entryType, normalized), domain.BlocklistAction(input.Action), input.Reason, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match também traz as entradas globais; só uma da mesma lista é duplicada
+	existing, err := s.entries.Match(ctx, accountID, []string{entry.ValueHash})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if other.AccountID == accountID {
+			return nil, domain.ErrBlocklistEntryExists
+		}
+	}
+
+	if err := s.entries.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return dto.FromBlocklistEntry(entry), nil
+}
+
+func (s *BlocklistService) delete(ctx context.Context, accountID, id string) error {
+	entry, err := s.entries.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entry.AccountID != accountID {
+		return domain.ErrBlocklistEntryNotFound
+	}
+	return s.entries.Delete(ctx, id)
+}
+
+// Screen confere os valores das faturas contra a lista global e a da conta
+// Para cada valor a entrada da conta prevalece sobre a global; as entradas aplicadas, de bloqueio ou liberação,
+// registram o acerto
+// Retorna ErrBlocklisted quando algum valor está bloqueado
+func (s *BlocklistService) Screen(ctx context.Context, accountID string, subjects []BlocklistSubject) error {
+	if s == nil {
+		return nil
+	}
+
+	var hashes []string
+	seen := make(map[string]bool)
+	for _, subject := range subjects {
+		for _, value := range []struct {
+			entryType domain.BlocklistType
+			value     string
+		}{
+			{domain.BlocklistCard, subject.Card},
+			{domain.BlocklistEmail, subject.Email},
+			{domain.BlocklistDocument, subject.Document},
+			{domain.BlocklistIP, subject.IP},
+		} {
+			if value.value == "" {
+				continue
+			}
+			// Valores fora do formato do tipo não podem estar na lista
+			normalized, err := domain.NormalizeBlocklistValue(value.entryType, value.value)
+			if err != nil {
+				continue
+			}
+			if hash := s.hash(value.entryType, normalized); !seen[hash] {
+				seen[hash] = true
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	entries, err := s.entries.Match(ctx, accountID, hashes)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	blocked, applied := domain.ResolveBlocklist(entries, accountID, now)
+	if len(applied) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(applied))
+	for i, entry := range applied {
+		ids[i] = entry.ID
+		metrics.BlocklistHitsTotal.WithLabelValues(blocklistScope(entry), string(entry.Type), string(entry.Action)).Inc()
+	}
+	// A contagem de acertos é informativa e não impede a decisão
+	if err := s.entries.RecordHits(ctx, ids, now); err != nil {
+		slog.WarnContext(ctx, "falha ao registrar os acertos da lista de bloqueio", "account_id", accountID, "error", err)
+	}
+	if blocked == nil {
+		return nil
+	}
+
+	scope := blocklistScope(blocked)
+	slog.WarnContext(ctx, "fatura recusada pela lista de bloqueio",
+		"account_id", accountID,
+		"entry_id", blocked.ID,
+		"scope", scope,
+		"type", blocked.Type,
+	)
+	s.notifier.Notify(ctx, SecurityEvent{
+		AccountID: accountID,
+		Type:      domain.SecurityEventBlocklistHit,
+		Key:       blocked.ID,
+		Data: map[string]any{
+			"entry_id": blocked.ID,
+			"scope":    scope,
+			"type":     blocked.Type,
+			"value":    blocked.Hint,
+		},
+	})
+	return domain.ErrBlocklisted
+}
+
+// hash é o HMAC do tipo com o valor normalizado, para que valores iguais de tipos diferentes não se confundam
+func (s *BlocklistService) hash(entryType domain.BlocklistType, normalized string) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte(string(entryType) + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// blocklistScope é o escopo da entrada em /metrics e nos eventos
+func blocklistScope(entry *domain.BlocklistEntry) string {
+	if entry.AccountID == "" {
+		return dto.BlocklistScopeGlobal
+	}
+	return dto.BlocklistScopeAccount
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// maxCouponPageSize limita a listagem de cupons da conta
+const maxCouponPageSize = 500
+
+// CouponService gerencia os cupons de desconto das contas e aplica os cupons informados na criação das faturas
+type CouponService struct {
+	coupons        domain.CouponRepository
+	accountService *AccountService
+}
+
+// NewCouponService cria o serviço de cupons
+func NewCouponService(coupons domain.CouponRepository, accountService *AccountService) *CouponService {
+	return &CouponService{coupons: coupons, accountService: accountService}
+}
+
+// Create cria o cupom da conta do API Key
+// Retorna ErrInvalidCoupon se o cupom for inválido e ErrCouponExists se a conta já tiver o código
+func (s *CouponService) Create(ctx context.Context, apiKey string, input dto.CreateCouponInput) (*dto.CouponOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	coupon, err := domain.NewCoupon(account.ID, input.Code, input.Type, input.Value, input.MaxRedemptions, input.ValidFrom, input.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.coupons.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "cupom criado", "account_id", account.ID, "coupon_id", coupon.ID, "code", coupon.Code)
+	return dto.FromCoupon(coupon), nil
+}
+
+// List retorna os cupons da conta do API Key, dos mais novos para os mais antigos
+func (s *CouponService) List(ctx context.Context, apiKey string) ([]*dto.CouponOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	coupons, err := s.coupons.List(ctx, account.ID, maxCouponPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromCoupons(coupons), nil
+}
+
+// Get retorna o cupom da conta do API Key com os usos já consumidos
+// Retorna ErrCouponNotFound se ele não existir ou for de outra conta
+func (s *CouponService) Get(ctx context.Context, apiKey, couponID string) (*dto.CouponOutput, error) {
+	coupon, err := s.find(ctx, apiKey, couponID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromCoupon(coupon), nil
+}
+
+// Deactivate desativa o cupom da conta do API Key; as faturas já criadas com ele mantêm o desconto
+// Retorna ErrCouponNotFound se ele não existir ou for de outra conta
+func (s *CouponService) Deactivate(ctx context.Context, apiKey, couponID string) (*dto.CouponOutput, error) {
+	coupon, err := s.find(ctx, apiKey, couponID)
+	if err != nil {
+		return nil, err
+	}
+	coupon.Active = false
+	coupon.UpdatedAt = time.Now()
+	if err := s.coupons.Deactivate(ctx, coupon.ID, coupon.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "cupom desativado", "account_id", coupon.AccountID, "coupon_id", coupon.ID)
+	return dto.FromCoupon(coupon), nil
+}
+
+// find busca o cupom da conta do API Key
+func (s *CouponService) find(ctx context.Context, apiKey, couponID string) (*domain.Coupon, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	coupon, err := s.coupons.FindByID(ctx, couponID)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.AccountID != account.ID {
+		return nil, domain.ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+// Apply aplica à fatura o desconto do cupom da conta com o código informado e retorna o uso a gravar com Redeem
+// Sem código, a fatura não muda e o uso é nil; retorna ErrCouponNotFound se a conta não tiver o cupom e
+// ErrCouponUnavailable se ele não puder ser usado agora
+func (s *CouponService) Apply(ctx context.Context, invoice *domain.Invoice, code string) (*domain.CouponRedemption, error) {
+	if code == "" {
+		return nil, nil
+	}
+	coupon, err := s.coupons.FindByCode(ctx, invoice.AccountID, domain.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+	discount, err := coupon.Discount(invoice.Amount, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	invoice.ApplyDiscount(coupon.Code, discount)
+	return domain.NewCouponRedemption(coupon, invoice, discount), nil
+}
+
+// Redeem consome um uso do cupom aplicado por Apply; redemption nil é ignorado
+// Retorna ErrCouponUnavailable se o último uso foi consumido ou o cupom desativado depois de Apply
+func (s *CouponService) Redeem(ctx context.Context, redemption *domain.CouponRedemption) error {
+	if redemption == nil {
+		return nil
+	}
+	if err := s.coupons.Redeem(ctx, redemption); err != nil {
+		return err
+	}
+	metrics.CouponRedemptionsTotal.Inc()
+	metrics.CouponDiscountTotal.Add(redemption.Discount)
+	return nil
+}
+
+// SumDiscounts soma os descontos concedidos pelos cupons da conta de from até to
+func (s *CouponService) SumDiscounts(ctx context.Context, accountID string, from, to time.Time) (float64, error) {
+	return s.coupons.SumDiscounts(ctx, accountID, from, to)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// maxCustomerPageSize limita a listagem de clientes da conta
+const maxCustomerPageSize = 500
+
+// CustomerService gerencia os clientes das contas e os cartões guardados deles, cobrados nas compras seguintes
+// sem que o pagador informe o cartão de novo
+type CustomerService struct {
+	customers      domain.CustomerRepository
+	cards          *carddata.Vault
+	accountService *AccountService
+}
+
+// NewCustomerService cria o serviço de clientes; os cartões dos clientes ficam no cofre cards
+func NewCustomerService(customers domain.CustomerRepository, cards *carddata.Vault, accountService *AccountService) *CustomerService {
+	return &CustomerService{customers: customers, cards: cards, accountService: accountService}
+}
+
+// CardsEnabled indica se o cofre guarda o número dos cartões, sem o qual os cartões dos clientes não podem ser cobrados
+func (s *CustomerService) CardsEnabled() bool {
+	return s.cards.RetainsNumbers()
+}
+
+// Create cria o cliente da conta do API Key e guarda o cartão informado
+// Retorna ErrInvalidCustomer se os dados do cliente forem inválidos, os erros de validação do cartão e
+// ErrDependencyUnavailable se o cartão não puder ser guardado porque o cofre não guarda os números
+func (s *CustomerService) Create(ctx context.Context, apiKey string, input dto.CreateCustomerInput) (*dto.CustomerOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := domain.NewCustomer(account.ID, input.Name, input.Email, input.Document)
+	if err != nil {
+		return nil, err
+	}
+	var card *carddata.Card
+	if input.Card != nil {
+		if !s.CardsEnabled() {
+			return nil, domain.ErrDependencyUnavailable
+		}
+		if card, err = newCustomerCard(*input.Card); err != nil {
+			return nil, err
+		}
+		// O cliente só é criado se o cartão puder ser guardado
+		if err := card.CheckMode(account.TestMode); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.customers.Create(ctx, customer); err != nil {
+		return nil, err
+	}
+	var methods []*domain.CustomerPaymentMethod
+	if card != nil {
+		method, err := s.addCard(ctx, customer, card, account.TestMode)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	slog.InfoContext(ctx, "cliente criado", "account_id", account.ID, "customer_id", customer.ID)
+	return dto.FromCustomer(customer, methods), nil
+}
+
+// List retorna os clientes da conta do API Key, dos mais novos para os mais antigos, sem os cartões
+func (s *CustomerService) List(ctx context.Context, apiKey string) ([]*dto.CustomerOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	customers, err := s.customers.List(ctx, account.ID, maxCustomerPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromCustomers(customers), nil
+}
+
+// Get retorna o cliente da conta do API Key com os cartões guardados
+// Retorna ErrCustomerNotFound se ele não existir ou for de outra conta
+func (s *CustomerService) Get(ctx context.Context, apiKey, customerID string) (*dto.CustomerOutput, error) {
+	customer, err := s.findByAPIKey(ctx, apiKey, customerID)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.customers.ListPaymentMethods(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromCustomer(customer, methods), nil
+}
+
+// Delete apaga o cliente da conta do API Key e os dados dos cartões dele no cofre
+// As faturas já pagas mantêm o cliente nos metadados; retorna ErrCustomerNotFound se ele não existir ou for de
+// outra conta
+func (s *CustomerService) Delete(ctx context.Context, apiKey, customerID string) error {
+	customer, err := s.findByAPIKey(ctx, apiKey, customerID)
+	if err != nil {
+		return err
+	}
+	methods, err := s.customers.ListPaymentMethods(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.forget(ctx, customer, methods); err != nil {
+		return err
+	}
+	if err := s.customers.Delete(ctx, customer.ID); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "cliente apagado", "account_id", customer.AccountID, "customer_id", customer.ID)
+	return nil
+}
+
+// AddPaymentMethod guarda um cartão no cliente da conta do API Key
+// Retorna ErrCustomerNotFound se ele não existir ou for de outra conta, ErrPaymentMethodLimit se ele já tiver
+// MaxCustomerPaymentMethods cartões e os erros de validação do cartão
+func (s *CustomerService) AddPaymentMethod(ctx context.Context, apiKey, customerID string, input dto.CardInput) (*dto.PaymentMethodOutput, error) {
+	customer, err := s.findByAPIKey(ctx, apiKey, customerID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := newCustomerCard(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckCardLimit(ctx, customer); err != nil {
+		return nil, err
+	}
+	method, err := s.addCard(ctx, customer, card, domain.IsTestAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromPaymentMethod(method), nil
+}
+
+// RemovePaymentMethod desliga o cartão do cliente da conta do API Key e apaga os dados dele no cofre
+// Retorna ErrCustomerNotFound se o cliente não existir ou for de outra conta e ErrCardNotFound se o cartão não for
+// dele
+func (s *CustomerService) RemovePaymentMethod(ctx context.Context, apiKey, customerID, cardToken string) error {
+	customer, err := s.findByAPIKey(ctx, apiKey, customerID)
+	if err != nil {
+		return err
+	}
+	if err := s.customers.RemovePaymentMethod(ctx, customer.ID, cardToken); err != nil {
+		return err
+	}
+	return s.forget(ctx, customer, []*domain.CustomerPaymentMethod{{CardToken: cardToken}})
+}
+
+// Find retorna o cliente da conta para a criação de uma fatura
+// Retorna ErrCustomerNotFound se ele não existir ou for de outra conta
+func (s *CustomerService) Find(ctx context.Context, accountID, customerID string) (*domain.Customer, error) {
+	customer, err := s.customers.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.AccountID != accountID {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// Card recupera do cofre um cartão guardado do cliente, para cobrá-lo sem que o pagador o informe de novo
+// Retorna ErrCardNotFound se o token não for de um cartão do cliente ou o cofre não guardar o número
+func (s *CustomerService) Card(ctx context.Context, customer *domain.Customer, cardToken string) (*carddata.Card, error) {
+	methods, err := s.customers.ListPaymentMethods(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, method := range methods {
+		if method.CardToken == cardToken {
+			return s.cards.Detokenize(ctx, customer.AccountID, cardToken)
+		}
+	}
+	return nil, domain.ErrCardNotFound
+}
+
+// CheckCardLimit confere se o cliente ainda pode guardar um cartão
+// Retorna ErrDependencyUnavailable se o cofre não guardar o número dos cartões e ErrPaymentMethodLimit se o cliente
+// já tiver MaxCustomerPaymentMethods cartões
+func (s *CustomerService) CheckCardLimit(ctx context.Context, customer *domain.Customer) error {
+	if !s.CardsEnabled() {
+		return domain.ErrDependencyUnavailable
+	}
+	methods, err := s.customers.ListPaymentMethods(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	if len(methods) >= domain.MaxCustomerPaymentMethods {
+		return domain.ErrPaymentMethodLimit
+	}
+	return nil
+}
+
+// SaveCard liga ao cliente o cartão já guardado no cofre com a fatura
+func (s *CustomerService) SaveCard(ctx context.Context, customer *domain.Customer, card domain.PaymentCard) error {
+	return s.customers.AddPaymentMethod(ctx, domain.NewCustomerPaymentMethod(customer, card))
+}
+
+// findByAPIKey busca o cliente da conta do API Key
+func (s *CustomerService) findByAPIKey(ctx context.Context, apiKey, customerID string) (*domain.Customer, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.Find(ctx, account.ID, customerID)
+}
+
+// addCard guarda o cartão no cofre e o liga ao cliente; testMode informa se a conta está em modo de teste
+func (s *CustomerService) addCard(ctx context.Context, customer *domain.Customer, card *carddata.Card, testMode bool) (*domain.CustomerPaymentMethod, error) {
+	token, err := s.cards.Tokenize(ctx, customer.AccountID, card, testMode)
+	if err != nil {
+		return nil, err
+	}
+	paymentCard := card.PaymentCard()
+	paymentCard.Token = token
+	method := domain.NewCustomerPaymentMethod(customer, paymentCard)
+	if err := s.customers.AddPaymentMethod(ctx, method); err != nil {
+		return nil, err
+	}
+	return method, nil
+}
+
+// forget apaga do cofre os dados dos cartões do cliente, que deixam de poder ser cobrados
+func (s *CustomerService) forget(ctx context.Context, customer *domain.Customer, methods []*domain.CustomerPaymentMethod) error {
+	tokens := make([]string, len(methods))
+	for i, method := range methods {
+		tokens[i] = method.CardToken
+	}
+	anonymizationToken, err := domain.NewAnonymizationToken()
+	if err != nil {
+		return err
+	}
+	return s.cards.Forget(ctx, customer.AccountID, tokens, anonymizationToken)
+}
+
+// newCustomerCard valida o cartão a guardar no cliente
+func newCustomerCard(input dto.CardInput) (*carddata.Card, error) {
+	return carddata.NewCard(input.CardNumber, input.CVV, input.ExpiryMonth, input.ExpiryYear, input.CardholderName)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// DataSubjectService atende os pedidos de titulares de dados (LGPD/GDPR) sobre os pagadores das faturas:
+// exportação dos dados pessoais e anonimização, que preserva os registros financeiros
+// Cada pedido atendido é registrado sem os dados pessoais do titular
+type DataSubjectService struct {
+	invoices       domain.InvoiceRepository
+	requests       domain.DataSubjectRequestRepository
+	accountService *AccountService
+	vault          *carddata.Vault
+}
+
+// NewDataSubjectService cria o serviço de pedidos de titulares
+func NewDataSubjectService(invoices domain.InvoiceRepository, requests domain.DataSubjectRequestRepository, accountService *AccountService, vault *carddata.Vault) *DataSubjectService {
+	return &DataSubjectService{invoices: invoices, requests: requests, accountService: accountService, vault: vault}
+}
+
+// findSubject busca a conta do API Key e as faturas do pagador informado
+// Retorna ErrInvalidDataSubject sem nome e ErrDataSubjectNotFound quando nenhuma fatura é do pagador
+func (s *DataSubjectService) findSubject(ctx context.Context, apiKey string, input dto.DataSubjectInput) (*dto.AccountOutput, string, []*domain.Invoice, error) {
+	payerName := domain.NormalizePayerName(input.PayerName)
+	if payerName == "" {
+		return nil, "", nil, domain.ErrInvalidDataSubject
+	}
+
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	invoices, err := s.invoices.FindByPayer(ctx, account.ID, payerName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, "", nil, domain.ErrDataSubjectNotFound
+	}
+	return account, payerName, invoices, nil
+}
+
+// cardTokens retorna os tokens de cartão distintos das faturas
+func cardTokens(invoices []*domain.Invoice) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, invoice := range invoices {
+		if invoice.CardToken != "" && !seen[invoice.CardToken] {
+			seen[invoice.CardToken] = true
+			tokens = append(tokens, invoice.CardToken)
+		}
+	}
+	return tokens
+}
+
+// record registra o pedido atendido; falhas vão para o log, já que o pedido em si foi concluído
+func (s *DataSubjectService) record(ctx context.Context, request *domain.DataSubjectRequest) {
+	if err := s.requests.Save(ctx, request); err != nil {
+		slog.ErrorContext(ctx, "erro ao registrar pedido de titular", "error", err, "account_id", request.AccountID, "type", request.Type)
+	}
+}
+
+// Export reúne as faturas do pagador, inclusive excluídas e arquivadas, e os cartões dele guardados no cofre
+func (s *DataSubjectService) Export(ctx context.Context, apiKey string, input dto.DataSubjectInput) (*dto.DataSubjectExport, error) {
+	account, payerName, invoices, err := s.findSubject(ctx, apiKey, input)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &dto.DataSubjectExport{
+		PayerName:   payerName,
+		GeneratedAt: time.Now(),
+		Invoices:    make([]*dto.InvoiceOutput, len(invoices)),
+		Cards:       []*dto.DataSubjectCardOutput{},
+	}
+	for i, invoice := range invoices {
+		export.Invoices[i] = dto.FromInvoice(invoice)
+	}
+
+	for _, token := range cardTokens(invoices) {
+		details, err := s.vault.Describe(ctx, account.ID, token)
+		if err == domain.ErrCardNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		export.Cards = append(export.Cards, dto.FromCardDetails(details))
+	}
+
+	request := domain.NewDataSubjectRequest(account.ID, domain.DataSubjectExport, len(invoices), "", requestctx.Actor(ctx), requestctx.RequestID(ctx))
+	s.record(ctx, request)
+	export.RequestID = request.ID
+	return export, nil
+}
+
+// Anonymize troca o nome do pagador por um token irreversível nas faturas e na auditoria delas e apaga os dados
+// dos cartões dele no cofre; valores, status e datas das faturas são preservados
+// O mesmo token substitui o nome em todas as faturas, que continuam agrupadas pelo titular
+func (s *DataSubjectService) Anonymize(ctx context.Context, apiKey string, input dto.DataSubjectInput) (*dto.DataSubjectRequestOutput, error) {
+	account, payerName, invoices, err := s.findSubject(ctx, apiKey, input)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := domain.NewAnonymizationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// O cofre vem primeiro: se as faturas falharem, um novo pedido ainda encontra os cartões pelas faturas
+	if err := s.vault.Forget(ctx, account.ID, cardTokens(invoices), token); err != nil {
+		return nil, err
+	}
+	count, err := s.invoices.AnonymizePayer(ctx, account.ID, payerName, token)
+	if err != nil {
+		return nil, err
+	}
+
+	request := domain.NewDataSubjectRequest(account.ID, domain.DataSubjectAnonymize, int(count), token, requestctx.Actor(ctx), requestctx.RequestID(ctx))
+	s.record(ctx, request)
+	return dto.FromDataSubjectRequest(request), nil
+}
+
+// ListRequests lista os pedidos de titulares atendidos para a conta do API Key
+func (s *DataSubjectService) ListRequests(ctx context.Context, apiKey string) ([]*dto.DataSubjectRequestOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.requests.FindByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.DataSubjectRequestOutput, len(requests))
+	for i, request := range requests {
+		output[i] = dto.FromDataSubjectRequest(request)
+	}
+	return output, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/objectstore"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// maxDisputePageSize limita as listagens de disputas
+const maxDisputePageSize = 500
+
+// DisputeService registra as disputas das faturas aprovadas e guarda as evidências enviadas pelas contas
+// No registro, a tarifa de chargeback das tarifas da conta é debitada do saldo e lançada no razão, na mesma
+// transação da disputa; na disputa perdida o valor da fatura é estornado na transação do encerramento
+// Os arquivos ficam no armazenamento de objetos das exportações e só são servidos à conta dona da disputa
+type DisputeService struct {
+	disputes       domain.DisputeRepository
+	invoices       domain.InvoiceRepository
+	accountService *AccountService
+	fees           *FeeScheduleService
+	store          objectstore.Store
+}
+
+// NewDisputeService cria o serviço de disputas
+// Com store nil as disputas funcionam, mas o envio e o download de evidências ficam desativados
+func NewDisputeService(disputes domain.DisputeRepository, invoices domain.InvoiceRepository, accountService *AccountService, fees *FeeScheduleService, store objectstore.Store) *DisputeService {
+	return &DisputeService{
+		disputes:       disputes,
+		invoices:       invoices,
+		accountService: accountService,
+		fees:           fees,
+		store:          store,
+	}
+}
+
+// EvidenceEnabled indica se há armazenamento configurado para as evidências
+func (s *DisputeService) EvidenceEnabled() bool {
+	return s.store != nil
+}
+
+// Open registra a disputa da fatura, aberta pelo pagador junto ao emissor do cartão, e cobra a tarifa de chargeback
+// Retorna ErrInvalidStatus se a fatura não estiver aprovada e ErrTransactionAlreadyDisputed se ela já tiver disputa
+func (s *DisputeService) Open(ctx context.Context, invoiceID string, input dto.OpenDisputeInput) (*dto.DisputeOutput, error) {
+	invoice, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := s.fees.ChargebackFee(ctx, invoice.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	dispute, err := domain.NewDispute(invoice, input.Reason, fee)
+	if err != nil {
+		return nil, err
+	}
+	// A tarifa é gravada junto com a disputa, então um registro repetido da mesma fatura não cobra duas vezes
+	if err := s.disputes.Create(ctx, dispute, feePosting(dispute)); err != nil {
+		return nil, err
+	}
+
+	metrics.DisputesTotal.WithLabelValues(string(domain.DisputeOpen)).Inc()
+	metrics.ChargebackFeeAmountTotal.Add(dispute.Fee)
+	slog.InfoContext(ctx, "disputa registrada",
+		"account_id", dispute.AccountID, "invoice_id", dispute.InvoiceID, "dispute_id", dispute.ID,
+		"amount", dispute.Amount, "fee", dispute.Fee)
+	return dto.FromDispute(dispute, nil), nil
+}
+
+// feePosting monta o movimento que debita a tarifa de chargeback do saldo da conta, mesmo que ele fique negativo, e
+// a lança no razão; retorna nil para a conta isenta
+func feePosting(dispute *domain.Dispute) *domain.Posting {
+	if dispute.Fee <= 0 {
+		return nil
+	}
+	return &domain.Posting{
+		Entries:  []*domain.LedgerEntry{domain.NewLedgerEntry(dispute.AccountID, "", dispute.InvoiceID, domain.LedgerChargebackFee, -dispute.Fee)},
+		Balances: map[string]float64{dispute.AccountID: -dispute.Fee},
+	}
+}
+
+// Resolve encerra a disputa com o resultado decidido pelo emissor
+// Na disputa perdida, na mesma transação, as retenções ainda retidas da fatura são devolvidas, o restante do valor
+// é debitado do saldo da conta e a fatura aprovada passa para StatusChargedBack
+// Retorna ErrInvalidDisputeResolution para resultados desconhecidos e ErrDisputeClosed se ela já foi encerrada
+func (s *DisputeService) Resolve(ctx context.Context, id string, input dto.ResolveDisputeInput) (*dto.DisputeOutput, error) {
+	dispute, err := s.disputes.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := dispute.Resolve(input.Status); err != nil {
+		return nil, err
+	}
+	if err := s.disputes.Resolve(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	metrics.DisputesTotal.WithLabelValues(string(dispute.Status)).Inc()
+	slog.InfoContext(ctx, "disputa encerrada",
+		"account_id", dispute.AccountID, "invoice_id", dispute.InvoiceID, "dispute_id", dispute.ID,
+		"status", dispute.Status, "amount", dispute.Amount)
+	return dto.FromDispute(dispute, nil), nil
+}
+
+// ListAll retorna as disputas de todas as contas na situação informada, ou em todas com status vazio
+// Retorna ErrInvalidDisputeResolution se a situação for desconhecida
+func (s *DisputeService) ListAll(ctx context.Context, status domain.DisputeStatus) ([]*dto.DisputeOutput, error) {
+	return s.list(ctx, "", status)
+}
+
+// List retorna as disputas da conta do API Key na situação informada, ou em todas com status vazio
+func (s *DisputeService) List(ctx context.Context, apiKey string, status domain.DisputeStatus) ([]*dto.DisputeOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, account.ID, status)
+}
+
+func (s *DisputeService) list(ctx context.Context, accountID string, status domain.DisputeStatus) ([]*dto.DisputeOutput, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidDisputeResolution
+	}
+	disputes, err := s.disputes.List(ctx, accountID, status, maxDisputePageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDisputes(disputes), nil
+}
+
+// Get retorna a disputa da conta do API Key com as evidências já enviadas
+// Retorna ErrDisputeNotFound se a disputa for de outra conta
+func (s *DisputeService) Get(ctx context.Context, apiKey, id string) (*dto.DisputeOutput, error) {
+	dispute, err := s.find(ctx, apiKey, id)
+	if err != nil {
+		return nil, err
+	}
+	evidence, err := s.disputes.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromDispute(dispute, evidence), nil
+}
+
+// AddEvidence guarda o arquivo no armazenamento e o anexa à disputa aberta da conta do API Key
+// O tipo do arquivo é identificado pelo conteúdo, não pelo nome nem pelo tipo declarado no upload
+// Retorna ErrDisputeNotFound se a disputa for de outra conta, ErrDisputeClosed se ela já foi encerrada e
+// ErrInvalidDisputeEvidence se ela já tiver MaxDisputeEvidenceFiles arquivos, além dos erros de NewDisputeEvidence
+func (s *DisputeService) AddEvidence(ctx context.Context, apiKey, id string, input dto.DisputeEvidenceInput) (*dto.DisputeEvidenceOutput, error) {
+	dispute, err := s.find(ctx, apiKey, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(input.Body), ";")
+	evidence, err := domain.NewDisputeEvidence(dispute, input.FileName, contentType, int64(len(input.Body)), input.Description, requestctx.Actor(ctx))
+	if err != nil {
+		return nil, err
+	}
+	// O limite de arquivos é conferido antes do envio; dois uploads simultâneos podem, no limite, passar dele
+	existing, err := s.disputes.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= domain.MaxDisputeEvidenceFiles {
+		return nil, domain.ErrInvalidDisputeEvidence
+	}
+
+	// O arquivo é gravado antes do registro, para que a evidência nunca aponte para um objeto ausente
+	if err := s.store.Put(ctx, evidence.ObjectKey, input.Body, evidence.ContentType); err != nil {
+		return nil, fmt.Errorf("store dispute evidence: %w", err)
+	}
+	if err := s.disputes.AddEvidence(ctx, evidence); err != nil {
+		return nil, err
+	}
+
+	metrics.DisputeEvidenceUploadsTotal.WithLabelValues(evidence.ContentType).Inc()
+	slog.InfoContext(ctx, "evidência anexada à disputa",
+		"dispute_id", dispute.ID, "evidence_id", evidence.ID, "content_type", evidence.ContentType, "size", evidence.Size)
+	return dto.FromDisputeEvidence(evidence), nil
+}
+
+// Evidence abre o arquivo da evidência da disputa da conta do API Key; o chamador fecha o leitor
+// Retorna ErrDisputeNotFound se a disputa for de outra conta e ErrDisputeEvidenceNotFound se a evidência não for
+// da disputa
+func (s *DisputeService) Evidence(ctx context.Context, apiKey, id, evidenceID string) (*domain.DisputeEvidence, io.ReadCloser, error) {
+	dispute, err := s.find(ctx, apiKey, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	evidence, err := s.disputes.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range evidence {
+		if item.ID != evidenceID {
+			continue
+		}
+		body, err := s.store.Get(ctx, item.ObjectKey)
+		if err == objectstore.ErrObjectNotFound {
+			return nil, nil, domain.ErrDisputeEvidenceNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return item, body, nil
+	}
+	return nil, nil, domain.ErrDisputeEvidenceNotFound
+}
+
+// find busca a disputa da conta do API Key
+// Disputas de outras contas são tratadas como inexistentes, para não revelar os IDs
+func (s *DisputeService) find(ctx context.Context, apiKey, id string) (*domain.Dispute, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	dispute, err := s.disputes.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.AccountID != account.ID {
+		return nil, domain.ErrDisputeNotFound
+	}
+	return dispute, nil
+}
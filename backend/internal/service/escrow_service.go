@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// escrowReleaseActor identifica nas retenções e na auditoria as liberações feitas pelo prazo
+const escrowReleaseActor = "system:escrow-release"
+
+// Origens das liberações em /metrics
+const (
+	escrowSourceManual   = "manual"
+	escrowSourceSchedule = "schedule"
+)
+
+// escrowDueBatch limita as retenções vencidas liberadas a cada consulta
+const escrowDueBatch = 100
+
+// EscrowService retém em custódia o valor das faturas aprovadas que pediram custódia e o libera para o saldo das
+// contas no fim do prazo ou quando a conta dona da fatura pede, por exemplo na confirmação da entrega
+// Também libera no fim do prazo os valores que aguardam o prazo de repasse das contas, que não podem ser liberados
+// antes
+type EscrowService struct {
+	holds          domain.EscrowRepository
+	invoices       domain.InvoiceRepository
+	accountService *AccountService
+	ledger         *LedgerService
+	interval       time.Duration
+}
+
+// NewEscrowService cria o serviço de custódia; interval é a frequência com que as retenções vencidas são liberadas
+func NewEscrowService(holds domain.EscrowRepository, invoices domain.InvoiceRepository, accountService *AccountService, ledger *LedgerService, interval time.Duration) *EscrowService {
+	return &EscrowService{holds: holds, invoices: invoices, accountService: accountService, ledger: ledger, interval: interval}
+}
+
+// Held registra em /metrics as retenções dos créditos das faturas aprovadas, gravadas junto com os créditos
+func (s *EscrowService) Held(holds []*domain.EscrowHold) {
+	for _, hold := range holds {
+		if hold.Kind == domain.EscrowKindPayout {
+			metrics.PayoutPendingAmountTotal.WithLabelValues("held").Add(hold.Amount)
+			continue
+		}
+		metrics.EscrowAmountTotal.WithLabelValues("held").Add(hold.Amount)
+	}
+}
+
+// Balance retorna o valor ainda retido em custódia da conta
+func (s *EscrowService) Balance(ctx context.Context, accountID string) (float64, error) {
+	return s.holds.SumHeld(ctx, accountID, domain.EscrowKindEscrow)
+}
+
+// PendingBalance retorna o valor da conta que ainda aguarda a data de repasse
+func (s *EscrowService) PendingBalance(ctx context.Context, accountID string) (float64, error) {
+	return s.holds.SumHeld(ctx, accountID, domain.EscrowKindPayout)
+}
+
+// Release libera para o saldo das contas o valor em custódia da fatura da conta do API Key
+// Os valores que aguardam o prazo de repasse continuam retidos até a data de repasse
+// Retorna ErrUnauthorizedAccess se a fatura for de outra conta, ErrEscrowNotFound se ela não tiver valores em
+// custódia e ErrEscrowAlreadyReleased se todos já foram liberados
+func (s *EscrowService) Release(ctx context.Context, apiKey, invoiceID string) ([]*dto.EscrowHoldOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	all, err := s.holds.ListByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	var holds []*domain.EscrowHold
+	for _, hold := range all {
+		if hold.Kind == domain.EscrowKindEscrow {
+			holds = append(holds, hold)
+		}
+	}
+	if len(holds) == 0 {
+		return nil, domain.ErrEscrowNotFound
+	}
+
+	released := 0
+	for _, hold := range holds {
+		err := s.release(ctx, hold, requestctx.Actor(ctx), escrowSourceManual)
+		switch {
+		case err == nil:
+			released++
+		case errors.Is(err, domain.ErrEscrowAlreadyReleased):
+		default:
+			return nil, err
+		}
+	}
+	if released == 0 {
+		return nil, domain.ErrEscrowAlreadyReleased
+	}
+	return dto.FromEscrowHolds(holds), nil
+}
+
+// release libera a retenção e credita o valor no saldo da conta, registrando o crédito no razão, em uma única
+// transação
+// A liberação só é gravada se a retenção ainda estiver retida, para que duas liberações simultâneas não creditem
+// duas vezes; se o crédito falhar, ela continua retida
+func (s *EscrowService) release(ctx context.Context, hold *domain.EscrowHold, releasedBy, source string) error {
+	if err := hold.Release(releasedBy); err != nil {
+		return err
+	}
+	err := s.ledger.Post(ctx, &domain.Posting{
+		Entries:  []*domain.LedgerEntry{domain.NewLedgerEntry(hold.AccountID, "", hold.InvoiceID, domain.LedgerCredit, hold.Amount)},
+		Releases: []*domain.EscrowHold{hold},
+		Balances: map[string]float64{hold.AccountID: hold.Amount},
+	})
+	if err != nil {
+		return err
+	}
+
+	if hold.Kind == domain.EscrowKindPayout {
+		metrics.PayoutPendingAmountTotal.WithLabelValues("released").Add(hold.Amount)
+		slog.InfoContext(ctx, "valor repassado ao saldo na data de repasse",
+			"account_id", hold.AccountID, "invoice_id", hold.InvoiceID, "amount", hold.Amount)
+		return nil
+	}
+	metrics.EscrowAmountTotal.WithLabelValues("released").Add(hold.Amount)
+	metrics.EscrowReleasesTotal.WithLabelValues(source).Inc()
+	slog.InfoContext(ctx, "valor liberado da custódia",
+		"account_id", hold.AccountID, "invoice_id", hold.InvoiceID, "amount", hold.Amount, "released_by", releasedBy)
+	return nil
+}
+
+// Run libera as retenções vencidas a cada intervalo; bloqueia até o contexto ser cancelado
+func (s *EscrowService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.ReleaseDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ReleaseDue(ctx, now)
+		}
+	}
+}
+
+// ReleaseDue libera as retenções com prazo até now e retorna quantas foram liberadas
+// As falhas vão para o log; a retenção que não chegou a ser marcada como liberada volta na próxima verificação
+func (s *EscrowService) ReleaseDue(ctx context.Context, now time.Time) int {
+	ctx = requestctx.WithActor(ctx, escrowReleaseActor)
+	released := 0
+	for {
+		holds, err := s.holds.ListDue(ctx, now, escrowDueBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar as retenções em custódia vencidas", "error", err)
+			return released
+		}
+
+		progress := false
+		for _, hold := range holds {
+			err := s.release(ctx, hold, escrowReleaseActor, escrowSourceSchedule)
+			switch {
+			case err == nil:
+				released++
+				progress = true
+			case errors.Is(err, domain.ErrEscrowAlreadyReleased):
+				progress = true
+			default:
+				slog.ErrorContext(ctx, "erro ao liberar a retenção em custódia vencida", "escrow_id", hold.ID, "error", err)
+			}
+		}
+		// Um lote sem nenhuma retenção liberada se repetiria igual; as restantes ficam para a próxima verificação
+		if len(holds) < escrowDueBatch || !progress {
+			return released
+		}
+	}
+}
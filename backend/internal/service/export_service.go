@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/objectstore"
+)
+
+// ExportConfig define onde os links de download apontam, como são assinados e por quanto tempo valem
+type ExportConfig struct {
+	// BaseURL é o endereço público do gateway usado nos links, como https://api.exemplo.com
+	BaseURL string
+	// Secret assina os links; trocá-lo invalida todos os links emitidos
+	Secret []byte
+	// LinkTTL é a validade de cada link, limitada à expiração da exportação
+	LinkTTL time.Duration
+	// Retention é por quanto tempo a exportação pode ser baixada
+	Retention time.Duration
+}
+
+// ExportService gera relatórios e exportações no armazenamento de objetos e os entrega por links assinados e
+// temporários do próprio gateway, sem expor o caminho no armazenamento; cada download vai para a auditoria
+type ExportService struct {
+	exports        domain.ExportRepository
+	invoices       domain.InvoiceRepository
+	dataSubjects   *DataSubjectService
+	accountService *AccountService
+	store          objectstore.Store
+	config         ExportConfig
+}
+
+// NewExportService cria o serviço de exportações
+// Com store nil as exportações ficam desativadas
+func NewExportService(
+	exports domain.ExportRepository,
+	invoices domain.InvoiceRepository,
+	dataSubjects *DataSubjectService,
+	accountService *AccountService,
+	store objectstore.Store,
+	config ExportConfig,
+) *ExportService {
+	return &ExportService{
+		exports:        exports,
+		invoices:       invoices,
+		dataSubjects:   dataSubjects,
+		accountService: accountService,
+		store:          store,
+		config:         config,
+	}
+}
+
+// Enabled indica se há armazenamento configurado para as exportações
+func (s *ExportService) Enabled() bool {
+	return s.store != nil
+}
+
+// CreateInvoiceReport gera o CSV das faturas da conta que atendem aos filtros, sem paginação
+func (s *ExportService) CreateInvoiceReport(ctx context.Context, input dto.ListInvoicesInput) (*dto.ExportOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, input.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := dto.ToInvoiceFilter(input, account.ID)
+	filter.Limit = 0
+	filter.IncludeDeleted = false
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.invoices.FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := csv.NewWriter(&body)
+	writer.Write([]string{"id", "amount", "status", "description", "payment_type", "card_brand", "card_last_digits", "payer_name", "created_at", "updated_at", "coupon_code", "discount", "tax_amount"})
+	for _, invoice := range invoices {
+		couponCode, discount := invoice.Discount()
+		writer.Write([]string{
+			invoice.ID,
+			strconv.FormatFloat(invoice.Amount, 'f', 2, 64),
+			string(invoice.Status),
+			invoice.Description,
+			invoice.PaymentType,
+			invoice.CardBrand,
+			invoice.CardLastDigits,
+			invoice.PayerName,
+			invoice.CreatedAt.UTC().Format(time.RFC3339),
+			invoice.UpdatedAt.UTC().Format(time.RFC3339),
+			couponCode,
+			strconv.FormatFloat(discount, 'f', 2, 64),
+			strconv.FormatFloat(invoice.TaxAmount(), 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, account.ID, domain.ExportInvoices, "invoices.csv", "text/csv", body.Bytes())
+}
+
+// CreateDataSubjectExport gera a exportação dos dados pessoais do pagador, registrada como pedido de titular
+func (s *ExportService) CreateDataSubjectExport(ctx context.Context, apiKey string, input dto.DataSubjectInput) (*dto.ExportOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.dataSubjects.Export(ctx, apiKey, input)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, account.ID, domain.ExportDataSubject, "data-subject-export.json", "application/json", body)
+}
+
+// save grava o arquivo no armazenamento e só então registra a exportação, para que ela nunca aponte para um
+// objeto ausente
+func (s *ExportService) save(ctx context.Context, accountID string, exportType domain.ExportType, fileName, contentType string, body []byte) (*dto.ExportOutput, error) {
+	export := domain.NewExport(accountID, exportType, fileName, contentType, int64(len(body)), s.config.Retention)
+	if err := s.store.Put(ctx, export.ObjectKey, body, contentType); err != nil {
+		return nil, fmt.Errorf("store export: %w", err)
+	}
+	if err := s.exports.Save(ctx, export); err != nil {
+		return nil, err
+	}
+	return s.output(export, time.Now()), nil
+}
+
+// Link emite um novo link para uma exportação do tipo informado da conta do API Key
+// Retorna ErrExportNotFound se a exportação não for da conta ou do tipo e ErrInvalidDownloadLink se já expirou
+func (s *ExportService) Link(ctx context.Context, apiKey, id string, exportType domain.ExportType) (*dto.ExportOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.exports.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Exportações de outras contas são tratadas como inexistentes, para não revelar os IDs
+	if export.AccountID != account.ID || export.Type != exportType {
+		return nil, domain.ErrExportNotFound
+	}
+
+	now := time.Now()
+	if export.Expired(now) {
+		return nil, domain.ErrInvalidDownloadLink
+	}
+	return s.output(export, now), nil
+}
+
+// output monta a saída com um link que vale LinkTTL a partir de now, sem passar da expiração da exportação
+func (s *ExportService) output(export *domain.Export, now time.Time) *dto.ExportOutput {
+	expires := now.Add(s.config.LinkTTL)
+	if expires.After(export.ExpiresAt) {
+		expires = export.ExpiresAt
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", s.sign(export.ID, expires.Unix()))
+	link := s.config.BaseURL + "/downloads/" + url.PathEscape(export.ID) + "?" + query.Encode()
+	return dto.FromExport(export, link, time.Unix(expires.Unix(), 0))
+}
+
+// sign assina o ID da exportação e a expiração do link com HMAC-SHA256
+func (s *ExportService) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.config.Secret)
+	mac.Write([]byte(id + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Download confere o link, registra o download e abre o arquivo; o chamador fecha o leitor
+// Retorna ErrInvalidDownloadLink quando a assinatura não confere ou o link ou a exportação expiraram
+func (s *ExportService) Download(ctx context.Context, id, expires, signature string) (*domain.Export, io.ReadCloser, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidDownloadLink
+	}
+	expected := s.sign(id, expiresAt)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, nil, domain.ErrInvalidDownloadLink
+	}
+
+	now := time.Now()
+	if now.Unix() >= expiresAt {
+		return nil, nil, domain.ErrInvalidDownloadLink
+	}
+
+	export, err := s.exports.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Expired(now) {
+		return nil, nil, domain.ErrInvalidDownloadLink
+	}
+
+	body, err := s.store.Get(ctx, export.ObjectKey)
+	if err == objectstore.ErrObjectNotFound {
+		return nil, nil, domain.ErrExportNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// O download só é servido depois de registrado na auditoria
+	if err := s.exports.RecordDownload(ctx, id); err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+	return export, body, nil
+}
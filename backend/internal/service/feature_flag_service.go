@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/flags"
+)
+
+// FeatureFlagService consulta as flags em uso e a decisão delas para cada conta
+// Os serviços que têm comportamentos atrás de uma flag recebem o *flags.Flags diretamente
+type FeatureFlagService struct {
+	flags          *flags.Flags
+	accountService *AccountService
+}
+
+// NewFeatureFlagService cria o serviço de consulta das flags
+func NewFeatureFlagService(flags *flags.Flags, accountService *AccountService) *FeatureFlagService {
+	return &FeatureFlagService{flags: flags, accountService: accountService}
+}
+
+// List retorna as definições das flags em uso
+func (s *FeatureFlagService) List() []*dto.FeatureFlagOutput {
+	return dto.FromFeatureFlags(s.flags.List())
+}
+
+// ForAccount decide as flags para a conta do API Key, para que o frontend exiba apenas o que está liberado
+func (s *FeatureFlagService) ForAccount(ctx context.Context, apiKey string) (*dto.AccountFlagsOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AccountFlagsOutput{AccountID: account.ID, Flags: s.flags.Evaluate(account.ID)}, nil
+}
+
+// ForAccountID decide as flags para a conta informada (uso administrativo)
+// Retorna ErrAccountNotFound quando a conta não existe
+func (s *FeatureFlagService) ForAccountID(ctx context.Context, accountID string) (*dto.AccountFlagsOutput, error) {
+	account, err := s.accountService.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AccountFlagsOutput{AccountID: account.ID, Flags: s.flags.Evaluate(account.ID)}, nil
+}
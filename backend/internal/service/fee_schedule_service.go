@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// FeeScheduleService gerencia as tarifas das contas, definidas pelos administradores no lugar das tarifas padrão
+type FeeScheduleService struct {
+	schedules      domain.FeeScheduleRepository
+	accountService *AccountService
+	tenants        *TenantService
+	chargebackFee  float64
+}
+
+// NewFeeScheduleService cria o serviço de tarifas com a tarifa de chargeback padrão, usada pelas contas sem tarifas
+// próprias; nas contas de uma organização com tarifa própria vale a da organização
+func NewFeeScheduleService(schedules domain.FeeScheduleRepository, accountService *AccountService, tenants *TenantService, chargebackFee float64) *FeeScheduleService {
+	return &FeeScheduleService{schedules: schedules, accountService: accountService, tenants: tenants, chargebackFee: chargebackFee}
+}
+
+// schedule retorna as tarifas da conta ou, se ela não tiver tarifas próprias, as padrão, sem UpdatedAt
+func (s *FeeScheduleService) schedule(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	schedule, err := s.schedules.FindByAccountID(ctx, accountID)
+	if err == domain.ErrFeeScheduleNotFound {
+		return s.defaultSchedule(ctx, accountID)
+	}
+	return schedule, err
+}
+
+// defaultSchedule retorna as tarifas padrão da conta: as da organização dela, se tiver, ou as do gateway
+func (s *FeeScheduleService) defaultSchedule(ctx context.Context, accountID string) (*domain.FeeSchedule, error) {
+	account, err := s.accountService.FindByID(ctx, accountID, domain.IncludeDeleted())
+	if err != nil {
+		return nil, err
+	}
+	fee, err := s.tenants.ChargebackFee(ctx, account.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if fee == nil {
+		fee = &s.chargebackFee
+	}
+	return &domain.FeeSchedule{AccountID: accountID, ChargebackFee: *fee}, nil
+}
+
+// ChargebackFee retorna a tarifa de chargeback em vigor para a conta
+func (s *FeeScheduleService) ChargebackFee(ctx context.Context, accountID string) (float64, error) {
+	schedule, err := s.schedule(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	return schedule.ChargebackFee, nil
+}
+
+// Get retorna as tarifas em vigor para a conta do API Key
+func (s *FeeScheduleService) Get(ctx context.Context, apiKey string) (*dto.FeeScheduleOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedule(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromFeeSchedule(schedule), nil
+}
+
+// GetForAccount retorna as tarifas em vigor para a conta informada
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *FeeScheduleService) GetForAccount(ctx context.Context, accountID string) (*dto.FeeScheduleOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedule(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromFeeSchedule(schedule), nil
+}
+
+// Update substitui as tarifas da conta informada
+// Retorna ErrAccountNotFound se a conta não existir e ErrInvalidFeeSchedule se alguma tarifa for negativa
+func (s *FeeScheduleService) Update(ctx context.Context, accountID string, input dto.FeeScheduleInput) (*dto.FeeScheduleOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	schedule, err := domain.NewFeeSchedule(accountID, input.ChargebackFee)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Save(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "tarifas da conta atualizadas", "account_id", accountID, "chargeback_fee", schedule.ChargebackFee)
+	return dto.FromFeeSchedule(schedule), nil
+}
+
+// Reset volta a conta informada às tarifas padrão
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *FeeScheduleService) Reset(ctx context.Context, accountID string) (*dto.FeeScheduleOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Delete(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "tarifas da conta voltaram ao padrão", "account_id", accountID)
+	schedule, err := s.defaultSchedule(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromFeeSchedule(schedule), nil
+}
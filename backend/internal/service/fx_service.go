@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// FXConfig configura as faturas em outra moeda
+type FXConfig struct {
+	// SettlementCurrency é a moeda dos saldos e do valor gravado nas faturas
+	SettlementCurrency string
+	// Rates cota as demais moedas; nil só aceita faturas na moeda de liquidação
+	Rates domain.ExchangeRateProvider
+	// LockWindow é por quanto tempo a cotação da criação vale para a captura da fatura
+	LockWindow time.Duration
+}
+
+// FXService trava a cotação das faturas em outra moeda na criação, para que o valor liquidado para a conta seja
+// previsível, e cota de novo as que só são capturadas depois do vencimento da trava
+type FXService struct {
+	config FXConfig
+}
+
+// NewFXService cria o serviço de câmbio
+func NewFXService(config FXConfig) *FXService {
+	return &FXService{config: config}
+}
+
+// Foreign indica se currency é outra moeda que não a de liquidação; vazia é a de liquidação
+func (s *FXService) Foreign(currency string) bool {
+	return currency != "" && currency != s.config.SettlementCurrency
+}
+
+// Lock converte o valor da fatura pedida em currency para a moeda de liquidação pela cotação atual, travada por
+// LockWindow; na moeda de liquidação a fatura não muda
+// Retorna ErrInvalidCurrency se a moeda for inválida, não tiver cotação ou a fatura tiver itens, que só são aceitos
+// na moeda de liquidação, e ErrDependencyUnavailable se o cotador falhar
+func (s *FXService) Lock(ctx context.Context, invoice *domain.Invoice, items []*domain.InvoiceItem, currency string) error {
+	if !s.Foreign(currency) {
+		return nil
+	}
+	if !domain.ValidCurrency(currency) || s.config.Rates == nil || len(items) > 0 {
+		return domain.ErrInvalidCurrency
+	}
+
+	rate, err := s.rate(ctx, currency)
+	if err != nil {
+		return err
+	}
+	invoice.LockExchangeRate(currency, rate, time.Now().Add(s.config.LockWindow))
+	metrics.FXRateLocksTotal.WithLabelValues(currency).Inc()
+	return nil
+}
+
+// Revalidate cota de novo a fatura em outra moeda cuja trava venceu antes da captura, recalculando o valor
+// liquidado; as demais faturas não mudam
+// Retorna ErrDependencyUnavailable se o cotador falhar ou não estiver mais configurado, e a captura deve ser
+// tentada de novo
+func (s *FXService) Revalidate(ctx context.Context, invoice *domain.Invoice) error {
+	now := time.Now()
+	if !invoice.ExchangeRateExpired(now) {
+		return nil
+	}
+	if s.config.Rates == nil {
+		return domain.ErrDependencyUnavailable
+	}
+
+	rate, err := s.rate(ctx, invoice.Currency())
+	if err != nil {
+		return err
+	}
+	previous := invoice.Amount
+	invoice.RequoteExchangeRate(rate, now)
+	metrics.FXRequotesTotal.WithLabelValues(invoice.Currency()).Inc()
+	slog.InfoContext(ctx, "fatura cotada de novo na captura",
+		"invoice_id", invoice.ID, "currency", invoice.Currency(), "locked_rate", invoice.LockedExchangeRate(),
+		"rate", rate, "previous_amount", previous, "amount", invoice.Amount)
+	return nil
+}
+
+// rate consulta a cotação da moeda
+// Retorna ErrInvalidCurrency se o cotador não cotar a moeda e ErrDependencyUnavailable nas demais falhas
+func (s *FXService) rate(ctx context.Context, currency string) (float64, error) {
+	rate, err := s.config.Rates.Rate(ctx, currency, s.config.SettlementCurrency)
+	if err == domain.ErrInvalidCurrency {
+		return 0, err
+	}
+	if err == nil && rate <= 0 {
+		err = fmt.Errorf("invalid rate %v", rate)
+	}
+	if err != nil {
+		metrics.FXRateErrorsTotal.Inc()
+		slog.ErrorContext(ctx, "erro ao cotar a moeda da fatura", "currency", currency, "error", err)
+		return 0, domain.ErrDependencyUnavailable
+	}
+	return rate, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// stubRates cota todas as moedas pela mesma taxa ou falha com err
+type stubRates struct {
+	rate  float64
+	err   error
+	calls int
+}
+
+func (r *stubRates) Rate(ctx context.Context, currency, settlement string) (float64, error) {
+	r.calls++
+	return r.rate, r.err
+}
+
+func TestFXServiceLock(t *testing.T) {
+	tests := []struct {
+		name       string
+		rates      *stubRates
+		currency   string
+		items      []*domain.InvoiceItem
+		wantAmount float64
+		wantErr    error
+	}{
+		{"settlement currency", &stubRates{rate: 5}, "BRL", nil, 10, nil},
+		{"no currency", &stubRates{rate: 5}, "", nil, 10, nil},
+		{"foreign currency", &stubRates{rate: 5.4321}, "USD", nil, 54.32, nil},
+		{"lowercase code", &stubRates{rate: 5}, "usd", nil, 10, domain.ErrInvalidCurrency},
+		{"with items", &stubRates{rate: 5}, "USD", []*domain.InvoiceItem{{}}, 10, domain.ErrInvalidCurrency},
+		{"without rates", nil, "USD", nil, 10, domain.ErrInvalidCurrency},
+		{"unknown currency", &stubRates{err: domain.ErrInvalidCurrency}, "XYZ", nil, 10, domain.ErrInvalidCurrency},
+		{"provider failure", &stubRates{err: errors.New("timeout")}, "USD", nil, 10, domain.ErrDependencyUnavailable},
+		{"invalid rate", &stubRates{rate: 0}, "USD", nil, 10, domain.ErrDependencyUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := FXConfig{SettlementCurrency: "BRL", LockWindow: time.Minute}
+			if tt.rates != nil {
+				config.Rates = tt.rates
+			}
+			invoice := &domain.Invoice{Amount: 10, Metadata: map[string]string{}}
+			err := NewFXService(config).Lock(context.Background(), invoice, tt.items, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lock error = %v, want %v", err, tt.wantErr)
+			}
+			if invoice.Amount != tt.wantAmount {
+				t.Errorf("Amount = %v, want %v", invoice.Amount, tt.wantAmount)
+			}
+			if err == nil && tt.wantAmount != 10 && invoice.ExchangeRateLockedUntil().Before(time.Now()) {
+				t.Errorf("rate locked until %v, want the lock window ahead", invoice.ExchangeRateLockedUntil())
+			}
+		})
+	}
+}
+
+func TestFXServiceRevalidate(t *testing.T) {
+	newInvoice := func(until time.Time) *domain.Invoice {
+		invoice := &domain.Invoice{Amount: 10}
+		invoice.LockExchangeRate("USD", 5, until)
+		return invoice
+	}
+
+	// Dentro da trava o cotador nem é consultado
+	rates := &stubRates{rate: 6}
+	fxService := NewFXService(FXConfig{SettlementCurrency: "BRL", Rates: rates})
+	locked := newInvoice(time.Now().Add(time.Minute))
+	if err := fxService.Revalidate(context.Background(), locked); err != nil {
+		t.Fatalf("Revalidate: %v", err)
+	}
+	if locked.Amount != 50 || rates.calls != 0 {
+		t.Errorf("Amount = %v after %d quotes, want 50 without quotes", locked.Amount, rates.calls)
+	}
+
+	expired := newInvoice(time.Now().Add(-time.Minute))
+	if err := fxService.Revalidate(context.Background(), expired); err != nil {
+		t.Fatalf("Revalidate: %v", err)
+	}
+	if expired.Amount != 60 || expired.LockedExchangeRate() != 5 || expired.ExchangeRateRequotedAt().IsZero() {
+		t.Errorf("Amount = %v locked %v requoted %v, want 60 at the new rate", expired.Amount, expired.LockedExchangeRate(), expired.ExchangeRateRequotedAt())
+	}
+
+	// Sem cotação a captura é adiada e a fatura não muda
+	failing := NewFXService(FXConfig{SettlementCurrency: "BRL", Rates: &stubRates{err: errors.New("timeout")}})
+	stale := newInvoice(time.Now().Add(-time.Minute))
+	if err := failing.Revalidate(context.Background(), stale); !errors.Is(err, domain.ErrDependencyUnavailable) {
+		t.Errorf("Revalidate error = %v, want ErrDependencyUnavailable", err)
+	}
+	if stale.Amount != 50 {
+		t.Errorf("Amount = %v after a failed quote, want 50", stale.Amount)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/geoip"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// GeoDecision é o resultado da avaliação geográfica de uma requisição
+type GeoDecision struct {
+	// Country é o país do IP da requisição ou "" quando desconhecido
+	Country string
+	// Flagged indica que o país não é permitido e a política da conta manda apenas marcar
+	Flagged bool
+	// Shadow é a ação que a política em modo sombra aplicaria, vazia quando o país é permitido
+	Shadow domain.GeoAction
+}
+
+// GeoRiskService compara o país de origem das requisições com a política geográfica de cada conta
+type GeoRiskService struct {
+	policies       domain.GeoPolicyRepository
+	accountService *AccountService
+	database       *geoip.Database
+	notifier       *SecurityWebhookService
+}
+
+// NewGeoRiskService cria o serviço de risco geográfico
+// Com database nil o país não é resolvido e nenhuma requisição é bloqueada ou marcada
+// As requisições fora da política vão para o webhook de segurança da conta pelo notifier
+func NewGeoRiskService(policies domain.GeoPolicyRepository, accountService *AccountService, database *geoip.Database, notifier *SecurityWebhookService) *GeoRiskService {
+	return &GeoRiskService{policies: policies, accountService: accountService, database: database, notifier: notifier}
+}
+
+// Enabled indica se GEOIP_DATABASE foi configurada
+func (s *GeoRiskService) Enabled() bool {
+	return s.database != nil
+}
+
+// Evaluate avalia o IP da requisição contra a política da conta
+// Retorna ErrCountryNotAllowed quando o país não é permitido e a política manda bloquear
+func (s *GeoRiskService) Evaluate(ctx context.Context, accountID string) (GeoDecision, error) {
+	if s.database == nil {
+		return GeoDecision{}, nil
+	}
+
+	ip := requestctx.ClientInfo(ctx).IP
+	decision := GeoDecision{Country: s.database.Country(ip)}
+
+	policy, err := s.policies.FindByAccountID(ctx, accountID)
+	if err == domain.ErrGeoPolicyNotFound {
+		return decision, nil
+	}
+	if err != nil {
+		return decision, err
+	}
+	if policy.Allows(decision.Country) {
+		return decision, nil
+	}
+
+	// Em modo sombra a requisição segue; só o registro e a contagem mostram o que a política faria
+	if policy.Shadow {
+		metrics.GeoRiskShadowTotal.WithLabelValues(string(policy.Action)).Inc()
+		slog.InfoContext(ctx, "requisição de país não permitido pela política da conta em modo sombra",
+			"account_id", accountID,
+			"ip", ip,
+			"country", decision.Country,
+			"action", policy.Action,
+		)
+		decision.Shadow = policy.Action
+		return decision, nil
+	}
+
+	metrics.GeoRiskTotal.WithLabelValues(string(policy.Action)).Inc()
+	slog.WarnContext(ctx, "requisição de país não permitido pela política da conta",
+		"account_id", accountID,
+		"ip", ip,
+		"country", decision.Country,
+		"action", policy.Action,
+	)
+	s.notifier.Notify(ctx, SecurityEvent{
+		AccountID: accountID,
+		Type:      domain.SecurityEventCountryNotAllowed,
+		Key:       decision.Country,
+		Data: map[string]any{
+			"ip":      ip,
+			"country": decision.Country,
+			"action":  policy.Action,
+			"route":   requestctx.ClientInfo(ctx).Route,
+		},
+	})
+
+	if policy.Action == domain.GeoActionBlock {
+		return decision, domain.ErrCountryNotAllowed
+	}
+	decision.Flagged = true
+	return decision, nil
+}
+
+// GetPolicy retorna a política da conta do API Key; sem política, a padrão que permite todos os países
+func (s *GeoRiskService) GetPolicy(ctx context.Context, apiKey string) (*dto.GeoPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policies.FindByAccountID(ctx, account.ID)
+	if err == domain.ErrGeoPolicyNotFound {
+		policy = &domain.GeoPolicy{AccountID: account.ID, Action: domain.GeoActionBlock}
+	} else if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromGeoPolicy(policy)
+	return &output, nil
+}
+
+// UpdatePolicy substitui a política da conta do API Key
+// Retorna ErrInvalidGeoPolicy se a ação ou algum país for inválido
+func (s *GeoRiskService) UpdatePolicy(ctx context.Context, apiKey string, input dto.GeoPolicyInput) (*dto.GeoPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.GeoAction(input.Action)
+	if action == "" {
+		action = domain.GeoActionBlock
+	}
+
+	policy, err := domain.NewGeoPolicy(account.ID, action, input.Shadow, input.AllowedCountries, input.BlockedCountries)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policies.Save(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromGeoPolicy(policy)
+	return &output, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/buildinfo"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/database"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// startupCheck é uma condição que precisa ser satisfeita uma única vez antes de a instância receber tráfego,
+// como as migrations aplicadas ou um cache aquecido
+type startupCheck struct {
+	name  string
+	check func(ctx context.Context) error
+	// passed e latency são preenchidos quando a verificação passa; err guarda a última falha
+	passed  bool
+	latency time.Duration
+	err     error
+}
+
+// statusConsumer é um consumidor do Kafka exibido em /status
+type statusConsumer struct {
+	topic   string
+	groupID string
+	lag     func() int64
+}
+
+// statusQueue é uma fila interna exibida em /status
+type statusQueue struct {
+	name  string
+	depth func() int
+}
+
+// HealthService consolida a saúde das dependências para a verificação de prontidão e para /status
+type HealthService struct {
+	checker   *database.HealthChecker
+	mu        sync.Mutex
+	startup   []*startupCheck
+	consumers []statusConsumer
+	queues    []statusQueue
+}
+
+// NewHealthService cria um novo serviço de saúde
+// Com checker nil só as verificações de subida são consideradas
+func NewHealthService(checker *database.HealthChecker) *HealthService {
+	return &HealthService{checker: checker}
+}
+
+// AddStartupCheck inclui uma verificação de subida; até ela passar a instância fica "starting"
+// Deve ser chamado antes de RunStartupChecks
+func (s *HealthService) AddStartupCheck(name string, check func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startup = append(s.startup, &startupCheck{name: name, check: check})
+}
+
+// AddConsumer inclui em /status o consumidor do Kafka do tópico e grupo informados, com as mensagens ainda
+// não lidas retornadas por lag
+func (s *HealthService) AddConsumer(topic, groupID string, lag func() int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumers = append(s.consumers, statusConsumer{topic: topic, groupID: groupID, lag: lag})
+}
+
+// AddQueue inclui em /status a fila interna name, com os itens aguardando retornados por depth
+func (s *HealthService) AddQueue(name string, depth func() int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues = append(s.queues, statusQueue{name: name, depth: depth})
+}
+
+// RunStartupChecks executa as verificações de subida na ordem em que foram incluídas: cada uma é repetida a
+// cada interval até passar, e só então a seguinte começa, para que o aquecimento rode sobre o schema certo
+// Cada tentativa tem até interval para terminar; retorna quando todas passam ou o contexto é cancelado
+func (s *HealthService) RunStartupChecks(ctx context.Context, interval time.Duration) {
+	s.mu.Lock()
+	checks := s.startup
+	s.mu.Unlock()
+
+	started := time.Now()
+	for _, check := range checks {
+		for attempt := 1; ; attempt++ {
+			attemptCtx, cancel := context.WithTimeout(ctx, interval)
+			start := time.Now()
+			err := check.check(attemptCtx)
+			latency := time.Since(start)
+			cancel()
+
+			s.mu.Lock()
+			check.passed, check.latency, check.err = err == nil, latency, err
+			s.mu.Unlock()
+			if err == nil {
+				slog.InfoContext(ctx, "verificação de subida concluída", "check", check.name, "attempts", attempt)
+				break
+			}
+			if attempt == 1 {
+				slog.WarnContext(ctx, "aguardando verificação de subida", "check", check.name, "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+	if len(checks) > 0 {
+		slog.InfoContext(ctx, "instância pronta para receber tráfego", "duration_ms", time.Since(started).Milliseconds())
+	}
+}
+
+// Readiness indica se a aplicação pode receber tráfego
+// Fica "starting" até as verificações de subida passarem, "unavailable" se uma dependência crítica não responde
+// e "degraded" se algum pool crítico está saturado
+// Falhas das dependências opcionais deixam o status em "warning", sem tirar a instância do ar
+func (s *HealthService) Readiness(ctx context.Context) *dto.ReadinessOutput {
+	output := &dto.ReadinessOutput{Status: dto.ReadinessOK, Dependencies: []dto.DependencyReadinessOutput{}}
+
+	s.mu.Lock()
+	for _, check := range s.startup {
+		dependency := dto.DependencyReadinessOutput{
+			Name:                check.name,
+			Status:              dto.ReadinessOK,
+			Critical:            true,
+			LatencyMilliseconds: float64(check.latency.Microseconds()) / 1000,
+		}
+		if !check.passed {
+			dependency.Status = dto.ReadinessStarting
+			if check.err != nil {
+				dependency.Error = check.err.Error()
+			}
+		}
+		output.Status = worseReadiness(output.Status, dependency.Status)
+		output.Dependencies = append(output.Dependencies, dependency)
+	}
+	s.mu.Unlock()
+
+	if s.checker == nil {
+		return output
+	}
+
+	for _, status := range s.checker.Check(ctx) {
+		dependency := dto.DependencyReadinessOutput{
+			Name:                status.Name,
+			Status:              dto.ReadinessOK,
+			Critical:            !status.Optional,
+			LatencyMilliseconds: float64(status.PingLatency.Microseconds()) / 1000,
+		}
+		if status.Pool {
+			dependency.Pool = &dto.PoolReadinessOutput{
+				TotalConns:        status.TotalConns,
+				AcquiredConns:     status.AcquiredConns,
+				IdleConns:         status.IdleConns,
+				MaxConns:          status.MaxConns,
+				EmptyAcquireCount: status.EmptyAcquireCount,
+			}
+		}
+
+		switch {
+		case status.PingError != nil && status.Optional:
+			dependency.Status = dto.ReadinessWarning
+			dependency.Error = status.PingError.Error()
+		case status.PingError != nil:
+			dependency.Status = dto.ReadinessUnavailable
+			dependency.Error = status.PingError.Error()
+		case status.Saturated:
+			dependency.Status = dto.ReadinessDegraded
+		}
+
+		// Uma dependência opcional saturada não muda o status geral
+		if !status.Optional || dependency.Status == dto.ReadinessWarning {
+			output.Status = worseReadiness(output.Status, dependency.Status)
+		}
+		output.Dependencies = append(output.Dependencies, dependency)
+	}
+	return output
+}
+
+// Status reúne a identificação do build, o tempo no ar, a prontidão com as dependências, o lag dos
+// consumidores do Kafka e a profundidade das filas internas
+func (s *HealthService) Status(ctx context.Context) *dto.StatusOutput {
+	readiness := s.Readiness(ctx)
+	output := &dto.StatusOutput{
+		Status:        readiness.Status,
+		Version:       buildinfo.Version,
+		Commit:        buildinfo.Revision(),
+		BuildDate:     buildinfo.BuildDate,
+		GoVersion:     runtime.Version(),
+		StartedAt:     buildinfo.StartedAt,
+		UptimeSeconds: int64(buildinfo.Uptime().Seconds()),
+		Dependencies:  readiness.Dependencies,
+		Consumers:     []dto.ConsumerStatusOutput{},
+		Queues:        []dto.QueueStatusOutput{},
+	}
+
+	s.mu.Lock()
+	consumers, queues := s.consumers, s.queues
+	s.mu.Unlock()
+
+	for _, consumer := range consumers {
+		output.Consumers = append(output.Consumers, dto.ConsumerStatusOutput{
+			Topic:   consumer.topic,
+			GroupID: consumer.groupID,
+			Lag:     consumer.lag(),
+		})
+	}
+	for _, queue := range queues {
+		output.Queues = append(output.Queues, dto.QueueStatusOutput{Name: queue.name, Depth: queue.depth()})
+	}
+	return output
+}
+
+// worseReadiness retorna o pior entre dois status de prontidão
+func worseReadiness(a, b string) string {
+	rank := map[string]int{dto.ReadinessOK: 0, dto.ReadinessWarning: 1, dto.ReadinessDegraded: 2, dto.ReadinessStarting: 3, dto.ReadinessUnavailable: 4}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -2,47 +2,400 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain/events"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/velocity"
 )
 
 type InvoiceService struct {
 	invoiceRepository domain.InvoiceRepository
 	accountService    AccountService
 	kafkaProducer     KafkaProducerInterface
+	cards             *carddata.Vault
+	geo               *GeoRiskService
+	limits            domain.SpendingLimits
+	velocity          *velocity.Checker
+	blocklist         *BlocklistService
+	splits            *SplitService
+	coupons           *CouponService
+	taxes             *TaxService
+	items             domain.InvoiceItemRepository
+	customers         *CustomerService
+	tenants           *TenantService
+	lateFees          domain.LateFeePolicy
+	fx                *FXService
+	amounts           *AmountLimitService
 }
 
+// NewInvoiceService cria o serviço de faturas
+// Os cartões recebidos são validados e guardados em cards; as faturas recebem apenas o token
+// geo confere o país de origem contra a política da conta, recusando ou marcando as faturas
+// limits são os tetos de gasto diário e mensal aplicados a todas as contas e velocity as regras de frequência
+// por cartão, pagador e conta; velocity nil desativa as regras
+// blocklist recusa as faturas com cartão, e-mail, documento ou IP nas listas de bloqueio
+// splits divide as faturas entre contas recebedoras e credita o saldo de todas as faturas aprovadas
+// coupons aplica o cupom de desconto informado na criação da fatura e taxes soma os impostos ao valor já descontado
+// items guarda os itens das faturas compostas e customers os clientes que pagam com cartões guardados
+// tenants troca os tetos de gasto pelos da organização da conta, quando ela define os próprios
+// lateFees são a multa e os juros somados às faturas com vencimento pagas depois dele
+// fx converte as faturas pedidas em outra moeda e cota de novo as capturadas depois do vencimento da trava
+// amounts recusa as faturas fora da faixa de valor da conta e do meio de pagamento
 func NewInvoiceService(
 	invoiceRepository domain.InvoiceRepository,
 	accountService AccountService,
 	kafkaProducer KafkaProducerInterface,
+	cards *carddata.Vault,
+	geo *GeoRiskService,
+	limits domain.SpendingLimits,
+	velocity *velocity.Checker,
+	blocklist *BlocklistService,
+	splits *SplitService,
+	coupons *CouponService,
+	taxes *TaxService,
+	items domain.InvoiceItemRepository,
+	customers *CustomerService,
+	tenants *TenantService,
+	lateFees domain.LateFeePolicy,
+	fx *FXService,
+	amounts *AmountLimitService,
 ) *InvoiceService {
 	return &InvoiceService{
 		invoiceRepository: invoiceRepository,
 		accountService:    accountService,
 		kafkaProducer:     kafkaProducer,
+		cards:             cards,
+		geo:               geo,
+		limits:            limits,
+		velocity:          velocity,
+		blocklist:         blocklist,
+		splits:            splits,
+		coupons:           coupons,
+		taxes:             taxes,
+		items:             items,
+		customers:         customers,
+		tenants:           tenants,
+		lateFees:          lateFees,
+		fx:                fx,
+		amounts:           amounts,
 	}
 }
 
-func (s *InvoiceService) Create(input dto.CreateInvoiceInput) (*dto.InvoiceOutput, error) {
-	accountOutput, err := s.accountService.FindByAPIKey(input.APIKey)
+// checkVelocity conta as transações nas regras de frequência, barrando testes de cartão
+// Retorna *domain.VelocityLimitError da regra estourada; se o contador falhar (Redis fora do ar), as faturas
+// seguem sem a verificação para não derrubar a criação junto
+func (s *InvoiceService) checkVelocity(ctx context.Context, transactions []velocity.Transaction) error {
+	err := s.velocity.Check(ctx, transactions)
+	var limitErr *domain.VelocityLimitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &limitErr):
+		metrics.VelocityRejectionsTotal.WithLabelValues(limitErr.Dimension).Inc()
+		slog.WarnContext(ctx, "transações recusadas pela regra de frequência",
+			"account_id", transactions[0].AccountID, "dimension", limitErr.Dimension, "limit", limitErr.Limit, "window", limitErr.Window)
+		return err
+	default:
+		metrics.VelocityErrorsTotal.Inc()
+		slog.WarnContext(ctx, "falha ao consultar as regras de frequência", "error", err)
+		return nil
+	}
+}
+
+// blocklistSubject reúne os valores da fatura conferidos contra as listas de bloqueio
+// Sem e-mail ou documento na entrada, valem os do cliente da fatura
+func blocklistSubject(ctx context.Context, input dto.CreateInvoiceInput, card *carddata.Card, customer *domain.Customer) BlocklistSubject {
+	subject := BlocklistSubject{
+		Card:     card.Number(),
+		Email:    input.PayerEmail,
+		Document: input.PayerDocument,
+		IP:       requestctx.ClientInfo(ctx).IP,
+	}
+	if customer != nil {
+		if subject.Email == "" {
+			subject.Email = customer.Email
+		}
+		if subject.Document == "" {
+			subject.Document = customer.Document
+		}
+	}
+	return subject
+}
+
+// velocityTransaction identifica a fatura nas regras de frequência
+// O pagador é o cliente da fatura ou, sem cliente, o nome do portador do cartão
+func velocityTransaction(invoice *domain.Invoice, card *carddata.Card) velocity.Transaction {
+	payer := invoice.PayerName
+	if customerID := invoice.CustomerID(); customerID != "" {
+		payer = "customer:" + customerID
+	}
+	return velocity.Transaction{AccountID: invoice.AccountID, Card: card.Number(), Payer: payer}
+}
+
+// checkSpending confere se amount cabe nos tetos de gasto da conta somado ao que ela já faturou no dia e no mês
+// Valem os tetos da organização da conta, se ela definir os próprios, ou os padrão
+// Retorna *domain.TransactionLimitError com o saldo restante do período estourado
+// Requisições simultâneas da mesma conta podem, juntas, passar um pouco do teto
+func (s *InvoiceService) checkSpending(ctx context.Context, accountID, tenantID string, amount float64) error {
+	limits := s.limits
+	tenantLimits, err := s.tenants.SpendingLimits(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tenantLimits != nil {
+		limits = *tenantLimits
+	}
+	if !limits.Enabled() {
+		return nil
+	}
+
+	now := time.Now()
+	dayStart, monthStart := domain.SpendingPeriods(now)
+	totals, err := s.invoiceRepository.SumSpending(ctx, accountID, dayStart, monthStart)
+	if err != nil {
+		return err
+	}
+
+	err = limits.Check(totals, amount, now)
+	var limitErr *domain.TransactionLimitError
+	if errors.As(err, &limitErr) {
+		metrics.SpendingLimitRejectionsTotal.WithLabelValues(limitErr.Period).Inc()
+		slog.InfoContext(ctx, "fatura recusada pelo teto de gasto da conta",
+			"account_id", accountID, "period", limitErr.Period, "amount", amount, "remaining", limitErr.Remaining)
+	}
+	return err
+}
+
+// Metadados gravados nas faturas criadas de países não permitidos quando a política da conta manda marcar
+// Com a política em modo sombra, geo_risk_shadow guarda a ação que seria aplicada
+const (
+	geoRiskMetadataKey       = "geo_risk"
+	geoRiskShadowMetadataKey = "geo_risk_shadow"
+	geoCountryMetadataKey    = "geo_country"
+	geoRiskCountry           = "country_not_allowed"
+)
+
+// flagInvoice marca a fatura para análise quando a avaliação geográfica pediu ou a política em modo sombra
+// teria barrado ou marcado a requisição
+func flagInvoice(invoice *domain.Invoice, decision GeoDecision) {
+	if !decision.Flagged && decision.Shadow == "" {
+		return
+	}
+	if invoice.Metadata == nil {
+		invoice.Metadata = map[string]string{}
+	}
+
+	country := decision.Country
+	if country == "" {
+		country = "unknown"
+	}
+	if decision.Flagged {
+		invoice.Metadata[geoRiskMetadataKey] = geoRiskCountry
+	} else {
+		invoice.Metadata[geoRiskShadowMetadataKey] = string(decision.Shadow)
+	}
+	invoice.Metadata[geoCountryMetadataKey] = country
+}
+
+// newCard valida o cartão da entrada
+func newCard(input dto.CreateInvoiceInput) (*carddata.Card, error) {
+	return carddata.NewCard(input.CardNumber, input.CVV, input.ExpiryMonth, input.ExpiryYear, input.CardholderName)
+}
+
+// payer resolve o cliente e o cartão da fatura: o cartão guardado do cliente em CardToken ou o cartão da entrada
+// Retorna ErrInvalidCustomer se CardToken ou SaveCard vierem sem cliente ou juntos, ErrCustomerNotFound e
+// ErrCardNotFound se o cliente ou o cartão guardado não forem da conta e, com SaveCard, os erros de
+// CustomerService.CheckCardLimit
+func (s *InvoiceService) payer(ctx context.Context, accountID string, input dto.CreateInvoiceInput) (*domain.Customer, *carddata.Card, error) {
+	if input.CustomerID == "" {
+		if input.CardToken != "" || input.SaveCard {
+			return nil, nil, domain.ErrInvalidCustomer
+		}
+		card, err := newCard(input)
+		return nil, card, err
+	}
+	if input.CardToken != "" && input.SaveCard {
+		return nil, nil, domain.ErrInvalidCustomer
+	}
+
+	customer, err := s.customers.Find(ctx, accountID, input.CustomerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if input.CardToken != "" {
+		card, err := s.customers.Card(ctx, customer, input.CardToken)
+		return customer, card, err
+	}
+	card, err := newCard(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if input.SaveCard {
+		if err := s.customers.CheckCardLimit(ctx, customer); err != nil {
+			return nil, nil, err
+		}
+	}
+	return customer, card, nil
+}
+
+// process decide a fatura com o SandboxProcessor nas contas em modo de teste e com o RandomProcessor nas demais
+// Retorna ErrProcessorTimeout quando o adquirente não responde e ErrTestCardNotAllowed para um cartão de teste
+// fora do modo de teste, sem alterar a fatura
+func process(ctx context.Context, invoice *domain.Invoice, card *carddata.Card, testMode bool) error {
+	if err := card.CheckMode(testMode); err != nil {
+		return err
+	}
+	if !testMode {
+		return invoice.Process(domain.RandomProcessor{})
+	}
+
+	err := invoice.Process(domain.SandboxProcessor{TestCard: card.TestDecline()})
+	switch {
+	case errors.Is(err, domain.ErrProcessorTimeout):
+		metrics.SandboxAuthorizationsTotal.WithLabelValues("timeout").Inc()
+		slog.InfoContext(ctx, "fatura de teste recusada pelo adquirente simulado fora do ar", "amount", invoice.Amount)
+	case err == nil:
+		metrics.SandboxAuthorizationsTotal.WithLabelValues(string(invoice.Status)).Inc()
+	}
+	return err
+}
+
+// Origens da decisão das faturas em /metrics
+const (
+	decisionSourceProcessor = "processor"
+	decisionSourceAntifraud = "antifraud"
+	decisionSourceReview    = "review"
+	decisionSourcePayment   = "payment"
+)
+
+// observeCreated registra em /metrics a fatura gravada e, se ela já saiu aprovada ou recusada, a decisão
+func observeCreated(invoice *domain.Invoice) {
+	metrics.InvoicesCreatedTotal.WithLabelValues(string(invoice.Status), invoice.CardBrand).Inc()
+	if invoice.Status != domain.StatusPending {
+		observeDecision(invoice, decisionSourceProcessor)
+	}
+}
+
+// observeDecision registra em /metrics a fatura que chegou ao status final
+// As faturas que uma política geográfica em modo sombra barraria ou marcaria também entram na contagem do modo sombra
+func observeDecision(invoice *domain.Invoice, source string) {
+	metrics.InvoiceDecisionsTotal.WithLabelValues(string(invoice.Status), source).Inc()
+	metrics.InvoiceAmount.WithLabelValues(string(invoice.Status)).Observe(invoice.Amount)
+	if action := invoice.Metadata[geoRiskShadowMetadataKey]; action != "" {
+		metrics.GeoRiskShadowDecisionsTotal.WithLabelValues(action, string(invoice.Status)).Inc()
+	}
+}
+
+func (s *InvoiceService) Create(ctx context.Context, input dto.CreateInvoiceInput) (*dto.InvoiceOutput, error) {
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, input.APIKey)
 	if err != nil {
 		return nil, err
 	}
 
-	invoice, err := dto.ToInvoice(input, accountOutput.ID)
+	decision, err := s.geo.Evaluate(ctx, accountOutput.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := invoice.Process(); err != nil {
+	customer, card, err := s.payer(ctx, accountOutput.ID, input)
+	if err != nil {
 		return nil, err
 	}
 
-	// Se o status for pending, significa que é uma transação de alto valor
-	if invoice.Status == domain.StatusPending {
+	invoice, items, err := dto.ToInvoice(input, accountOutput.ID, card.PaymentCard())
+	if err != nil {
+		return nil, err
+	}
+	if customer != nil {
+		invoice.SetCustomer(customer)
+	}
+	flagInvoice(invoice, decision)
+	ctx = logging.With(ctx, "invoice_id", invoice.ID)
+
+	// A conversão vem antes de tudo, e o desconto, os impostos, as divisões e os limites ficam na moeda de liquidação
+	if err := s.fx.Lock(ctx, invoice, items, input.Currency); err != nil {
+		return nil, err
+	}
+	// O desconto e os impostos vêm antes das divisões e dos limites, que valem para o valor cobrado
+	redemption, err := s.coupons.Apply(ctx, invoice, input.CouponCode)
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := s.taxes.Apply(ctx, invoice, items)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.amounts.Check(ctx, accountOutput.ID, invoice.PaymentType, invoice.Amount); err != nil {
+		return nil, err
+	}
+
+	splits, err := s.splits.Plan(ctx, accountOutput, invoice, input.Splits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.blocklist.Screen(ctx, accountOutput.ID, []BlocklistSubject{blocklistSubject(ctx, input, card, customer)}); err != nil {
+		return nil, err
+	}
+	if err := s.checkVelocity(ctx, []velocity.Transaction{velocityTransaction(invoice, card)}); err != nil {
+		return nil, err
+	}
+	if err := s.checkSpending(ctx, accountOutput.ID, accountOutput.TenantID, invoice.Amount); err != nil {
+		return nil, err
+	}
+	// As faturas com vencimento esperam a confirmação do pagamento, sem passar pelo processamento nem pelo antifraude
+	// O processamento vem antes de gravar qualquer coisa para que o adquirente fora do ar não deixe registros soltos
+	if !invoice.AwaitingPayment() {
+		if err := process(ctx, invoice, card, accountOutput.TestMode); err != nil {
+			return nil, err
+		}
+	}
+
+	// O cartão só é guardado depois que a fatura é válida; o cartão guardado do cliente mantém o token
+	if input.CardToken != "" {
+		invoice.CardToken = input.CardToken
+	} else if invoice.CardToken, err = s.cards.Tokenize(ctx, accountOutput.ID, card, accountOutput.TestMode); err != nil {
+		return nil, err
+	}
+	if err := s.items.SaveBatch(ctx, items); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.Save(ctx, taxes); err != nil {
+		return nil, err
+	}
+
+	// A fatura recusada não consome o cupom nem guarda o cartão no cliente; a pendente consome e guarda mesmo se o
+	// antifraude recusar depois
+	if invoice.Status != domain.StatusRejected {
+		if err := s.coupons.Redeem(ctx, redemption); err != nil {
+			return nil, err
+		}
+		if input.SaveCard {
+			paymentCard := card.PaymentCard()
+			paymentCard.Token = invoice.CardToken
+			if err := s.customers.SaveCard(ctx, customer, paymentCard); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// A fatura, as partes e, para transações aprovadas, o crédito da conta ou das recebedoras são gravados juntos,
+	// antes do evento, para que o resultado do antifraude já encontre a fatura e as partes
+	if err := s.save(ctx, invoice, splits); err != nil {
+		return nil, err
+	}
+	observeCreated(invoice)
+
+	// Se o status for pending sem vencimento, significa que é uma transação de alto valor
+	if invoice.Status == domain.StatusPending && !invoice.AwaitingPayment() {
 		// Criar e publicar evento de transação pendente
 		pendingTransaction := events.NewPendingTransaction(
 			invoice.AccountID,
@@ -50,33 +403,274 @@ func (s *InvoiceService) Create(input dto.CreateInvoiceInput) (*dto.InvoiceOutpu
 			invoice.Amount,
 		)
 
-		if err := s.kafkaProducer.SendingPendingTransaction(context.Background(), *pendingTransaction); err != nil {
+		if err := s.kafkaProducer.SendingPendingTransaction(ctx, *pendingTransaction); err != nil {
+			return nil, err
+		}
+	}
+
+	output := dto.FromInvoice(invoice)
+	output.Splits = dto.FromInvoiceSplits(splits)
+	output.Items = dto.FromInvoiceItems(items)
+	output.Taxes = dto.FromInvoiceTaxes(taxes)
+	return output, nil
+}
+
+// ChargeStoredCard cobra amount da conta no cartão guardado no cofre com o token, sem a presença do pagador, como
+// nas cobranças recorrentes; metadata identifica a origem da cobrança na fatura
+// A cobrança passa pela faixa de valor, pelas listas de bloqueio e pelos tetos de gasto; a política geográfica e as
+// regras de frequência ficam de fora porque ela não parte de uma requisição do pagador
+// A fatura recusada também é gravada e retornada sem erro; retorna ErrCardNotFound se o token não for da conta ou o
+// cofre não guardar o número
+func (s *InvoiceService) ChargeStoredCard(ctx context.Context, accountID, cardToken string, amount float64, description string, metadata map[string]string) (*domain.Invoice, error) {
+	card, err := s.cards.Detokenize(ctx, accountID, cardToken)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentCard := card.PaymentCard()
+	paymentCard.Token = cardToken
+	invoice, err := domain.NewInvoice(accountID, amount, description, "credit_card", paymentCard)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range metadata {
+		invoice.Metadata[key] = value
+	}
+	ctx = logging.With(ctx, "invoice_id", invoice.ID)
+
+	taxes, err := s.taxes.Apply(ctx, invoice, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.amounts.Check(ctx, accountID, invoice.PaymentType, invoice.Amount); err != nil {
+		return nil, err
+	}
+	if err := s.blocklist.Screen(ctx, accountID, []BlocklistSubject{{Card: card.Number()}}); err != nil {
+		return nil, err
+	}
+	account, err := s.accountService.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSpending(ctx, accountID, account.TenantID, invoice.Amount); err != nil {
+		return nil, err
+	}
+	if err := process(ctx, invoice, card, account.TestMode); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.Save(ctx, taxes); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, invoice, nil); err != nil {
+		return nil, err
+	}
+	observeCreated(invoice)
+
+	if invoice.Status == domain.StatusPending {
+		pendingTransaction := events.NewPendingTransaction(invoice.AccountID, invoice.ID, invoice.Amount)
+		if err := s.kafkaProducer.SendingPendingTransaction(ctx, *pendingTransaction); err != nil {
 			return nil, err
 		}
 	}
+	return invoice, nil
+}
+
+// save grava a fatura nova com as partes e, se ela já foi aprovada, com o crédito da conta ou das recebedoras, tudo
+// na mesma transação
+func (s *InvoiceService) save(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit) error {
+	posting, err := s.creditPosting(ctx, invoice, splits)
+	if err != nil {
+		return err
+	}
+	if err := s.invoiceRepository.Save(ctx, invoice, splits, posting); err != nil {
+		return err
+	}
+	s.posted(posting, splits)
+	return nil
+}
+
+// creditPosting monta o crédito da fatura aprovada para a conta ou as recebedoras, ou retorna nil se ela não foi
+// aprovada
+func (s *InvoiceService) creditPosting(ctx context.Context, invoice *domain.Invoice, splits []*domain.InvoiceSplit) (*domain.Posting, error) {
+	if invoice.Status != domain.StatusApproved {
+		return nil, nil
+	}
+	return s.splits.Posting(ctx, []*domain.Invoice{invoice}, [][]*domain.InvoiceSplit{splits})
+}
+
+// posted conta nas métricas o crédito de creditPosting depois de gravado
+func (s *InvoiceService) posted(posting *domain.Posting, splits []*domain.InvoiceSplit) {
+	if posting != nil {
+		s.splits.Posted(posting, [][]*domain.InvoiceSplit{splits})
+	}
+}
+
+// maxInvoicePageSize limita o tamanho de uma página da listagem de faturas
+const maxInvoicePageSize = 1000
+
+// MaxInvoiceBatchSize é a quantidade máxima de faturas aceitas em CreateBatch
+const MaxInvoiceBatchSize = 5000
+
+// CreateBatch cria um lote de faturas da conta da API Key gravando todas com SaveBatch
+// O lote é rejeitado por inteiro se alguma fatura for inválida ou bloqueada, se passar de uma regra de frequência ou se o total
+// ultrapassar um teto de gasto da conta; o saldo de cada conta, dona ou recebedora, é atualizado uma vez com o total aprovado
+// Retorna ErrInvalidBatchSize para lotes vazios ou maiores que MaxInvoiceBatchSize, ErrInvalidCoupon se alguma
+// fatura informar cupom, ErrInvalidCustomer se alguma informar cliente e ErrInvalidCurrency se alguma for em outra
+// moeda, que só são aceitos na criação individual
+func (s *InvoiceService) CreateBatch(ctx context.Context, input dto.CreateInvoiceBatchInput) ([]*dto.InvoiceOutput, error) {
+	if len(input.Invoices) == 0 || len(input.Invoices) > MaxInvoiceBatchSize {
+		return nil, domain.ErrInvalidBatchSize
+	}
+
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, input.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.geo.Evaluate(ctx, accountOutput.ID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Para transações aprovadas, atualizar o saldo
-	if invoice.Status == domain.StatusApproved {
-		_, err = s.accountService.UpdateBalance(input.APIKey, invoice.Amount)
+	invoices := make([]*domain.Invoice, len(input.Invoices))
+	cards := make([]*carddata.Card, len(input.Invoices))
+	splits := make([][]*domain.InvoiceSplit, len(input.Invoices))
+	var allSplits []*domain.InvoiceSplit
+	var allItems []*domain.InvoiceItem
+	var allTaxes []*domain.InvoiceTax
+	items := make([][]*domain.InvoiceItem, len(input.Invoices))
+	taxes := make([][]*domain.InvoiceTax, len(input.Invoices))
+	var batchAmount float64
+	for i, invoiceInput := range input.Invoices {
+		if invoiceInput.CouponCode != "" {
+			return nil, domain.ErrInvalidCoupon
+		}
+		if s.fx.Foreign(invoiceInput.Currency) {
+			return nil, domain.ErrInvalidCurrency
+		}
+		if invoiceInput.CustomerID != "" || invoiceInput.CardToken != "" || invoiceInput.SaveCard {
+			return nil, domain.ErrInvalidCustomer
+		}
+		card, err := newCard(invoiceInput)
+		if err != nil {
+			return nil, err
+		}
+		invoice, invoiceItems, err := dto.ToInvoice(invoiceInput, accountOutput.ID, card.PaymentCard())
 		if err != nil {
 			return nil, err
 		}
+		flagInvoice(invoice, decision)
+		if taxes[i], err = s.taxes.Apply(ctx, invoice, invoiceItems); err != nil {
+			return nil, err
+		}
+		if err := s.amounts.Check(ctx, accountOutput.ID, invoice.PaymentType, invoice.Amount); err != nil {
+			return nil, err
+		}
+		items[i] = invoiceItems
+		allItems = append(allItems, invoiceItems...)
+		allTaxes = append(allTaxes, taxes[i]...)
+		if splits[i], err = s.splits.Plan(ctx, accountOutput, invoice, invoiceInput.Splits); err != nil {
+			return nil, err
+		}
+		allSplits = append(allSplits, splits[i]...)
+		batchAmount += invoice.Amount
+		invoices[i], cards[i] = invoice, card
 	}
 
-	if err := s.invoiceRepository.Save(invoice); err != nil {
+	subjects := make([]BlocklistSubject, len(invoices))
+	transactions := make([]velocity.Transaction, len(invoices))
+	for i, invoice := range invoices {
+		subjects[i] = blocklistSubject(ctx, input.Invoices[i], cards[i], nil)
+		transactions[i] = velocityTransaction(invoice, cards[i])
+	}
+	if err := s.blocklist.Screen(ctx, accountOutput.ID, subjects); err != nil {
+		return nil, err
+	}
+	if err := s.checkVelocity(ctx, transactions); err != nil {
 		return nil, err
 	}
+	// O teto vale para o valor pedido, antes da decisão de cada fatura
+	if err := s.checkSpending(ctx, accountOutput.ID, accountOutput.TenantID, batchAmount); err != nil {
+		return nil, err
+	}
+	for i, invoice := range invoices {
+		if invoice.AwaitingPayment() {
+			continue
+		}
+		if err := process(ctx, invoice, cards[i], accountOutput.TestMode); err != nil {
+			return nil, err
+		}
+	}
 
-	return dto.FromInvoice(invoice), nil
+	tokens, err := s.cards.TokenizeBatch(ctx, accountOutput.ID, cards, accountOutput.TestMode)
+	if err != nil {
+		return nil, err
+	}
+	for i, invoice := range invoices {
+		invoice.CardToken = tokens[i]
+	}
+
+	if err := s.splits.Save(ctx, allSplits); err != nil {
+		return nil, err
+	}
+	if err := s.items.SaveBatch(ctx, allItems); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.Save(ctx, allTaxes); err != nil {
+		return nil, err
+	}
+	if err := s.invoiceRepository.SaveBatch(ctx, invoices); err != nil {
+		return nil, err
+	}
+	for _, invoice := range invoices {
+		observeCreated(invoice)
+	}
+
+	var approved []*domain.Invoice
+	var approvedSplits [][]*domain.InvoiceSplit
+	for i, invoice := range invoices {
+		switch invoice.Status {
+		case domain.StatusApproved:
+			approved = append(approved, invoice)
+			approvedSplits = append(approvedSplits, splits[i])
+		case domain.StatusPending:
+			if invoice.AwaitingPayment() {
+				continue
+			}
+			pendingTransaction := events.NewPendingTransaction(
+				invoice.AccountID,
+				invoice.ID,
+				invoice.Amount,
+			)
+
+			if err := s.kafkaProducer.SendingPendingTransaction(ctx, *pendingTransaction); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.splits.Credit(ctx, approved, approvedSplits); err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.InvoiceOutput, len(invoices))
+	for i, invoice := range invoices {
+		output[i] = dto.FromInvoice(invoice)
+		output[i].Splits = dto.FromInvoiceSplits(splits[i])
+		output[i].Items = dto.FromInvoiceItems(items[i])
+		output[i].Taxes = dto.FromInvoiceTaxes(taxes[i])
+	}
+	return output, nil
 }
 
-func (s *InvoiceService) GetByID(id, apiKey string) (*dto.InvoiceOutput, error) {
-	invoice, err := s.invoiceRepository.FindByID(id)
+func (s *InvoiceService) GetByID(ctx context.Context, id, apiKey string) (*dto.InvoiceOutput, error) {
+	invoice, err := s.invoiceRepository.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, apiKey)
 	if err != nil {
 		return nil, err
 	}
@@ -85,11 +679,27 @@ func (s *InvoiceService) GetByID(id, apiKey string) (*dto.InvoiceOutput, error)
 		return nil, domain.ErrUnauthorizedAccess
 	}
 
-	return dto.FromInvoice(invoice), nil
+	splits, err := s.splits.Find(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.items.FindByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := s.taxes.Find(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	output := dto.FromInvoice(invoice)
+	output.Splits = dto.FromInvoiceSplits(splits)
+	output.Items = dto.FromInvoiceItems(items)
+	output.Taxes = dto.FromInvoiceTaxes(taxes)
+	return output, nil
 }
 
-func (s *InvoiceService) ListByAccount(accountID string) ([]*dto.InvoiceOutput, error) {
-	invoices, err := s.invoiceRepository.FindByAccountID(accountID)
+func (s *InvoiceService) ListByAccount(ctx context.Context, accountID string) ([]*dto.InvoiceOutput, error) {
+	invoices, err := s.invoiceRepository.FindByAccountID(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -102,40 +712,213 @@ func (s *InvoiceService) ListByAccount(accountID string) ([]*dto.InvoiceOutput,
 }
 
 // ListByAccountAPIKey lista as faturas de uma conta através de uma API Key
-func (s *InvoiceService) ListByAccountAPIKey(apiKey string) ([]*dto.InvoiceOutput, error) {
-	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+func (s *InvoiceService) ListByAccountAPIKey(ctx context.Context, apiKey string) ([]*dto.InvoiceOutput, error) {
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, apiKey)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.ListByAccount(accountOutput.ID)
+	return s.ListByAccount(ctx, accountOutput.ID)
 }
 
-// ProcessTransactionResult processa o resultado de uma transação após análise de fraude
-func (s *InvoiceService) ProcessTransactionResult(invoiceID string, status domain.Status) error {
-	invoice, err := s.invoiceRepository.FindByID(invoiceID)
+// Search lista as faturas da conta da API Key aplicando os filtros informados
+// Retorna ErrInvalidStatus, ErrInvalidAmount ou ErrInvalidDateRange para filtros inconsistentes
+func (s *InvoiceService) Search(ctx context.Context, input dto.ListInvoicesInput) (*dto.InvoicePage, error) {
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, input.APIKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := invoice.UpdateStatus(status); err != nil {
-		return err
+	// Excluídas só aparecem nas consultas administrativas
+	input.IncludeDeleted = false
+	return s.SearchByAccountID(ctx, accountOutput.ID, input)
+}
+
+// SearchByAccountID lista as faturas de uma conta aplicando os filtros informados (uso administrativo)
+// Com Limit informado a listagem é paginada por keyset e NextCursor aponta para a próxima página
+// Buscas textuais (Search) são ordenadas por relevância e retornam apenas a primeira página
+// Retorna ErrInvalidCursor se o cursor estiver malformado ou for combinado com Search
+func (s *InvoiceService) SearchByAccountID(ctx context.Context, accountID string, input dto.ListInvoicesInput) (*dto.InvoicePage, error) {
+	filter := dto.ToInvoiceFilter(input, accountID)
+	if err := filter.Validate(); err != nil {
+		return nil, err
 	}
 
-	if err := s.invoiceRepository.UpdateStatus(invoice); err != nil {
+	if input.Search != "" && input.Cursor != "" {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	after, err := dto.DecodeCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter.After = after
+
+	// Um item extra indica se existe próxima página
+	limit := min(input.Limit, maxInvoicePageSize)
+	if limit > 0 {
+		filter.Limit = limit + 1
+	}
+
+	invoices, err := s.invoiceRepository.FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.InvoicePage{}
+	if limit > 0 && len(invoices) > limit {
+		invoices = invoices[:limit]
+		if input.Search == "" {
+			last := invoices[limit-1]
+			page.NextCursor = dto.EncodeCursor(domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		}
+	}
+
+	page.Invoices = make([]*dto.InvoiceOutput, len(invoices))
+	for i, invoice := range invoices {
+		page.Invoices[i] = dto.FromInvoice(invoice)
+	}
+	return page, nil
+}
+
+// Stats resume as faturas da conta do API Key criadas no período que termina agora, como "30d" (padrão) ou "24h"
+// Os totais vêm de uma agregação no banco, sem carregar as faturas; retorna ErrInvalidStatsPeriod para
+// períodos inválidos
+func (s *InvoiceService) Stats(ctx context.Context, apiKey, period string) (*dto.AccountStatsOutput, error) {
+	if period == "" {
+		period = domain.DefaultStatsPeriod
+	}
+	duration, err := domain.ParseStatsPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	accountOutput, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	from := to.Add(-duration)
+	totals, err := s.invoiceRepository.SummarizeByStatus(ctx, accountOutput.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := s.coupons.SumDiscounts(ctx, accountOutput.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.NewAccountStatsOutput(period, from, to, totals)
+	output.Discounts = discounts
+	return output, nil
+}
+
+// FindByID busca uma fatura pelo ID sem verificar a conta dona (uso administrativo)
+// Use domain.IncludeDeleted() para incluir faturas excluídas
+func (s *InvoiceService) FindByID(ctx context.Context, id string, opts ...domain.FindOption) (*dto.InvoiceOutput, error) {
+	invoice, err := s.invoiceRepository.FindByID(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromInvoice(invoice), nil
+}
+
+// Delete exclui logicamente uma fatura
+// Retorna ErrInvoiceNotFound se a fatura não existir ou já estiver excluída
+func (s *InvoiceService) Delete(ctx context.Context, id string) error {
+	return s.invoiceRepository.Delete(ctx, id)
+}
+
+// ProcessTransactionResult processa o resultado de uma transação após análise de fraude
+// Retorna ErrInvalidStatus se a fatura já tiver sido decidida ou o resultado não for uma mudança permitida
+func (s *InvoiceService) ProcessTransactionResult(ctx context.Context, invoiceID string, status domain.Status) error {
+	return s.decide(ctx, invoiceID, status, decisionSourceAntifraud)
+}
+
+// ProcessReviewDecision aplica à fatura a decisão da revisão manual, de um analista ou do prazo
+// Retorna ErrInvalidStatus se a fatura já tiver sido decidida
+func (s *InvoiceService) ProcessReviewDecision(ctx context.Context, invoiceID string, status domain.Status) error {
+	return s.decide(ctx, invoiceID, status, decisionSourceReview)
+}
+
+// decide muda o status da fatura pendente e, se ela foi aprovada, credita o saldo da conta ou das recebedoras
+// O status conferido e o crédito são gravados na mesma transação: um resultado repetido não credita de novo, e uma
+// falha no crédito não deixa a fatura aprovada
+func (s *InvoiceService) decide(ctx context.Context, invoiceID string, status domain.Status, source string) error {
+	invoice, err := s.invoiceRepository.FindByID(ctx, invoiceID)
+	if err != nil {
 		return err
 	}
+	// As faturas com vencimento só são decididas pela confirmação do pagamento
+	if invoice.AwaitingPayment() {
+		return domain.ErrInvalidStatus
+	}
 
+	if err := invoice.TransitionTo(status); err != nil {
+		return err
+	}
+	var splits []*domain.InvoiceSplit
 	if status == domain.StatusApproved {
-		account, err := s.accountService.FindByID(invoice.AccountID)
-		if err != nil {
+		if err := s.fx.Revalidate(ctx, invoice); err != nil {
 			return err
 		}
-
-		if _, err := s.accountService.UpdateBalance(account.APIKey, invoice.Amount); err != nil {
+		if splits, err = s.splits.Find(ctx, invoice.ID); err != nil {
 			return err
 		}
 	}
+	posting, err := s.creditPosting(ctx, invoice, splits)
+	if err != nil {
+		return err
+	}
 
+	if err := s.invoiceRepository.UpdateStatus(ctx, invoice, posting); err != nil {
+		return err
+	}
+	observeDecision(invoice, source)
+	s.posted(posting, splits)
 	return nil
 }
+
+// ConfirmPayment aprova a fatura com vencimento cujo pagamento foi feito em paidAt e credita o saldo da conta ou
+// das recebedoras; o pagamento depois do vencimento soma ao valor a multa e os juros de lateFees
+// Retorna ErrInvoiceNotFound se a fatura não existir, ErrInvalidStatus se ela não esperar pagamento e
+// ErrInvalidPaymentDate se paidAt for anterior à criação da fatura ou futuro
+// A fatura em outra moeda com a trava vencida é cotada de novo antes do cálculo da multa e dos juros
+func (s *InvoiceService) ConfirmPayment(ctx context.Context, invoiceID string, paidAt time.Time) (*dto.InvoiceOutput, error) {
+	invoice, err := s.invoiceRepository.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.AwaitingPayment() {
+		return nil, domain.ErrInvalidStatus
+	}
+	if err := s.fx.Revalidate(ctx, invoice); err != nil {
+		return nil, err
+	}
+	if err := invoice.ConfirmPayment(s.lateFees, paidAt); err != nil {
+		return nil, err
+	}
+
+	splits, err := s.splits.Find(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	posting, err := s.creditPosting(ctx, invoice, splits)
+	if err != nil {
+		return nil, err
+	}
+	// Como em decide, a aprovação e o crédito são gravados juntos
+	if err := s.invoiceRepository.UpdateStatus(ctx, invoice, posting); err != nil {
+		return nil, err
+	}
+	observeDecision(invoice, decisionSourcePayment)
+	s.posted(posting, splits)
+
+	fine, interest, daysLate := invoice.LateCharges()
+	slog.InfoContext(ctx, "pagamento da fatura confirmado",
+		"invoice_id", invoice.ID, "days_late", daysLate, "late_fine", fine, "late_interest", interest)
+	output := dto.FromInvoice(invoice)
+	output.Splits = dto.FromInvoiceSplits(splits)
+	return output, nil
+}
@@ -7,8 +7,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/joaodematejr/imersao22/go-gateway/internal/breaker"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain/events"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/faults"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
 )
 
 type KafkaProducerInterface interface {
@@ -51,13 +56,38 @@ func NewKafkaConfig() *KafkaConfig {
 	}
 }
 
+// Ping verifica se algum dos brokers responde ao pedido de metadados do cluster, para a verificação de saúde
+func (c *KafkaConfig) Ping(ctx context.Context) error {
+	var err error
+	for _, broker := range c.Brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		_, err = conn.Brokers()
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 type KafkaProducer struct {
 	writer  *kafka.Writer
 	topic   string
 	brokers []string
+	breaker *breaker.Breaker
 }
 
-func NewKafkaProducer(config *KafkaConfig) *KafkaProducer {
+// NewKafkaProducer cria o produtor do tópico da configuração
+// As publicações passam pelo circuit breaker, para que um Kafka lento não segure a criação das faturas;
+// com circuit nil elas vão sempre ao Kafka
+func NewKafkaProducer(config *KafkaConfig, circuit *breaker.Breaker) *KafkaProducer {
 	writer := &kafka.Writer{
 		Addr:     kafka.TCP(config.Brokers...),
 		Topic:    config.Topic,
@@ -69,30 +99,40 @@ func NewKafkaProducer(config *KafkaConfig) *KafkaProducer {
 		writer:  writer,
 		topic:   config.Topic,
 		brokers: config.Brokers,
+		breaker: circuit,
 	}
 }
 
+// SendingPendingTransaction publica a fatura pendente para o antifraude
+// Retorna ErrDependencyUnavailable, sem publicar, com o circuito do Kafka aberto
 func (s *KafkaProducer) SendingPendingTransaction(ctx context.Context, event events.PendingTransaction) error {
 	value, err := json.Marshal(event)
 	if err != nil {
-		slog.Error("erro ao converter evento para json", "error", err)
+		slog.ErrorContext(ctx, "erro ao converter evento para json", "error", err)
 		return err
 	}
 
 	msg := kafka.Message{
-		Value: value,
+		Value:   value,
+		Headers: correlationHeaders(ctx),
 	}
 
-	slog.Info("enviando mensagem para o kafka",
+	slog.DebugContext(ctx, "enviando mensagem para o kafka",
 		"topic", s.topic,
 		"message", string(value))
 
-	if err := s.writer.WriteMessages(ctx, msg); err != nil {
-		slog.Error("erro ao enviar mensagem para o kafka", "error", err)
+	err = s.breaker.Execute(ctx, func(ctx context.Context) error {
+		if err := faults.Inject(ctx, faults.Kafka, nil); err != nil {
+			return err
+		}
+		return s.writer.WriteMessages(ctx, msg)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao enviar mensagem para o kafka", "error", err)
 		return err
 	}
 
-	slog.Info("mensagem enviada com sucesso para o kafka", "topic", s.topic)
+	slog.DebugContext(ctx, "mensagem enviada com sucesso para o kafka", "topic", s.topic)
 	return nil
 }
 
@@ -101,15 +141,21 @@ func (s *KafkaProducer) Close() error {
 	return s.writer.Close()
 }
 
+// antifraudActor identifica na auditoria as mutações vindas do resultado do antifraude
+const antifraudActor = "system:antifraud"
+
 type KafkaConsumer struct {
 	reader         *kafka.Reader
 	topic          string
 	brokers        []string
 	groupID        string
 	invoiceService *InvoiceService
+	reviews        *RiskReviewService
 }
 
-func NewKafkaConsumer(config *KafkaConfig, groupID string, invoiceService *InvoiceService) *KafkaConsumer {
+// NewKafkaConsumer cria o consumidor dos resultados do antifraude
+// Os resultados com score de risco são decididos por reviews, pelos limites de score da conta
+func NewKafkaConsumer(config *KafkaConfig, groupID string, invoiceService *InvoiceService, reviews *RiskReviewService) *KafkaConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: config.Brokers,
 		Topic:   config.Topic,
@@ -127,44 +173,127 @@ func NewKafkaConsumer(config *KafkaConfig, groupID string, invoiceService *Invoi
 		brokers:        config.Brokers,
 		groupID:        groupID,
 		invoiceService: invoiceService,
+		reviews:        reviews,
 	}
 }
 
+// Consume processa os resultados do antifraude até o contexto ser cancelado, quando retorna nil
+// Cada mensagem só é confirmada no Kafka depois de processada; o cancelamento não interrompe a mensagem em
+// processamento, que termina e é confirmada antes de Consume retornar
 func (c *KafkaConsumer) Consume(ctx context.Context) error {
 	for {
-		msg, err := c.reader.ReadMessage(ctx)
+		msg, err := c.reader.FetchMessage(ctx)
 		if err != nil {
-			slog.Error("erro ao ler mensagem do kafka", "error", err)
+			if ctx.Err() != nil {
+				return nil
+			}
+			slog.ErrorContext(ctx, "erro ao ler mensagem do kafka", "error", err)
 			return err
 		}
 
-		var result events.TransactionResult
-		if err := json.Unmarshal(msg.Value, &result); err != nil {
-			slog.Error("erro ao converter mensagem para TransactionResult", "error", err)
-			continue
+		processCtx := context.WithoutCancel(ctx)
+		c.process(processCtx, msg)
+		if err := c.reader.CommitMessages(processCtx, msg); err != nil {
+			slog.ErrorContext(ctx, "erro ao confirmar mensagem do kafka", "error", err)
+			return err
 		}
+	}
+}
 
-		slog.Info("mensagem recebida do kafka",
-			"topic", c.topic,
-			"invoice_id", result.InvoiceID,
-			"status", result.Status)
+// process aplica o resultado da mensagem à fatura; mensagens inválidas ou com falha vão para o log e são
+// confirmadas mesmo assim, para não travar a partição
+func (c *KafkaConsumer) process(ctx context.Context, msg kafka.Message) {
+	var result events.TransactionResult
+	if err := json.Unmarshal(msg.Value, &result); err != nil {
+		slog.ErrorContext(ctx, "erro ao converter mensagem para TransactionResult", "error", err)
+		return
+	}
 
-		// Processa o resultado da transação
-		if err := c.invoiceService.ProcessTransactionResult(result.InvoiceID, result.ToDomainStatus()); err != nil {
-			slog.Error("erro ao processar resultado da transação",
-				"error", err,
-				"invoice_id", result.InvoiceID,
-				"status", result.Status)
-			continue
-		}
+	// Processa o resultado da transação em nome do antifraude; os logs do processamento levam a fatura e,
+	// se o antifraude devolveu os headers de correlação, o ID da requisição que criou a fatura
+	resultCtx := logging.With(requestctx.WithActor(withCorrelation(ctx, msg.Headers), antifraudActor), "invoice_id", result.InvoiceID)
+	slog.DebugContext(resultCtx, "mensagem recebida do kafka",
+		"topic", c.topic,
+		"status", result.Status)
 
-		slog.Info("transação processada com sucesso",
-			"invoice_id", result.InvoiceID,
+	if err := c.apply(resultCtx, result); err != nil {
+		slog.ErrorContext(resultCtx, "erro ao processar resultado da transação",
+			"error", err,
 			"status", result.Status)
+		return
 	}
+
+	slog.InfoContext(resultCtx, "transação processada com sucesso",
+		"status", result.Status)
+}
+
+// apply decide a fatura pelo score de risco, quando o antifraude o enviou, ou pelo status do resultado
+func (c *KafkaConsumer) apply(ctx context.Context, result events.TransactionResult) error {
+	if result.RiskScore != nil {
+		return c.reviews.ProcessScore(ctx, result.InvoiceID, *result.RiskScore)
+	}
+	return c.invoiceService.ProcessTransactionResult(ctx, result.InvoiceID, result.ToDomainStatus())
+}
+
+// Lag retorna quantas mensagens do tópico ainda faltam ler, segundo o último lote buscado pelo consumidor
+// Usa as estatísticas do reader, já que Reader.Lag não funciona em consumer groups; os demais contadores das
+// estatísticas são zerados a cada leitura, mas não são usados
+func (c *KafkaConsumer) Lag() int64 {
+	return c.reader.Stats().Lag
 }
 
 func (c *KafkaConsumer) Close() error {
 	slog.Info("fechando conexao com o kafka consumer")
 	return c.reader.Close()
 }
+
+// requestIDHeader é o header das mensagens do Kafka com o ID da requisição que originou o evento
+const requestIDHeader = "request_id"
+
+// kafkaHeaderCarrier adapta os headers de uma mensagem do Kafka ao propagador do OpenTelemetry
+type kafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+// Get implementa propagation.TextMapCarrier
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set implementa propagation.TextMapCarrier
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys implementa propagation.TextMapCarrier
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, header := range *c.headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
+
+// correlationHeaders monta os headers com o ID da requisição e o contexto de trace de ctx
+func correlationHeaders(ctx context.Context) []kafka.Header {
+	var headers []kafka.Header
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		headers = append(headers, kafka.Header{Key: requestIDHeader, Value: []byte(requestID)})
+	}
+	otel.GetTextMapPropagator().Inject(ctx, kafkaHeaderCarrier{headers: &headers})
+	return headers
+}
+
+// withCorrelation recupera dos headers da mensagem o ID da requisição e o contexto de trace
+func withCorrelation(ctx context.Context, headers []kafka.Header) context.Context {
+	carrier := kafkaHeaderCarrier{headers: &headers}
+	if requestID := carrier.Get(requestIDHeader); requestID != "" {
+		ctx = requestctx.WithRequestID(ctx, requestID)
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
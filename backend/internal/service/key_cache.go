@@ -0,0 +1,85 @@
+package service
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// maxMissingKeys limita as entradas do cache de API Keys inexistentes, mesmo sob enumeração intensa
+const maxMissingKeys = 10000
+
+// missingKeyCache lembra por pouco tempo os API Keys que não pertencem a nenhuma conta
+// Guarda apenas o SHA-256 das chaves, para que os palpites recebidos não fiquem em memória
+// Um cache nil está desativado e não lembra nada
+type missingKeyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[[sha256.Size]byte]time.Time
+}
+
+// newMissingKeyCache cria o cache com a validade informada; ttl zero desativa o cache
+func newMissingKeyCache(ttl time.Duration) *missingKeyCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &missingKeyCache{ttl: ttl, entries: make(map[[sha256.Size]byte]time.Time)}
+}
+
+// contains indica se o API Key foi procurado e não encontrado dentro da validade
+func (c *missingKeyCache) contains(apiKey string) bool {
+	if c == nil {
+		return false
+	}
+
+	key := sha256.Sum256([]byte(apiKey))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt, ok := c.entries[key]
+	if ok && time.Now().After(expiresAt) {
+		delete(c.entries, key)
+		return false
+	}
+	return ok
+}
+
+// add registra um API Key inexistente
+// Com o cache cheio as entradas vencidas são removidas e, se ainda faltar espaço, uma entrada qualquer é descartada
+func (c *missingKeyCache) add(apiKey string) {
+	if c == nil {
+		return
+	}
+
+	key := sha256.Sum256([]byte(apiKey))
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxMissingKeys {
+		for k, expiresAt := range c.entries {
+			if now.After(expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxMissingKeys {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
+
+// remove esquece um API Key, usado quando ele passa a pertencer a uma conta
+func (c *missingKeyCache) remove(apiKey string) {
+	if c == nil {
+		return
+	}
+
+	key := sha256.Sum256([]byte(apiKey))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// LedgerService grava o razão das contas e monta o extrato delas
+type LedgerService struct {
+	entries        domain.LedgerRepository
+	accountService *AccountService
+}
+
+// NewLedgerService cria o serviço do razão
+func NewLedgerService(entries domain.LedgerRepository, accountService *AccountService) *LedgerService {
+	return &LedgerService{entries: entries, accountService: accountService}
+}
+
+// Record grava os lançamentos
+func (s *LedgerService) Record(ctx context.Context, entries []*domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.entries.CreateBatch(ctx, entries)
+}
+
+// Post grava o movimento de saldo, com os lançamentos, as retenções e os saldos em uma única transação
+func (s *LedgerService) Post(ctx context.Context, posting *domain.Posting) error {
+	return s.entries.Post(ctx, posting)
+}
+
+// Adjust ajusta manualmente o saldo da conta do API Key, gravando o lançamento do ajuste e o saldo no mesmo movimento
+func (s *LedgerService) Adjust(ctx context.Context, apiKey string, amount float64) (*dto.AccountOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Post(ctx, &domain.Posting{
+		Entries:  []*domain.LedgerEntry{domain.NewLedgerEntry(account.ID, "", "", domain.LedgerAdjustment, amount)},
+		Balances: map[string]float64{account.ID: amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.accountService.FindByID(ctx, account.ID)
+}
+
+// Statement retorna o extrato da conta do API Key no período que termina agora, como "30d" (padrão) ou "24h"
+// Retorna ErrInvalidStatsPeriod para períodos inválidos
+func (s *LedgerService) Statement(ctx context.Context, apiKey, period string) (*dto.StatementOutput, error) {
+	if period == "" {
+		period = domain.DefaultStatsPeriod
+	}
+	duration, err := domain.ParseStatsPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	from := to.Add(-duration)
+	entries, err := s.entries.List(ctx, account.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewStatementOutput(period, from, to, entries), nil
+}
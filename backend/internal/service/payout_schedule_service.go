@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// PayoutScheduleService gerencia os prazos de repasse das contas: o que cada conta recebe pelas faturas aprovadas
+// fica retido até a data de repasse e é liberado para o saldo disponível pela mesma tarefa que libera as custódias
+type PayoutScheduleService struct {
+	schedules      domain.PayoutScheduleRepository
+	accountService *AccountService
+	defaultDelay   int
+}
+
+// NewPayoutScheduleService cria o serviço de prazos de repasse; defaultDelay é o prazo, em dias, das contas que não
+// escolheram um
+func NewPayoutScheduleService(schedules domain.PayoutScheduleRepository, accountService *AccountService, defaultDelay int) *PayoutScheduleService {
+	return &PayoutScheduleService{schedules: schedules, accountService: accountService, defaultDelay: defaultDelay}
+}
+
+// Schedule retorna o prazo da conta ou, se ela não escolheu um, o prazo padrão, sem UpdatedAt
+func (s *PayoutScheduleService) Schedule(ctx context.Context, accountID string) (*domain.PayoutSchedule, error) {
+	schedule, err := s.schedules.FindByAccountID(ctx, accountID)
+	if err == domain.ErrPayoutScheduleNotFound {
+		return &domain.PayoutSchedule{AccountID: accountID, DelayDays: s.defaultDelay}, nil
+	}
+	return schedule, err
+}
+
+// Get retorna o prazo de repasse da conta do API Key
+func (s *PayoutScheduleService) Get(ctx context.Context, apiKey string) (*dto.PayoutScheduleOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.Schedule(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPayoutSchedule(schedule)
+	return &output, nil
+}
+
+// Update substitui o prazo de repasse da conta do API Key
+// O novo prazo vale para as faturas aprovadas depois da troca; os valores já retidos mantêm a data de repasse
+// Retorna ErrInvalidPayoutSchedule se o prazo não for um dos aceitos
+func (s *PayoutScheduleService) Update(ctx context.Context, apiKey string, input dto.PayoutScheduleInput) (*dto.PayoutScheduleOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	delay, err := domain.ParsePayoutDelay(input.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := domain.NewPayoutSchedule(account.ID, delay)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Save(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "prazo de repasse alterado", "account_id", account.ID, "schedule", schedule.Name())
+	output := dto.FromPayoutSchedule(schedule)
+	return &output, nil
+}
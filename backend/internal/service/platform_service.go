@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// PlatformService liga contas filhas às plataformas (marketplaces) e retém a comissão delas nos créditos
+// As ligações são geridas pelos administradores; uma plataforma não pode ser filha de outra
+type PlatformService struct {
+	links          domain.PlatformLinkRepository
+	accountService *AccountService
+}
+
+// NewPlatformService cria o serviço de plataformas
+func NewPlatformService(links domain.PlatformLinkRepository, accountService *AccountService) *PlatformService {
+	return &PlatformService{links: links, accountService: accountService}
+}
+
+// Get retorna a ligação da conta com a plataforma
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (s *PlatformService) Get(ctx context.Context, accountID string) (*dto.PlatformLinkOutput, error) {
+	link, err := s.links.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromPlatformLink(link), nil
+}
+
+// Link cria ou substitui a ligação da conta com a plataforma
+// Retorna ErrAccountNotFound se a conta não existir e ErrInvalidPlatformLink se a plataforma não existir, for de
+// outra organização ou de outro modo, for filha de outra, a conta já for plataforma de outras ou a comissão for
+// inválida
+func (s *PlatformService) Link(ctx context.Context, accountID string, input dto.PlatformLinkInput) (*dto.PlatformLinkOutput, error) {
+	account, err := s.accountService.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	link, err := domain.NewPlatformLink(accountID, input.PlatformID, input.CommissionPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := s.accountService.FindByID(ctx, link.PlatformID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotFound) {
+			return nil, domain.ErrInvalidPlatformLink
+		}
+		return nil, err
+	}
+	// A comissão das contas de teste não pode chegar a uma plataforma real, nem a de uma organização a outra
+	if !sameLedger(account, platform) {
+		return nil, domain.ErrInvalidPlatformLink
+	}
+	// Sem cadeias: a plataforma não é filha de outra e a conta não tem filhas
+	if _, err := s.links.FindByAccountID(ctx, link.PlatformID); !errors.Is(err, domain.ErrPlatformLinkNotFound) {
+		if err == nil {
+			return nil, domain.ErrInvalidPlatformLink
+		}
+		return nil, err
+	}
+	children, err := s.links.CountByPlatformID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if children > 0 {
+		return nil, domain.ErrInvalidPlatformLink
+	}
+
+	current, err := s.links.FindByAccountID(ctx, accountID)
+	switch {
+	case err == nil:
+		link.CreatedAt = current.CreatedAt
+	case !errors.Is(err, domain.ErrPlatformLinkNotFound):
+		return nil, err
+	}
+	if err := s.links.Save(ctx, link); err != nil {
+		return nil, err
+	}
+	return dto.FromPlatformLink(link), nil
+}
+
+// sameLedger retorna a ligação se a conta filha e a plataforma puderem trocar saldo e nil se não puderem, como nas
+// ligações gravadas antes da conferência do modo em Link; uma conta excluída também desfaz a retenção
+func (s *PlatformService) sameLedger(ctx context.Context, link *domain.PlatformLink) (*domain.PlatformLink, error) {
+	account, err := s.accountService.FindByID(ctx, link.AccountID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	platform, err := s.accountService.FindByID(ctx, link.PlatformID)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !sameLedger(account, platform) {
+		return nil, nil
+	}
+	return link, nil
+}
+
+// Unlink desfaz a ligação da conta com a plataforma; as comissões já retidas continuam no razão
+// Retorna ErrPlatformLinkNotFound se a conta não for filha de uma plataforma
+func (s *PlatformService) Unlink(ctx context.Context, accountID string) error {
+	return s.links.Delete(ctx, accountID)
+}
+
+// Withheld registra em /metrics as comissões dos lançamentos devolvidos por Withhold, depois de gravados
+func (s *PlatformService) Withheld(entries []*domain.LedgerEntry) {
+	for _, entry := range entries {
+		if entry.Type == domain.LedgerCommission {
+			metrics.PlatformCommissionTotal.Add(entry.Amount)
+		}
+	}
+}
+
+// Withhold retém a comissão das plataformas sobre o que cada conta filha recebe das faturas aprovadas
+// payouts[i] é quanto cada conta recebe por invoices[i] e é ajustado no lugar: a comissão sai da conta filha e
+// vai para a plataforma
+// Retorna os lançamentos do razão das comissões, um na plataforma e um na conta filha por fatura, que o chamador
+// grava junto com os créditos
+func (s *PlatformService) Withhold(ctx context.Context, invoices []*domain.Invoice, payouts []map[string]float64) ([]*domain.LedgerEntry, error) {
+	links := make(map[string]*domain.PlatformLink)
+	var entries []*domain.LedgerEntry
+	for i, invoice := range invoices {
+		accountIDs := make([]string, 0, len(payouts[i]))
+		for accountID := range payouts[i] {
+			accountIDs = append(accountIDs, accountID)
+		}
+		sort.Strings(accountIDs)
+
+		for _, accountID := range accountIDs {
+			link, cached := links[accountID]
+			if !cached {
+				var err error
+				link, err = s.links.FindByAccountID(ctx, accountID)
+				if err != nil && !errors.Is(err, domain.ErrPlatformLinkNotFound) {
+					return nil, err
+				}
+				if link != nil {
+					if link, err = s.sameLedger(ctx, link); err != nil {
+						return nil, err
+					}
+				}
+				links[accountID] = link
+			}
+			if link == nil {
+				continue
+			}
+
+			commission := link.Commission(payouts[i][accountID])
+			if commission <= 0 {
+				continue
+			}
+			payouts[i][accountID] -= commission
+			payouts[i][link.PlatformID] += commission
+			entries = append(entries,
+				domain.NewLedgerEntry(link.PlatformID, accountID, invoice.ID, domain.LedgerCommission, commission),
+				domain.NewLedgerEntry(accountID, link.PlatformID, invoice.ID, domain.LedgerCommissionWithheld, -commission),
+			)
+		}
+	}
+	return entries, nil
+}
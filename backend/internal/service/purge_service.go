@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// PurgeService remove definitivamente registros excluídos logicamente há mais tempo que a retenção
+type PurgeService struct {
+	invoiceRepository domain.PurgeableRepository
+	accountRepository domain.PurgeableRepository
+}
+
+// NewPurgeService cria um novo serviço de expurgo
+func NewPurgeService(invoiceRepository, accountRepository domain.PurgeableRepository) *PurgeService {
+	return &PurgeService{
+		invoiceRepository: invoiceRepository,
+		accountRepository: accountRepository,
+	}
+}
+
+// Purge remove os registros excluídos antes de before
+// Faturas são removidas antes das contas para liberar as referências; em dryRun apenas conta os registros
+func (s *PurgeService) Purge(ctx context.Context, before time.Time, dryRun bool) (*dto.PurgeOutput, error) {
+	output := &dto.PurgeOutput{Before: before, DryRun: dryRun}
+
+	run := func(repository domain.PurgeableRepository) (int64, error) {
+		if dryRun {
+			return repository.CountDeleted(ctx, before)
+		}
+		return repository.PurgeDeleted(ctx, before)
+	}
+
+	var err error
+	if output.Invoices, err = run(s.invoiceRepository); err != nil {
+		return nil, err
+	}
+	if output.Accounts, err = run(s.accountRepository); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "expurgo de registros excluídos concluído",
+		"before", before,
+		"dry_run", dryRun,
+		"invoices", output.Invoices,
+		"accounts", output.Accounts)
+
+	return output, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// ReconciliationConfig configura a conciliação periódica dos saldos com o razão
+type ReconciliationConfig struct {
+	// Interval é a frequência com que todas as contas são conciliadas; zero desativa a conciliação periódica
+	Interval time.Duration
+	// AutoCorrect corrige os saldos divergentes para o valor do razão; desligado, as divergências só vão para o log
+	// e para as métricas
+	AutoCorrect bool
+}
+
+// balanceReconciliationActor identifica na auditoria os saldos corrigidos pela conciliação periódica
+const balanceReconciliationActor = "system:balance-reconciliation"
+
+// reconciliationBatch limita as contas lidas a cada consulta
+const reconciliationBatch = 100
+
+// ReconciliationService recalcula o saldo das contas pelo razão e o compara com o saldo gravado, corrigindo as
+// divergências quando pedido
+// A primeira conciliação de cada conta lança no razão o saldo de abertura, que cobre os movimentos anteriores a ele
+type ReconciliationService struct {
+	accounts domain.AccountRepository
+	ledger   domain.LedgerRepository
+	config   ReconciliationConfig
+}
+
+// NewReconciliationService cria o serviço de conciliação dos saldos
+func NewReconciliationService(accounts domain.AccountRepository, ledger domain.LedgerRepository, config ReconciliationConfig) *ReconciliationService {
+	return &ReconciliationService{accounts: accounts, ledger: ledger, config: config}
+}
+
+// Enabled indica se a conciliação periódica está ligada
+func (s *ReconciliationService) Enabled() bool {
+	return s.config.Interval > 0
+}
+
+// reconcile concilia a conta com o saldo travado: com write, lança o saldo de abertura das contas que ainda não o têm
+// e, com correct, soma ao saldo divergente a diferença para o valor do razão
+func (s *ReconciliationService) reconcile(ctx context.Context, account *domain.Account, write, correct bool) (*domain.BalanceReconciliation, error) {
+	reconciliation, err := s.ledger.Reconcile(ctx, account.ID, write, correct)
+	if err != nil {
+		return nil, err
+	}
+	if write && reconciliation.OpeningBalance != 0 {
+		slog.InfoContext(ctx, "saldo de abertura lançado no razão", "account_id", account.ID, "amount", reconciliation.OpeningBalance)
+	}
+	if !reconciliation.Drifted() {
+		return reconciliation, nil
+	}
+
+	slog.WarnContext(ctx, "saldo divergente do razão",
+		"account_id", account.ID, "balance", reconciliation.Balance, "ledger_balance", reconciliation.LedgerBalance, "drift", reconciliation.Drift())
+	if !reconciliation.Corrected {
+		return reconciliation, nil
+	}
+	// A auditoria da conta guarda o saldo anterior e o corrigido, com o autor da correção
+	metrics.BalanceCorrectionsTotal.Inc()
+	slog.WarnContext(ctx, "saldo corrigido para o valor do razão",
+		"account_id", account.ID, "balance", reconciliation.Balance, "ledger_balance", reconciliation.LedgerBalance,
+		"corrected_by", requestctx.Actor(ctx))
+	return reconciliation, nil
+}
+
+// Check concilia a conta sem alterar nada; contas ainda sem saldo de abertura não divergem
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *ReconciliationService) Check(ctx context.Context, accountID string) (*dto.BalanceReconciliationOutput, error) {
+	account, err := s.accounts.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	reconciliation, err := s.reconcile(ctx, account, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromBalanceReconciliation(reconciliation), nil
+}
+
+// Correct concilia a conta em nome do autor do contexto, corrigindo o saldo divergente para o valor do razão
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *ReconciliationService) Correct(ctx context.Context, accountID string) (*dto.BalanceReconciliationOutput, error) {
+	account, err := s.accounts.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	reconciliation, err := s.reconcile(ctx, account, true, true)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromBalanceReconciliation(reconciliation), nil
+}
+
+// each chama fn para cada conta não excluída, em lotes de reconciliationBatch
+func (s *ReconciliationService) each(ctx context.Context, fn func(account *domain.Account) error) error {
+	afterID := ""
+	for {
+		accounts, err := s.accounts.ListAfter(ctx, afterID, reconciliationBatch)
+		if err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			if err := fn(account); err != nil {
+				return err
+			}
+		}
+		if len(accounts) < reconciliationBatch {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// CheckAll concilia todas as contas sem alterar nada e retorna as divergentes
+func (s *ReconciliationService) CheckAll(ctx context.Context) ([]*dto.BalanceReconciliationOutput, error) {
+	output := []*dto.BalanceReconciliationOutput{}
+	err := s.each(ctx, func(account *domain.Account) error {
+		reconciliation, err := s.reconcile(ctx, account, false, false)
+		if err != nil {
+			return err
+		}
+		if reconciliation.Drifted() {
+			output = append(output, dto.FromBalanceReconciliation(reconciliation))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ReconcileAll concilia todas as contas, corrigindo as divergentes se AutoCorrect estiver ligado, e retorna quantas
+// divergiam
+// As falhas de cada conta vão para o log e não interrompem as demais
+func (s *ReconciliationService) ReconcileAll(ctx context.Context) int {
+	ctx = requestctx.WithActor(ctx, balanceReconciliationActor)
+	drifted := 0
+	err := s.each(ctx, func(account *domain.Account) error {
+		reconciliation, err := s.reconcile(ctx, account, true, s.config.AutoCorrect)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			slog.ErrorContext(ctx, "erro ao conciliar o saldo da conta", "account_id", account.ID, "error", err)
+			return nil
+		}
+		if reconciliation.Drifted() {
+			drifted++
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao buscar as contas a conciliar", "error", err)
+		return drifted
+	}
+	metrics.BalanceDriftAccounts.Set(float64(drifted))
+	return drifted
+}
+
+// Run concilia todas as contas a cada intervalo; bloqueia até o contexto ser cancelado
+func (s *ReconciliationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.ReconcileAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ReconcileAll(ctx)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// RefundConfig configura o prazo dos reembolsos pendentes de aprovação e o prazo para reembolsar as faturas
+type RefundConfig struct {
+	// ApprovalTTL é o prazo para aprovar um reembolso pendente; vencido, ele expira sem debitar a conta
+	ApprovalTTL time.Duration
+	// Interval é a frequência com que os pendentes vencidos são procurados
+	Interval time.Duration
+	// WindowDays é o prazo padrão, em dias contados da criação da fatura, para pedir o reembolso; zero desativa o
+	// prazo
+	WindowDays int
+}
+
+// refundExpiryActor identifica nos reembolsos os pedidos expirados pelo prazo
+const refundExpiryActor = "system:refund-expiry"
+
+// Limites das listagens e dos reembolsos vencidos expirados a cada consulta
+const (
+	maxRefundPageSize = 500
+	refundExpiryBatch = 100
+)
+
+// RefundService reembolsa as faturas aprovadas, debitando o saldo disponível das contas que receberam por elas
+// Acima do limite da política da conta, o reembolso fica pendente até um usuário diferente de quem pediu aprovar
+// (maker-checker); sem aprovação no prazo, ele expira
+// Passado o prazo de reembolso da conta, só os administradores globais reembolsam a fatura
+type RefundService struct {
+	refunds        domain.RefundRepository
+	policies       domain.RefundPolicyRepository
+	windows        domain.AccountRefundWindowRepository
+	invoices       domain.InvoiceRepository
+	accountService *AccountService
+	splits         *SplitService
+	config         RefundConfig
+}
+
+// NewRefundService cria o serviço de reembolsos
+func NewRefundService(refunds domain.RefundRepository, policies domain.RefundPolicyRepository, windows domain.AccountRefundWindowRepository, invoices domain.InvoiceRepository, accountService *AccountService, splits *SplitService, config RefundConfig) *RefundService {
+	return &RefundService{
+		refunds:        refunds,
+		policies:       policies,
+		windows:        windows,
+		invoices:       invoices,
+		accountService: accountService,
+		splits:         splits,
+		config:         config,
+	}
+}
+
+// policy retorna a política da conta ou, se ela não definiu uma, a que dispensa a aprovação
+func (s *RefundService) policy(ctx context.Context, accountID string) (*domain.RefundPolicy, error) {
+	policy, err := s.policies.FindByAccountID(ctx, accountID)
+	if err == domain.ErrRefundPolicyNotFound {
+		return &domain.RefundPolicy{AccountID: accountID}, nil
+	}
+	return policy, err
+}
+
+// accountWindow retorna o prazo de reembolso próprio da conta, ou nil se ela usa o padrão
+func (s *RefundService) accountWindow(ctx context.Context, accountID string) (*domain.AccountRefundWindow, error) {
+	window, err := s.windows.FindByAccountID(ctx, accountID)
+	if err == domain.ErrRefundWindowNotFound {
+		return nil, nil
+	}
+	return window, err
+}
+
+// windowDays retorna o prazo de reembolso que vale para a conta: o próprio dela ou o padrão
+func (s *RefundService) windowDays(ctx context.Context, accountID string) (int, error) {
+	window, err := s.accountWindow(ctx, accountID)
+	if err != nil || window == nil {
+		return s.config.WindowDays, err
+	}
+	return window.Days, nil
+}
+
+// GetPolicy retorna a política de reembolso da conta do API Key, com o prazo de reembolso que vale para ela
+func (s *RefundService) GetPolicy(ctx context.Context, apiKey string) (*dto.RefundPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.policy(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	windowDays, err := s.windowDays(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromRefundPolicy(policy)
+	output.WindowDays = windowDays
+	return &output, nil
+}
+
+// UpdatePolicy substitui a política de reembolso da conta do API Key
+// Retorna ErrInvalidRefundPolicy se o limite for negativo
+func (s *RefundService) UpdatePolicy(ctx context.Context, apiKey string, input dto.RefundPolicyInput) (*dto.RefundPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := domain.NewRefundPolicy(account.ID, input.ApprovalThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policies.Save(ctx, policy); err != nil {
+		return nil, err
+	}
+	windowDays, err := s.windowDays(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromRefundPolicy(policy)
+	output.WindowDays = windowDays
+	return &output, nil
+}
+
+// GetWindow retorna o prazo de reembolso que vale para a conta
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *RefundService) GetWindow(ctx context.Context, accountID string) (*dto.RefundWindowOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	window, err := s.accountWindow(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromRefundWindow(accountID, window, s.config.WindowDays), nil
+}
+
+// UpdateWindow substitui o prazo de reembolso da conta; zero desativa o prazo
+// Retorna ErrAccountNotFound se a conta não existir e ErrInvalidRefundWindow se o prazo for inválido
+func (s *RefundService) UpdateWindow(ctx context.Context, accountID string, input dto.RefundWindowInput) (*dto.RefundWindowOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	window, err := domain.NewAccountRefundWindow(accountID, input.Days)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.windows.Save(ctx, window); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "prazo de reembolso da conta alterado", "account_id", accountID, "window_days", window.Days)
+	return dto.FromRefundWindow(accountID, window, s.config.WindowDays), nil
+}
+
+// ResetWindow volta a conta para o prazo de reembolso padrão do gateway
+// Retorna ErrAccountNotFound se a conta não existir
+func (s *RefundService) ResetWindow(ctx context.Context, accountID string) (*dto.RefundWindowOutput, error) {
+	if _, err := s.accountService.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	if err := s.windows.Delete(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "prazo de reembolso da conta voltou ao padrão", "account_id", accountID)
+	return dto.FromRefundWindow(accountID, nil, s.config.WindowDays), nil
+}
+
+// checkWindow confere se o prazo de reembolso da conta dona ainda está aberto para a fatura
+// Retorna ErrTransactionNotAllowed se o prazo já passou
+func (s *RefundService) checkWindow(ctx context.Context, invoice *domain.Invoice) error {
+	windowDays, err := s.windowDays(ctx, invoice.AccountID)
+	if err != nil {
+		return err
+	}
+	if !domain.RefundWindowOpen(invoice, windowDays, time.Now()) {
+		metrics.RefundWindowTotal.WithLabelValues("rejected").Inc()
+		slog.InfoContext(ctx, "reembolso recusado fora do prazo",
+			"account_id", invoice.AccountID, "invoice_id", invoice.ID, "window_days", windowDays)
+		return domain.ErrTransactionNotAllowed
+	}
+	return nil
+}
+
+// prepare cria o reembolso da fatura pedido pelo autor do contexto, sem debitar nada
+// O limite é conferido de novo, com a fatura travada, quando o reembolso é gravado
+// Retorna ErrInvalidStatus se a fatura não estiver aprovada ou liquidada, ErrTransactionAlreadyRefunded se ela já
+// foi toda reembolsada e ErrInvalidRefund se o valor passar do que falta
+func (s *RefundService) prepare(ctx context.Context, invoice *domain.Invoice, input dto.RefundInput) (*domain.Refund, error) {
+	if invoice.Status == domain.StatusRefunded {
+		return nil, domain.ErrTransactionAlreadyRefunded
+	}
+	if !invoice.Status.IsPaid() {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	// Os pendentes também contam, para que dois pedidos não reembolsem juntos mais que a fatura
+	refunded, err := s.refunds.SumByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := math.Round((invoice.Amount-refunded)*100) / 100
+	if remaining <= 0 {
+		return nil, domain.ErrTransactionAlreadyRefunded
+	}
+	amount := input.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	return domain.NewRefund(invoice, amount, remaining, input.Reason, requestctx.Actor(ctx))
+}
+
+// refundPosting monta o movimento que debita o reembolso concluído das contas que receberam pela fatura, na
+// proporção do que cada uma recebeu, e o lança no razão
+func (s *RefundService) refundPosting(ctx context.Context, invoice *domain.Invoice, refund *domain.Refund) (*domain.Posting, error) {
+	payouts, err := s.splits.Payouts(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+	return refund.Posting(payouts), nil
+}
+
+// debit grava o reembolso concluído junto com os débitos nos saldos e os lançamentos no razão
+func (s *RefundService) debit(ctx context.Context, invoice *domain.Invoice, refund *domain.Refund) error {
+	posting, err := s.refundPosting(ctx, invoice, refund)
+	if err != nil {
+		return err
+	}
+	if err := s.refunds.Create(ctx, refund, posting); err != nil {
+		return err
+	}
+	s.completed(ctx, refund)
+	return nil
+}
+
+// Request pede o reembolso da fatura da conta do API Key em nome do autor do contexto
+// Abaixo do limite da política o saldo é debitado na hora; acima, o reembolso fica pendente de aprovação
+// Retorna ErrUnauthorizedAccess se a fatura for de outra conta, ErrInvalidStatus se ela não estiver aprovada,
+// ErrTransactionNotAllowed se o prazo de reembolso da conta já passou, ErrTransactionAlreadyRefunded se ela já foi
+// toda reembolsada, ErrInvalidRefund se o valor passar do que falta e ErrInsufficientBalance se o saldo de uma das
+// contas debitadas não cobrir a parte dela
+func (s *RefundService) Request(ctx context.Context, apiKey, invoiceID string, input dto.RefundInput) (*dto.RefundOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+	if err := s.checkWindow(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	refund, err := s.prepare(ctx, invoice, input)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.policy(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.RequiresApproval(refund.Amount) {
+		refund.RequireApproval(s.config.ApprovalTTL)
+		if err := s.refunds.Create(ctx, refund, nil); err != nil {
+			return nil, err
+		}
+		metrics.RefundsTotal.WithLabelValues(string(domain.RefundPending)).Inc()
+		slog.InfoContext(ctx, "reembolso aguardando aprovação",
+			"invoice_id", invoice.ID, "refund_id", refund.ID, "amount", refund.Amount, "expires_at", refund.ExpiresAt)
+		return dto.FromRefund(refund), nil
+	}
+
+	if err := s.debit(ctx, invoice, refund); err != nil {
+		return nil, err
+	}
+	return dto.FromRefund(refund), nil
+}
+
+// Force reembolsa a fatura de qualquer conta em nome do administrador do contexto, mesmo fora do prazo de reembolso
+// e sem a aprovação da política da conta; os saldos são debitados na hora
+// Retorna ErrInvoiceNotFound, ErrInvalidStatus se a fatura não estiver aprovada, ErrTransactionAlreadyRefunded se
+// ela já foi toda reembolsada, ErrInvalidRefund se o valor passar do que falta e ErrInsufficientBalance se o saldo
+// de uma das contas debitadas não cobrir a parte dela
+func (s *RefundService) Force(ctx context.Context, invoiceID string, input dto.RefundInput) (*dto.RefundOutput, error) {
+	invoice, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.accountService.FindByID(ctx, invoice.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	refund, err := s.prepare(ctx, invoice, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.debit(ctx, invoice, refund); err != nil {
+		return nil, err
+	}
+	metrics.RefundWindowTotal.WithLabelValues("forced").Inc()
+	slog.WarnContext(ctx, "reembolso forçado pelo administrador",
+		"account_id", account.ID, "invoice_id", invoice.ID, "refund_id", refund.ID, "amount", refund.Amount,
+		"requested_by", refund.RequestedBy)
+	return dto.FromRefund(refund), nil
+}
+
+// List retorna os reembolsos da conta do API Key na situação informada, ou em todas com status vazio
+// Retorna ErrInvalidRefundDecision se a situação for desconhecida
+func (s *RefundService) List(ctx context.Context, apiKey string, status domain.RefundStatus) ([]*dto.RefundOutput, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidRefundDecision
+	}
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.refunds.List(ctx, account.ID, status, maxRefundPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromRefunds(refunds), nil
+}
+
+// Decide aprova ou recusa o reembolso pendente da conta do API Key em nome do autor do contexto
+// A aprovação debita os saldos e precisa vir de um usuário diferente de quem pediu; a recusa pode vir de
+// qualquer um, inclusive de quem pediu, para desistir do pedido
+// Retorna ErrInvalidRefundDecision para decisões desconhecidas, ErrRefundNotFound se o reembolso não for da conta,
+// ErrTransactionNotAllowed se o prazo de reembolso da conta já passou na aprovação, ErrRefundSelfApproval,
+// ErrRefundAlreadyDecided se ele não estiver mais pendente e ErrInsufficientBalance se o saldo de uma das contas
+// debitadas não cobrir a parte dela
+func (s *RefundService) Decide(ctx context.Context, apiKey, refundID string, input dto.RefundDecisionInput) (*dto.RefundOutput, error) {
+	var status domain.RefundStatus
+	switch input.Decision {
+	case "approved":
+		status = domain.RefundCompleted
+	case string(domain.RefundRejected):
+		status = domain.RefundRejected
+	default:
+		return nil, domain.ErrInvalidRefundDecision
+	}
+
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	refund, err := s.refunds.FindByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+	if refund.AccountID != account.ID {
+		return nil, domain.ErrRefundNotFound
+	}
+
+	// O prazo vale também na aprovação, para que um pedido feito no fim dele não seja aprovado depois
+	var invoice *domain.Invoice
+	if status == domain.RefundCompleted && refund.Status == domain.RefundPending {
+		if invoice, err = s.invoices.FindByID(ctx, refund.InvoiceID); err != nil {
+			return nil, err
+		}
+		if err := s.checkWindow(ctx, invoice); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := refund.Decide(status, requestctx.Actor(ctx)); err != nil {
+		return nil, err
+	}
+	if status == domain.RefundRejected {
+		if err := s.refunds.Decide(ctx, refund, nil); err != nil {
+			return nil, err
+		}
+		metrics.RefundsTotal.WithLabelValues(string(domain.RefundRejected)).Inc()
+		slog.InfoContext(ctx, "reembolso recusado", "invoice_id", refund.InvoiceID, "refund_id", refund.ID, "decided_by", refund.DecidedBy)
+		return dto.FromRefund(refund), nil
+	}
+
+	// A decisão e o débito são gravados juntos, só se o reembolso ainda estiver pendente, para que duas aprovações
+	// simultâneas não debitem duas vezes
+	posting, err := s.refundPosting(ctx, invoice, refund)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refunds.Decide(ctx, refund, posting); err != nil {
+		return nil, err
+	}
+	s.completed(ctx, refund)
+	return dto.FromRefund(refund), nil
+}
+
+// completed registra nas métricas e no log o reembolso debitado
+func (s *RefundService) completed(ctx context.Context, refund *domain.Refund) {
+	metrics.RefundsTotal.WithLabelValues(string(domain.RefundCompleted)).Inc()
+	metrics.RefundAmountTotal.Add(refund.Amount)
+	slog.InfoContext(ctx, "reembolso concluído",
+		"invoice_id", refund.InvoiceID, "refund_id", refund.ID, "amount", refund.Amount,
+		"requested_by", refund.RequestedBy, "decided_by", refund.DecidedBy)
+}
+
+// Run expira os reembolsos pendentes vencidos a cada Interval; bloqueia até o contexto ser cancelado
+func (s *RefundService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.ExpireDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ExpireDue(ctx, now)
+		}
+	}
+}
+
+// ExpireDue expira os reembolsos pendentes com prazo até now e retorna quantos foram expirados
+// As falhas vão para o log e o reembolso volta na próxima verificação
+func (s *RefundService) ExpireDue(ctx context.Context, now time.Time) int {
+	ctx = requestctx.WithActor(ctx, refundExpiryActor)
+	expired := 0
+	for {
+		refunds, err := s.refunds.ListExpired(ctx, now, refundExpiryBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar os reembolsos pendentes vencidos", "error", err)
+			return expired
+		}
+
+		progress := false
+		for _, refund := range refunds {
+			if err := refund.Decide(domain.RefundExpired, refundExpiryActor); err != nil {
+				continue
+			}
+			err := s.refunds.Decide(ctx, refund, nil)
+			switch {
+			case err == nil:
+				expired++
+				progress = true
+				metrics.RefundsTotal.WithLabelValues(string(domain.RefundExpired)).Inc()
+				slog.InfoContext(ctx, "reembolso expirado sem aprovação",
+					"account_id", refund.AccountID, "invoice_id", refund.InvoiceID, "refund_id", refund.ID)
+			case errors.Is(err, domain.ErrRefundAlreadyDecided):
+				progress = true
+			default:
+				slog.ErrorContext(ctx, "erro ao expirar o reembolso pendente", "refund_id", refund.ID, "error", err)
+			}
+		}
+		// Um lote sem nenhum reembolso expirado se repetiria igual; os restantes ficam para a próxima verificação
+		if len(refunds) < refundExpiryBatch || !progress {
+			return expired
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// RetentionService aplica a política de retenção: arquiva faturas antigas e remove a auditoria expirada
+type RetentionService struct {
+	invoiceRepository domain.RetentionRepository
+	auditRepository   domain.RetentionRepository
+}
+
+// NewRetentionService cria um novo serviço de retenção
+func NewRetentionService(invoiceRepository, auditRepository domain.RetentionRepository) *RetentionService {
+	return &RetentionService{
+		invoiceRepository: invoiceRepository,
+		auditRepository:   auditRepository,
+	}
+}
+
+// Apply arquiva as faturas criadas antes de invoicesBefore e remove a auditoria anterior a auditLogsBefore
+// Em dryRun apenas conta os registros afetados
+func (s *RetentionService) Apply(ctx context.Context, invoicesBefore, auditLogsBefore time.Time, dryRun bool) (*dto.RetentionOutput, error) {
+	output := &dto.RetentionOutput{
+		InvoicesBefore:  invoicesBefore,
+		AuditLogsBefore: auditLogsBefore,
+		DryRun:          dryRun,
+	}
+
+	run := func(repository domain.RetentionRepository, before time.Time) (int64, error) {
+		if dryRun {
+			return repository.CountExpired(ctx, before)
+		}
+		return repository.RemoveExpired(ctx, before)
+	}
+
+	var err error
+	if output.ArchivedInvoices, err = run(s.invoiceRepository, invoicesBefore); err != nil {
+		return nil, err
+	}
+	if output.PrunedAuditLogs, err = run(s.auditRepository, auditLogsBefore); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "política de retenção aplicada",
+		"invoices_before", invoicesBefore,
+		"audit_logs_before", auditLogsBefore,
+		"dry_run", dryRun,
+		"archived_invoices", output.ArchivedInvoices,
+		"pruned_audit_logs", output.PrunedAuditLogs)
+
+	return output, nil
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// RiskReviewConfig configura os limites padrão de score e o prazo da fila de revisão manual
+type RiskReviewConfig struct {
+	// Defaults são os limites das contas que não definiram os próprios
+	Defaults domain.RiskPolicy
+	// SLA é o prazo de cada revisão; vencido, a revisão é decidida com SLADecision
+	SLA         time.Duration
+	SLADecision domain.ReviewStatus
+	// Interval é a frequência com que os prazos vencidos são procurados
+	Interval time.Duration
+}
+
+// reviewSLAActor identifica na auditoria e nas revisões as decisões tomadas pelo prazo
+const reviewSLAActor = "system:review-sla"
+
+// Origens das decisões das revisões em /metrics
+const (
+	reviewSourceAnalyst = "analyst"
+	reviewSourceSLA     = "sla"
+)
+
+// Limites das listagens da fila e das revisões vencidas decididas a cada verificação
+const (
+	maxReviewPageSize = 500
+	reviewDueBatch    = 100
+)
+
+// RiskReviewService aplica os limites de score de risco de cada conta aos resultados do antifraude
+// Os scores entre os limites deixam a fatura pendente na fila de revisão manual, decidida por um analista ou,
+// vencido o prazo, pela decisão configurada
+type RiskReviewService struct {
+	policies       domain.RiskPolicyRepository
+	reviews        domain.ReviewRepository
+	invoices       domain.InvoiceRepository
+	invoiceService *InvoiceService
+	accountService *AccountService
+	config         RiskReviewConfig
+}
+
+// NewRiskReviewService cria o serviço de limites de score e da fila de revisão
+func NewRiskReviewService(policies domain.RiskPolicyRepository, reviews domain.ReviewRepository, invoices domain.InvoiceRepository, invoiceService *InvoiceService, accountService *AccountService, config RiskReviewConfig) *RiskReviewService {
+	return &RiskReviewService{
+		policies:       policies,
+		reviews:        reviews,
+		invoices:       invoices,
+		invoiceService: invoiceService,
+		accountService: accountService,
+		config:         config,
+	}
+}
+
+// ProcessScore decide a fatura pelo score de risco enviado pelo antifraude e pelos limites da conta
+// Retorna ErrInvalidStatus se a fatura já tiver sido decidida; um score repetido da mesma fatura em revisão é ignorado
+func (s *RiskReviewService) ProcessScore(ctx context.Context, invoiceID string, score float64) error {
+	invoice, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	policy, err := s.policy(ctx, invoice.AccountID)
+	if err != nil {
+		return err
+	}
+
+	decision := policy.Decide(score)
+	metrics.RiskDecisionsTotal.WithLabelValues(string(decision)).Inc()
+	switch decision {
+	case domain.RiskApprove:
+		return s.invoiceService.ProcessTransactionResult(ctx, invoiceID, domain.StatusApproved)
+	case domain.RiskReject:
+		return s.invoiceService.ProcessTransactionResult(ctx, invoiceID, domain.StatusRejected)
+	}
+
+	if invoice.Status != domain.StatusPending {
+		return domain.ErrInvalidStatus
+	}
+	review := domain.NewInvoiceReview(invoice, score, s.config.SLA)
+	if err := s.reviews.Create(ctx, review); err != nil {
+		if err == domain.ErrReviewExists {
+			return nil
+		}
+		return err
+	}
+	slog.InfoContext(ctx, "fatura enviada para revisão manual",
+		"account_id", invoice.AccountID, "score", score, "due_at", review.DueAt)
+	return nil
+}
+
+// policy retorna os limites da conta ou, se ela não definiu os próprios, os padrão
+func (s *RiskReviewService) policy(ctx context.Context, accountID string) (*domain.RiskPolicy, error) {
+	policy, err := s.policies.FindByAccountID(ctx, accountID)
+	if err == domain.ErrRiskPolicyNotFound {
+		defaults := s.config.Defaults
+		defaults.AccountID = accountID
+		return &defaults, nil
+	}
+	return policy, err
+}
+
+// GetPolicy retorna os limites de score da conta do API Key; sem limites próprios, os padrão
+func (s *RiskReviewService) GetPolicy(ctx context.Context, apiKey string) (*dto.RiskPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.policy(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromRiskPolicy(policy)
+	return &output, nil
+}
+
+// UpdatePolicy substitui os limites de score da conta do API Key
+// Retorna ErrInvalidRiskPolicy se os limites forem inválidos
+func (s *RiskReviewService) UpdatePolicy(ctx context.Context, apiKey string, input dto.RiskPolicyInput) (*dto.RiskPolicyOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := domain.NewRiskPolicy(account.ID, input.ApproveBelow, input.RejectAbove)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policies.Save(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromRiskPolicy(policy)
+	return &output, nil
+}
+
+// List retorna a fila de revisão da conta do API Key na situação informada, ou em todas com status vazio
+// Retorna ErrInvalidReviewDecision se a situação for desconhecida
+func (s *RiskReviewService) List(ctx context.Context, apiKey string, status domain.ReviewStatus) ([]*dto.ReviewOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListAll(ctx, account.ID, status)
+}
+
+// ListAll retorna a fila de revisão da conta informada, ou de todas com accountID vazio, das mais antigas para
+// as mais novas
+// Retorna ErrInvalidReviewDecision se a situação for desconhecida
+func (s *RiskReviewService) ListAll(ctx context.Context, accountID string, status domain.ReviewStatus) ([]*dto.ReviewOutput, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidReviewDecision
+	}
+	reviews, err := s.reviews.List(ctx, accountID, status, maxReviewPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromInvoiceReviews(reviews), nil
+}
+
+// Decide aplica a decisão do analista do contexto à revisão da fatura
+// Retorna ErrInvalidReviewDecision se a decisão não for aprovar nem recusar, ErrReviewNotFound se a fatura não
+// estiver na fila e ErrReviewAlreadyDecided se outra decisão chegou antes
+func (s *RiskReviewService) Decide(ctx context.Context, invoiceID string, input dto.ReviewDecisionInput) (*dto.ReviewOutput, error) {
+	status := domain.ReviewStatus(input.Decision)
+	if status != domain.ReviewApproved && status != domain.ReviewDeclined {
+		return nil, domain.ErrInvalidReviewDecision
+	}
+
+	review, err := s.reviews.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decide(ctx, review, status, requestctx.Actor(ctx), reviewSourceAnalyst); err != nil {
+		return nil, err
+	}
+	return dto.FromInvoiceReview(review), nil
+}
+
+// decide aplica a decisão à fatura e fecha a revisão
+// A fatura decide quem chegou primeiro: se ela já saiu de pending, a revisão é fechada com o status dela e a
+// decisão é recusada com ErrReviewAlreadyDecided
+func (s *RiskReviewService) decide(ctx context.Context, review *domain.InvoiceReview, status domain.ReviewStatus, decidedBy, source string) error {
+	if review.Status != domain.ReviewOpen {
+		return domain.ErrReviewAlreadyDecided
+	}
+
+	err := s.invoiceService.ProcessReviewDecision(ctx, review.InvoiceID, status.InvoiceStatus())
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidStatus):
+		invoice, findErr := s.invoices.FindByID(ctx, review.InvoiceID)
+		if findErr != nil {
+			return findErr
+		}
+		// Reembolsada ou estornada, a fatura foi aprovada antes
+		current := domain.ReviewApproved
+		if invoice.Status == domain.StatusRejected {
+			current = domain.ReviewDeclined
+		}
+		s.close(ctx, review, current, decidedBy)
+		return domain.ErrReviewAlreadyDecided
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		// A fatura foi excluída; a revisão é fechada para não voltar à fila
+		s.close(ctx, review, domain.ReviewDeclined, decidedBy)
+		return err
+	default:
+		return err
+	}
+
+	if err := s.close(ctx, review, status, decidedBy); err != nil {
+		return err
+	}
+	metrics.ReviewDecisionsTotal.WithLabelValues(string(status), source).Inc()
+	slog.InfoContext(ctx, "revisão manual decidida",
+		"account_id", review.AccountID, "invoice_id", review.InvoiceID, "status", status, "decided_by", decidedBy)
+	return nil
+}
+
+// close grava a decisão da revisão; a falha vai para o log, já que a fatura já foi decidida
+func (s *RiskReviewService) close(ctx context.Context, review *domain.InvoiceReview, status domain.ReviewStatus, decidedBy string) error {
+	if err := review.Decide(status, decidedBy); err != nil {
+		return err
+	}
+	err := s.reviews.Decide(ctx, review)
+	if err != nil && err != domain.ErrReviewAlreadyDecided {
+		slog.ErrorContext(ctx, "erro ao fechar a revisão manual", "invoice_id", review.InvoiceID, "error", err)
+	}
+	return nil
+}
+
+// Run decide as revisões vencidas a cada Interval; bloqueia até o contexto ser cancelado
+func (s *RiskReviewService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.ExpireDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ExpireDue(ctx, now)
+		}
+	}
+}
+
+// ExpireDue aplica SLADecision às revisões abertas com prazo até now e retorna quantas foram decididas
+// As falhas vão para o log e a revisão volta na próxima verificação
+func (s *RiskReviewService) ExpireDue(ctx context.Context, now time.Time) int {
+	ctx = requestctx.WithActor(ctx, reviewSLAActor)
+	decided := 0
+	for {
+		reviews, err := s.reviews.ListDue(ctx, now, reviewDueBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar as revisões manuais vencidas", "error", err)
+			return decided
+		}
+
+		progress := false
+		for _, review := range reviews {
+			err := s.decide(ctx, review, s.config.SLADecision, reviewSLAActor, reviewSourceSLA)
+			switch {
+			case err == nil:
+				decided++
+				progress = true
+			case errors.Is(err, domain.ErrReviewAlreadyDecided), errors.Is(err, domain.ErrInvoiceNotFound):
+				progress = true
+			default:
+				slog.ErrorContext(ctx, "erro ao decidir a revisão manual vencida", "invoice_id", review.InvoiceID, "error", err)
+			}
+		}
+		// Um lote sem nenhuma revisão fechada se repetiria igual; as restantes ficam para a próxima verificação
+		if len(reviews) < reviewDueBatch || !progress {
+			return decided
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// maxAuthEventField limita o tamanho dos campos vindos do cliente, como o User-Agent
+const maxAuthEventField = 512
+
+// SecurityConfig reúne os limites do SecurityService; valores zero desativam o recurso correspondente
+type SecurityConfig struct {
+	// AlertFailedIPs é quantos IPs distintos falhando com a mesma credencial dentro de AlertWindow geram um alerta
+	AlertFailedIPs int
+	AlertWindow    time.Duration
+	// LockoutThreshold é quantas falhas consecutivas bloqueiam o IP, ou o API Key pelo prefixo, durante LockoutCooloff
+	LockoutThreshold int
+	LockoutCooloff   time.Duration
+}
+
+// failureCount acompanha as falhas consecutivas de um IP ou prefixo de API Key
+type failureCount struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// SecurityService registra as tentativas de autenticação, bloqueia temporariamente origens com falhas
+// consecutivas e alerta sobre padrões suspeitos
+// Os contadores de bloqueio vivem na memória do processo; com várias instâncias cada uma conta as falhas que recebeu
+type SecurityService struct {
+	events         domain.AuthEventRepository
+	accountService *AccountService
+	notifier       *SecurityWebhookService
+	// config é trocado de uma vez por SetConfig; cada operação lê os limites uma única vez
+	config atomic.Pointer[SecurityConfig]
+
+	mu sync.Mutex
+	// alerted guarda o último alerta de cada credencial, para alertar no máximo uma vez por janela
+	alerted   map[string]time.Time
+	failures  map[string]*failureCount
+	lastSweep time.Time
+}
+
+// NewSecurityService cria o serviço de eventos de autenticação com os limites informados
+// Alertas e bloqueios de credenciais de uma conta conhecida vão para o webhook de segurança dela pelo notifier
+func NewSecurityService(events domain.AuthEventRepository, accountService *AccountService, notifier *SecurityWebhookService, config SecurityConfig) *SecurityService {
+	s := &SecurityService{
+		events:         events,
+		accountService: accountService,
+		notifier:       notifier,
+		alerted:        make(map[string]time.Time),
+		failures:       make(map[string]*failureCount),
+		lastSweep:      time.Now(),
+	}
+	s.SetConfig(config)
+	return s
+}
+
+// SetConfig troca os limites de alerta e de bloqueio sem descartar as falhas já contadas
+func (s *SecurityService) SetConfig(config SecurityConfig) {
+	s.config.Store(&config)
+}
+
+// Record grava a tentativa de autenticação com a origem da requisição guardada no contexto e atualiza os bloqueios
+// Falhas na gravação só vão para o log, para que a trilha não bloqueie a autenticação
+func (s *SecurityService) Record(ctx context.Context, event domain.AuthEvent) {
+	client := requestctx.ClientInfo(ctx)
+	event.ID = domain.NewID()
+	event.IP = client.IP
+	event.UserAgent = clip(client.UserAgent)
+	event.Route = clip(client.Route)
+	event.Reason = clip(event.Reason)
+	event.CreatedAt = time.Now()
+
+	result := "success"
+	if !event.Success {
+		result = "failure"
+	}
+	metrics.AuthAttemptsTotal.WithLabelValues(string(event.Method), result).Inc()
+	s.trackFailures(ctx, &event)
+
+	if err := s.events.Save(ctx, &event); err != nil {
+		slog.ErrorContext(ctx, "erro ao registrar evento de autenticação", "error", err, "method", event.Method, "success", event.Success)
+		return
+	}
+
+	if config := s.config.Load(); !event.Success && event.KeyID != "" && config.AlertFailedIPs > 0 {
+		s.alertFailedIPs(ctx, &event, config)
+	}
+}
+
+// alertFailedIPs alerta quando a credencial do evento acumula falhas de muitos IPs distintos na janela,
+// sinal de tentativas distribuídas contra a mesma chave ou usuário
+func (s *SecurityService) alertFailedIPs(ctx context.Context, event *domain.AuthEvent, config *SecurityConfig) {
+	count, err := s.events.CountFailedIPs(ctx, event.KeyID, event.CreatedAt.Add(-config.AlertWindow))
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao contar falhas de autenticação", "error", err, "key_id", event.KeyID)
+		return
+	}
+	if count < config.AlertFailedIPs {
+		return
+	}
+
+	s.mu.Lock()
+	if last, ok := s.alerted[event.KeyID]; ok && event.CreatedAt.Sub(last) < config.AlertWindow {
+		s.mu.Unlock()
+		return
+	}
+	s.alerted[event.KeyID] = event.CreatedAt
+	for keyID, last := range s.alerted {
+		if event.CreatedAt.Sub(last) >= config.AlertWindow {
+			delete(s.alerted, keyID)
+		}
+	}
+	s.mu.Unlock()
+
+	metrics.SuspiciousAuthTotal.WithLabelValues("failures_from_many_ips").Inc()
+	slog.WarnContext(ctx, "atividade suspeita de autenticação",
+		"pattern", "failures_from_many_ips",
+		"key_id", event.KeyID,
+		"account_id", event.AccountID,
+		"method", event.Method,
+		"ips", count,
+		"window", config.AlertWindow)
+	s.notifier.Notify(ctx, SecurityEvent{
+		AccountID: event.AccountID,
+		Type:      domain.SecurityEventFailedAuthSpike,
+		Key:       event.KeyID,
+		Data: map[string]any{
+			"key_id": event.KeyID,
+			"method": event.Method,
+			"ips":    count,
+			"window": config.AlertWindow.String(),
+		},
+	})
+}
+
+// lockoutKeys retorna as chaves dos contadores de bloqueio do evento: o IP e, nas tentativas por API Key
+// e por código do segundo fator, a credencial
+// A credencial tem contador próprio porque o segundo fator é conferido depois de uma autenticação bem-sucedida,
+// que zera o contador do IP
+func lockoutKeys(ip string, method domain.AuthMethod, keyID string) []string {
+	var keys []string
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	if (method == domain.AuthMethodAPIKey || method == domain.AuthMethodTOTP) && keyID != "" {
+		keys = append(keys, "key:"+keyID)
+	}
+	return keys
+}
+
+// trackFailures conta as falhas consecutivas do IP e do prefixo do API Key do evento, bloqueando-os ao atingir
+// o limite; um sucesso zera os contadores
+// O bloqueio de uma credencial de conta conhecida, como o segundo fator, vai para o webhook de segurança da conta
+func (s *SecurityService) trackFailures(ctx context.Context, event *domain.AuthEvent) {
+	config := s.config.Load()
+	if config.LockoutThreshold <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweepFailures(event.CreatedAt, config.LockoutCooloff)
+
+	for _, key := range lockoutKeys(event.IP, event.Method, event.KeyID) {
+		if event.Success {
+			delete(s.failures, key)
+			continue
+		}
+
+		failure, ok := s.failures[key]
+		if !ok {
+			failure = &failureCount{}
+			s.failures[key] = failure
+		}
+		failure.count++
+		failure.lastFailure = event.CreatedAt
+		if failure.count < config.LockoutThreshold || event.CreatedAt.Before(failure.lockedUntil) {
+			continue
+		}
+
+		// O bloqueio recomeça a contagem, para que a próxima sequência de falhas bloqueie de novo
+		failure.count = 0
+		failure.lockedUntil = event.CreatedAt.Add(config.LockoutCooloff)
+
+		scope, value, _ := strings.Cut(key, ":")
+		metrics.SuspiciousAuthTotal.WithLabelValues(scope + "_lockout").Inc()
+		slog.WarnContext(ctx, "autenticação bloqueada por falhas consecutivas",
+			"pattern", scope+"_lockout",
+			scope, value,
+			"method", event.Method,
+			"failures", config.LockoutThreshold,
+			"locked_until", failure.lockedUntil)
+		if scope == "key" {
+			s.notifier.Notify(ctx, SecurityEvent{
+				AccountID: event.AccountID,
+				Type:      domain.SecurityEventCredentialLocked,
+				Key:       value,
+				Data: map[string]any{
+					"key_id":       value,
+					"method":       event.Method,
+					"failures":     config.LockoutThreshold,
+					"locked_until": failure.lockedUntil,
+				},
+			})
+		}
+	}
+}
+
+// sweepFailures descarta os contadores sem falhas recentes e já desbloqueados; deve ser chamado com o lock
+func (s *SecurityService) sweepFailures(now time.Time, cooloff time.Duration) {
+	if now.Sub(s.lastSweep) < cooloff {
+		return
+	}
+	for key, failure := range s.failures {
+		if now.Sub(failure.lastFailure) >= cooloff && now.After(failure.lockedUntil) {
+			delete(s.failures, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// CheckLockout verifica se o IP da requisição ou, nas tentativas por API Key e segundo fator, a credencial estão bloqueados
+// Retorna ErrClientBlocked, ErrAPIKeyLocked ou ErrTwoFactorLocked com o tempo restante do bloqueio
+func (s *SecurityService) CheckLockout(ctx context.Context, method domain.AuthMethod, keyID string) (time.Duration, error) {
+	if s.config.Load().LockoutThreshold <= 0 {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range lockoutKeys(requestctx.ClientInfo(ctx).IP, method, keyID) {
+		failure, ok := s.failures[key]
+		if !ok || !now.Before(failure.lockedUntil) {
+			continue
+		}
+
+		scope, _, _ := strings.Cut(key, ":")
+		metrics.AuthBlockedTotal.WithLabelValues(scope).Inc()
+		if scope == "key" && method == domain.AuthMethodTOTP {
+			return failure.lockedUntil.Sub(now), domain.ErrTwoFactorLocked
+		}
+		if scope == "key" {
+			return failure.lockedUntil.Sub(now), domain.ErrAPIKeyLocked
+		}
+		return failure.lockedUntil.Sub(now), domain.ErrClientBlocked
+	}
+	return 0, nil
+}
+
+// ListEvents busca uma página dos eventos de autenticação da conta do API Key
+// NextCursor aponta para a próxima página; retorna ErrInvalidCursor se o cursor estiver malformado
+func (s *SecurityService) ListEvents(ctx context.Context, apiKey string, input dto.AuthEventFilterInput) (*dto.AuthEventPage, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := dto.DecodeCursor(input.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	limit = min(limit, maxAuditLimit)
+
+	// Um item extra indica se existe próxima página
+	events, err := s.events.FindByFilter(ctx, domain.AuthEventFilter{
+		AccountID: account.ID,
+		Success:   input.Success,
+		Limit:     limit + 1,
+		After:     after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.AuthEventPage{}
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		page.NextCursor = dto.EncodeCursor(domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	page.Events = make([]*dto.AuthEventOutput, len(events))
+	for i, event := range events {
+		page.Events[i] = dto.FromAuthEvent(event)
+	}
+	return page, nil
+}
+
+// clip corta valores longos e troca bytes inválidos, que os bancos recusariam em colunas de texto
+func clip(value string) string {
+	if len(value) > maxAuthEventField {
+		value = value[:maxAuthEventField]
+	}
+	return strings.ToValidUTF8(value, "")
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+)
+
+// securityEventRepeatWindow é o intervalo mínimo entre dois eventos iguais para a mesma conta, para que
+// uma origem insistente não inunde o webhook do lojista
+const securityEventRepeatWindow = 15 * time.Minute
+
+// SecurityEvent é um evento de segurança de uma conta a entregar no webhook que ela assinou
+// Key distingue ocorrências do mesmo tipo, como a credencial ou o país; eventos com a mesma conta, tipo e Key
+// são entregues no máximo uma vez por securityEventRepeatWindow
+type SecurityEvent struct {
+	AccountID string
+	Type      domain.SecurityEventType
+	Key       string
+	Data      map[string]any
+}
+
+// SecurityWebhookService gerencia as assinaturas dos eventos de segurança das contas e entrega os eventos pelo
+// mesmo formato e assinatura dos demais webhooks do gateway, com o API Key da conta como segredo
+// As entregas são assíncronas e não são repetidas; falhas vão para o log e para /metrics
+type SecurityWebhookService struct {
+	webhooks       domain.SecurityWebhookRepository
+	accountService *AccountService
+	timeout        time.Duration
+
+	mu sync.Mutex
+	// sent guarda a última entrega de cada evento, para descartar as repetições
+	sent      map[string]time.Time
+	lastSweep time.Time
+
+	// deliveries acompanha as entregas em andamento, esperadas por Drain, e pending conta quantas são
+	deliveries sync.WaitGroup
+	pending    atomic.Int64
+}
+
+// NewSecurityWebhookService cria o serviço de webhooks de segurança; timeout limita cada entrega
+func NewSecurityWebhookService(webhooks domain.SecurityWebhookRepository, accountService *AccountService, timeout time.Duration) *SecurityWebhookService {
+	return &SecurityWebhookService{
+		webhooks:       webhooks,
+		accountService: accountService,
+		timeout:        timeout,
+		sent:           make(map[string]time.Time),
+		lastSweep:      time.Now(),
+	}
+}
+
+// Get retorna a assinatura da conta do API Key
+// Retorna ErrSecurityWebhookNotFound quando a conta não assinou os eventos
+func (s *SecurityWebhookService) Get(ctx context.Context, apiKey string) (*dto.SecurityWebhookOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.webhooks.FindByAccountID(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	output := dto.FromSecurityWebhook(webhook)
+	return &output, nil
+}
+
+// Update substitui a assinatura da conta do API Key
+func (s *SecurityWebhookService) Update(ctx context.Context, apiKey string, input dto.SecurityWebhookInput) (*dto.SecurityWebhookOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.SecurityEventType, len(input.Events))
+	for i, event := range input.Events {
+		events[i] = domain.SecurityEventType(event)
+	}
+	webhook, err := domain.NewSecurityWebhook(account.ID, input.URL, events)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhooks.Save(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromSecurityWebhook(webhook)
+	return &output, nil
+}
+
+// Delete cancela a assinatura da conta do API Key
+func (s *SecurityWebhookService) Delete(ctx context.Context, apiKey string) error {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return err
+	}
+	return s.webhooks.Delete(ctx, account.ID)
+}
+
+// Notify entrega o evento em segundo plano, se a conta assinou o tipo dele, sem atrasar quem o gerou
+// O contexto só fornece os valores da requisição, como o request ID; o cancelamento dele não interrompe a entrega
+func (s *SecurityWebhookService) Notify(ctx context.Context, event SecurityEvent) {
+	if event.AccountID == "" || !s.firstInWindow(event, time.Now()) {
+		return
+	}
+	s.deliveries.Add(1)
+	s.pending.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		defer s.pending.Add(-1)
+		s.deliver(context.WithoutCancel(ctx), event)
+	}()
+}
+
+// Pending retorna quantas entregas estão em andamento
+func (s *SecurityWebhookService) Pending() int {
+	return int(s.pending.Load())
+}
+
+// Drain espera as entregas em andamento terminarem ou o contexto acabar
+// Usado no desligamento, depois que as requisições e os consumidores pararam de gerar eventos
+func (s *SecurityWebhookService) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// firstInWindow registra o evento e indica se ele não foi entregue na janela de repetição
+func (s *SecurityWebhookService) firstInWindow(event SecurityEvent, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= securityEventRepeatWindow {
+		for key, sentAt := range s.sent {
+			if now.Sub(sentAt) >= securityEventRepeatWindow {
+				delete(s.sent, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	key := event.AccountID + "|" + string(event.Type) + "|" + event.Key
+	if sentAt, ok := s.sent[key]; ok && now.Sub(sentAt) < securityEventRepeatWindow {
+		return false
+	}
+	s.sent[key] = now
+	return true
+}
+
+// deliver busca a assinatura da conta e envia o evento, assinado com o API Key da conta
+func (s *SecurityWebhookService) deliver(ctx context.Context, event SecurityEvent) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	webhook, err := s.webhooks.FindByAccountID(ctx, event.AccountID)
+	if err == domain.ErrSecurityWebhookNotFound {
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao buscar o webhook de segurança da conta", "error", err, "account_id", event.AccountID)
+		return
+	}
+	if !webhook.Subscribed(event.Type) {
+		return
+	}
+
+	account, err := s.accountService.FindByID(ctx, event.AccountID)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao buscar a conta do webhook de segurança", "error", err, "account_id", event.AccountID)
+		return
+	}
+
+	data := make(map[string]any, len(event.Data)+1)
+	for key, value := range event.Data {
+		data[key] = value
+	}
+	data["account_id"] = event.AccountID
+
+	err = notify.NewWebhook(webhook.URL, account.APIKey, s.timeout).Send(ctx, notify.Event{
+		Type:      string(event.Type),
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	metrics.ObserveWebhookDelivery("merchant_webhook", err)
+	if err != nil {
+		metrics.AlertDeliveryErrorsTotal.WithLabelValues("merchant_webhook").Inc()
+		slog.ErrorContext(ctx, "erro ao enviar webhook de segurança da conta", "error", err, "account_id", event.AccountID, "type", event.Type)
+	}
+}
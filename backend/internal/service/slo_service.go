@@ -0,0 +1,63 @@
+package service
+
+import (
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// SLOService acompanha o objetivo de latência das rotas e informa quanto do orçamento está sendo consumido
+type SLOService struct {
+	tracker *metrics.LatencyTracker
+}
+
+// NewSLOService cria um novo serviço do objetivo de latência
+func NewSLOService(tracker *metrics.LatencyTracker) *SLOService {
+	return &SLOService{tracker: tracker}
+}
+
+// Observe registra a duração de uma requisição atendida pela rota
+func (s *SLOService) Observe(route string, elapsed time.Duration) {
+	s.tracker.Observe(route, elapsed)
+}
+
+// Report informa p95, p99 e o consumo do orçamento de cada rota nas janelas curta e longa
+// Fica "critical" quando as duas janelas consomem o orçamento acima da taxa rápida, o que esgota o orçamento
+// da janela longa em poucos minutos, e "warning" quando a janela longa o consome mais rápido que o permitido
+func (s *SLOService) Report() *dto.SLOReportOutput {
+	report := s.tracker.Report()
+	fastBurn := report.Objective.FastBurnRate
+
+	output := &dto.SLOReportOutput{
+		Status:      sloStatus(report.OverallShort, report.Overall, fastBurn),
+		Objective:   dto.FromLatencyObjective(report.Objective),
+		Window:      report.Window.String(),
+		ShortWindow: report.ShortWindow.String(),
+		Short:       dto.FromLatencyWindow(report.OverallShort),
+		Long:        dto.FromLatencyWindow(report.Overall),
+		Routes:      make([]dto.RouteSLOOutput, len(report.Routes)),
+	}
+	for i, route := range report.Routes {
+		output.Routes[i] = dto.RouteSLOOutput{
+			Route:       route.Route,
+			Status:      sloStatus(route.Short, route.Long, fastBurn),
+			ThresholdMs: float64(route.Threshold.Microseconds()) / 1000,
+			Short:       dto.FromLatencyWindow(route.Short),
+			Long:        dto.FromLatencyWindow(route.Long),
+		}
+	}
+	return output
+}
+
+// sloStatus classifica o consumo do orçamento a partir das janelas curta e longa
+// A janela curta evita alertas de um pico que já passou; a longa, de poucas requisições lentas isoladas
+func sloStatus(short, long metrics.LatencyWindow, fastBurn float64) string {
+	switch {
+	case short.BurnRate >= fastBurn && long.BurnRate >= fastBurn:
+		return dto.SLOCritical
+	case long.BurnRate > 1:
+		return dto.SLOWarning
+	}
+	return dto.SLOOK
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// SplitService divide o valor das faturas entre contas recebedoras e credita o saldo delas na aprovação, ou retém
+// o valor em custódia quando a fatura pediu
+// A taxa de divisão, feePercent do valor das faturas divididas, é rateada entre as partes e fica com o gateway
+type SplitService struct {
+	splits         domain.SplitRepository
+	accountService *AccountService
+	platforms      *PlatformService
+	ledger         *LedgerService
+	escrow         *EscrowService
+	payouts        *PayoutScheduleService
+	feePercent     float64
+}
+
+// NewSplitService cria o serviço de divisão das faturas
+// Nos créditos, platforms retém a comissão das plataformas sobre as contas filhas, registrada em ledger, e escrow
+// guarda o valor das faturas em custódia
+func NewSplitService(splits domain.SplitRepository, accountService *AccountService, platforms *PlatformService, ledger *LedgerService, escrow *EscrowService, payouts *PayoutScheduleService, feePercent float64) *SplitService {
+	return &SplitService{splits: splits, accountService: accountService, platforms: platforms, ledger: ledger, escrow: escrow, payouts: payouts, feePercent: feePercent}
+}
+
+// Plan calcula as partes da fatura da conta pelas regras de divisão; sem regras a fatura não é dividida e retorna nil
+// Retorna ErrInvalidSplit se as regras forem inválidas ou alguma conta recebedora não existir, for de outra
+// organização ou de outro modo
+func (s *SplitService) Plan(ctx context.Context, account *dto.AccountOutput, invoice *domain.Invoice, rules []dto.SplitRuleInput) ([]*domain.InvoiceSplit, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	splits, err := domain.NewInvoiceSplits(invoice, dto.ToSplitRules(rules), s.feePercent)
+	if err != nil {
+		return nil, err
+	}
+	for _, split := range splits {
+		recipient, err := s.accountService.FindByID(ctx, split.RecipientID)
+		if errors.Is(err, domain.ErrAccountNotFound) || (err == nil && !sameLedger(account, recipient)) {
+			return nil, domain.ErrInvalidSplit
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return splits, nil
+}
+
+// sameLedger informa se as contas podem trocar saldo: as duas na mesma organização e no mesmo modo, para que o
+// valor das faturas de teste nunca chegue a uma conta real
+func sameLedger(a, b *dto.AccountOutput) bool {
+	return a.TenantID == b.TenantID && a.TestMode == b.TestMode
+}
+
+// Save grava as partes de uma ou mais faturas
+func (s *SplitService) Save(ctx context.Context, splits []*domain.InvoiceSplit) error {
+	if len(splits) == 0 {
+		return nil
+	}
+	return s.splits.SaveBatch(ctx, splits)
+}
+
+// Find retorna as partes da fatura, ou nenhuma se ela não foi dividida
+func (s *SplitService) Find(ctx context.Context, invoiceID string) ([]*domain.InvoiceSplit, error) {
+	return s.splits.FindByInvoiceID(ctx, invoiceID)
+}
+
+// Payouts retorna quanto cada conta recebe pela fatura aprovada: a conta dona e as recebedoras, descontada a
+// comissão das plataformas, e as plataformas pela comissão, pelos vínculos atuais
+func (s *SplitService) Payouts(ctx context.Context, invoice *domain.Invoice) (map[string]float64, error) {
+	splits, err := s.Find(ctx, invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	payouts := []map[string]float64{domain.SplitPayouts(invoice, splits)}
+	if _, err := s.platforms.Withhold(ctx, []*domain.Invoice{invoice}, payouts); err != nil {
+		return nil, err
+	}
+	return payouts[0], nil
+}
+
+// Credit credita o saldo das contas com o valor das faturas aprovadas
+// splits[i] são as partes de invoices[i], vazias quando a fatura não foi dividida e a conta dona recebe tudo
+// O razão, as retenções e os saldos são gravados em uma única transação: se uma parte falhar, nada é creditado
+func (s *SplitService) Credit(ctx context.Context, invoices []*domain.Invoice, splits [][]*domain.InvoiceSplit) error {
+	posting, err := s.Posting(ctx, invoices, splits)
+	if err != nil {
+		return err
+	}
+	if err := s.ledger.Post(ctx, posting); err != nil {
+		return err
+	}
+	s.Posted(posting, splits)
+	return nil
+}
+
+// Posting monta, sem gravar, o crédito das faturas aprovadas, para que o repositório das faturas o grave na mesma
+// transação da fatura; splits[i] são as partes de invoices[i], como em Credit
+// As comissões das plataformas são retidas e gravadas no razão junto com os próprios créditos; o que cabe a cada
+// conta pelas faturas em custódia fica retido até a liberação, fora do saldo, e o das demais faturas até a data de
+// repasse do prazo da conta
+func (s *SplitService) Posting(ctx context.Context, invoices []*domain.Invoice, splits [][]*domain.InvoiceSplit) (*domain.Posting, error) {
+	invoicePayouts := make([]map[string]float64, len(invoices))
+	for i, invoice := range invoices {
+		invoicePayouts[i] = domain.SplitPayouts(invoice, splits[i])
+	}
+
+	entries, err := s.platforms.Withhold(ctx, invoices, invoicePayouts)
+	if err != nil {
+		return nil, err
+	}
+	// Os impostos ficam registrados no extrato da conta dona, que os recolhe
+	for _, invoice := range invoices {
+		if tax := invoice.TaxAmount(); tax > 0 {
+			entries = append(entries, domain.NewLedgerEntry(invoice.AccountID, "", invoice.ID, domain.LedgerTax, tax))
+		}
+	}
+	payouts := make(map[string]float64)
+	schedules := make(map[string]*domain.PayoutSchedule)
+	var holds []*domain.EscrowHold
+	now := time.Now()
+	for i, invoicePayout := range invoicePayouts {
+		days := invoices[i].EscrowDays()
+		for accountID, amount := range invoicePayout {
+			if days > 0 {
+				if amount > 0 {
+					holds = append(holds, domain.NewEscrowHold(accountID, invoices[i].ID, amount, now.AddDate(0, 0, days)))
+				}
+				continue
+			}
+
+			schedule, ok := schedules[accountID]
+			if !ok {
+				if schedule, err = s.payouts.Schedule(ctx, accountID); err != nil {
+					return nil, err
+				}
+				schedules[accountID] = schedule
+			}
+			// Só os créditos esperam a data de repasse
+			if schedule.DelayDays == 0 || amount <= 0 {
+				payouts[accountID] += amount
+				if amount != 0 {
+					entries = append(entries, domain.NewLedgerEntry(accountID, "", invoices[i].ID, domain.LedgerCredit, amount))
+				}
+				continue
+			}
+			holds = append(holds, domain.NewPayoutHold(accountID, invoices[i].ID, amount, schedule.ReleaseAt(now)))
+		}
+	}
+	return &domain.Posting{Entries: entries, Holds: holds, Balances: payouts}, nil
+}
+
+// Posted conta nas métricas as comissões, as retenções e as partes do crédito de Posting depois de gravado
+func (s *SplitService) Posted(posting *domain.Posting, splits [][]*domain.InvoiceSplit) {
+	s.platforms.Withheld(posting.Entries)
+	s.escrow.Held(posting.Holds)
+	for _, invoiceSplits := range splits {
+		for _, split := range invoiceSplits {
+			metrics.SplitAmountTotal.WithLabelValues("net").Add(split.Net())
+			metrics.SplitAmountTotal.WithLabelValues("fee").Add(split.Fee)
+		}
+	}
+}
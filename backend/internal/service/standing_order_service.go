@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// StandingOrderConfig configura a execução das ordens permanentes
+type StandingOrderConfig struct {
+	// Interval é a frequência com que as ordens vencidas são procuradas
+	Interval time.Duration
+	// WebhookTimeout limita a entrega de cada evento ao webhook da ordem
+	WebhookTimeout time.Duration
+}
+
+// standingOrderActor identifica nos saldos e na auditoria as transferências feitas pelas ordens permanentes
+const standingOrderActor = "system:standing-orders"
+
+// Limites das listagens e das ordens executadas a cada consulta
+const (
+	maxStandingOrderPageSize = 500
+	standingOrderBatch       = 100
+)
+
+// standingOrderEventFailed é a transferência não feita, entregue no webhook da ordem com o motivo
+const standingOrderEventFailed = "standing_order.failed"
+
+// StandingOrderService executa as ordens permanentes, que transferem parte do saldo disponível da conta para outra
+// conta da mesma organização a cada período
+// A execução é gravada na mesma transação do movimento do saldo, só se a ordem ainda estiver ativa, para que uma
+// ordem nunca transfira duas vezes no mesmo período; as transferências não feitas avisam a conta pelo webhook da
+// ordem e ela segue para o próximo período
+type StandingOrderService struct {
+	orders         domain.StandingOrderRepository
+	accountService *AccountService
+	config         StandingOrderConfig
+}
+
+// NewStandingOrderService cria o serviço de ordens permanentes
+func NewStandingOrderService(orders domain.StandingOrderRepository, accountService *AccountService, config StandingOrderConfig) *StandingOrderService {
+	return &StandingOrderService{orders: orders, accountService: accountService, config: config}
+}
+
+// Create cria a ordem permanente da conta do API Key; a primeira transferência é feita pela tarefa de
+// transferências a partir de StartAt
+// Retorna ErrInvalidStandingOrder se a ordem for inválida ou se a conta de destino não existir, for de outra
+// organização ou de outro modo
+func (s *StandingOrderService) Create(ctx context.Context, apiKey string, input dto.CreateStandingOrderInput) (*dto.StandingOrderOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt := time.Now()
+	if input.StartAt != nil && input.StartAt.After(startAt) {
+		startAt = *input.StartAt
+	}
+	order, err := domain.NewStandingOrder(account.ID, input.DestinationAccountID, input.Percent, input.Amount, input.IntervalDays, input.WebhookURL, startAt)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := s.accountService.FindByID(ctx, order.DestinationID)
+	if errors.Is(err, domain.ErrAccountNotFound) || (err == nil && !sameLedger(account, destination)) {
+		return nil, domain.ErrInvalidStandingOrder
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orders.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "ordem permanente criada",
+		"account_id", account.ID, "standing_order_id", order.ID, "destination_id", order.DestinationID,
+		"percent", order.Percent, "amount", order.Amount, "interval_days", order.IntervalDays)
+	return dto.FromStandingOrder(order), nil
+}
+
+// List retorna as ordens da conta do API Key na situação informada, ou em todas com status vazio
+// Retorna ErrInvalidStandingOrder para situações desconhecidas
+func (s *StandingOrderService) List(ctx context.Context, apiKey string, status domain.StandingOrderStatus) ([]*dto.StandingOrderOutput, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidStandingOrder
+	}
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.orders.List(ctx, account.ID, status, maxStandingOrderPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromStandingOrders(orders), nil
+}
+
+// Get retorna a ordem da conta do API Key
+// Retorna ErrStandingOrderNotFound se ela não existir ou for de outra conta
+func (s *StandingOrderService) Get(ctx context.Context, apiKey, orderID string) (*dto.StandingOrderOutput, error) {
+	order, err := s.find(ctx, apiKey, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromStandingOrder(order), nil
+}
+
+// Cancel encerra as transferências da ordem da conta do API Key
+// Retorna ErrStandingOrderNotFound e ErrStandingOrderCancelled se ela já foi cancelada
+func (s *StandingOrderService) Cancel(ctx context.Context, apiKey, orderID string) (*dto.StandingOrderOutput, error) {
+	order, err := s.find(ctx, apiKey, orderID)
+	if err != nil {
+		return nil, err
+	}
+	from := order.Status
+	if err := order.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.orders.Update(ctx, order, from); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "ordem permanente cancelada", "account_id", order.AccountID, "standing_order_id", order.ID)
+	return dto.FromStandingOrder(order), nil
+}
+
+// find busca a ordem da conta do API Key
+func (s *StandingOrderService) find(ctx context.Context, apiKey, orderID string) (*domain.StandingOrder, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.AccountID != account.ID {
+		return nil, domain.ErrStandingOrderNotFound
+	}
+	return order, nil
+}
+
+// Run executa as ordens vencidas a cada intervalo; bloqueia até o contexto ser cancelado
+func (s *StandingOrderService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.ExecuteDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ExecuteDue(ctx, now)
+		}
+	}
+}
+
+// ExecuteDue executa as ordens com transferência até now e retorna quantas foram executadas, transferindo ou não
+// As falhas ao ler as contas ou gravar a ordem vão para o log e a ordem volta na próxima verificação
+func (s *StandingOrderService) ExecuteDue(ctx context.Context, now time.Time) int {
+	ctx = requestctx.WithActor(ctx, standingOrderActor)
+	executed := 0
+	for {
+		orders, err := s.orders.ListDue(ctx, now, standingOrderBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar as ordens permanentes a executar", "error", err)
+			return executed
+		}
+
+		progress := false
+		for _, order := range orders {
+			if s.execute(ctx, order, now) {
+				executed++
+				progress = true
+			}
+		}
+		// Um lote sem nenhuma execução se repetiria igual; as restantes ficam para a próxima verificação
+		if len(orders) < standingOrderBatch || !progress {
+			return executed
+		}
+	}
+}
+
+// execute executa a ordem com StandingOrderRepository.Run, que calcula a transferência sobre o saldo travado da
+// conta e grava a execução junto com o movimento; retorna false se a ordem não chegou a ser gravada
+func (s *StandingOrderService) execute(ctx context.Context, order *domain.StandingOrder, now time.Time) bool {
+	account, err := s.accountService.FindByID(ctx, order.AccountID)
+	if err != nil {
+		metrics.StandingOrderRunsTotal.WithLabelValues("error").Inc()
+		slog.ErrorContext(ctx, "erro ao buscar a conta da ordem permanente",
+			"account_id", order.AccountID, "standing_order_id", order.ID, "error", err)
+		return false
+	}
+
+	var reason string
+	destination, err := s.accountService.FindByID(ctx, order.DestinationID)
+	switch {
+	case errors.Is(err, domain.ErrAccountNotFound) || (err == nil && !sameLedger(account, destination)):
+		reason = domain.StandingOrderDestinationNotFound
+	case err != nil:
+		metrics.StandingOrderRunsTotal.WithLabelValues("error").Inc()
+		slog.ErrorContext(ctx, "erro ao buscar a conta de destino da ordem permanente",
+			"account_id", order.AccountID, "standing_order_id", order.ID, "error", err)
+		return false
+	}
+
+	// O valor sai do saldo travado pelo repositório na transação da execução, e não do saldo lido acima; se o
+	// movimento falhar nada é gravado, e a ordem é gravada de novo como não feita
+	recorded := false
+	if reason == "" {
+		err := s.orders.Run(ctx, order, now)
+		switch {
+		case errors.Is(err, domain.ErrStandingOrderChanged):
+			s.updateFailed(ctx, order, err)
+			return false
+		case err != nil:
+			slog.ErrorContext(ctx, "erro ao transferir o saldo da ordem permanente",
+				"account_id", order.AccountID, "standing_order_id", order.ID, "error", err)
+			reason = domain.StandingOrderTransferFailed
+		default:
+			recorded = true
+			reason = order.LastFailure
+		}
+	}
+	if !recorded {
+		order.Failed(reason, now)
+		if err := s.orders.Update(ctx, order, domain.StandingOrderActive); err != nil {
+			s.updateFailed(ctx, order, err)
+			return false
+		}
+	}
+
+	amount := order.LastAmount
+	switch {
+	case reason != "":
+		metrics.StandingOrderRunsTotal.WithLabelValues("failed").Inc()
+		slog.WarnContext(ctx, "transferência da ordem permanente não feita",
+			"account_id", order.AccountID, "standing_order_id", order.ID, "reason", reason,
+			"failed_runs", order.FailedRuns, "next_run_at", order.NextRunAt)
+		s.notify(ctx, order, account.APIKey, map[string]any{
+			"standing_order_id": order.ID,
+			"destination_id":    order.DestinationID,
+			"reason":            reason,
+			"failed_runs":       order.FailedRuns,
+			"next_run_at":       order.NextRunAt,
+		})
+	case amount == 0:
+		metrics.StandingOrderRunsTotal.WithLabelValues("empty").Inc()
+		slog.InfoContext(ctx, "ordem permanente sem saldo a varrer", "account_id", order.AccountID, "standing_order_id", order.ID)
+	default:
+		metrics.StandingOrderRunsTotal.WithLabelValues("transferred").Inc()
+		metrics.StandingOrderTransferredAmountTotal.Add(amount)
+		slog.InfoContext(ctx, "ordem permanente transferida",
+			"account_id", order.AccountID, "standing_order_id", order.ID, "destination_id", order.DestinationID, "amount", amount)
+	}
+	return true
+}
+
+// updateFailed registra a execução da ordem que não foi gravada
+// Uma ordem cancelada durante a execução não é gravada de novo e o saldo não se move
+func (s *StandingOrderService) updateFailed(ctx context.Context, order *domain.StandingOrder, err error) {
+	metrics.StandingOrderRunsTotal.WithLabelValues("error").Inc()
+	if errors.Is(err, domain.ErrStandingOrderChanged) {
+		slog.WarnContext(ctx, "ordem permanente alterada durante a execução", "account_id", order.AccountID, "standing_order_id", order.ID)
+		return
+	}
+	slog.ErrorContext(ctx, "erro ao gravar a execução da ordem permanente",
+		"account_id", order.AccountID, "standing_order_id", order.ID, "error", err)
+}
+
+// notify entrega o evento da transferência não feita no webhook da ordem, assinado com o API Key da conta, como
+// os webhooks das assinaturas
+// As entregas não são repetidas; falhas vão para o log e para /metrics
+func (s *StandingOrderService) notify(ctx context.Context, order *domain.StandingOrder, apiKey string, data map[string]any) {
+	if order.WebhookURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.config.WebhookTimeout)
+	defer cancel()
+
+	data["account_id"] = order.AccountID
+	err := notify.NewWebhook(order.WebhookURL, apiKey, s.config.WebhookTimeout).Send(ctx, notify.Event{
+		Type:      standingOrderEventFailed,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	metrics.ObserveWebhookDelivery("standing_order_webhook", err)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao enviar webhook da ordem permanente", "error", err, "standing_order_id", order.ID)
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/carddata"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/notify"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// SubscriptionConfig configura as cobranças das assinaturas e as retentativas das recusadas
+type SubscriptionConfig struct {
+	Dunning domain.DunningPolicy
+	// Interval é a frequência com que as cobranças vencidas são procuradas
+	Interval time.Duration
+	// WebhookTimeout limita a entrega de cada evento ao webhook da assinatura
+	WebhookTimeout time.Duration
+}
+
+// subscriptionBillingActor identifica nos saldos e na auditoria as cobranças feitas pelo prazo
+const subscriptionBillingActor = "system:subscription-billing"
+
+// Limites das listagens e das assinaturas cobradas a cada consulta
+const (
+	maxSubscriptionPageSize = 500
+	subscriptionChargeBatch = 100
+)
+
+// Eventos entregues no webhook das assinaturas
+const (
+	// subscriptionEventChargeFailed é uma cobrança recusada com retentativa agendada
+	subscriptionEventChargeFailed = "subscription.charge_failed"
+	// subscriptionEventPastDue é a assinatura que ficou inadimplente depois da última retentativa
+	subscriptionEventPastDue = "subscription.past_due"
+	// subscriptionEventCancelled é a assinatura cancelada depois da última retentativa
+	subscriptionEventCancelled = "subscription.cancelled"
+)
+
+// SubscriptionService cobra as assinaturas no cartão guardado no cofre a cada período
+// Uma cobrança recusada é repetida nos dias da política de retentativas, avisando a conta pelo webhook da
+// assinatura entre as tentativas; esgotadas as retentativas, a assinatura fica inadimplente ou é cancelada
+type SubscriptionService struct {
+	subscriptions  domain.SubscriptionRepository
+	invoices       *InvoiceService
+	cards          *carddata.Vault
+	accountService *AccountService
+	config         SubscriptionConfig
+}
+
+// NewSubscriptionService cria o serviço de assinaturas
+func NewSubscriptionService(subscriptions domain.SubscriptionRepository, invoices *InvoiceService, cards *carddata.Vault, accountService *AccountService, config SubscriptionConfig) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptions:  subscriptions,
+		invoices:       invoices,
+		cards:          cards,
+		accountService: accountService,
+		config:         config,
+	}
+}
+
+// Enabled indica se o cofre guarda o número dos cartões, sem o qual as assinaturas não podem ser cobradas
+func (s *SubscriptionService) Enabled() bool {
+	return s.cards.RetainsNumbers()
+}
+
+// Create cria a assinatura da conta do API Key, guardando o cartão no cofre; a primeira cobrança é feita pela
+// tarefa de cobrança a partir de StartAt
+// Retorna os erros de validação do cartão e ErrInvalidSubscription se a assinatura for inválida
+func (s *SubscriptionService) Create(ctx context.Context, apiKey string, input dto.CreateSubscriptionInput) (*dto.SubscriptionOutput, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	card, err := carddata.NewCard(input.CardNumber, input.CVV, input.ExpiryMonth, input.ExpiryYear, input.CardholderName)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt := time.Now()
+	if input.StartAt != nil && input.StartAt.After(startAt) {
+		startAt = *input.StartAt
+	}
+	subscription, err := domain.NewSubscription(account.ID, card.PaymentCard(), input.Description, input.Amount, input.IntervalDays, input.WebhookURL, startAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// O cartão só é guardado depois que a assinatura é válida
+	if subscription.CardToken, err = s.cards.Tokenize(ctx, account.ID, card, account.TestMode); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptions.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "assinatura criada",
+		"account_id", account.ID, "subscription_id", subscription.ID, "amount", subscription.Amount, "interval_days", subscription.IntervalDays)
+	return dto.FromSubscription(subscription), nil
+}
+
+// List retorna as assinaturas da conta do API Key na situação informada, ou em todas com status vazio
+// Retorna ErrInvalidSubscription para situações desconhecidas
+func (s *SubscriptionService) List(ctx context.Context, apiKey string, status domain.SubscriptionStatus) ([]*dto.SubscriptionOutput, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidSubscription
+	}
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := s.subscriptions.List(ctx, account.ID, status, maxSubscriptionPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromSubscriptions(subscriptions), nil
+}
+
+// Get retorna a assinatura da conta do API Key
+// Retorna ErrSubscriptionNotFound se ela não existir ou for de outra conta
+func (s *SubscriptionService) Get(ctx context.Context, apiKey, subscriptionID string) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.find(ctx, apiKey, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromSubscription(subscription), nil
+}
+
+// Cancel encerra as cobranças da assinatura da conta do API Key
+// Retorna ErrSubscriptionNotFound e ErrSubscriptionCancelled se ela já foi cancelada
+func (s *SubscriptionService) Cancel(ctx context.Context, apiKey, subscriptionID string) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.find(ctx, apiKey, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	from := subscription.Status
+	if err := subscription.Cancel(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptions.Update(ctx, subscription, from); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "assinatura cancelada", "account_id", subscription.AccountID, "subscription_id", subscription.ID)
+	return dto.FromSubscription(subscription), nil
+}
+
+// Resume reativa a assinatura inadimplente da conta do API Key, que é cobrada na próxima verificação
+// Retorna ErrSubscriptionNotFound, ErrSubscriptionCancelled e ErrSubscriptionNotPastDue se ela já está ativa
+func (s *SubscriptionService) Resume(ctx context.Context, apiKey, subscriptionID string) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.find(ctx, apiKey, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := subscription.Resume(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptions.Update(ctx, subscription, domain.SubscriptionPastDue); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "assinatura retomada", "account_id", subscription.AccountID, "subscription_id", subscription.ID)
+	return dto.FromSubscription(subscription), nil
+}
+
+// find busca a assinatura da conta do API Key
+func (s *SubscriptionService) find(ctx context.Context, apiKey, subscriptionID string) (*domain.Subscription, error) {
+	account, err := s.accountService.FindByAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.subscriptions.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.AccountID != account.ID {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// Run cobra as assinaturas vencidas a cada intervalo; bloqueia até o contexto ser cancelado
+func (s *SubscriptionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.ChargeDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.ChargeDue(ctx, now)
+		}
+	}
+}
+
+// ChargeDue cobra as assinaturas com tentativa até now e retorna quantas foram cobradas, aprovadas ou não
+// As falhas que não são uma recusa da cobrança vão para o log e a assinatura volta na próxima verificação
+func (s *SubscriptionService) ChargeDue(ctx context.Context, now time.Time) int {
+	ctx = requestctx.WithActor(ctx, subscriptionBillingActor)
+	charged := 0
+	for {
+		subscriptions, err := s.subscriptions.ListDue(ctx, now, subscriptionChargeBatch)
+		if err != nil {
+			slog.ErrorContext(ctx, "erro ao buscar as assinaturas a cobrar", "error", err)
+			return charged
+		}
+
+		progress := false
+		for _, subscription := range subscriptions {
+			if s.charge(ctx, subscription, now) {
+				charged++
+				progress = true
+			}
+		}
+		// Um lote sem nenhuma cobrança se repetiria igual; as restantes ficam para a próxima verificação
+		if len(subscriptions) < subscriptionChargeBatch || !progress {
+			return charged
+		}
+	}
+}
+
+// charge cobra a assinatura e agenda a próxima tentativa; retorna false se a cobrança não chegou a uma decisão
+// Recusas da fatura, das listas de bloqueio ou dos tetos de gasto e cartões que saíram do cofre contam como
+// cobrança recusada
+func (s *SubscriptionService) charge(ctx context.Context, subscription *domain.Subscription, now time.Time) bool {
+	attempt := "first"
+	if subscription.FailedAttempts > 0 {
+		attempt = "retry"
+	}
+	metadata := map[string]string{domain.SubscriptionIDMetadataKey: subscription.ID}
+	invoice, err := s.invoices.ChargeStoredCard(ctx, subscription.AccountID, subscription.CardToken, subscription.Amount, subscription.Description, metadata)
+
+	var limitErr *domain.TransactionLimitError
+	var invoiceID, reason string
+	switch {
+	case err == nil && invoice.Status != domain.StatusRejected:
+		// Abaixo de MaxSubscriptionAmount a fatura sai aprovada ou recusada na hora, sem passar pelo antifraude
+		subscription.ChargeApproved(invoice.ID, now)
+	case err == nil:
+		invoiceID, reason = invoice.ID, "rejected"
+	case errors.Is(err, domain.ErrBlocklisted):
+		reason = "blocklisted"
+	case errors.As(err, &limitErr):
+		reason = "spending_limit"
+	case errors.Is(err, domain.ErrCardNotFound):
+		reason = "card_not_found"
+	default:
+		metrics.SubscriptionChargesTotal.WithLabelValues("error", attempt).Inc()
+		slog.ErrorContext(ctx, "erro ao cobrar a assinatura",
+			"account_id", subscription.AccountID, "subscription_id", subscription.ID, "error", err)
+		return false
+	}
+
+	exhausted := false
+	if reason != "" {
+		exhausted = subscription.ChargeFailed(invoiceID, s.config.Dunning, now)
+		metrics.SubscriptionChargesTotal.WithLabelValues("rejected", attempt).Inc()
+	} else {
+		metrics.SubscriptionChargesTotal.WithLabelValues("approved", attempt).Inc()
+	}
+
+	err = s.subscriptions.Update(ctx, subscription, domain.SubscriptionActive)
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionChanged):
+		// Cancelada durante a cobrança; a fatura já gravada continua valendo
+		slog.WarnContext(ctx, "assinatura alterada durante a cobrança",
+			"account_id", subscription.AccountID, "subscription_id", subscription.ID, "invoice_id", subscription.LastInvoiceID)
+		return true
+	case err != nil:
+		slog.ErrorContext(ctx, "erro ao gravar a cobrança da assinatura",
+			"account_id", subscription.AccountID, "subscription_id", subscription.ID, "invoice_id", subscription.LastInvoiceID, "error", err)
+		return false
+	}
+
+	if reason == "" {
+		slog.InfoContext(ctx, "assinatura cobrada",
+			"account_id", subscription.AccountID, "subscription_id", subscription.ID, "invoice_id", invoice.ID, "amount", subscription.Amount)
+		return true
+	}
+
+	data := map[string]any{
+		"subscription_id": subscription.ID,
+		"invoice_id":      invoiceID,
+		"reason":          reason,
+		"failed_attempts": subscription.FailedAttempts,
+	}
+	if !exhausted {
+		data["next_attempt_at"] = subscription.NextAttemptAt
+		slog.WarnContext(ctx, "cobrança da assinatura recusada, retentativa agendada",
+			"account_id", subscription.AccountID, "subscription_id", subscription.ID, "reason", reason,
+			"failed_attempts", subscription.FailedAttempts, "next_attempt_at", subscription.NextAttemptAt)
+		s.notify(ctx, subscription, subscriptionEventChargeFailed, data)
+		return true
+	}
+
+	event := subscriptionEventPastDue
+	if subscription.Status == domain.SubscriptionCancelled {
+		event = subscriptionEventCancelled
+	}
+	metrics.SubscriptionsDunnedTotal.WithLabelValues(string(subscription.Status)).Inc()
+	slog.WarnContext(ctx, "retentativas da assinatura esgotadas",
+		"account_id", subscription.AccountID, "subscription_id", subscription.ID, "reason", reason,
+		"failed_attempts", subscription.FailedAttempts, "status", subscription.Status)
+	s.notify(ctx, subscription, event, data)
+	return true
+}
+
+// notify entrega o evento no webhook da assinatura, assinado com o API Key da conta, como os webhooks de segurança
+// As entregas não são repetidas; falhas vão para o log e para /metrics
+func (s *SubscriptionService) notify(ctx context.Context, subscription *domain.Subscription, eventType string, data map[string]any) {
+	if subscription.WebhookURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.config.WebhookTimeout)
+	defer cancel()
+
+	account, err := s.accountService.FindByID(ctx, subscription.AccountID)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao buscar a conta do webhook da assinatura", "error", err, "subscription_id", subscription.ID)
+		return
+	}
+	data["account_id"] = subscription.AccountID
+
+	err = notify.NewWebhook(subscription.WebhookURL, account.APIKey, s.config.WebhookTimeout).Send(ctx, notify.Event{
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	metrics.ObserveWebhookDelivery("subscription_webhook", err)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao enviar webhook da assinatura", "error", err, "subscription_id", subscription.ID, "type", eventType)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// TaxService calcula os impostos das faturas na criação e guarda os impostos de cada linha
+type TaxService struct {
+	calculator domain.TaxCalculator
+	taxes      domain.InvoiceTaxRepository
+}
+
+// NewTaxService cria o serviço de impostos; calculator nil não cobra impostos
+func NewTaxService(calculator domain.TaxCalculator, taxes domain.InvoiceTaxRepository) *TaxService {
+	return &TaxService{calculator: calculator, taxes: taxes}
+}
+
+// Apply calcula os impostos sobre o valor da fatura, já descontado dos cupons, e os soma ao valor
+// Cada item é uma linha; sem itens a fatura é uma única linha, e sem calculadora ela não muda
+// Retorna ErrDependencyUnavailable se a calculadora falhar ou retornar impostos inválidos, recusando a fatura em
+// vez de cobrá-la sem os impostos
+func (s *TaxService) Apply(ctx context.Context, invoice *domain.Invoice, items []*domain.InvoiceItem) ([]*domain.InvoiceTax, error) {
+	if s.calculator == nil {
+		return nil, nil
+	}
+
+	request := domain.TaxRequest{
+		AccountID: invoice.AccountID,
+		InvoiceID: invoice.ID,
+		Lines:     invoice.TaxLines(items),
+	}
+	calculated, err := s.calculator.Calculate(ctx, request)
+	if err == nil {
+		err = domain.ValidateTaxItems(request, calculated)
+	}
+	if err != nil {
+		metrics.TaxCalculationErrorsTotal.Inc()
+		slog.ErrorContext(ctx, "erro ao calcular os impostos da fatura", "error", err)
+		return nil, domain.ErrDependencyUnavailable
+	}
+	return invoice.ApplyTaxes(calculated), nil
+}
+
+// Save grava os impostos de uma ou mais faturas
+func (s *TaxService) Save(ctx context.Context, taxes []*domain.InvoiceTax) error {
+	if len(taxes) == 0 {
+		return nil
+	}
+	if err := s.taxes.SaveBatch(ctx, taxes); err != nil {
+		return err
+	}
+	for _, tax := range taxes {
+		metrics.InvoiceTaxAmountTotal.WithLabelValues(tax.Name).Add(tax.Amount)
+	}
+	return nil
+}
+
+// Find retorna os impostos da fatura, ou nenhum se ela não tem impostos
+func (s *TaxService) Find(ctx context.Context, invoiceID string) ([]*domain.InvoiceTax, error) {
+	return s.taxes.FindByInvoiceID(ctx, invoiceID)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// TenantService gerencia as organizações das implantações white-label: a chave dos administradores de cada uma,
+// a marca e a tarifa de chargeback e os tetos de gasto que valem para as contas delas
+type TenantService struct {
+	tenants        domain.TenantRepository
+	accountService *AccountService
+}
+
+// NewTenantService cria o serviço de organizações
+func NewTenantService(tenants domain.TenantRepository, accountService *AccountService) *TenantService {
+	return &TenantService{tenants: tenants, accountService: accountService}
+}
+
+// AuthenticateAdmin identifica a organização pela chave dos administradores dela
+// Retorna ErrInvalidCredentials se a chave não for de nenhuma organização
+func (s *TenantService) AuthenticateAdmin(ctx context.Context, key string) (*domain.Tenant, error) {
+	if !strings.HasPrefix(key, domain.TenantAdminKeyPrefix) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	tenant, err := s.tenants.FindByAdminKeyHash(ctx, domain.HashTenantAdminKey(key))
+	if err == domain.ErrTenantNotFound {
+		return nil, domain.ErrInvalidCredentials
+	}
+	return tenant, err
+}
+
+// Create cria a organização, retornando a chave dos administradores dela
+// Retorna ErrInvalidTenant se o nome for inválido
+func (s *TenantService) Create(ctx context.Context, input dto.CreateTenantInput) (*dto.TenantOutput, error) {
+	tenant, adminKey, err := domain.NewTenant(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tenants.Save(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "organização criada", "tenant_id", tenant.ID)
+	output := dto.FromTenant(tenant)
+	output.AdminKey = adminKey
+	return output, nil
+}
+
+// List retorna todas as organizações
+func (s *TenantService) List(ctx context.Context) ([]*dto.TenantOutput, error) {
+	tenants, err := s.tenants.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromTenants(tenants), nil
+}
+
+// Get retorna a organização informada
+// Retorna ErrTenantNotFound se ela não existir
+func (s *TenantService) Get(ctx context.Context, id string) (*dto.TenantOutput, error) {
+	tenant, err := s.tenants.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromTenant(tenant), nil
+}
+
+// Current retorna a organização dos administradores autenticados
+// Retorna ErrForbidden fora das requisições dos administradores de uma organização
+func (s *TenantService) Current(ctx context.Context) (*dto.TenantOutput, error) {
+	tenantID := requestctx.Tenant(ctx)
+	if tenantID == "" {
+		return nil, domain.ErrForbidden
+	}
+	return s.Get(ctx, tenantID)
+}
+
+// Configure substitui a marca, a tarifa de chargeback e os tetos de gasto da organização
+// Retorna ErrTenantNotFound se ela não existir e ErrInvalidTenant se a configuração for inválida
+func (s *TenantService) Configure(ctx context.Context, id string, input dto.TenantConfigInput) (*dto.TenantOutput, error) {
+	tenant, err := s.tenants.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits *domain.SpendingLimits
+	if input.SpendingLimits != nil {
+		limits = &domain.SpendingLimits{Daily: input.SpendingLimits.Daily, Monthly: input.SpendingLimits.Monthly}
+	}
+	if err := tenant.Configure(domain.TenantBranding(input.Branding), input.ChargebackFee, limits); err != nil {
+		return nil, err
+	}
+	if err := s.tenants.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "configuração da organização atualizada", "tenant_id", id)
+	return dto.FromTenant(tenant), nil
+}
+
+// UpdateBranding substitui apenas a marca da organização dos administradores autenticados; a tarifa e os tetos
+// de gasto são definidos pelos administradores globais
+// Retorna ErrForbidden fora das requisições dos administradores de uma organização
+func (s *TenantService) UpdateBranding(ctx context.Context, input dto.TenantBrandingInput) (*dto.TenantOutput, error) {
+	tenantID := requestctx.Tenant(ctx)
+	if tenantID == "" {
+		return nil, domain.ErrForbidden
+	}
+	tenant, err := s.tenants.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tenant.Configure(domain.TenantBranding(input), tenant.ChargebackFee, tenant.SpendingLimits); err != nil {
+		return nil, err
+	}
+	if err := s.tenants.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "marca da organização atualizada", "tenant_id", tenantID)
+	return dto.FromTenant(tenant), nil
+}
+
+// RotateAdminKey troca a chave dos administradores da organização, retornando a nova; a anterior deixa de valer
+// Retorna ErrTenantNotFound se ela não existir
+func (s *TenantService) RotateAdminKey(ctx context.Context, id string) (*dto.TenantOutput, error) {
+	tenant, err := s.tenants.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	adminKey := tenant.RotateAdminKey()
+	if err := s.tenants.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "chave dos administradores da organização trocada", "tenant_id", id)
+	output := dto.FromTenant(tenant)
+	output.AdminKey = adminKey
+	return output, nil
+}
+
+// Branding retorna a marca pública da organização
+// Retorna ErrTenantNotFound se ela não existir
+func (s *TenantService) Branding(ctx context.Context, id string) (*dto.TenantBrandingOutput, error) {
+	tenant, err := s.tenants.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TenantBrandingOutput{TenantID: tenant.ID, TenantBrandingInput: dto.TenantBrandingInput(tenant.Branding)}, nil
+}
+
+// AssignAccount move a conta para a organização informada; tenantID vazio tira a conta da organização
+// Retorna ErrTenantNotFound se a organização não existir e ErrAccountNotFound se a conta não existir
+func (s *TenantService) AssignAccount(ctx context.Context, accountID, tenantID string) (*dto.AccountOutput, error) {
+	if tenantID != "" {
+		if _, err := s.tenants.FindByID(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	output, err := s.accountService.UpdateTenant(ctx, accountID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "organização da conta alterada", "account_id", accountID, "tenant_id", tenantID)
+	return output, nil
+}
+
+// ChargebackFee retorna a tarifa de chargeback da organização, ou nil se ela usa a padrão ou se tenantID é vazio
+// Um TenantService nil retorna sempre nil
+func (s *TenantService) ChargebackFee(ctx context.Context, tenantID string) (*float64, error) {
+	if s == nil || tenantID == "" {
+		return nil, nil
+	}
+	tenant, err := s.tenants.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return tenant.ChargebackFee, nil
+}
+
+// SpendingLimits retorna os tetos de gasto da organização, ou nil se ela usa os padrão ou se tenantID é vazio
+// Um TenantService nil retorna sempre nil
+func (s *TenantService) SpendingLimits(ctx context.Context, tenantID string) (*domain.SpendingLimits, error) {
+	if s == nil || tenantID == "" {
+		return nil, nil
+	}
+	tenant, err := s.tenants.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return tenant.SpendingLimits, nil
+}
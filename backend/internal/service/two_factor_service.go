@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// TwoFactorService cadastra e confere o segundo fator (TOTP ou código de recuperação) exigido nas operações sensíveis
+// Cada conferência é registrada em events, de modo que códigos inválidos consecutivos bloqueiam o segundo fator
+type TwoFactorService struct {
+	repository domain.TwoFactorRepository
+	events     *SecurityService
+	issuer     string
+	// required exige o segundo fator também de quem ainda não o cadastrou
+	required bool
+}
+
+// NewTwoFactorService cria o serviço de segundo fator; issuer é o nome exibido nos autenticadores
+// Com required false, quem não cadastrou o segundo fator executa as operações sensíveis sem código
+func NewTwoFactorService(repository domain.TwoFactorRepository, events *SecurityService, issuer string, required bool) *TwoFactorService {
+	return &TwoFactorService{repository: repository, events: events, issuer: issuer, required: required}
+}
+
+// Enroll inicia o cadastro do segundo fator do autor, substituindo um cadastro pendente
+// Retorna ErrTwoFactorAlreadyEnrolled se o autor já tiver concluído o cadastro
+func (s *TwoFactorService) Enroll(ctx context.Context, actor string) (*dto.TwoFactorEnrollmentOutput, error) {
+	current, err := s.repository.FindByActor(ctx, actor)
+	switch {
+	case err == nil && current.IsConfirmed():
+		return nil, domain.ErrTwoFactorAlreadyEnrolled
+	case err == nil:
+		if err := s.repository.Delete(ctx, actor); err != nil {
+			return nil, err
+		}
+	case err != domain.ErrTwoFactorNotEnrolled:
+		return nil, err
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, backupCodes, err := domain.NewTwoFactor(actor, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.Save(ctx, twoFactor); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollmentOutput{
+		Secret:      secret,
+		URI:         auth.TOTPURI(s.issuer, actor, secret),
+		BackupCodes: backupCodes,
+	}, nil
+}
+
+// Confirm conclui o cadastro com um código TOTP válido
+// Retorna ErrTwoFactorNotEnrolled sem cadastro iniciado e ErrInvalidTwoFactorCode se o código não conferir
+func (s *TwoFactorService) Confirm(ctx context.Context, actor, code string) error {
+	twoFactor, err := s.repository.FindByActor(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if twoFactor.IsConfirmed() {
+		return domain.ErrTwoFactorAlreadyEnrolled
+	}
+
+	// Os códigos de recuperação não concluem o cadastro, que precisa provar que o autenticador foi configurado
+	step, ok := auth.VerifyTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastStep)
+	s.record(ctx, actor, ok)
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	now := time.Now()
+	twoFactor.LastStep = step
+	twoFactor.ConfirmedAt = &now
+	twoFactor.UpdatedAt = now
+	if err := s.repository.Update(ctx, twoFactor); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "segundo fator cadastrado", "actor", actor)
+	return nil
+}
+
+// Disable remove o segundo fator do autor; um cadastro concluído exige um código válido
+func (s *TwoFactorService) Disable(ctx context.Context, actor, code string) error {
+	twoFactor, err := s.repository.FindByActor(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if twoFactor.IsConfirmed() {
+		if err := s.verify(ctx, twoFactor, code); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repository.Delete(ctx, actor); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "segundo fator removido", "actor", actor)
+	return nil
+}
+
+// Authorize confere o código do segundo fator de uma operação sensível
+// Sem cadastro concluído, retorna ErrTwoFactorNotEnrolled quando o segundo fator é obrigatório e nil caso contrário
+// Retorna ErrTwoFactorRequired sem código e ErrInvalidTwoFactorCode se o código não conferir
+func (s *TwoFactorService) Authorize(ctx context.Context, actor, code string) error {
+	twoFactor, err := s.repository.FindByActor(ctx, actor)
+	if err != nil && err != domain.ErrTwoFactorNotEnrolled {
+		return err
+	}
+	if err != nil || !twoFactor.IsConfirmed() {
+		if s.required {
+			return domain.ErrTwoFactorNotEnrolled
+		}
+		return nil
+	}
+
+	if code == "" {
+		return domain.ErrTwoFactorRequired
+	}
+	return s.verify(ctx, twoFactor, code)
+}
+
+// verify aceita um código TOTP ainda não usado ou consome um código de recuperação
+func (s *TwoFactorService) verify(ctx context.Context, twoFactor *domain.TwoFactor, code string) error {
+	step, ok := auth.VerifyTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastStep)
+	if ok {
+		twoFactor.LastStep = step
+		twoFactor.UpdatedAt = time.Now()
+	} else {
+		ok = twoFactor.UseBackupCode(code)
+	}
+
+	s.record(ctx, twoFactor.Actor, ok)
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return s.repository.Update(ctx, twoFactor)
+}
+
+// record registra a conferência do código nos eventos de autenticação
+func (s *TwoFactorService) record(ctx context.Context, actor string, success bool) {
+	event := domain.AuthEvent{Method: domain.AuthMethodTOTP, KeyID: domain.TwoFactorKeyID(actor), Success: success}
+	if !success {
+		event.Reason = domain.ErrInvalidTwoFactorCode.Error()
+	}
+	s.events.Record(ctx, event)
+}
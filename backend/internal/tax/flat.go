@@ -0,0 +1,42 @@
+// Package tax implementa as calculadoras de impostos das faturas: alíquotas fixas da configuração ou um provedor
+// externo por HTTP
+package tax
+
+import (
+	"context"
+	"math"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// Rate é uma alíquota fixa, em percentual sobre o valor da linha
+type Rate struct {
+	Name    string
+	Percent float64
+}
+
+// FlatRate aplica as mesmas alíquotas a todas as linhas de todas as contas
+type FlatRate struct {
+	rates []Rate
+}
+
+// NewFlatRate cria a calculadora com as alíquotas informadas
+func NewFlatRate(rates []Rate) *FlatRate {
+	return &FlatRate{rates: rates}
+}
+
+// Calculate calcula cada alíquota sobre cada linha, arredondando em centavos
+func (c *FlatRate) Calculate(ctx context.Context, request domain.TaxRequest) ([]domain.TaxItem, error) {
+	items := make([]domain.TaxItem, 0, len(request.Lines)*len(c.rates))
+	for line, taxLine := range request.Lines {
+		for _, rate := range c.rates {
+			items = append(items, domain.TaxItem{
+				Line:   line,
+				Name:   rate.Name,
+				Rate:   rate.Percent,
+				Amount: math.Round(taxLine.Amount*rate.Percent) / 100,
+			})
+		}
+	}
+	return items, nil
+}
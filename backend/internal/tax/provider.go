@@ -0,0 +1,105 @@
+package tax
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/breaker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// maxProviderResponse limita o corpo lido da resposta do provedor
+const maxProviderResponse = 1 << 20
+
+// providerLine é uma linha da fatura no corpo enviado ao provedor
+type providerLine struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// providerRequest é o corpo enviado ao provedor
+type providerRequest struct {
+	AccountID string         `json:"account_id"`
+	InvoiceID string         `json:"invoice_id"`
+	Lines     []providerLine `json:"lines"`
+}
+
+// providerTax é um imposto na resposta do provedor
+type providerTax struct {
+	Line   int     `json:"line"`
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// providerResponse é a resposta do provedor
+type providerResponse struct {
+	Taxes []providerTax `json:"taxes"`
+}
+
+// Provider calcula os impostos em um serviço externo, que recebe as linhas da fatura por POST e responde os
+// impostos de cada uma
+type Provider struct {
+	url     string
+	token   string
+	client  *http.Client
+	breaker *breaker.Breaker
+}
+
+// NewProvider cria a calculadora do provedor em url; token, opcional, vai no header Authorization
+// O circuit breaker limita cada chamada e recusa as faturas na hora com o provedor fora do ar
+func NewProvider(url, token string, circuit *breaker.Breaker) *Provider {
+	return &Provider{url: url, token: token, client: &http.Client{}, breaker: circuit}
+}
+
+// Calculate envia as linhas ao provedor
+// Respostas fora da faixa 2xx ou com impostos inválidos são tratadas como falha do provedor
+func (p *Provider) Calculate(ctx context.Context, request domain.TaxRequest) ([]domain.TaxItem, error) {
+	body := providerRequest{AccountID: request.AccountID, InvoiceID: request.InvoiceID, Lines: make([]providerLine, len(request.Lines))}
+	for i, line := range request.Lines {
+		body.Lines[i] = providerLine{Description: line.Description, Amount: line.Amount}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []domain.TaxItem
+	err = p.breaker.Execute(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if p.token != "" {
+			req.Header.Set("Authorization", "Bearer "+p.token)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("tax: provider responded with status %d", resp.StatusCode)
+		}
+
+		var response providerResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxProviderResponse)).Decode(&response); err != nil {
+			return fmt.Errorf("tax: invalid provider response: %w", err)
+		}
+		items = make([]domain.TaxItem, len(response.Taxes))
+		for i, tax := range response.Taxes {
+			items[i] = domain.TaxItem{Line: tax.Line, Name: tax.Name, Rate: tax.Rate, Amount: tax.Amount}
+		}
+		return domain.ValidateTaxItems(request, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,53 @@
+package velocity
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxMemoryCounters limita as chaves mantidas; as de janelas encerradas são descartadas ao atingir o limite
+const maxMemoryCounters = 100000
+
+type counter struct {
+	total     int64
+	expiresAt time.Time
+}
+
+// MemoryCounter guarda as contagens na memória da instância, para desenvolvimento ou uma única réplica
+type MemoryCounter struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+}
+
+// NewMemoryCounter cria o contador em memória
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{counters: make(map[string]*counter)}
+}
+
+// Increment soma delta à contagem da chave
+func (m *MemoryCounter) Increment(ctx context.Context, key string, delta int64, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	c, ok := m.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		if len(m.counters) >= maxMemoryCounters {
+			m.prune(now)
+		}
+		c = &counter{expiresAt: now.Add(window)}
+		m.counters[key] = c
+	}
+	c.total += delta
+	return c.total, nil
+}
+
+// prune remove as contagens de janelas encerradas
+func (m *MemoryCounter) prune(now time.Time) {
+	for key, c := range m.counters {
+		if !now.Before(c.expiresAt) {
+			delete(m.counters, key)
+		}
+	}
+}
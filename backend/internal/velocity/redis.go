@@ -0,0 +1,51 @@
+package velocity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// keyPrefix separa as chaves das contagens das demais chaves do Redis
+const keyPrefix = "gateway:velocity:"
+
+// incrementScript soma a contagem e define a expiração na primeira transação da janela, de forma atômica
+var incrementScript = redis.NewScript(`
+local total = redis.call('INCRBY', KEYS[1], ARGV[1])
+if total == tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return total
+`)
+
+// errUnexpectedReply indica uma resposta do script fora do formato esperado
+var errUnexpectedReply = errors.New("velocity: unexpected redis reply")
+
+// RedisCounter guarda as contagens no Redis, compartilhadas entre as réplicas do gateway
+// Cada consulta tem no máximo timeout, para que um Redis sem resposta não prenda a criação das faturas
+type RedisCounter struct {
+	client  *redis.Client
+	timeout time.Duration
+}
+
+// NewRedisCounter cria o contador sobre o cliente Redis informado
+func NewRedisCounter(client *redis.Client, timeout time.Duration) *RedisCounter {
+	return &RedisCounter{client: client, timeout: timeout}
+}
+
+// Increment soma delta à contagem da chave
+func (r *RedisCounter) Increment(ctx context.Context, key string, delta int64, window time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	reply, err := incrementScript.Run(ctx, r.client, []string{keyPrefix + key}, delta, window.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	total, ok := reply.(int64)
+	if !ok {
+		return 0, errUnexpectedReply
+	}
+	return total, nil
+}
@@ -0,0 +1,134 @@
+// Package velocity limita a frequência das transações por cartão, pagador ou conta, barrando testes de cartão
+// As contagens usam janelas fixas; o contador em Redis compartilha as janelas entre as réplicas do gateway e o em
+// memória vale por instância
+package velocity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+)
+
+// Dimension é o que uma regra conta: o cartão, o pagador ou a conta da transação
+type Dimension string
+
+const (
+	DimensionCard    Dimension = "card"
+	DimensionPayer   Dimension = "payer"
+	DimensionAccount Dimension = "account"
+)
+
+// IsValid indica se a dimensão é conhecida
+func (d Dimension) IsValid() bool {
+	switch d {
+	case DimensionCard, DimensionPayer, DimensionAccount:
+		return true
+	}
+	return false
+}
+
+// Rule permite até Limit transações da mesma dimensão em cada janela de Window
+type Rule struct {
+	Dimension Dimension
+	Limit     int
+	Window    time.Duration
+}
+
+// Transaction identifica uma transação pelas dimensões das regras
+// Card é o número completo, que só entra nas chaves pelo HMAC
+type Transaction struct {
+	AccountID string
+	Card      string
+	Payer     string
+}
+
+// Counter soma delta à contagem da chave e retorna o total
+// A chave já inclui o início da janela e expira sozinha depois de window
+type Counter interface {
+	Increment(ctx context.Context, key string, delta int64, window time.Duration) (int64, error)
+}
+
+// Checker confere as transações contra as regras configuradas
+// Todas as tentativas contam, inclusive as recusadas, para que um teste de cartão continue barrado enquanto insistir
+type Checker struct {
+	counter Counter
+	rules   []Rule
+	hashKey []byte
+}
+
+// NewChecker cria o verificador; hashKey é a chave do HMAC que protege os cartões e pagadores nas chaves do contador
+func NewChecker(counter Counter, rules []Rule, hashKey []byte) *Checker {
+	return &Checker{counter: counter, rules: rules, hashKey: hashKey}
+}
+
+// Check conta as transações em cada regra, agrupando as que caem na mesma chave, como as faturas de um lote
+// Todas as regras contam a tentativa; retorna *domain.VelocityLimitError da primeira estourada e erros do
+// contador como estão
+func (c *Checker) Check(ctx context.Context, transactions []Transaction) error {
+	if c == nil || len(c.rules) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var exceeded error
+	for _, rule := range c.rules {
+		windowStart := now.Truncate(rule.Window)
+		counts := make(map[string]int64)
+		var order []string
+		for _, transaction := range transactions {
+			value := c.value(rule.Dimension, transaction)
+			if value == "" {
+				continue
+			}
+			key := string(rule.Dimension) + ":" + rule.Window.String() + ":" + strconv.FormatInt(windowStart.Unix(), 10) + ":" + value
+			if counts[key] == 0 {
+				order = append(order, key)
+			}
+			counts[key]++
+		}
+
+		for _, key := range order {
+			total, err := c.counter.Increment(ctx, key, counts[key], rule.Window)
+			if err != nil {
+				return err
+			}
+			if total > int64(rule.Limit) && exceeded == nil {
+				exceeded = &domain.VelocityLimitError{
+					Dimension:  string(rule.Dimension),
+					Limit:      rule.Limit,
+					Window:     rule.Window,
+					RetryAfter: windowStart.Add(rule.Window).Sub(now),
+				}
+			}
+		}
+	}
+	return exceeded
+}
+
+// value retorna o valor da dimensão na transação; cartões e pagadores vão pelo HMAC, para não ficarem no contador
+func (c *Checker) value(dimension Dimension, transaction Transaction) string {
+	switch dimension {
+	case DimensionAccount:
+		return transaction.AccountID
+	case DimensionCard:
+		return c.hash(transaction.Card)
+	case DimensionPayer:
+		return c.hash(strings.ToLower(strings.TrimSpace(transaction.Payer)))
+	}
+	return ""
+}
+
+func (c *Checker) hash(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
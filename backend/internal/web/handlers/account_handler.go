@@ -4,18 +4,24 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
 )
 
 // AccountHandler processa requisições HTTP relacionadas a contas
 type AccountHandler struct {
 	accountService *service.AccountService
+	escrowService  *service.EscrowService
+	ledgerService  *service.LedgerService
 }
 
 // NewAccountHandler cria um novo handler de contas
-func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
-	return &AccountHandler{accountService: accountService}
+// escrowService informa na consulta da conta o valor retido em custódia, e ledgerService lança no razão os ajustes
+// de saldo
+func NewAccountHandler(accountService *service.AccountService, escrowService *service.EscrowService, ledgerService *service.LedgerService) *AccountHandler {
+	return &AccountHandler{accountService: accountService, escrowService: escrowService, ledgerService: ledgerService}
 }
 
 // Create processa POST /accounts
@@ -28,10 +34,16 @@ func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := h.accountService.CreateAccount(input)
+	output, err := h.accountService.CreateAccount(r.Context(), input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		switch err {
+		case domain.ErrInvalidScope:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -40,15 +52,47 @@ func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 // Get processa GET /accounts
-// Requer X-API-Key no header
+// Request autenticação via X-API-KEY, assinatura HMAC ou JWT
+// balance é o saldo disponível, escrowed_balance o valor ainda em custódia e pending_balance o que aguarda a data
+// de repasse
 func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
-	apiKey := r.Header.Get("X-API-Key")
-	if apiKey == "" {
-		http.Error(w, "API Key is required", http.StatusUnauthorized)
+	output, err := h.accountService.FindByAPIKey(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	escrowed, err := h.escrowService.Balance(r.Context(), output.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	output.EscrowedBalance = &escrowed
+	pending, err := h.escrowService.PendingBalance(r.Context(), output.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	output.PendingBalance = &pending
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// AdjustBalance processa POST /accounts/balance
+// Requer o papel admin; valores negativos debitam a conta
+func (h *AccountHandler) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	var input dto.AdjustBalanceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Amount == 0 {
+		http.Error(w, domain.ErrInvalidAmount.Error(), http.StatusBadRequest)
 		return
 	}
 
-	output, err := h.accountService.FindByAPIKey(apiKey)
+	output, err := h.ledgerService.Adjust(r.Context(), requestctx.APIKey(r.Context()), input.Amount)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AdminHandler processa as rotas administrativas de contas, faturas e papéis
+type AdminHandler struct {
+	accountService *service.AccountService
+	invoiceService *service.InvoiceService
+	authService    *service.AuthService
+}
+
+// NewAdminHandler cria um novo handler administrativo
+func NewAdminHandler(accountService *service.AccountService, invoiceService *service.InvoiceService, authService *service.AuthService) *AdminHandler {
+	return &AdminHandler{
+		accountService: accountService,
+		invoiceService: invoiceService,
+		authService:    authService,
+	}
+}
+
+// findOptions traduz o parâmetro include_deleted=true nas opções de busca
+func findOptions(r *http.Request) []domain.FindOption {
+	if r.URL.Query().Get("include_deleted") == "true" {
+		return []domain.FindOption{domain.IncludeDeleted()}
+	}
+	return nil
+}
+
+// GetAccount processa GET /admin/accounts/{id}
+func (h *AdminHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	output, err := h.accountService.FindByID(r.Context(), chi.URLParam(r, "id"), findOptions(r)...)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// DeleteAccount processa DELETE /admin/accounts/{id}
+// Retorna 204 No Content após a exclusão lógica
+func (h *AdminHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	if err := h.accountService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateAccountRole processa PUT /admin/accounts/{id}/role
+// Altera o papel do API Key da conta
+func (h *AdminHandler) UpdateAccountRole(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateRoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.accountService.UpdateRole(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// UpdateUserRole processa PUT /admin/users/{id}/role
+// Altera o papel de um usuário do dashboard, inclusive para admin
+func (h *AdminHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateRoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.authService.UpdateUserRole(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// ListAccountInvoices processa GET /admin/accounts/{id}/invoices
+// Aceita os mesmos filtros de GET /invoice e include_deleted=true
+func (h *AdminHandler) ListAccountInvoices(w http.ResponseWriter, r *http.Request) {
+	input, err := parseListInvoicesInput(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.IncludeDeleted = r.URL.Query().Get("include_deleted") == "true"
+
+	output, err := h.invoiceService.SearchByAccountID(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	setNextCursor(w, output.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output.Invoices)
+}
+
+// GetInvoice processa GET /admin/invoices/{id}
+func (h *AdminHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	output, err := h.invoiceService.FindByID(r.Context(), chi.URLParam(r, "id"), findOptions(r)...)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// DeleteInvoice processa DELETE /admin/invoices/{id}
+// Retorna 204 No Content após a exclusão lógica
+func (h *AdminHandler) DeleteInvoice(w http.ResponseWriter, r *http.Request) {
+	if err := h.invoiceService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ConfirmPayment processa POST /admin/invoices/{id}/payment
+// Aprova a fatura com vencimento pelo pagamento confirmado, somando a multa e os juros se ele veio depois; o corpo
+// é opcional e sem paid_at vale o momento da confirmação
+func (h *AdminHandler) ConfirmPayment(w http.ResponseWriter, r *http.Request) {
+	var input dto.ConfirmPaymentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	paidAt := time.Now()
+	if input.PaidAt != nil {
+		paidAt = *input.PaidAt
+	}
+
+	output, err := h.invoiceService.ConfirmPayment(r.Context(), chi.URLParam(r, "id"), paidAt)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// writeAdminError mapeia os erros de domínio para os status HTTP das rotas administrativas
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound, domain.ErrInvoiceNotFound, domain.ErrUserNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidStatus, domain.ErrInvalidAmount, domain.ErrInvalidDateRange, domain.ErrInvalidCursor, domain.ErrInvalidRole, domain.ErrInvalidPaymentDate:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrForbidden:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrDependencyUnavailable:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
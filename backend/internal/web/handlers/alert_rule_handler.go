@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AlertRuleHandler processa o cadastro das regras do alerta interno
+type AlertRuleHandler struct {
+	alertService *service.AlertService
+}
+
+// NewAlertRuleHandler cria um novo handler de regras de alerta
+func NewAlertRuleHandler(alertService *service.AlertService) *AlertRuleHandler {
+	return &AlertRuleHandler{alertService: alertService}
+}
+
+// writeAlertRuleError traduz os erros das regras de alerta em status HTTP
+func writeAlertRuleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidAlertRule:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAlertRuleNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// List processa GET /admin/alert-rules
+func (h *AlertRuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.alertService.List(r.Context())
+	if err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Create processa POST /admin/alert-rules
+func (h *AlertRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.AlertRuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.alertService.Create(r.Context(), input)
+	if err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Update processa PUT /admin/alert-rules/{id} e substitui a regra inteira
+func (h *AlertRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.AlertRuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.alertService.Update(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Delete processa DELETE /admin/alert-rules/{id}
+func (h *AlertRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.alertService.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeAlertRuleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
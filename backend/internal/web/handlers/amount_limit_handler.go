@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AmountLimitHandler processa as faixas de valor por fatura próprias das contas
+type AmountLimitHandler struct {
+	amountLimitService *service.AmountLimitService
+}
+
+// NewAmountLimitHandler cria um novo handler de faixas de valor
+func NewAmountLimitHandler(amountLimitService *service.AmountLimitService) *AmountLimitHandler {
+	return &AmountLimitHandler{amountLimitService: amountLimitService}
+}
+
+// writeAmountLimitError traduz os erros das faixas de valor em status HTTP
+func writeAmountLimitError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidAmountLimits:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeAmountLimits responde as faixas da conta
+func writeAmountLimits(w http.ResponseWriter, output *dto.AmountLimitsOutput, err error) {
+	if err != nil {
+		writeAmountLimitError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /admin/accounts/{id}/amount-limits
+func (h *AmountLimitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.amountLimitService.Get(r.Context(), chi.URLParam(r, "id"))
+	writeAmountLimits(w, output, err)
+}
+
+// Update processa PUT /admin/accounts/{id}/amount-limits
+// A faixa informada substitui a padrão para a conta; os limites por meio de pagamento continuam valendo
+func (h *AmountLimitHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.AmountRangeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.amountLimitService.Update(r.Context(), chi.URLParam(r, "id"), input)
+	writeAmountLimits(w, output, err)
+}
+
+// Reset processa DELETE /admin/accounts/{id}/amount-limits, que volta a conta para a faixa padrão
+func (h *AmountLimitHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	output, err := h.amountLimitService.Reset(r.Context(), chi.URLParam(r, "id"))
+	writeAmountLimits(w, output, err)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AnticipationHandler processa as antecipações dos valores que aguardam a data de repasse
+type AnticipationHandler struct {
+	anticipationService *service.AnticipationService
+}
+
+// NewAnticipationHandler cria um novo handler de antecipações
+func NewAnticipationHandler(anticipationService *service.AnticipationService) *AnticipationHandler {
+	return &AnticipationHandler{anticipationService: anticipationService}
+}
+
+// writeAnticipationError traduz os erros das antecipações em status HTTP
+func writeAnticipationError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrNothingToAnticipate:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// enabled responde 503 enquanto a taxa de antecipação não estiver configurada
+func (h *AnticipationHandler) enabled(w http.ResponseWriter) bool {
+	if !h.anticipationService.Enabled() {
+		http.Error(w, "anticipation is not configured", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// Quote processa GET /accounts/anticipations/quote
+func (h *AnticipationHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	output, err := h.anticipationService.Quote(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Create processa POST /accounts/anticipations
+// O corpo é opcional; sem invoice_ids antecipa os valores de todas as faturas
+func (h *AnticipationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.AnticipationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.anticipationService.Request(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/anticipations
+func (h *AnticipationHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.anticipationService.List(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
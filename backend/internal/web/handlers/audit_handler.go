@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AuditHandler processa as consultas administrativas à trilha de auditoria
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler cria um novo handler de auditoria
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List processa GET /admin/audit-logs
+// Filtros opcionais: entity, entity_id, actor, request_id, created_from, created_to (RFC3339 ou AAAA-MM-DD),
+// limit e cursor
+// O cursor da próxima página vem no header X-Next-Cursor
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	input, err := parseAuditLogFilterInput(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.auditService.List(r.Context(), input)
+	if err != nil {
+		writeAuditError(w, err)
+		return
+	}
+
+	setNextCursor(w, output.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output.Entries)
+}
+
+// Export processa GET /admin/audit-logs/export
+// Aceita os mesmos filtros da listagem e retorna em CSV todas as entradas encontradas, sem paginação
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	input, err := parseAuditLogFilterInput(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-logs.csv"`)
+	body := &startedWriter{ResponseWriter: w}
+	if err := h.auditService.Export(r.Context(), input, body); err != nil {
+		// Com parte do CSV já enviada, a conexão é interrompida para que o cliente não tome o arquivo truncado
+		// por completo
+		if body.started {
+			panic(http.ErrAbortHandler)
+		}
+		w.Header().Del("Content-Disposition")
+		writeAuditError(w, err)
+	}
+}
+
+// startedWriter registra se o corpo da resposta já começou a ser enviado
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// parseAuditLogFilterInput lê os filtros da trilha de auditoria da query string
+func parseAuditLogFilterInput(query url.Values) (dto.AuditLogFilterInput, error) {
+	input := dto.AuditLogFilterInput{
+		Entity:    query.Get("entity"),
+		EntityID:  query.Get("entity_id"),
+		Actor:     query.Get("actor"),
+		RequestID: query.Get("request_id"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	var err error
+	if input.CreatedFrom, err = parseDateParam(query, "created_from"); err != nil {
+		return input, err
+	}
+	if input.CreatedTo, err = parseDateParam(query, "created_to"); err != nil {
+		return input, err
+	}
+	// Uma data sem horário em created_to inclui o dia inteiro
+	if len(query.Get("created_to")) == len(time.DateOnly) {
+		input.CreatedTo = input.CreatedTo.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return input, fmt.Errorf("invalid limit")
+		}
+		input.Limit = value
+	}
+	return input, nil
+}
+
+// writeAuditError converte os erros da consulta de auditoria em respostas HTTP
+func writeAuditError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidCursor, domain.ErrInvalidDateRange:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AuthHandler processa o cadastro e o login dos usuários do dashboard
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+// NewAuthHandler cria um novo handler de autenticação
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// CreateUser processa POST /users
+// Request autenticação via X-API-KEY, assinatura HMAC ou JWT; o usuário é vinculado à conta autenticada
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.CreateUserInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.authService.CreateUser(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		switch err {
+		case domain.ErrWeakPassword, domain.ErrInvalidRole:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrForbidden:
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case domain.ErrDuplicatedEmail:
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Login processa POST /auth/login
+// Retorna o JWT de acesso e o refresh token ou 401 se as credenciais não conferem
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.LoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.authService.Login(r.Context(), input)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Refresh processa POST /auth/refresh
+// O refresh token enviado é revogado e um novo par é retornado
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.authService.Refresh(r.Context(), input)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Logout processa POST /auth/logout
+// Revoga o refresh token e retorna 204 No Content
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), input); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions processa GET /auth/sessions
+// Lista as sessões ativas do usuário autenticado por JWT
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := dashboardUser(w, r)
+	if !ok {
+		return
+	}
+
+	output, err := h.authService.ListSessions(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// RevokeSession processa DELETE /auth/sessions/{id}
+// Encerra uma sessão do usuário autenticado e retorna 204 No Content ou 404 se ela não estiver ativa
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := dashboardUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.authService.RevokeSession(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		switch err {
+		case domain.ErrSessionNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePassword processa PUT /auth/password
+// Troca a senha do usuário autenticado e encerra todas as suas sessões; retorna 204 No Content
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := dashboardUser(w, r)
+	if !ok {
+		return
+	}
+
+	var input dto.ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.ChangePassword(r.Context(), userID, input)
+	if err != nil {
+		switch err {
+		case domain.ErrWeakPassword:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrInvalidCredentials:
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Cookies que guardam state e nonce entre o redirecionamento ao provedor e o callback
+const (
+	oidcStateCookie = "oidc_state"
+	oidcNonceCookie = "oidc_nonce"
+	oidcCookiePath  = "/auth/oidc"
+	oidcCookieTTL   = 10 * time.Minute
+)
+
+// OIDCLogin processa GET /auth/oidc/login
+// Redireciona ao provedor de identidade guardando state e nonce em cookies de curta duração
+func (h *AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.authService.OIDCEnabled() {
+		http.Error(w, "single sign-on is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	loginURL, state, nonce, err := h.authService.OIDCLoginURL()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setOIDCCookie(w, oidcStateCookie, state, int(oidcCookieTTL.Seconds()))
+	setOIDCCookie(w, oidcNonceCookie, nonce, int(oidcCookieTTL.Seconds()))
+	http.Redirect(w, r, loginURL, http.StatusFound)
+}
+
+// OIDCCallback processa GET /auth/oidc/callback
+// Confere o state, conclui o login no provedor e retorna o par de tokens do gateway
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if !h.authService.OIDCEnabled() {
+		http.Error(w, "single sign-on is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	state, stateErr := r.Cookie(oidcStateCookie)
+	nonce, nonceErr := r.Cookie(oidcNonceCookie)
+	// Os cookies valem para uma única tentativa
+	setOIDCCookie(w, oidcStateCookie, "", -1)
+	setOIDCCookie(w, oidcNonceCookie, "", -1)
+
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		http.Error(w, "identity provider error: "+query.Get("error"), http.StatusUnauthorized)
+		return
+	}
+	if stateErr != nil || nonceErr != nil || subtle.ConstantTimeCompare([]byte(state.Value), []byte(query.Get("state"))) != 1 {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.authService.OIDCLogin(r.Context(), query.Get("code"), nonce.Value)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// setOIDCCookie grava um cookie restrito às rotas de SSO; maxAge (segundos) negativo o remove
+func setOIDCCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     oidcCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// enabled responde 503 quando JWT_SECRET não foi configurado, como as rotas administrativas sem chave
+func (h *AuthHandler) enabled(w http.ResponseWriter) bool {
+	if !h.authService.Enabled() {
+		http.Error(w, "user authentication is disabled", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// dashboardUser retorna o ID do usuário autenticado por JWT
+// Responde 403 para as demais credenciais, que não têm sessões
+func dashboardUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := strings.CutPrefix(requestctx.Actor(r.Context()), "user:")
+	if !ok {
+		http.Error(w, "sessions are only available to dashboard users", http.StatusForbidden)
+		return "", false
+	}
+	return userID, true
+}
+
+// writeAuthError traduz os erros de credenciais e tokens para 401
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidCredentials, domain.ErrInvalidToken:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
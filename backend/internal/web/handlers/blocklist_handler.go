@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// BlocklistHandler processa o cadastro das listas de bloqueio da conta e da lista global
+type BlocklistHandler struct {
+	blocklistService *service.BlocklistService
+}
+
+// NewBlocklistHandler cria um novo handler de listas de bloqueio
+func NewBlocklistHandler(blocklistService *service.BlocklistService) *BlocklistHandler {
+	return &BlocklistHandler{blocklistService: blocklistService}
+}
+
+// writeBlocklistError traduz os erros das listas de bloqueio em status HTTP
+func writeBlocklistError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidBlocklistEntry:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrBlocklistEntryNotFound, domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrBlocklistEntryExists:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// List processa GET /accounts/blocklist
+func (h *BlocklistHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.blocklistService.List(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Create processa POST /accounts/blocklist
+func (h *BlocklistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.BlocklistEntryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.blocklistService.Create(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Delete processa DELETE /accounts/blocklist/{id}
+func (h *BlocklistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.blocklistService.Delete(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id")); err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListGlobal processa GET /admin/blocklist
+func (h *BlocklistHandler) ListGlobal(w http.ResponseWriter, r *http.Request) {
+	output, err := h.blocklistService.ListGlobal(r.Context())
+	if err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// CreateGlobal processa POST /admin/blocklist
+func (h *BlocklistHandler) CreateGlobal(w http.ResponseWriter, r *http.Request) {
+	var input dto.BlocklistEntryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.blocklistService.CreateGlobal(r.Context(), input)
+	if err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// DeleteGlobal processa DELETE /admin/blocklist/{id}
+func (h *BlocklistHandler) DeleteGlobal(w http.ResponseWriter, r *http.Request) {
+	if err := h.blocklistService.DeleteGlobal(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeBlocklistError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+)
+
+// ConfigReloader recarrega os ajustes do gateway sem reiniciar o processo
+type ConfigReloader interface {
+	Reload(ctx context.Context) (*dto.ConfigOutput, error)
+}
+
+// ConfigHandler expõe o recarregamento da configuração aos administradores
+type ConfigHandler struct {
+	reloader ConfigReloader
+}
+
+// NewConfigHandler cria um novo handler de configuração
+func NewConfigHandler(reloader ConfigReloader) *ConfigHandler {
+	return &ConfigHandler{reloader: reloader}
+}
+
+// Endpoint: /admin/config/reload
+// Method: POST
+// Equivale a enviar SIGHUP ao processo; responde 422 quando algum valor é inválido, sem alterar nada
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	output, err := h.reloader.Reload(r.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidConfig) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
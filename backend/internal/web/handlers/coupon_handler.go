@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// CouponHandler processa os cupons de desconto das contas
+type CouponHandler struct {
+	couponService *service.CouponService
+}
+
+// NewCouponHandler cria um novo handler de cupons
+func NewCouponHandler(couponService *service.CouponService) *CouponHandler {
+	return &CouponHandler{couponService: couponService}
+}
+
+// writeCouponError traduz os erros dos cupons em status HTTP
+func writeCouponError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidCoupon:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrCouponNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrCouponExists:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Create processa POST /accounts/coupons
+func (h *CouponHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCouponInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.couponService.Create(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/coupons
+func (h *CouponHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.couponService.List(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /accounts/coupons/{id}
+func (h *CouponHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.couponService.Get(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Deactivate processa POST /accounts/coupons/{id}/deactivate
+// O cupom deixa de ser aceito nas novas faturas; as já criadas mantêm o desconto
+func (h *CouponHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	output, err := h.couponService.Deactivate(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
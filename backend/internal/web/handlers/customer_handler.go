@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// CustomerHandler processa os clientes das contas e os cartões guardados deles
+type CustomerHandler struct {
+	customerService *service.CustomerService
+}
+
+// NewCustomerHandler cria um novo handler de clientes
+func NewCustomerHandler(customerService *service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{customerService: customerService}
+}
+
+// writeCustomerError traduz os erros dos clientes em status HTTP
+func writeCustomerError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidCustomer, domain.ErrInvalidCardNumber, domain.ErrInvalidCardCVV, domain.ErrInvalidCardExpiry, domain.ErrInvalidCardholderName, domain.ErrTestCardNotAllowed:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrCustomerNotFound, domain.ErrCardNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrPaymentMethodLimit:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrDependencyUnavailable:
+		http.Error(w, "card number retention is not configured", http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Create processa POST /accounts/customers
+// O cartão é opcional; informado, responde 503 enquanto o cofre não guardar o número dos cartões
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCustomerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.customerService.Create(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/customers
+func (h *CustomerHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.customerService.List(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /accounts/customers/{id}
+func (h *CustomerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.customerService.Get(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Delete processa DELETE /accounts/customers/{id}
+// Os cartões guardados do cliente são apagados do cofre; as faturas já criadas mantêm a referência
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.customerService.Delete(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id")); err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddPaymentMethod processa POST /accounts/customers/{id}/payment-methods
+// Responde 503 enquanto o cofre não guardar o número dos cartões, sem o qual o cartão não seria cobrado
+func (h *CustomerHandler) AddPaymentMethod(w http.ResponseWriter, r *http.Request) {
+	if !h.customerService.CardsEnabled() {
+		http.Error(w, "card number retention is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var input dto.CardInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.customerService.AddPaymentMethod(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// RemovePaymentMethod processa DELETE /accounts/customers/{id}/payment-methods/{token}
+func (h *CustomerHandler) RemovePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	err := h.customerService.RemovePaymentMethod(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), chi.URLParam(r, "token"))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// DataSubjectHandler processa os pedidos de titulares de dados (LGPD/GDPR)
+type DataSubjectHandler struct {
+	dataSubjectService *service.DataSubjectService
+}
+
+// NewDataSubjectHandler cria um novo handler de pedidos de titulares
+func NewDataSubjectHandler(dataSubjectService *service.DataSubjectService) *DataSubjectHandler {
+	return &DataSubjectHandler{dataSubjectService: dataSubjectService}
+}
+
+// writeDataSubjectError traduz os erros dos pedidos de titulares em status HTTP
+func writeDataSubjectError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidDataSubject:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound, domain.ErrDataSubjectNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Export processa POST /accounts/data-subjects/export
+// O nome do pagador vai no corpo, e não na URL, para não ficar nos logs de acesso
+func (h *DataSubjectHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var input dto.DataSubjectInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.dataSubjectService.Export(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeDataSubjectError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="data-subject-export.json"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Anonymize processa POST /accounts/data-subjects/anonymize
+func (h *DataSubjectHandler) Anonymize(w http.ResponseWriter, r *http.Request) {
+	var input dto.DataSubjectInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.dataSubjectService.Anonymize(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeDataSubjectError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// ListRequests processa GET /accounts/data-subjects/requests
+func (h *DataSubjectHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	output, err := h.dataSubjectService.ListRequests(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeDataSubjectError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// Limites do upload multipart das evidências: a folga cobre a descrição e os delimitadores além do arquivo, e o
+// que passar de disputeEvidenceMemory vai para arquivos temporários
+const (
+	disputeEvidenceFormOverhead = 64 << 10
+	disputeEvidenceMemory       = 1 << 20
+)
+
+// DisputeHandler processa as disputas das faturas e o envio e o download das evidências
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+}
+
+// NewDisputeHandler cria um novo handler de disputas
+func NewDisputeHandler(disputeService *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{disputeService: disputeService}
+}
+
+// writeDisputeError traduz os erros das disputas e das evidências em status HTTP
+func writeDisputeError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidDispute, domain.ErrInvalidDisputeResolution, domain.ErrInvalidDisputeEvidence:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound, domain.ErrInvoiceNotFound, domain.ErrDisputeNotFound, domain.ErrDisputeEvidenceNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidStatus, domain.ErrTransactionAlreadyDisputed, domain.ErrDisputeClosed:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrDisputeEvidenceTooLarge:
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case domain.ErrUnsupportedDisputeEvidence:
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// evidenceEnabled responde 503 enquanto EXPORT_STORAGE não estiver configurado
+func (h *DisputeHandler) evidenceEnabled(w http.ResponseWriter) bool {
+	if !h.disputeService.EvidenceEnabled() {
+		http.Error(w, "evidence storage is not configured", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeDispute responde a disputa em JSON
+func writeDispute(w http.ResponseWriter, status int, output any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Open processa POST /admin/invoices/{id}/disputes
+func (h *DisputeHandler) Open(w http.ResponseWriter, r *http.Request) {
+	var input dto.OpenDisputeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.disputeService.Open(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusCreated, output)
+}
+
+// Resolve processa POST /admin/disputes/{id}/resolve
+func (h *DisputeHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	var input dto.ResolveDisputeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.disputeService.Resolve(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusOK, output)
+}
+
+// ListAll processa GET /admin/disputes
+// Query param opcional: status (open, won ou lost)
+func (h *DisputeHandler) ListAll(w http.ResponseWriter, r *http.Request) {
+	output, err := h.disputeService.ListAll(r.Context(), domain.DisputeStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusOK, output)
+}
+
+// List processa GET /accounts/disputes
+// Query param opcional: status (open, won ou lost)
+func (h *DisputeHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.disputeService.List(r.Context(), requestctx.APIKey(r.Context()), domain.DisputeStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusOK, output)
+}
+
+// Get processa GET /accounts/disputes/{id} e retorna a disputa com as evidências
+func (h *DisputeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.disputeService.Get(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusOK, output)
+}
+
+// AddEvidence processa POST /accounts/disputes/{id}/evidence
+// Recebe multipart/form-data com o arquivo no campo file e a descrição opcional no campo description
+func (h *DisputeHandler) AddEvidence(w http.ResponseWriter, r *http.Request) {
+	if !h.evidenceEnabled(w) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxDisputeEvidenceSize+disputeEvidenceFormOverhead)
+	if err := r.ParseMultipartForm(disputeEvidenceMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeDisputeError(w, domain.ErrDisputeEvidenceTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	// Um byte além do limite basta para recusar o arquivo grande demais
+	body, err := io.ReadAll(io.LimitReader(file, domain.MaxDisputeEvidenceSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input := dto.DisputeEvidenceInput{
+		FileName:    header.Filename,
+		Description: r.FormValue("description"),
+		Body:        body,
+	}
+	output, err := h.disputeService.AddEvidence(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	writeDispute(w, http.StatusCreated, output)
+}
+
+// GetEvidence processa GET /accounts/disputes/{id}/evidence/{evidence_id} e serve o arquivo da evidência
+func (h *DisputeHandler) GetEvidence(w http.ResponseWriter, r *http.Request) {
+	if !h.evidenceEnabled(w) {
+		return
+	}
+
+	evidence, body, err := h.disputeService.Evidence(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), chi.URLParam(r, "evidence_id"))
+	if err != nil {
+		writeDisputeError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", evidence.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(evidence.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": evidence.FileName}))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
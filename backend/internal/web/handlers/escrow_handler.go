@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// EscrowHandler processa a liberação dos valores em custódia das faturas
+type EscrowHandler struct {
+	escrowService *service.EscrowService
+}
+
+// NewEscrowHandler cria um novo handler de custódia
+func NewEscrowHandler(escrowService *service.EscrowService) *EscrowHandler {
+	return &EscrowHandler{escrowService: escrowService}
+}
+
+// Release processa POST /invoice/{id}/release
+// Libera antes do prazo o valor em custódia da fatura, por exemplo na confirmação da entrega
+// Retorna 200 com as retenções da fatura, 404 se ela não tiver valores em custódia ou 409 se já foram liberados
+func (h *EscrowHandler) Release(w http.ResponseWriter, r *http.Request) {
+	output, err := h.escrowService.Release(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		switch err {
+		case domain.ErrInvoiceNotFound, domain.ErrEscrowNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case domain.ErrUnauthorizedAccess:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case domain.ErrEscrowAlreadyReleased:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// ExportHandler gera relatórios e exportações e serve os downloads pelos links assinados
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler cria um novo handler de exportações
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// enabled responde 503 enquanto EXPORT_STORAGE não estiver configurado
+func (h *ExportHandler) enabled(w http.ResponseWriter) bool {
+	if !h.exportService.Enabled() {
+		http.Error(w, "export storage is not configured", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeExportError traduz os erros das exportações em status HTTP
+func writeExportError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidStatus, domain.ErrInvalidAmount, domain.ErrInvalidDateRange, domain.ErrInvalidDataSubject:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrInvalidDownloadLink:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrAccountNotFound, domain.ErrExportNotFound, domain.ErrDataSubjectNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeExport responde a exportação com o link de download
+func writeExport(w http.ResponseWriter, status int, output *dto.ExportOutput) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(output)
+}
+
+// CreateInvoiceReport processa POST /invoice/exports
+// Aceita os mesmos filtros da listagem de faturas na query string, sem paginação
+func (h *ExportHandler) CreateInvoiceReport(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	input, err := parseListInvoicesInput(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.APIKey = requestctx.APIKey(r.Context())
+
+	output, err := h.exportService.CreateInvoiceReport(r.Context(), input)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	writeExport(w, http.StatusCreated, output)
+}
+
+// GetInvoiceReport processa GET /invoice/exports/{id} e emite um novo link para o relatório
+func (h *ExportHandler) GetInvoiceReport(w http.ResponseWriter, r *http.Request) {
+	h.link(w, r, domain.ExportInvoices)
+}
+
+// CreateDataSubjectExport processa POST /accounts/data-subjects/exports
+// Como POST /accounts/data-subjects/export, mas guarda o arquivo e responde com um link de download
+func (h *ExportHandler) CreateDataSubjectExport(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var input dto.DataSubjectInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.exportService.CreateDataSubjectExport(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	writeExport(w, http.StatusCreated, output)
+}
+
+// GetDataSubjectExport processa GET /accounts/data-subjects/exports/{id} e emite um novo link para a exportação
+func (h *ExportHandler) GetDataSubjectExport(w http.ResponseWriter, r *http.Request) {
+	h.link(w, r, domain.ExportDataSubject)
+}
+
+// link emite um novo link para a exportação do tipo informado
+func (h *ExportHandler) link(w http.ResponseWriter, r *http.Request, exportType domain.ExportType) {
+	if !h.enabled(w) {
+		return
+	}
+
+	output, err := h.exportService.Link(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), exportType)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	writeExport(w, http.StatusOK, output)
+}
+
+// Download processa GET /downloads/{id}?expires=&signature=
+// Não exige autenticação: o link assinado é a credencial, por isso só vale até expires
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	query := r.URL.Query()
+	export, body, err := h.exportService.Download(r.Context(), chi.URLParam(r, "id"), query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(export.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// FeatureFlagHandler expõe as flags em uso e a decisão delas por conta
+type FeatureFlagHandler struct {
+	service *service.FeatureFlagService
+}
+
+// NewFeatureFlagHandler cria um novo handler de flags
+func NewFeatureFlagHandler(service *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{service: service}
+}
+
+// Endpoint: /accounts/flags
+// Method: GET
+func (h *FeatureFlagHandler) ForAccount(w http.ResponseWriter, r *http.Request) {
+	apiKey := requestctx.APIKey(r.Context())
+	if apiKey == "" {
+		http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
+		return
+	}
+
+	output, err := h.service.ForAccount(r.Context(), apiKey)
+	if err != nil {
+		switch err {
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /admin/flags
+func (h *FeatureFlagHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.service.List())
+}
+
+// ForAccountID processa GET /admin/accounts/{id}/flags
+func (h *FeatureFlagHandler) ForAccountID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ForAccountID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
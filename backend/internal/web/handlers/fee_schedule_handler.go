@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// FeeScheduleHandler processa as tarifas das contas
+type FeeScheduleHandler struct {
+	feeService *service.FeeScheduleService
+}
+
+// NewFeeScheduleHandler cria um novo handler de tarifas
+func NewFeeScheduleHandler(feeService *service.FeeScheduleService) *FeeScheduleHandler {
+	return &FeeScheduleHandler{feeService: feeService}
+}
+
+// writeFeeScheduleError traduz os erros das tarifas em status HTTP
+func writeFeeScheduleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidFeeSchedule:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeFeeSchedule responde as tarifas em JSON
+func writeFeeSchedule(w http.ResponseWriter, output *dto.FeeScheduleOutput) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /accounts/fees
+func (h *FeeScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.feeService.Get(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeFeeScheduleError(w, err)
+		return
+	}
+	writeFeeSchedule(w, output)
+}
+
+// GetForAccount processa GET /admin/accounts/{id}/fees
+func (h *FeeScheduleHandler) GetForAccount(w http.ResponseWriter, r *http.Request) {
+	output, err := h.feeService.GetForAccount(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeFeeScheduleError(w, err)
+		return
+	}
+	writeFeeSchedule(w, output)
+}
+
+// Update processa PUT /admin/accounts/{id}/fees
+func (h *FeeScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.FeeScheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.feeService.Update(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeFeeScheduleError(w, err)
+		return
+	}
+	writeFeeSchedule(w, output)
+}
+
+// Reset processa DELETE /admin/accounts/{id}/fees e volta a conta às tarifas padrão
+func (h *FeeScheduleHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	output, err := h.feeService.Reset(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeFeeScheduleError(w, err)
+		return
+	}
+	writeFeeSchedule(w, output)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// GeoPolicyHandler processa a consulta e a alteração da política geográfica da conta
+type GeoPolicyHandler struct {
+	geoService *service.GeoRiskService
+}
+
+// NewGeoPolicyHandler cria um novo handler de política geográfica
+func NewGeoPolicyHandler(geoService *service.GeoRiskService) *GeoPolicyHandler {
+	return &GeoPolicyHandler{geoService: geoService}
+}
+
+// Get processa GET /accounts/geo-policy
+func (h *GeoPolicyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.geoService.GetPolicy(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		switch err {
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Update processa PUT /accounts/geo-policy
+// Substitui a política inteira; responde 503 enquanto GEOIP_DATABASE não estiver configurada
+func (h *GeoPolicyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !h.geoService.Enabled() {
+		http.Error(w, "geoip database is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var input dto.GeoPolicyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.geoService.UpdatePolicy(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidGeoPolicy:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
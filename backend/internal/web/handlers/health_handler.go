@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// HealthHandler expõe as verificações de saúde para orquestradores e balanceadores
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler cria um novo handler de saúde
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Readyz processa GET /readyz
+// Responde 503 quando uma dependência crítica está indisponível ou saturada para que o tráfego seja desviado
+// Com apenas dependências opcionais fora do ar responde 200 com status "warning"
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	output := h.healthService.Readiness(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if output.Status != dto.ReadinessOK && output.Status != dto.ReadinessWarning {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(output)
+}
+
+// Status processa GET /status
+// Sempre responde 200: o status geral repete o de /readyz, mas quem decide o tráfego é /readyz
+func (h *HealthHandler) Status(w http.ResponseWriter, r *http.Request) {
+	output := h.healthService.Status(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -2,12 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
-	"github.com/go-chi/chi/v5"
 )
 
 type InvoiceHandler struct {
@@ -20,7 +28,7 @@ func NewInvoiceHandler(service *service.InvoiceService) *InvoiceHandler {
 	}
 }
 
-// Request autenticação via X-API-KEY
+// Request autenticação via X-API-KEY, assinatura HMAC ou JWT
 // Endpoint: /invoice
 // Method: POST
 func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -31,19 +39,108 @@ func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	input.APIKey = r.Header.Get("X-API-KEY")
+	input.APIKey = requestctx.APIKey(r.Context())
 
-	output, err := h.service.Create(input)
+	output, err := h.service.Create(r.Context(), input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if writeInvoiceLimitError(w, err) {
+			return
+		}
+		switch err {
+		case domain.ErrInvalidAmount, domain.ErrInvalidInvoiceItems, domain.ErrInvalidCustomer, domain.ErrInvalidSplit, domain.ErrInvalidEscrow, domain.ErrInvalidDueDate, domain.ErrInvalidCurrency, domain.ErrInvalidCardNumber, domain.ErrInvalidCardCVV, domain.ErrInvalidCardExpiry, domain.ErrInvalidCardholderName, domain.ErrTestCardNotAllowed:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrCouponNotFound, domain.ErrCouponUnavailable, domain.ErrCustomerNotFound, domain.ErrCardNotFound, domain.ErrPaymentMethodLimit:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		case domain.ErrCountryNotAllowed, domain.ErrBlocklisted:
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case domain.ErrDependencyUnavailable:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		case domain.ErrProcessorTimeout:
+			http.Error(w, err.Error(), http.StatusGatewayTimeout)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Request autenticação via X-API-KEY, assinatura HMAC ou JWT
+// Endpoint: /invoice/batch
+// Method: POST
+func (h *InvoiceHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateInvoiceBatchInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	input.APIKey = requestctx.APIKey(r.Context())
+
+	output, err := h.service.CreateBatch(r.Context(), input)
+	if err != nil {
+		if writeInvoiceLimitError(w, err) {
+			return
+		}
+		switch err {
+		case domain.ErrInvalidBatchSize, domain.ErrInvalidCoupon, domain.ErrInvalidCustomer, domain.ErrInvalidAmount, domain.ErrInvalidInvoiceItems, domain.ErrInvalidSplit, domain.ErrInvalidEscrow, domain.ErrInvalidDueDate, domain.ErrInvalidCurrency, domain.ErrInvalidCardNumber, domain.ErrInvalidCardCVV, domain.ErrInvalidCardExpiry, domain.ErrInvalidCardholderName, domain.ErrTestCardNotAllowed:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrCountryNotAllowed, domain.ErrBlocklisted:
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		case domain.ErrDependencyUnavailable:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		case domain.ErrProcessorTimeout:
+			http.Error(w, err.Error(), http.StatusGatewayTimeout)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(output)
 }
 
+// writeInvoiceLimitError responde as recusas pelos limites da conta e retorna false para os demais erros
+// A faixa de valor responde 400 com o mínimo e o máximo permitidos; o teto de gasto responde 422 com o período estourado e quanto ainda pode ser faturado nele; a regra de
+// frequência responde 429 com Retry-After, sem revelar a regra a quem testa cartões
+func writeInvoiceLimitError(w http.ResponseWriter, err error) bool {
+	var transactionErr *domain.TransactionLimitError
+	var velocityErr *domain.VelocityLimitError
+	var amountErr *domain.AmountLimitError
+	switch {
+	case errors.As(err, &amountErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(dto.FromAmountLimitError(amountErr))
+		return true
+	case errors.As(err, &transactionErr):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(dto.FromTransactionLimitError(transactionErr))
+		return true
+	case errors.As(err, &velocityErr):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(velocityErr.RetryAfter.Seconds()))))
+		http.Error(w, domain.ErrVelocityLimitExceeded.Error(), http.StatusTooManyRequests)
+		return true
+	}
+	return false
+}
+
 // Endpoint: /invoice/{id}
 // Method: GET
 func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -53,13 +150,13 @@ func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	apiKey := r.Header.Get("X-API-KEY")
+	apiKey := requestctx.APIKey(r.Context())
 	if apiKey == "" {
 		http.Error(w, "X-API-KEY is required", http.StatusBadRequest)
 		return
 	}
 
-	output, err := h.service.GetByID(id, apiKey)
+	output, err := h.service.GetByID(r.Context(), id, apiKey)
 	if err != nil {
 		switch err {
 		case domain.ErrInvoiceNotFound:
@@ -81,27 +178,151 @@ func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(output)
 }
 
+// Endpoint: /accounts/stats
+// Method: GET
+// Parâmetro opcional: period em dias ou horas (30d por padrão, até 366d)
+func (h *InvoiceHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	apiKey := requestctx.APIKey(r.Context())
+	if apiKey == "" {
+		http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
+		return
+	}
+
+	output, err := h.service.Stats(r.Context(), apiKey, r.URL.Query().Get("period"))
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidStatsPeriod:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
 // Endpoint: /invoice
 // Method: GET
+// Filtros opcionais: status (separados por vírgula), created_from, created_to (RFC3339 ou AAAA-MM-DD),
+// min_amount, max_amount, metadata.<chave>=<valor> e search (busca textual ordenada por relevância)
+// Paginação opcional: limit e cursor; o cursor da próxima página vem no header X-Next-Cursor
 func (h *InvoiceHandler) ListByAccount(w http.ResponseWriter, r *http.Request) {
-	apiKey := r.Header.Get("X-API-KEY")
+	apiKey := requestctx.APIKey(r.Context())
 	if apiKey == "" {
 		http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
 		return
 	}
 
-	output, err := h.service.ListByAccountAPIKey(apiKey)
+	input, err := parseListInvoicesInput(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.APIKey = apiKey
+
+	output, err := h.service.Search(r.Context(), input)
 	if err != nil {
 		switch err {
 		case domain.ErrAccountNotFound:
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
+		case domain.ErrInvalidStatus, domain.ErrInvalidAmount, domain.ErrInvalidDateRange, domain.ErrInvalidCursor:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
+	setNextCursor(w, output.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(output)
+	json.NewEncoder(w).Encode(output.Invoices)
+}
+
+// parseListInvoicesInput converte a query string nos filtros de listagem
+func parseListInvoicesInput(query url.Values) (dto.ListInvoicesInput, error) {
+	var input dto.ListInvoicesInput
+	var err error
+
+	if status := query.Get("status"); status != "" {
+		input.Statuses = strings.Split(status, ",")
+	}
+
+	if input.CreatedFrom, err = parseDateParam(query, "created_from"); err != nil {
+		return input, err
+	}
+	if input.CreatedTo, err = parseDateParam(query, "created_to"); err != nil {
+		return input, err
+	}
+	// Uma data sem horário em created_to inclui o dia inteiro
+	if len(query.Get("created_to")) == len(time.DateOnly) {
+		input.CreatedTo = input.CreatedTo.Add(24*time.Hour - time.Nanosecond)
+	}
+	if input.MinAmount, err = parseAmountParam(query, "min_amount"); err != nil {
+		return input, err
+	}
+	if input.MaxAmount, err = parseAmountParam(query, "max_amount"); err != nil {
+		return input, err
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return input, fmt.Errorf("invalid limit: %q", limit)
+		}
+		input.Limit = value
+	}
+	input.Cursor = query.Get("cursor")
+	input.Search = strings.TrimSpace(query.Get("search"))
+
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "metadata.")
+		if !ok || name == "" || len(values) == 0 {
+			continue
+		}
+		if input.Metadata == nil {
+			input.Metadata = map[string]string{}
+		}
+		input.Metadata[name] = values[0]
+	}
+
+	return input, nil
+}
+
+// parseDateParam aceita datas em RFC3339 ou no formato AAAA-MM-DD
+func parseDateParam(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return t, nil
+}
+
+// parseAmountParam converte um valor monetário opcional da query string
+func parseAmountParam(query url.Values, name string) (float64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return amount, nil
 }
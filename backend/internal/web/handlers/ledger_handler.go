@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// LedgerHandler processa o extrato do razão das contas
+type LedgerHandler struct {
+	ledgerService *service.LedgerService
+}
+
+// NewLedgerHandler cria um novo handler do extrato
+func NewLedgerHandler(ledgerService *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// Statement processa GET /accounts/statement
+// Parâmetro opcional: period em dias ou horas (30d por padrão, até 366d)
+func (h *LedgerHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	output, err := h.ledgerService.Statement(r.Context(), requestctx.APIKey(r.Context()), r.URL.Query().Get("period"))
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidStatsPeriod:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import "net/http"
+
+// nextCursorHeader expõe o cursor opaco da próxima página sem alterar o corpo das listagens
+const nextCursorHeader = "X-Next-Cursor"
+
+// setNextCursor informa o cursor da próxima página; na última página o header é omitido
+func setNextCursor(w http.ResponseWriter, cursor string) {
+	if cursor != "" {
+		w.Header().Set(nextCursorHeader, cursor)
+	}
+}
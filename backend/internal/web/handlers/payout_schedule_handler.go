@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// PayoutScheduleHandler processa o prazo de repasse das contas
+type PayoutScheduleHandler struct {
+	payoutScheduleService *service.PayoutScheduleService
+}
+
+// NewPayoutScheduleHandler cria um novo handler de prazos de repasse
+func NewPayoutScheduleHandler(payoutScheduleService *service.PayoutScheduleService) *PayoutScheduleHandler {
+	return &PayoutScheduleHandler{payoutScheduleService: payoutScheduleService}
+}
+
+// writePayoutScheduleError traduz os erros dos prazos de repasse em status HTTP
+func writePayoutScheduleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidPayoutSchedule:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Get processa GET /accounts/payout-schedule
+func (h *PayoutScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.payoutScheduleService.Get(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writePayoutScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Update processa PUT /accounts/payout-schedule
+// O novo prazo vale para as faturas aprovadas depois da troca
+func (h *PayoutScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.PayoutScheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.payoutScheduleService.Update(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writePayoutScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// PlatformHandler processa as ligações das contas filhas com as plataformas
+type PlatformHandler struct {
+	platformService *service.PlatformService
+}
+
+// NewPlatformHandler cria um novo handler de plataformas
+func NewPlatformHandler(platformService *service.PlatformService) *PlatformHandler {
+	return &PlatformHandler{platformService: platformService}
+}
+
+// writePlatformError traduz os erros das ligações com plataformas em status HTTP
+func writePlatformError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidPlatformLink:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrPlatformLinkNotFound, domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Get processa GET /admin/accounts/{id}/platform
+func (h *PlatformHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.platformService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writePlatformError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Link processa PUT /admin/accounts/{id}/platform
+func (h *PlatformHandler) Link(w http.ResponseWriter, r *http.Request) {
+	var input dto.PlatformLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.platformService.Link(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writePlatformError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Unlink processa DELETE /admin/accounts/{id}/platform
+func (h *PlatformHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	if err := h.platformService.Unlink(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writePlatformError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
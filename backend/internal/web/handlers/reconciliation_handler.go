@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// ReconciliationHandler processa a conciliação dos saldos das contas com o razão
+type ReconciliationHandler struct {
+	reconciliationService *service.ReconciliationService
+}
+
+// NewReconciliationHandler cria um novo handler de conciliação
+func NewReconciliationHandler(reconciliationService *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconciliationService: reconciliationService}
+}
+
+// writeReconciliationError traduz os erros da conciliação em status HTTP
+func writeReconciliationError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// List processa GET /admin/reconciliation
+// Concilia todas as contas sem alterar nada e responde só as divergentes
+func (h *ReconciliationHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.reconciliationService.CheckAll(r.Context())
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /admin/accounts/{id}/reconciliation
+func (h *ReconciliationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.reconciliationService.Check(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Correct processa POST /admin/accounts/{id}/reconciliation
+// Corrige o saldo divergente para o valor do razão; a auditoria da conta registra o saldo anterior e quem corrigiu
+func (h *ReconciliationHandler) Correct(w http.ResponseWriter, r *http.Request) {
+	output, err := h.reconciliationService.Correct(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
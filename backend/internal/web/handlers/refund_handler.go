@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// RefundHandler processa os reembolsos das faturas e a política de aprovação das contas
+type RefundHandler struct {
+	refundService *service.RefundService
+}
+
+// NewRefundHandler cria um novo handler de reembolsos
+func NewRefundHandler(refundService *service.RefundService) *RefundHandler {
+	return &RefundHandler{refundService: refundService}
+}
+
+// writeRefundError traduz os erros dos reembolsos em status HTTP
+func writeRefundError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidRefund, domain.ErrInvalidRefundPolicy, domain.ErrInvalidRefundDecision, domain.ErrInvalidRefundWindow:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrRefundSelfApproval:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrInvoiceNotFound, domain.ErrRefundNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidStatus, domain.ErrTransactionAlreadyRefunded, domain.ErrRefundAlreadyDecided, domain.ErrTransactionNotAllowed:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrInsufficientBalance:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeAdminRefundError traduz os erros das rotas administrativas de reembolso, em que a conta não encontrada é a
+// do caminho e não a autenticada
+func writeAdminRefundError(w http.ResponseWriter, err error) {
+	if err == domain.ErrAccountNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeRefundError(w, err)
+}
+
+// writeRefundWindow responde o prazo de reembolso da conta
+func writeRefundWindow(w http.ResponseWriter, output *dto.RefundWindowOutput, err error) {
+	if err != nil {
+		writeAdminRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// GetPolicy processa GET /accounts/refund-policy
+func (h *RefundHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	output, err := h.refundService.GetPolicy(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// UpdatePolicy processa PUT /accounts/refund-policy
+func (h *RefundHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	var input dto.RefundPolicyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.refundService.UpdatePolicy(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Request processa POST /invoice/{id}/refunds
+// Retorna 201 com o reembolso concluído ou, acima do limite da política da conta, pendente de aprovação
+func (h *RefundHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var input dto.RefundInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.refundService.Request(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/refunds
+// Query param opcional: status (pending, completed, rejected ou expired)
+func (h *RefundHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.RefundStatus(r.URL.Query().Get("status"))
+	output, err := h.refundService.List(r.Context(), requestctx.APIKey(r.Context()), status)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Decide processa POST /accounts/refunds/{id}/decision
+func (h *RefundHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	var input dto.RefundDecisionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.refundService.Decide(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Force processa POST /admin/invoices/{id}/refunds
+// Reembolsa a fatura de qualquer conta mesmo fora do prazo de reembolso e sem a aprovação da política da conta
+func (h *RefundHandler) Force(w http.ResponseWriter, r *http.Request) {
+	var input dto.RefundInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.refundService.Force(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeAdminRefundError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// GetWindow processa GET /admin/accounts/{id}/refund-window
+func (h *RefundHandler) GetWindow(w http.ResponseWriter, r *http.Request) {
+	output, err := h.refundService.GetWindow(r.Context(), chi.URLParam(r, "id"))
+	writeRefundWindow(w, output, err)
+}
+
+// UpdateWindow processa PUT /admin/accounts/{id}/refund-window
+// O prazo informado substitui o padrão para a conta; 0 desativa o prazo
+func (h *RefundHandler) UpdateWindow(w http.ResponseWriter, r *http.Request) {
+	var input dto.RefundWindowInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.refundService.UpdateWindow(r.Context(), chi.URLParam(r, "id"), input)
+	writeRefundWindow(w, output, err)
+}
+
+// ResetWindow processa DELETE /admin/accounts/{id}/refund-window, que volta a conta para o prazo padrão
+func (h *RefundHandler) ResetWindow(w http.ResponseWriter, r *http.Request) {
+	output, err := h.refundService.ResetWindow(r.Context(), chi.URLParam(r, "id"))
+	writeRefundWindow(w, output, err)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// RiskReviewHandler processa os limites de score de risco das contas e a fila de revisão manual
+type RiskReviewHandler struct {
+	reviewService *service.RiskReviewService
+}
+
+// NewRiskReviewHandler cria um novo handler de limites de score e revisão manual
+func NewRiskReviewHandler(reviewService *service.RiskReviewService) *RiskReviewHandler {
+	return &RiskReviewHandler{reviewService: reviewService}
+}
+
+// writeRiskReviewError traduz os erros dos limites de score e da fila de revisão em status HTTP
+func writeRiskReviewError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidRiskPolicy, domain.ErrInvalidReviewDecision:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrReviewNotFound, domain.ErrInvoiceNotFound, domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrReviewAlreadyDecided:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetPolicy processa GET /accounts/risk-policy
+func (h *RiskReviewHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	output, err := h.reviewService.GetPolicy(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeRiskReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// UpdatePolicy processa PUT /accounts/risk-policy
+func (h *RiskReviewHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	var input dto.RiskPolicyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.reviewService.UpdatePolicy(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeRiskReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/reviews
+// Query param opcional: status (open, approved ou declined)
+func (h *RiskReviewHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.ReviewStatus(r.URL.Query().Get("status"))
+	output, err := h.reviewService.List(r.Context(), requestctx.APIKey(r.Context()), status)
+	if err != nil {
+		writeRiskReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// ListAll processa GET /admin/reviews
+// Query params opcionais: status (open, approved ou declined) e account_id
+func (h *RiskReviewHandler) ListAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	output, err := h.reviewService.ListAll(r.Context(), query.Get("account_id"), domain.ReviewStatus(query.Get("status")))
+	if err != nil {
+		writeRiskReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Decide processa POST /admin/reviews/{invoice_id}/decision
+func (h *RiskReviewHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	var input dto.ReviewDecisionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.reviewService.Decide(r.Context(), chi.URLParam(r, "invoice_id"), input)
+	if err != nil {
+		writeRiskReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// SecurityHandler processa as consultas da conta aos próprios eventos de segurança
+type SecurityHandler struct {
+	securityService *service.SecurityService
+}
+
+// NewSecurityHandler cria um novo handler de eventos de segurança
+func NewSecurityHandler(securityService *service.SecurityService) *SecurityHandler {
+	return &SecurityHandler{securityService: securityService}
+}
+
+// ListEvents processa GET /accounts/security/events
+// Filtros opcionais: success (true ou false), limit e cursor
+// O cursor da próxima página vem no header X-Next-Cursor
+func (h *SecurityHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := dto.AuthEventFilterInput{Cursor: query.Get("cursor")}
+
+	if success := query.Get("success"); success != "" {
+		value, err := strconv.ParseBool(success)
+		if err != nil {
+			http.Error(w, "invalid success", http.StatusBadRequest)
+			return
+		}
+		input.Success = &value
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		input.Limit = value
+	}
+
+	output, err := h.securityService.ListEvents(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidCursor:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	setNextCursor(w, output.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output.Events)
+}
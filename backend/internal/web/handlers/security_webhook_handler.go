@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// SecurityWebhookHandler processa a assinatura dos eventos de segurança da conta
+type SecurityWebhookHandler struct {
+	webhookService *service.SecurityWebhookService
+}
+
+// NewSecurityWebhookHandler cria um novo handler de webhooks de segurança
+func NewSecurityWebhookHandler(webhookService *service.SecurityWebhookService) *SecurityWebhookHandler {
+	return &SecurityWebhookHandler{webhookService: webhookService}
+}
+
+// writeSecurityWebhookError traduz os erros das assinaturas de segurança em status HTTP
+func writeSecurityWebhookError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidSecurityWebhook:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound, domain.ErrSecurityWebhookNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Get processa GET /accounts/security/webhook
+func (h *SecurityWebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.webhookService.Get(r.Context(), requestctx.APIKey(r.Context()))
+	if err != nil {
+		writeSecurityWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Update processa PUT /accounts/security/webhook e substitui a assinatura inteira
+func (h *SecurityWebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.SecurityWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.webhookService.Update(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeSecurityWebhookError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Delete processa DELETE /accounts/security/webhook
+func (h *SecurityWebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhookService.Delete(r.Context(), requestctx.APIKey(r.Context())); err != nil {
+		writeSecurityWebhookError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
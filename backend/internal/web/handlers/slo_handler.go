@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// SLOHandler expõe o consumo do objetivo de latência para os operadores
+type SLOHandler struct {
+	sloService *service.SLOService
+}
+
+// NewSLOHandler cria um novo handler do objetivo de latência
+func NewSLOHandler(sloService *service.SLOService) *SLOHandler {
+	return &SLOHandler{sloService: sloService}
+}
+
+// Report processa GET /admin/slo
+func (h *SLOHandler) Report(w http.ResponseWriter, r *http.Request) {
+	output := h.sloService.Report()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
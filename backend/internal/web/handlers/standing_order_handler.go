@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// StandingOrderHandler processa as ordens permanentes de transferência entre contas
+type StandingOrderHandler struct {
+	standingOrderService *service.StandingOrderService
+}
+
+// NewStandingOrderHandler cria um novo handler de ordens permanentes
+func NewStandingOrderHandler(standingOrderService *service.StandingOrderService) *StandingOrderHandler {
+	return &StandingOrderHandler{standingOrderService: standingOrderService}
+}
+
+// writeStandingOrderError traduz os erros das ordens permanentes em status HTTP
+func writeStandingOrderError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidStandingOrder:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrStandingOrderNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrStandingOrderCancelled, domain.ErrStandingOrderChanged:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Create processa POST /accounts/standing-orders
+func (h *StandingOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateStandingOrderInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.standingOrderService.Create(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeStandingOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/standing-orders
+// Query param opcional: status (active ou cancelled)
+func (h *StandingOrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.StandingOrderStatus(r.URL.Query().Get("status"))
+	output, err := h.standingOrderService.List(r.Context(), requestctx.APIKey(r.Context()), status)
+	if err != nil {
+		writeStandingOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /accounts/standing-orders/{id}
+func (h *StandingOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.standingOrderService.Get(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeStandingOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Cancel processa POST /accounts/standing-orders/{id}/cancel
+func (h *StandingOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	output, err := h.standingOrderService.Cancel(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeStandingOrderError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// SubscriptionHandler processa as assinaturas cobradas no cartão guardado no cofre
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewSubscriptionHandler cria um novo handler de assinaturas
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionService: subscriptionService}
+}
+
+// writeSubscriptionError traduz os erros das assinaturas em status HTTP
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidSubscription, domain.ErrInvalidCardNumber, domain.ErrInvalidCardCVV, domain.ErrInvalidCardExpiry, domain.ErrInvalidCardholderName, domain.ErrTestCardNotAllowed:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrSubscriptionNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrSubscriptionCancelled, domain.ErrSubscriptionNotPastDue, domain.ErrSubscriptionChanged:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Create processa POST /accounts/subscriptions
+// Responde 503 enquanto o cofre não guardar o número dos cartões, sem o qual a assinatura não seria cobrada
+func (h *SubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !h.subscriptionService.Enabled() {
+		http.Error(w, "card number retention is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var input dto.CreateSubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.subscriptionService.Create(r.Context(), requestctx.APIKey(r.Context()), input)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /accounts/subscriptions
+// Query param opcional: status (active, past_due ou cancelled)
+func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.SubscriptionStatus(r.URL.Query().Get("status"))
+	output, err := h.subscriptionService.List(r.Context(), requestctx.APIKey(r.Context()), status)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /accounts/subscriptions/{id}
+func (h *SubscriptionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.subscriptionService.Get(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Cancel processa POST /accounts/subscriptions/{id}/cancel
+func (h *SubscriptionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	output, err := h.subscriptionService.Cancel(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Resume processa POST /accounts/subscriptions/{id}/resume
+// A assinatura inadimplente volta a ser cobrada na próxima verificação da tarefa de cobrança
+func (h *SubscriptionHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	output, err := h.subscriptionService.Resume(r.Context(), requestctx.APIKey(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
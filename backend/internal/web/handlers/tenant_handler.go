@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// TenantHandler processa as organizações das implantações white-label e a marca pública delas
+type TenantHandler struct {
+	tenantService *service.TenantService
+}
+
+// NewTenantHandler cria um novo handler de organizações
+func NewTenantHandler(tenantService *service.TenantService) *TenantHandler {
+	return &TenantHandler{tenantService: tenantService}
+}
+
+// writeTenantError traduz os erros das organizações em status HTTP
+func writeTenantError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidTenant:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrForbidden:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrTenantNotFound, domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Create processa POST /admin/tenants
+// A chave dos administradores da organização só aparece nesta resposta
+func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateTenantInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.tenantService.Create(r.Context(), input)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// List processa GET /admin/tenants
+func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.tenantService.List(r.Context())
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get processa GET /admin/tenants/{id}
+func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
+	output, err := h.tenantService.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Configure processa PUT /admin/tenants/{id}
+// Substitui a marca, a tarifa de chargeback e os tetos de gasto; os campos ausentes voltam ao padrão
+func (h *TenantHandler) Configure(w http.ResponseWriter, r *http.Request) {
+	var input dto.TenantConfigInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.tenantService.Configure(r.Context(), chi.URLParam(r, "id"), input)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// RotateAdminKey processa POST /admin/tenants/{id}/admin-key
+// A nova chave só aparece nesta resposta; a anterior deixa de valer na hora
+func (h *TenantHandler) RotateAdminKey(w http.ResponseWriter, r *http.Request) {
+	output, err := h.tenantService.RotateAdminKey(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// AssignAccount processa PUT /admin/accounts/{id}/tenant
+func (h *TenantHandler) AssignAccount(w http.ResponseWriter, r *http.Request) {
+	var input dto.AssignTenantInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.tenantService.AssignAccount(r.Context(), chi.URLParam(r, "id"), input.TenantID)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Current processa GET /admin/tenant, a organização dos administradores autenticados
+func (h *TenantHandler) Current(w http.ResponseWriter, r *http.Request) {
+	output, err := h.tenantService.Current(r.Context())
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// UpdateBranding processa PUT /admin/tenant/branding
+func (h *TenantHandler) UpdateBranding(w http.ResponseWriter, r *http.Request) {
+	var input dto.TenantBrandingInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.tenantService.UpdateBranding(r.Context(), input)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Branding processa GET /tenants/{id}/branding, pública para que as telas das contas exibam a marca antes do login
+func (h *TenantHandler) Branding(w http.ResponseWriter, r *http.Request) {
+	output, err := h.tenantService.Branding(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
+)
+
+// TwoFactorHandler processa o cadastro do segundo fator do autor autenticado
+// As mesmas rotas servem às contas e usuários (/accounts/2fa) e aos administradores (/admin/2fa)
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler cria um novo handler de segundo fator
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// Enroll processa POST /accounts/2fa e POST /admin/2fa
+// Retorna o segredo, a URI otpauth:// e os códigos de recuperação, exibidos apenas uma vez
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	output, err := h.twoFactorService.Enroll(r.Context(), requestctx.Actor(r.Context()))
+	if err != nil {
+		switch err {
+		case domain.ErrTwoFactorAlreadyEnrolled:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Confirm processa POST /accounts/2fa/confirm e POST /admin/2fa/confirm
+// Conclui o cadastro com o primeiro código do autenticador
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var input dto.ConfirmTwoFactorInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.twoFactorService.Confirm(r.Context(), requestctx.Actor(r.Context()), input.Code); err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Disable processa DELETE /accounts/2fa e DELETE /admin/2fa
+// Um cadastro concluído exige o código no header X-2FA-Code
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	if err := h.twoFactorService.Disable(r.Context(), requestctx.Actor(r.Context()), r.Header.Get(middleware.TwoFactorCodeHeader)); err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTwoFactorError traduz os erros do segundo fator em status HTTP
+func writeTwoFactorError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrTwoFactorNotEnrolled:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrTwoFactorAlreadyEnrolled:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrInvalidTwoFactorCode:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/webhook"
+)
+
+// WebhookHandler ajuda os lojistas a testarem a verificação dos webhooks
+type WebhookHandler struct{}
+
+// NewWebhookHandler cria um novo handler de webhooks
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{}
+}
+
+// Verify processa POST /webhooks/verify
+// Confere a assinatura informada com o API Key da conta autenticada, aplicando a tolerância padrão ao timestamp,
+// e devolve só o resultado e o motivo da recusa
+func (h *WebhookHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var input dto.VerifyWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apiKey := requestctx.APIKey(r.Context())
+	payload := []byte(input.Payload)
+	output := dto.VerifyWebhookOutput{Valid: true}
+	if err := webhook.Verify(apiKey, input.Timestamp, payload, input.Signature, webhook.DefaultTolerance); err != nil {
+		output.Valid, output.Reason = false, err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/errortracker"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// quietPaths são as rotas consultadas a todo momento por coletores e orquestradores, registradas só em debug
+var quietPaths = map[string]bool{
+	"/metrics": true,
+	"/readyz":  true,
+}
+
+type accessLogKey struct{}
+
+// accessEntry guarda os campos descobertos pelos middlewares internos, como a conta autenticada,
+// para que entrem na linha de acesso registrada ao fim da requisição
+type accessEntry struct {
+	attrs []any
+	// recovered indica que Recover tratou um panic, já enviado ao rastreador de erros
+	recovered bool
+}
+
+// annotateAccess acrescenta campos à linha de acesso da requisição; sem AccessLog não faz nada
+func annotateAccess(ctx context.Context, args ...any) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessEntry); ok {
+		entry.attrs = append(entry.attrs, args...)
+	}
+}
+
+// markRecovered registra na linha de acesso que a requisição entrou em panic
+func markRecovered(ctx context.Context) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessEntry); ok {
+		entry.recovered = true
+		entry.attrs = append(entry.attrs, "panic", true)
+	}
+}
+
+// AccessLogConfig define a amostragem das linhas de acesso das respostas bem-sucedidas
+// Respostas com status 400 ou maior são sempre registradas
+type AccessLogConfig struct {
+	// SampleRate é a fração, de 0 a 1, das respostas bem-sucedidas registradas nas rotas sem taxa própria
+	SampleRate float64
+	// RouteSampleRates define a taxa por rota, no formato "MÉTODO padrão" das rotas do chi (ex: "GET /invoice/{id}")
+	RouteSampleRates map[string]float64
+}
+
+// sampleRate retorna a taxa de amostragem da rota
+func (c AccessLogConfig) sampleRate(route string) float64 {
+	if rate, ok := c.RouteSampleRates[route]; ok {
+		return rate
+	}
+	return c.SampleRate
+}
+
+// AccessLogSampling guarda a amostragem em uso pelo AccessLog, trocada de uma vez ao recarregar a configuração
+type AccessLogSampling struct {
+	config atomic.Pointer[AccessLogConfig]
+}
+
+// NewAccessLogSampling cria a amostragem com a configuração inicial
+func NewAccessLogSampling(config AccessLogConfig) *AccessLogSampling {
+	sampling := &AccessLogSampling{}
+	sampling.Set(config)
+	return sampling
+}
+
+// Set passa a amostrar as próximas requisições com a nova configuração
+func (s *AccessLogSampling) Set(config AccessLogConfig) {
+	s.config.Store(&config)
+}
+
+// AccessLog registra uma linha por requisição com método, rota, status, duração, bytes e IP
+// Respostas 5xx saem como erro; /metrics e /readyz só aparecem com LOG_LEVEL=debug
+// As respostas bem-sucedidas são amostradas conforme sampling; as linhas amostradas trazem sample_rate
+// Deve vir depois de RequestID e ClientInfo para que a linha leve o ID da requisição e o IP
+func AccessLog(sampling *AccessLogSampling) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &accessEntry{}
+			ctx := context.WithValue(r.Context(), accessLogKey{}, entry)
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			body := &errorBody{status: ww.Status}
+			ww.Tee(body)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			attrs := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"bytes", ww.BytesWritten(),
+				"ip", requestctx.ClientInfo(ctx).IP,
+			}
+
+			// O padrão da rota só é conhecido depois que o roteador atendeu a requisição
+			if status < http.StatusBadRequest {
+				config := sampling.config.Load()
+				rate := config.SampleRate
+				if routeContext := chi.RouteContext(ctx); routeContext != nil {
+					rate = config.sampleRate(r.Method + " " + routeContext.RoutePattern())
+				}
+				if rate < 1 {
+					if rand.Float64() >= rate {
+						return
+					}
+					attrs = append(attrs, "sample_rate", rate)
+				}
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+				// O corpo das respostas 5xx é a mensagem do erro, que acompanha a linha e o evento do rastreador
+				if message := strings.TrimSpace(body.String()); message != "" {
+					attrs = append(attrs, "error", message)
+				}
+			case quietPaths[r.URL.Path]:
+				level = slog.LevelDebug
+			}
+			// O panic já foi enviado ao rastreador por Recover, com o stack
+			if entry.recovered {
+				ctx = errortracker.WithoutCapture(ctx)
+			}
+			slog.Log(ctx, level, "requisição HTTP", append(attrs, entry.attrs...)...)
+		})
+	}
+}
+
+// maxErrorBody limita quanto do corpo de uma resposta 5xx é guardado para a linha de acesso
+const maxErrorBody = 512
+
+// errorBody guarda o início do corpo das respostas 5xx, que os handlers preenchem com a mensagem do erro
+type errorBody struct {
+	status func() int
+	buf    bytes.Buffer
+}
+
+// Write guarda o corpo apenas das respostas 5xx, até maxErrorBody bytes
+func (b *errorBody) Write(p []byte) (int, error) {
+	if b.status() >= http.StatusInternalServerError && b.buf.Len() < maxErrorBody {
+		b.buf.Write(p[:min(len(p), maxErrorBody-b.buf.Len())])
+	}
+	return len(p), nil
+}
+
+// String retorna o corpo guardado
+func (b *errorBody) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// AdminMiddleware protege as rotas administrativas com uma chave compartilhada, com o SSO da empresa ou com a
+// chave dos administradores de uma organização
+type AdminMiddleware struct {
+	apiKey       string
+	authService  *service.AuthService
+	tenants      *service.TenantService
+	events       *service.SecurityService
+	certificates *auth.CertificateMapper
+}
+
+// NewAdminMiddleware cria o middleware; sem chave, sem OIDC_ADMIN_GROUPS e sem serviços mapeados
+// em certificates as rotas administrativas ficam desabilitadas para os administradores globais, mas continuam
+// abertas às chaves das organizações de tenants; as tentativas são registradas em events
+func NewAdminMiddleware(apiKey string, authService *service.AuthService, tenants *service.TenantService, events *service.SecurityService, certificates *auth.CertificateMapper) *AdminMiddleware {
+	return &AdminMiddleware{apiKey: apiKey, authService: authService, tenants: tenants, events: events, certificates: certificates}
+}
+
+// Authenticate aceita serviços internos identificados pelo certificado de cliente, o header X-ADMIN-KEY,
+// validado em tempo constante, ou, com Authorization: Bearer, o ID token do provedor de identidade
+// de um operador dos grupos administrativos
+// Com a chave de uma organização (prefixo domain.TenantAdminKeyPrefix) no X-ADMIN-KEY, a requisição fica restrita
+// às contas da organização (requestctx.WithTenant)
+func (m *AdminMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowUnlocked(w, r, m.events, "", "") {
+			return
+		}
+
+		if identity, ok := certificateIdentity(r, m.certificates); ok && identity.Kind == auth.IdentityService {
+			m.events.Record(r.Context(), domain.AuthEvent{Method: domain.AuthMethodCertificate, KeyID: identity.Kind + ":" + identity.ID, Success: true})
+			ctx := requestctx.WithActor(r.Context(), "service:"+identity.ID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		if idToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && m.authService.AdminSSOEnabled() {
+			email, err := m.authService.AuthenticateAdmin(r.Context(), idToken)
+			event := domain.AuthEvent{Method: domain.AuthMethodOIDC, Success: err == nil}
+			if email != "" {
+				event.KeyID = "admin:" + email
+			}
+			switch err {
+			case nil:
+				m.events.Record(r.Context(), event)
+				ctx := requestctx.WithActor(r.Context(), "admin:"+email)
+				next.ServeHTTP(w, r.WithContext(ctx))
+			case domain.ErrForbidden:
+				event.Reason = err.Error()
+				m.events.Record(r.Context(), event)
+				http.Error(w, err.Error(), http.StatusForbidden)
+			case domain.ErrInvalidToken, domain.ErrInvalidCredentials:
+				event.Reason = err.Error()
+				m.events.Record(r.Context(), event)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if adminKey := r.Header.Get("X-ADMIN-KEY"); strings.HasPrefix(adminKey, domain.TenantAdminKeyPrefix) {
+			m.authenticateTenant(w, r, next, adminKey)
+			return
+		}
+
+		if m.apiKey == "" {
+			http.Error(w, "admin API is disabled", http.StatusServiceUnavailable)
+			return
+		}
+
+		// Todas as tentativas com a chave compartilhada são agrupadas, para alertar sobre tentativas distribuídas
+		event := domain.AuthEvent{Method: domain.AuthMethodAdminKey, KeyID: "admin"}
+		adminKey := r.Header.Get("X-ADMIN-KEY")
+		if subtle.ConstantTimeCompare([]byte(adminKey), []byte(m.apiKey)) != 1 {
+			event.Reason = "invalid admin key"
+			m.events.Record(r.Context(), event)
+			http.Error(w, "invalid admin key", http.StatusUnauthorized)
+			return
+		}
+		event.Success = true
+		m.events.Record(r.Context(), event)
+
+		ctx := requestctx.WithActor(r.Context(), "admin")
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticateTenant autentica os administradores de uma organização pela chave dela
+// As tentativas que falham são agrupadas, como as da chave compartilhada
+func (m *AdminMiddleware) authenticateTenant(w http.ResponseWriter, r *http.Request, next http.Handler, adminKey string) {
+	event := domain.AuthEvent{Method: domain.AuthMethodAdminKey, KeyID: "tenant-admin"}
+	tenant, err := m.tenants.AuthenticateAdmin(r.Context(), adminKey)
+	switch err {
+	case nil:
+	case domain.ErrInvalidCredentials:
+		event.Reason = "invalid admin key"
+		m.events.Record(r.Context(), event)
+		http.Error(w, "invalid admin key", http.StatusUnauthorized)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	event.KeyID = "tenant-admin:" + tenant.ID
+	event.Success = true
+	m.events.Record(r.Context(), event)
+
+	ctx := requestctx.WithActor(r.Context(), "tenant-admin:"+tenant.ID)
+	ctx = requestctx.WithTenant(ctx, tenant.ID)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
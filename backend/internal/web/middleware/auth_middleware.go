@@ -1,41 +1,252 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/logging"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
 )
 
+// Headers do modo de autenticação por assinatura HMAC
+const (
+	AccountIDHeader = "X-Account-ID"
+	TimestampHeader = "X-Timestamp"
+	NonceHeader     = "X-Nonce"
+	SignatureHeader = "X-Signature"
+)
+
+// maxNonceLength limita o tamanho do nonce guardado no registro de replays
+const maxNonceLength = 128
+
+// maxSignedBodySize limita o corpo lido antes de a assinatura ser conferida, quando quem envia ainda não foi
+// identificado; cobre o maior corpo aceito pelas rotas, o das provas de disputa
+const maxSignedBodySize = domain.MaxDisputeEvidenceSize + 64<<10
+
 type AuthMiddleware struct {
 	accountService *service.AccountService
+	authService    *service.AuthService
+	events         *service.SecurityService
+	geo            *service.GeoRiskService
+	certificates   *auth.CertificateMapper
+	maxSkew        time.Duration
+	nonces         NonceStore
 }
 
-func NewAuthMiddleware(accountService *service.AccountService) *AuthMiddleware {
+// NewAuthMiddleware cria o middleware de autenticação das contas
+// Requisições assinadas com timestamp fora de maxSkew são rejeitadas; com maxSkew zero a assinatura fica desabilitada
+// certificates identifica as contas que chamam pelo listener mTLS; pode ser nil
+// Cada tentativa, aceita ou recusada, é registrada em events
+// geo recusa as contas autenticadas de países bloqueados pela política geográfica delas
+func NewAuthMiddleware(accountService *service.AccountService, authService *service.AuthService, events *service.SecurityService, geo *service.GeoRiskService, certificates *auth.CertificateMapper, maxSkew time.Duration, nonces NonceStore) *AuthMiddleware {
 	return &AuthMiddleware{
 		accountService: accountService,
+		authService:    authService,
+		events:         events,
+		geo:            geo,
+		certificates:   certificates,
+		maxSkew:        maxSkew,
+		nonces:         nonces,
 	}
 }
 
+// Authenticate identifica a conta pelo certificado de cliente mapeado para ela, pelo header X-API-KEY,
+// pelo JWT de um usuário do dashboard em Authorization: Bearer ou, quando X-Signature está presente,
+// pela assinatura HMAC da requisição, em que o API Key é o segredo e não trafega
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get("X-API-KEY")
-		if apiKey == "" {
-			http.Error(w, "X-API-KEY is required", http.StatusUnauthorized)
+		var account *dto.AccountOutput
+		var user *dto.UserOutput
+		var err error
+		// event identifica a credencial apresentada; conta e resultado são completados abaixo
+		var event domain.AuthEvent
+
+		// IPs com muitas falhas consecutivas ficam bloqueados antes de qualquer consulta às credenciais
+		if !allowUnlocked(w, r, m.events, "", "") {
 			return
 		}
 
-		_, err := m.accountService.FindByAPIKey(apiKey)
+		if identity, ok := certificateIdentity(r, m.certificates); ok && identity.Kind == auth.IdentityAccount {
+			event.Method, event.KeyID = domain.AuthMethodCertificate, identity.Kind+":"+identity.ID
+			account, err = m.accountService.FindByID(r.Context(), identity.ID)
+		} else if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			event.Method = domain.AuthMethodJWT
+			account, user, err = m.authService.Authenticate(r.Context(), bearer)
+		} else if r.Header.Get(SignatureHeader) != "" {
+			event.Method = domain.AuthMethodSignature
+			// A conta informada só é confiável depois da assinatura, mas falhas contra ela interessam ao lojista
+			if accountID := r.Header.Get(AccountIDHeader); isUUID(accountID) {
+				event.AccountID, event.KeyID = accountID, "account:"+accountID
+			}
+			account, err = m.verifySignature(w, r)
+		} else {
+			apiKey := r.Header.Get("X-API-KEY")
+			event.Method, event.KeyID = domain.AuthMethodAPIKey, domain.APIKeyPrefix(apiKey)
+			if apiKey == "" {
+				event.Reason = "missing credentials"
+				m.events.Record(r.Context(), event)
+				http.Error(w, "X-API-KEY or Authorization is required", http.StatusUnauthorized)
+				return
+			}
+			if !allowUnlocked(w, r, m.events, event.Method, event.KeyID) {
+				return
+			}
+			account, err = m.accountService.FindByAPIKey(r.Context(), apiKey)
+		}
+
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			event.Reason = "request body too large"
+			m.events.Record(r.Context(), event)
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		if err != nil {
-			if err == domain.ErrAccountNotFound {
+			switch err {
+			case domain.ErrAccountNotFound, domain.ErrInvalidSignature, domain.ErrStaleTimestamp, domain.ErrReplayedRequest, domain.ErrInvalidToken:
+				event.Reason = err.Error()
+				m.events.Record(r.Context(), event)
 				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
+			default:
+				// O erro interno pode expor detalhes do armazenamento, então só vai para o log
+				slog.ErrorContext(r.Context(), "erro ao autenticar a requisição", "error", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
 			}
+		}
 
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		event.AccountID = account.ID
+		if user != nil {
+			event.KeyID = "user:" + user.ID
 		}
 
-		next.ServeHTTP(w, r)
+		// A credencial confere, mas a política geográfica da conta pode recusar a origem
+		if _, err := m.geo.Evaluate(r.Context(), account.ID); err != nil {
+			switch err {
+			case domain.ErrCountryNotAllowed:
+				event.Reason = err.Error()
+				m.events.Record(r.Context(), event)
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			default:
+				slog.ErrorContext(r.Context(), "erro ao avaliar a política geográfica da conta", "account_id", account.ID, "error", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		event.Success = true
+		m.events.Record(r.Context(), event)
+
+		// A conta autenticada é a autora das mutações desta requisição; com JWT, o usuário
+		actor, role := "account:"+account.ID, account.Role
+		if user != nil {
+			actor, role = "user:"+user.ID, user.Role
+		}
+		ctx := requestctx.WithActor(r.Context(), actor)
+		ctx = requestctx.WithAPIKey(ctx, account.APIKey)
+		ctx = requestctx.WithRole(ctx, role)
+		// Usuários do dashboard não têm escopos; o API Key, usado diretamente ou como segredo, tem
+		if user == nil {
+			ctx = requestctx.WithScopes(ctx, account.Scopes)
+		}
+		// Os logs da requisição, inclusive a linha de acesso, passam a identificar a conta
+		ctx = logging.With(ctx, "account_id", account.ID)
+		annotateAccess(ctx, "account_id", account.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// verifySignature valida a assinatura, a janela do timestamp e o nonce de uma requisição assinada
+// O nonce só é registrado depois que a assinatura confere, para que terceiros não consigam queimá-lo
+// Um corpo maior que maxSignedBodySize retorna *http.MaxBytesError sem ser lido até o fim
+func (m *AuthMiddleware) verifySignature(w http.ResponseWriter, r *http.Request) (*dto.AccountOutput, error) {
+	if m.maxSkew <= 0 {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	timestamp := r.Header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidSignature
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > m.maxSkew || skew < -m.maxSkew {
+		return nil, domain.ErrStaleTimestamp
+	}
+
+	nonce := r.Header.Get(NonceHeader)
+	if nonce == "" || len(nonce) > maxNonceLength {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	// O corpo é lido para a assinatura e devolvido intacto aos handlers
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	accountID := r.Header.Get(AccountIDHeader)
+	if !isUUID(accountID) {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	account, err := m.accountService.FindByID(r.Context(), accountID)
+	if err == domain.ErrAccountNotFound {
+		return nil, domain.ErrInvalidSignature
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expected := SignRequest(account.APIKey, r.Method, r.URL.RequestURI(), timestamp, nonce, body)
+	if !hmac.Equal(signature, expected) {
+		return nil, domain.ErrInvalidSignature
+	}
+
+	// Nonces vivem o dobro da tolerância, cobrindo toda a janela em que o timestamp seria aceito
+	fresh, err := m.nonces.Use(r.Context(), account.ID+":"+nonce, 2*m.maxSkew)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, domain.ErrReplayedRequest
+	}
+
+	return account, nil
+}
+
+// isUUID indica se o valor é um UUID válido, como os IDs das contas
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+// SignRequest calcula o HMAC-SHA256, com o API Key como segredo, de
+// timestamp, nonce, método, caminho com query string e corpo, separados por quebras de linha
+func SignRequest(apiKey, method, requestURI, timestamp, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(apiKey))
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n" + method + "\n" + requestURI + "\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/dto"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/repository/memory"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+const testSkew = 5 * time.Minute
+
+func TestSignRequest(t *testing.T) {
+	body := []byte(`{"amount":10}`)
+	got := SignRequest("key", http.MethodPost, "/invoice?x=1", "1700000000", "n1", body)
+
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("1700000000\nn1\nPOST\n/invoice?x=1\n" + string(body)))
+	if want := mac.Sum(nil); !hmac.Equal(got, want) {
+		t.Errorf("SignRequest = %x, want %x", got, want)
+	}
+
+	// Cada parte assinada muda a assinatura
+	variants := map[string][]byte{
+		"key":       SignRequest("other", http.MethodPost, "/invoice?x=1", "1700000000", "n1", body),
+		"method":    SignRequest("key", http.MethodPut, "/invoice?x=1", "1700000000", "n1", body),
+		"uri":       SignRequest("key", http.MethodPost, "/invoice?x=2", "1700000000", "n1", body),
+		"timestamp": SignRequest("key", http.MethodPost, "/invoice?x=1", "1700000001", "n1", body),
+		"nonce":     SignRequest("key", http.MethodPost, "/invoice?x=1", "1700000000", "n2", body),
+		"body":      SignRequest("key", http.MethodPost, "/invoice?x=1", "1700000000", "n1", []byte(`{"amount":11}`)),
+	}
+	for name, signature := range variants {
+		if hmac.Equal(got, signature) {
+			t.Errorf("changing the %s did not change the signature", name)
+		}
+	}
+}
+
+// newTestAuth cria o middleware sobre um repositório em memória com uma conta
+func newTestAuth(t *testing.T, maxSkew time.Duration) (*AuthMiddleware, *dto.AccountOutput) {
+	t.Helper()
+	store := memory.NewStore()
+	accounts := service.NewAccountService(memory.NewAccountRepository(store), 0)
+	account, err := accounts.CreateAccount(context.Background(), dto.CreateAccountInput{Name: "Loja", Email: "loja@example.com"})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	// Só os campos usados pelas requisições assinadas; os demais modos de autenticação ficam desligados
+	notifier := service.NewSecurityWebhookService(memory.NewSecurityWebhookRepository(store), accounts, time.Second)
+	events := service.NewSecurityService(memory.NewAuthEventRepository(store), accounts, notifier, service.SecurityConfig{})
+	geo := service.NewGeoRiskService(memory.NewGeoPolicyRepository(store), accounts, nil, notifier)
+	return &AuthMiddleware{accountService: accounts, events: events, geo: geo, maxSkew: maxSkew, nonces: NewMemoryNonceStore()}, account
+}
+
+// signedRequest monta uma requisição assinada com o API Key informado
+func signedRequest(accountID, apiKey string, at time.Time, nonce string, body []byte) *http.Request {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	r := httptest.NewRequest(http.MethodPost, "/invoice", bytes.NewReader(body))
+	r.Header.Set(AccountIDHeader, accountID)
+	r.Header.Set(TimestampHeader, timestamp)
+	r.Header.Set(NonceHeader, nonce)
+	r.Header.Set(SignatureHeader, hex.EncodeToString(SignRequest(apiKey, http.MethodPost, "/invoice", timestamp, nonce, body)))
+	return r
+}
+
+func TestAuthenticateSignedRequest(t *testing.T) {
+	body := []byte(`{"amount":10}`)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		maxSkew time.Duration
+		request func(account *dto.AccountOutput) *http.Request
+		want    int
+	}{
+		{"valid", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now, "n1", body)
+		}, http.StatusOK},
+		{"inside the skew", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now.Add(-testSkew+time.Minute), "n1", body)
+		}, http.StatusOK},
+		{"too old", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now.Add(-testSkew-time.Minute), "n1", body)
+		}, http.StatusUnauthorized},
+		{"too far ahead", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now.Add(testSkew+time.Minute), "n1", body)
+		}, http.StatusUnauthorized},
+		{"wrong key", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, "wrong", now, "n1", body)
+		}, http.StatusUnauthorized},
+		{"tampered body", testSkew, func(a *dto.AccountOutput) *http.Request {
+			r := signedRequest(a.ID, a.APIKey, now, "n1", body)
+			r.Body = io.NopCloser(bytes.NewReader([]byte(`{"amount":1000}`)))
+			return r
+		}, http.StatusUnauthorized},
+		{"missing nonce", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now, "", body)
+		}, http.StatusUnauthorized},
+		{"unknown account", testSkew, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest("0b6f6d7e-2b7c-4f63-9a39-1f6f1f7d9c11", a.APIKey, now, "n1", body)
+		}, http.StatusUnauthorized},
+		{"signing disabled", 0, func(a *dto.AccountOutput) *http.Request {
+			return signedRequest(a.ID, a.APIKey, now, "n1", body)
+		}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, account := newTestAuth(t, tt.maxSkew)
+			var gotBody []byte
+			var gotKey string
+			handler := auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = io.ReadAll(r.Body)
+				gotKey = requestctx.APIKey(r.Context())
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.request(account))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			// O handler recebe o corpo intacto e o API Key da conta, que não trafegou
+			if !bytes.Equal(gotBody, body) {
+				t.Errorf("handler body = %q, want %q", gotBody, body)
+			}
+			if gotKey != account.APIKey {
+				t.Errorf("context API key = %q, want the account key", gotKey)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsReplayedNonce(t *testing.T) {
+	auth, account := newTestAuth(t, testSkew)
+	handler := auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	body := []byte(`{"amount":10}`)
+
+	for i, want := range []int{http.StatusOK, http.StatusUnauthorized} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, signedRequest(account.ID, account.APIKey, time.Now(), "same", body))
+		if w.Code != want {
+			t.Errorf("request %d status = %d, want %d", i+1, w.Code, want)
+		}
+	}
+
+	// Outro nonce na mesma janela continua valendo
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(account.ID, account.APIKey, time.Now(), "other", body))
+	if w.Code != http.StatusOK {
+		t.Errorf("fresh nonce status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+)
+
+// certificateIdentity retorna a identidade do certificado de cliente verificado no handshake mTLS
+// Requisições do listener HTTP comum, sem certificado, nunca têm identidade
+func certificateIdentity(r *http.Request, certificates *auth.CertificateMapper) (auth.CertificateIdentity, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return auth.CertificateIdentity{}, false
+	}
+	return certificates.Identify(r.TLS.VerifiedChains[0][0])
+}
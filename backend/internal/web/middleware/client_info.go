@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// ClientInfo guarda no contexto o IP, o User-Agent e a rota da requisição
+// O IP é o endereço da conexão; headers como X-Forwarded-For não são considerados
+func ClientInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := requestctx.WithClient(r.Context(), requestctx.Client{
+			IP:        ip,
+			UserAgent: r.UserAgent(),
+			Route:     r.Method + " " + r.URL.Path,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
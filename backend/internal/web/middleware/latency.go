@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+)
+
+// LatencyRecorder recebe a duração das requisições que contam para o objetivo de latência
+type LatencyRecorder interface {
+	Observe(route string, elapsed time.Duration)
+}
+
+// ObserveLatency mede cada requisição no histograma de latência por rota e classe de status e entrega a
+// duração ao objetivo de latência
+// Ficam fora do objetivo as respostas 5xx, as rotas /admin, /metrics e /readyz e as requisições sem rota
+// Deve vir antes de Recover para que os panics contem como 500
+func ObserveLatency(recorder LatencyRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			elapsed := time.Since(start)
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// O padrão da rota só é conhecido depois que o roteador atendeu a requisição
+			pattern := ""
+			if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+				pattern = routeContext.RoutePattern()
+			}
+			if pattern == "" {
+				metrics.HTTPRequestDuration.WithLabelValues("unmatched", statusClass(status)).Observe(elapsed.Seconds())
+				return
+			}
+			route := r.Method + " " + pattern
+			metrics.HTTPRequestDuration.WithLabelValues(route, statusClass(status)).Observe(elapsed.Seconds())
+
+			if status >= http.StatusInternalServerError || quietPaths[pattern] || strings.HasPrefix(pattern, "/admin/") {
+				return
+			}
+			recorder.Observe(route, elapsed)
+		})
+	}
+}
+
+// statusClass agrupa o status pela centena, como "2xx"
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
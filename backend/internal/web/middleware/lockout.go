@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// allowUnlocked recusa a requisição com 429, se o IP estiver bloqueado, ou 423, se o API Key ou o segundo fator estiver,
+// indicando em Retry-After quando tentar de novo; retorna false se a requisição foi recusada
+func allowUnlocked(w http.ResponseWriter, r *http.Request, events *service.SecurityService, method domain.AuthMethod, keyID string) bool {
+	retryAfter, err := events.CheckLockout(r.Context(), method, keyID)
+	if err == nil {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	status := http.StatusTooManyRequests
+	if err == domain.ErrAPIKeyLocked || err == domain.ErrTwoFactorLocked {
+		status = http.StatusLocked
+	}
+	http.Error(w, err.Error(), status)
+	return false
+}
+
+// RejectBlockedClients recusa com 429 as requisições de IPs bloqueados por falhas consecutivas
+// Protege as rotas de login, que não passam pelo AuthMiddleware
+func RejectBlockedClients(events *service.SecurityService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if allowUnlocked(w, r, events, "", "") {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
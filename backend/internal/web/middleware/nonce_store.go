@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/redis"
+)
+
+// NonceStore registra os nonces das requisições assinadas para bloquear replays
+type NonceStore interface {
+	// Use marca o nonce como usado por ttl e retorna false se ele já tinha sido usado
+	Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore guarda os nonces na memória do processo
+// Com várias instâncias atrás de um balanceador cada uma conhece apenas os nonces que recebeu
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	expiresAt map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore cria um registro de nonces vazio
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{expiresAt: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+// Use marca o nonce como usado, descartando periodicamente os que já expiraram
+func (s *MemoryNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= ttl {
+		for key, expiresAt := range s.expiresAt {
+			if now.After(expiresAt) {
+				delete(s.expiresAt, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if expiresAt, ok := s.expiresAt[nonce]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	s.expiresAt[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// nonceKeyPrefix separa as chaves dos nonces das demais chaves do Redis
+const nonceKeyPrefix = "gateway:nonce:"
+
+// RedisNonceStore guarda os nonces no Redis, compartilhados entre as réplicas do gateway
+// Cada nonce é uma chave que expira sozinha; SET NX torna a verificação e o registro uma única operação atômica
+// Cada consulta tem no máximo timeout, para que um Redis sem resposta não prenda as requisições assinadas
+type RedisNonceStore struct {
+	client  *redis.Client
+	timeout time.Duration
+}
+
+// NewRedisNonceStore cria o registro de nonces sobre o cliente Redis informado
+func NewRedisNonceStore(client *redis.Client, timeout time.Duration) *RedisNonceStore {
+	return &RedisNonceStore{client: client, timeout: timeout}
+}
+
+// Use marca o nonce como usado por ttl
+// Falhas do Redis, inclusive a falta de resposta dentro do prazo, são retornadas, e a requisição recusada, já que
+// sem o registro um replay passaria despercebido
+func (s *RedisNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	reply, err := s.client.Do(ctx, "SET", nonceKeyPrefix+nonce, "1", "NX", "PX", ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	// Com NX, o Redis responde nil quando a chave já existe
+	return reply != nil, nil
+}
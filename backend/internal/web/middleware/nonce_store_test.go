@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryNonceStore()
+	ttl := 20 * time.Millisecond
+
+	for i, want := range []bool{true, false} {
+		fresh, err := store.Use(ctx, "n1", ttl)
+		if err != nil {
+			t.Fatalf("Use: %v", err)
+		}
+		if fresh != want {
+			t.Errorf("use %d fresh = %v, want %v", i+1, fresh, want)
+		}
+	}
+
+	// Depois do ttl o nonce pode ser usado de novo, pois o timestamp que o acompanha já saiu da tolerância
+	time.Sleep(2 * ttl)
+	fresh, err := store.Use(ctx, "n1", ttl)
+	if err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+	if !fresh {
+		t.Error("expired nonce fresh = false, want true")
+	}
+}
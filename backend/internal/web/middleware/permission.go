@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// RequirePermission rejeita com 403 as requisições cujo papel autenticado não concede a permissão
+// ou, quando autenticadas por API Key, cujos escopos não a incluem
+// Deve ser usado depois de AuthMiddleware.Authenticate, que coloca papel e escopos no contexto
+func RequirePermission(permission domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if !requestctx.Role(ctx).Can(permission) || !domain.Allows(requestctx.Scopes(ctx), permission) {
+				http.Error(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireGlobalAdmin rejeita com 403 os administradores de uma organização nas rotas administrativas que valem
+// para todo o gateway, como a auditoria, a configuração e as próprias organizações
+// Deve ser usado depois de AdminMiddleware.Authenticate, que coloca a organização no contexto
+func RequireGlobalAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestctx.Tenant(r.Context()) != "" {
+			http.Error(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/metrics"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/ratelimit"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// RateLimit limita as requisições de cada API Key, inclusive quando usado como segredo da assinatura ou por trás de um JWT
+// Deve vir depois da autenticação; informa o limite nos headers RateLimit-Limit, RateLimit-Remaining e RateLimit-Reset
+// e responde 429 com Retry-After quando o bucket está vazio
+// Se o limitador falhar (Redis fora do ar ou sem resposta dentro de REDIS_REQUEST_TIMEOUT), a requisição é aceita
+// para não derrubar o gateway junto
+func RateLimit(limiter ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), rateLimitKey(r))
+			if err != nil {
+				metrics.RateLimiterErrorsTotal.Inc()
+				slog.WarnContext(r.Context(), "falha ao consultar o limite de requisições", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(result.ResetAfter))
+
+			if !result.Allowed {
+				metrics.RateLimitedTotal.Inc()
+				w.Header().Set("Retry-After", seconds(result.RetryAfter))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifica o bucket pelo hash do API Key, que não é gravado no limitador
+// Credenciais sem API Key, como certificados de serviços internos, usam o autor da requisição
+func rateLimitKey(r *http.Request) string {
+	if apiKey := requestctx.APIKey(r.Context()); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:16])
+	}
+	return "actor:" + requestctx.Actor(r.Context())
+}
+
+// seconds arredonda a duração para cima em segundos inteiros, como esperado pelos headers
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
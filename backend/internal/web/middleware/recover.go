@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Recover transforma um panic ao atender a requisição em resposta 500, em vez de derrubar a conexão
+// O panic é registrado como erro, com o stack, e vai para o rastreador de erros quando configurado
+// Deve vir depois de AccessLog para que a linha de acesso registre o 500
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// http.ErrAbortHandler interrompe a resposta de propósito e não é um erro
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			slog.ErrorContext(r.Context(), "panic ao atender a requisição", "panic", value)
+			markRecovered(r.Context())
+			w.WriteHeader(http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+)
+
+// RequestIDHeader é o header usado para receber e devolver o ID da requisição
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID propaga o X-Request-ID recebido ou gera um novo, devolvendo-o na resposta
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(requestctx.WithRequestID(r.Context(), requestID)))
+	})
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Políticas padrão do Content-Security-Policy
+const (
+	// DefaultContentSecurityPolicy vale para as respostas JSON, que não carregam nenhum recurso
+	DefaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+	// DefaultHTMLContentSecurityPolicy libera apenas recursos da própria origem para as páginas HTML
+	DefaultHTMLContentSecurityPolicy = "default-src 'self'; img-src 'self' data:; style-src 'self' 'unsafe-inline'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"
+)
+
+// SecurityHeadersConfig define os headers de segurança das respostas
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge é a validade do Strict-Transport-Security; zero não envia o header
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	// FrameOptions é o valor de X-Frame-Options, como DENY ou SAMEORIGIN; vazio não envia o header
+	FrameOptions string
+	// ContentSecurityPolicy vale para todas as rotas; HTMLContentSecurityPolicy substitui-a nas rotas que servem HTML
+	ContentSecurityPolicy     string
+	HTMLContentSecurityPolicy string
+}
+
+// SecurityHeaders aplica a todas as respostas X-Content-Type-Options, X-Frame-Options, o CSP e,
+// nas conexões TLS, o Strict-Transport-Security
+// Rotas específicas trocam os valores com OverrideHeaders, registrado depois deste middleware
+func SecurityHeaders(config SecurityHeadersConfig) func(http.Handler) http.Handler {
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(config.HSTSMaxAge.Seconds()), 10)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			if config.FrameOptions != "" {
+				header.Set("X-Frame-Options", config.FrameOptions)
+			}
+			if config.ContentSecurityPolicy != "" {
+				header.Set("Content-Security-Policy", config.ContentSecurityPolicy)
+			}
+			// Navegadores ignoram HSTS recebido em HTTP puro
+			if hsts != "" && r.TLS != nil {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HTML aplica o CSP das páginas HTML, como o Swagger UI e os links de pagamento
+func (c SecurityHeadersConfig) HTML() func(http.Handler) http.Handler {
+	return OverrideHeaders(map[string]string{"Content-Security-Policy": c.HTMLContentSecurityPolicy})
+}
+
+// OverrideHeaders troca os headers de segurança de uma rota; valores vazios removem o header
+func OverrideHeaders(headers map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range headers {
+				if value == "" {
+					w.Header().Del(name)
+					continue
+				}
+				w.Header().Set(name, value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
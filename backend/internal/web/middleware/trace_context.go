@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceContext continua o trace iniciado no sistema do lojista: lê os headers traceparent e tracestate (W3C Trace
+// Context) e guarda o contexto de trace na requisição, de onde ele segue para o Kafka e os webhooks
+// Headers ausentes ou inválidos são ignorados e a requisição segue sem trace
+func TraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/requestctx"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
+)
+
+// TwoFactorCodeHeader é o header com o código TOTP ou de recuperação das operações sensíveis
+const TwoFactorCodeHeader = "X-2FA-Code"
+
+// RequireSecondFactor exige o código do segundo fator do autor autenticado antes de operações sensíveis
+// Deve ser registrado depois dos middlewares de autenticação, que definem o autor
+func RequireSecondFactor(twoFactor *service.TwoFactorService, events *service.SecurityService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := requestctx.Actor(r.Context())
+			if !allowUnlocked(w, r, events, domain.AuthMethodTOTP, domain.TwoFactorKeyID(actor)) {
+				return
+			}
+
+			err := twoFactor.Authorize(r.Context(), actor, r.Header.Get(TwoFactorCodeHeader))
+			switch err {
+			case nil:
+				next.ServeHTTP(w, r)
+			case domain.ErrTwoFactorRequired, domain.ErrInvalidTwoFactorCode, domain.ErrTwoFactorNotEnrolled:
+				http.Error(w, err.Error(), http.StatusForbidden)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+// RejectLockedSecondFactor recusa com 423 as requisições de autores com o segundo fator bloqueado
+// Protege as rotas que conferem códigos fora de RequireSecondFactor, como a confirmação do cadastro
+func RejectLockedSecondFactor(events *service.SecurityService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := requestctx.Actor(r.Context())
+			if allowUnlocked(w, r, events, domain.AuthMethodTOTP, domain.TwoFactorKeyID(actor)) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
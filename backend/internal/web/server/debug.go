@@ -0,0 +1,29 @@
+package server
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// debugRoutes registra os perfis do net/http/pprof e as variáveis do expvar no grupo informado
+// Os handlers do pprof esperam o prefixo /debug/pprof/, então cada perfil tem a própria rota
+func debugRoutes(r chi.Router) {
+	r.Get("/debug/vars", expvar.Handler().ServeHTTP)
+
+	r.Get("/debug/pprof", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+	})
+	r.Get("/debug/pprof/", pprof.Index)
+	r.Get("/debug/pprof/cmdline", pprof.Cmdline)
+	r.Get("/debug/pprof/profile", pprof.Profile)
+	r.Get("/debug/pprof/symbol", pprof.Symbol)
+	r.Post("/debug/pprof/symbol", pprof.Symbol)
+	r.Get("/debug/pprof/trace", pprof.Trace)
+	// heap, goroutine, allocs, block, mutex e threadcreate
+	r.Get("/debug/pprof/{profile}", func(w http.ResponseWriter, r *http.Request) {
+		pprof.Handler(chi.URLParam(r, "profile")).ServeHTTP(w, r)
+	})
+}
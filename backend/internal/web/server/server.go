@@ -1,51 +1,499 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/auth"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/domain"
+	"github.com/joaodematejr/imersao22/go-gateway/internal/ratelimit"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/service"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/web/handlers"
 	"github.com/joaodematejr/imersao22/go-gateway/internal/web/middleware"
-	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// MTLSConfig configura o listener opcional que exige certificado de cliente, para chamadas entre serviços
+type MTLSConfig struct {
+	Port string
+	TLS  *tls.Config
+	// Identities associa o CN/SAN dos certificados a contas ou serviços internos
+	Identities *auth.CertificateMapper
+}
+
+// TLSConfig configura a terminação TLS do listener principal
+type TLSConfig struct {
+	Port string
+	TLS  *tls.Config
+	// HTTPHandler envolve o redirecionamento do listener HTTP; o autocert o usa para os desafios ACME. Pode ser nil
+	HTTPHandler func(http.Handler) http.Handler
+}
+
 type Server struct {
-	router         *chi.Mux
-	server         *http.Server
-	accountService *service.AccountService
-	invoiceService *service.InvoiceService
-	port           string
+	router *chi.Mux
+	// mu protege os listeners, criados por Start ou StartTLS e encerrados por Shutdown
+	mu              sync.Mutex
+	server          *http.Server
+	tlsServer       *http.Server
+	mtlsServer      *http.Server
+	metricsServer   *http.Server
+	accountService  *service.AccountService
+	invoiceService  *service.InvoiceService
+	auditService    *service.AuditService
+	healthService   *service.HealthService
+	authService     *service.AuthService
+	securityService *service.SecurityService
+	// twoFactor confere o segundo fator das operações sensíveis
+	twoFactor *service.TwoFactorService
+	// geo aplica a política geográfica das contas na autenticação
+	geo *service.GeoRiskService
+	// limiter limita as requisições por API Key; nil desativa o limite
+	limiter ratelimit.Limiter
+	// nonces registra os nonces das requisições assinadas, bloqueando replays
+	nonces middleware.NonceStore
+	// dataSubjects atende os pedidos de titulares de dados (LGPD/GDPR)
+	dataSubjects *service.DataSubjectService
+	// exports gera as exportações e serve os downloads por links assinados
+	exports *service.ExportService
+	// securityWebhooks gerencia as assinaturas dos eventos de segurança das contas
+	securityWebhooks *service.SecurityWebhookService
+	featureFlags     *service.FeatureFlagService
+	slo              *service.SLOService
+	// alerts gerencia as regras do alerta interno
+	alerts *service.AlertService
+	// blocklist gerencia a lista de bloqueio global e as das contas
+	blocklist *service.BlocklistService
+	// reviews gerencia os limites de score de risco e a fila de revisão manual
+	reviews *service.RiskReviewService
+	// platforms gerencia as ligações das contas filhas com as plataformas e ledger o extrato das contas
+	platforms *service.PlatformService
+	ledger    *service.LedgerService
+	// escrow libera os valores das faturas em custódia
+	escrow *service.EscrowService
+	// refunds reembolsa as faturas, com a aprovação por um segundo usuário acima do limite da conta
+	refunds *service.RefundService
+	// disputes registra as disputas das faturas e guarda as evidências enviadas pelas contas
+	disputes *service.DisputeService
+	// fees gerencia as tarifas das contas no lugar das tarifas padrão
+	fees *service.FeeScheduleService
+	// subscriptions gerencia as assinaturas cobradas no cartão guardado no cofre
+	subscriptions *service.SubscriptionService
+	// coupons gerencia os cupons de desconto aplicados na criação das faturas
+	coupons *service.CouponService
+	// customers gerencia os clientes das contas e os cartões guardados deles
+	customers *service.CustomerService
+	// payouts gerencia os prazos de repasse das contas
+	payouts *service.PayoutScheduleService
+	// anticipations antecipa os valores que aguardam a data de repasse
+	anticipations *service.AnticipationService
+	// reconciliation concilia os saldos das contas com o razão
+	reconciliation *service.ReconciliationService
+	// tenants gerencia as organizações e autentica os administradores delas
+	tenants *service.TenantService
+	// amounts gerencia as faixas de valor por fatura próprias das contas
+	amounts *service.AmountLimitService
+	// standingOrders gerencia as ordens permanentes de transferência entre contas
+	standingOrders *service.StandingOrderService
+	adminAPIKey    string
+	// signatureMaxSkew é a tolerância do timestamp das requisições assinadas; zero desativa a assinatura
+	signatureMaxSkew time.Duration
+	// mtls é nil quando o listener mTLS não está configurado
+	mtls *MTLSConfig
+	// headers são os headers de segurança de todas as respostas
+	headers middleware.SecurityHeadersConfig
+	// accessLog define a amostragem das linhas de acesso
+	accessLog *middleware.AccessLogSampling
+	// reloader recarrega a configuração pela rota administrativa
+	reloader handlers.ConfigReloader
+	port     string
+	// metricsPort é a porta do listener interno que serve apenas /metrics, fora das rotas públicas
+	metricsPort string
 }
 
-func NewServer(accountService *service.AccountService, invoiceService *service.InvoiceService, port string) *Server {
+func NewServer(accountService *service.AccountService, invoiceService *service.InvoiceService, auditService *service.AuditService, healthService *service.HealthService, authService *service.AuthService, securityService *service.SecurityService, twoFactor *service.TwoFactorService, geo *service.GeoRiskService, limiter ratelimit.Limiter, nonces middleware.NonceStore, dataSubjects *service.DataSubjectService, exports *service.ExportService, securityWebhooks *service.SecurityWebhookService, featureFlags *service.FeatureFlagService, slo *service.SLOService, alerts *service.AlertService, blocklist *service.BlocklistService, reviews *service.RiskReviewService, platforms *service.PlatformService, ledger *service.LedgerService, escrow *service.EscrowService, refunds *service.RefundService, disputes *service.DisputeService, fees *service.FeeScheduleService, subscriptions *service.SubscriptionService, coupons *service.CouponService, customers *service.CustomerService, payouts *service.PayoutScheduleService, anticipations *service.AnticipationService, reconciliation *service.ReconciliationService, tenants *service.TenantService, amounts *service.AmountLimitService, standingOrders *service.StandingOrderService, adminAPIKey string, signatureMaxSkew time.Duration, mtls *MTLSConfig, headers middleware.SecurityHeadersConfig, accessLog *middleware.AccessLogSampling, reloader handlers.ConfigReloader, port string, metricsPort string) *Server {
 	return &Server{
-		router:         chi.NewRouter(),
-		accountService: accountService,
-		invoiceService: invoiceService,
-		port:           port,
+		router:           chi.NewRouter(),
+		accountService:   accountService,
+		invoiceService:   invoiceService,
+		auditService:     auditService,
+		healthService:    healthService,
+		authService:      authService,
+		securityService:  securityService,
+		twoFactor:        twoFactor,
+		geo:              geo,
+		limiter:          limiter,
+		nonces:           nonces,
+		dataSubjects:     dataSubjects,
+		exports:          exports,
+		securityWebhooks: securityWebhooks,
+		featureFlags:     featureFlags,
+		slo:              slo,
+		alerts:           alerts,
+		blocklist:        blocklist,
+		reviews:          reviews,
+		platforms:        platforms,
+		ledger:           ledger,
+		escrow:           escrow,
+		refunds:          refunds,
+		disputes:         disputes,
+		fees:             fees,
+		subscriptions:    subscriptions,
+		coupons:          coupons,
+		customers:        customers,
+		payouts:          payouts,
+		anticipations:    anticipations,
+		reconciliation:   reconciliation,
+		tenants:          tenants,
+		amounts:          amounts,
+		standingOrders:   standingOrders,
+		adminAPIKey:      adminAPIKey,
+		signatureMaxSkew: signatureMaxSkew,
+		mtls:             mtls,
+		headers:          headers,
+		accessLog:        accessLog,
+		reloader:         reloader,
+		port:             port,
+		metricsPort:      metricsPort,
 	}
 }
 
 func (s *Server) ConfigureRoutes() {
-	accountHandler := handlers.NewAccountHandler(s.accountService)
+	accountHandler := handlers.NewAccountHandler(s.accountService, s.escrow, s.ledger)
 	invoiceHandler := handlers.NewInvoiceHandler(s.invoiceService)
-	authMiddleware := middleware.NewAuthMiddleware(s.accountService)
+	auditHandler := handlers.NewAuditHandler(s.auditService)
+	adminHandler := handlers.NewAdminHandler(s.accountService, s.invoiceService, s.authService)
+	healthHandler := handlers.NewHealthHandler(s.healthService)
+	authHandler := handlers.NewAuthHandler(s.authService)
+	securityHandler := handlers.NewSecurityHandler(s.securityService)
+	webhookHandler := handlers.NewWebhookHandler()
+	twoFactorHandler := handlers.NewTwoFactorHandler(s.twoFactor)
+	geoPolicyHandler := handlers.NewGeoPolicyHandler(s.geo)
+	dataSubjectHandler := handlers.NewDataSubjectHandler(s.dataSubjects)
+	exportHandler := handlers.NewExportHandler(s.exports)
+	securityWebhookHandler := handlers.NewSecurityWebhookHandler(s.securityWebhooks)
+	configHandler := handlers.NewConfigHandler(s.reloader)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(s.featureFlags)
+	sloHandler := handlers.NewSLOHandler(s.slo)
+	alertRuleHandler := handlers.NewAlertRuleHandler(s.alerts)
+	blocklistHandler := handlers.NewBlocklistHandler(s.blocklist)
+	riskReviewHandler := handlers.NewRiskReviewHandler(s.reviews)
+	platformHandler := handlers.NewPlatformHandler(s.platforms)
+	ledgerHandler := handlers.NewLedgerHandler(s.ledger)
+	escrowHandler := handlers.NewEscrowHandler(s.escrow)
+	refundHandler := handlers.NewRefundHandler(s.refunds)
+	disputeHandler := handlers.NewDisputeHandler(s.disputes)
+	feeScheduleHandler := handlers.NewFeeScheduleHandler(s.fees)
+	subscriptionHandler := handlers.NewSubscriptionHandler(s.subscriptions)
+	couponHandler := handlers.NewCouponHandler(s.coupons)
+	customerHandler := handlers.NewCustomerHandler(s.customers)
+	payoutScheduleHandler := handlers.NewPayoutScheduleHandler(s.payouts)
+	anticipationHandler := handlers.NewAnticipationHandler(s.anticipations)
+	reconciliationHandler := handlers.NewReconciliationHandler(s.reconciliation)
+	tenantHandler := handlers.NewTenantHandler(s.tenants)
+	amountLimitHandler := handlers.NewAmountLimitHandler(s.amounts)
+	standingOrderHandler := handlers.NewStandingOrderHandler(s.standingOrders)
+	var certificates *auth.CertificateMapper
+	if s.mtls != nil {
+		certificates = s.mtls.Identities
+	}
+	authMiddleware := middleware.NewAuthMiddleware(s.accountService, s.authService, s.securityService, s.geo, certificates, s.signatureMaxSkew, s.nonces)
+	adminMiddleware := middleware.NewAdminMiddleware(s.adminAPIKey, s.authService, s.tenants, s.securityService, certificates)
+	// Operações destrutivas exigem o código do segundo fator de quem as executa
+	secondFactor := middleware.RequireSecondFactor(s.twoFactor, s.securityService)
+	lockedSecondFactor := middleware.RejectLockedSecondFactor(s.securityService)
+
+	s.router.Use(middleware.RequestID)
+	s.router.Use(middleware.TraceContext)
+	s.router.Use(middleware.ClientInfo)
+	s.router.Use(middleware.AccessLog(s.accessLog))
+	s.router.Use(middleware.ObserveLatency(s.slo))
+	s.router.Use(middleware.Recover)
+	s.router.Use(middleware.SecurityHeaders(s.headers))
+
+	s.router.Get("/readyz", healthHandler.Readyz)
+	s.router.Get("/status", healthHandler.Status)
 
 	s.router.Post("/accounts", accountHandler.Create)
-	s.router.Get("/accounts", accountHandler.Get)
+	s.router.Get("/tenants/{id}/branding", tenantHandler.Branding)
 
+	// O link assinado é a credencial do download
+	s.router.Get("/downloads/{id}", exportHandler.Download)
+
+	s.router.With(middleware.RejectBlockedClients(s.securityService)).Post("/auth/login", authHandler.Login)
+	s.router.Post("/auth/refresh", authHandler.Refresh)
+	s.router.Post("/auth/logout", authHandler.Logout)
+	s.router.Get("/auth/oidc/login", authHandler.OIDCLogin)
+	s.router.With(middleware.RejectBlockedClients(s.securityService)).Get("/auth/oidc/callback", authHandler.OIDCCallback)
+
+	// Cada rota exige a permissão correspondente do papel do API Key ou do usuário autenticado
 	s.router.Group(func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
-		s.router.Post("/invoice", invoiceHandler.Create)
-		s.router.Get("/invoice/{id}", invoiceHandler.GetByID)
-		s.router.Get("/invoice", invoiceHandler.ListByAccount)
+		if s.limiter != nil {
+			r.Use(middleware.RateLimit(s.limiter))
+		}
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts", accountHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/stats", invoiceHandler.Stats)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/flags", featureFlagHandler.ForAccount)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/security/events", securityHandler.ListEvents)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/security/webhook", securityWebhookHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Put("/accounts/security/webhook", securityWebhookHandler.Update)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Delete("/accounts/security/webhook", securityWebhookHandler.Delete)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/geo-policy", geoPolicyHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Put("/accounts/geo-policy", geoPolicyHandler.Update)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/blocklist", blocklistHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Post("/accounts/blocklist", blocklistHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Delete("/accounts/blocklist/{id}", blocklistHandler.Delete)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/risk-policy", riskReviewHandler.GetPolicy)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Put("/accounts/risk-policy", riskReviewHandler.UpdatePolicy)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/reviews", riskReviewHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/statement", ledgerHandler.Statement)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/refund-policy", refundHandler.GetPolicy)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Put("/accounts/refund-policy", refundHandler.UpdatePolicy)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/refunds", refundHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionWriteRefunds)).Post("/accounts/refunds/{id}/decision", refundHandler.Decide)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/fees", feeScheduleHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/payout-schedule", payoutScheduleHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionManageSecurity)).Put("/accounts/payout-schedule", payoutScheduleHandler.Update)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/anticipations/quote", anticipationHandler.Quote)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/anticipations", anticipationHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance)).Post("/accounts/anticipations", anticipationHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance), secondFactor).Post("/accounts/standing-orders", standingOrderHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/standing-orders", standingOrderHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Get("/accounts/standing-orders/{id}", standingOrderHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance)).Post("/accounts/standing-orders/{id}/cancel", standingOrderHandler.Cancel)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/disputes", disputeHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/disputes/{id}", disputeHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/disputes/{id}/evidence", disputeHandler.AddEvidence)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/disputes/{id}/evidence/{evidence_id}", disputeHandler.GetEvidence)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/subscriptions", subscriptionHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/subscriptions", subscriptionHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/subscriptions/{id}", subscriptionHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/subscriptions/{id}/cancel", subscriptionHandler.Cancel)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/subscriptions/{id}/resume", subscriptionHandler.Resume)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/coupons", couponHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/coupons", couponHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/coupons/{id}", couponHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/coupons/{id}/deactivate", couponHandler.Deactivate)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/customers", customerHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/customers", customerHandler.List)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/accounts/customers/{id}", customerHandler.Get)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Delete("/accounts/customers/{id}", customerHandler.Delete)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/accounts/customers/{id}/payment-methods", customerHandler.AddPaymentMethod)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Delete("/accounts/customers/{id}/payment-methods/{token}", customerHandler.RemovePaymentMethod)
+		r.With(middleware.RequirePermission(domain.PermissionReadAccount)).Post("/webhooks/verify", webhookHandler.Verify)
+		r.With(middleware.RequirePermission(domain.PermissionManagePrivacy)).Post("/accounts/data-subjects/export", dataSubjectHandler.Export)
+		r.With(middleware.RequirePermission(domain.PermissionManagePrivacy), secondFactor).Post("/accounts/data-subjects/anonymize", dataSubjectHandler.Anonymize)
+		r.With(middleware.RequirePermission(domain.PermissionManagePrivacy)).Get("/accounts/data-subjects/requests", dataSubjectHandler.ListRequests)
+		r.With(middleware.RequirePermission(domain.PermissionManagePrivacy)).Post("/accounts/data-subjects/exports", exportHandler.CreateDataSubjectExport)
+		r.With(middleware.RequirePermission(domain.PermissionManagePrivacy)).Get("/accounts/data-subjects/exports/{id}", exportHandler.GetDataSubjectExport)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance), secondFactor).Post("/accounts/balance", accountHandler.AdjustBalance)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance)).Post("/accounts/2fa", twoFactorHandler.Enroll)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance), lockedSecondFactor).Post("/accounts/2fa/confirm", twoFactorHandler.Confirm)
+		r.With(middleware.RequirePermission(domain.PermissionAdjustBalance), lockedSecondFactor).Delete("/accounts/2fa", twoFactorHandler.Disable)
+		r.With(middleware.RequirePermission(domain.PermissionManageUsers)).Post("/users", authHandler.CreateUser)
+		r.Get("/auth/sessions", authHandler.ListSessions)
+		r.Delete("/auth/sessions/{id}", authHandler.RevokeSession)
+		r.Put("/auth/password", authHandler.ChangePassword)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/invoice", invoiceHandler.Create)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/invoice/batch", invoiceHandler.CreateBatch)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Post("/invoice/exports", exportHandler.CreateInvoiceReport)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/invoice/exports/{id}", exportHandler.GetInvoiceReport)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/invoice/{id}", invoiceHandler.GetByID)
+		r.With(middleware.RequirePermission(domain.PermissionWriteInvoices)).Post("/invoice/{id}/release", escrowHandler.Release)
+		r.With(middleware.RequirePermission(domain.PermissionWriteRefunds)).Post("/invoice/{id}/refunds", refundHandler.Request)
+		r.With(middleware.RequirePermission(domain.PermissionReadInvoices)).Get("/invoice", invoiceHandler.ListByAccount)
+	})
+
+	s.router.Route("/admin", func(r chi.Router) {
+		r.Use(adminMiddleware.Authenticate)
+
+		// Os administradores de uma organização só chegam a estas rotas, restritas às contas da organização pelos
+		// repositórios
+		r.Post("/2fa", twoFactorHandler.Enroll)
+		r.With(lockedSecondFactor).Post("/2fa/confirm", twoFactorHandler.Confirm)
+		r.With(lockedSecondFactor).Delete("/2fa", twoFactorHandler.Disable)
+
+		r.Get("/tenant", tenantHandler.Current)
+		r.Put("/tenant/branding", tenantHandler.UpdateBranding)
+
+		r.Post("/accounts", accountHandler.Create)
+		r.Get("/accounts/{id}", adminHandler.GetAccount)
+		r.With(secondFactor).Delete("/accounts/{id}", adminHandler.DeleteAccount)
+		r.Put("/accounts/{id}/role", adminHandler.UpdateAccountRole)
+		r.Get("/accounts/{id}/invoices", adminHandler.ListAccountInvoices)
+		r.Get("/accounts/{id}/platform", platformHandler.Get)
+		r.Put("/accounts/{id}/platform", platformHandler.Link)
+		r.Delete("/accounts/{id}/platform", platformHandler.Unlink)
+		r.Get("/accounts/{id}/fees", feeScheduleHandler.GetForAccount)
+		r.With(secondFactor).Put("/accounts/{id}/fees", feeScheduleHandler.Update)
+		r.With(secondFactor).Delete("/accounts/{id}/fees", feeScheduleHandler.Reset)
+		r.Get("/accounts/{id}/reconciliation", reconciliationHandler.Get)
+		r.With(secondFactor).Post("/accounts/{id}/reconciliation", reconciliationHandler.Correct)
+		r.Get("/reconciliation", reconciliationHandler.List)
+		r.Get("/invoices/{id}", adminHandler.GetInvoice)
+		r.With(secondFactor).Delete("/invoices/{id}", adminHandler.DeleteInvoice)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireGlobalAdmin)
+			r.Get("/audit-logs", auditHandler.List)
+			r.Get("/audit-logs/export", auditHandler.Export)
+
+			r.Get("/tenants", tenantHandler.List)
+			r.Post("/tenants", tenantHandler.Create)
+			r.Get("/tenants/{id}", tenantHandler.Get)
+			r.Put("/tenants/{id}", tenantHandler.Configure)
+			r.With(secondFactor).Post("/tenants/{id}/admin-key", tenantHandler.RotateAdminKey)
+			r.Put("/accounts/{id}/tenant", tenantHandler.AssignAccount)
+			r.Get("/accounts/{id}/amount-limits", amountLimitHandler.Get)
+			r.Put("/accounts/{id}/amount-limits", amountLimitHandler.Update)
+			r.Delete("/accounts/{id}/amount-limits", amountLimitHandler.Reset)
+			r.Get("/accounts/{id}/refund-window", refundHandler.GetWindow)
+			r.Put("/accounts/{id}/refund-window", refundHandler.UpdateWindow)
+			r.Delete("/accounts/{id}/refund-window", refundHandler.ResetWindow)
+			r.With(secondFactor).Post("/invoices/{id}/refunds", refundHandler.Force)
+			r.With(secondFactor).Post("/invoices/{id}/payment", adminHandler.ConfirmPayment)
+
+			r.Put("/users/{id}/role", adminHandler.UpdateUserRole)
+			r.Get("/accounts/{id}/flags", featureFlagHandler.ForAccountID)
+			r.Post("/invoices/{id}/disputes", disputeHandler.Open)
+			r.Get("/disputes", disputeHandler.ListAll)
+			r.Post("/disputes/{id}/resolve", disputeHandler.Resolve)
+			r.Post("/config/reload", configHandler.Reload)
+			r.Get("/flags", featureFlagHandler.List)
+			r.Get("/slo", sloHandler.Report)
+			r.Get("/alert-rules", alertRuleHandler.List)
+			r.Post("/alert-rules", alertRuleHandler.Create)
+			r.Put("/alert-rules/{id}", alertRuleHandler.Update)
+			r.Delete("/alert-rules/{id}", alertRuleHandler.Delete)
+			r.Get("/blocklist", blocklistHandler.ListGlobal)
+			r.Post("/blocklist", blocklistHandler.CreateGlobal)
+			r.Delete("/blocklist/{id}", blocklistHandler.DeleteGlobal)
+			r.Get("/reviews", riskReviewHandler.ListAll)
+			r.Post("/reviews/{invoice_id}/decision", riskReviewHandler.Decide)
+
+			// Diagnóstico em produção sem novo deploy: perfis de CPU e memória e variáveis de runtime
+			debugRoutes(r)
+		})
 	})
 }
 
+// Start sobe o listener HTTP e, se configurado, o listener mTLS com as mesmas rotas, além do listener interno das
+// métricas
+// Retorna o erro do primeiro listener que parar
 func (s *Server) Start() error {
+	s.mu.Lock()
 	s.server = &http.Server{
 		Addr:    ":" + s.port,
 		Handler: s.router,
 	}
-	return s.server.ListenAndServe()
+	s.mu.Unlock()
+	return s.serve(s.server.ListenAndServe)
+}
+
+// StartTLS serve as rotas por HTTPS na porta de config e deixa a porta HTTP apenas redirecionando para HTTPS
+// O listener mTLS, se configurado, sobe junto; retorna o erro do primeiro listener que parar
+func (s *Server) StartTLS(config *TLSConfig) error {
+	s.mu.Lock()
+	s.tlsServer = &http.Server{
+		Addr:      ":" + config.Port,
+		Handler:   s.router,
+		TLSConfig: config.TLS,
+	}
+
+	redirect := redirectToHTTPS(config.Port)
+	if config.HTTPHandler != nil {
+		redirect = config.HTTPHandler(redirect)
+	}
+	s.server = &http.Server{
+		Addr:    ":" + s.port,
+		Handler: redirect,
+	}
+	s.mu.Unlock()
+
+	// Os certificados já estão em TLSConfig
+	return s.serve(s.server.ListenAndServe, func() error { return s.tlsServer.ListenAndServeTLS("", "") })
+}
+
+// serve executa os listeners informados, o listener interno das métricas e, se configurado, o listener mTLS,
+// retornando o primeiro erro
+func (s *Server) serve(listeners ...func() error) error {
+	// As métricas expõem volumes e taxas de aprovação por conta, então ficam num listener só para a rede interna,
+	// sem passar pelas rotas públicas
+	metrics := http.NewServeMux()
+	metrics.Handle("GET /metrics", promhttp.Handler())
+	s.mu.Lock()
+	s.metricsServer = &http.Server{
+		Addr:    ":" + s.metricsPort,
+		Handler: metrics,
+	}
+	s.mu.Unlock()
+	listeners = append(listeners, s.metricsServer.ListenAndServe)
+
+	if s.mtls != nil {
+		s.mu.Lock()
+		s.mtlsServer = &http.Server{
+			Addr:      ":" + s.mtls.Port,
+			Handler:   s.router,
+			TLSConfig: s.mtls.TLS,
+		}
+		s.mu.Unlock()
+		listeners = append(listeners, func() error { return s.mtlsServer.ListenAndServeTLS("", "") })
+	}
+
+	errs := make(chan error, len(listeners))
+	for _, listen := range listeners {
+		go func() { errs <- listen() }()
+	}
+	return <-errs
+}
+
+// Shutdown para de aceitar conexões em todos os listeners e espera as requisições em andamento terminarem ou
+// o contexto acabar; depois dele Start e StartTLS retornam http.ErrServerClosed
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	servers := []*http.Server{s.server, s.tlsServer, s.mtlsServer, s.metricsServer}
+	s.mu.Unlock()
+
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			if server == nil {
+				errs <- nil
+				return
+			}
+			errs <- server.Shutdown(ctx)
+		}()
+	}
+
+	var err error
+	for range servers {
+		err = errors.Join(err, <-errs)
+	}
+	return err
+}
+
+// redirectToHTTPS redireciona para o mesmo endereço em HTTPS na porta informada
+// O status 308 faz os clientes repetirem o mesmo método e corpo
+func redirectToHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
 }
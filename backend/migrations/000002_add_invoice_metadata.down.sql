@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_invoices_account_id_created_at;
+DROP INDEX IF EXISTS idx_invoices_metadata;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS metadata;
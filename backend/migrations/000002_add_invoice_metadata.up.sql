@@ -0,0 +1,4 @@
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_invoices_metadata ON invoices USING GIN (metadata);
+CREATE INDEX idx_invoices_account_id_created_at ON invoices(account_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_invoices_deleted_at;
+DROP INDEX IF EXISTS idx_accounts_deleted_at;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS deleted_at;
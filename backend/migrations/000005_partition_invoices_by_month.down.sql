@@ -0,0 +1,30 @@
+CREATE TABLE invoices_unpartitioned (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    description TEXT NOT NULL,
+    payment_type VARCHAR(50) NOT NULL,
+    card_last_digits VARCHAR(4),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+INSERT INTO invoices_unpartitioned (id, account_id, amount, status, description, payment_type, card_last_digits, metadata, created_at, updated_at, deleted_at)
+SELECT id, account_id, amount, status, description, payment_type, card_last_digits, metadata, created_at, updated_at, deleted_at
+FROM invoices;
+
+DROP TABLE invoices;
+DROP FUNCTION IF EXISTS create_invoice_partition(DATE);
+
+ALTER TABLE invoices_unpartitioned RENAME TO invoices;
+ALTER TABLE invoices RENAME CONSTRAINT invoices_unpartitioned_pkey TO invoices_pkey;
+
+CREATE INDEX idx_invoices_account_id ON invoices(account_id);
+CREATE INDEX idx_invoices_status ON invoices(status);
+CREATE INDEX idx_invoices_created_at ON invoices(created_at);
+CREATE INDEX idx_invoices_metadata ON invoices USING GIN (metadata);
+CREATE INDEX idx_invoices_account_id_created_at ON invoices(account_id, created_at DESC);
+CREATE INDEX idx_invoices_deleted_at ON invoices(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,60 @@
+-- Converte invoices em tabela particionada por mês de created_at
+-- A chave primária passa a incluir created_at, exigência do PostgreSQL para tabelas particionadas
+ALTER TABLE invoices RENAME TO invoices_unpartitioned;
+ALTER TABLE invoices_unpartitioned RENAME CONSTRAINT invoices_pkey TO invoices_unpartitioned_pkey;
+
+CREATE TABLE invoices (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    description TEXT NOT NULL,
+    payment_type VARCHAR(50) NOT NULL,
+    card_last_digits VARCHAR(4),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- Cria (se ainda não existir) a partição do mês que contém a data informada, ex.: invoices_2025_01
+-- Usada por esta migration e pelo job de manutenção da aplicação
+CREATE OR REPLACE FUNCTION create_invoice_partition(month DATE) RETURNS TEXT AS $$
+DECLARE
+    start_date DATE := date_trunc('month', month)::DATE;
+    end_date DATE := (date_trunc('month', month) + INTERVAL '1 month')::DATE;
+    partition_name TEXT := 'invoices_' || to_char(start_date, 'YYYY_MM');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF invoices FOR VALUES FROM (%L) TO (%L)',
+        partition_name, start_date, end_date
+    );
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Partições desde a fatura mais antiga até três meses à frente
+SELECT create_invoice_partition(month::DATE)
+FROM generate_series(
+    date_trunc('month', COALESCE((SELECT MIN(created_at) FROM invoices_unpartitioned), CURRENT_DATE)),
+    date_trunc('month', CURRENT_DATE) + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS month;
+
+-- Recebe faturas fora das partições mensais caso o job de manutenção atrase
+CREATE TABLE invoices_default PARTITION OF invoices DEFAULT;
+
+INSERT INTO invoices (id, account_id, amount, status, description, payment_type, card_last_digits, metadata, created_at, updated_at, deleted_at)
+SELECT id, account_id, amount, status, description, payment_type, card_last_digits, metadata, created_at, updated_at, deleted_at
+FROM invoices_unpartitioned;
+
+DROP TABLE invoices_unpartitioned;
+
+-- Índices criados na tabela pai são replicados em cada partição
+CREATE INDEX idx_invoices_account_id ON invoices(account_id);
+CREATE INDEX idx_invoices_status ON invoices(status);
+CREATE INDEX idx_invoices_created_at ON invoices(created_at);
+CREATE INDEX idx_invoices_metadata ON invoices USING GIN (metadata);
+CREATE INDEX idx_invoices_account_id_created_at ON invoices(account_id, created_at DESC);
+CREATE INDEX idx_invoices_deleted_at ON invoices(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_log_created_at_id;
+
+DROP INDEX IF EXISTS idx_invoices_account_id_created_at_id;
+CREATE INDEX idx_invoices_account_id_created_at ON invoices(account_id, created_at DESC);
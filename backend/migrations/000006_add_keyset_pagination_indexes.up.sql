@@ -0,0 +1,5 @@
+-- Índices na ordem da paginação por keyset (created_at DESC, id DESC)
+DROP INDEX IF EXISTS idx_invoices_account_id_created_at;
+CREATE INDEX idx_invoices_account_id_created_at_id ON invoices(account_id, created_at DESC, id DESC);
+
+CREATE INDEX idx_audit_log_created_at_id ON audit_log(created_at DESC, id DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_invoices_search_vector;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE invoices DROP COLUMN IF EXISTS payer_name;
@@ -0,0 +1,11 @@
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS payer_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Documento de busca: nome do pagador (peso A), descrição (B) e valores string de metadata (C)
+-- A configuração 'simple' não aplica stemming, adequada para nomes e códigos
+ALTER TABLE invoices ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', payer_name), 'A') ||
+    setweight(to_tsvector('simple', description), 'B') ||
+    setweight(jsonb_to_tsvector('simple', metadata, '["string"]'), 'C')
+) STORED;
+
+CREATE INDEX idx_invoices_search_vector ON invoices USING GIN (search_vector);
@@ -0,0 +1,18 @@
+-- Faturas removidas de invoices pela política de retenção (cmd/retention)
+CREATE TABLE IF NOT EXISTS invoices_archive (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL,
+    payment_type VARCHAR(50) NOT NULL,
+    card_last_digits VARCHAR(4),
+    payer_name VARCHAR(255) NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoices_archive_account_id_created_at ON invoices_archive(account_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- Só é reversível enquanto não houver valores cifrados nas colunas
+ALTER TABLE invoices_archive ALTER COLUMN card_last_digits TYPE VARCHAR(4);
+ALTER TABLE invoices ALTER COLUMN card_last_digits TYPE VARCHAR(4);
+
+DROP INDEX IF EXISTS idx_accounts_email_hash;
+ALTER TABLE accounts DROP COLUMN IF EXISTS email_hash;
+
+ALTER TABLE accounts ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE accounts ADD CONSTRAINT accounts_email_key UNIQUE (email);
+CREATE INDEX idx_accounts_email ON accounts(email);
@@ -0,0 +1,13 @@
+-- Colunas cifradas pela aplicação (envelope encryption) precisam de TEXT
+-- A unicidade do e-mail passa para o índice cego email_hash (HMAC-SHA256)
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_email_key;
+DROP INDEX IF EXISTS idx_accounts_email;
+
+ALTER TABLE accounts ALTER COLUMN email TYPE TEXT;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS email_hash VARCHAR(64);
+
+-- Contas anteriores ficam com email_hash nulo e continuam legíveis em texto puro
+CREATE UNIQUE INDEX idx_accounts_email_hash ON accounts(email_hash);
+
+ALTER TABLE invoices ALTER COLUMN card_last_digits TYPE TEXT;
+ALTER TABLE invoices_archive ALTER COLUMN card_last_digits TYPE TEXT;
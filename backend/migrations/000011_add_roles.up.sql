@@ -0,0 +1,4 @@
+-- Papéis do API Key das contas e dos usuários do dashboard: admin, merchant ou read_only
+-- Registros existentes passam a ser merchant, que mantém as permissões anteriores
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'merchant';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'merchant';
@@ -0,0 +1,2 @@
+-- Escopos do API Key separados por vírgulas; chaves existentes recebem todos os escopos
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS scopes VARCHAR(255) NOT NULL DEFAULT 'accounts:read,invoices:read,invoices:write,refunds:write';
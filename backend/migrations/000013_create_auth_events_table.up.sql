@@ -0,0 +1,18 @@
+-- Tentativas de autenticação, bem-sucedidas ou não, para a trilha de segurança das contas
+-- account_id fica nulo quando a credencial não levou a nenhuma conta e não referencia accounts,
+-- pois falhas podem citar contas inexistentes
+CREATE TABLE IF NOT EXISTS auth_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID,
+    key_id VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL,
+    route TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auth_events_account_id_created_at ON auth_events(account_id, created_at DESC, id DESC);
+CREATE INDEX idx_auth_events_key_id_created_at ON auth_events(key_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE invoices_archive DROP COLUMN IF EXISTS card_brand;
+ALTER TABLE invoices_archive DROP COLUMN IF EXISTS card_token;
+ALTER TABLE invoices DROP COLUMN IF EXISTS card_brand;
+ALTER TABLE invoices DROP COLUMN IF EXISTS card_token;
+DROP TABLE IF EXISTS card_tokens;
@@ -0,0 +1,21 @@
+-- Cofre de cartões, separado das faturas; número e nome do portador são cifrados pela aplicação
+-- com a chave CARD_ENCRYPTION_KEY e o número fica vazio quando a chave não está configurada
+CREATE TABLE IF NOT EXISTS card_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    brand VARCHAR(20) NOT NULL,
+    last_digits VARCHAR(4) NOT NULL,
+    expiry_month SMALLINT NOT NULL,
+    expiry_year SMALLINT NOT NULL,
+    holder_name TEXT NOT NULL,
+    number TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_card_tokens_account_id ON card_tokens(account_id);
+
+-- As faturas passam a referenciar o cartão pelo token; faturas anteriores ficam sem token
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS card_token VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS card_brand VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE invoices_archive ADD COLUMN IF NOT EXISTS card_token VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE invoices_archive ADD COLUMN IF NOT EXISTS card_brand VARCHAR(20) NOT NULL DEFAULT '';
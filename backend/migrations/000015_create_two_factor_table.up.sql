@@ -0,0 +1,11 @@
+-- Segundo fator (TOTP) de quem executa operações sensíveis, identificado pelo autor da auditoria
+-- O segredo é cifrado pela aplicação; backup_codes guarda os hashes dos códigos de recuperação separados por vírgulas
+CREATE TABLE IF NOT EXISTS two_factor (
+    actor VARCHAR(320) PRIMARY KEY,
+    secret TEXT NOT NULL,
+    backup_codes TEXT NOT NULL,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id_session_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,11 @@
+-- Sessões do dashboard: session_id se mantém nas rotações do refresh token
+-- Tokens existentes viram cada um a sua própria sessão
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
+UPDATE refresh_tokens SET session_id = id WHERE session_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_id SET NOT NULL;
+
+-- Origem do login ou da última renovação, exibida na lista de sessões
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id_session_id ON refresh_tokens(user_id, session_id);
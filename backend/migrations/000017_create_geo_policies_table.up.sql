@@ -0,0 +1,9 @@
+-- Países de onde cada conta pode ser usada, identificados pelo IP da requisição
+-- As listas guardam códigos ISO 3166-1 separados por vírgulas; action é "block" ou "flag"
+CREATE TABLE IF NOT EXISTS geo_policies (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    allowed_countries TEXT NOT NULL DEFAULT '',
+    blocked_countries TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
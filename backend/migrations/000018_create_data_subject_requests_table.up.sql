@@ -0,0 +1,14 @@
+-- Pedidos de titulares de dados (LGPD/GDPR) atendidos, sem os dados pessoais do titular
+-- type é "export" ou "anonymize"; token é o valor que substituiu os dados na anonimização
+CREATE TABLE IF NOT EXISTS data_subject_requests (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL,
+    invoices INTEGER NOT NULL,
+    token VARCHAR(64) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_data_subject_requests_account_id ON data_subject_requests(account_id, created_at DESC);
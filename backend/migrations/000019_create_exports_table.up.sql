@@ -0,0 +1,16 @@
+-- Relatórios e exportações gerados para download por link assinado
+-- object_key é a chave do arquivo no armazenamento; expires_at é quando o arquivo deixa de ser baixado
+CREATE TABLE IF NOT EXISTS exports (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    object_key VARCHAR(512) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(128) NOT NULL,
+    size BIGINT NOT NULL,
+    downloads INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_exports_account_id ON exports(account_id, created_at DESC);
@@ -0,0 +1,8 @@
+-- Assinaturas dos eventos de segurança das contas, entregues por webhook assinado com o API Key
+-- events guarda os tipos assinados separados por vírgulas; vazio assina todos
+CREATE TABLE IF NOT EXISTS security_webhooks (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    url VARCHAR(2048) NOT NULL,
+    events TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
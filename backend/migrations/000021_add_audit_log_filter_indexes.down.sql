@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_log_actor_created_at_id;
+DROP INDEX IF EXISTS idx_audit_log_entity_id_created_at_id;
+DROP INDEX IF EXISTS idx_audit_log_entity_created_at_id;
+CREATE INDEX idx_audit_log_entity ON audit_log(entity, entity_id);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor);
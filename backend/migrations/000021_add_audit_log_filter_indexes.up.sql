@@ -0,0 +1,7 @@
+-- Índices das consultas de auditoria filtradas por entidade ou autor, na ordem da paginação por keyset,
+-- para que filtros com intervalo de datas e exportações não ordenem a trilha inteira
+DROP INDEX IF EXISTS idx_audit_log_entity;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+CREATE INDEX idx_audit_log_entity_created_at_id ON audit_log(entity, created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_entity_id_created_at_id ON audit_log(entity, entity_id, created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_actor_created_at_id ON audit_log(actor, created_at DESC, id DESC);
@@ -0,0 +1 @@
+ALTER TABLE geo_policies DROP COLUMN IF EXISTS shadow;
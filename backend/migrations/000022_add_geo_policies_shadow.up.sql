@@ -0,0 +1,2 @@
+-- Políticas em modo sombra são avaliadas e registradas sem bloquear nem marcar as requisições
+ALTER TABLE geo_policies ADD COLUMN IF NOT EXISTS shadow BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- Regras do alerta interno, avaliadas periodicamente sobre as métricas do gateway
+-- window_seconds é a janela da medida; consumer_lag não usa janela
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    window_seconds BIGINT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
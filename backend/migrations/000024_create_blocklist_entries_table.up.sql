@@ -0,0 +1,19 @@
+-- Listas de bloqueio: a global, com account_id vazio, e a de cada conta
+-- value_hash é o HMAC do tipo com o valor normalizado; hint guarda o valor mascarado para exibição
+-- action é "block" ou "allow"; hits e last_hit_at registram as faturas que bateram com a entrada
+CREATE TABLE IF NOT EXISTS blocklist_entries (
+    id UUID PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL DEFAULT '',
+    type VARCHAR(16) NOT NULL,
+    value_hash CHAR(64) NOT NULL,
+    hint VARCHAR(320) NOT NULL,
+    action VARCHAR(8) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NULL,
+    hits BIGINT NOT NULL DEFAULT 0,
+    last_hit_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- A conferência na criação das faturas busca pelo hash nas listas global e da conta
+CREATE UNIQUE INDEX idx_blocklist_entries_value_hash_account_id ON blocklist_entries(value_hash, account_id);
+CREATE INDEX idx_blocklist_entries_account_id_created_at ON blocklist_entries(account_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS invoice_reviews;
+DROP TABLE IF EXISTS risk_policies;
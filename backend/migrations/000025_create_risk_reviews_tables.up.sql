@@ -0,0 +1,25 @@
+-- Limites de score de risco de cada conta; sem linha a conta usa os limites padrão da configuração
+-- Scores abaixo de approve_below são aprovados, acima de reject_above recusados e os demais vão para revisão manual
+CREATE TABLE IF NOT EXISTS risk_policies (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    approve_below NUMERIC(5,2) NOT NULL,
+    reject_above NUMERIC(5,2) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Fila de revisão manual: uma linha por fatura pendente que caiu entre os limites de score da conta
+-- status é "open", "approved" ou "declined"; decided_by é o analista ou o prazo (system:review-sla)
+CREATE TABLE IF NOT EXISTS invoice_reviews (
+    invoice_id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    score NUMERIC(5,2) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- A fila é listada por conta e situação; o prazo busca só as revisões abertas vencidas
+CREATE INDEX idx_invoice_reviews_account_id_status_created_at ON invoice_reviews(account_id, status, created_at);
+CREATE INDEX idx_invoice_reviews_status_due_at ON invoice_reviews(status, due_at);
@@ -0,0 +1,12 @@
+-- Partes das faturas divididas entre contas recebedoras, calculadas na criação e creditadas na aprovação
+-- amount é a parte bruta e fee a taxa de divisão atribuída a ela; a recebedora recebe amount - fee
+CREATE TABLE IF NOT EXISTS invoice_splits (
+    invoice_id UUID NOT NULL,
+    recipient_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (invoice_id, recipient_id)
+);
+-- As partes são buscadas pela fatura, pela chave primária; o índice por recebedora atende à exclusão em cascata das contas
+CREATE INDEX idx_invoice_splits_recipient_id ON invoice_splits(recipient_id);
@@ -0,0 +1,24 @@
+-- Contas filhas de plataformas (marketplaces), que retêm commission_percent do que a conta filha recebe
+-- Uma conta tem no máximo uma plataforma, e uma plataforma não é filha de outra
+CREATE TABLE IF NOT EXISTS platform_links (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    platform_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    commission_percent NUMERIC(5,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_platform_links_platform_id ON platform_links(platform_id);
+
+-- Razão das contas: cada lançamento credita (amount positivo) ou debita (negativo) uma conta por causa de uma fatura
+-- type é "commission" na plataforma e "commission_withheld" na conta filha; counterparty_id é a outra conta, vazio quando não há
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    counterparty_id VARCHAR(36) NOT NULL DEFAULT '',
+    invoice_id UUID NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- O extrato lista os lançamentos da conta por período
+CREATE INDEX idx_ledger_entries_account_id_created_at ON ledger_entries(account_id, created_at);
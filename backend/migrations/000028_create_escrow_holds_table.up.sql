@@ -0,0 +1,17 @@
+-- Valores de faturas aprovadas retidos em custódia, fora do saldo da conta, até release_at ou uma liberação explícita
+-- status é "held", "released" ou "cancelled" (chargeback); released_by é quem liberou ou o prazo (system:escrow-release)
+CREATE TABLE IF NOT EXISTS escrow_holds (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    release_at TIMESTAMP NOT NULL,
+    released_by VARCHAR(255) NOT NULL DEFAULT '',
+    released_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- A liberação explícita busca pela fatura, o prazo só as retidas vencidas e o saldo soma as retidas da conta
+CREATE INDEX idx_escrow_holds_invoice_id ON escrow_holds(invoice_id);
+CREATE INDEX idx_escrow_holds_status_release_at ON escrow_holds(status, release_at);
+CREATE INDEX idx_escrow_holds_account_id_status ON escrow_holds(account_id, status);
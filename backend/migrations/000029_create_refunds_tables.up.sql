@@ -0,0 +1,26 @@
+-- Limite de aprovação dos reembolsos de cada conta; sem linha a conta não exige aprovação
+CREATE TABLE IF NOT EXISTS refund_policies (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    approval_threshold DECIMAL(10,2) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Reembolsos das faturas aprovadas; status é "pending", "completed", "rejected" ou "expired"
+-- Os pendentes aguardam até expires_at a aprovação de um usuário diferente de requested_by
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY,
+    invoice_id UUID NOT NULL,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Os reembolsos são listados por conta e situação e somados por fatura; o prazo busca só os pendentes vencidos
+CREATE INDEX idx_refunds_account_id_status_created_at ON refunds(account_id, status, created_at);
+CREATE INDEX idx_refunds_invoice_id ON refunds(invoice_id);
+CREATE INDEX idx_refunds_status_expires_at ON refunds(status, expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dispute_evidence;
+DROP TABLE IF EXISTS disputes;
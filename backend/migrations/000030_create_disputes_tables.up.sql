@@ -0,0 +1,27 @@
+-- Disputas das faturas aprovadas; status é "open", "won" ou "lost" e cada fatura tem no máximo uma disputa
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY,
+    invoice_id UUID NOT NULL UNIQUE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_disputes_account_id_status_created_at ON disputes(account_id, status, created_at);
+
+-- Evidências anexadas às disputas; o arquivo fica no armazenamento de objetos, em object_key
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id UUID PRIMARY KEY,
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL,
+    object_key VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_dispute_evidence_dispute_id_created_at ON dispute_evidence(dispute_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS fee_schedules;
+
+ALTER TABLE disputes DROP COLUMN IF EXISTS fee;
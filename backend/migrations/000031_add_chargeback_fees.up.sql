@@ -0,0 +1,9 @@
+-- Tarifa de chargeback debitada da conta no registro de cada disputa
+ALTER TABLE disputes ADD COLUMN IF NOT EXISTS fee DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Tarifas de cada conta no lugar das tarifas padrão; sem linha a conta usa CHARGEBACK_FEE
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    chargeback_fee DECIMAL(10,2) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
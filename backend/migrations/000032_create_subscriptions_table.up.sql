@@ -0,0 +1,25 @@
+-- Assinaturas cobradas no cartão guardado no cofre a cada interval_days dias; status é "active", "past_due" ou "cancelled"
+-- due_at é a data da cobrança do período atual e next_attempt_at a próxima tentativa, vazia fora de "active";
+-- failed_attempts conta as recusas seguidas da cobrança do período
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    card_token VARCHAR(64) NOT NULL,
+    card_brand VARCHAR(20) NOT NULL DEFAULT '',
+    card_last_digits VARCHAR(4) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    amount DECIMAL(10,2) NOT NULL,
+    interval_days INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    due_at TIMESTAMP NOT NULL,
+    next_attempt_at TIMESTAMP NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    last_invoice_id VARCHAR(36) NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- As assinaturas são listadas por conta e situação; a cobrança busca só as ativas com tentativa vencida
+CREATE INDEX idx_subscriptions_account_id_status_created_at ON subscriptions(account_id, status, created_at);
+CREATE INDEX idx_subscriptions_status_next_attempt_at ON subscriptions(status, next_attempt_at);
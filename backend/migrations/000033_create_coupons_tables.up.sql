@@ -0,0 +1,29 @@
+-- Cupons de desconto das contas; type é "percentage" ou "fixed" e max_redemptions 0 não limita os usos
+-- valid_from e valid_until, opcionais, delimitam a validade; o código é único por conta
+CREATE TABLE IF NOT EXISTS coupons (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    value DECIMAL(10,2) NOT NULL,
+    max_redemptions INTEGER NOT NULL DEFAULT 0,
+    redemptions INTEGER NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP NULL,
+    valid_until TIMESTAMP NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, code)
+);
+CREATE INDEX idx_coupons_account_id_created_at ON coupons(account_id, created_at);
+
+-- Usos dos cupons, um por fatura, com o valor descontado
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id UUID PRIMARY KEY,
+    coupon_id UUID NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL,
+    invoice_id UUID NOT NULL UNIQUE,
+    discount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_coupon_redemptions_account_id_created_at ON coupon_redemptions(account_id, created_at);
@@ -0,0 +1,11 @@
+-- Impostos das faturas, calculados por linha na criação e somados ao valor cobrado
+-- line é a posição da linha na fatura e rate o percentual aplicado sobre ela
+CREATE TABLE IF NOT EXISTS invoice_taxes (
+    invoice_id UUID NOT NULL,
+    line INTEGER NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    rate DECIMAL(7,4) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (invoice_id, line, name)
+);
@@ -0,0 +1,11 @@
+-- Itens das faturas compostas; a soma de quantity * unit_price é o valor pedido, antes do desconto e dos impostos
+-- position é a posição do item na fatura, a mesma da linha dos impostos em invoice_taxes
+CREATE TABLE IF NOT EXISTS invoice_items (
+    invoice_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (invoice_id, position)
+);
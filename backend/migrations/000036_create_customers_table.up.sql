@@ -0,0 +1,21 @@
+-- Clientes (pagadores) das contas; nome, e-mail e documento chegam cifrados pela aplicação
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    document TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_customers_account_id_created_at ON customers(account_id, created_at);
+
+-- Cartões guardados dos clientes, pelo token do cofre (card_tokens)
+CREATE TABLE IF NOT EXISTS customer_payment_methods (
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    card_token VARCHAR(64) NOT NULL,
+    card_brand VARCHAR(20) NOT NULL,
+    card_last_digits VARCHAR(4) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (customer_id, card_token)
+);
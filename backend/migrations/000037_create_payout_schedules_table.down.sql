@@ -0,0 +1,3 @@
+ALTER TABLE escrow_holds DROP COLUMN IF EXISTS kind;
+
+DROP TABLE IF EXISTS payout_schedules;
@@ -0,0 +1,9 @@
+-- Prazo de repasse de cada conta, em dias depois da aprovação; sem linha a conta usa PAYOUT_DEFAULT_SCHEDULE
+CREATE TABLE IF NOT EXISTS payout_schedules (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    delay_days INTEGER NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Motivo das retenções: "escrow", a custódia pedida pela fatura, ou "payout", o prazo de repasse da conta
+ALTER TABLE escrow_holds ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'escrow';
@@ -0,0 +1,12 @@
+-- Antecipações dos valores que aguardavam a data de repasse; a conta recebe amount menos fee
+-- holds é o número de retenções liberadas, marcadas com released_by igual a anticipation:{id}
+CREATE TABLE IF NOT EXISTS anticipations (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL,
+    holds INTEGER NOT NULL,
+    requested_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_anticipations_account_id_created_at ON anticipations(account_id, created_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_accounts_tenant_id;
+ALTER TABLE accounts DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,20 @@
+-- Organizações acima das contas nas implantações white-label, com a marca, a tarifa de chargeback e os tetos
+-- de gasto das contas delas; admin_key_hash é o SHA-256 da chave dos administradores da organização
+CREATE TABLE IF NOT EXISTS tenants (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    admin_key_hash VARCHAR(64) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    logo_url VARCHAR(2048) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    support_email VARCHAR(255) NOT NULL DEFAULT '',
+    chargeback_fee DECIMAL(10,2),
+    spending_limit_daily DECIMAL(12,2),
+    spending_limit_monthly DECIMAL(12,2),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Organização da conta; sem organização a conta só é vista pelos administradores globais
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id);
+CREATE INDEX IF NOT EXISTS idx_accounts_tenant_id ON accounts(tenant_id);
@@ -0,0 +1,8 @@
+-- Faixa de valor por fatura própria de cada conta; zero desativa o limite e sem linha a conta usa AMOUNT_MIN e
+-- AMOUNT_MAX
+CREATE TABLE IF NOT EXISTS account_amount_limits (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    min_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    max_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,25 @@
+-- Ordens permanentes que transferem parte do saldo da conta para destination_id a cada interval_days dias; status é
+-- "active" ou "cancelled"
+-- Com percent maior que zero a transferência é essa porcentagem do saldo, senão o valor fixo amount; next_run_at é a
+-- próxima transferência, vazia na ordem cancelada, e failed_runs conta as transferências seguidas não feitas
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    destination_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    interval_days INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    next_run_at TIMESTAMP NULL,
+    last_run_at TIMESTAMP NULL,
+    last_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    failed_runs INTEGER NOT NULL DEFAULT 0,
+    last_failure VARCHAR(32) NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- As ordens são listadas por conta e situação; a tarefa de transferências busca só as ativas vencidas
+CREATE INDEX idx_standing_orders_account_id_status_created_at ON standing_orders(account_id, status, created_at);
+CREATE INDEX idx_standing_orders_status_next_run_at ON standing_orders(status, next_run_at);
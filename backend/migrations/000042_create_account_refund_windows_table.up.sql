@@ -0,0 +1,7 @@
+-- Prazo para reembolsar as faturas próprio de cada conta, em dias contados da criação da fatura; zero desativa o
+-- prazo e sem linha a conta usa REFUND_WINDOW_DAYS
+CREATE TABLE IF NOT EXISTS account_refund_windows (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    days INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
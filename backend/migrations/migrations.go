@@ -0,0 +1,52 @@
+// Package migrations embute no binário as migrations do golang-migrate, para que a aplicação saiba qual versão
+// do schema o código espera e para que gateway migrate as aplique
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql mysql/*.sql
+var files embed.FS
+
+// dir retorna o diretório das migrations do banco informado
+func dir(driver string) string {
+	if driver == "mysql" {
+		return "mysql"
+	}
+	return "."
+}
+
+// FS retorna as migrations do banco informado, "postgres" ou "mysql", com as de subida e as de descida
+func FS(driver string) (fs.FS, error) {
+	return fs.Sub(files, dir(driver))
+}
+
+// Latest retorna a versão da última migration do banco informado: "postgres" ou "mysql"
+func Latest(driver string) (uint, error) {
+	entries, err := fs.ReadDir(files, dir(driver))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration file name %q: %w", entry.Name(), err)
+		}
+		latest = max(latest, uint(version))
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found for driver %q", driver)
+	}
+	return latest, nil
+}
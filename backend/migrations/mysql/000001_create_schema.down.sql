@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS invoices_archive;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,73 @@
+-- Esquema completo para MySQL 8 / MariaDB 10.6+ (DB_DRIVER=mysql)
+-- Equivale às migrations do PostgreSQL até 000009, sem o particionamento mensal de invoices
+-- Datas são gravadas em UTC e os dados pessoais chegam cifrados pela aplicação
+CREATE TABLE IF NOT EXISTS accounts (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email TEXT NOT NULL,
+    email_hash VARCHAR(64),
+    api_key VARCHAR(255) NOT NULL,
+    balance DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at DATETIME(6) NULL,
+    UNIQUE KEY idx_accounts_api_key (api_key),
+    UNIQUE KEY idx_accounts_email_hash (email_hash),
+    KEY idx_accounts_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    description TEXT NOT NULL,
+    payment_type VARCHAR(50) NOT NULL,
+    card_last_digits TEXT,
+    payer_name VARCHAR(255) NOT NULL DEFAULT '',
+    metadata JSON NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    deleted_at DATETIME(6) NULL,
+    CONSTRAINT fk_invoices_account_id FOREIGN KEY (account_id) REFERENCES accounts(id),
+    KEY idx_invoices_account_id_created_at_id (account_id, created_at DESC, id DESC),
+    KEY idx_invoices_status (status),
+    KEY idx_invoices_created_at (created_at),
+    KEY idx_invoices_deleted_at (deleted_at),
+    -- Busca textual em nome do pagador e descrição (metadata fica de fora no MySQL)
+    FULLTEXT KEY idx_invoices_search (payer_name, description)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    entity VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    old_value JSON,
+    new_value JSON,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_audit_log_entity (entity, entity_id),
+    KEY idx_audit_log_actor (actor),
+    KEY idx_audit_log_request_id (request_id),
+    KEY idx_audit_log_created_at_id (created_at DESC, id DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Faturas removidas de invoices pela política de retenção (cmd/retention)
+CREATE TABLE IF NOT EXISTS invoices_archive (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL,
+    payment_type VARCHAR(50) NOT NULL,
+    card_last_digits TEXT,
+    payer_name VARCHAR(255) NOT NULL DEFAULT '',
+    metadata JSON NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NOT NULL,
+    deleted_at DATETIME(6) NULL,
+    archived_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_invoices_archive_account_id_created_at (account_id, created_at DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN role;
+ALTER TABLE accounts DROP COLUMN role;
@@ -0,0 +1,3 @@
+-- Papéis do API Key das contas e dos usuários do dashboard (equivale à migration 000011 do PostgreSQL)
+ALTER TABLE accounts ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'merchant' AFTER api_key;
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'merchant' AFTER email_hash;
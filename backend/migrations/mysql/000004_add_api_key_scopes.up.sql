@@ -0,0 +1,2 @@
+-- Escopos do API Key separados por vírgulas (equivale à migration 000012 do PostgreSQL)
+ALTER TABLE accounts ADD COLUMN scopes VARCHAR(255) NOT NULL DEFAULT 'accounts:read,invoices:read,invoices:write,refunds:write' AFTER role;
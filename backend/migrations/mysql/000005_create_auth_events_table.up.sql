@@ -0,0 +1,15 @@
+-- Eventos de autenticação (equivale à migration 000013 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS auth_events (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NULL,
+    key_id VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL,
+    route TEXT NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_auth_events_account_id_created_at (account_id, created_at DESC, id DESC),
+    KEY idx_auth_events_key_id_created_at (key_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+ALTER TABLE invoices_archive DROP COLUMN card_brand, DROP COLUMN card_token;
+ALTER TABLE invoices DROP COLUMN card_brand, DROP COLUMN card_token;
+DROP TABLE IF EXISTS card_tokens;
@@ -0,0 +1,21 @@
+-- Cofre de cartões e token nas faturas (equivale à migration 000014 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS card_tokens (
+    token VARCHAR(64) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    brand VARCHAR(20) NOT NULL,
+    last_digits VARCHAR(4) NOT NULL,
+    expiry_month SMALLINT NOT NULL,
+    expiry_year SMALLINT NOT NULL,
+    holder_name TEXT NOT NULL,
+    number TEXT NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_card_tokens_account_id FOREIGN KEY (account_id) REFERENCES accounts(id),
+    KEY idx_card_tokens_account_id (account_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE invoices
+    ADD COLUMN card_token VARCHAR(64) NOT NULL DEFAULT '' AFTER payment_type,
+    ADD COLUMN card_brand VARCHAR(20) NOT NULL DEFAULT '' AFTER card_token;
+ALTER TABLE invoices_archive
+    ADD COLUMN card_token VARCHAR(64) NOT NULL DEFAULT '' AFTER payment_type,
+    ADD COLUMN card_brand VARCHAR(20) NOT NULL DEFAULT '' AFTER card_token;
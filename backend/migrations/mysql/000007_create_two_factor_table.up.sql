@@ -0,0 +1,10 @@
+-- Segundo fator das operações sensíveis (equivale à migration 000015 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS two_factor (
+    actor VARCHAR(320) PRIMARY KEY,
+    secret TEXT NOT NULL,
+    backup_codes TEXT NOT NULL,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
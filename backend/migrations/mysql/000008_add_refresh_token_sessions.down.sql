@@ -0,0 +1,4 @@
+DROP INDEX idx_refresh_tokens_user_id_session_id ON refresh_tokens;
+ALTER TABLE refresh_tokens DROP COLUMN user_agent;
+ALTER TABLE refresh_tokens DROP COLUMN ip;
+ALTER TABLE refresh_tokens DROP COLUMN session_id;
@@ -0,0 +1,9 @@
+-- Sessões do dashboard (equivale à migration 000016 do PostgreSQL)
+ALTER TABLE refresh_tokens ADD COLUMN session_id CHAR(36) NULL AFTER user_id;
+UPDATE refresh_tokens SET session_id = id WHERE session_id IS NULL;
+ALTER TABLE refresh_tokens MODIFY session_id CHAR(36) NOT NULL;
+
+ALTER TABLE refresh_tokens ADD COLUMN ip VARCHAR(45) NOT NULL DEFAULT '' AFTER token_hash;
+ALTER TABLE refresh_tokens ADD COLUMN user_agent TEXT NOT NULL AFTER ip;
+
+CREATE INDEX idx_refresh_tokens_user_id_session_id ON refresh_tokens(user_id, session_id);
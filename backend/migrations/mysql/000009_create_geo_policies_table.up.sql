@@ -0,0 +1,9 @@
+-- Políticas geográficas das contas (equivale à migration 000017 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS geo_policies (
+    account_id CHAR(36) PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
+    allowed_countries TEXT NOT NULL,
+    blocked_countries TEXT NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_geo_policies_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
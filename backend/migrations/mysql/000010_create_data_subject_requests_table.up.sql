@@ -0,0 +1,13 @@
+-- Pedidos de titulares de dados atendidos (equivale à migration 000018 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS data_subject_requests (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    invoices INT NOT NULL,
+    token VARCHAR(64) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY idx_data_subject_requests_account_id (account_id, created_at DESC),
+    CONSTRAINT fk_data_subject_requests_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
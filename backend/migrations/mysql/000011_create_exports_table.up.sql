@@ -0,0 +1,15 @@
+-- Exportações para download por link assinado (equivale à migration 000019 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS exports (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    object_key VARCHAR(512) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(128) NOT NULL,
+    size BIGINT NOT NULL,
+    downloads INT NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    expires_at DATETIME(6) NOT NULL,
+    KEY idx_exports_account_id (account_id, created_at DESC),
+    CONSTRAINT fk_exports_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,8 @@
+-- Assinaturas dos eventos de segurança das contas (equivale à migration 000020 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS security_webhooks (
+    account_id CHAR(36) PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    events TEXT NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_security_webhooks_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,6 @@
+ALTER TABLE audit_log
+    DROP INDEX idx_audit_log_actor_created_at_id,
+    DROP INDEX idx_audit_log_entity_id_created_at_id,
+    DROP INDEX idx_audit_log_entity_created_at_id,
+    ADD INDEX idx_audit_log_entity (entity, entity_id),
+    ADD INDEX idx_audit_log_actor (actor);
@@ -0,0 +1,7 @@
+-- Índices das consultas de auditoria filtradas por entidade ou autor (equivale à migration 000021 do PostgreSQL)
+ALTER TABLE audit_log
+    DROP INDEX idx_audit_log_entity,
+    DROP INDEX idx_audit_log_actor,
+    ADD INDEX idx_audit_log_entity_created_at_id (entity, created_at DESC, id DESC),
+    ADD INDEX idx_audit_log_entity_id_created_at_id (entity, entity_id, created_at DESC, id DESC),
+    ADD INDEX idx_audit_log_actor_created_at_id (actor, created_at DESC, id DESC);
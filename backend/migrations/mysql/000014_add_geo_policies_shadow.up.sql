@@ -0,0 +1,2 @@
+-- Modo sombra das políticas geográficas (equivale à migration 000022 do PostgreSQL)
+ALTER TABLE geo_policies ADD COLUMN shadow BOOLEAN NOT NULL DEFAULT FALSE AFTER action;
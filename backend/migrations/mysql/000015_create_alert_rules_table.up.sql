@@ -0,0 +1,11 @@
+-- Regras do alerta interno (equivale à migration 000023 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    threshold DOUBLE NOT NULL,
+    window_seconds BIGINT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+-- Listas de bloqueio global e das contas (equivale à migration 000024 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS blocklist_entries (
+    id CHAR(36) PRIMARY KEY,
+    account_id VARCHAR(36) NOT NULL DEFAULT '',
+    type VARCHAR(16) NOT NULL,
+    value_hash CHAR(64) NOT NULL,
+    hint VARCHAR(320) NOT NULL,
+    action VARCHAR(8) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME(6) NULL,
+    hits BIGINT NOT NULL DEFAULT 0,
+    last_hit_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE INDEX idx_blocklist_entries_value_hash_account_id (value_hash, account_id),
+    INDEX idx_blocklist_entries_account_id_created_at (account_id, created_at DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,23 @@
+-- Limites de score de risco das contas e fila de revisão manual (equivale à migration 000025 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS risk_policies (
+    account_id CHAR(36) PRIMARY KEY,
+    approve_below DECIMAL(5,2) NOT NULL,
+    reject_above DECIMAL(5,2) NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_risk_policies_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice_reviews (
+    invoice_id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    score DECIMAL(5,2) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    due_at DATETIME(6) NOT NULL,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_invoice_reviews_account_id_status_created_at (account_id, status, created_at),
+    INDEX idx_invoice_reviews_status_due_at (status, due_at),
+    CONSTRAINT fk_invoice_reviews_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
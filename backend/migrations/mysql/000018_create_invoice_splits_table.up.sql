@@ -0,0 +1,11 @@
+-- Partes das faturas divididas entre contas recebedoras (equivale à migration 000026 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS invoice_splits (
+    invoice_id CHAR(36) NOT NULL,
+    recipient_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (invoice_id, recipient_id),
+    INDEX idx_invoice_splits_recipient_id (recipient_id),
+    CONSTRAINT fk_invoice_splits_recipient_id FOREIGN KEY (recipient_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,23 @@
+-- Contas filhas de plataformas e razão das contas (equivale à migration 000027 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS platform_links (
+    account_id CHAR(36) PRIMARY KEY,
+    platform_id CHAR(36) NOT NULL,
+    commission_percent DECIMAL(5,2) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_platform_links_platform_id (platform_id),
+    CONSTRAINT fk_platform_links_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_platform_links_platform_id FOREIGN KEY (platform_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    counterparty_id VARCHAR(36) NOT NULL DEFAULT '',
+    invoice_id CHAR(36) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_ledger_entries_account_id_created_at (account_id, created_at),
+    CONSTRAINT fk_ledger_entries_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
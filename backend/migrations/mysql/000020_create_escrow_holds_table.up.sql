@@ -0,0 +1,16 @@
+-- Valores de faturas aprovadas retidos em custódia (equivale à migration 000028 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS escrow_holds (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    invoice_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    release_at DATETIME(6) NOT NULL,
+    released_by VARCHAR(255) NOT NULL DEFAULT '',
+    released_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_escrow_holds_invoice_id (invoice_id),
+    INDEX idx_escrow_holds_status_release_at (status, release_at),
+    INDEX idx_escrow_holds_account_id_status (account_id, status),
+    CONSTRAINT fk_escrow_holds_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
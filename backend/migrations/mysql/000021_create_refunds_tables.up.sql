@@ -0,0 +1,25 @@
+-- Políticas de reembolso das contas e reembolsos das faturas (equivale à migration 000029 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS refund_policies (
+    account_id CHAR(36) PRIMARY KEY,
+    approval_threshold DECIMAL(10,2) NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_refund_policies_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id CHAR(36) PRIMARY KEY,
+    invoice_id CHAR(36) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    decided_by VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at DATETIME(6) NULL,
+    expires_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_refunds_account_id_status_created_at (account_id, status, created_at),
+    INDEX idx_refunds_invoice_id (invoice_id),
+    INDEX idx_refunds_status_expires_at (status, expires_at),
+    CONSTRAINT fk_refunds_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,29 @@
+-- Disputas das faturas e as suas evidências (equivale à migration 000030 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS disputes (
+    id CHAR(36) PRIMARY KEY,
+    invoice_id CHAR(36) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    resolved_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY idx_disputes_invoice_id (invoice_id),
+    INDEX idx_disputes_account_id_status_created_at (account_id, status, created_at),
+    CONSTRAINT fk_disputes_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id CHAR(36) PRIMARY KEY,
+    dispute_id CHAR(36) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    object_key VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_dispute_evidence_dispute_id_created_at (dispute_id, created_at),
+    CONSTRAINT fk_dispute_evidence_dispute_id FOREIGN KEY (dispute_id) REFERENCES disputes(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
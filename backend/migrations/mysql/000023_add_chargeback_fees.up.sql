@@ -0,0 +1,9 @@
+-- Tarifa de chargeback das disputas e tarifas das contas (equivale à migration 000031 do PostgreSQL)
+ALTER TABLE disputes ADD COLUMN fee DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER amount;
+
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    account_id CHAR(36) PRIMARY KEY,
+    chargeback_fee DECIMAL(10,2) NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_fee_schedules_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
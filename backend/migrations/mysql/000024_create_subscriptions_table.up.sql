@@ -0,0 +1,23 @@
+-- Assinaturas e o estado das retentativas das cobranças (equivale à migration 000032 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    card_token VARCHAR(64) NOT NULL,
+    card_brand VARCHAR(20) NOT NULL DEFAULT '',
+    card_last_digits VARCHAR(4) NOT NULL DEFAULT '',
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    amount DECIMAL(10,2) NOT NULL,
+    interval_days INT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    webhook_url TEXT NOT NULL,
+    due_at DATETIME(6) NOT NULL,
+    next_attempt_at DATETIME(6) NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_invoice_id VARCHAR(36) NOT NULL DEFAULT '',
+    cancelled_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_subscriptions_account_id_status_created_at (account_id, status, created_at),
+    INDEX idx_subscriptions_status_next_attempt_at (status, next_attempt_at),
+    CONSTRAINT fk_subscriptions_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,30 @@
+-- Cupons de desconto das contas e os seus usos (equivale à migration 000033 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS coupons (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    code VARCHAR(32) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    value DECIMAL(10,2) NOT NULL,
+    max_redemptions INT NOT NULL DEFAULT 0,
+    redemptions INT NOT NULL DEFAULT 0,
+    valid_from DATETIME(6) NULL,
+    valid_until DATETIME(6) NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY idx_coupons_account_id_code (account_id, code),
+    INDEX idx_coupons_account_id_created_at (account_id, created_at),
+    CONSTRAINT fk_coupons_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id CHAR(36) PRIMARY KEY,
+    coupon_id CHAR(36) NOT NULL,
+    account_id CHAR(36) NOT NULL,
+    invoice_id CHAR(36) NOT NULL,
+    discount DECIMAL(10,2) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY idx_coupon_redemptions_invoice_id (invoice_id),
+    INDEX idx_coupon_redemptions_account_id_created_at (account_id, created_at),
+    CONSTRAINT fk_coupon_redemptions_coupon_id FOREIGN KEY (coupon_id) REFERENCES coupons(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
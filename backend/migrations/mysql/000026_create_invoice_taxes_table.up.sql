@@ -0,0 +1,10 @@
+-- Impostos das faturas, calculados por linha na criação (equivale à migration 000034 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS invoice_taxes (
+    invoice_id CHAR(36) NOT NULL,
+    line INT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    rate DECIMAL(7,4) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (invoice_id, line, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
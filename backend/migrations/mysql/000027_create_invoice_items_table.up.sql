@@ -0,0 +1,10 @@
+-- Itens das faturas compostas (equivale à migration 000035 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS invoice_items (
+    invoice_id CHAR(36) NOT NULL,
+    position INT NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    unit_price DECIMAL(10,2) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (invoice_id, position)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
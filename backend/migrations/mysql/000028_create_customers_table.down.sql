@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_payment_methods;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,22 @@
+-- Clientes das contas e os cartões guardados deles (equivale à migration 000036 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS customers (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    document TEXT NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_customers_account_id_created_at (account_id, created_at),
+    CONSTRAINT fk_customers_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS customer_payment_methods (
+    customer_id CHAR(36) NOT NULL,
+    card_token VARCHAR(64) NOT NULL,
+    card_brand VARCHAR(20) NOT NULL,
+    card_last_digits VARCHAR(4) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (customer_id, card_token),
+    CONSTRAINT fk_customer_payment_methods_customer_id FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+ALTER TABLE escrow_holds DROP COLUMN kind;
+
+DROP TABLE IF EXISTS payout_schedules;
@@ -0,0 +1,9 @@
+-- Prazos de repasse das contas e motivo das retenções (equivale à migration 000037 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS payout_schedules (
+    account_id CHAR(36) PRIMARY KEY,
+    delay_days INT NOT NULL,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_payout_schedules_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE escrow_holds ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'escrow' AFTER invoice_id;
@@ -0,0 +1,12 @@
+-- Antecipações dos valores que aguardavam a data de repasse (equivale à migration 000038 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS anticipations (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL,
+    holds INT NOT NULL,
+    requested_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_anticipations_account_id_created_at (account_id, created_at),
+    CONSTRAINT fk_anticipations_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
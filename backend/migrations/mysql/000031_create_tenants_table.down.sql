@@ -0,0 +1,3 @@
+ALTER TABLE accounts DROP FOREIGN KEY fk_accounts_tenant_id, DROP INDEX idx_accounts_tenant_id, DROP COLUMN tenant_id;
+
+DROP TABLE IF EXISTS tenants;
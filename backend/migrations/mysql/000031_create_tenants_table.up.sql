@@ -0,0 +1,20 @@
+-- Organizações das implantações white-label e organização das contas (equivale à migration 000039 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS tenants (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    admin_key_hash VARCHAR(64) NOT NULL,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    logo_url VARCHAR(2048) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    support_email VARCHAR(255) NOT NULL DEFAULT '',
+    chargeback_fee DECIMAL(10,2) NULL,
+    spending_limit_daily DECIMAL(12,2) NULL,
+    spending_limit_monthly DECIMAL(12,2) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY idx_tenants_admin_key_hash (admin_key_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE accounts ADD COLUMN tenant_id CHAR(36) NULL AFTER scopes,
+    ADD INDEX idx_accounts_tenant_id (tenant_id),
+    ADD CONSTRAINT fk_accounts_tenant_id FOREIGN KEY (tenant_id) REFERENCES tenants(id);
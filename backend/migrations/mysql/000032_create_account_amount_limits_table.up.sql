@@ -0,0 +1,8 @@
+-- Faixas de valor próprias das contas (equivale à migration 000040 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS account_amount_limits (
+    account_id CHAR(36) PRIMARY KEY,
+    min_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    max_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_account_amount_limits_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,23 @@
+-- Ordens permanentes de transferência entre contas (equivale à migration 000041 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    destination_id CHAR(36) NOT NULL,
+    percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    interval_days INT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    webhook_url TEXT NOT NULL,
+    next_run_at DATETIME(6) NULL,
+    last_run_at DATETIME(6) NULL,
+    last_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    failed_runs INT NOT NULL DEFAULT 0,
+    last_failure VARCHAR(32) NOT NULL DEFAULT '',
+    cancelled_at DATETIME(6) NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_standing_orders_account_id_status_created_at (account_id, status, created_at),
+    INDEX idx_standing_orders_status_next_run_at (status, next_run_at),
+    CONSTRAINT fk_standing_orders_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_standing_orders_destination_id FOREIGN KEY (destination_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
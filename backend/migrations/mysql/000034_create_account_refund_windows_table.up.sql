@@ -0,0 +1,7 @@
+-- Prazos de reembolso próprios das contas (equivale à migration 000042 do PostgreSQL)
+CREATE TABLE IF NOT EXISTS account_refund_windows (
+    account_id CHAR(36) PRIMARY KEY,
+    days INT NOT NULL DEFAULT 0,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CONSTRAINT fk_account_refund_windows_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,62 @@
+// Package webhook define a assinatura dos webhooks enviados pelo gateway e a verificação usada pelos lojistas
+//
+// A assinatura é o HMAC-SHA256, em hexadecimal, de "<timestamp>.<payload>", com o API Key da conta como segredo,
+// o mesmo segredo das requisições assinadas. O timestamp, em segundos Unix, vai no header X-Gateway-Timestamp
+// e a assinatura no header X-Gateway-Signature
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Headers que acompanham cada webhook
+const (
+	SignatureHeader = "X-Gateway-Signature"
+	TimestampHeader = "X-Gateway-Timestamp"
+	// RequestIDHeader traz o ID da requisição ao gateway que originou o evento, o mesmo do X-Request-ID
+	// devolvido na resposta; vem vazio nos eventos gerados fora de uma requisição
+	RequestIDHeader = "X-Request-ID"
+)
+
+// DefaultTolerance é a diferença máxima recomendada entre o timestamp do webhook e o relógio do lojista
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature é retornado quando a assinatura está malformada ou não confere com o payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStaleTimestamp é retornado quando o timestamp do webhook está fora da tolerância.
+	ErrStaleTimestamp = errors.New("webhook timestamp out of tolerance")
+)
+
+// Sign calcula a assinatura do payload enviado no instante timestamp
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura em tempo constante e, com tolerance positiva, se o timestamp está a no máximo
+// tolerance do relógio local, o que impede que um webhook capturado seja reenviado mais tarde
+func Verify(secret string, timestamp int64, payload []byte, signature string, tolerance time.Duration) error {
+	received, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(Sign(secret, timestamp, payload))
+	if !hmac.Equal(received, expected) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		if skew := time.Since(time.Unix(timestamp, 0)); skew > tolerance || skew < -tolerance {
+			return ErrStaleTimestamp
+		}
+	}
+	return nil
+}